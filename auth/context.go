@@ -0,0 +1,17 @@
+package auth
+
+import "context"
+
+type clientKey struct{}
+
+// NewContext returns a copy of ctx carrying the authenticated client.
+func NewContext(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// FromContext returns the authenticated client of ctx; ok is false when the
+// call was not authenticated (API keys are not configured).
+func FromContext(ctx context.Context) (client Client, ok bool) {
+	client, ok = ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
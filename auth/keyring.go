@@ -0,0 +1,132 @@
+// Package auth authenticates API clients by key and enforces their per-client
+// rate limits and usage accounting.
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Client is an API client identified by its key.
+type Client struct {
+	Name string
+	// RatePerMinute caps sustained requests; bursts up to the same number are allowed. 0 = unlimited.
+	RatePerMinute int
+}
+
+// Keyring holds the configured API keys with a token bucket and usage counters
+// per client. It is safe for concurrent use.
+type Keyring struct {
+	keys map[[sha256.Size]byte]Client
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	usage   map[string]*dto.APIUsage
+	now     func() time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// NewKeyring builds a keyring from key -> client.
+func NewKeyring(keys map[string]Client) *Keyring {
+	k := &Keyring{
+		keys:    make(map[[sha256.Size]byte]Client, len(keys)),
+		buckets: map[string]*bucket{},
+		usage:   map[string]*dto.APIUsage{},
+		now:     time.Now,
+	}
+	for key, c := range keys {
+		k.keys[sha256.Sum256([]byte(key))] = c
+		k.usage[c.Name] = &dto.APIUsage{Client: c.Name, RatePerMinute: c.RatePerMinute}
+	}
+	return k
+}
+
+// Len returns the number of configured keys.
+func (k *Keyring) Len() int {
+	return len(k.keys)
+}
+
+// Authenticate returns the client owning key. Keys are compared by their
+// SHA-256 digest so lookup time does not depend on how much of a key matches.
+func (k *Keyring) Authenticate(key string) (Client, bool) {
+	sum := sha256.Sum256([]byte(key))
+	for digest, c := range k.keys {
+		if subtle.ConstantTimeCompare(sum[:], digest[:]) == 1 {
+			return c, true
+		}
+	}
+	return Client{}, false
+}
+
+// Allow takes one request from the client's bucket. When the limit is reached
+// it returns false with the time until the next request is allowed.
+func (k *Keyring) Allow(c Client) (ok bool, remaining int, retryAfter time.Duration) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	u := k.usageLocked(c)
+	u.Requests++
+	u.LastUsed = k.now()
+	if c.RatePerMinute <= 0 {
+		return true, -1, 0
+	}
+
+	limit := float64(c.RatePerMinute)
+	perSec := limit / 60
+	now := k.now()
+	b, found := k.buckets[c.Name]
+	if !found {
+		b = &bucket{tokens: limit, last: now}
+		k.buckets[c.Name] = b
+	}
+	b.tokens += now.Sub(b.last).Seconds() * perSec
+	if b.tokens > limit {
+		b.tokens = limit
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		u.RateLimited++
+		return false, 0, time.Duration((1 - b.tokens) / perSec * float64(time.Second))
+	}
+	b.tokens--
+	return true, int(b.tokens), 0
+}
+
+// Record accounts the response status of a request the client made.
+func (k *Keyring) Record(c Client, status int) {
+	if status < 400 {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.usageLocked(c).Errors++
+}
+
+// Usage returns a snapshot of the client's accounting.
+func (k *Keyring) Usage(name string) (dto.APIUsage, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	u, ok := k.usage[name]
+	if !ok {
+		return dto.APIUsage{}, false
+	}
+	return *u, true
+}
+
+func (k *Keyring) usageLocked(c Client) *dto.APIUsage {
+	u, ok := k.usage[c.Name]
+	if !ok {
+		u = &dto.APIUsage{Client: c.Name, RatePerMinute: c.RatePerMinute}
+		k.usage[c.Name] = u
+	}
+	return u
+}
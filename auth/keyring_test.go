@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeyringRateLimitAndUsage(t *testing.T) {
+	k := NewKeyring(map[string]Client{"secret-1": {Name: "acme", RatePerMinute: 2}})
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	k.now = func() time.Time { return now }
+
+	_, ok := k.Authenticate("wrong")
+	assert.False(t, ok)
+	c, ok := k.Authenticate("secret-1")
+	assert.True(t, ok)
+	assert.Equal(t, "acme", c.Name)
+
+	ok, _, _ = k.Allow(c)
+	assert.True(t, ok)
+	ok, remaining, _ := k.Allow(c)
+	assert.True(t, ok)
+	assert.Equal(t, 0, remaining)
+
+	ok, _, retry := k.Allow(c)
+	assert.False(t, ok)
+	assert.Equal(t, 30*time.Second, retry)
+
+	// one token refills every 30s at 2/min
+	now = now.Add(30 * time.Second)
+	ok, _, _ = k.Allow(c)
+	assert.True(t, ok)
+
+	k.Record(c, 500)
+	k.Record(c, 200)
+	u, _ := k.Usage("acme")
+	assert.Equal(t, int64(4), u.Requests)
+	assert.Equal(t, int64(1), u.RateLimited)
+	assert.Equal(t, int64(1), u.Errors)
+}
@@ -0,0 +1,30 @@
+// Package cache stores parsed document results so identical uploads are not
+// OCRed again. Backends are an in-process LRU and Redis.
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Cache is a byte store with per-entry expiry.
+type Cache interface {
+	// Get returns the value for key; ok is false when it is missing or expired.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+}
+
+// New builds the cache selected by backend: "memory" (LRU of size entries),
+// "redis" (at redisURL) or "off"/"" for no cache (nil).
+func New(backend string, size int, redisURL string) (Cache, error) {
+	switch backend {
+	case "", "off":
+		return nil, nil
+	case "memory":
+		return NewLRU(size), nil
+	case "redis":
+		return NewRedis(redisURL)
+	}
+	return nil, fmt.Errorf("unknown cache backend %q", backend)
+}
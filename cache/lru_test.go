@@ -0,0 +1,34 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRU(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := NewLRU(2)
+	c.now = func() time.Time { return now }
+
+	c.Set(ctx, "a", []byte("1"), time.Minute)
+	c.Set(ctx, "b", []byte("2"), 0)
+	c.Get(ctx, "a") // a is now the most recently used
+	c.Set(ctx, "c", []byte("3"), 0)
+
+	_, ok, _ := c.Get(ctx, "b")
+	assert.False(t, ok, "least recently used entry is evicted")
+
+	v, ok, _ := c.Get(ctx, "a")
+	assert.True(t, ok)
+	assert.Equal(t, "1", string(v))
+
+	now = now.Add(2 * time.Minute)
+	_, ok, _ = c.Get(ctx, "a")
+	assert.False(t, ok, "entry expired")
+	_, ok, _ = c.Get(ctx, "c")
+	assert.True(t, ok, "no ttl never expires")
+}
@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CompanyDetails is a company as returned by the company registry lookup.
+type CompanyDetails struct {
+	CIN    string `json:"cin"`
+	Name   string `json:"name"`
+	Status string `json:"status"` // e.g. Active, Strike Off, Amalgamated, Under Liquidation
+	// IncorporatedOn is the date of incorporation, YYYY-MM-DD.
+	IncorporatedOn string `json:"date_of_incorporation"`
+}
+
+// Active reports whether the registry lists the company as active.
+func (d *CompanyDetails) Active() bool {
+	return strings.EqualFold(strings.TrimSpace(d.Status), "active")
+}
+
+// CompanyRegistryClient checks employers against a company registry: an MCA
+// (Ministry of Corporate Affairs) master data API or a gateway in front of it
+// answering GET <URL>?cin=<CIN> or GET <URL>?name=<name> with CompanyDetails,
+// 404 when no company matches. The API key, if any, is sent as X-API-Key.
+// Answers are kept for the life of the process.
+type CompanyRegistryClient struct {
+	URL    string
+	APIKey string
+	HTTP   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*CompanyDetails
+}
+
+// NewCompanyRegistryClient returns a client for COMPANY_REGISTRY_URL (with
+// COMPANY_REGISTRY_API_KEY), or nil when it is unset.
+func NewCompanyRegistryClient() *CompanyRegistryClient {
+	u := os.Getenv("COMPANY_REGISTRY_URL")
+	if u == "" {
+		return nil
+	}
+	return &CompanyRegistryClient{
+		URL:    u,
+		APIKey: os.Getenv("COMPANY_REGISTRY_API_KEY"),
+		HTTP:   &http.Client{Timeout: 5 * time.Second},
+		cache:  map[string]*CompanyDetails{},
+	}
+}
+
+// LookupCIN returns the company with a corporate identity number, or nil when
+// the registry does not know it.
+func (c *CompanyRegistryClient) LookupCIN(ctx context.Context, cin string) (*CompanyDetails, error) {
+	return c.lookup(ctx, "cin", strings.ToUpper(cin))
+}
+
+// LookupName returns the company registered under name, or nil when none is.
+func (c *CompanyRegistryClient) LookupName(ctx context.Context, name string) (*CompanyDetails, error) {
+	return c.lookup(ctx, "name", strings.ToUpper(strings.Join(strings.Fields(name), " ")))
+}
+
+func (c *CompanyRegistryClient) lookup(ctx context.Context, key, value string) (*CompanyDetails, error) {
+	cacheKey := key + ":" + value
+	c.mu.Lock()
+	details, ok := c.cache[cacheKey]
+	c.mu.Unlock()
+	if ok {
+		return details, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"?"+url.Values{key: {value}}.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	if c.APIKey != "" {
+		req.Header.Set("X-API-Key", c.APIKey)
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		details = &CompanyDetails{}
+		if err := json.NewDecoder(resp.Body).Decode(details); err != nil {
+			return nil, fmt.Errorf("company registry lookup: %w", err)
+		}
+	case http.StatusNotFound:
+		details = nil
+	default:
+		return nil, fmt.Errorf("company registry lookup returned %s", resp.Status)
+	}
+
+	c.mu.Lock()
+	c.cache[cacheKey] = details
+	c.mu.Unlock()
+	return details, nil
+}
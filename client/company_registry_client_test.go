@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompanyRegistryLookup(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "secret", r.Header.Get("X-API-Key"))
+		switch {
+		case r.URL.Query().Get("cin") == "U72200KA2009PTC049889":
+			w.Write([]byte(`{"cin":"U72200KA2009PTC049889","name":"ACME SOFTWARE PRIVATE LIMITED","status":"Active","date_of_incorporation":"2009-04-01"}`))
+		case r.URL.Query().Get("name") == "SHELL TRADERS PVT LTD":
+			w.Write([]byte(`{"cin":"U51909DL2015PTC123456","name":"SHELL TRADERS PRIVATE LIMITED","status":"Strike Off"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	t.Setenv("COMPANY_REGISTRY_URL", srv.URL)
+	t.Setenv("COMPANY_REGISTRY_API_KEY", "secret")
+	c := NewCompanyRegistryClient()
+	require.NotNil(t, c)
+	ctx := context.Background()
+
+	acme, err := c.LookupCIN(ctx, "u72200ka2009ptc049889")
+	require.NoError(t, err)
+	require.NotNil(t, acme)
+	assert.Equal(t, "ACME SOFTWARE PRIVATE LIMITED", acme.Name)
+	assert.True(t, acme.Active())
+
+	shell, err := c.LookupName(ctx, "Shell  Traders Pvt Ltd")
+	require.NoError(t, err)
+	require.NotNil(t, shell)
+	assert.False(t, shell.Active())
+
+	unknown, err := c.LookupName(ctx, "Nonexistent Corp")
+	require.NoError(t, err)
+	assert.Nil(t, unknown)
+
+	// answers, including misses, are cached
+	_, _ = c.LookupName(ctx, "Nonexistent Corp")
+	assert.Equal(t, 3, calls)
+}
+
+func TestNewCompanyRegistryClientUnset(t *testing.T) {
+	t.Setenv("COMPANY_REGISTRY_URL", "")
+	assert.Nil(t, NewCompanyRegistryClient())
+}
@@ -0,0 +1,104 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// ErrDocumentTooLarge is returned when a document URL serves more than MaxBytes.
+var ErrDocumentTooLarge = errors.New("document exceeds the size limit")
+
+// DocumentFetcher downloads documents callers link to (typically pre-signed
+// S3 or GCS URLs) instead of uploading them. Only https URLs are fetched, and
+// hosts resolving to loopback, private or link-local addresses are refused so
+// a caller cannot make the service read internal endpoints, unless
+// DOCUMENT_URL_ALLOW_PRIVATE=true (local development with MinIO and such).
+type DocumentFetcher struct {
+	HTTP     *http.Client
+	MaxBytes int64
+}
+
+// NewDocumentFetcher returns a fetcher for documents of at most maxBytes,
+// with a DOCUMENT_URL_TIMEOUT_SECONDS (default 30) download timeout.
+func NewDocumentFetcher(maxBytes int64) *DocumentFetcher {
+	timeout, err := strconv.Atoi(os.Getenv("DOCUMENT_URL_TIMEOUT_SECONDS"))
+	if err != nil || timeout < 1 {
+		timeout = 30
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if os.Getenv("DOCUMENT_URL_ALLOW_PRIVATE") != "true" {
+		dialer.Control = refusePrivate
+	}
+	return &DocumentFetcher{
+		HTTP: &http.Client{
+			Timeout:   time.Duration(timeout) * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: http.ProxyFromEnvironment},
+		},
+		MaxBytes: maxBytes,
+	}
+}
+
+// refusePrivate rejects connections to internal addresses after DNS
+// resolution, so rebinding a public name to one does not get through. It is
+// the dialer Control of document downloads and webhook deliveries.
+func refusePrivate(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsUnspecified() {
+		return fmt.Errorf("refusing to connect to non-public address %s", host)
+	}
+	return nil
+}
+
+// Fetch downloads rawURL and returns its content and a file name for it (the
+// last path segment, which keeps the extension file type detection uses).
+func (f *DocumentFetcher) Fetch(ctx context.Context, rawURL string) ([]byte, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, "", fmt.Errorf("document URL must be an absolute https URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, "", err
+	}
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return nil, "", fmt.Errorf("download document: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", fmt.Errorf("download document: %s", resp.Status)
+	}
+	if resp.ContentLength > f.MaxBytes {
+		return nil, "", ErrDocumentTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, f.MaxBytes+1))
+	if err != nil {
+		return nil, "", fmt.Errorf("download document: %w", err)
+	}
+	if int64(len(data)) > f.MaxBytes {
+		return nil, "", ErrDocumentTooLarge
+	}
+
+	name := path.Base(u.Path)
+	if name == "." || name == "/" || !strings.Contains(name, ".") {
+		name = "document"
+	}
+	return data, name, nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDocumentFetcher(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("%PDF-1.7 statement"))
+	}))
+	defer srv.Close()
+
+	f := &DocumentFetcher{HTTP: srv.Client(), MaxBytes: 64}
+	data, name, err := f.Fetch(context.Background(), srv.URL+"/bucket/statement.pdf?X-Amz-Signature=abc")
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7 statement", string(data))
+	assert.Equal(t, "statement.pdf", name)
+
+	f.MaxBytes = 8
+	_, _, err = f.Fetch(context.Background(), srv.URL+"/statement.pdf")
+	assert.ErrorIs(t, err, ErrDocumentTooLarge)
+
+	_, _, err = f.Fetch(context.Background(), "http://example.com/statement.pdf")
+	assert.Error(t, err)
+
+	// The default fetcher refuses the test server: it listens on loopback
+	_, _, err = NewDocumentFetcher(64).Fetch(context.Background(), srv.URL+"/statement.pdf")
+	assert.ErrorContains(t, err, "non-public address")
+}
@@ -0,0 +1,96 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+)
+
+// FaceEmbedder turns a cropped face image into an embedding vector; faces of
+// the same person have embeddings with a high cosine similarity.
+type FaceEmbedder interface {
+	Embed(faceImage []byte) ([]float32, error)
+}
+
+// FaceClient is a FaceEmbedder backed by an HTTP embedding service: the ONNX
+// model served by the PaddleOCR container ("local"), or an external service
+// speaking the same protocol ("remote"): the face is posted as the multipart
+// "image" field and the answer is {"embedding": [...]}.
+type FaceClient struct {
+	URL    string
+	APIKey string // sent as a bearer token to remote services
+	HTTP   *http.Client
+}
+
+// NewFaceClient picks the backend from FACE_BACKEND: local (default), remote
+// (FACE_EMBED_URL and FACE_API_KEY required) or off, which returns nil.
+func NewFaceClient() (*FaceClient, error) {
+	c := &FaceClient{
+		URL:  os.Getenv("FACE_EMBED_URL"),
+		HTTP: &http.Client{Timeout: time.Duration(envInt("FACE_TIMEOUT_SECONDS", 15)) * time.Second},
+	}
+
+	switch backend := os.Getenv("FACE_BACKEND"); backend {
+	case "", "local":
+		if c.URL == "" {
+			c.URL = "http://paddle:8866/face/embed"
+		}
+	case "remote":
+		c.APIKey = os.Getenv("FACE_API_KEY")
+		if c.URL == "" || c.APIKey == "" {
+			return nil, fmt.Errorf("FACE_BACKEND=remote needs FACE_EMBED_URL and FACE_API_KEY")
+		}
+	case "off":
+		return nil, nil
+	default:
+		return nil, fmt.Errorf("unknown FACE_BACKEND %q", backend)
+	}
+	return c, nil
+}
+
+// Embed returns the embedding of a cropped face.
+func (f *FaceClient) Embed(faceImage []byte) ([]float32, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("image", "face.jpg")
+	if err != nil {
+		return nil, err
+	}
+	part.Write(faceImage)
+	writer.Close()
+
+	req, err := http.NewRequest("POST", f.URL, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	if f.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+f.APIKey)
+	}
+
+	resp, err := f.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("face embedding service returned %s", resp.Status)
+	}
+
+	var out struct {
+		Embedding []float32 `json:"embedding"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, err
+	}
+	if len(out.Embedding) == 0 {
+		return nil, fmt.Errorf("face embedding service returned an empty embedding")
+	}
+	return out.Embedding, nil
+}
@@ -0,0 +1,69 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"time"
+)
+
+// HandwritingClient talks to a recognition-only endpoint backed by a
+// handwriting model. It expects tightly cropped regions, not whole pages.
+type HandwritingClient struct {
+	URL  string
+	HTTP *http.Client
+}
+
+func NewHandwritingClient() *HandwritingClient {
+	url := os.Getenv("HANDWRITING_OCR_URL")
+	if url == "" {
+		url = "http://paddle:8866/handwriting"
+	}
+	return &HandwritingClient{
+		URL:  url,
+		HTTP: &http.Client{Timeout: time.Duration(envInt("HANDWRITING_TIMEOUT_SECONDS", 30)) * time.Second},
+	}
+}
+
+// Recognize returns the text in a cropped region image and the model's
+// confidence (0–100). It gives up when ctx is done.
+func (h *HandwritingClient) Recognize(ctx context.Context, regionImage []byte) (string, float64, error) {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	part, err := writer.CreateFormFile("image", "region.png")
+	if err != nil {
+		return "", 0, err
+	}
+	part.Write(regionImage)
+	writer.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.URL, body)
+	if err != nil {
+		return "", 0, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+
+	resp, err := h.HTTP.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", 0, fmt.Errorf("handwriting OCR returned %s", resp.Status)
+	}
+
+	var out struct {
+		Text       string  `json:"text"`
+		Confidence float64 `json:"confidence"` // 0–1
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return "", 0, err
+	}
+	return out.Text, out.Confidence * 100, nil
+}
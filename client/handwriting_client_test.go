@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandwritingRecognize(t *testing.T) {
+	stalled := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Has("slow") {
+			<-stalled
+			return
+		}
+		w.Write([]byte(`{"text":"12,500","confidence":0.8}`))
+	}))
+	defer srv.Close()
+	defer close(stalled)
+
+	t.Setenv("HANDWRITING_OCR_URL", srv.URL)
+	h := NewHandwritingClient()
+	text, conf, err := h.Recognize(context.Background(), []byte("png"))
+	require.NoError(t, err)
+	assert.Equal(t, "12,500", text)
+	assert.InDelta(t, 80, conf, 1e-9)
+
+	// a stalled model ends with the request
+	h.URL = srv.URL + "?slow"
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, _, err = h.Recognize(ctx, []byte("png"))
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,83 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IFSCDetails is a bank branch as returned by the IFSC lookup API.
+type IFSCDetails struct {
+	Bank    string `json:"BANK"`
+	Branch  string `json:"BRANCH"`
+	Address string `json:"ADDRESS"`
+	City    string `json:"CITY"`
+	State   string `json:"STATE"`
+}
+
+// IFSCClient resolves IFSCs through a lookup API answering GET <URL>/<IFSC>
+// with IFSCDetails, 404 for unknown codes (the protocol of Razorpay's open
+// IFSC API, which can also be self-hosted). Answers are kept for the life of
+// the process; branches rarely change.
+type IFSCClient struct {
+	URL  string
+	HTTP *http.Client
+
+	mu    sync.Mutex
+	cache map[string]*IFSCDetails
+}
+
+// NewIFSCClient returns a client for IFSC_LOOKUP_URL, or nil when it is unset.
+func NewIFSCClient() *IFSCClient {
+	url := os.Getenv("IFSC_LOOKUP_URL")
+	if url == "" {
+		return nil
+	}
+	return &IFSCClient{
+		URL:   strings.TrimSuffix(url, "/"),
+		HTTP:  &http.Client{Timeout: 5 * time.Second},
+		cache: map[string]*IFSCDetails{},
+	}
+}
+
+// Lookup returns the branch of an IFSC, or nil when the API does not know it.
+func (c *IFSCClient) Lookup(ctx context.Context, ifsc string) (*IFSCDetails, error) {
+	c.mu.Lock()
+	details, ok := c.cache[ifsc]
+	c.mu.Unlock()
+	if ok {
+		return details, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL+"/"+ifsc, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		details = &IFSCDetails{}
+		if err := json.NewDecoder(resp.Body).Decode(details); err != nil {
+			return nil, fmt.Errorf("IFSC lookup: %w", err)
+		}
+	case http.StatusNotFound:
+		details = nil
+	default:
+		return nil, fmt.Errorf("IFSC lookup returned %s", resp.Status)
+	}
+
+	c.mu.Lock()
+	c.cache[ifsc] = details
+	c.mu.Unlock()
+	return details, nil
+}
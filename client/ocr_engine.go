@@ -0,0 +1,40 @@
+package client
+
+import (
+	"context"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// TesseractEngine is Tesseract OCR as the services use it. TesseractClient
+// implements it with the installed Tesseract; ocrmock.Tesseract stands in for
+// it in tests. lang is a Tesseract language such as "eng" or "hin+eng"; ""
+// is the client's default.
+type TesseractEngine interface {
+	// Lang returns the default language
+	Lang() string
+	// ValidateLang checks that lang can be read with the installed traineddata
+	ValidateLang(lang string) error
+
+	ExtractTextFromBytes(ctx context.Context, data []byte) (string, error)
+	ExtractTextAndQuality(ctx context.Context, filePath string) (string, float64, error)
+	ExtractTextAndQualityFromBytes(ctx context.Context, data []byte) (string, float64, error)
+	ExtractTextAndQualityFromBytesLang(ctx context.Context, data []byte, lang string) (string, float64, error)
+	ExtractStructured(ctx context.Context, data []byte, lang string) (*dto.StructuredText, error)
+
+	DetectOrientation(ctx context.Context, data []byte) (rotate int, confidence float64, err error)
+	DetectScript(ctx context.Context, data []byte) (script string, confidence float64, err error)
+}
+
+// PaddleEngine is PaddleOCR as the services use it. PaddleClient implements
+// it over HTTP; ocrmock.Paddle stands in for it in tests.
+type PaddleEngine interface {
+	ExtractText(ctx context.Context, imageBytes []byte) (string, error)
+	ExtractTextFromFile(ctx context.Context, path string) (string, error)
+	ExtractStructured(ctx context.Context, imageBytes []byte) (*dto.StructuredText, error)
+}
+
+var (
+	_ TesseractEngine = (*TesseractClient)(nil)
+	_ PaddleEngine    = (*PaddleClient)(nil)
+)
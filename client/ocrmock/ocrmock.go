@@ -0,0 +1,194 @@
+// Package ocrmock has stand-ins for Tesseract and PaddleOCR, for testing
+// services without the Tesseract libraries or the PaddleOCR container. They
+// return canned results and record what they were asked to read.
+package ocrmock
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Call is one read of an image.
+type Call struct {
+	Image []byte
+	Lang  string // the language asked for; "" = the default
+}
+
+// recorder keeps the calls of an engine.
+type recorder struct {
+	mu    sync.Mutex
+	calls []Call
+}
+
+func (r *recorder) record(image []byte, lang string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, Call{Image: image, Lang: lang})
+}
+
+// Calls returns the reads so far, in order.
+func (r *recorder) Calls() []Call {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Call(nil), r.calls...)
+}
+
+// Tesseract is a client.TesseractEngine. Every image reads as Result unless
+// Read is set; Err, when set, fails every read. The zero value reads every
+// image as no text.
+type Tesseract struct {
+	Result dto.StructuredText
+	// Read, when set, reads image in lang instead of Result
+	Read func(image []byte, lang string) (dto.StructuredText, error)
+	Err  error
+
+	DefaultLang string   // Lang(); "eng" when empty
+	Installed   []string // languages ValidateLang accepts; nil accepts any
+
+	// What DetectOrientation and DetectScript return
+	Rotate                int
+	OrientationConfidence float64
+	Script                string
+	ScriptConfidence      float64
+
+	recorder
+}
+
+var _ client.TesseractEngine = (*Tesseract)(nil)
+
+func (t *Tesseract) Lang() string {
+	if t.DefaultLang == "" {
+		return "eng"
+	}
+	return t.DefaultLang
+}
+
+func (t *Tesseract) ValidateLang(lang string) error {
+	if t.Installed == nil {
+		return nil
+	}
+	for _, l := range t.Installed {
+		if l == lang {
+			return nil
+		}
+	}
+	return fmt.Errorf("OCR language %q is not installed", lang)
+}
+
+func (t *Tesseract) ExtractStructured(ctx context.Context, data []byte, lang string) (*dto.StructuredText, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	t.record(data, lang)
+	if t.Err != nil {
+		return nil, t.Err
+	}
+	if t.Read != nil {
+		st, err := t.Read(data, lang)
+		if err != nil {
+			return nil, err
+		}
+		return &st, nil
+	}
+	st := t.Result
+	return &st, nil
+}
+
+func (t *Tesseract) ExtractTextFromBytes(ctx context.Context, data []byte) (string, error) {
+	st, err := t.ExtractStructured(ctx, data, "")
+	if err != nil {
+		return "", err
+	}
+	return st.Text, nil
+}
+
+func (t *Tesseract) ExtractTextAndQuality(ctx context.Context, filePath string) (string, float64, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return "", 0, err
+	}
+	return t.ExtractTextAndQualityFromBytesLang(ctx, data, "")
+}
+
+func (t *Tesseract) ExtractTextAndQualityFromBytes(ctx context.Context, data []byte) (string, float64, error) {
+	return t.ExtractTextAndQualityFromBytesLang(ctx, data, "")
+}
+
+func (t *Tesseract) ExtractTextAndQualityFromBytesLang(ctx context.Context, data []byte, lang string) (string, float64, error) {
+	st, err := t.ExtractStructured(ctx, data, lang)
+	if err != nil {
+		return "", 0, err
+	}
+	return st.Text, st.Confidence, nil
+}
+
+func (t *Tesseract) DetectOrientation(ctx context.Context, _ []byte) (int, float64, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, 0, err
+	}
+	return t.Rotate, t.OrientationConfidence, nil
+}
+
+func (t *Tesseract) DetectScript(ctx context.Context, _ []byte) (string, float64, error) {
+	if err := ctx.Err(); err != nil {
+		return "", 0, err
+	}
+	if t.Script == "" {
+		return "", 0, fmt.Errorf("too few characters to detect the script")
+	}
+	return t.Script, t.ScriptConfidence, nil
+}
+
+// Paddle is a client.PaddleEngine. Every image reads as Result unless Read is
+// set; Err, when set, fails every read, as client.ErrPaddleUnavailable does
+// while PaddleOCR is down.
+type Paddle struct {
+	Result dto.StructuredText
+	// Read, when set, reads image instead of Result
+	Read func(image []byte) (dto.StructuredText, error)
+	Err  error
+
+	recorder
+}
+
+var _ client.PaddleEngine = (*Paddle)(nil)
+
+func (p *Paddle) ExtractStructured(ctx context.Context, imageBytes []byte) (*dto.StructuredText, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	p.record(imageBytes, "")
+	if p.Err != nil {
+		return nil, p.Err
+	}
+	if p.Read != nil {
+		st, err := p.Read(imageBytes)
+		if err != nil {
+			return nil, err
+		}
+		return &st, nil
+	}
+	st := p.Result
+	return &st, nil
+}
+
+func (p *Paddle) ExtractText(ctx context.Context, imageBytes []byte) (string, error) {
+	st, err := p.ExtractStructured(ctx, imageBytes)
+	if err != nil {
+		return "", err
+	}
+	return st.Text, nil
+}
+
+func (p *Paddle) ExtractTextFromFile(ctx context.Context, path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return p.ExtractText(ctx, data)
+}
@@ -2,14 +2,35 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"os"
+	"strconv"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
 )
 
+// ErrPaddleUnavailable is returned without calling PaddleOCR while its circuit
+// breaker is open, so OCR chains fall through to Tesseract at once.
+var ErrPaddleUnavailable = errors.New("paddle OCR is unavailable (circuit open)")
+
+// PaddleClient calls the PaddleOCR container. Each request has a timeout and is
+// retried with exponential backoff on network errors and 5xx answers; repeated
+// failures open the circuit breaker.
 type PaddleClient struct {
-	URL string
+	URL         string
+	HTTP        *http.Client
+	MaxAttempts int
+	Backoff     time.Duration // delay before the first retry; doubles per attempt
+	MaxBackoff  time.Duration
+	Breaker     *CircuitBreaker
 }
 
 func NewPaddleClient() (*PaddleClient, error) {
@@ -17,48 +38,209 @@ func NewPaddleClient() (*PaddleClient, error) {
 	if url == "" {
 		url = "http://paddle:8866/ocr"
 	}
-	return &PaddleClient{URL: url}, nil
+	return &PaddleClient{
+		URL:         url,
+		HTTP:        &http.Client{Timeout: time.Duration(envInt("PADDLE_TIMEOUT_SECONDS", 30)) * time.Second},
+		MaxAttempts: envInt("PADDLE_MAX_ATTEMPTS", 3),
+		Backoff:     500 * time.Millisecond,
+		MaxBackoff:  5 * time.Second,
+		Breaker: NewCircuitBreaker(
+			envInt("PADDLE_BREAKER_THRESHOLD", 5),
+			time.Duration(envInt("PADDLE_BREAKER_COOLDOWN_SECONDS", 30))*time.Second,
+		),
+	}, nil
+}
+
+// Health reports the circuit breaker state; "open" means Tesseract-only mode.
+func (p *PaddleClient) Health() BreakerStatus {
+	return p.Breaker.Status()
 }
 
-func (p *PaddleClient) ExtractText(imageBytes []byte) (string, error) {
+func (p *PaddleClient) ExtractText(ctx context.Context, imageBytes []byte) (string, error) {
+	st, err := p.ExtractStructured(ctx, imageBytes)
+	if err != nil {
+		return "", err
+	}
+	return st.Text, nil
+}
+
+// ExtractStructured OCRs an image and returns the text with word boxes.
+// PaddleOCR detects text lines, so each line's box is split between its words
+// in proportion to their length; word confidence is the line's score.
+// Cancelling ctx aborts the request and any retry wait.
+func (p *PaddleClient) ExtractStructured(ctx context.Context, imageBytes []byte) (*dto.StructuredText, error) {
+	if p.Breaker != nil && !p.Breaker.Allow() {
+		return nil, ErrPaddleUnavailable
+	}
+
 	body := &bytes.Buffer{}
 	writer := multipart.NewWriter(body)
 
 	part, err := writer.CreateFormFile("image", "upload.jpg")
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 	part.Write(imageBytes)
 	writer.Close()
 
-	req, err := http.NewRequest("POST", p.URL, body)
+	out, err := p.postWithRetry(ctx, body.Bytes(), writer.FormDataContentType())
+	if p.Breaker != nil {
+		switch {
+		case ctx.Err() != nil:
+			p.Breaker.Release() // the caller gave up: says nothing about PaddleOCR
+		case err != nil:
+			p.Breaker.Failure()
+		default:
+			p.Breaker.Success()
+		}
+	}
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	return out.structured(), nil
+}
+
+// paddleResponse is the /ocr answer; lines is absent on older servers.
+type paddleResponse struct {
+	Text  string `json:"text"`
+	Lines []struct {
+		Text       string       `json:"text"`
+		Confidence float64      `json:"confidence"` // 0-1
+		Box        [][2]float64 `json:"box"`        // 4 corner points
+	} `json:"lines"`
+}
+
+func (r *paddleResponse) structured() *dto.StructuredText {
+	st := &dto.StructuredText{Text: r.Text, Words: []dto.OCRWord{}}
+	var totalConf float64
+	for _, line := range r.Lines {
+		totalConf += line.Confidence * 100
+		st.Words = append(st.Words, splitLineWords(line.Text, line.Confidence*100, line.Box)...)
+	}
+	if len(r.Lines) > 0 {
+		st.Confidence = totalConf / float64(len(r.Lines))
+	}
+	return st
+}
+
+// splitLineWords divides a text line's bounding box between its words by character offset.
+func splitLineWords(text string, conf float64, quad [][2]float64) []dto.OCRWord {
+	if len(quad) == 0 {
+		return nil
+	}
+	x0, y0, x1, y1 := quad[0][0], quad[0][1], quad[0][0], quad[0][1]
+	for _, pt := range quad[1:] {
+		x0, y0 = min(x0, pt[0]), min(y0, pt[1])
+		x1, y1 = max(x1, pt[0]), max(y1, pt[1])
 	}
-	req.Header.Set("Content-Type", writer.FormDataContentType())
 
-	resp, err := http.DefaultClient.Do(req)
+	runes := []rune(text)
+	if len(runes) == 0 {
+		return nil
+	}
+	perChar := (x1 - x0) / float64(len(runes))
+
+	var words []dto.OCRWord
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		space := i == len(runes) || runes[i] == ' ' || runes[i] == '\t'
+		if !space && start < 0 {
+			start = i
+		}
+		if space && start >= 0 {
+			words = append(words, dto.OCRWord{
+				Text:       string(runes[start:i]),
+				Confidence: conf,
+				Box: dto.BoundingBox{
+					X0: int(x0 + perChar*float64(start)),
+					Y0: int(y0),
+					X1: int(x0 + perChar*float64(i)),
+					Y1: int(y1),
+				},
+				Page: 1,
+			})
+			start = -1
+		}
+	}
+	return words
+}
+
+func (p *PaddleClient) postWithRetry(ctx context.Context, body []byte, contentType string) (*paddleResponse, error) {
+	attempts := max(p.MaxAttempts, 1)
+	backoff := p.Backoff
+	var lastErr error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		out, retry, err := p.post(ctx, body, contentType)
+		if err == nil {
+			return out, nil
+		}
+		lastErr = err
+		if !retry || attempt == attempts {
+			break
+		}
+
+		slog.WarnContext(ctx, "PaddleOCR attempt failed, retrying", "attempt", attempt, "error", err, "backoff", backoff)
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		if p.MaxBackoff > 0 && backoff > p.MaxBackoff {
+			backoff = p.MaxBackoff
+		}
+	}
+	return nil, lastErr
+}
+
+// post sends one request and reports whether a failure is worth retrying.
+func (p *PaddleClient) post(ctx context.Context, body []byte, contentType string) (*paddleResponse, bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.URL, bytes.NewReader(body))
 	if err != nil {
-		return "", err
+		return nil, false, err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	httpClient := p.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, ctx.Err() == nil, err
 	}
 	defer resp.Body.Close()
 
-	var out struct {
-		Text string `json:"text"`
+	if resp.StatusCode != http.StatusOK {
+		io.Copy(io.Discard, resp.Body)
+		return nil, resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests,
+			fmt.Errorf("paddle OCR returned %s", resp.Status)
 	}
-	json.NewDecoder(resp.Body).Decode(&out)
 
-	return out.Text, nil
+	var out paddleResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, false, fmt.Errorf("invalid paddle OCR response: %w", err)
+	}
+	return &out, false, nil
 }
 
-func (p *PaddleClient) ExtractTextFromFile(path string) (string, error) {
+func (p *PaddleClient) ExtractTextFromFile(ctx context.Context, path string) (string, error) {
 	b, err := os.ReadFile(path)
 	if err != nil {
 		return "", err
 	}
-	return p.ExtractText(b)
+	return p.ExtractText(ctx, b)
 }
 
-func (p *PaddleClient) ExtractTextFromImageBytes(img []byte) (string, error) {
-	return p.ExtractText(img)
+func (p *PaddleClient) ExtractTextFromImageBytes(ctx context.Context, img []byte) (string, error) {
+	return p.ExtractText(ctx, img)
+}
+
+// envInt reads a positive integer environment variable, falling back to def.
+func envInt(key string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(key))
+	if err != nil || n < 1 {
+		return def
+	}
+	return n
 }
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPaddleClientRetriesThenTripsBreaker(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if calls == 2 {
+			w.Write([]byte(`{"text":"INCOME TAX DEPARTMENT"}`))
+			return
+		}
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	p := &PaddleClient{URL: srv.URL, HTTP: srv.Client(), MaxAttempts: 2, Backoff: time.Millisecond, Breaker: NewCircuitBreaker(2, time.Hour)}
+
+	text, err := p.ExtractText(context.Background(), []byte("img"))
+	require.NoError(t, err, "503 is retried")
+	assert.Equal(t, "INCOME TAX DEPARTMENT", text)
+
+	_, err = p.ExtractText(context.Background(), []byte("img"))
+	assert.Error(t, err)
+	_, err = p.ExtractText(context.Background(), []byte("img"))
+	assert.Error(t, err)
+	assert.Equal(t, BreakerOpen, p.Health().State)
+
+	before := calls
+	_, err = p.ExtractText(context.Background(), []byte("img"))
+	assert.ErrorIs(t, err, ErrPaddleUnavailable)
+	assert.Equal(t, before, calls, "open breaker fails fast")
+}
+
+func TestPaddleClientStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		cancel() // the client disconnects while PaddleOCR is busy
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	p := &PaddleClient{URL: srv.URL, HTTP: srv.Client(), MaxAttempts: 3, Backoff: time.Hour, Breaker: NewCircuitBreaker(1, time.Hour)}
+	_, err := p.ExtractText(ctx, []byte("img"))
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 1, calls, "no retry after cancellation")
+	assert.Equal(t, BreakerClosed, p.Health().State, "cancellation is not a PaddleOCR failure")
+}
+
+func TestCircuitBreakerHalfOpen(t *testing.T) {
+	now := time.Now()
+	b := NewCircuitBreaker(1, time.Minute)
+	b.now = func() time.Time { return now }
+
+	b.Failure()
+	assert.False(t, b.Allow())
+
+	now = now.Add(2 * time.Minute)
+	assert.True(t, b.Allow(), "probe after cooldown")
+	assert.False(t, b.Allow(), "only one probe at a time")
+	b.Success()
+	assert.Equal(t, BreakerClosed, b.Status().State)
+}
+
+func TestSplitLineWords(t *testing.T) {
+	words := splitLineWords("NET PAY 45200", 90, [][2]float64{{100, 50}, {230, 52}, {230, 70}, {100, 68}})
+	require.Len(t, words, 3)
+	assert.Equal(t, "PAY", words[1].Text)
+	assert.Equal(t, 140, words[1].Box.X0)
+	assert.Equal(t, 170, words[1].Box.X1)
+	assert.Equal(t, 50, words[1].Box.Y0)
+	assert.Equal(t, 70, words[2].Box.Y1)
+}
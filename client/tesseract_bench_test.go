@@ -0,0 +1,77 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"testing"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+// Compare a pooled client with a new gosseract client per extraction (the
+// previous behaviour). Needs Tesseract and its eng traineddata:
+//
+//	TESSDATA_PREFIX=/usr/share/tesseract-ocr/5/tessdata go test ./client -bench Tesseract -run '^$'
+func benchImage(b *testing.B) string {
+	dataPath := os.Getenv("TESSDATA_PREFIX")
+	if _, err := os.Stat(dataPath + "/eng.traineddata"); dataPath == "" || err != nil {
+		b.Skip("TESSDATA_PREFIX with eng.traineddata required")
+	}
+
+	img := image.NewGray(image.Rect(0, 0, 600, 200))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	for x := 100; x < 500; x++ {
+		img.SetGray(x, 100, color.Gray{})
+	}
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		b.Fatal(err)
+	}
+	f, err := os.CreateTemp(b.TempDir(), "bench-*.png")
+	if err != nil {
+		b.Fatal(err)
+	}
+	f.Write(buf.Bytes())
+	f.Close()
+	return f.Name()
+}
+
+func BenchmarkTesseractPooled(b *testing.B) {
+	path := benchImage(b)
+	tc := NewTesseractClient(os.Getenv("TESSDATA_PREFIX"), 4, "eng")
+	defer tc.Close()
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := tc.extractText(context.Background(), path); err != nil {
+				b.Error(err)
+			}
+		}
+	})
+}
+
+func BenchmarkTesseractUnpooled(b *testing.B) {
+	path := benchImage(b)
+	dataPath := os.Getenv("TESSDATA_PREFIX")
+
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			c := gosseract.NewClient()
+			c.SetTessdataPrefix(dataPath)
+			c.SetLanguage("eng")
+			c.SetImage(path)
+			if _, err := c.Text(); err != nil {
+				b.Error(err)
+			}
+			c.Close()
+		}
+	})
+}
@@ -1,28 +1,165 @@
 package client
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
 
+	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/otiai10/gosseract/v2"
 )
 
+// defaultTesseractLang is used when no language is configured.
+const defaultTesseractLang = "eng"
+
+// maxTesseractPools bounds the languages with a pool at once; the least
+// recently used one is closed to make room for another.
+const maxTesseractPools = 8
+
+// tesseractLangRe matches Tesseract language specs such as "eng", "hin+eng" or "chi_sim".
+var tesseractLangRe = regexp.MustCompile(`^[a-z_]{3,10}(\+[a-z_]{3,10})*$`)
+
+// TesseractClient runs Tesseract through pools of reusable gosseract clients,
+// one pool per language, each holding up to poolSize clients; at most
+// maxPools languages have one at a time. Extractions use
+// lang (e.g. "hin+eng" for Devanagari cards) unless a call names another.
+// A cancelled context stops an extraction waiting for a pooled client or
+// about to start; a page already being recognized runs to completion, as
+// Tesseract cannot be interrupted through gosseract.
 type TesseractClient struct {
 	dataPath string
+	poolSize int
+	lang     string
+
+	mu       sync.Mutex
+	pools    map[string]*tesseractPool
+	order    []string // languages with a pool, least recently used first
+	maxPools int
+	total    chan struct{} // one token per client in existence, over all pools
 }
 
-func NewTesseractClient(dataPath string) *TesseractClient {
+func NewTesseractClient(dataPath string, poolSize int, lang string) *TesseractClient {
+	if lang == "" {
+		lang = defaultTesseractLang
+	}
 	return &TesseractClient{
 		dataPath: dataPath,
+		poolSize: poolSize,
+		lang:     lang,
+		pools:    map[string]*tesseractPool{},
+		maxPools: maxTesseractPools,
+		total:    make(chan struct{}, maxTesseractPools*max(poolSize, 1)),
+	}
+}
+
+// Lang is the language spec extractions use when a call names none.
+func (tc *TesseractClient) Lang() string {
+	return tc.lang
+}
+
+// ValidateLang checks a language spec ("hin+eng", "tam", ...) and, when the
+// tessdata directory is known, that every language's traineddata is installed.
+func (tc *TesseractClient) ValidateLang(lang string) error {
+	if !tesseractLangRe.MatchString(lang) {
+		return fmt.Errorf("invalid OCR language %q", lang)
+	}
+	if tc.dataPath == "" {
+		return nil
+	}
+	for _, code := range strings.Split(lang, "+") {
+		if _, err := os.Stat(filepath.Join(tc.dataPath, code+".traineddata")); err != nil {
+			return fmt.Errorf("OCR language %q is not installed", code)
+		}
+	}
+	return nil
+}
+
+// normalizeLang drops the repeated languages of a spec ("eng+hin+eng" is
+// "eng+hin"), keeping their order: the first one is the primary language.
+func normalizeLang(lang string) string {
+	var codes []string
+	for _, code := range strings.Split(lang, "+") {
+		if !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	return strings.Join(codes, "+")
+}
+
+// withClient lends fn a pooled client for lang, unless ctx is done first.
+func (tc *TesseractClient) withClient(ctx context.Context, lang string, fn func(client *gosseract.Client) error) error {
+	lang = normalizeLang(lang)
+	var pool *tesseractPool
+	var client *gosseract.Client
+	for client == nil {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		var err error
+		if pool, err = tc.pool(lang); err != nil {
+			return err
+		}
+		client, err = pool.get(ctx)
+		// a pool evicted while waiting for it: wait for the next one
+		if errors.Is(err, errTesseractClosed) && !tc.closed() {
+			continue
+		}
+		if err != nil {
+			return err
+		}
 	}
+	defer pool.put(client)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return fn(client)
+}
+
+// pool returns the pool for lang, creating it, and closing the least recently
+// used one when maxPools languages already have one.
+func (tc *TesseractClient) pool(lang string) (*tesseractPool, error) {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	if tc.pools == nil {
+		return nil, errTesseractClosed
+	}
+	if i := slices.Index(tc.order, lang); i >= 0 {
+		tc.order = append(slices.Delete(tc.order, i, i+1), lang)
+		return tc.pools[lang], nil
+	}
+	// Languages come from requests: never create a pool for an unusable one
+	if err := tc.ValidateLang(lang); err != nil {
+		return nil, err
+	}
+	if len(tc.order) >= tc.maxPools {
+		oldest := tc.order[0]
+		tc.pools[oldest].close()
+		delete(tc.pools, oldest)
+		tc.order = tc.order[1:]
+	}
+	pool := newTesseractPool(lang, tc.dataPath, tc.poolSize, tc.total)
+	tc.pools[lang] = pool
+	tc.order = append(tc.order, lang)
+	return pool, nil
+}
+
+func (tc *TesseractClient) closed() bool {
+	tc.mu.Lock()
+	defer tc.mu.Unlock()
+	return tc.pools == nil
 }
 
 // ExtractTextFromFile extracts text from an uploaded file using Tesseract OCR
-func (tc *TesseractClient) ExtractTextFromFile(fileHeader *multipart.FileHeader) (string, error) {
+func (tc *TesseractClient) ExtractTextFromFile(ctx context.Context, fileHeader *multipart.FileHeader) (string, error) {
 	// Open uploaded file
 	file, err := fileHeader.Open()
 	if err != nil {
@@ -38,7 +175,7 @@ func (tc *TesseractClient) ExtractTextFromFile(fileHeader *multipart.FileHeader)
 	defer os.Remove(tempFile)
 
 	// Extract text using Tesseract
-	text, err := tc.extractText(tempFile)
+	text, err := tc.extractText(ctx, tempFile)
 	if err != nil {
 		return "", fmt.Errorf("OCR extraction failed: %w", err)
 	}
@@ -63,34 +200,27 @@ func (tc *TesseractClient) CreateTempFile(file multipart.File, filename string)
 	return tempFile.Name(), nil
 }
 
-func (tc *TesseractClient) extractText(filePath string) (string, error) {
-	client := gosseract.NewClient()
-	defer client.Close()
-
-	// VERY IMPORTANT: Explicitly set correct tessdata path
-	client.SetTessdataPrefix("/usr/share/tesseract-ocr/5/tessdata/")
-
-	// Set language to English
-	if err := client.SetLanguage("eng"); err != nil {
-		return "", fmt.Errorf("failed to set language: %w", err)
-	}
-
-	// Set input image
-	if err := client.SetImage(filePath); err != nil {
-		return "", fmt.Errorf("failed to set image: %w", err)
-	}
-
-	// Extract text
-	text, err := client.Text()
-	if err != nil {
-		return "", fmt.Errorf("failed to extract text: %w", err)
-	}
-
-	return text, nil
+func (tc *TesseractClient) extractText(ctx context.Context, filePath string) (string, error) {
+	var text string
+	err := tc.withClient(ctx, tc.lang, func(client *gosseract.Client) error {
+		// Set input image
+		if err := client.SetImage(filePath); err != nil {
+			return fmt.Errorf("failed to set image: %w", err)
+		}
+
+		// Extract text
+		var err error
+		text, err = client.Text()
+		if err != nil {
+			return fmt.Errorf("failed to extract text: %w", err)
+		}
+		return nil
+	})
+	return text, err
 }
 
 // ExtractTextAndQualityFromFile extracts text and quality scores from an uploaded file
-func (tc *TesseractClient) ExtractTextAndQualityFromFile(fileHeader *multipart.FileHeader) (string, float64, error) {
+func (tc *TesseractClient) ExtractTextAndQualityFromFile(ctx context.Context, fileHeader *multipart.FileHeader) (string, float64, error) {
 	file, err := fileHeader.Open()
 	if err != nil {
 		return "", 0, fmt.Errorf("failed to open file: %w", err)
@@ -103,56 +233,112 @@ func (tc *TesseractClient) ExtractTextAndQualityFromFile(fileHeader *multipart.F
 	}
 	defer os.Remove(tempFile)
 
-	return tc.ExtractTextAndQuality(tempFile)
+	return tc.ExtractTextAndQuality(ctx, tempFile)
 }
 
-func (tc *TesseractClient) ExtractTextAndQuality(filePath string) (string, float64, error) {
-	client := gosseract.NewClient()
-	defer client.Close()
+// ExtractTextAndQualityFromBytes extracts text and average word confidence from image bytes.
+func (tc *TesseractClient) ExtractTextAndQualityFromBytes(ctx context.Context, data []byte) (string, float64, error) {
+	return tc.ExtractTextAndQualityFromBytesLang(ctx, data, "")
+}
+
+// ExtractTextAndQualityFromBytesLang is ExtractTextAndQualityFromBytes in the
+// given language spec; an empty lang uses the configured language.
+func (tc *TesseractClient) ExtractTextAndQualityFromBytesLang(ctx context.Context, data []byte, lang string) (string, float64, error) {
+	st, err := tc.ExtractStructured(ctx, data, lang)
+	if err != nil {
+		return "", 0, err
+	}
+	return st.Text, st.Confidence, nil
+}
 
-	client.SetTessdataPrefix("/usr/share/tesseract-ocr/5/tessdata/")
-	if err := client.SetLanguage("eng"); err != nil {
-		return "", 0, fmt.Errorf("failed to set language: %w", err)
+// ExtractStructured OCRs image bytes and returns the text together with every
+// word's bounding box and confidence. An empty lang uses the configured language.
+func (tc *TesseractClient) ExtractStructured(ctx context.Context, data []byte, lang string) (*dto.StructuredText, error) {
+	tempFile, err := os.CreateTemp("", "tess-bytes-*.img")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
 	}
+	defer os.Remove(tempFile.Name())
 
-	if err := client.SetImage(filePath); err != nil {
-		return "", 0, fmt.Errorf("failed to set image: %w", err)
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return nil, fmt.Errorf("failed to write image bytes: %w", err)
 	}
+	tempFile.Close()
 
-	text, err := client.Text()
+	return tc.extractStructured(ctx, tempFile.Name(), lang)
+}
+
+func (tc *TesseractClient) ExtractTextAndQuality(ctx context.Context, filePath string) (string, float64, error) {
+	st, err := tc.extractStructured(ctx, filePath, "")
 	if err != nil {
-		return "", 0, fmt.Errorf("failed to extract text: %w", err)
+		return "", 0, err
 	}
+	return st.Text, st.Confidence, nil
+}
 
-	// Get bounding boxes to calculate confidence
-	boxes, err := client.GetBoundingBoxes(gosseract.RIL_WORD)
+func (tc *TesseractClient) extractStructured(ctx context.Context, filePath, lang string) (*dto.StructuredText, error) {
+	if lang == "" {
+		lang = tc.lang
+	}
+	var text string
+	var boxes []gosseract.BoundingBox
+	err := tc.withClient(ctx, lang, func(client *gosseract.Client) error {
+		if err := client.SetImage(filePath); err != nil {
+			return fmt.Errorf("failed to set image: %w", err)
+		}
+
+		var err error
+		text, err = client.Text()
+		if err != nil {
+			return fmt.Errorf("failed to extract text: %w", err)
+		}
+
+		// Word boxes also give the confidence; without them confidence is 0
+		boxes, _ = client.GetBoundingBoxes(gosseract.RIL_WORD)
+		return nil
+	})
 	if err != nil {
-		// If bounding boxes fail, just return text and 0 confidence
-		return text, 0, nil
+		return nil, err
 	}
 
+	st := &dto.StructuredText{Text: text, Words: make([]dto.OCRWord, 0, len(boxes))}
 	var totalConf float64
-	var count int
 	for _, box := range boxes {
 		totalConf += box.Confidence
-		count++
+		if strings.TrimSpace(box.Word) == "" {
+			continue
+		}
+		st.Words = append(st.Words, dto.OCRWord{
+			Text:       strings.TrimSpace(box.Word),
+			Confidence: box.Confidence,
+			Box:        dto.BoundingBox{X0: box.Box.Min.X, Y0: box.Box.Min.Y, X1: box.Box.Max.X, Y1: box.Box.Max.Y},
+			Page:       1,
+		})
 	}
-
-	avgConf := 0.0
-	if count > 0 {
-		avgConf = totalConf / float64(count)
+	if len(boxes) > 0 {
+		st.Confidence = totalConf / float64(len(boxes))
 	}
-
-	return text, avgConf, nil
+	return st, nil
 }
 
-// Close performs cleanup
+// Close releases the pooled clients. Extractions still running finish and
+// their clients are closed when returned; later extractions fail.
 func (tc *TesseractClient) Close() {
-	log.Println("Tesseract client closed")
+	tc.mu.Lock()
+	pools := tc.pools
+	tc.pools = nil
+	tc.order = nil
+	tc.mu.Unlock()
+
+	for _, pool := range pools {
+		pool.close()
+	}
+	slog.Info("Tesseract client closed")
 }
 
 // ExtractTextFromBytes extracts text directly from an image byte slice.
-func (tc *TesseractClient) ExtractTextFromBytes(data []byte) (string, error) {
+func (tc *TesseractClient) ExtractTextFromBytes(ctx context.Context, data []byte) (string, error) {
 	// Create a temp file to store the image
 	tempFile, err := os.CreateTemp("", "tess-bytes-*.png")
 	if err != nil {
@@ -167,5 +353,5 @@ func (tc *TesseractClient) ExtractTextFromBytes(data []byte) (string, error) {
 	tempFile.Close()
 
 	// Now reuse existing extractText()
-	return tc.extractText(tempFile.Name())
+	return tc.extractText(ctx, tempFile.Name())
 }
@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+)
+
+var (
+	osdRotateRe           = regexp.MustCompile(`(?m)^Rotate:\s*(\d+)`)
+	osdConfidenceRe       = regexp.MustCompile(`(?m)^Orientation confidence:\s*([\d.]+)`)
+	osdScriptRe           = regexp.MustCompile(`(?m)^Script:\s*(\S+)`)
+	osdScriptConfidenceRe = regexp.MustCompile(`(?m)^Script confidence:\s*([\d.]+)`)
+)
+
+// DetectOrientation runs Tesseract's orientation and script detection (the
+// tesseract command with --psm 0, as gosseract does not expose it) on an
+// encoded image and returns how far to turn it clockwise to read upright,
+// with Tesseract's confidence in that. It needs osd.traineddata; pages with
+// too little text fail.
+func (tc *TesseractClient) DetectOrientation(ctx context.Context, data []byte) (rotate int, confidence float64, err error) {
+	out, err := tc.osd(ctx, data)
+	if err != nil {
+		return 0, 0, err
+	}
+	return parseOSD(out)
+}
+
+// DetectScript runs the same detection and returns the script the page is
+// written in ("Latin", "Devanagari", "Tamil", ...) with Tesseract's
+// confidence in that.
+func (tc *TesseractClient) DetectScript(ctx context.Context, data []byte) (script string, confidence float64, err error) {
+	out, err := tc.osd(ctx, data)
+	if err != nil {
+		return "", 0, err
+	}
+	return parseOSDScript(out)
+}
+
+// osd returns the output of tesseract --psm 0 on an encoded image.
+func (tc *TesseractClient) osd(ctx context.Context, data []byte) (string, error) {
+	tempFile, err := os.CreateTemp("", "tess-osd-*.png")
+	if err != nil {
+		return "", fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	if _, err := tempFile.Write(data); err != nil {
+		tempFile.Close()
+		return "", fmt.Errorf("failed to write image bytes: %w", err)
+	}
+	tempFile.Close()
+
+	args := []string{tempFile.Name(), "stdout", "--psm", "0"}
+	if tc.dataPath != "" {
+		args = append(args, "--tessdata-dir", tc.dataPath)
+	}
+	output, err := exec.CommandContext(ctx, "tesseract", args...).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("tesseract OSD failed: %v\nOutput: %s", err, string(output))
+	}
+	return string(output), nil
+}
+
+// parseOSD reads the "Rotate:" and "Orientation confidence:" lines of
+// tesseract --psm 0 output.
+func parseOSD(out string) (int, float64, error) {
+	m := osdRotateRe.FindStringSubmatch(out)
+	if m == nil {
+		return 0, 0, fmt.Errorf("no orientation in OSD output: %s", out)
+	}
+	rotate, _ := strconv.Atoi(m[1])
+	var confidence float64
+	if m := osdConfidenceRe.FindStringSubmatch(out); m != nil {
+		confidence, _ = strconv.ParseFloat(m[1], 64)
+	}
+	return rotate, confidence, nil
+}
+
+// parseOSDScript reads the "Script:" and "Script confidence:" lines of
+// tesseract --psm 0 output.
+func parseOSDScript(out string) (string, float64, error) {
+	m := osdScriptRe.FindStringSubmatch(out)
+	if m == nil {
+		return "", 0, fmt.Errorf("no script in OSD output: %s", out)
+	}
+	var confidence float64
+	if c := osdScriptConfidenceRe.FindStringSubmatch(out); c != nil {
+		confidence, _ = strconv.ParseFloat(c[1], 64)
+	}
+	return m[1], confidence, nil
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOSD(t *testing.T) {
+	out := `Page number: 0
+Orientation in degrees: 270
+Rotate: 90
+Orientation confidence: 18.42
+Script: Latin
+Script confidence: 4.27
+`
+	rotate, confidence, err := parseOSD(out)
+	require.NoError(t, err)
+	assert.Equal(t, 90, rotate)
+	assert.Equal(t, 18.42, confidence)
+
+	_, _, err = parseOSD("Too few characters. Skipping this page\nError during processing.")
+	assert.Error(t, err)
+
+	script, confidence, err := parseOSDScript(out)
+	require.NoError(t, err)
+	assert.Equal(t, "Latin", script)
+	assert.Equal(t, 4.27, confidence)
+}
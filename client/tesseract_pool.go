@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+var errTesseractClosed = errors.New("tesseract client is closed")
+
+// tesseractPool lends long-lived gosseract clients for one language. Creating a
+// client loads the traineddata, which dominates the cost of a small page, so
+// clients are kept and reused. A gosseract.Client is not safe for concurrent
+// use: each one serves a single extraction at a time, and at most size exist.
+// Each client also holds a token of total, shared by the pools of all
+// languages, when it is not nil.
+type tesseractPool struct {
+	lang     string
+	dataPath string
+
+	idle  chan *gosseract.Client
+	slots chan struct{} // one token per client in existence
+	total chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func newTesseractPool(lang, dataPath string, size int, total chan struct{}) *tesseractPool {
+	if size < 1 {
+		size = 1
+	}
+	return &tesseractPool{
+		lang:     lang,
+		dataPath: dataPath,
+		idle:     make(chan *gosseract.Client, size),
+		slots:    make(chan struct{}, size),
+		total:    total,
+	}
+}
+
+// get returns an idle client, creates one if the pool is below size, or waits
+// for one to be returned until ctx is done.
+func (p *tesseractPool) get(ctx context.Context) (*gosseract.Client, error) {
+	if p.isClosed() {
+		return nil, errTesseractClosed
+	}
+
+	select {
+	case c := <-p.idle:
+		return c, nil
+	default:
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case c := <-p.idle:
+		return c, nil
+	case p.slots <- struct{}{}:
+		if p.total != nil {
+			select {
+			case <-ctx.Done():
+				<-p.slots
+				return nil, ctx.Err()
+			case p.total <- struct{}{}:
+			}
+		}
+		if p.isClosed() {
+			p.release()
+			return nil, errTesseractClosed
+		}
+		c, err := p.newClient()
+		if err != nil {
+			p.release()
+			return nil, err
+		}
+		return c, nil
+	}
+}
+
+// release gives back the tokens of a client no longer in existence.
+func (p *tesseractPool) release() {
+	<-p.slots
+	if p.total != nil {
+		<-p.total
+	}
+}
+
+// put returns a client to the pool, or closes it once the pool is closed.
+func (p *tesseractPool) put(c *gosseract.Client) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.closed {
+		c.Close()
+		p.release()
+		return
+	}
+	p.idle <- c // never blocks: at most size clients exist
+}
+
+// close closes the idle clients now and the busy ones as they are returned.
+func (p *tesseractPool) close() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+	for {
+		select {
+		case c := <-p.idle:
+			c.Close()
+			p.release()
+		default:
+			return
+		}
+	}
+}
+
+func (p *tesseractPool) isClosed() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.closed
+}
+
+func (p *tesseractPool) newClient() (*gosseract.Client, error) {
+	c := gosseract.NewClient()
+	if p.dataPath != "" {
+		c.SetTessdataPrefix(p.dataPath)
+	}
+	if err := c.SetLanguage(p.lang); err != nil {
+		c.Close()
+		return nil, fmt.Errorf("failed to set language: %w", err)
+	}
+	return c, nil
+}
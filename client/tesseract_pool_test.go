@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/otiai10/gosseract/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTesseractPoolReusesClients(t *testing.T) {
+	p := newTesseractPool("eng", "", 1, nil)
+
+	c1, err := p.get(context.Background())
+	require.NoError(t, err)
+
+	// The only client is busy: a second get waits until it is returned
+	got := make(chan *gosseract.Client)
+	go func() {
+		c, _ := p.get(context.Background())
+		got <- c
+	}()
+	select {
+	case <-got:
+		t.Fatal("get did not wait for a free client")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	p.put(c1)
+	c2 := <-got
+	assert.Same(t, c1, c2, "client is reused, not recreated")
+
+	p.close()
+	p.put(c2)
+	_, err = p.get(context.Background())
+	assert.ErrorIs(t, err, errTesseractClosed)
+}
+
+func TestTesseractPoolGetCancelled(t *testing.T) {
+	p := newTesseractPool("eng", "", 1, nil)
+	c, err := p.get(context.Background())
+	require.NoError(t, err)
+	defer p.put(c)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = p.get(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "waiting for a busy client ends with the request")
+}
+
+func TestTesseractClientClose(t *testing.T) {
+	tc := NewTesseractClient("", 2, "")
+	tc.Close()
+	_, err := tc.ExtractTextFromBytes(context.Background(), []byte("not an image"))
+	assert.ErrorIs(t, err, errTesseractClosed)
+}
+
+func TestTesseractPoolsShareTotal(t *testing.T) {
+	total := make(chan struct{}, 1)
+	eng := newTesseractPool("eng", "", 1, total)
+	hin := newTesseractPool("hin", "", 1, total)
+
+	c, err := eng.get(context.Background())
+	require.NoError(t, err)
+	eng.put(c)
+
+	// the idle eng client holds the only token
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = hin.get(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	eng.close()
+	c, err = hin.get(context.Background())
+	require.NoError(t, err)
+	hin.put(c)
+	hin.close()
+}
+
+func TestTesseractClientEvictsLeastRecentlyUsedPool(t *testing.T) {
+	tc := NewTesseractClient("", 1, "")
+	defer tc.Close()
+	tc.maxPools = 2
+
+	noop := func(*gosseract.Client) error { return nil }
+	for _, lang := range []string{"eng", "hin", "eng+eng", "tam"} {
+		require.NoError(t, tc.withClient(context.Background(), lang, noop), lang)
+	}
+	assert.Equal(t, []string{"eng", "tam"}, tc.order, "hin was used least recently")
+	assert.Len(t, tc.pools, 2)
+}
+
+func TestNormalizeLang(t *testing.T) {
+	assert.Equal(t, "eng", normalizeLang("eng+eng+eng"))
+	assert.Equal(t, "hin+eng", normalizeLang("hin+eng+hin"))
+}
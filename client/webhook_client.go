@@ -0,0 +1,178 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// WebhookClient POSTs extraction results to caller-supplied callback URLs,
+// retrying with exponential backoff. When WEBHOOK_SECRET is set every body is
+// signed: X-Webhook-Signature: sha256=<hex HMAC of the body>. Results carry
+// PII, so callbacks go over https to public hosts only: like DocumentFetcher
+// it refuses hosts resolving to loopback, private or link-local addresses,
+// unless WEBHOOK_ALLOW_PRIVATE=true (local development), which also allows
+// plain http.
+type WebhookClient struct {
+	HTTP        *http.Client
+	Secret      string
+	MaxAttempts int
+	Backoff     time.Duration // delay before the first retry; doubles per attempt
+	MaxBackoff  time.Duration
+	// Redact, when set, rewrites the JSON body before it is signed and sent
+	// (masking identity numbers the same way API responses are masked).
+	Redact func(body []byte) ([]byte, error)
+}
+
+func NewWebhookClient() *WebhookClient {
+	attempts, err := strconv.Atoi(os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	if err != nil || attempts < 1 {
+		attempts = 5
+	}
+	dialer := &net.Dialer{Timeout: 10 * time.Second}
+	if !allowPrivateWebhooks() {
+		dialer.Control = refusePrivate
+	}
+	return &WebhookClient{
+		HTTP: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: &http.Transport{DialContext: dialer.DialContext, Proxy: http.ProxyFromEnvironment},
+		},
+		Secret:      os.Getenv("WEBHOOK_SECRET"),
+		MaxAttempts: attempts,
+		Backoff:     2 * time.Second,
+		MaxBackoff:  time.Minute,
+	}
+}
+
+func allowPrivateWebhooks() bool {
+	return os.Getenv("WEBHOOK_ALLOW_PRIVATE") == "true"
+}
+
+// ValidateCallbackURL accepts absolute https URLs of hosts that are not
+// loopback or private addresses, so a bad callback fails the request rather
+// than its delivery; names are checked again once resolved, on delivery.
+// With WEBHOOK_ALLOW_PRIVATE=true any absolute http(s) URL is accepted.
+func ValidateCallbackURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return fmt.Errorf("invalid callback_url: %w", err)
+	}
+	if allowPrivateWebhooks() {
+		if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("invalid callback_url: must be an absolute http(s) URL")
+		}
+		return nil
+	}
+	if u.Scheme != "https" || u.Host == "" {
+		return fmt.Errorf("invalid callback_url: must be an absolute https URL")
+	}
+	host := u.Hostname()
+	if strings.EqualFold(host, "localhost") || strings.HasSuffix(strings.ToLower(host), ".localhost") {
+		return fmt.Errorf("invalid callback_url: %s is not a public host", host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if err := refusePrivate("tcp", net.JoinHostPort(host, "443"), nil); err != nil {
+			return fmt.Errorf("invalid callback_url: %w", err)
+		}
+	}
+	return nil
+}
+
+// Notify delivers the event in the background. A nil client or empty URL is a no-op.
+func (w *WebhookClient) Notify(callbackURL string, event dto.WebhookEvent) {
+	if w == nil || callbackURL == "" {
+		return
+	}
+	go func() {
+		if err := w.Deliver(context.Background(), callbackURL, event); err != nil {
+			slog.Error("Webhook delivery failed", "event", event.Event, "url", callbackURL, "error", err)
+		}
+	}()
+}
+
+// Deliver POSTs the event until the receiver answers 2xx, the receiver rejects
+// it with a non-retryable 4xx, or the attempts run out.
+func (w *WebhookClient) Deliver(ctx context.Context, callbackURL string, event dto.WebhookEvent) error {
+	if event.Timestamp == "" {
+		event.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	if w.Redact != nil {
+		if body, err = w.Redact(body); err != nil {
+			return err
+		}
+	}
+
+	backoff := w.Backoff
+	var lastErr error
+	for attempt := 1; attempt <= w.MaxAttempts; attempt++ {
+		retry, err := w.post(ctx, callbackURL, event.Event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry || attempt == w.MaxAttempts {
+			break
+		}
+
+		slog.Warn("Webhook attempt failed, retrying", "event", event.Event, "attempt", attempt, "error", err, "backoff", backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if w.MaxBackoff > 0 && backoff > w.MaxBackoff {
+			backoff = w.MaxBackoff
+		}
+	}
+	return lastErr
+}
+
+// post sends one attempt and reports whether a failure is worth retrying.
+func (w *WebhookClient) post(ctx context.Context, callbackURL, eventName string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, callbackURL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", eventName)
+	if w.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(w.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Webhook-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := w.HTTP.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("receiver returned %s", resp.Status)
+	default:
+		return false, fmt.Errorf("receiver rejected webhook: %s", resp.Status)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestWebhookDeliverRetriesAndSigns(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(body)
+		assert.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Webhook-Signature"))
+		assert.Equal(t, "pan.completed", r.Header.Get("X-Webhook-Event"))
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	w := &WebhookClient{HTTP: srv.Client(), Secret: "s3cret", MaxAttempts: 3}
+	err := w.Deliver(context.Background(), srv.URL, dto.NewWebhookEvent("pan", map[string]string{"pan": "ABCDE1234F"}, nil))
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+}
+
+func TestWebhookDeliverStopsOnClientError(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer srv.Close()
+
+	w := &WebhookClient{HTTP: srv.Client(), MaxAttempts: 3}
+	err := w.Deliver(context.Background(), srv.URL, dto.NewWebhookEvent("itr", nil, nil))
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestValidateCallbackURL(t *testing.T) {
+	assert.NoError(t, ValidateCallbackURL("https://example.com/hook"))
+	assert.Error(t, ValidateCallbackURL("/relative"))
+	assert.Error(t, ValidateCallbackURL("ftp://example.com"))
+	assert.Error(t, ValidateCallbackURL("http://example.com/hook"), "results are not sent in cleartext")
+	assert.Error(t, ValidateCallbackURL("https://127.0.0.1/hook"))
+	assert.Error(t, ValidateCallbackURL("https://169.254.169.254/latest/meta-data"))
+	assert.Error(t, ValidateCallbackURL("https://10.0.0.7:8443/hook"))
+	assert.Error(t, ValidateCallbackURL("https://localhost/hook"))
+
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+	assert.NoError(t, ValidateCallbackURL("http://127.0.0.1:9000/hook"))
+}
+
+func TestWebhookRefusesLoopbackCallback(t *testing.T) {
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer srv.Close()
+
+	// the name resolves to loopback only once dialed
+	w := NewWebhookClient()
+	w.MaxAttempts = 1
+	err := w.Deliver(context.Background(), strings.Replace(srv.URL, "127.0.0.1", "localhost", 1), dto.NewWebhookEvent("pan", nil, nil))
+	assert.ErrorContains(t, err, "non-public address")
+	assert.Zero(t, calls)
+}
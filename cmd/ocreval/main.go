@@ -0,0 +1,343 @@
+// Command ocreval runs the labeled fixture corpus through every configured
+// OCR engine / preprocessing combination and prints per-field accuracy and
+// latency tables.
+//
+// Corpus layout: each document (PNG/JPG/PDF) sits next to a label file with the
+// same base name and a .json extension:
+//
+//	corpus/slip_acme_01.jpg
+//	corpus/slip_acme_01.json   {"doc_type": "salary_slip", "fields": {"net_salary": 72450, "employee_name": "Priya Nair"}}
+//
+// Usage:
+//
+//	go run ./cmd/ocreval -corpus ./testdata/corpus -engines paddle,tesseract -preprocess none,binarize
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	"image/png"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/config"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageprep"
+)
+
+// label is the expected output for one corpus document.
+type label struct {
+	DocType string                 `json:"doc_type"`
+	Fields  map[string]interface{} `json:"fields"`
+}
+
+type fixture struct {
+	path  string
+	label label
+}
+
+// engine turns PNG bytes into text.
+type engine func(context.Context, []byte) (string, error)
+
+// comboStats accumulates results for one engine + preprocessing combination.
+type comboStats struct {
+	name      string
+	latencies []time.Duration
+	failures  int
+	correct   map[string]int
+	total     map[string]int
+}
+
+func main() {
+	corpusDir := flag.String("corpus", "testdata/corpus", "directory holding documents and their .json labels")
+	engineList := flag.String("engines", "paddle,tesseract", "comma-separated OCR engines to evaluate")
+	prepList := flag.String("preprocess", strings.Join(imageprep.Names(), ","), "comma-separated preprocessing steps")
+	jsonOut := flag.Bool("json", false, "emit results as JSON instead of tables")
+	flag.Parse()
+
+	fixtures, err := loadCorpus(*corpusDir)
+	if err != nil {
+		log.Fatalf("Failed to load corpus: %v", err)
+	}
+	if len(fixtures) == 0 {
+		log.Fatalf("No labeled documents found in %s", *corpusDir)
+	}
+
+	engines, err := buildEngines(strings.Split(*engineList, ","))
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+
+	pdfProcessor := service.NewPDFProcessor(service.DefaultPDFLimits, service.PDFRendering{})
+
+	var results []*comboStats
+	for _, engName := range sortedKeys(engines) {
+		for _, prepName := range strings.Split(*prepList, ",") {
+			prep, err := imageprep.Lookup(strings.TrimSpace(prepName))
+			if err != nil {
+				log.Fatalf("%v", err)
+			}
+			stats := &comboStats{
+				name:    engName + "+" + strings.TrimSpace(prepName),
+				correct: map[string]int{},
+				total:   map[string]int{},
+			}
+			for _, fx := range fixtures {
+				evaluate(fx, engines[engName], prep, pdfProcessor, stats)
+			}
+			results = append(results, stats)
+		}
+	}
+
+	if *jsonOut {
+		writeJSON(results)
+		return
+	}
+	writeTables(results)
+}
+
+func buildEngines(names []string) (map[string]engine, error) {
+	engines := map[string]engine{}
+	for _, n := range names {
+		switch strings.TrimSpace(n) {
+		case "paddle":
+			paddle, err := client.NewPaddleClient()
+			if err != nil {
+				return nil, fmt.Errorf("paddle engine: %w", err)
+			}
+			engines["paddle"] = paddle.ExtractText
+		case "tesseract":
+			cfg := config.LoadConfig()
+			tess := client.NewTesseractClient(cfg.TesseractDataPath, cfg.TesseractPoolSize, cfg.TesseractLang)
+			engines["tesseract"] = tess.ExtractTextFromBytes
+		default:
+			return nil, fmt.Errorf("unknown engine %q", n)
+		}
+	}
+	return engines, nil
+}
+
+func loadCorpus(dir string) ([]fixture, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var fixtures []fixture
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".png" && ext != ".jpg" && ext != ".jpeg" && ext != ".pdf") {
+			continue
+		}
+		labelPath := filepath.Join(dir, strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))+".json")
+		raw, err := os.ReadFile(labelPath)
+		if err != nil {
+			log.Printf("Skipping %s: no label file", e.Name())
+			continue
+		}
+		var l label
+		if err := json.Unmarshal(raw, &l); err != nil {
+			return nil, fmt.Errorf("bad label %s: %w", labelPath, err)
+		}
+		fixtures = append(fixtures, fixture{path: filepath.Join(dir, e.Name()), label: l})
+	}
+	return fixtures, nil
+}
+
+// evaluate OCRs one fixture with the given combination and scores each labeled field.
+func evaluate(fx fixture, eng engine, prep imageprep.Step, pdf service.PDFProcessor, stats *comboStats) {
+	pages, err := loadPages(fx.path, pdf)
+	if err != nil {
+		log.Printf("%s: %v", fx.path, err)
+		stats.failures++
+		return
+	}
+
+	start := time.Now()
+	var text strings.Builder
+	for _, page := range pages {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, prep(page)); err != nil {
+			continue
+		}
+		pageText, err := eng(context.Background(), buf.Bytes())
+		if err != nil {
+			log.Printf("%s [%s]: %v", fx.path, stats.name, err)
+			continue
+		}
+		text.WriteString(pageText)
+		text.WriteString("\n")
+	}
+	stats.latencies = append(stats.latencies, time.Since(start))
+
+	got, err := parseFields(fx.label.DocType, text.String())
+	if err != nil {
+		log.Printf("%s: %v", fx.path, err)
+		stats.failures++
+		return
+	}
+
+	for field, want := range fx.label.Fields {
+		stats.total[field]++
+		if fieldMatches(want, got[field]) {
+			stats.correct[field]++
+		}
+	}
+}
+
+func loadPages(path string, pdf service.PDFProcessor) ([]image.Image, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".pdf") {
+		return service.CollectImages(pdf.ExtractImages(context.Background(), data, "", service.RenderOptions{}))
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return []image.Image{img}, nil
+}
+
+// parseFields runs the production parser for docType and flattens the result by json field name.
+func parseFields(docType, text string) (map[string]interface{}, error) {
+	var parsed interface{}
+	switch dto.DocumentType(docType) {
+	case dto.DocTypeSalarySlip:
+		parsed = utils.ParseSalarySlip(text)
+	case dto.DocTypeBankStatement:
+		stmt := utils.ParseBankStatement(text)
+		parsed = map[string]interface{}{
+			"account_holder_name": stmt.AccountHolderName,
+			"account_number":      stmt.AccountNumber,
+			"transaction_count":   len(stmt.Transactions),
+		}
+	case "pan":
+		p := utils.ParsePANText(text)
+		parsed = dto.PANResponse{PAN: p.PAN, Name: p.Name, FatherName: p.FatherName, DOB: p.DOB}
+	case "aadhaar":
+		parsed = utils.ParseAadhaarFromText(text)
+	case "itr":
+		parsed = utils.ParseITR(text)
+	default:
+		return nil, fmt.Errorf("unsupported doc_type %q", docType)
+	}
+
+	raw, err := json.Marshal(parsed)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]interface{}{}
+	return out, json.Unmarshal(raw, &out)
+}
+
+func fieldMatches(want, got interface{}) bool {
+	if wf, ok := want.(float64); ok {
+		gf, ok := got.(float64)
+		return ok && math.Abs(wf-gf) < 0.01
+	}
+	return strings.EqualFold(strings.TrimSpace(fmt.Sprint(want)), strings.TrimSpace(fmt.Sprint(got)))
+}
+
+func writeTables(results []*comboStats) {
+	fields := map[string]bool{}
+	for _, r := range results {
+		for f := range r.total {
+			fields[f] = true
+		}
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD ACCURACY")
+	header := "field"
+	for _, r := range results {
+		header += "\t" + r.name
+	}
+	fmt.Fprintln(w, header)
+	for _, f := range sortedKeys(fields) {
+		row := f
+		for _, r := range results {
+			row += "\t" + accuracy(r.correct[f], r.total[f])
+		}
+		fmt.Fprintln(w, row)
+	}
+	w.Flush()
+
+	fmt.Println()
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "LATENCY\tdocs\tfailures\tmean\tp95")
+	for _, r := range results {
+		mean, p95 := latencyStats(r.latencies)
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%s\n", r.name, len(r.latencies), r.failures, mean, p95)
+	}
+	w.Flush()
+}
+
+func writeJSON(results []*comboStats) {
+	type out struct {
+		Combo     string            `json:"combo"`
+		Accuracy  map[string]string `json:"accuracy"`
+		Failures  int               `json:"failures"`
+		MeanMs    int64             `json:"mean_ms"`
+		P95Ms     int64             `json:"p95_ms"`
+		Documents int               `json:"documents"`
+	}
+	var all []out
+	for _, r := range results {
+		acc := map[string]string{}
+		for f, t := range r.total {
+			acc[f] = accuracy(r.correct[f], t)
+		}
+		mean, p95 := latencyStats(r.latencies)
+		all = append(all, out{r.name, acc, r.failures, mean.Milliseconds(), p95.Milliseconds(), len(r.latencies)})
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	enc.Encode(all)
+}
+
+func accuracy(correct, total int) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%% (%d/%d)", 100*float64(correct)/float64(total), correct, total)
+}
+
+func latencyStats(d []time.Duration) (time.Duration, time.Duration) {
+	if len(d) == 0 {
+		return 0, 0
+	}
+	sorted := append([]time.Duration(nil), d...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var sum time.Duration
+	for _, v := range sorted {
+		sum += v
+	}
+	idx := int(math.Ceil(0.95*float64(len(sorted)))) - 1
+	return (sum / time.Duration(len(sorted))).Round(time.Millisecond), sorted[idx].Round(time.Millisecond)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
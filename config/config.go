@@ -1,11 +1,177 @@
 package config
 
-import "os"
+import (
+	"os"
+	"runtime"
+	"runtime/debug"
+	"strconv"
+	"strings"
+)
 
 type Config struct {
-	ServerPort        string
-	TesseractDataPath string
-	MaxFileSize       int64
+	ServerPort         string
+	GRPCPort           string // gRPC API port; empty = gRPC off
+	TesseractDataPath  string
+	MaxFileSize        int64 // default per-file upload limit
+	MaxDocumentAgeDays int
+	TemplateDir        string
+	RulesDir           string
+	ScoreWeightsFile   string
+	PipelinesFile      string
+
+	// Payroll software layouts (Zoho Payroll, greytHR, Keka, ADP, SAP) shipped
+	// with the service, used alongside the templates of TemplateDir
+	BuiltinTemplates bool
+
+	// Long-lived Tesseract engines per language (concurrent Tesseract extractions)
+	TesseractPoolSize int
+	// Default Tesseract language spec, e.g. "eng" or "hin+eng"; requests may override it
+	TesseractLang string
+	// Tesseract language for cheque MICR lines, "e13b" where that model is installed
+	MICRLang string
+
+	// Pages of text bank statement PDFs to re-OCR and compare with the text layer (0 = off)
+	TextLayerCheckPages int
+
+	// Documents OCRed at once across all requests; further documents queue
+	OCRConcurrency int
+	// Documents of one income verification request OCRed at once
+	OCRRequestConcurrency int
+
+	// A bank credit matches a salary slip's net pay when it is within
+	// SalaryMatchTolerance rupees or SalaryMatchTolerancePct percent of it
+	// (whichever is larger) and dated within SalaryMatchWindowDays of the pay month
+	SalaryMatchTolerance    float64
+	SalaryMatchTolerancePct float64
+	SalaryMatchWindowDays   int
+
+	// UIDAI certificate (PEM/DER) for Aadhaar Secure QR signatures; empty = unverified
+	AadhaarQRCertFile string
+	// UIDAI certificate (PEM/DER) for Aadhaar offline e-KYC XML signatures;
+	// empty = unverified
+	AadhaarEKYCCertFile string
+	// Reject Aadhaar cards printing the full number; only masked Aadhaar
+	// ("XXXX XXXX 1234") may be stored
+	AadhaarRejectUnmasked bool
+
+	// CA certificates trusted for e-signed PDFs on top of the system roots
+	// (e.g. the CCA India root); empty = system roots only
+	PDFTrustedCertsDir string
+
+	// Parsed result cache: memory | redis | off, entries kept for CacheTTLSecs
+	CacheBackend string
+	CacheSize    int
+	CacheTTLSecs int
+	RedisURL     string
+
+	// Structured logging: debug | info | warn | error, text | json, and whether
+	// Aadhaar/PAN/account numbers are masked in log output
+	LogLevel     string
+	LogFormat    string
+	LogRedactPII bool
+
+	// Cosine similarity at or above which a selfie matches the ID portrait
+	FaceMatchThreshold float64
+
+	// Reviewer bearer tokens for the override API, token -> reviewer name
+	ReviewerTokens map[string]string
+	// Admin bearer tokens for the layout template API, token -> admin name
+	AdminTokens map[string]string
+
+	// Identity numbers masked in every response (dto.PIIType* values), and the
+	// store holding originals of tokenized values: off | memory | hashicorp
+	PIIMaskTypes []string
+	TokenStore   string
+	VaultAddr    string
+	VaultToken   string
+	VaultMount   string
+	// Bearer tokens of systems allowed to detokenize, token -> system name
+	DetokenizeTokens map[string]string
+
+	// API client keys for /api/v1 and gRPC, key -> client. The service
+	// refuses to start without any unless APIAuthDisabled is set.
+	APIKeys         map[string]APIKey
+	APIAuthDisabled bool
+
+	// Uploaded documents: local | memory | s3 | gcs | off. Staged copies are
+	// deleted when the request completes, or kept for UploadRetentionSecs and
+	// then swept; STORAGE_* configure the object store backends.
+	UploadStorage       string
+	UploadDir           string
+	UploadRetentionSecs int
+	StorageBucket       string
+	StoragePrefix       string
+	StorageEndpoint     string
+	StorageRegion       string
+	StorageAccessKey    string
+	StorageSecretKey    string
+
+	// Malware scanning of uploads: clamav | http | off; an infected upload is
+	// rejected, or with UploadScanAction "flag" processed and marked
+	UploadScanner     string
+	ClamAVAddr        string
+	UploadScanURL     string
+	UploadScanToken   string
+	UploadScanAction  string
+	UploadScanTimeout int // seconds
+
+	// Per-route upload limits over the built-in ones, route -> limit
+	UploadLimits map[string]UploadLimit
+
+	// PDF bomb protection (service.PDFLimits); 0 = no limit
+	PDFMaxPages          int
+	PDFMaxPageMegapixels float64
+	PDFPageTimeoutSecs   int
+	PDFRenderBudgetSecs  int
+	PDFMaxPageMB         int
+
+	// PDF page rendering for OCR: pdftoppm (Poppler) or mupdf (in process),
+	// and the resolution
+	PDFRenderer  string
+	PDFRenderDPI int
+
+	// Release the extractions recorded for feedback are tagged with (default:
+	// the VCS revision the binary was built from); FeedbackHashKey keys the
+	// hashes of recorded values (random per process when empty), and at most
+	// FeedbackMaxExtractions extractions await feedback
+	Release                string
+	FeedbackHashKey        string
+	FeedbackMaxExtractions int
+
+	// Responses kept for repeated Idempotency-Key requests (0 = off); shared
+	// through Redis when CACHE_BACKEND is redis
+	IdempotencyTTLSecs int
+
+	// Full responses of requests cut short by max_processing_ms kept for
+	// their continuation tokens; shared through Redis like the above
+	ContinuationTTLSecs int
+
+	// Postgres URL for stored verifications; empty = kept in memory, at most
+	// MemoryStoreMaxRecords verifications and the documents of as many
+	// applicants, each for MemoryStoreTTLSecs
+	DatabaseURL           string
+	MemoryStoreMaxRecords int
+	MemoryStoreTTLSecs    int
+
+	// Folder ingestion (on-prem deployments without HTTP ingress)
+	WatchDir          string
+	WatchOnly         bool
+	WatchIntervalSecs int
+	WatchWorkers      int
+	WatchMaxAttempts  int
+}
+
+// UploadLimit bounds the uploads of a route; zero fields keep the default.
+type UploadLimit struct {
+	MaxBytes int64
+	MaxPages int // per PDF
+	MaxFiles int
+}
+
+// APIKey is the client an API key belongs to and its request limit.
+type APIKey struct {
+	Client        string
+	RatePerMinute int // 0 = unlimited
 }
 
 func LoadConfig() *Config {
@@ -19,9 +185,235 @@ func LoadConfig() *Config {
 		tesseractDataPath = "/usr/share/tesseract-ocr/4.00/tessdata"
 	}
 
+	templateDir := os.Getenv("TEMPLATE_DIR")
+	if templateDir == "" {
+		templateDir = "templates"
+	}
+
+	rulesDir := os.Getenv("RULES_DIR")
+	if rulesDir == "" {
+		rulesDir = "rules"
+	}
+
+	scoreWeightsFile := os.Getenv("SCORE_WEIGHTS_FILE")
+	if scoreWeightsFile == "" {
+		scoreWeightsFile = "scoring.yaml"
+	}
+
+	pipelinesFile := os.Getenv("PIPELINES_FILE")
+	if pipelinesFile == "" {
+		pipelinesFile = "pipelines.yaml"
+	}
+
 	return &Config{
-		ServerPort:        serverPort,
-		TesseractDataPath: tesseractDataPath,
-		MaxFileSize:       10 * 1024 * 1024, // 10 MB
+		ServerPort:         serverPort,
+		GRPCPort:           os.Getenv("GRPC_PORT"),
+		TesseractDataPath:  tesseractDataPath,
+		MaxFileSize:        int64(getEnvInt("MAX_FILE_SIZE_MB", 10)) << 20,
+		MaxDocumentAgeDays: getEnvInt("MAX_DOCUMENT_AGE_DAYS", 90),
+		TemplateDir:        templateDir,
+		BuiltinTemplates:   os.Getenv("BUILTIN_TEMPLATES") != "false",
+		RulesDir:           rulesDir,
+		ScoreWeightsFile:   scoreWeightsFile,
+		PipelinesFile:      pipelinesFile,
+		ReviewerTokens:     parseReviewerTokens(os.Getenv("REVIEWER_TOKENS")),
+		AdminTokens:        parseReviewerTokens(os.Getenv("ADMIN_TOKENS")),
+		APIKeys:            parseAPIKeys(os.Getenv("API_KEYS"), getEnvInt("API_RATE_LIMIT_PER_MINUTE", 60)),
+		APIAuthDisabled:    os.Getenv("API_AUTH_DISABLED") == "true",
+		WatchDir:           os.Getenv("WATCH_DIR"),
+		WatchOnly:          os.Getenv("WATCH_ONLY") == "true",
+		WatchIntervalSecs:  getEnvInt("WATCH_INTERVAL_SECONDS", 5),
+		WatchWorkers:       getEnvInt("WATCH_WORKERS", 2),
+		WatchMaxAttempts:   getEnvInt("WATCH_MAX_ATTEMPTS", 3),
+
+		TesseractPoolSize:     getEnvInt("TESSERACT_POOL_SIZE", runtime.NumCPU()),
+		TesseractLang:         getEnvString("TESSERACT_LANG", "eng"),
+		MICRLang:              getEnvString("MICR_TESSERACT_LANG", "eng"),
+		TextLayerCheckPages:   getEnvInt("TEXT_LAYER_CHECK_PAGES", 0),
+		AadhaarQRCertFile:     os.Getenv("AADHAAR_QR_CERT_FILE"),
+		AadhaarEKYCCertFile:   os.Getenv("AADHAAR_EKYC_CERT_FILE"),
+		AadhaarRejectUnmasked: os.Getenv("AADHAAR_REJECT_UNMASKED") == "true",
+		PDFTrustedCertsDir:    os.Getenv("PDF_TRUSTED_CERTS_DIR"),
+
+		OCRConcurrency:        getEnvInt("OCR_CONCURRENCY", runtime.NumCPU()),
+		OCRRequestConcurrency: getEnvInt("OCR_REQUEST_CONCURRENCY", 4),
+
+		SalaryMatchTolerance:    getEnvFloat("SALARY_MATCH_TOLERANCE", 1),
+		SalaryMatchTolerancePct: getEnvFloat("SALARY_MATCH_TOLERANCE_PCT", 0.5),
+		SalaryMatchWindowDays:   getEnvInt("SALARY_MATCH_WINDOW_DAYS", 7),
+
+		CacheBackend: getEnvString("CACHE_BACKEND", "memory"),
+		CacheSize:    getEnvInt("CACHE_SIZE", 1000),
+		CacheTTLSecs: getEnvInt("CACHE_TTL_SECONDS", 24*60*60),
+		RedisURL:     getEnvString("REDIS_URL", "redis://localhost:6379/0"),
+
+		FaceMatchThreshold: getEnvFloat("FACE_MATCH_THRESHOLD", 0.4),
+
+		UploadStorage:       getEnvString("UPLOAD_STORAGE", "local"),
+		UploadDir:           os.Getenv("UPLOAD_DIR"),
+		UploadRetentionSecs: getEnvInt("UPLOAD_RETENTION_SECONDS", 0),
+		StorageBucket:       os.Getenv("STORAGE_BUCKET"),
+		StoragePrefix:       os.Getenv("STORAGE_PREFIX"),
+		StorageEndpoint:     os.Getenv("STORAGE_ENDPOINT"),
+		StorageRegion:       os.Getenv("STORAGE_REGION"),
+		StorageAccessKey:    os.Getenv("STORAGE_ACCESS_KEY"),
+		StorageSecretKey:    os.Getenv("STORAGE_SECRET_KEY"),
+
+		UploadScanner:     getEnvString("UPLOAD_SCANNER", "off"),
+		ClamAVAddr:        getEnvString("CLAMAV_ADDR", "localhost:3310"),
+		UploadScanURL:     os.Getenv("UPLOAD_SCAN_URL"),
+		UploadScanToken:   os.Getenv("UPLOAD_SCAN_TOKEN"),
+		UploadScanAction:  getEnvString("UPLOAD_SCAN_ACTION", "reject"),
+		UploadScanTimeout: getEnvInt("UPLOAD_SCAN_TIMEOUT_SECONDS", 30),
+
+		UploadLimits: parseUploadLimits(os.Getenv("UPLOAD_LIMITS")),
+
+		PDFMaxPages:          getEnvInt("PDF_MAX_PAGES", 500),
+		PDFMaxPageMegapixels: getEnvFloat("PDF_MAX_PAGE_MEGAPIXELS", 40),
+		PDFPageTimeoutSecs:   getEnvInt("PDF_PAGE_TIMEOUT_SECONDS", 60),
+		PDFRenderBudgetSecs:  getEnvInt("PDF_RENDER_BUDGET_SECONDS", 600),
+		PDFMaxPageMB:         getEnvInt("PDF_MAX_PAGE_MB", 100),
+
+		PDFRenderer:  getEnvString("PDF_RENDERER", "pdftoppm"),
+		PDFRenderDPI: getEnvInt("PDF_RENDER_DPI", 150),
+
+		PIIMaskTypes:     strings.Split(getEnvString("PII_MASK_TYPES", "aadhaar"), ","),
+		TokenStore:       getEnvString("TOKEN_STORE", "off"),
+		VaultAddr:        os.Getenv("VAULT_ADDR"),
+		VaultToken:       os.Getenv("VAULT_TOKEN"),
+		VaultMount:       getEnvString("VAULT_KV_MOUNT", "secret"),
+		DetokenizeTokens: parseReviewerTokens(os.Getenv("DETOKENIZE_TOKENS")),
+
+		IdempotencyTTLSecs:  getEnvInt("IDEMPOTENCY_TTL_SECONDS", 24*60*60),
+		ContinuationTTLSecs: getEnvInt("CONTINUATION_TTL_SECONDS", 60*60),
+		DatabaseURL:         os.Getenv("DATABASE_URL"),
+
+		MemoryStoreMaxRecords: getEnvInt("MEMORY_STORE_MAX_RECORDS", 10000),
+		MemoryStoreTTLSecs:    getEnvInt("MEMORY_STORE_TTL_SECONDS", 24*60*60),
+
+		Release:                getEnvString("RELEASE", buildRevision()),
+		FeedbackHashKey:        os.Getenv("FEEDBACK_HASH_KEY"),
+		FeedbackMaxExtractions: getEnvInt("FEEDBACK_MAX_EXTRACTIONS", 100000),
+
+		LogLevel:     getEnvString("LOG_LEVEL", "info"),
+		LogFormat:    getEnvString("LOG_FORMAT", "text"),
+		LogRedactPII: os.Getenv("LOG_REDACT_PII") != "false",
+	}
+}
+
+// buildRevision is the VCS revision of the binary, "dev" when unknown.
+func buildRevision() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" && len(s.Value) >= 12 {
+			return s.Value[:12]
+		}
+	}
+	return "dev"
+}
+
+// getEnvString reads a string environment variable, falling back to def when unset.
+func getEnvString(key, def string) string {
+	if v := os.Getenv(key); v != "" {
+		return v
 	}
-}
\ No newline at end of file
+	return def
+}
+
+// getEnvInt reads an integer environment variable, falling back to def when unset or invalid.
+func getEnvInt(key string, def int) int {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return def
+	}
+	return n
+}
+
+// getEnvFloat reads a float environment variable, falling back to def when unset or invalid.
+func getEnvFloat(key string, def float64) float64 {
+	v := os.Getenv(key)
+	if v == "" {
+		return def
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return def
+	}
+	return f
+}
+
+// parseReviewerTokens parses "alice:tok1,bob:tok2" into a token -> reviewer map.
+func parseReviewerTokens(s string) map[string]string {
+	tokens := map[string]string{}
+	for _, pair := range strings.Split(s, ",") {
+		name, token, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || name == "" || token == "" {
+			continue
+		}
+		tokens[token] = name
+	}
+	return tokens
+}
+
+// parseAPIKeys parses "acme:key1,globex:key2:600" into a key -> client map.
+// The optional third field overrides defaultRate requests per minute.
+func parseAPIKeys(s string, defaultRate int) map[string]APIKey {
+	keys := map[string]APIKey{}
+	for _, entry := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(entry), ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			continue
+		}
+		rate := defaultRate
+		if len(parts) == 3 {
+			n, err := strconv.Atoi(parts[2])
+			if err != nil || n < 0 {
+				continue
+			}
+			rate = n
+		}
+		keys[parts[1]] = APIKey{Client: parts[0], RatePerMinute: rate}
+	}
+	return keys
+}
+
+// parseUploadLimits parses "/api/v1/pan/ocr=5:2,/api/v1/documents/batch=25::50"
+// into a route -> limit map: megabytes per file, then optionally pages per PDF
+// and files per request, each left empty to keep the default.
+func parseUploadLimits(s string) map[string]UploadLimit {
+	limits := map[string]UploadLimit{}
+	for _, entry := range strings.Split(s, ",") {
+		route, spec, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || route == "" {
+			continue
+		}
+		parts := strings.Split(spec, ":")
+		if len(parts) > 3 {
+			continue
+		}
+		var n [3]int
+		valid := true
+		for i, p := range parts {
+			if p == "" {
+				continue
+			}
+			v, err := strconv.Atoi(p)
+			if err != nil || v < 0 {
+				valid = false
+				break
+			}
+			n[i] = v
+		}
+		if valid {
+			limits[route] = UploadLimit{MaxBytes: int64(n[0]) << 20, MaxPages: n[1], MaxFiles: n[2]}
+		}
+	}
+	return limits
+}
@@ -0,0 +1,107 @@
+// Package container is the dependency-injection container the services and
+// handlers are wired with: each registers a constructor for its type once,
+// and what needs it resolves it by type. A type is built on first use and
+// shared from then on, so the order of registration does not matter.
+package container
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Container holds the constructors and the built values. It is meant for
+// wiring at start-up and is not safe for concurrent use.
+type Container struct {
+	providers map[reflect.Type]func(*Container) (any, error)
+	values    map[reflect.Type]any
+	building  []reflect.Type
+}
+
+// New returns an empty Container.
+func New() *Container {
+	return &Container{
+		providers: make(map[reflect.Type]func(*Container) (any, error)),
+		values:    make(map[reflect.Type]any),
+	}
+}
+
+// Provide registers the constructor of T. It panics when T has one already,
+// as two constructors for a type are a wiring mistake.
+func Provide[T any](c *Container, build func(*Container) (T, error)) {
+	t := typeOf[T]()
+	if _, ok := c.providers[t]; ok {
+		panic(fmt.Sprintf("container: %s provided twice", t))
+	}
+	c.providers[t] = func(c *Container) (any, error) { return build(c) }
+}
+
+// Value registers a T built already.
+func Value[T any](c *Container, v T) {
+	Provide(c, func(*Container) (T, error) { return v, nil })
+}
+
+// Resolve returns the T of c, building it and the types it needs first. A
+// type without a constructor, a failing constructor or types needing each
+// other fail it.
+func Resolve[T any](c *Container) (v T, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			re, ok := r.(resolveError)
+			if !ok {
+				panic(r)
+			}
+			c.building = nil
+			err = re.err
+		}
+	}()
+	return Get[T](c), nil
+}
+
+// Get is Resolve for constructors: it returns the T a constructor depends
+// on, and a failure to build it fails the Resolve the constructor runs in.
+func Get[T any](c *Container) T {
+	t := typeOf[T]()
+	if v, ok := c.values[t]; ok {
+		out, _ := v.(T) // a nil interface value is stored as nil
+		return out
+	}
+	build, ok := c.providers[t]
+	if !ok {
+		panic(resolveError{fmt.Errorf("container: no constructor for %s%s", t, c.path())})
+	}
+	for _, b := range c.building {
+		if b == t {
+			panic(resolveError{fmt.Errorf("container: %s depends on itself%s", t, c.path())})
+		}
+	}
+
+	c.building = append(c.building, t)
+	v, err := build(c)
+	c.building = c.building[:len(c.building)-1]
+	if err != nil {
+		panic(resolveError{fmt.Errorf("%s%s: %w", t, c.path(), err)})
+	}
+	c.values[t] = v
+	out, _ := v.(T)
+	return out
+}
+
+// path describes the types being built, outermost first.
+func (c *Container) path() string {
+	if len(c.building) == 0 {
+		return ""
+	}
+	names := make([]string, len(c.building))
+	for i, t := range c.building {
+		names[i] = t.String()
+	}
+	return " (for " + strings.Join(names, " -> ") + ")"
+}
+
+// resolveError carries a failure out of nested constructors to Resolve.
+type resolveError struct{ err error }
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
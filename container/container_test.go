@@ -0,0 +1,64 @@
+package container
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type config struct{ name string }
+
+type repo struct{ cfg *config }
+
+type svc struct{ repo *repo }
+
+func TestResolveBuildsOnceInAnyOrder(t *testing.T) {
+	c := New()
+	built := 0
+	Provide(c, func(c *Container) (*svc, error) { return &svc{repo: Get[*repo](c)}, nil })
+	Provide(c, func(c *Container) (*repo, error) {
+		built++
+		return &repo{cfg: Get[*config](c)}, nil
+	})
+	Value(c, &config{name: "test"})
+
+	s, err := Resolve[*svc](c)
+	require.NoError(t, err)
+	assert.Equal(t, "test", s.repo.cfg.name)
+
+	r, err := Resolve[*repo](c)
+	require.NoError(t, err)
+	assert.Same(t, s.repo, r)
+	assert.Equal(t, 1, built)
+}
+
+func TestResolveFailures(t *testing.T) {
+	c := New()
+	Provide(c, func(c *Container) (*svc, error) { return &svc{repo: Get[*repo](c)}, nil })
+	_, err := Resolve[*svc](c)
+	assert.ErrorContains(t, err, "no constructor for *container.repo (for *container.svc)")
+
+	failing := errors.New("database unreachable")
+	Provide(c, func(c *Container) (*repo, error) { return nil, failing })
+	_, err = Resolve[*svc](c)
+	assert.ErrorIs(t, err, failing)
+	assert.ErrorContains(t, err, "*container.repo (for *container.svc)")
+
+	cyclic := New()
+	Provide(cyclic, func(c *Container) (*svc, error) { return &svc{repo: Get[*repo](c)}, nil })
+	Provide(cyclic, func(c *Container) (*repo, error) { Get[*svc](c); return &repo{}, nil })
+	_, err = Resolve[*svc](cyclic)
+	assert.ErrorContains(t, err, "*container.svc depends on itself")
+
+	assert.Panics(t, func() { Value(cyclic, &repo{}) }, "a second constructor")
+}
+
+func TestNilInterfaceValue(t *testing.T) {
+	c := New()
+	Value[error](c, nil)
+	err, resolveErr := Resolve[error](c)
+	require.NoError(t, resolveErr)
+	assert.Nil(t, err)
+}
@@ -2,40 +2,9 @@ package dto
 
 import (
 	"encoding/xml"
-	"fmt"
-	"mime/multipart"
 	"strings"
 )
 
-// AadhaarExtractRequest represents the request for Aadhaar extraction
-type AadhaarExtractRequest struct {
-	File     *multipart.FileHeader
-	Password string
-}
-
-// Validate validates the Aadhaar extraction request
-func (r *AadhaarExtractRequest) Validate() error {
-	if r.File == nil {
-		return fmt.Errorf("file is required")
-	}
-
-	// Validate file extension
-	filename := strings.ToLower(r.File.Filename)
-	validExtensions := []string{".pdf", ".png", ".jpg", ".jpeg"}
-	valid := false
-	for _, ext := range validExtensions {
-		if strings.HasSuffix(filename, ext) {
-			valid = true
-			break
-		}
-	}
-	if !valid {
-		return fmt.Errorf("invalid file type. Supported: PDF, PNG, JPG")
-	}
-
-	return nil
-}
-
 // AadhaarExtractResponse represents the response from Aadhaar extraction
 type AadhaarExtractResponse struct {
 	Name         string `json:"name"`
@@ -43,7 +12,39 @@ type AadhaarExtractResponse struct {
 	Gender       string `json:"gender"`
 	Address      string `json:"address"`
 	AadhaarLast4 string `json:"aadhaar_last4"`
-	Source       string `json:"source"` // "qr" or "ocr"
+	Source       string `json:"source"` // "qr", "ocr" or "ekyc_xml"
+	// QRSignature is the UIDAI signature check of a Secure QR: verified,
+	// unverified (no certificate configured) or invalid (QR ignored, OCR used).
+	QRSignature string `json:"qr_signature,omitempty"`
+	// EKYCSignature is the UIDAI signature check of an offline e-KYC XML:
+	// verified or unverified (no certificate configured); invalid ones are
+	// rejected.
+	EKYCSignature string `json:"ekyc_signature,omitempty"`
+	// EmailVerified and MobileVerified are whether the email and mobile number
+	// sent with an offline e-KYC are the ones registered with UIDAI; absent
+	// when not sent or not registered.
+	EmailVerified  *bool `json:"email_verified,omitempty"`
+	MobileVerified *bool `json:"mobile_verified,omitempty"`
+	// Assurance is "high" when the details come from a UIDAI signature that
+	// was verified (Secure QR or offline e-KYC) rather than from OCR.
+	Assurance string `json:"assurance,omitempty"`
+	// AadhaarNumber is the full number when the card shows it. API responses
+	// mask it (or replace it with a token when tokenize_pii=true).
+	AadhaarNumber string `json:"aadhaar_number,omitempty"`
+	// Masked is whether the card prints the number masked ("XXXX XXXX 1234"),
+	// as regulations require of stored copies; absent when no number was read.
+	Masked *bool `json:"masked,omitempty"`
+	// MaskedImages are the uploaded pages as base64 JPEGs with the first eight
+	// digits of the Aadhaar number blacked out (masked_image=true).
+	MaskedImages []string `json:"masked_images,omitempty"`
+	// Photo is the cropped portrait as a base64 JPEG (include_photo=true).
+	Photo string `json:"photo,omitempty"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// DOBISO is DOB in ISO 8601 (utils.NormalizeDOB); empty when DOB is not a valid date.
+	DOBISO string `json:"dob_iso,omitempty"`
+	// AddressParts is Address split into its components, with the PIN code checked.
+	AddressParts *Address `json:"address_parts,omitempty"`
 }
 
 // AadhaarQRData represents the XML structure in Aadhaar QR code
@@ -0,0 +1,16 @@
+package dto
+
+// Address is a postal address split into its components (see utils/address).
+type Address struct {
+	House    string `json:"house,omitempty"`
+	Street   string `json:"street,omitempty"`
+	Locality string `json:"locality,omitempty"`
+	City     string `json:"city,omitempty"`
+	District string `json:"district,omitempty"`
+	State    string `json:"state,omitempty"`
+	Pincode  string `json:"pincode,omitempty"`
+	// PincodeValid is set when the PIN code is a known one and agrees with
+	// the state given in the address; Issues says why it is not.
+	PincodeValid bool     `json:"pincode_valid"`
+	Issues       []string `json:"issues,omitempty"`
+}
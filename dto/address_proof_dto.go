@@ -0,0 +1,20 @@
+package dto
+
+// Utility bill types accepted as address proof.
+const (
+	BillElectricity = "electricity"
+	BillTelecom     = "telecom" // mobile postpaid, landline, broadband
+	BillGas         = "gas"     // piped gas (PNG) or LPG
+)
+
+// AddressProofResponse is a utility bill read for address proof.
+type AddressProofResponse struct {
+	BillType       string   `json:"bill_type,omitempty"` // empty when not recognized
+	Provider       string   `json:"provider,omitempty"`
+	ConsumerName   string   `json:"consumer_name"`
+	ConsumerNumber string   `json:"consumer_number,omitempty"`
+	Address        string   `json:"address"`
+	AddressParts   *Address `json:"address_parts,omitempty"`
+	BillDate       string   `json:"bill_date,omitempty"` // YYYY-MM-DD
+	Amount         float64  `json:"amount"`              // amount payable
+}
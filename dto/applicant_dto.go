@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ApplicantDocument is the stored result of one document extraction made for
+// an applicant (requests carrying applicant_id). Result is the response body
+// of the extraction endpoint for DocType.
+type ApplicantDocument struct {
+	ApplicantID string          `json:"applicant_id"`
+	TenantID    string          `json:"tenant_id,omitempty"`
+	Client      string          `json:"client,omitempty"` // the API client that sent the document
+	DocType     string          `json:"doc_type"`
+	RequestID   string          `json:"request_id"`
+	Result      json.RawMessage `json:"result"`
+	ProcessedAt time.Time       `json:"processed_at"`
+}
+
+// ApplicantSummary is GET /applicants/:id/summary: every document processed
+// for an applicant consolidated into one identity and income profile. The
+// latest document of each type is used.
+type ApplicantSummary struct {
+	ApplicantID string                 `json:"applicant_id"`
+	Documents   []ApplicantDocumentRef `json:"documents"`
+
+	Identity ApplicantIdentity `json:"identity"`
+	// IdentityChecks cross-verify name, DOB and address over the identity
+	// documents and the latest verification's salary slip and bank statement.
+	IdentityChecks  []KYCFieldCheck `json:"identity_checks"`
+	IdentityVerdict string          `json:"identity_verdict"`
+	Mismatches      []string        `json:"mismatches,omitempty"`
+
+	Income ApplicantIncome `json:"income"`
+
+	// Score (0–100) is the mean of the identity score (the KYC field checks)
+	// and the latest income verification's quick-screen score, of those the
+	// documents allow; Components gives both.
+	Score      float64          `json:"score"`
+	Components []ScoreComponent `json:"components"`
+}
+
+// ApplicantDocumentRef names a document or income verification of the
+// applicant; VerificationID is set for income verifications.
+type ApplicantDocumentRef struct {
+	DocType        string    `json:"doc_type"`
+	RequestID      string    `json:"request_id,omitempty"`
+	VerificationID string    `json:"verification_id,omitempty"`
+	ProcessedAt    time.Time `json:"processed_at"`
+}
+
+// ApplicantIdentity is the applicant's identity as the documents give it;
+// Sources names the document each field was taken from.
+type ApplicantIdentity struct {
+	Name         string            `json:"name,omitempty"`
+	DOB          string            `json:"dob,omitempty"` // YYYY-MM-DD
+	Gender       string            `json:"gender,omitempty"`
+	PAN          string            `json:"pan,omitempty"`
+	AadhaarLast4 string            `json:"aadhaar_last4,omitempty"`
+	Address      string            `json:"address,omitempty"`
+	AddressParts *Address          `json:"address_parts,omitempty"`
+	Sources      map[string]string `json:"sources"`
+}
+
+// ApplicantIncome is the applicant's income as the documents give it.
+type ApplicantIncome struct {
+	EmployerName string `json:"employer_name,omitempty"`
+	// MonthlyNetSalary is the net pay of the latest salary slip.
+	MonthlyNetSalary float64 `json:"monthly_net_salary,omitempty"`
+	// AverageMonthlyIncome is the recurring salary-like bank credits.
+	AverageMonthlyIncome float64 `json:"average_monthly_income,omitempty"`
+	// AnnualIncome and AssessmentYear come from the latest ITR.
+	AnnualIncome   float64 `json:"annual_income,omitempty"`
+	AssessmentYear string  `json:"assessment_year,omitempty"`
+	// AnnualGrossSalary and FinancialYear come from the latest Form-16.
+	AnnualGrossSalary float64 `json:"annual_gross_salary,omitempty"`
+	FinancialYear     string  `json:"financial_year,omitempty"`
+	// TDSReportedIncome is what the deductors of the latest Form 26AS or AIS
+	// reported paying the applicant in TDSAssessmentYear.
+	TDSReportedIncome      float64 `json:"tds_reported_income,omitempty"`
+	TDSAssessmentYear      string  `json:"tds_assessment_year,omitempty"`
+	MonthlyRent            float64 `json:"monthly_rent,omitempty"`
+	TotalMonthlyObligation float64 `json:"total_monthly_obligation,omitempty"`
+	FOIR                   float64 `json:"foir,omitempty"`
+	// VerificationScore is the latest income verification's quick-screen score.
+	VerificationScore *VerificationScore `json:"verification_score,omitempty"`
+}
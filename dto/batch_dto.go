@@ -0,0 +1,30 @@
+package dto
+
+// Document types accepted by the batch endpoint in addition to the income documents.
+const (
+	BatchDocAadhaar = "aadhaar"
+	BatchDocPAN     = "pan"
+	BatchDocDL      = "dl"
+	BatchDocITR     = "itr"
+)
+
+// BatchMetadata describes the files of a batch request. Only Filename, DocType,
+// Password and PasswordHints of each entry are used.
+type BatchMetadata struct {
+	Documents []DocumentMeta `json:"documents"`
+	TenantID  string         `json:"tenant_id,omitempty"`
+}
+
+// BatchItemResult is the outcome of one file. Exactly one of Result and Error is set.
+type BatchItemResult struct {
+	DocType string      `json:"doc_type"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// BatchResponse holds the per-file results keyed by filename.
+type BatchResponse struct {
+	Results   map[string]BatchItemResult `json:"results"`
+	Succeeded int                        `json:"succeeded"`
+	Failed    int                        `json:"failed"`
+}
@@ -0,0 +1,15 @@
+package dto
+
+// CaptureQualityResponse is the image quality of a camera frame, checked
+// before the document is submitted for verification.
+type CaptureQualityResponse struct {
+	// Acceptable is false when any issue calls for a retake.
+	Acceptable      bool    `json:"acceptable"`
+	Width           int     `json:"width"`
+	Height          int     `json:"height"`
+	ResolutionScore float64 `json:"resolution_score"`
+	ContrastScore   float64 `json:"contrast_score"`
+	SharpnessScore  float64 `json:"sharpness_score"`
+	// Issues are "blurry", "glare", "cropped" and "low_resolution".
+	Issues []string `json:"issues"`
+}
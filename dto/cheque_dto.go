@@ -0,0 +1,29 @@
+package dto
+
+// ChequeResponse is a cancelled cheque read for bank account ownership proof.
+type ChequeResponse struct {
+	AccountHolderName string `json:"account_holder_name"`
+	AccountNumber     string `json:"account_number"`
+	IFSC              string `json:"ifsc"`
+	BankName          string `json:"bank_name,omitempty"`
+	// Cancelled is set when "CANCELLED" is written across the leaf.
+	Cancelled bool      `json:"cancelled"`
+	MICR      *MICRLine `json:"micr,omitempty"`
+}
+
+// MICRLine is the magnetic ink (E-13B) code line at the foot of a CTS-2010
+// cheque: cheque number, MICR code, base account number and transaction code.
+type MICRLine struct {
+	ChequeNumber string `json:"cheque_number"`
+	// MICRCode is the 9-digit bank branch code: city (the first three digits
+	// of the city's PIN code), bank and branch, three digits each.
+	MICRCode   string `json:"micr_code"`
+	CityCode   string `json:"city_code"`
+	BankCode   string `json:"bank_code"`
+	BranchCode string `json:"branch_code"`
+	City       string `json:"city,omitempty"` // district of the city code, when known
+	// BaseAccount is the 6-digit short account number some banks print.
+	BaseAccount     string `json:"base_account,omitempty"`
+	TransactionCode string `json:"transaction_code,omitempty"`
+	Raw             string `json:"raw"`
+}
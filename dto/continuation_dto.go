@@ -0,0 +1,8 @@
+package dto
+
+// ContinuationPending answers GET /continuations/:token while the full
+// response of a request answered partially is still being produced.
+type ContinuationPending struct {
+	ContinuationToken string `json:"continuation_token"`
+	Status            string `json:"status"` // always "pending"
+}
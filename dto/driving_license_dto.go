@@ -0,0 +1,28 @@
+package dto
+
+// DLResponse is the result of POST /driving-license/ocr.
+type DLResponse struct {
+	Name string `json:"name"`
+	// DLNumber is normalized to the 15-character format ("MH1220110012345")
+	// when DLNumberValid; older numbers are as printed.
+	DLNumber      string `json:"dl_number"`
+	DLNumberValid bool   `json:"dl_number_valid"`
+	DOB           string `json:"dob"`
+	IssueDate     string `json:"issue_date"`
+	ValidTill     string `json:"valid_till"`
+	// IsExpired is whether ValidTill is past; false when it was not read.
+	IsExpired bool `json:"is_expired"`
+	// VehicleClasses are the classes of vehicle authorized: LMV, MCWG,
+	// TRANS ...
+	VehicleClasses []string `json:"vehicle_classes,omitempty"`
+	Address        string   `json:"address"`
+	RawText        string   `json:"raw_text"`
+	// Photo is the cropped portrait as a base64 JPEG (include_photo=true).
+	Photo string `json:"photo,omitempty"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// DOBISO is DOB in ISO 8601 (utils.NormalizeDOB); empty when DOB is not a valid date.
+	DOBISO string `json:"dob_iso,omitempty"`
+	// AddressParts is Address split into its components, with the PIN code checked.
+	AddressParts *Address `json:"address_parts,omitempty"`
+}
@@ -5,6 +5,14 @@ type EmployeeIDInfo struct {
 	EmployeeID  string `json:"employee_id"`
 	Company     string `json:"company_name"`
 	Designation string `json:"designation"`
+	BloodGroup  string `json:"blood_group,omitempty"`
+	// ValidFrom and ValidUntil are YYYY-MM-DD, or YYYY-MM when the card
+	// prints only the month.
+	ValidFrom  string `json:"valid_from,omitempty"`
+	ValidUntil string `json:"valid_until,omitempty"`
+	// BadgeCode is the decoded QR code or barcode of the badge; the fields
+	// it carries are taken over the OCR text's.
+	BadgeCode string `json:"badge_code,omitempty"`
 }
 
 type AppointmentLetterInfo struct {
@@ -24,4 +32,7 @@ type EmployeeVerifyResponse struct {
 type ValidationResult struct {
 	NameMatch    bool `json:"name_match"`
 	CompanyMatch bool `json:"company_match"`
+
+	// NameScore is the utils.NameMatchScore of the two names (0..1).
+	NameScore float64 `json:"name_score"`
 }
@@ -0,0 +1,51 @@
+package dto
+
+// EPFPassbookData is what an EPFO member passbook shows: the provident fund
+// contributions made for a UAN, by establishment (employer).
+type EPFPassbookData struct {
+	UAN            string             `json:"uan"`
+	MemberName     string             `json:"member_name"`
+	Establishments []EPFEstablishment `json:"establishments"`
+	PIIFound       PIISummary         `json:"pii_found"`
+	Quality        DocumentQuality    `json:"quality"`
+}
+
+// EPFEstablishment is one employer of the member and its contributions,
+// oldest first.
+type EPFEstablishment struct {
+	ID            string            `json:"id"` // establishment code, e.g. MHBAN0012345000
+	Name          string            `json:"name"`
+	MemberID      string            `json:"member_id,omitempty"`
+	Contributions []EPFContribution `json:"contributions"`
+}
+
+// EPFContribution is the monthly contribution for one wage month.
+type EPFContribution struct {
+	Month         string  `json:"month"`           // wage month, YYYY-MM
+	Wages         float64 `json:"wages,omitempty"` // EPF wages
+	EmployeeShare float64 `json:"employee_share"`
+	EmployerShare float64 `json:"employer_share"`
+	PensionShare  float64 `json:"pension_share"` // employer's EPS contribution
+}
+
+// EPFCheck compares the salary slips with the EPF passbooks: the slips'
+// employer must be an establishment that contributed for each slip month.
+type EPFCheck struct {
+	// Establishment and EstablishmentID identify the establishment matched to
+	// the slips' employer; empty when none matched.
+	Establishment   string          `json:"establishment,omitempty"`
+	EstablishmentID string          `json:"establishment_id,omitempty"`
+	Months          []EPFMonthCheck `json:"months"`
+	// Gaps are the months without a contribution between the matched
+	// establishment's first and last, breaks in the employment.
+	Gaps []string `json:"gaps,omitempty"`
+	// Consistent is false when a slip's employer or month is not backed by
+	// a contribution (see CrossCheckResult.FraudSignals).
+	Consistent bool `json:"consistent"`
+}
+
+// EPFMonthCheck compares one salary slip with the contribution for its month.
+type EPFMonthCheck struct {
+	Month       string `json:"month"` // YYYY-MM
+	Contributed bool   `json:"contributed"`
+}
@@ -0,0 +1,65 @@
+package dto
+
+// ErrorCode is the machine-readable error of an ErrorResponse. A condition
+// has the same code on every endpoint; Message says what went wrong in the
+// request at hand.
+type ErrorCode string
+
+// The error code catalogue.
+const (
+	// The request
+	CodeInvalidRequest        ErrorCode = "INVALID_REQUEST" // a field or parameter is malformed or missing
+	CodeFileMissing           ErrorCode = "FILE_MISSING"
+	CodeInvalidUpload         ErrorCode = "INVALID_UPLOAD" // the multipart body cannot be read
+	CodeUnsupportedType       ErrorCode = "UNSUPPORTED_TYPE"
+	CodeInvalidArchive        ErrorCode = "INVALID_ARCHIVE"
+	CodeInvalidDocumentURL    ErrorCode = "INVALID_DOCUMENT_URL"
+	CodeInvalidLanguage       ErrorCode = "INVALID_LANGUAGE"
+	CodeRequestTooLarge       ErrorCode = "REQUEST_TOO_LARGE"
+	CodeFileTooLarge          ErrorCode = "FILE_TOO_LARGE"
+	CodeTooManyPages          ErrorCode = "TOO_MANY_PAGES"
+	CodeTooManyFiles          ErrorCode = "TOO_MANY_FILES"
+	CodeMalwareDetected       ErrorCode = "MALWARE_DETECTED"
+	CodeUnauthorized          ErrorCode = "UNAUTHORIZED"
+	CodeRateLimited           ErrorCode = "RATE_LIMITED"
+	CodeNotFound              ErrorCode = "NOT_FOUND"
+	CodeInvalidIdempotencyKey ErrorCode = "INVALID_IDEMPOTENCY_KEY"
+	CodeIdempotencyKeyInUse   ErrorCode = "IDEMPOTENCY_KEY_IN_USE"
+	CodeIdempotencyKeyReused  ErrorCode = "IDEMPOTENCY_KEY_REUSED"
+
+	// The document
+	CodePDFEncrypted      ErrorCode = "PDF_ENCRYPTED" // the password is missing or wrong
+	CodePDFTooManyPages   ErrorCode = "PDF_TOO_MANY_PAGES"
+	CodePDFPageTooLarge   ErrorCode = "PDF_PAGE_TOO_LARGE"
+	CodePDFRenderTimeout  ErrorCode = "PDF_RENDER_TIMEOUT"
+	CodePDFRenderTooLarge ErrorCode = "PDF_RENDER_TOO_LARGE"
+	CodeOCRFailed         ErrorCode = "OCR_FAILED"  // no text could be read
+	CodeLowQuality        ErrorCode = "LOW_QUALITY" // no text could be read from a blurry or low-resolution capture
+	CodeParseEmpty        ErrorCode = "PARSE_EMPTY" // text was read, but none of the document's fields
+	CodeUnprocessable     ErrorCode = "UNPROCESSABLE_DOCUMENT"
+
+	// The service
+	CodeProcessingFailed   ErrorCode = "PROCESSING_FAILED"
+	CodeServiceUnavailable ErrorCode = "SERVICE_UNAVAILABLE" // a backend (OCR engine, storage, scanner) is down or not configured
+	CodeTimeout            ErrorCode = "TIMEOUT"
+)
+
+// ErrorCodes is the whole catalogue, in the order above.
+var ErrorCodes = []ErrorCode{
+	CodeInvalidRequest, CodeFileMissing, CodeInvalidUpload, CodeUnsupportedType, CodeInvalidArchive,
+	CodeInvalidDocumentURL, CodeInvalidLanguage, CodeRequestTooLarge, CodeFileTooLarge, CodeTooManyPages,
+	CodeTooManyFiles, CodeMalwareDetected, CodeUnauthorized, CodeRateLimited, CodeNotFound,
+	CodeInvalidIdempotencyKey, CodeIdempotencyKeyInUse, CodeIdempotencyKeyReused,
+	CodePDFEncrypted, CodePDFTooManyPages, CodePDFPageTooLarge, CodePDFRenderTimeout, CodePDFRenderTooLarge,
+	CodeOCRFailed, CodeLowQuality, CodeParseEmpty, CodeUnprocessable,
+	CodeProcessingFailed, CodeServiceUnavailable, CodeTimeout,
+}
+
+// Enum lists the codes for the OpenAPI schema.
+func (ErrorCode) Enum() []string {
+	out := make([]string, len(ErrorCodes))
+	for i, c := range ErrorCodes {
+		out[i] = string(c)
+	}
+	return out
+}
@@ -0,0 +1,13 @@
+package dto
+
+// FaceMatchResponse compares the portrait on an ID document with a selfie.
+type FaceMatchResponse struct {
+	DocType string `json:"doc_type"`
+	// Similarity is the cosine similarity of the two face embeddings (-1..1).
+	Similarity float64 `json:"similarity"`
+	Threshold  float64 `json:"threshold"`
+	Match      bool    `json:"match"`
+	// SelfieFaceFound is false when no face was located on the selfie and the
+	// whole image was compared instead.
+	SelfieFaceFound bool `json:"selfie_face_found"`
+}
@@ -0,0 +1,68 @@
+package dto
+
+import "time"
+
+// FeedbackRequest is the body of POST /feedback: the values an integrator
+// ultimately used for an extraction, by the X-Request-ID of the extraction
+// request. Fields are dot paths into its response, as in overrides, e.g.
+// "name" or "salary_slips.0.net_salary".
+type FeedbackRequest struct {
+	RequestID string                 `json:"request_id" binding:"required"`
+	Fields    map[string]interface{} `json:"fields" binding:"required"`
+}
+
+// FeedbackResult tells which of the reported fields the extraction got right.
+type FeedbackResult struct {
+	RequestID string          `json:"request_id"`
+	DocType   string          `json:"doc_type"`
+	Release   string          `json:"release"`
+	Fields    []FieldFeedback `json:"fields"`
+	Correct   int             `json:"correct"`
+	Compared  int             `json:"compared"`
+}
+
+// FieldFeedback is the outcome of one reported field. Extracted is false when
+// the response had no value at the path (a missed field).
+type FieldFeedback struct {
+	Field     string `json:"field"`
+	Extracted bool   `json:"extracted"`
+	Correct   bool   `json:"correct"`
+}
+
+// FeedbackRecord is the stored feedback of one extraction request. Array
+// indices in Field are kept; accuracy groups them.
+type FeedbackRecord struct {
+	RequestID  string          `json:"request_id"`
+	Client     string          `json:"client,omitempty"`
+	DocType    string          `json:"doc_type"`
+	Release    string          `json:"release"`
+	Fields     []FieldFeedback `json:"fields"`
+	ReceivedAt time.Time       `json:"received_at"`
+}
+
+// AccuracyReport is GET /feedback/accuracy: the share of reported fields the
+// parsers got right, per release and document type.
+type AccuracyReport struct {
+	Groups []AccuracyGroup `json:"groups"`
+}
+
+// AccuracyGroup is the accuracy of one document type in one release.
+type AccuracyGroup struct {
+	Release  string          `json:"release"`
+	DocType  string          `json:"doc_type"`
+	Requests int             `json:"requests"`
+	Compared int             `json:"compared"`
+	Correct  int             `json:"correct"`
+	Accuracy float64         `json:"accuracy"`
+	Fields   []FieldAccuracy `json:"fields"`
+}
+
+// FieldAccuracy is the accuracy of one field; array indices in its path are
+// replaced by "*" ("salary_slips.*.net_salary").
+type FieldAccuracy struct {
+	Field    string  `json:"field"`
+	Compared int     `json:"compared"`
+	Correct  int     `json:"correct"`
+	Missed   int     `json:"missed"` // reported but not extracted
+	Accuracy float64 `json:"accuracy"`
+}
@@ -0,0 +1,54 @@
+package dto
+
+// Types of the fields of a FieldSchema.
+const (
+	FieldTypeString = "string"
+	FieldTypeDate   = "date"   // returned as YYYY-MM-DD
+	FieldTypeAmount = "amount" // returned as a number
+	FieldTypeRegex  = "regex"  // Pattern's first group, or its whole match
+)
+
+// FieldSchema is the "schema" of POST /documents/extract-fields: the fields
+// to read from a document of a type the service has no parser for.
+type FieldSchema struct {
+	Fields []FieldSpec `json:"fields"`
+}
+
+// FieldSpec is one field of a FieldSchema. Its value is looked for next to
+// any of Labels: to the right on the same line, or on the line below. Regex
+// fields without labels are looked for anywhere in the document.
+type FieldSpec struct {
+	Name     string   `json:"name"`
+	Labels   []string `json:"labels,omitempty"`
+	Type     string   `json:"type,omitempty"`    // default string
+	Pattern  string   `json:"pattern,omitempty"` // regex fields
+	Required bool     `json:"required,omitempty"`
+}
+
+// ExtractedFields is the result of POST /documents/extract-fields, fields in
+// schema order.
+type ExtractedFields struct {
+	Fields []ExtractedField `json:"fields"`
+	// Missing names the fields that were not found; Complete is false when a
+	// required one is among them.
+	Missing  []string        `json:"missing"`
+	Complete bool            `json:"complete"`
+	Quality  DocumentQuality `json:"quality"`
+}
+
+// ExtractedField is the value read for one field.
+type ExtractedField struct {
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"` // a number for amounts, else a string
+	Raw   string      `json:"raw"`   // the text the value was read from
+	Label string      `json:"label,omitempty"`
+	// Source is "layout" when the value was found right of the label in the
+	// word boxes, "text" when found in the OCR text.
+	Source string `json:"source"`
+}
+
+// Where an extracted field was found.
+const (
+	FieldSourceLayout = "layout"
+	FieldSourceText   = "text"
+)
@@ -0,0 +1,17 @@
+package dto
+
+// HandwrittenField is one handwritten region read by the handwriting model.
+// Value is the normalized reading (number for amounts, YYYY-MM-DD for dates,
+// text otherwise) and is empty when RawText could not be normalized.
+type HandwrittenField struct {
+	Field      string      `json:"field"`
+	RawText    string      `json:"raw_text"`
+	Value      interface{} `json:"value,omitempty"`
+	Confidence float64     `json:"confidence"`
+	Valid      bool        `json:"valid"`
+}
+
+type HandwritingResponse struct {
+	DocType string             `json:"doc_type"`
+	Fields  []HandwrittenField `json:"fields"`
+}
@@ -0,0 +1,71 @@
+package dto
+
+// Identity documents of a KYC report, as named in its field checks. The
+// income document goes by its DocumentType.
+const (
+	KYCDocAadhaar  = "aadhaar"
+	KYCDocPAN      = "pan"
+	KYCDocDL       = "driving_license"
+	KYCDocPassport = "passport"
+	KYCDocBill     = "address_proof" // utility bill
+)
+
+// Fields cross-verified by the KYC endpoint.
+const (
+	KYCFieldName    = "name"
+	KYCFieldDOB     = "dob"
+	KYCFieldAddress = "address"
+)
+
+// Outcomes of a KYC field check and of the whole report.
+const (
+	KYCMatch        = "match"
+	KYCMismatch     = "mismatch"
+	KYCInsufficient = "insufficient" // fewer than two documents carry the field
+)
+
+// KYCComparison scores one field between two documents (0..1).
+type KYCComparison struct {
+	Documents []string `json:"documents"`
+	Score     float64  `json:"score"`
+	Match     bool     `json:"match"`
+}
+
+// KYCFieldCheck is the cross-verification of one field over every document
+// that carries it. Score is the lowest pairwise score.
+type KYCFieldCheck struct {
+	Field       string            `json:"field"`
+	Values      map[string]string `json:"values"` // document -> value as read (DOBs in ISO 8601)
+	Comparisons []KYCComparison   `json:"comparisons,omitempty"`
+	Score       float64           `json:"score"`
+	Status      string            `json:"status"`
+}
+
+// KYCReport is the consolidated result of POST /kyc/verify: the extracted
+// Aadhaar, PAN, income document and optional driving licence, passport and
+// utility bill, and the name, DOB and address checks across them.
+type KYCReport struct {
+	// Status is complete, partial or failed depending on how many of the
+	// documents could be read; Documents gives each one's outcome.
+	Status    string           `json:"status"`
+	Documents []DocumentStatus `json:"documents"`
+
+	Aadhaar       *AadhaarExtractResponse `json:"aadhaar,omitempty"`
+	PAN           *PANResponse            `json:"pan,omitempty"`
+	SalarySlip    *SalarySlipData         `json:"salary_slip,omitempty"`
+	BankStatement *BankStatementData      `json:"bank_statement,omitempty"`
+
+	DrivingLicense *DLResponse           `json:"driving_license,omitempty"`
+	Passport       *PassportResponse     `json:"passport,omitempty"`
+	AddressProof   *AddressProofResponse `json:"address_proof,omitempty"`
+
+	Fields []KYCFieldCheck `json:"fields"`
+	// Score is the mean score of the fields that could be checked.
+	Score float64 `json:"score"`
+	// Verdict is match when every checked field matches, mismatch when any
+	// does not, and insufficient when no field could be checked.
+	Verdict string `json:"verdict"`
+	// Mismatches describes each pair of documents that disagree, e.g.
+	// `dob: aadhaar "1990-08-14" vs pan "1991-08-14"`.
+	Mismatches []string `json:"mismatches,omitempty"`
+}
@@ -0,0 +1,47 @@
+package dto
+
+import "time"
+
+// LayoutTemplateRequest is the "template" of POST /admin/templates: how to
+// read a layout the generic parsers get wrong (small cooperative banks'
+// statements), annotated on the uploaded sample.
+type LayoutTemplateRequest struct {
+	Name    string `json:"name"`
+	DocType string `json:"doc_type"` // salary_slip or bank_statement
+	// TenantID limits the template to one tenant's documents; empty = all.
+	TenantID string `json:"tenant_id,omitempty"`
+	// Keywords must all occur in a document's text for the template to apply
+	// (e.g. the bank's name); they keep look-alike layouts apart.
+	Keywords []string         `json:"keywords,omitempty"`
+	Fields   []TemplateRegion `json:"fields"`
+}
+
+// TemplateRegion is a field of a layout template and where it is printed.
+// The box is in page-relative coordinates (0-1, from the top left), so it
+// holds for any rendering resolution.
+type TemplateRegion struct {
+	// Name is the JSON name of the field it sets in the extraction result
+	// (account_number, account_holder_name, net_salary, ...).
+	Name string     `json:"name"`
+	Type string     `json:"type,omitempty"` // FieldTypeString (default), FieldTypeDate or FieldTypeAmount
+	Page int        `json:"page,omitempty"` // 1-based, default 1
+	Box  [4]float64 `json:"box"`            // x0, y0, x1, y1
+	// SampleValue is what the region reads on the registered sample.
+	SampleValue string `json:"sample_value,omitempty"`
+}
+
+// LayoutTemplate is a registered layout template.
+type LayoutTemplate struct {
+	ID string `json:"id"`
+	LayoutTemplateRequest
+	Fingerprint LayoutFingerprint `json:"fingerprint"`
+	CreatedBy   string            `json:"created_by,omitempty"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// LayoutFingerprint identifies documents in a template's layout by the look
+// of their first page.
+type LayoutFingerprint struct {
+	ImageHash   string  `json:"image_hash"`   // hex difference hash (utils/imagehash)
+	AspectRatio float64 `json:"aspect_ratio"` // width / height
+}
@@ -7,16 +7,48 @@ type DocumentType string
 const (
 	DocTypeSalarySlip    DocumentType = "salary_slip"
 	DocTypeBankStatement DocumentType = "bank_statement"
+	DocTypeGSTReturn     DocumentType = "gst_return"   // GST registration certificate or GSTR-3B
+	DocTypeForm26AS      DocumentType = "form_26as"    // Form 26AS or Annual Information Statement (AIS)
+	DocTypeRent          DocumentType = "rent"         // rent receipt or rental agreement
+	DocTypeEPFPassbook   DocumentType = "epf_passbook" // EPFO member passbook
 )
 
 type DocumentMeta struct {
 	Filename string       `json:"filename"`
 	DocType  DocumentType `json:"doc_type"`
 	Password string       `json:"password,omitempty"`
+	Lang     string       `json:"lang,omitempty"` // OCR language hint, e.g. "hin+eng"
+	// Pages lists, in order, the uploaded images that make up one logical document
+	// (e.g. a bank statement photographed page by page). Filename is then only a label.
+	Pages []string `json:"pages,omitempty"`
+	// PasswordHints are the account holder's details banks derive statement
+	// passwords from; they are tried when Password is empty or wrong.
+	PasswordHints *PasswordHints `json:"password_hints,omitempty"`
+}
+
+// PasswordHints are the details a protected PDF's password may be made of
+// (PAN, date of birth, mobile number, name).
+type PasswordHints struct {
+	PAN   string `json:"pan,omitempty"`
+	DOB   string `json:"dob,omitempty"` // any format NormalizeDOB reads, e.g. "14/08/1990"
+	Phone string `json:"phone,omitempty"`
+	Name  string `json:"name,omitempty"`
 }
 
 type UploadMetadata struct {
 	Documents []DocumentMeta `json:"documents"`
+	TenantID  string         `json:"tenant_id,omitempty"` // selects the tenant's decision rules
+	// ApplicantID is the integrator's identifier of the applicant (e.g. a loan
+	// application number); stored verifications can be listed by it
+	ApplicantID string `json:"applicant_id,omitempty"`
+	// CallbackURL receives the result (or failure) as a webhook once processing finishes
+	CallbackURL string `json:"callback_url,omitempty"`
+	// PasswordHints apply to the documents without their own
+	PasswordHints *PasswordHints `json:"password_hints,omitempty"`
+	// Client is the authenticated API client making the request, set by the
+	// transport, never from the metadata JSON; its stored verification is
+	// readable by that client only
+	Client string `json:"-"`
 }
 
 type DocumentQuality struct {
@@ -24,7 +56,101 @@ type DocumentQuality struct {
 	OcrConfidence   float64  `json:"ocr_confidence"`
 	ContrastScore   float64  `json:"contrast_score"`
 	FinalScore      float64  `json:"final_score"`
+	DocumentAgeDays *int     `json:"document_age_days"`
 	Issues          []string `json:"issues"`
+
+	// SharpnessScore (0-100) is that of the blurriest page image; below 50
+	// the "blurry" issue is raised.
+	SharpnessScore float64 `json:"sharpness_score,omitempty"`
+
+	// Rotations lists the pages that were turned upright before OCR.
+	Rotations []PageRotation `json:"rotations,omitempty"`
+
+	// Script is the Indian script detected on a page read without a language
+	// hint ("Devanagari", "Tamil", ...); that page and the following ones
+	// were read with OCRLang ("hin+eng", ...).
+	Script  string `json:"script,omitempty"`
+	OCRLang string `json:"ocr_lang,omitempty"`
+}
+
+// Where a page's rotation was detected.
+const (
+	RotationEXIF = "exif" // the photo's EXIF orientation tag
+	RotationOSD  = "osd"  // Tesseract orientation and script detection
+)
+
+// PageRotation is a page image turned Degrees clockwise (90, 180 or 270)
+// before OCR.
+type PageRotation struct {
+	Page    int    `json:"page"`
+	Degrees int    `json:"degrees"`
+	Source  string `json:"source"`
+}
+
+// PDFMetadata holds the document information dictionary of a PDF.
+type PDFMetadata struct {
+	Title        string     `json:"title,omitempty"`
+	Author       string     `json:"author,omitempty"`
+	Creator      string     `json:"creator,omitempty"`
+	Producer     string     `json:"producer,omitempty"`
+	CreationDate *time.Time `json:"creation_date,omitempty"`
+	ModDate      *time.Time `json:"mod_date,omitempty"`
+	PageCount    int        `json:"page_count"`
+	// PageFonts lists the fonts each page uses, as "SUBSET+BaseFont" for
+	// embedded subsets.
+	PageFonts [][]string `json:"page_fonts,omitempty"`
+}
+
+// Provenance findings: what the metadata of a PDF says about its history.
+const (
+	ProvenanceEditingSoftware  = "editing_software"        // last written by a PDF editor or online tool
+	ProvenanceRegenerated      = "regenerated"             // produced by a word processor, not the issuer's system
+	ProvenanceModifiedLater    = "modified_after_creation" // modification date well after creation
+	ProvenanceIncrementalSaves = "incremental_updates"     // changes appended after the original was written
+	ProvenanceInvalidSignature = "invalid_signature"       // a digital signature does not verify
+)
+
+// DocumentProvenance is the forensic reading of a PDF's metadata.
+type DocumentProvenance struct {
+	Producer     string     `json:"producer,omitempty"`
+	Creator      string     `json:"creator,omitempty"`
+	CreationDate *time.Time `json:"creation_date,omitempty"`
+	ModDate      *time.Time `json:"mod_date,omitempty"`
+	// EditingSoftware is the editor or office suite named by the producer or
+	// creator, when it is one.
+	EditingSoftware    string   `json:"editing_software,omitempty"`
+	IncrementalUpdates int      `json:"incremental_updates"`
+	Findings           []string `json:"findings"`
+	// TamperRisk is 0 (nothing suspicious) to 100.
+	TamperRisk int `json:"tamper_risk"`
+
+	// Signatures are the issuer's digital signatures on the PDF. A document
+	// with a valid signature over the whole file (SignatureVerified) was not
+	// altered after signing: its metadata findings are cleared and the
+	// heavier tamper checks are skipped.
+	Signatures        []DocumentSignature `json:"signatures,omitempty"`
+	SignatureVerified bool                `json:"signature_verified"`
+
+	Metadata *PDFMetadata `json:"-"`
+}
+
+// Signature validity.
+const (
+	SignatureValid   = "valid"
+	SignatureInvalid = "invalid"
+	SignatureUnknown = "unknown" // e.g. the certificate chain is not trusted
+)
+
+// DocumentSignature is one digital signature on a PDF and its validation.
+type DocumentSignature struct {
+	SignerName  string     `json:"signer_name"`
+	Issuer      string     `json:"issuer,omitempty"` // certificate issuer (CA)
+	SigningTime *time.Time `json:"signing_time,omitempty"`
+	Validity    string     `json:"validity"`
+	Reason      string     `json:"reason,omitempty"` // why it is invalid or unknown
+	Certified   bool       `json:"certified"`        // a certification (DocMDP) signature
+	// DocModified is set when content was appended after this signature.
+	DocModified bool `json:"doc_modified"`
 }
 
 type SalarySlipData struct {
@@ -37,7 +163,32 @@ type SalarySlipData struct {
 	NetSalary     float64         `json:"net_salary"`
 	AccountNumber string          `json:"account_number,omitempty"`
 	IFSC          string          `json:"ifsc,omitempty"`
+	BankName      string          `json:"bank_name,omitempty"`
+	Template      string          `json:"template,omitempty"` // layout template that refined the fields
+	PIIFound      PIISummary      `json:"pii_found"`
 	Quality       DocumentQuality `json:"quality"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// Provenance is the metadata forensics of a PDF upload.
+	Provenance *DocumentProvenance `json:"provenance,omitempty"`
+	// BankBranch is the branch of the IFSC, when an IFSC lookup is configured.
+	BankBranch string `json:"bank_branch,omitempty"`
+	// CIN is the employer's corporate identity number, when the slip prints it.
+	CIN string `json:"cin,omitempty"`
+	// EmployerRegistration is the employer's company registry record, when a
+	// registry lookup is configured.
+	EmployerRegistration *EmployerRegistration `json:"employer_registration,omitempty"`
+}
+
+// EmployerRegistration is what the company registry (MCA) says about the
+// employer named on a salary slip.
+type EmployerRegistration struct {
+	Found          bool   `json:"found"`
+	CIN            string `json:"cin,omitempty"`
+	RegisteredName string `json:"registered_name,omitempty"`
+	Status         string `json:"status,omitempty"` // e.g. Active, Strike Off
+	IncorporatedOn string `json:"incorporated_on,omitempty"`
+	Active         bool   `json:"active"`
 }
 
 type BankTransaction struct {
@@ -47,6 +198,17 @@ type BankTransaction struct {
 	IsCredit    bool      `json:"is_credit"`
 	Balance     float64   `json:"balance,omitempty"`
 	RawLine     string    `json:"raw_line,omitempty"`
+
+	// UPI is set for UPI transfers, parsed from the description
+	UPI *UPIDetails `json:"upi,omitempty"`
+}
+
+// UPIDetails are the parties of a UPI transfer as banks print them in the
+// transaction description.
+type UPIDetails struct {
+	VPA          string `json:"vpa,omitempty"`          // e.g. ravi.k@okaxis
+	Counterparty string `json:"counterparty,omitempty"` // payer of a credit, payee of a debit
+	Reference    string `json:"reference,omitempty"`    // 12-digit UPI reference number (RRN)
 }
 
 type BankStatementData struct {
@@ -57,7 +219,114 @@ type BankStatementData struct {
 	PeriodFrom        *time.Time        `json:"period_from,omitempty"`
 	PeriodTo          *time.Time        `json:"period_to,omitempty"`
 	Transactions      []BankTransaction `json:"transactions"`
+	Template          string            `json:"template,omitempty"` // layout template that refined the fields
+	PIIFound          PIISummary        `json:"pii_found"`
+	TextLayerCheck    *TextLayerCheck   `json:"text_layer_check,omitempty"`
 	Quality           DocumentQuality   `json:"quality"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// FraudSignals are integrity findings on this statement (see utils/integrity).
+	FraudSignals []FraudSignal `json:"fraud_signals,omitempty"`
+	// Provenance is the metadata forensics of a PDF upload.
+	Provenance *DocumentProvenance `json:"provenance,omitempty"`
+	// BankBranch is the branch of the IFSC, when an IFSC lookup is configured.
+	BankBranch string `json:"bank_branch,omitempty"`
+	// MonthlyBalances summarize the balance column per calendar month.
+	MonthlyBalances []MonthlyBalance `json:"monthly_balances,omitempty"`
+	// Address is the account holder's address from the statement header.
+	Address string `json:"address,omitempty"`
+	// Source is "csv" or "xlsx" for a statement downloaded as a spreadsheet,
+	// "account_aggregator" for one from consented Account Aggregator data,
+	// both read without OCR; empty for scans and PDFs.
+	Source string `json:"source,omitempty"`
+}
+
+// MonthlyBalance summarizes a statement's running balance over one month.
+type MonthlyBalance struct {
+	Month   string  `json:"month"` // "YYYY-MM"
+	Closing float64 `json:"closing"`
+	Minimum float64 `json:"minimum"`
+	// Average is the mean end-of-day balance over Days, the way banks compute
+	// the average monthly balance.
+	Average float64 `json:"average"`
+	Days    int     `json:"days"` // days of the month the statement covers
+}
+
+// GSTData is income evidence for self-employed applicants: a GST registration
+// certificate (REG-06) or a monthly GSTR-3B summary.
+type GSTData struct {
+	Kind         string `json:"kind"` // registration | gstr3b
+	GSTIN        string `json:"gstin"`
+	LegalName    string `json:"legal_name"`
+	TradeName    string `json:"trade_name,omitempty"`
+	ReturnPeriod string `json:"return_period,omitempty"` // "YYYY-MM", GSTR-3B only
+	// Turnover is the taxable value of outward supplies declared in table 3.1
+	// (rows a, b, c and e) of a GSTR-3B; zero for registration certificates.
+	Turnover float64         `json:"turnover"`
+	PIIFound PIISummary      `json:"pii_found"`
+	Quality  DocumentQuality `json:"quality"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+}
+
+// MonthlyIncomeSummary aggregates bank statement credits by calendar month.
+type MonthlyIncomeSummary struct {
+	Months           []MonthlyCredits  `json:"months"`
+	RecurringCredits []RecurringCredit `json:"recurring_credits"`
+	// AverageMonthlyCredits is total credits over the months the statements cover.
+	AverageMonthlyCredits float64 `json:"average_monthly_credits"`
+	// AverageMonthlyIncome is the expected monthly inflow from recurring salary-like credits.
+	AverageMonthlyIncome float64 `json:"average_monthly_income"`
+}
+
+type MonthlyCredits struct {
+	Month           string  `json:"month"` // "YYYY-MM"
+	TotalCredits    float64 `json:"total_credits"`
+	CreditCount     int     `json:"credit_count"`
+	RecurringIncome float64 `json:"recurring_income"`
+}
+
+// RecurringCredit is a run of credits from the same payer, of similar amount, about once a month.
+type RecurringCredit struct {
+	Payer         string   `json:"payer"`
+	AverageAmount float64  `json:"average_amount"`
+	Months        []string `json:"months"`
+	SalaryLike    bool     `json:"salary_like"` // description mentions salary
+}
+
+// DebtObligations are the loan repayments (EMIs) found among bank statement
+// debits, for the fixed-obligation-to-income ratio (FOIR).
+type DebtObligations struct {
+	Obligations []Obligation `json:"obligations"`
+	// TotalMonthlyObligation is the sum of the obligations' monthly amounts.
+	TotalMonthlyObligation float64 `json:"total_monthly_obligation"`
+	// FOIR is TotalMonthlyObligation over the average monthly recurring income;
+	// 0 when there is no recurring income to compare with.
+	FOIR float64 `json:"foir"`
+}
+
+// Obligation is the run of EMI debits to one lender.
+type Obligation struct {
+	Lender        string   `json:"lender"`
+	Mode          string   `json:"mode"` // EMI, NACH, ECS, LOAN or SI (standing instruction)
+	MonthlyAmount float64  `json:"monthly_amount"`
+	Months        []string `json:"months"`
+	Recurring     bool     `json:"recurring"` // debited in two or more months
+}
+
+// TextLayerCheck compares key figures read from a text PDF's embedded text layer
+// with OCR of the same pages as rendered. A text layer edited over an untouched
+// scan shows up as a mismatch.
+type TextLayerCheck struct {
+	PagesChecked []int            `json:"pages_checked"`
+	Mismatches   []FigureMismatch `json:"mismatches"`
+}
+
+type FigureMismatch struct {
+	Page      int     `json:"page"`
+	Figure    string  `json:"figure"` // closing_balance | total_credits | total_debits
+	TextLayer float64 `json:"text_layer"`
+	OCR       float64 `json:"ocr"`
 }
 
 type CrossCheckResult struct {
@@ -66,17 +335,154 @@ type CrossCheckResult struct {
 	AccountMatch         bool     `json:"account_match"`
 	MissingSalaryCredits []string `json:"missing_salary_credits"`
 	Notes                []string `json:"notes"`
+	// FraudSignals collects the integrity findings of all bank statements.
+	FraudSignals []FraudSignal `json:"fraud_signals"`
+
+	// Bounces lists the months with returned cheques or mandates, or charges
+	// for them; BounceCount is the number of returns over all months.
+	Bounces     []MonthlyBounces `json:"bounces"`
+	BounceCount int              `json:"bounce_count"`
+
+	// TDS compares the salary slips with the salary TDS of Form 26AS / AIS,
+	// when one was uploaded.
+	TDS *TDSCheck `json:"tds,omitempty"`
+	// EPF compares the salary slips with the EPF passbooks, when one was
+	// uploaded.
+	EPF *EPFCheck `json:"epf,omitempty"`
 }
 
+// MonthlyBounces counts one month's payments returned unpaid and the charges
+// levied for them. Underwriters read repeated bounces as a credit risk.
+type MonthlyBounces struct {
+	Month          string  `json:"month"` // "YYYY-MM"
+	ChequeReturns  int     `json:"cheque_returns"`
+	MandateReturns int     `json:"mandate_returns"` // ECS/NACH debits (often EMIs) returned unpaid
+	PenaltyCharges int     `json:"penalty_charges"` // bounce and insufficient-funds charges
+	PenaltyAmount  float64 `json:"penalty_amount"`
+}
+
+// Fraud signal types.
+const (
+	FraudBalanceMismatch = "balance_mismatch"      // running balance does not follow from the amounts
+	FraudDuplicateRow    = "duplicate_transaction" // a transaction row appears twice
+	FraudRoundedSalary   = "rounded_salary_credit" // salary credit in whole thousands
+	FraudFontAnomaly     = "font_anomaly"          // fonts suggest text was added to the PDF
+	FraudMetadataAnomaly = "metadata_anomaly"      // PDF dates suggest it was edited after issue
+	FraudTDSMismatch     = "tds_mismatch"          // salary slip not backed by the employer's TDS records
+	FraudEPFMismatch     = "epf_mismatch"          // salary slip not backed by the employer's EPF contributions
+	FraudUnknownEmployer = "unknown_employer"      // employer not in the company registry, or not active
+)
+
+// FraudSignal is one integrity finding on a bank statement. Signals are
+// heuristics for a reviewer, not proof of tampering.
+type FraudSignal struct {
+	Type    string `json:"type"`
+	Account string `json:"account,omitempty"`
+	Page    int    `json:"page,omitempty"`
+	Detail  string `json:"detail"`
+}
+
+// Where an ITRResult was read from.
+const (
+	ITRSourceOCR        = "ocr"        // the text of a PDF or image (ITR-V, acknowledgement)
+	ITRSourceStructured = "structured" // the JSON/XML of the e-filing utility
+)
+
 // ITRResult represents parsed Income Tax Return data
 type ITRResult struct {
-	PAN            string  `json:"pan"`
-	Name           string  `json:"name"`
-	AssessmentYear string  `json:"assessment_year"`
-	TotalIncome    float64 `json:"total_income"`
-	TaxableIncome  float64 `json:"taxable_income"`
-	TaxPaid        float64 `json:"tax_paid"`
-	RefundAmount   float64 `json:"refund_amount"`
-	FilingDate     string  `json:"filing_date"`
-	RawText        string  `json:"raw_text"`
+	PAN            string     `json:"pan"`
+	Name           string     `json:"name"`
+	AssessmentYear string     `json:"assessment_year"`
+	Form           string     `json:"form,omitempty"` // ITR-1 ... ITR-7, from structured files
+	TotalIncome    float64    `json:"total_income"`
+	TaxableIncome  float64    `json:"taxable_income"`
+	TaxPaid        float64    `json:"tax_paid"`
+	RefundAmount   float64    `json:"refund_amount"`
+	FilingDate     string     `json:"filing_date"`
+	PIIFound       PIISummary `json:"pii_found"`
+	RawText        string     `json:"raw_text"`
+	// Source is ITRSourceOCR or ITRSourceStructured; Confidence (0-100) is
+	// how far the figures can be relied on, from the quality of the text read
+	// or near certain for structured files.
+	Source     string  `json:"source"`
+	Confidence float64 `json:"confidence"`
+	// Composition is the income under each head, from the computation sheet
+	// or the full form; nil when only the acknowledgement summary was read.
+	Composition *ITRComposition `json:"income_composition,omitempty"`
+	// Provenance is the metadata forensics and signatures of a PDF upload.
+	Provenance *DocumentProvenance `json:"provenance,omitempty"`
+}
+
+// ITRComposition is the make-up of a return's income: the income under each
+// head, the Chapter VI-A deductions and the tax computed on the total income.
+type ITRComposition struct {
+	Salary           float64 `json:"salary"`
+	HouseProperty    float64 `json:"house_property"` // negative for a loss, e.g. home loan interest
+	Business         float64 `json:"business"`       // profits and gains of business or profession
+	CapitalGains     float64 `json:"capital_gains"`
+	OtherSources     float64 `json:"other_sources"`
+	GrossTotalIncome float64 `json:"gross_total_income"`
+	Deduction80C     float64 `json:"deduction_80c"`
+	Deduction80D     float64 `json:"deduction_80d"`
+	ChapterVIA       float64 `json:"deductions_chapter_via"` // all Chapter VI-A deductions
+	// TaxComputed is the tax on the total income after rebate, with
+	// surcharge and cess, before taxes paid are set off.
+	TaxComputed float64 `json:"tax_computed"`
+}
+
+// Form16Result represents parsed Form-16 (TDS certificate on salary) data
+type Form16Result struct {
+	EmployerTAN    string     `json:"employer_tan"`
+	EmployeePAN    string     `json:"employee_pan"`
+	FinancialYear  string     `json:"financial_year"`
+	AssessmentYear string     `json:"assessment_year"`
+	GrossSalary    float64    `json:"gross_salary"`
+	TDSDeducted    float64    `json:"tds_deducted"`
+	PIIFound       PIISummary `json:"pii_found"`
+	RawText        string     `json:"raw_text"`
+	// Provenance is the metadata forensics and signatures of a PDF upload.
+	Provenance *DocumentProvenance `json:"provenance,omitempty"`
+}
+
+// Decision outcomes, in increasing order of severity.
+const (
+	DecisionApprove = "approve"
+	DecisionReview  = "review"
+	DecisionReject  = "reject"
+)
+
+// DecisionReason records why a decision rule did not pass.
+type DecisionReason struct {
+	RuleID  string `json:"rule_id"`
+	Outcome string `json:"outcome"`
+	Message string `json:"message"`
+}
+
+// Decision is the overall verdict produced by tenant decision rules.
+type Decision struct {
+	Outcome string           `json:"outcome"`
+	Reasons []DecisionReason `json:"reasons"`
+}
+
+var decisionSeverity = map[string]int{DecisionApprove: 0, DecisionReview: 1, DecisionReject: 2}
+
+// Add appends a reason and escalates the outcome if the reason is more severe.
+func (d *Decision) Add(r DecisionReason) {
+	d.Reasons = append(d.Reasons, r)
+	if decisionSeverity[r.Outcome] > decisionSeverity[d.Outcome] {
+		d.Outcome = r.Outcome
+	}
+}
+
+// VerificationScore is the quick-screen summary of a verification.
+type VerificationScore struct {
+	VerificationID string           `json:"verification_id"`
+	Score          float64          `json:"score"` // 0–100
+	Components     []ScoreComponent `json:"components"`
+}
+
+type ScoreComponent struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`  // 0–100
+	Weight float64 `json:"weight"` // normalized, weights sum to 1
 }
@@ -0,0 +1,24 @@
+package dto
+
+// BoundingBox is an axis-aligned rectangle in page pixels; (X0,Y0) is the top-left corner.
+type BoundingBox struct {
+	X0 int `json:"x0"`
+	Y0 int `json:"y0"`
+	X1 int `json:"x1"`
+	Y1 int `json:"y1"`
+}
+
+// OCRWord is one recognized word with its position on the page.
+type OCRWord struct {
+	Text       string      `json:"text"`
+	Confidence float64     `json:"confidence"` // 0-100
+	Box        BoundingBox `json:"box"`
+	Page       int         `json:"page"` // 1-based
+}
+
+// StructuredText is OCR output with word-level layout.
+type StructuredText struct {
+	Text       string    `json:"text"`
+	Confidence float64   `json:"confidence"` // mean word confidence, 0-100
+	Words      []OCRWord `json:"words"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+// FieldOverrideRequest is the body of PATCH /income/verifications/:id.
+// Field is a dot path into the verification response, e.g. "salary_slips.0.net_salary".
+type FieldOverrideRequest struct {
+	Overrides []FieldOverrideInput `json:"overrides" binding:"required"`
+}
+
+type FieldOverrideInput struct {
+	Field  string      `json:"field"`
+	Value  interface{} `json:"value"`
+	Reason string      `json:"reason"`
+}
+
+// FieldOverride is one entry of the override audit trail.
+type FieldOverride struct {
+	Field        string      `json:"field"`
+	MachineValue interface{} `json:"machine_value"`
+	Value        interface{} `json:"value"`
+	Reason       string      `json:"reason,omitempty"`
+	Reviewer     string      `json:"reviewer"`
+	OverriddenAt time.Time   `json:"overridden_at"`
+}
+
+// VerificationRecord is the stored, authoritative view of one verification.
+// Extracted never changes after OCR; Current is Extracted with every override
+// applied in order, and is what downstream systems should read.
+type VerificationRecord struct {
+	ID       string `json:"verification_id"`
+	TenantID string `json:"tenant_id,omitempty"`
+	// Client is the API client that requested the verification; only it can
+	// read the record ("" when API keys are not configured)
+	Client string `json:"client,omitempty"`
+	// ApplicantID is the metadata's applicant_id
+	ApplicantID string                     `json:"applicant_id,omitempty"`
+	Extracted   IncomeVerificationResponse `json:"extracted"`
+	Current     IncomeVerificationResponse `json:"current"`
+	Overrides   []FieldOverride            `json:"overrides"`
+	CreatedAt   time.Time                  `json:"created_at"`
+	UpdatedAt   time.Time                  `json:"updated_at"`
+}
+
+// VerificationList is the body of GET /verifications.
+type VerificationList struct {
+	Verifications []*VerificationRecord `json:"verifications"`
+}
@@ -6,4 +6,16 @@ type PANResponse struct {
 	FatherName string `json:"father_name"`
 	DOB        string `json:"dob"`
 	RawText    string `json:"raw_text"`
+
+	// Structural validation of PAN (see utils.ValidatePAN).
+	Valid            bool     `json:"valid"`
+	HolderType       string   `json:"holder_type,omitempty"`
+	ValidationIssues []string `json:"validation_issues,omitempty"`
+
+	// Photo is the cropped portrait as a base64 JPEG (include_photo=true).
+	Photo string `json:"photo,omitempty"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// DOBISO is DOB in ISO 8601 (utils.NormalizeDOB); empty when DOB is not a valid date.
+	DOBISO string `json:"dob_iso,omitempty"`
 }
@@ -0,0 +1,24 @@
+package dto
+
+type PassportResponse struct {
+	PassportNumber string `json:"passport_number"`
+	Surname        string `json:"surname"`
+	GivenNames     string `json:"given_names"`
+	DOB            string `json:"dob"`
+	Sex            string `json:"sex"`
+	Nationality    string `json:"nationality"`
+	IssuingCountry string `json:"issuing_country,omitempty"`
+	ExpiryDate     string `json:"expiry_date"`
+	// Source is "mrz" when the fields were decoded from the machine readable
+	// zone and "ocr" when they came from the printed labels.
+	Source          string   `json:"source"`
+	MRZValid        bool     `json:"mrz_valid"`
+	MRZFailedChecks []string `json:"mrz_failed_checks,omitempty"`
+	RawText         string   `json:"raw_text"`
+	// Photo is the cropped portrait as a base64 JPEG (include_photo=true).
+	Photo string `json:"photo,omitempty"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+	// DOBISO is DOB in ISO 8601 (utils.NormalizeDOB); empty when DOB is not a valid date.
+	DOBISO string `json:"dob_iso,omitempty"`
+}
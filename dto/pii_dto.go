@@ -0,0 +1,22 @@
+package dto
+
+// PII identifier types reported by the scanner
+const (
+	PIITypeAadhaar       = "aadhaar"
+	PIITypePAN           = "pan"
+	PIITypeAccountNumber = "account_number"
+	PIITypePhone         = "phone"
+	PIITypeEmail         = "email"
+)
+
+// PIIFinding is one identifier detected in extracted text.
+// Start/End are byte offsets into the scanned text; Masked never carries the full value.
+type PIIFinding struct {
+	Type   string `json:"type"`
+	Start  int    `json:"start"`
+	End    int    `json:"end"`
+	Masked string `json:"masked"`
+}
+
+// PIISummary counts findings per PII type, e.g. {"pan": 1, "phone": 2}.
+type PIISummary map[string]int
@@ -0,0 +1,33 @@
+package dto
+
+// Regions POST /documents/redact hides.
+const (
+	RedactAadhaar   = "aadhaar_number" // the first eight digits, as on a masked Aadhaar
+	RedactSignature = "signature"
+	RedactPhoto     = "photo"
+)
+
+// Redaction modes.
+const (
+	RedactModeBlackout = "blackout"
+	RedactModeBlur     = "blur"
+)
+
+// RedactedDocument is the result of POST /documents/redact: the document with
+// its sensitive regions hidden, ready to archive.
+type RedactedDocument struct {
+	// RedactedFile is the redacted document, base64: a PDF of the redacted
+	// page images (no text layer) for a PDF or multi-page upload, else a JPEG.
+	RedactedFile string           `json:"redacted_file"`
+	ContentType  string           `json:"content_type"`
+	Mode         string           `json:"mode"`
+	Pages        int              `json:"pages"`
+	Regions      []RedactedRegion `json:"regions"`
+}
+
+// RedactedRegion is one hidden region, in the page pixels of the redacted file.
+type RedactedRegion struct {
+	Type string      `json:"type"`
+	Page int         `json:"page"` // 1-based
+	Box  BoundingBox `json:"box"`
+}
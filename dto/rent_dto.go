@@ -0,0 +1,18 @@
+package dto
+
+// RentData is what a rent receipt or a rental (lease, leave and licence)
+// agreement says about a tenancy.
+type RentData struct {
+	Kind         string  `json:"kind"` // receipt | agreement
+	LandlordName string  `json:"landlord_name"`
+	LandlordPAN  string  `json:"landlord_pan,omitempty"`
+	TenantName   string  `json:"tenant_name"`
+	MonthlyRent  float64 `json:"monthly_rent"`
+	// PeriodFrom and PeriodTo bound the months paid for (receipt) or the term
+	// of the tenancy (agreement), YYYY-MM-DD.
+	PeriodFrom string          `json:"period_from,omitempty"`
+	PeriodTo   string          `json:"period_to,omitempty"`
+	Address    string          `json:"address,omitempty"`
+	PIIFound   PIISummary      `json:"pii_found"`
+	Quality    DocumentQuality `json:"quality"`
+}
@@ -1,14 +1,16 @@
 package dto
 
 import (
-	"mime/multipart"
 	"errors"
+	"mime/multipart"
 )
 
 // IncomeVerificationRequest represents the incoming request
 type IncomeVerificationRequest struct {
 	Files    []*multipart.FileHeader `form:"files[]" binding:"required"`
 	Metadata string                  `form:"metadata" binding:"required"`
+	TenantID string                  `form:"-"` // from the X-Tenant-ID header
+	Client   string                  `form:"-"` // the authenticated API client
 }
 
 // Validate performs basic validation on the request
@@ -20,4 +22,4 @@ func (r *IncomeVerificationRequest) Validate() error {
 		return errors.New("metadata is required")
 	}
 	return nil
-}
\ No newline at end of file
+}
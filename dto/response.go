@@ -7,18 +7,74 @@ var (
 	ErrInsufficientSalarySlips = errors.New("minimum 6 salary slips required")
 )
 
-// ErrorResponse represents an error response
+// ErrorResponse is the body of every error response.
 type ErrorResponse struct {
-	Error   string `json:"error"`
-	Message string `json:"message"`
-	Code    int    `json:"code"`
+	Error   ErrorCode `json:"error"`
+	Message string    `json:"message"`
+	Code    int       `json:"code"`
+}
+
+// Overall status of an income verification.
+const (
+	VerificationComplete = "complete" // every document was processed
+	VerificationPartial  = "partial"  // some documents failed; results cover the rest
+	VerificationFailed   = "failed"   // no document could be processed
+)
+
+// Status of one document of an income verification.
+const (
+	DocumentSucceeded = "success"
+	DocumentFailed    = "failed"
+	// DocumentDuplicate repeats another document of the request; it is left
+	// out of the results so it does not count twice
+	DocumentDuplicate = "duplicate"
+)
+
+// DocumentStatus reports the outcome of one metadata entry.
+type DocumentStatus struct {
+	Filename string       `json:"filename"`
+	DocType  DocumentType `json:"doc_type"`
+	Status   string       `json:"status"`
+	Error    string       `json:"error,omitempty"` // why the document failed
+
+	// DuplicateOf is the filename of the document this one repeats, and
+	// DuplicateReason how: an identical file, the same image, or the same
+	// pay month or statement period.
+	DuplicateOf     string `json:"duplicate_of,omitempty"`
+	DuplicateReason string `json:"duplicate_reason,omitempty"`
+	// ContentHash is the SHA-256 of the uploaded file(s), hex.
+	ContentHash string `json:"content_hash,omitempty"`
+	// PreviousVerificationID is an earlier verification of the same
+	// applicant that had the identical file.
+	PreviousVerificationID string `json:"previous_verification_id,omitempty"`
 }
 
 // IncomeVerificationResponse is the final response structure
 type IncomeVerificationResponse struct {
-	SalarySlips     []SalarySlipData  `json:"salary_slips"`
+	VerificationID  string              `json:"verification_id,omitempty"`
+	Status          string              `json:"status"`
+	Documents       []DocumentStatus    `json:"documents"`
+	SalarySlips     []SalarySlipData    `json:"salary_slips"`
 	BankStatements  []BankStatementData `json:"bank_statements"`
-	CrossCheck      CrossCheckResult   `json:"cross_check"`
-	MinQualityScore float64            `json:"min_quality_score"`
-	ProcessedAt     string             `json:"processed_at"`
-}
\ No newline at end of file
+	GSTReturns      []GSTData           `json:"gst_returns,omitempty"`
+	Form26AS        []Form26ASData      `json:"form_26as,omitempty"`
+	Rent            []RentData          `json:"rent,omitempty"`
+	EPFPassbooks    []EPFPassbookData   `json:"epf_passbooks,omitempty"`
+	CrossCheck      CrossCheckResult    `json:"cross_check"`
+	MinQualityScore float64             `json:"min_quality_score"`
+	ProcessedAt     string              `json:"processed_at"`
+	Decision        *Decision           `json:"decision,omitempty"` // set when the tenant has decision rules
+
+	// Bank statement credits aggregated per month, with recurring salary-like credits
+	MonthlyIncomeSummary *MonthlyIncomeSummary `json:"monthly_income_summary,omitempty"`
+
+	// Loan EMIs debited from the bank statements, with the FOIR they imply
+	DebtObligations *DebtObligations `json:"debt_obligations,omitempty"`
+
+	// TamperRisk is the highest PDF metadata tamper risk among the documents (0-100).
+	TamperRisk int `json:"tamper_risk"`
+
+	// MonthlyRent is the housing expense shown by the rent receipts and rental
+	// agreements (the latest period's rent), for affordability net of housing.
+	MonthlyRent float64 `json:"monthly_rent,omitempty"`
+}
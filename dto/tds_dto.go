@@ -0,0 +1,72 @@
+package dto
+
+// Form26ASData is the TDS and SFT parts of a Form 26AS or Annual Information
+// Statement (AIS): the tax deducted at source for a PAN, grouped by deductor,
+// and the high-value transactions reported for it.
+type Form26ASData struct {
+	Kind           string        `json:"kind"` // 26as | ais
+	PAN            string        `json:"pan"`
+	AssessmentYear string        `json:"assessment_year,omitempty"`
+	Deductors      []TDSDeductor `json:"deductors"`
+	// SFTTransactions are the high-value transactions banks, registrars and
+	// others reported for the PAN (26AS Part E, the AIS "SFT Information").
+	SFTTransactions []SFTTransaction `json:"sft_transactions,omitempty"`
+	PIIFound        PIISummary       `json:"pii_found"`
+	Quality         DocumentQuality  `json:"quality"`
+	// Provenance is the metadata forensics and signatures of a PDF upload.
+	Provenance *DocumentProvenance `json:"provenance,omitempty"`
+}
+
+// TDSDeductor is one deductor (an employer for section 192) and its entries.
+type TDSDeductor struct {
+	Name             string     `json:"name"`
+	TAN              string     `json:"tan"`
+	TotalAmountPaid  float64    `json:"total_amount_paid"`
+	TotalTaxDeducted float64    `json:"total_tax_deducted"`
+	Entries          []TDSEntry `json:"entries"`
+}
+
+// TDSEntry is one credit on which tax was deducted.
+type TDSEntry struct {
+	Section         string  `json:"section"`          // 192 is salary
+	TransactionDate string  `json:"transaction_date"` // YYYY-MM-DD
+	Quarter         string  `json:"quarter"`          // Q1 (Apr-Jun) .. Q4 (Jan-Mar)
+	FinancialYear   string  `json:"financial_year"`   // e.g. 2024-25
+	AmountPaid      float64 `json:"amount_paid"`
+	TaxDeducted     float64 `json:"tax_deducted"`
+}
+
+// SFTTransaction is one transaction of a Statement of Financial Transactions,
+// such as cash deposits or time deposits above the reporting limits.
+type SFTTransaction struct {
+	Code            string  `json:"code,omitempty"` // SFT-001 .. SFT-018; the AIS names it, 26AS does not
+	Description     string  `json:"description"`
+	FilerName       string  `json:"filer_name,omitempty"`
+	TransactionDate string  `json:"transaction_date"` // YYYY-MM-DD
+	Amount          float64 `json:"amount"`
+}
+
+// SectionSalary is the TDS section for salary payments.
+const SectionSalary = "192"
+
+// TDSCheck compares the salary slips with the salary TDS records of Form
+// 26AS / AIS.
+type TDSCheck struct {
+	// Deductor and TAN identify the deductor matched to the slips' employer;
+	// empty when none matched.
+	Deductor string          `json:"deductor,omitempty"`
+	TAN      string          `json:"tan,omitempty"`
+	Months   []TDSMonthCheck `json:"months"`
+	// Consistent is false when a slip's employer or salary is not backed by
+	// the TDS records (see CrossCheckResult.FraudSignals).
+	Consistent bool `json:"consistent"`
+}
+
+// TDSMonthCheck compares one salary slip with the salary the deductor
+// reported for that month.
+type TDSMonthCheck struct {
+	Month        string  `json:"month"` // YYYY-MM
+	SlipNet      float64 `json:"slip_net"`
+	ReportedPaid float64 `json:"reported_paid"` // gross salary credited per Form 26AS; 0 when not reported
+	Consistent   bool    `json:"consistent"`
+}
@@ -0,0 +1,14 @@
+package dto
+
+import "time"
+
+// APIUsage is the request accounting of one API client.
+type APIUsage struct {
+	Client      string    `json:"client"`
+	Requests    int64     `json:"requests"`
+	RateLimited int64     `json:"rate_limited"`
+	Errors      int64     `json:"errors"` // responses with status >= 400
+	LastUsed    time.Time `json:"last_used,omitempty"`
+	// RatePerMinute is the client's request limit; 0 = unlimited.
+	RatePerMinute int `json:"rate_per_minute"`
+}
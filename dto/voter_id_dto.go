@@ -0,0 +1,12 @@
+package dto
+
+type VoterIDResponse struct {
+	EPICNumber   string `json:"epic_number"`
+	Name         string `json:"name"`
+	RelationType string `json:"relation_type,omitempty"` // father | husband | mother | relation
+	RelationName string `json:"relation_name"`
+	Address      string `json:"address"`
+	RawText      string `json:"raw_text"`
+	// FieldSources names the OCR engine each field was read by (consensus OCR).
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+}
@@ -0,0 +1,25 @@
+package dto
+
+// Webhook event statuses
+const (
+	WebhookStatusCompleted = "completed"
+	WebhookStatusFailed    = "failed"
+)
+
+// WebhookEvent is the body POSTed to a caller's callback_url, e.g.
+// {"event": "income.completed", "status": "completed", "result": {...}}.
+type WebhookEvent struct {
+	Event     string      `json:"event"` // <income|aadhaar|pan|itr>.<completed|failed>
+	Status    string      `json:"status"`
+	Result    interface{} `json:"result,omitempty"`
+	Error     string      `json:"error,omitempty"`
+	Timestamp string      `json:"timestamp"`
+}
+
+// NewWebhookEvent builds the event for a finished extraction of kind (income, aadhaar, ...).
+func NewWebhookEvent(kind string, result interface{}, err error) WebhookEvent {
+	if err != nil {
+		return WebhookEvent{Event: kind + "." + WebhookStatusFailed, Status: WebhookStatusFailed, Error: err.Error()}
+	}
+	return WebhookEvent{Event: kind + "." + WebhookStatusCompleted, Status: WebhookStatusCompleted, Result: result}
+}
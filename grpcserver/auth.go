@@ -0,0 +1,82 @@
+package grpcserver
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+)
+
+// apiKeyMetadata carries the client's API key, like the REST X-API-Key header.
+const apiKeyMetadata = "x-api-key"
+
+// APIKeyInterceptors authenticate calls against the same keyring as the REST
+// API, enforce the client's rate limit and account the call to the client,
+// whom the handlers find in their context (auth.FromContext).
+func APIKeyInterceptors(keys *auth.Keyring) (grpc.UnaryServerInterceptor, grpc.StreamServerInterceptor) {
+	unary := func(ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		client, err := admit(ctx, keys)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := handler(auth.NewContext(ctx, client), req)
+		keys.Record(client, httpStatus(err))
+		return resp, err
+	}
+	stream := func(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		client, err := admit(ss.Context(), keys)
+		if err != nil {
+			return err
+		}
+		err = handler(srv, &clientStream{ServerStream: ss, ctx: auth.NewContext(ss.Context(), client)})
+		keys.Record(client, httpStatus(err))
+		return err
+	}
+	return unary, stream
+}
+
+// clientStream is a stream whose context carries the authenticated client.
+type clientStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *clientStream) Context() context.Context { return s.ctx }
+
+func admit(ctx context.Context, keys *auth.Keyring) (auth.Client, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	var key string
+	if v := md.Get(apiKeyMetadata); len(v) > 0 {
+		key = v[0]
+	}
+	client, ok := keys.Authenticate(key)
+	if !ok {
+		return auth.Client{}, status.Errorf(codes.Unauthenticated, "a valid API key is required in the %q metadata", apiKeyMetadata)
+	}
+	allowed, _, retryAfter := keys.Allow(client)
+	if !allowed {
+		slog.WarnContext(ctx, "API client rate limited", "client", client.Name)
+		return auth.Client{}, status.Errorf(codes.ResourceExhausted, "rate limit exceeded; retry after %ss", strconv.Itoa(int(retryAfter.Seconds())+1))
+	}
+	return client, nil
+}
+
+// httpStatus maps a call's outcome to the HTTP status usage accounting expects.
+func httpStatus(err error) int {
+	switch status.Code(err) {
+	case codes.OK:
+		return 200
+	case codes.InvalidArgument:
+		return 400
+	case codes.ResourceExhausted:
+		return 429
+	default:
+		return 500
+	}
+}
@@ -0,0 +1,348 @@
+// Package grpcserver serves the OCR operations over gRPC (proto/ocr/v1) for
+// internal services, next to the REST API. Both call the same services and
+// return the same JSON documents, PII-masked the same way.
+package grpcserver
+
+//go:generate protoc --go_out=.. --go_opt=paths=source_relative --go-grpc_out=.. --go-grpc_opt=paths=source_relative -I .. ../proto/ocr/v1/ocr.proto
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/handler"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	ocrv1 "github.com/Aashish23092/ocr-income-verification/proto/ocr/v1"
+	"github.com/Aashish23092/ocr-income-verification/scan"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/Aashish23092/ocr-income-verification/vault"
+)
+
+// Server implements ocrv1.OCRServiceServer.
+type Server struct {
+	ocrv1.UnimplementedOCRServiceServer
+
+	Income   *service.IncomeService
+	Aadhaar  *service.AadhaarService
+	PAN      *service.PANService
+	DL       *service.DrivingLicenseService
+	Redactor *vault.Redactor
+	// ValidateLang checks OCR language hints (TesseractClient.ValidateLang)
+	ValidateLang func(lang string) error
+	// UploadLimit and UploadLimits (by route) are the upload limits of the
+	// REST API; an operation has those of its REST route: the size of each
+	// file and of the whole upload, the pages of each PDF, the number of files
+	UploadLimit  handler.UploadLimit
+	UploadLimits map[string]handler.UploadLimit
+	// DocumentFormats and UploadFormats (by route) are the formats the REST
+	// API takes; an operation's files must be, by content, in a format of its
+	// REST route
+	DocumentFormats []filetype.Format
+	UploadFormats   map[string]handler.UploadFormats
+	// Scanner, when set, checks every file for malware before it is read;
+	// ScanAction (scan.ActionReject or scan.ActionFlag) is what happens to
+	// an infected one
+	Scanner    scan.Scanner
+	ScanAction string
+}
+
+func (s *Server) VerifyIncome(ctx context.Context, req *ocrv1.VerifyIncomeRequest) (*ocrv1.Result, error) {
+	return s.run(ctx, &ocrv1.UploadStart{
+		Operation:    ocrv1.Operation_OPERATION_VERIFY_INCOME,
+		MetadataJson: req.MetadataJson,
+		TenantId:     req.TenantId,
+	}, req.Files)
+}
+
+func (s *Server) ExtractAadhaar(ctx context.Context, req *ocrv1.DocumentRequest) (*ocrv1.Result, error) {
+	return s.run(ctx, documentStart(ocrv1.Operation_OPERATION_EXTRACT_AADHAAR, req), req.Files)
+}
+
+func (s *Server) ExtractPAN(ctx context.Context, req *ocrv1.DocumentRequest) (*ocrv1.Result, error) {
+	return s.run(ctx, documentStart(ocrv1.Operation_OPERATION_EXTRACT_PAN, req), req.Files)
+}
+
+func (s *Server) AnalyzeITR(ctx context.Context, req *ocrv1.DocumentRequest) (*ocrv1.Result, error) {
+	return s.run(ctx, documentStart(ocrv1.Operation_OPERATION_ANALYZE_ITR, req), req.Files)
+}
+
+func (s *Server) ExtractDL(ctx context.Context, req *ocrv1.DocumentRequest) (*ocrv1.Result, error) {
+	return s.run(ctx, documentStart(ocrv1.Operation_OPERATION_EXTRACT_DL, req), req.Files)
+}
+
+// operationRoutes are the REST routes of the operations, whose upload limits
+// and formats they share.
+var operationRoutes = map[ocrv1.Operation]string{
+	ocrv1.Operation_OPERATION_VERIFY_INCOME:   "/api/v1/income/verify",
+	ocrv1.Operation_OPERATION_EXTRACT_AADHAAR: "/api/v1/aadhaar/extract",
+	ocrv1.Operation_OPERATION_EXTRACT_PAN:     "/api/v1/pan/ocr",
+	ocrv1.Operation_OPERATION_ANALYZE_ITR:     "/api/v1/itr/analyze",
+	ocrv1.Operation_OPERATION_EXTRACT_DL:      "/api/v1/driving-license/ocr",
+}
+
+func (s *Server) uploadLimit(op ocrv1.Operation) handler.UploadLimit {
+	return handler.RouteUploadLimit(s.UploadLimit, s.UploadLimits, operationRoutes[op])
+}
+
+func documentStart(op ocrv1.Operation, req *ocrv1.DocumentRequest) *ocrv1.UploadStart {
+	return &ocrv1.UploadStart{Operation: op, Password: req.Password, Lang: req.Lang}
+}
+
+// Upload assembles a streamed upload and runs its operation. The upload is
+// cut off as soon as it exceeds the operation's file count or size limits.
+func (s *Server) Upload(stream ocrv1.OCRService_UploadServer) error {
+	var start *ocrv1.UploadStart
+	var files []*ocrv1.File
+	var limit handler.UploadLimit
+	var total int64
+	for {
+		chunk, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		switch part := chunk.Part.(type) {
+		case *ocrv1.UploadChunk_Start:
+			if start != nil {
+				return status.Error(codes.InvalidArgument, "upload has more than one start message")
+			}
+			start = part.Start
+			limit = s.uploadLimit(start.Operation)
+		case *ocrv1.UploadChunk_File:
+			if start == nil {
+				return status.Error(codes.InvalidArgument, "upload must begin with a start message")
+			}
+			if limit.MaxFiles > 0 && len(files) == limit.MaxFiles {
+				return status.Errorf(codes.ResourceExhausted, "more than %d files; %s takes at most %d", limit.MaxFiles, start.Operation, limit.MaxFiles)
+			}
+			files = append(files, &ocrv1.File{Filename: part.File.Filename})
+		case *ocrv1.UploadChunk_Data:
+			if len(files) == 0 {
+				return status.Error(codes.InvalidArgument, "data chunk before the first file message")
+			}
+			f := files[len(files)-1]
+			if limit.MaxBytes > 0 && int64(len(f.Content)+len(part.Data)) > limit.MaxBytes {
+				return status.Errorf(codes.ResourceExhausted, "%s exceeds the %d byte file size limit", f.Filename, limit.MaxBytes)
+			}
+			if total += int64(len(part.Data)); limit.MaxBytes > 0 && total > limit.MaxBody() {
+				return status.Errorf(codes.ResourceExhausted, "upload exceeds the %d byte limit of %s", limit.MaxBody(), start.Operation)
+			}
+			f.Content = append(f.Content, part.Data...)
+		}
+	}
+	if start == nil {
+		return status.Error(codes.InvalidArgument, "empty upload")
+	}
+
+	result, err := s.run(stream.Context(), start, files)
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(result)
+}
+
+// run executes one operation on its files and returns the masked JSON result.
+// Files go through the checks of REST uploads first: the limits, the malware
+// scan and the formats of the operation's route. A call with a deadline gets
+// most of the time left as its OCR budget, as a REST request does with
+// max_processing_ms, so pages not read in time are left out and the result
+// is marked "partial" rather than the call failing with DeadlineExceeded.
+func (s *Server) run(ctx context.Context, start *ocrv1.UploadStart, files []*ocrv1.File) (*ocrv1.Result, error) {
+	if len(files) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "no files provided")
+	}
+	if err := s.checkLimits(start.Operation, files); err != nil {
+		return nil, err
+	}
+	if err := s.scanFiles(ctx, files); err != nil {
+		return nil, err
+	}
+	if err := s.checkFormats(start.Operation, files); err != nil {
+		return nil, err
+	}
+	if start.Lang != "" {
+		if err := s.ValidateLang(start.Lang); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		ctx = pipeline.WithLanguage(ctx, start.Lang)
+	}
+	var budget *pipeline.OCRBudget
+	if deadline, ok := ctx.Deadline(); ok {
+		budget = pipeline.NewOCRBudget(time.Until(deadline) * 9 / 10)
+		ctx = pipeline.WithOCRBudget(ctx, budget)
+	}
+
+	var result interface{}
+	var err error
+	switch start.Operation {
+	case ocrv1.Operation_OPERATION_VERIFY_INCOME:
+		result, err = s.verifyIncome(ctx, start, files)
+	case ocrv1.Operation_OPERATION_EXTRACT_AADHAAR:
+		result, err = s.extractAadhaar(ctx, start.Password, files)
+	case ocrv1.Operation_OPERATION_EXTRACT_PAN:
+		result, err = s.PAN.ExtractPANFromBytes(ctx, files[0].Content, files[0].Filename)
+	case ocrv1.Operation_OPERATION_ANALYZE_ITR:
+		result, err = s.Income.AnalyzeITRData(ctx, files[0].Filename, files[0].Content)
+	case ocrv1.Operation_OPERATION_EXTRACT_DL:
+		result, err = s.DL.ExtractDLText(ctx, files[0].Content)
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "unknown operation %v", start.Operation)
+	}
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
+		slog.ErrorContext(ctx, "gRPC operation failed", "operation", start.Operation.String(), "error", err)
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	body, err := json.Marshal(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if body, err = s.Redactor.JSON(ctx, body, false); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if budget != nil && budget.Exhausted() {
+		body = markPartial(body)
+	}
+	return &ocrv1.Result{Json: string(body)}, nil
+}
+
+// checkLimits enforces the operation's upload limits on files, as
+// CheckUploads does for its REST route.
+func (s *Server) checkLimits(op ocrv1.Operation, files []*ocrv1.File) error {
+	limit := s.uploadLimit(op)
+	if limit.MaxFiles > 0 && len(files) > limit.MaxFiles {
+		return status.Errorf(codes.ResourceExhausted, "%d files; %s takes at most %d", len(files), op, limit.MaxFiles)
+	}
+	for _, f := range files {
+		if limit.MaxBytes > 0 && int64(len(f.Content)) > limit.MaxBytes {
+			return status.Errorf(codes.ResourceExhausted, "%s is %d bytes; %s takes files of at most %d", f.Filename, len(f.Content), op, limit.MaxBytes)
+		}
+		if limit.MaxPages > 0 {
+			if pages := handler.PDFPageCount(bytes.NewReader(f.Content)); pages > limit.MaxPages {
+				return status.Errorf(codes.ResourceExhausted, "%s has %d pages; %s takes PDFs of at most %d", f.Filename, pages, op, limit.MaxPages)
+			}
+		}
+	}
+	return nil
+}
+
+// checkFormats checks that each file is, by content, in a format the
+// operation's REST route takes, as ValidateUploads does.
+func (s *Server) checkFormats(op ocrv1.Operation, files []*ocrv1.File) error {
+	allowed := handler.RouteFormats(s.DocumentFormats, s.UploadFormats, operationRoutes[op], "")
+	if allowed == nil {
+		return nil
+	}
+	for _, f := range files {
+		format := filetype.SniffFile(bytes.NewReader(f.Content), int64(len(f.Content)), f.Filename, "")
+		if !slices.Contains(allowed, format) {
+			return status.Errorf(codes.InvalidArgument, "%s: unsupported file type; %s takes %s", f.Filename, op, filetype.Join(allowed))
+		}
+	}
+	return nil
+}
+
+// markPartial adds "partial": true to a JSON object result.
+func markPartial(body []byte) []byte {
+	rest := bytes.TrimSpace(body)
+	if !bytes.HasPrefix(rest, []byte("{")) {
+		return body
+	}
+	rest = bytes.TrimSpace(rest[1:])
+	if bytes.HasPrefix(rest, []byte("}")) {
+		return []byte(`{"partial":true}`)
+	}
+	return append([]byte(`{"partial":true,`), rest...)
+}
+
+func (s *Server) verifyIncome(ctx context.Context, start *ocrv1.UploadStart, files []*ocrv1.File) (*dto.IncomeVerificationResponse, error) {
+	if start.MetadataJson == "" {
+		return nil, status.Error(codes.InvalidArgument, "metadata is required")
+	}
+	var metadata dto.UploadMetadata
+	if err := json.Unmarshal([]byte(start.MetadataJson), &metadata); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid metadata JSON: %v", err)
+	}
+	if start.TenantId != "" {
+		metadata.TenantID = start.TenantId
+	}
+	if client, ok := auth.FromContext(ctx); ok {
+		metadata.Client = client.Name
+	}
+
+	byName := make(map[string][]byte, len(files))
+	for _, f := range files {
+		byName[f.Filename] = f.Content
+	}
+	return s.Income.VerifyDocuments(ctx, metadata, byName)
+}
+
+// extractAadhaar takes one PDF or image, or front and back images.
+func (s *Server) extractAadhaar(ctx context.Context, password string, files []*ocrv1.File) (*dto.AadhaarExtractResponse, error) {
+	var data [][]byte
+	var mimeTypes []string
+	for _, f := range files {
+		format := filetype.Sniff(f.Content)
+		if !slices.Contains(filetype.Documents, format) {
+			return nil, status.Errorf(codes.InvalidArgument, "%s: unsupported file type %s; Aadhaar takes %s", f.Filename, format, filetype.Join(filetype.Documents))
+		}
+		data = append(data, f.Content)
+		mimeTypes = append(mimeTypes, format.MimeType())
+	}
+	if len(files) > 1 {
+		return s.Aadhaar.ExtractFromImages(ctx, data, mimeTypes, password)
+	}
+	return s.Aadhaar.ExtractFromFile(ctx, data[0], mimeTypes[0], password)
+}
+
+// scanFiles checks files with the Scanner. Like the REST API, it fails the
+// call on an infected file (InvalidArgument) or a scanner failure
+// (Unavailable), unless ScanAction is scan.ActionFlag; then the outcome goes
+// out in the x-upload-scan response header.
+func (s *Server) scanFiles(ctx context.Context, files []*ocrv1.File) error {
+	if s.Scanner == nil {
+		return nil
+	}
+	flag := ""
+	for _, f := range files {
+		v, err := s.Scanner.Scan(ctx, f.Filename, f.Content)
+		switch {
+		case err != nil:
+			slog.ErrorContext(ctx, "Upload scan failed", "file", f.Filename, "error", err)
+			if s.ScanAction != scan.ActionFlag {
+				return status.Error(codes.Unavailable, "upload could not be scanned")
+			}
+			if flag == "" {
+				flag = "unscanned"
+			}
+		case v.Infected:
+			slog.WarnContext(ctx, "Malware detected in upload", "file", f.Filename, "threat", v.Threat, "action", s.ScanAction)
+			if s.ScanAction != scan.ActionFlag {
+				return status.Errorf(codes.InvalidArgument, "malware detected in %s: %s", f.Filename, v.Threat)
+			}
+			flag = "infected"
+		}
+	}
+	if flag != "" {
+		grpc.SetHeader(ctx, metadata.Pairs("x-upload-scan", flag))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package grpcserver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/handler"
+	ocrv1 "github.com/Aashish23092/ocr-income-verification/proto/ocr/v1"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+)
+
+func dial(t *testing.T, srv *Server, keys *auth.Keyring) ocrv1.OCRServiceClient {
+	lis := bufconn.Listen(1 << 20)
+	unary, stream := APIKeyInterceptors(keys)
+	s := grpc.NewServer(grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+	ocrv1.RegisterOCRServiceServer(s, srv)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return lis.Dial() }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return ocrv1.NewOCRServiceClient(conn)
+}
+
+func TestAPIKeyRequired(t *testing.T) {
+	keys := auth.NewKeyring(map[string]auth.Client{"secret": {Name: "loans"}})
+	c := dial(t, &Server{}, keys)
+
+	_, err := c.ExtractPAN(context.Background(), &ocrv1.DocumentRequest{})
+	assert.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "secret")
+	_, err = c.ExtractPAN(ctx, &ocrv1.DocumentRequest{})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err)) // no files
+
+	usage, ok := keys.Usage("loans")
+	require.True(t, ok)
+	assert.EqualValues(t, 1, usage.Requests)
+	assert.EqualValues(t, 1, usage.Errors)
+}
+
+func TestUploadStreamLimits(t *testing.T) {
+	keys := auth.NewKeyring(map[string]auth.Client{"secret": {Name: "loans"}})
+	c := dial(t, &Server{UploadLimit: handler.UploadLimit{MaxBytes: 4}}, keys)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "secret")
+
+	send := func(chunks ...*ocrv1.UploadChunk) error {
+		stream, err := c.Upload(ctx)
+		require.NoError(t, err)
+		for _, ch := range chunks {
+			if err := stream.Send(ch); err != nil {
+				break
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+
+	start := &ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_Start{Start: &ocrv1.UploadStart{Operation: ocrv1.Operation_OPERATION_EXTRACT_PAN}}}
+	file := &ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_File{File: &ocrv1.FileStart{Filename: "pan.jpg"}}}
+	data := &ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_Data{Data: []byte("abc")}}
+
+	assert.Equal(t, codes.InvalidArgument, status.Code(send()))
+	assert.Equal(t, codes.InvalidArgument, status.Code(send(file)))
+	assert.Equal(t, codes.InvalidArgument, status.Code(send(start, data)))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(send(start, file, data, data)))
+}
+
+func TestUploadsFollowRESTLimitsAndFormats(t *testing.T) {
+	keys := auth.NewKeyring(map[string]auth.Client{"secret": {Name: "loans"}})
+	c := dial(t, &Server{
+		UploadLimit:     handler.UploadLimit{MaxBytes: 8, MaxFiles: 1},
+		UploadLimits:    map[string]handler.UploadLimit{"/api/v1/income/verify": {MaxFiles: 3}},
+		DocumentFormats: filetype.Documents,
+		UploadFormats:   map[string]handler.UploadFormats{"/api/v1/income/verify": {"": {filetype.PDF}}},
+	}, keys)
+	ctx := metadata.AppendToOutgoingContext(context.Background(), apiKeyMetadata, "secret")
+
+	send := func(op ocrv1.Operation, files ...*ocrv1.File) error {
+		stream, err := c.Upload(ctx)
+		require.NoError(t, err)
+		stream.Send(&ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_Start{Start: &ocrv1.UploadStart{Operation: op}}})
+		for _, f := range files {
+			if err := stream.Send(&ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_File{File: &ocrv1.FileStart{Filename: f.Filename}}}); err != nil {
+				break
+			}
+			if err := stream.Send(&ocrv1.UploadChunk{Part: &ocrv1.UploadChunk_Data{Data: f.Content}}); err != nil {
+				break
+			}
+		}
+		_, err = stream.CloseAndRecv()
+		return err
+	}
+	pdf := func(name string) *ocrv1.File { return &ocrv1.File{Filename: name, Content: []byte("%PDF-1.4")} }
+	exe := &ocrv1.File{Filename: "pan.pdf", Content: []byte("MZ\x90\x00")}
+
+	// PAN takes one file, income verification three; 8 bytes a file, 24 in all
+	assert.Equal(t, codes.ResourceExhausted, status.Code(send(ocrv1.Operation_OPERATION_EXTRACT_PAN, pdf("a.pdf"), pdf("b.pdf"))))
+	assert.Equal(t, codes.ResourceExhausted, status.Code(send(ocrv1.Operation_OPERATION_VERIFY_INCOME, pdf("a.pdf"), pdf("b.pdf"), pdf("c.pdf"), pdf("d.pdf"))))
+	assert.Equal(t, codes.InvalidArgument, status.Code(send(ocrv1.Operation_OPERATION_VERIFY_INCOME, pdf("a.pdf"), pdf("b.pdf"), pdf("c.pdf"))), "metadata is checked once the files pass")
+
+	// the content decides the format, not the name
+	err := send(ocrv1.Operation_OPERATION_EXTRACT_PAN, exe)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	assert.Contains(t, status.Convert(err).Message(), "unsupported file type")
+	_, err = c.ExtractPAN(ctx, &ocrv1.DocumentRequest{Files: []*ocrv1.File{exe}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	// unary calls are held to the same file count
+	_, err = c.ExtractPAN(ctx, &ocrv1.DocumentRequest{Files: []*ocrv1.File{pdf("a.pdf"), pdf("b.pdf")}})
+	assert.Equal(t, codes.ResourceExhausted, status.Code(err))
+}
+
+func TestMarkPartial(t *testing.T) {
+	assert.JSONEq(t, `{"partial":true,"pan":"ABCDE1234F"}`, string(markPartial([]byte(`{"pan":"ABCDE1234F"}`))))
+	assert.JSONEq(t, `{"partial":true}`, string(markPartial([]byte(`{ }`))))
+	assert.Equal(t, `[1]`, string(markPartial([]byte(`[1]`))))
+}
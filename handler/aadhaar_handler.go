@@ -1,13 +1,12 @@
 package handler
 
 import (
-	"context"
 	"io"
-	"log"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
-	"strings"
 
+	"github.com/Aashish23092/ocr-income-verification/client"
 	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/Aashish23092/ocr-income-verification/service"
 	"github.com/gin-gonic/gin"
@@ -16,18 +15,20 @@ import (
 // AadhaarHandler handles Aadhaar extraction requests
 type AadhaarHandler struct {
 	aadhaarService *service.AadhaarService
+	webhooks       *client.WebhookClient
 }
 
 // NewAadhaarHandler creates a new AadhaarHandler instance
-func NewAadhaarHandler(aadhaarService *service.AadhaarService) *AadhaarHandler {
+func NewAadhaarHandler(aadhaarService *service.AadhaarService, webhooks *client.WebhookClient) *AadhaarHandler {
 	return &AadhaarHandler{
 		aadhaarService: aadhaarService,
+		webhooks:       webhooks,
 	}
 }
 
 // ExtractAadhaar handles the POST /aadhaar/extract endpoint
 func (h *AadhaarHandler) ExtractAadhaar(c *gin.Context) {
-	log.Println("Received Aadhaar extraction request")
+	slog.InfoContext(c.Request.Context(), "Received Aadhaar extraction request")
 
 	// Parse multipart form (must read both return values)
 	form, err := c.MultipartForm()
@@ -41,7 +42,7 @@ func (h *AadhaarHandler) ExtractAadhaar(c *gin.Context) {
 		// CASE B → single file
 		f, err := c.FormFile("file")
 		if err != nil {
-			h.sendError(c, http.StatusBadRequest, "At least one file is required", err)
+			respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "At least one file is required")
 			return
 		}
 		files = []*multipart.FileHeader{f}
@@ -49,11 +50,17 @@ func (h *AadhaarHandler) ExtractAadhaar(c *gin.Context) {
 
 	password := c.PostForm("password")
 
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
+		return
+	}
+
 	// ----------------------------------------------------
 	// CASE 1 → MULTIPLE IMAGE INPUTS
 	// ----------------------------------------------------
 	if len(files) > 1 {
-		log.Printf("Received %d Aadhaar images → Multi-page Aadhaar processing", len(files))
+		slog.InfoContext(c.Request.Context(), "Processing multi-image Aadhaar", "images", len(files))
 
 		var imagesData [][]byte
 		var mimeTypes []string
@@ -61,40 +68,30 @@ func (h *AadhaarHandler) ExtractAadhaar(c *gin.Context) {
 		for _, file := range files {
 			reader, err := file.Open()
 			if err != nil {
-				h.sendError(c, http.StatusInternalServerError, "Failed to open one of the uploaded files", err)
+				respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to open one of the uploaded files")
 				return
 			}
 			data, err := io.ReadAll(reader)
 			reader.Close()
 
 			if err != nil {
-				h.sendError(c, http.StatusInternalServerError, "Failed to read uploaded image", err)
-				return
-			}
-
-			mimeType := file.Header.Get("Content-Type")
-			if mimeType == "" {
-				mimeType = inferMimeType(file.Filename)
-			}
-
-			if !isValidMimeType(mimeType) {
-				h.sendError(c, http.StatusBadRequest, "Invalid file type. Supported: PDF, PNG, JPEG", nil)
+				respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to read uploaded image")
 				return
 			}
 
 			imagesData = append(imagesData, data)
-			mimeTypes = append(mimeTypes, mimeType)
+			mimeTypes = append(mimeTypes, file.Header.Get("Content-Type"))
 		}
 
 		// MULTI-PAGE Aadhaar extraction
-		ctx := context.Background()
-		result, err := h.aadhaarService.ExtractFromImages(ctx, imagesData, mimeTypes, password)
+		result, err := h.aadhaarService.ExtractFromImages(c.Request.Context(), imagesData, mimeTypes, password)
+		h.webhooks.Notify(callback, dto.NewWebhookEvent("aadhaar", result, err))
 		if err != nil {
-			h.sendError(c, http.StatusInternalServerError, "Failed to extract Aadhaar from multiple images", err)
+			respondServiceError(c, err, "Failed to extract Aadhaar from multiple images")
 			return
 		}
 
-		log.Println("Aadhaar extraction completed successfully (multi-image)")
+		slog.InfoContext(c.Request.Context(), "Aadhaar extraction completed", "images", len(files))
 		c.JSON(http.StatusOK, result)
 		return
 	}
@@ -103,88 +100,73 @@ func (h *AadhaarHandler) ExtractAadhaar(c *gin.Context) {
 	// CASE 2 → SINGLE FILE INPUT
 	// ----------------------------------------------------
 	file := files[0]
-	log.Printf("Processing single Aadhaar file: %s", file.Filename)
-
-	mimeType := file.Header.Get("Content-Type")
-	if mimeType == "" {
-		mimeType = inferMimeType(file.Filename)
-	}
-
-	if !isValidMimeType(mimeType) {
-		h.sendError(c, http.StatusBadRequest, "Invalid file type. Supported: PDF, PNG, JPEG", nil)
-		return
-	}
+	slog.InfoContext(c.Request.Context(), "Processing Aadhaar file", "file", file.Filename)
 
 	reader, err := file.Open()
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Failed to open uploaded file", err)
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to open uploaded file")
 		return
 	}
 	defer reader.Close()
 
 	fileData, err := io.ReadAll(reader)
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Failed to read file data", err)
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to read file data")
 		return
 	}
 
-	ctx := context.Background()
-	result, err := h.aadhaarService.ExtractFromFile(ctx, fileData, mimeType, password)
+	result, err := h.aadhaarService.ExtractFromFile(c.Request.Context(), fileData, file.Header.Get("Content-Type"), password)
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("aadhaar", result, err))
 	if err != nil {
-		if strings.Contains(err.Error(), "decrypt") {
-			h.sendError(c, http.StatusBadRequest, "Failed to decrypt PDF. Check password.", err)
-			return
-		}
-		h.sendError(c, http.StatusInternalServerError, "Failed to extract Aadhaar", err)
+		respondServiceError(c, err, "Failed to extract Aadhaar")
 		return
 	}
 
-	log.Println("Aadhaar extraction completed successfully")
+	slog.InfoContext(c.Request.Context(), "Aadhaar extraction completed")
 	c.JSON(http.StatusOK, result)
 }
 
-// sendError sends a structured error response
-func (h *AadhaarHandler) sendError(c *gin.Context, statusCode int, message string, err error) {
-	errorMsg := message
+// ExtractEKYC handles the POST /aadhaar/ekyc endpoint: an Aadhaar Paperless
+// Offline e-KYC ZIP in "file" and its share code, with the email and mobile
+// number to check against the ones registered with UIDAI.
+func (h *AadhaarHandler) ExtractEKYC(c *gin.Context) {
+	file, err := c.FormFile("file")
 	if err != nil {
-		errorMsg = err.Error()
-		log.Printf("Error: %s - %v", message, err)
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file (the offline e-KYC ZIP) is required")
+		return
+	}
+	shareCode := c.PostForm("share_code")
+	if shareCode == "" {
+		shareCode = c.PostForm(ArchivePasswordField)
+	}
+	if len(shareCode) != 4 {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "share_code must be the 4 characters chosen when downloading the e-KYC")
+		return
+	}
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
+		return
 	}
 
-	c.JSON(statusCode, dto.ErrorResponse{
-		Error:   "AADHAAR_EXTRACTION_FAILED",
-		Message: errorMsg,
-		Code:    statusCode,
-	})
-}
-
-// isValidMimeType checks if the MIME type is supported
-func isValidMimeType(mimeType string) bool {
-	validTypes := []string{
-		"application/pdf",
-		"image/png",
-		"image/jpeg",
-		"image/jpg",
+	data, err := readFormFile(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to read uploaded file")
+		return
 	}
 
-	mimeType = strings.ToLower(mimeType)
-	for _, valid := range validTypes {
-		if strings.Contains(mimeType, valid) {
-			return true
+	result, err := h.aadhaarService.ExtractFromEKYC(c.Request.Context(), data, shareCode, c.PostForm("email"), c.PostForm("mobile"))
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("aadhaar", result, err))
+	if err != nil {
+		if _, _, ok := errorStatus(err); !ok {
+			// any other error is an archive that cannot be read
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidArchive, err.Error())
+			return
 		}
+		respondServiceError(c, err, "Failed to read offline e-KYC")
+		return
 	}
-	return false
-}
 
-// inferMimeType infers MIME type from file extension
-func inferMimeType(filename string) string {
-	lower := strings.ToLower(filename)
-	if strings.HasSuffix(lower, ".pdf") {
-		return "application/pdf"
-	} else if strings.HasSuffix(lower, ".png") {
-		return "image/png"
-	} else if strings.HasSuffix(lower, ".jpg") || strings.HasSuffix(lower, ".jpeg") {
-		return "image/jpeg"
-	}
-	return ""
+	slog.InfoContext(c.Request.Context(), "Aadhaar offline e-KYC extraction completed", "signature", result.EKYCSignature)
+	c.JSON(http.StatusOK, result)
 }
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type AddressProofHandler struct {
+	service *service.AddressProofService
+}
+
+func NewAddressProofHandler(s *service.AddressProofService) *AddressProofHandler {
+	return &AddressProofHandler{service: s}
+}
+
+// ExtractAddressProof handles POST /addressproof/extract for an electricity,
+// telecom or gas bill ("file", PDF or image, and "password" for protected PDFs).
+func (h *AddressProofHandler) ExtractAddressProof(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.ExtractBill(c.Request.Context(), data, header.Filename, c.PostForm("password"))
+	if err != nil {
+		respondServiceError(c, err, "failed to extract utility bill")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
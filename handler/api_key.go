@@ -0,0 +1,72 @@
+package handler
+
+import (
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader carries the client's API key.
+const APIKeyHeader = "X-API-Key"
+
+// apiClientKey is the gin context key holding the authenticated API client.
+const apiClientKey = "api_client"
+
+// RequireAPIKey authenticates the X-API-Key header, enforces the client's rate
+// limit and accounts the request (and its outcome) to the client.
+func RequireAPIKey(keys *auth.Keyring) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, ok := keys.Authenticate(c.GetHeader(APIKeyHeader))
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+				Error:   dto.CodeUnauthorized,
+				Message: "a valid API key is required in the " + APIKeyHeader + " header",
+				Code:    http.StatusUnauthorized,
+			})
+			return
+		}
+		c.Set(apiClientKey, client)
+
+		allowed, remaining, retryAfter := keys.Allow(client)
+		if client.RatePerMinute > 0 {
+			c.Header("X-RateLimit-Limit", strconv.Itoa(client.RatePerMinute))
+			c.Header("X-RateLimit-Remaining", strconv.Itoa(remaining))
+		}
+		if !allowed {
+			slog.WarnContext(c.Request.Context(), "API client rate limited", "client", client.Name)
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, dto.ErrorResponse{
+				Error:   dto.CodeRateLimited,
+				Message: "rate limit exceeded",
+				Code:    http.StatusTooManyRequests,
+			})
+			return
+		}
+
+		c.Next()
+		keys.Record(client, c.Writer.Status())
+	}
+}
+
+// APIUsage handles GET /api/v1/usage: the calling client's own accounting.
+func APIUsage(keys *auth.Keyring) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		client, ok := c.Get(apiClientKey)
+		if !ok {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   dto.CodeNotFound,
+				Message: "API key authentication is not enabled",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		usage, _ := keys.Usage(client.(auth.Client).Name)
+		c.JSON(http.StatusOK, usage)
+	}
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ApplicantIDHeader names the applicant a document belongs to, as an
+// alternative to the applicant_id form field.
+const ApplicantIDHeader = "X-Applicant-ID"
+
+// RecordApplicantDocuments keeps the results of the extraction routes (route
+// -> document type) of requests naming an applicant, in the applicant_id
+// form field or the X-Applicant-ID header, for the applicant summary. Income
+// verifications name theirs in the metadata and are stored on their own.
+func RecordApplicantDocuments(s *service.ApplicantService, routes map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		docType, ok := routes[c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		w := &teeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if status := c.Writer.Status(); status != http.StatusOK && status != http.StatusMultiStatus {
+			return
+		}
+		// read after the handler: the form is parsed once the uploads are converted
+		applicantID := c.PostForm("applicant_id")
+		if applicantID == "" {
+			applicantID = c.GetHeader(ApplicantIDHeader)
+		}
+		if applicantID == "" {
+			return
+		}
+		ctx := c.Request.Context()
+		if !requestIDRe.MatchString(applicantID) {
+			slog.WarnContext(ctx, "Malformed applicant ID, document not recorded")
+			return
+		}
+		err := s.Record(applicantID, c.GetHeader("X-Tenant-ID"), apiClientName(c), docType, c.Writer.Header().Get(RequestIDHeader), w.body.Bytes())
+		if err != nil {
+			slog.ErrorContext(ctx, "Applicant document not recorded", "applicant_id", applicantID, "error", err)
+		}
+	}
+}
+
+type ApplicantHandler struct {
+	service *service.ApplicantService
+}
+
+func NewApplicantHandler(s *service.ApplicantService) *ApplicantHandler {
+	return &ApplicantHandler{service: s}
+}
+
+// GetSummary handles GET /applicants/:id/summary, from the documents the
+// calling API client sent for the X-Tenant-ID tenant (or for none, without
+// the header).
+func (h *ApplicantHandler) GetSummary(c *gin.Context) {
+	summary, err := h.service.Summary(c.Param("id"), c.GetHeader("X-Tenant-ID"), apiClientName(c))
+	if errors.Is(err, store.ErrNotFound) {
+		respondError(c, http.StatusNotFound, dto.CodeNotFound, "no documents processed for this applicant")
+		return
+	}
+	if err != nil {
+		respondServiceError(c, err, "failed to build applicant summary")
+		return
+	}
+	c.JSON(http.StatusOK, summary)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/Aashish23092/ocr-income-verification/utils/ziparchive"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ArchivePasswordField is the form field with the password of uploaded ZIPs
+// (for Aadhaar offline e-KYC, the share code).
+const ArchivePasswordField = "archive_password"
+
+// UnpackArchives replaces uploaded ZIP archives, encrypted or not, with the
+// PDFs and images in them, under the same form field and named by their base
+// name (so metadata refers to them as to any upload); other files in the
+// archive are ignored. XLSX workbooks, ZIPs themselves, are left as they
+// are. A field ending in "[]" takes every document of an archive, any other
+// field an archive of one document. maxSize bounds what the archives of a
+// request unpack to. Like ConvertImages it rewrites the request only when
+// there is an archive, and runs before it so phone photos inside an archive
+// are converted too. Uploads to the passThrough routes, which read archives
+// themselves, are left as they are.
+func UnpackArchives(maxSize int64, passThrough ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") || slices.Contains(passThrough, c.FullPath()) {
+			c.Next()
+			return
+		}
+		if err := c.Request.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			abortInvalidArchive(c, http.StatusBadRequest, err)
+			return
+		}
+		form := c.Request.MultipartForm
+		if form == nil || !hasArchive(form) {
+			c.Next()
+			return
+		}
+		defer form.RemoveAll()
+
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for key, values := range c.Request.PostForm {
+			for _, v := range values {
+				w.WriteField(key, v)
+			}
+		}
+		password := c.Request.PostForm.Get(ArchivePasswordField)
+		remaining := maxSize
+		for field, files := range form.File {
+			for _, fh := range files {
+				data, err := readFormFile(fh)
+				if err != nil {
+					abortInvalidArchive(c, http.StatusBadRequest, err)
+					return
+				}
+				if !isArchive(data) {
+					if err := writeFormPart(w, field, fh.Filename, fh.Header.Get("Content-Type"), data); err != nil {
+						abortInvalidArchive(c, http.StatusBadRequest, err)
+						return
+					}
+					continue
+				}
+
+				docs, status, err := archiveDocuments(data, password, remaining)
+				if err == nil && len(docs) > 1 && !strings.HasSuffix(field, "[]") {
+					status, err = http.StatusUnprocessableEntity, fmt.Errorf("%d documents, but %s takes one; send them to /api/v1/documents/batch", len(docs), field)
+				}
+				if err != nil {
+					abortInvalidArchive(c, status, fmt.Errorf("%s: %w", fh.Filename, err))
+					return
+				}
+				for _, doc := range docs {
+					remaining -= int64(len(doc.Data))
+					if err := writeFormPart(w, field, path.Base(doc.Name), http.DetectContentType(doc.Data), doc.Data); err != nil {
+						abortInvalidArchive(c, http.StatusBadRequest, err)
+						return
+					}
+				}
+			}
+		}
+		w.Close()
+
+		c.Request.Body = io.NopCloser(&body)
+		c.Request.ContentLength = int64(body.Len())
+		c.Request.Header.Set("Content-Type", w.FormDataContentType())
+		c.Request.Form, c.Request.PostForm, c.Request.MultipartForm = nil, nil, nil
+		c.Next()
+	}
+}
+
+// hasArchive reports whether any uploaded file is a ZIP archive, judged by
+// its bytes.
+func hasArchive(form *multipart.Form) bool {
+	for _, files := range form.File {
+		for _, fh := range files {
+			f, err := fh.Open()
+			if err != nil {
+				continue
+			}
+			format := filetype.SniffFile(f, fh.Size, fh.Filename, "")
+			f.Close()
+			if format == filetype.ZIP {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isArchive reports whether data is a ZIP archive; an XLSX workbook, a ZIP
+// too, is a document of its own.
+func isArchive(data []byte) bool {
+	return filetype.SniffFile(bytes.NewReader(data), int64(len(data)), "", "") == filetype.ZIP
+}
+
+// archiveDocuments unpacks the PDFs and images of an archive, and the status
+// to fail the request with when it cannot.
+func archiveDocuments(data []byte, password string, limit int64) ([]ziparchive.Entry, int, error) {
+	entries, err := ziparchive.Extract(data, password, limit)
+	switch {
+	case errors.Is(err, ziparchive.ErrPasswordRequired):
+		return nil, http.StatusBadRequest, fmt.Errorf("%w: send %s", err, ArchivePasswordField)
+	case errors.Is(err, ziparchive.ErrTooLarge):
+		return nil, http.StatusRequestEntityTooLarge, err
+	case err != nil:
+		return nil, http.StatusBadRequest, err
+	}
+	var docs []ziparchive.Entry
+	for _, e := range entries {
+		if isDocument(e.Data) {
+			docs = append(docs, e)
+		}
+	}
+	if len(docs) == 0 {
+		return nil, http.StatusUnprocessableEntity, errors.New("archive holds no PDF or image")
+	}
+	return docs, 0, nil
+}
+
+// isDocument reports whether data is a PDF or an image the OCR engines read
+// directly or after conversion.
+func isDocument(data []byte) bool {
+	return slices.Contains(filetype.Documents, filetype.Sniff(data))
+}
+
+func readFormFile(fh *multipart.FileHeader) ([]byte, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
+
+// writeFormPart writes a file part named name to w.
+func writeFormPart(w *multipart.Writer, field, name, contentType string, data []byte) error {
+	h := textproto.MIMEHeader{}
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, quoteEscaper.Replace(field), quoteEscaper.Replace(name)))
+	if contentType != "" {
+		h.Set("Content-Type", contentType)
+	}
+	part, err := w.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	_, err = part.Write(data)
+	return err
+}
+
+func abortInvalidArchive(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{
+		Error:   dto.CodeInvalidArchive,
+		Message: err.Error(),
+		Code:    status,
+	})
+}
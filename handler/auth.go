@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// reviewerKey is the gin context key holding the authenticated reviewer's name.
+const reviewerKey = "reviewer"
+
+// adminKey is the gin context key holding the authenticated admin's name.
+const adminKey = "admin"
+
+// detokenizerKey is the gin context key holding the system allowed to detokenize.
+const detokenizerKey = "detokenizer"
+
+// RequireReviewer authenticates "Authorization: Bearer <token>" against the
+// configured reviewer tokens and stores the reviewer name in the context.
+func RequireReviewer(tokens map[string]string) gin.HandlerFunc {
+	return requireBearer(tokens, reviewerKey, "a valid reviewer token is required")
+}
+
+// RequireDetokenizer authenticates systems allowed to read the originals of
+// PII tokens, the same way RequireReviewer does for reviewers.
+func RequireDetokenizer(tokens map[string]string) gin.HandlerFunc {
+	return requireBearer(tokens, detokenizerKey, "a valid detokenization token is required")
+}
+
+// RequireAdmin authenticates administrators of the service (layout
+// templates), the same way RequireReviewer does for reviewers.
+func RequireAdmin(tokens map[string]string) gin.HandlerFunc {
+	return requireBearer(tokens, adminKey, "a valid admin token is required")
+}
+
+func requireBearer(tokens map[string]string, key, denied string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+		if ok {
+			for token, name := range tokens {
+				if subtle.ConstantTimeCompare([]byte(given), []byte(token)) == 1 {
+					c.Set(key, name)
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.AbortWithStatusJSON(http.StatusUnauthorized, dto.ErrorResponse{
+			Error:   dto.CodeUnauthorized,
+			Message: denied,
+			Code:    http.StatusUnauthorized,
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+// BatchUploadLimit is the upload limit of POST /documents/batch: up to 50
+// files of 25 MB each.
+var BatchUploadLimit = UploadLimit{MaxBytes: 25 << 20, MaxPages: 500, MaxFiles: 50}
+
+type BatchHandler struct {
+	batchService *service.BatchService
+}
+
+func NewBatchHandler(batchService *service.BatchService) *BatchHandler {
+	return &BatchHandler{batchService: batchService}
+}
+
+// ProcessBatch handles POST /documents/batch: files[] plus a metadata JSON
+// giving each file's doc_type (and optional password), e.g.
+//
+//	{"documents": [{"filename": "pan.jpg", "doc_type": "pan"},
+//	               {"filename": "march.pdf", "doc_type": "salary_slip"}]}
+func (h *BatchHandler) ProcessBatch(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to parse multipart form")
+		return
+	}
+	files := form.File["files[]"]
+	if len(files) == 0 {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "no files provided")
+		return
+	}
+
+	var metadata dto.BatchMetadata
+	if err := json.Unmarshal([]byte(c.PostForm("metadata")), &metadata); err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "invalid metadata JSON")
+		return
+	}
+	metas := make(map[string]dto.DocumentMeta, len(metadata.Documents))
+	for _, m := range metadata.Documents {
+		metas[m.Filename] = m
+	}
+
+	items := make([]service.BatchItem, 0, len(files))
+	seen := map[string]bool{}
+	for _, f := range files {
+		if seen[f.Filename] {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, fmt.Sprintf("duplicate filename %s", f.Filename))
+			return
+		}
+		seen[f.Filename] = true
+
+		meta, ok := metas[f.Filename]
+		if !ok {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, fmt.Sprintf("no metadata for file %s", f.Filename))
+			return
+		}
+		if !service.SupportsBatchDocType(string(meta.DocType)) {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, fmt.Sprintf("unsupported doc_type %q for file %s", meta.DocType, f.Filename))
+			return
+		}
+		items = append(items, service.BatchItem{
+			File: f, DocType: string(meta.DocType), Password: meta.Password, Lang: meta.Lang, PasswordHints: meta.PasswordHints,
+		})
+	}
+
+	tenantID := c.GetHeader("X-Tenant-ID")
+	if tenantID == "" {
+		tenantID = metadata.TenantID
+	}
+
+	slog.InfoContext(c.Request.Context(), "Processing batch", "files", len(items), "tenant_id", tenantID)
+	c.JSON(http.StatusOK, h.batchService.Process(c.Request.Context(), items, tenantID))
+}
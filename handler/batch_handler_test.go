@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchUploadLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	def := UploadLimit{MaxBytes: 10 << 20, MaxFiles: 10}
+	routes := map[string]UploadLimit{"/documents/batch": BatchUploadLimit}
+	router := gin.New()
+	router.Use(LimitUploads(def, routes), CheckUploads(def, routes))
+	router.POST("/documents/batch", NewBatchHandler(service.NewBatchService(nil, nil, nil, nil)).ProcessBatch)
+
+	send := func(sizes []int, described int, contentLength int64) (int, dto.ErrorResponse) {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		var metadata dto.BatchMetadata
+		for i, size := range sizes {
+			name := fmt.Sprintf("slip%d.pdf", i)
+			fw, _ := mw.CreateFormFile("files[]", name)
+			fw.Write(bytes.Repeat([]byte("x"), size))
+			if i < described {
+				metadata.Documents = append(metadata.Documents, dto.DocumentMeta{Filename: name, DocType: dto.DocTypeSalarySlip})
+			}
+		}
+		raw, _ := json.Marshal(metadata)
+		mw.WriteField("metadata", string(raw))
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/documents/batch", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		if contentLength > 0 {
+			req.ContentLength = contentLength
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var resp dto.ErrorResponse
+		json.Unmarshal(w.Body.Bytes(), &resp)
+		return w.Code, resp
+	}
+	files := func(n, size int) []int {
+		sizes := make([]int, n)
+		for i := range sizes {
+			sizes[i] = size
+		}
+		return sizes
+	}
+
+	// 50 files pass the limits (over the default of 10) and reach the
+	// handler, which refuses the one without metadata
+	status, resp := send(files(50, 16), 49, 0)
+	assert.Equal(t, http.StatusBadRequest, status)
+	assert.Contains(t, resp.Message, "no metadata for file slip49.pdf")
+
+	status, resp = send(files(51, 16), 51, 0)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, dto.CodeTooManyFiles, resp.Error)
+
+	// 25 MB a file, over the default of 10
+	status, _ = send(files(1, 25<<20), 0, 0)
+	assert.Equal(t, http.StatusBadRequest, status, "a 25 MB file reaches the handler")
+	status, resp = send(files(1, 25<<20+1), 1, 0)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, dto.CodeFileTooLarge, resp.Error)
+
+	// 50 files of 25 MB in all, refused from Content-Length
+	status, resp = send(files(1, 16), 1, 50*(25<<20)+2<<20)
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, dto.CodeRequestTooLarge, resp.Error)
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CacheBypass lets a client force a fresh OCR run with "Cache-Control: no-cache"
+// (or no-store); the result is then neither served from nor written to the cache.
+func CacheBypass() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		cc := strings.ToLower(c.GetHeader("Cache-Control"))
+		if strings.Contains(cc, "no-cache") || strings.Contains(cc, "no-store") {
+			c.Request = c.Request.WithContext(pipeline.WithoutCache(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type CaptureQualityHandler struct {
+	service *service.CaptureQualityService
+}
+
+func NewCaptureQualityHandler(s *service.CaptureQualityService) *CaptureQualityHandler {
+	return &CaptureQualityHandler{service: s}
+}
+
+// CheckQuality handles POST /documents/quality ("file", a camera image): the
+// image checks only, no OCR, so a client can prompt a retake right away.
+func (h *CaptureQualityHandler) CheckQuality(c *gin.Context) {
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.Check(data)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeUnsupportedType, err.Error())
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// capturedPage is a w×h photo of a grey page inset by a margin of darker
+// background, covered in lines of dark strokes.
+func capturedPage(w, h, margin int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: 120}), image.Point{}, draw.Src)
+	doc := image.Rect(margin, margin, w-margin, h-margin)
+	draw.Draw(img, doc, image.NewUniform(color.Gray{Y: 210}), image.Point{}, draw.Src)
+	for y := doc.Min.Y + 20; y+14 < doc.Max.Y-10; y += 30 {
+		for x := doc.Min.X + 20; x+6 < doc.Max.X-20; x += 9 {
+			draw.Draw(img, image.Rect(x, y, x+3, y+14), image.NewUniform(color.Black), image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestCheckQuality(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/documents/quality", NewCaptureQualityHandler(service.NewCaptureQualityService()).CheckQuality)
+
+	send := func(field, filename string, data []byte) *httptest.ResponseRecorder {
+		var body bytes.Buffer
+		mw := multipart.NewWriter(&body)
+		fw, _ := mw.CreateFormFile(field, filename)
+		fw.Write(data)
+		mw.Close()
+		req := httptest.NewRequest(http.MethodPost, "/documents/quality", &body)
+		req.Header.Set("Content-Type", mw.FormDataContentType())
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("sharp photo is acceptable", func(t *testing.T) {
+		rec := send("file", "pan.png", encodePNG(t, capturedPage(1200, 800, 80)))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp dto.CaptureQualityResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.True(t, resp.Acceptable)
+		assert.Equal(t, 1200, resp.Width)
+		assert.Equal(t, 800, resp.Height)
+		assert.Empty(t, resp.Issues)
+		assert.NotNil(t, resp.Issues, "issues is [] rather than null")
+	})
+
+	t.Run("small blank frame calls for a retake", func(t *testing.T) {
+		frame := image.NewGray(image.Rect(0, 0, 320, 240))
+		draw.Draw(frame, frame.Bounds(), image.NewUniform(color.Gray{Y: 128}), image.Point{}, draw.Src)
+		rec := send("file", "pan.png", encodePNG(t, frame))
+		require.Equal(t, http.StatusOK, rec.Code)
+		var resp dto.CaptureQualityResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.False(t, resp.Acceptable)
+		assert.Contains(t, resp.Issues, "low_resolution")
+		assert.Contains(t, resp.Issues, "blurry")
+	})
+
+	t.Run("PDF is rejected", func(t *testing.T) {
+		rec := send("file", "pan.pdf", []byte("%PDF-1.4\n"))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, dto.CodeUnsupportedType, resp.Error)
+	})
+
+	t.Run("missing file is rejected", func(t *testing.T) {
+		rec := send("image", "pan.png", encodePNG(t, capturedPage(1200, 800, 80)))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		var resp dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		assert.Equal(t, dto.CodeFileMissing, resp.Error)
+	})
+}
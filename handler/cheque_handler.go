@@ -0,0 +1,43 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type ChequeHandler struct {
+	service *service.ChequeService
+}
+
+func NewChequeHandler(s *service.ChequeService) *ChequeHandler {
+	return &ChequeHandler{service: s}
+}
+
+// ExtractCheque handles POST /cheque/extract for a cancelled cheque ("file",
+// an image or scanned PDF, and "password" for protected PDFs).
+func (h *ChequeHandler) ExtractCheque(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.ExtractCheque(c.Request.Context(), data, header.Filename, c.PostForm("password"))
+	if err != nil {
+		respondServiceError(c, err, "failed to extract cheque")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"maps"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/vault"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxProcessingMsField is the query or form field giving a request's OCR
+// budget in milliseconds.
+const MaxProcessingMsField = "max_processing_ms"
+
+// continuation is the stored state of a continuation token: pending until
+// the full response is in.
+type continuation struct {
+	Done        bool   `json:"done"`
+	Status      int    `json:"status,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
+
+// partialFields are added to a response the OCR budget cut short.
+type partialFields struct {
+	Partial           bool   `json:"partial"`
+	ContinuationToken string `json:"continuation_token,omitempty"`
+}
+
+// ProcessingBudget gives requests with max_processing_ms (query or form
+// field) that long for OCR; pages not read by then are left out. A JSON
+// object response that was cut short gets "partial": true and a
+// "continuation_token": the route's handler runs again without a budget in
+// the background and its response, masked by redactor, is kept in store for
+// ttl for GET /continuations/:token. It is registered after the other
+// middleware, so only the handler runs again: under the request's API
+// client, on a context detached from the request, and without side effects
+// (service.WithoutSideEffects, no callback_url) as those of the partial
+// response were applied already. Continuations of a process stopped before
+// they finish stay pending until ttl.
+func ProcessingBudget(engine *gin.Engine, store cache.Cache, ttl time.Duration, redactor *vault.Redactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query(MaxProcessingMsField)
+		if raw == "" {
+			raw = c.PostForm(MaxProcessingMsField)
+		}
+		if raw == "" {
+			c.Next()
+			return
+		}
+		ms, err := strconv.Atoi(raw)
+		if err != nil || ms <= 0 {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidRequest,
+				Message: MaxProcessingMsField + " must be a positive number of milliseconds",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		budget := pipeline.NewOCRBudget(time.Duration(ms) * time.Millisecond)
+		c.Request = c.Request.WithContext(pipeline.WithOCRBudget(ctx, budget))
+
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if budget.Exhausted() && bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+			fields := partialFields{Partial: true}
+			// the uploads are removed once the request is done, so the
+			// request to run again is taken now
+			rerun, err := replayRequest(service.WithoutSideEffects(context.WithoutCancel(ctx)), c.Request)
+			if err == nil {
+				fields.ContinuationToken, err = continueInBackground(c, engine, rerun, store, "continuation:"+apiClientName(c)+":", ttl, redactor)
+			}
+			if err != nil {
+				slog.WarnContext(ctx, "Continuation not started", "error", err)
+			}
+			body = withFields(body, fields)
+		}
+		w.ResponseWriter.Write(body)
+	}
+}
+
+// Continuation handles GET /continuations/:token: 202 while the request
+// behind the token is still running, then its full response.
+func Continuation(store cache.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Param("token")
+		raw, ok, err := store.Get(c.Request.Context(), "continuation:"+apiClientName(c)+":"+token)
+		if err != nil {
+			respondError(c, http.StatusServiceUnavailable, dto.CodeServiceUnavailable, "continuation store unavailable")
+			return
+		}
+		var cont continuation
+		if !ok || json.Unmarshal(raw, &cont) != nil {
+			respondError(c, http.StatusNotFound, dto.CodeNotFound, "unknown or expired continuation token")
+			return
+		}
+		if !cont.Done {
+			c.Header("Retry-After", "2")
+			c.JSON(http.StatusAccepted, dto.ContinuationPending{ContinuationToken: token, Status: "pending"})
+			return
+		}
+		c.Data(cont.Status, cont.ContentType, cont.Body)
+	}
+}
+
+// replayRequest copies a multipart request, without max_processing_ms,
+// callback_url and Idempotency-Key, for running it again under ctx. Other
+// requests are not replayed.
+func replayRequest(ctx context.Context, r *http.Request) (*http.Request, error) {
+	form := r.MultipartForm
+	if form == nil {
+		return nil, errors.New("not a multipart request")
+	}
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for key, values := range form.Value {
+		if key == MaxProcessingMsField || key == "callback_url" {
+			continue
+		}
+		for _, v := range values {
+			w.WriteField(key, v)
+		}
+	}
+	for field, files := range form.File {
+		for _, fh := range files {
+			if err := copyFormFile(w, field, fh); err != nil {
+				return nil, err
+			}
+		}
+	}
+	w.Close()
+
+	u := *r.URL
+	query := u.Query()
+	query.Del(MaxProcessingMsField)
+	query.Del("callback_url")
+	u.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, r.Method, u.String(), &body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header = r.Header.Clone()
+	req.Header.Del(IdempotencyKeyHeader)
+	req.Header.Set("Content-Type", w.FormDataContentType())
+	req.RemoteAddr = r.RemoteAddr
+	return req, nil
+}
+
+// continueInBackground stores a pending continuation under a new token and
+// runs the handler of c again over req in the background, with c's route
+// parameters and keys (its API client among them), storing its response.
+func continueInBackground(c *gin.Context, engine *gin.Engine, req *http.Request, store cache.Cache, prefix string, ttl time.Duration, redactor *vault.Redactor) (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := hex.EncodeToString(b)
+	key := prefix + token
+
+	pending, _ := json.Marshal(continuation{})
+	if err := store.Set(req.Context(), key, pending, ttl); err != nil {
+		return "", err
+	}
+	handle := c.Handler()
+	params := slices.Clone(c.Params)
+	keys := maps.Clone(c.Keys)
+	go func() {
+		ctx := req.Context()
+		rec := &recordingWriter{header: http.Header{}, status: http.StatusOK}
+		bg := gin.CreateTestContextOnly(rec, engine)
+		bg.Request = req
+		bg.Params = params
+		bg.Keys = keys
+		handle(bg)
+		bg.Writer.WriteHeaderNow()
+
+		out := rec.body.Bytes()
+		if strings.Contains(rec.header.Get("Content-Type"), "json") {
+			masked, err := redactor.JSON(ctx, out, false)
+			if err != nil {
+				slog.WarnContext(ctx, "Continuation response not masked", "error", err)
+				return
+			}
+			out = masked
+		}
+		raw, _ := json.Marshal(continuation{
+			Done:        true,
+			Status:      rec.status,
+			ContentType: rec.header.Get("Content-Type"),
+			Body:        out,
+		})
+		if err := store.Set(ctx, key, raw, ttl); err != nil {
+			slog.WarnContext(ctx, "Continuation response not stored", "error", err)
+		}
+	}()
+	return token, nil
+}
+
+// withFields adds the fields of v, a struct, to the JSON object body.
+func withFields(body []byte, v interface{}) []byte {
+	fields, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	rest := bytes.TrimSpace(body)[1:]
+	if bytes.HasPrefix(bytes.TrimSpace(rest), []byte("}")) {
+		return fields
+	}
+	out := append(fields[:len(fields)-1:len(fields)-1], ',')
+	return append(out, rest...)
+}
+
+// recordingWriter is the http.ResponseWriter of a continued request.
+type recordingWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (w *recordingWriter) Header() http.Header { return w.header }
+
+func (w *recordingWriter) Write(b []byte) (int, error) { return w.body.Write(b) }
+
+func (w *recordingWriter) WriteHeader(status int) { w.status = status }
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessingBudgetContinuesHandlerOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	store := cache.NewLRU(10)
+
+	var mu sync.Mutex
+	var authorized, handled, stored, notified int
+	var clients []string
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		mu.Lock()
+		authorized++
+		mu.Unlock()
+		c.Set(apiClientKey, auth.Client{Name: "acme"})
+	})
+	router.Use(ProcessingBudget(router, store, time.Minute, nil))
+	// a stand-in document handler: OCR runs out of budget when there is one
+	router.POST("/verify/:kind", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		partial := false
+		if budget := pipeline.OCRBudgetFrom(ctx); budget != nil {
+			budget.Exhaust()
+			partial = true
+		}
+		file, err := c.FormFile("file")
+		require.NoError(t, err)
+
+		mu.Lock()
+		handled++
+		clients = append(clients, apiClientName(c))
+		if service.SideEffects(ctx) {
+			stored++
+			if c.PostForm("callback_url") != "" {
+				notified++
+			}
+		}
+		mu.Unlock()
+		c.JSON(http.StatusOK, gin.H{"kind": c.Param("kind"), "file": file.Filename, "partial_read": partial})
+	})
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("file", "slip.pdf")
+	require.NoError(t, err)
+	fw.Write([]byte("%PDF-1.4"))
+	mw.WriteField(MaxProcessingMsField, "50")
+	mw.WriteField("callback_url", "https://example.com/hook")
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, "/verify/slip", &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var first struct {
+		Partial           bool   `json:"partial"`
+		ContinuationToken string `json:"continuation_token"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &first))
+	assert.True(t, first.Partial)
+	require.NotEmpty(t, first.ContinuationToken)
+
+	var cont continuation
+	require.Eventually(t, func() bool {
+		raw, ok, err := store.Get(context.Background(), "continuation:acme:"+first.ContinuationToken)
+		return err == nil && ok && json.Unmarshal(raw, &cont) == nil && cont.Done
+	}, 2*time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, cont.Status)
+	assert.JSONEq(t, `{"kind":"slip","file":"slip.pdf","partial_read":false}`, string(cont.Body))
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 1, authorized)
+	assert.Equal(t, 2, handled)
+	assert.Equal(t, []string{"acme", "acme"}, clients)
+	assert.Equal(t, 1, stored)
+	assert.Equal(t, 1, notified)
+}
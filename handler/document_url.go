@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DocumentURLs lets callers link to documents instead of uploading them: a
+// "document_url" (query, urlencoded or multipart field, repeatable) is
+// downloaded as the route's main upload field, and "<field>_url" as any other
+// upload field of the route. fields maps a route (gin full path) to its upload
+// fields, main field first; routes not listed take a single "file". The
+// request is rewritten into the multipart upload it stands for, so handlers
+// and upload staging proceed exactly as for an upload.
+func DocumentURLs(fetcher *client.DocumentFetcher, fields map[string][]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		routeFields, ok := fields[c.FullPath()]
+		if !ok {
+			routeFields = []string{"file"}
+		}
+		if err := c.Request.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			abortInvalidDocumentURL(c, http.StatusBadRequest, err)
+			return
+		}
+
+		urls := map[string][]string{}
+		for i, field := range routeFields {
+			key := strings.TrimSuffix(field, "[]") + "_url"
+			urls[field] = c.Request.Form[key]
+			if i == 0 {
+				urls[field] = append(urls[field], c.Request.Form["document_url"]...)
+			}
+		}
+		if !hasURLs(urls) {
+			c.Next()
+			return
+		}
+
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for key, values := range c.Request.PostForm {
+			for _, v := range values {
+				w.WriteField(key, v)
+			}
+		}
+		if form := c.Request.MultipartForm; form != nil {
+			defer form.RemoveAll()
+			for field, files := range form.File {
+				for _, fh := range files {
+					if err := copyFormFile(w, field, fh); err != nil {
+						abortInvalidDocumentURL(c, http.StatusBadRequest, err)
+						return
+					}
+				}
+			}
+		}
+		for _, field := range routeFields {
+			for _, u := range urls[field] {
+				data, name, err := fetcher.Fetch(c.Request.Context(), u)
+				if errors.Is(err, client.ErrDocumentTooLarge) {
+					abortInvalidDocumentURL(c, http.StatusRequestEntityTooLarge, err)
+					return
+				}
+				if err != nil {
+					abortInvalidDocumentURL(c, http.StatusBadRequest, err)
+					return
+				}
+				part, _ := w.CreateFormFile(field, name)
+				part.Write(data)
+			}
+		}
+		w.Close()
+
+		c.Request.Body = io.NopCloser(&body)
+		c.Request.ContentLength = int64(body.Len())
+		c.Request.Header.Set("Content-Type", w.FormDataContentType())
+		c.Request.Form, c.Request.PostForm, c.Request.MultipartForm = nil, nil, nil
+		c.Next()
+	}
+}
+
+func hasURLs(urls map[string][]string) bool {
+	for _, u := range urls {
+		if len(u) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func copyFormFile(w *multipart.Writer, field string, fh *multipart.FileHeader) error {
+	f, err := fh.Open()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	part, err := w.CreateFormFile(field, fh.Filename)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(part, f)
+	return err
+}
+
+func abortInvalidDocumentURL(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{
+		Error:   dto.CodeInvalidDocumentURL,
+		Message: err.Error(),
+		Code:    status,
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/Aashish23092/ocr-income-verification/service"
 	"github.com/gin-gonic/gin"
 )
@@ -19,16 +20,16 @@ func NewDrivingLicenseHandler(s *service.DrivingLicenseService) *DrivingLicenseH
 func (h *DrivingLicenseHandler) ExtractDL(c *gin.Context) {
 	file, _, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file missing"})
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
 		return
 	}
 	defer file.Close()
 
 	bytes, _ := io.ReadAll(file)
 
-	result, err := h.service.ExtractDLText(bytes)
+	result, err := h.service.ExtractDLText(c.Request.Context(), bytes)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to extract DL"})
+		respondServiceError(c, err, "failed to extract DL")
 		return
 	}
 
@@ -4,6 +4,7 @@ import (
 	"io"
 	"net/http"
 
+	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/Aashish23092/ocr-income-verification/service"
 	"github.com/gin-gonic/gin"
 )
@@ -20,21 +21,21 @@ func (h *EmployeeHandler) VerifyEmployee(c *gin.Context) {
 
 	empFile, _, err := c.Request.FormFile("employee_id_card")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "employee_id_card missing"})
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "employee_id_card missing")
 		return
 	}
 	empBytes, _ := io.ReadAll(empFile)
 
 	appFile, _, err := c.Request.FormFile("appointment_letter")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "appointment_letter missing"})
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "appointment_letter missing")
 		return
 	}
 	appBytes, _ := io.ReadAll(appFile)
 
-	resp, err := h.svc.ProcessEmployeeDocs(empBytes, appBytes)
+	resp, err := h.svc.ProcessEmployeeDocs(c.Request.Context(), empBytes, appBytes)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err, "failed to process employee documents")
 		return
 	}
 
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/ekyc"
+	"github.com/Aashish23092/ocr-income-verification/utils/ziparchive"
+
+	"github.com/gin-gonic/gin"
+)
+
+// respondError answers with the error envelope and stops the handler chain.
+func respondError(c *gin.Context, status int, code dto.ErrorCode, message string) {
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{Error: code, Message: message, Code: status})
+}
+
+// errorStatus maps the errors services return for bad input or documents
+// they cannot process to their status and code; ok is false for the
+// service's own failures.
+func errorStatus(err error) (status int, code dto.ErrorCode, ok bool) {
+	var limit *service.PDFLimitError
+	switch {
+	case errors.As(err, &limit):
+		return http.StatusUnprocessableEntity, limit.Code, true
+	case errors.Is(err, service.ErrPDFEncrypted):
+		return http.StatusUnprocessableEntity, dto.CodePDFEncrypted, true
+	case errors.Is(err, service.ErrLowQuality):
+		return http.StatusUnprocessableEntity, dto.CodeLowQuality, true
+	case errors.Is(err, service.ErrOCRFailed):
+		return http.StatusUnprocessableEntity, dto.CodeOCRFailed, true
+	case errors.Is(err, service.ErrParseEmpty):
+		return http.StatusUnprocessableEntity, dto.CodeParseEmpty, true
+	case errors.Is(err, service.ErrNoDocumentFace), errors.Is(err, service.ErrUnmaskedAadhaar),
+		errors.Is(err, service.ErrEKYCSignatureInvalid), errors.Is(err, ekyc.ErrNoXML), errors.Is(err, ekyc.ErrNotEKYC):
+		return http.StatusUnprocessableEntity, dto.CodeUnprocessable, true
+	case errors.Is(err, utils.ErrNotITR):
+		return http.StatusBadRequest, dto.CodeUnsupportedType, true
+	case errors.Is(err, service.ErrInvalidSchema), errors.Is(err, service.ErrInvalidRedaction),
+		errors.Is(err, service.ErrInvalidOverride), errors.Is(err, service.ErrInvalidFeedback),
+		errors.Is(err, service.ErrInvalidTemplate):
+		return http.StatusBadRequest, dto.CodeInvalidRequest, true
+	case errors.Is(err, ziparchive.ErrPasswordRequired), errors.Is(err, ziparchive.ErrWrongPassword):
+		return http.StatusBadRequest, dto.CodeInvalidArchive, true
+	case errors.Is(err, ziparchive.ErrTooLarge), errors.Is(err, client.ErrDocumentTooLarge):
+		return http.StatusRequestEntityTooLarge, dto.CodeFileTooLarge, true
+	case errors.Is(err, store.ErrNotFound):
+		return http.StatusNotFound, dto.CodeNotFound, true
+	case errors.Is(err, client.ErrPaddleUnavailable):
+		return http.StatusServiceUnavailable, dto.CodeServiceUnavailable, true
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, dto.CodeTimeout, true
+	}
+	return 0, "", false
+}
+
+// respondServiceError answers for a failed service call: errors errorStatus
+// knows with their status, code and message, anything else with 500,
+// PROCESSING_FAILED and message, the error itself only logged.
+func respondServiceError(c *gin.Context, err error, message string) {
+	ctx := c.Request.Context()
+	if status, code, ok := errorStatus(err); ok {
+		slog.WarnContext(ctx, message, "status", status, "error", err)
+		respondError(c, status, code, err.Error())
+		return
+	}
+	slog.ErrorContext(ctx, message, "error", err)
+	respondError(c, http.StatusInternalServerError, dto.CodeProcessingFailed, message)
+}
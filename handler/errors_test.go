@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils/ekyc"
+	"github.com/Aashish23092/ocr-income-verification/utils/ziparchive"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrorStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+		code   dto.ErrorCode
+	}{
+		{&service.PDFLimitError{Code: dto.CodePDFTooManyPages, Detail: "900 pages"}, http.StatusUnprocessableEntity, dto.CodePDFTooManyPages},
+		{fmt.Errorf("could not decrypt PDF: %w", service.ErrPDFEncrypted), http.StatusUnprocessableEntity, dto.CodePDFEncrypted},
+		{fmt.Errorf("%w: %w", service.ErrOCRFailed, errors.New("engine returned no text")), http.StatusUnprocessableEntity, dto.CodeOCRFailed},
+		{fmt.Errorf("%w: blurry", service.ErrLowQuality), http.StatusUnprocessableEntity, dto.CodeLowQuality},
+		{fmt.Errorf("%w: no Aadhaar name or number", service.ErrParseEmpty), http.StatusUnprocessableEntity, dto.CodeParseEmpty},
+		{service.ErrUnmaskedAadhaar, http.StatusUnprocessableEntity, dto.CodeUnprocessable},
+		{ekyc.ErrNotEKYC, http.StatusUnprocessableEntity, dto.CodeUnprocessable},
+		{fmt.Errorf("%w: unknown field type", service.ErrInvalidSchema), http.StatusBadRequest, dto.CodeInvalidRequest},
+		{ziparchive.ErrWrongPassword, http.StatusBadRequest, dto.CodeInvalidArchive},
+		{client.ErrDocumentTooLarge, http.StatusRequestEntityTooLarge, dto.CodeFileTooLarge},
+		{store.ErrNotFound, http.StatusNotFound, dto.CodeNotFound},
+		{client.ErrPaddleUnavailable, http.StatusServiceUnavailable, dto.CodeServiceUnavailable},
+		{fmt.Errorf("OCR: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, dto.CodeTimeout},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.code), func(t *testing.T) {
+			status, code, ok := errorStatus(tt.err)
+			require.True(t, ok)
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.code, code)
+			assert.Contains(t, dto.ErrorCodes, code)
+		})
+	}
+
+	_, _, ok := errorStatus(errors.New("disk full"))
+	assert.False(t, ok)
+}
+
+func TestRespondServiceError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	respond := func(err error) dto.ErrorResponse {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(http.MethodPost, "/", nil)
+		respondServiceError(c, err, "failed to extract cheque")
+		var body dto.ErrorResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, w.Code, body.Code)
+		return body
+	}
+
+	body := respond(fmt.Errorf("%w: engine returned no text", service.ErrOCRFailed))
+	assert.Equal(t, dto.CodeOCRFailed, body.Error)
+	assert.Contains(t, body.Message, "engine returned no text")
+
+	// the service's own failures are not described to the client
+	body = respond(errors.New("open /tmp/x: disk full"))
+	assert.Equal(t, dto.ErrorResponse{Error: dto.CodeProcessingFailed, Message: "failed to extract cheque", Code: http.StatusInternalServerError}, body)
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FaceMatchHandler struct {
+	faceMatch *service.FaceMatchService
+}
+
+func NewFaceMatchHandler(faceMatch *service.FaceMatchService) *FaceMatchHandler {
+	return &FaceMatchHandler{faceMatch: faceMatch}
+}
+
+// FaceMatch handles POST /api/v1/kyc/facematch: multipart "document" (ID card
+// image or PDF), "selfie" and "doc_type" (aadhaar, pan, driving_license,
+// passport), plus "password" for protected PDFs.
+func (h *FaceMatchHandler) FaceMatch(c *gin.Context) {
+	if h.faceMatch == nil {
+		respondError(c, http.StatusServiceUnavailable, dto.CodeServiceUnavailable, "face match is not configured")
+		return
+	}
+
+	docType := c.PostForm("doc_type")
+	if !service.SupportsFaceMatchDocType(docType) {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "doc_type must be aadhaar, pan, driving_license or passport")
+		return
+	}
+
+	document, err := formFileBytes(c, "document")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "document file missing")
+		return
+	}
+	selfie, err := formFileBytes(c, "selfie")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "selfie file missing")
+		return
+	}
+
+	result, err := h.faceMatch.Match(c.Request.Context(), document, docType, c.PostForm("password"), selfie)
+	if err != nil {
+		respondServiceError(c, err, "failed to match faces")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+func formFileBytes(c *gin.Context, field string) ([]byte, error) {
+	header, err := c.FormFile(field)
+	if err != nil {
+		return nil, err
+	}
+	f, err := header.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(f)
+}
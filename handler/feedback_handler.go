@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/store"
+
+	"github.com/gin-gonic/gin"
+)
+
+// teeWriter copies the response body as it is written.
+type teeWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *teeWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *teeWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
+
+// RecordExtractions keeps the successful responses of the extraction routes
+// (route -> document type) for feedback, under the request's X-Request-ID.
+func RecordExtractions(s *service.FeedbackService, routes map[string]string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		docType, ok := routes[c.FullPath()]
+		if !ok {
+			c.Next()
+			return
+		}
+		w := &teeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if c.Writer.Status() != http.StatusOK {
+			return
+		}
+		requestID := c.Writer.Header().Get(RequestIDHeader)
+		if err := s.Record(requestID, apiClientName(c), docType, w.body.Bytes()); err != nil {
+			slog.WarnContext(c.Request.Context(), "Extraction not recorded for feedback", "error", err)
+		}
+	}
+}
+
+// apiClientName is the authenticated API client's name, empty when API keys
+// are not configured.
+func apiClientName(c *gin.Context) string {
+	if client, ok := c.Get(apiClientKey); ok {
+		return client.(auth.Client).Name
+	}
+	return ""
+}
+
+type FeedbackHandler struct {
+	service *service.FeedbackService
+}
+
+func NewFeedbackHandler(s *service.FeedbackService) *FeedbackHandler {
+	return &FeedbackHandler{service: s}
+}
+
+// SubmitFeedback handles POST /feedback: the values the integrator used for
+// the fields of an earlier extraction.
+func (h *FeedbackHandler) SubmitFeedback(c *gin.Context) {
+	var req dto.FeedbackRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "invalid feedback: "+err.Error())
+		return
+	}
+
+	result, err := h.service.Submit(apiClientName(c), req)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		respondError(c, http.StatusNotFound, dto.CodeNotFound, "no extraction recorded for request ID "+req.RequestID)
+		return
+	case err != nil:
+		respondServiceError(c, err, "failed to save feedback")
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// GetAccuracy handles GET /feedback/accuracy, optionally filtered by the
+// release and doc_type query parameters.
+func (h *FeedbackHandler) GetAccuracy(c *gin.Context) {
+	report, err := h.service.Accuracy(c.Query("release"), c.Query("doc_type"))
+	if err != nil {
+		respondServiceError(c, err, "failed to compute accuracy")
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type FieldsHandler struct {
+	service *service.FieldsService
+}
+
+func NewFieldsHandler(s *service.FieldsService) *FieldsHandler {
+	return &FieldsHandler{service: s}
+}
+
+// ExtractFields handles POST /documents/extract-fields: "file" (PDF or image,
+// "password" for protected PDFs) and "schema", a JSON dto.FieldSchema of the
+// fields to read.
+func (h *FieldsHandler) ExtractFields(c *gin.Context) {
+	var schema dto.FieldSchema
+	if err := json.Unmarshal([]byte(c.PostForm("schema")), &schema); err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "invalid schema: "+err.Error())
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.ExtractFields(c.Request.Context(), data, header.Filename, c.PostForm("password"), schema)
+	if err != nil {
+		respondServiceError(c, err, "failed to extract fields")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type GSTHandler struct {
+	service  *service.GSTService
+	webhooks *client.WebhookClient
+}
+
+func NewGSTHandler(s *service.GSTService, webhooks *client.WebhookClient) *GSTHandler {
+	return &GSTHandler{service: s, webhooks: webhooks}
+}
+
+// AnalyzeGST handles POST /gst/analyze for a GST certificate or GSTR-3B (PDF or image).
+func (h *GSTHandler) AnalyzeGST(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.Analyze(c.Request.Context(), data, header.Filename, c.PostForm("password"), c.GetHeader("X-Tenant-ID"))
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("gst", result, err))
+	if err != nil {
+		respondServiceError(c, err, "failed to analyze GST document")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type HandwritingHandler struct {
+	service *service.HandwritingService
+}
+
+func NewHandwritingHandler(s *service.HandwritingService) *HandwritingHandler {
+	return &HandwritingHandler{service: s}
+}
+
+// ExtractHandwriting handles POST /handwriting/extract (multipart: file, doc_type)
+func (h *HandwritingHandler) ExtractHandwriting(c *gin.Context) {
+	docType := c.PostForm("doc_type")
+	if !h.service.Supports(docType) {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "doc_type has no handwritten regions")
+		return
+	}
+
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.ExtractFields(c.Request.Context(), data, docType)
+	if err != nil {
+		respondServiceError(c, err, "failed to read handwritten fields")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/vault"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdempotencyKeyHeader carries the client's key for a retried request.
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// IdempotentReplayedHeader marks a response replayed for a repeated key.
+const IdempotentReplayedHeader = "Idempotent-Replayed"
+
+var idempotencyKeyRe = regexp.MustCompile(`^[\x21-\x7e]{1,255}$`)
+
+// idempotentResponse is a stored response and the route it answered.
+type idempotentResponse struct {
+	Method      string `json:"method"`
+	Route       string `json:"route"`
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// Idempotency makes POST, PUT and PATCH requests carrying an Idempotency-Key
+// header safe to retry: the response is kept for ttl per API client and key,
+// and a request repeating the key gets it back without being processed
+// again. Server errors (5xx) are not kept, so those can be retried. A repeat
+// arriving while the first request is still running gets 409; that check is
+// per process, the stored responses are shared when store is Redis.
+//
+// Responses are captured before ProtectPII sees them, so they are masked
+// with redactor before they are stored: a replay carries masked identity
+// numbers, never tokens or clear values. Responses allowed to carry PII in
+// the clear are not stored.
+func Idempotency(store cache.Cache, ttl time.Duration, redactor *vault.Redactor) gin.HandlerFunc {
+	var mu sync.Mutex
+	running := map[string]bool{}
+
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+		default:
+			key = ""
+		}
+		if key == "" {
+			c.Next()
+			return
+		}
+		if !idempotencyKeyRe.MatchString(key) {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidIdempotencyKey,
+				Message: IdempotencyKeyHeader + " must be 1 to 255 printable ASCII characters",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		storeKey := "idempotency:" + apiClientName(c) + ":" + key
+
+		mu.Lock()
+		if running[storeKey] {
+			mu.Unlock()
+			c.Header("Retry-After", "1")
+			c.AbortWithStatusJSON(http.StatusConflict, dto.ErrorResponse{
+				Error:   dto.CodeIdempotencyKeyInUse,
+				Message: "a request with this " + IdempotencyKeyHeader + " is still being processed",
+				Code:    http.StatusConflict,
+			})
+			return
+		}
+		running[storeKey] = true
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			delete(running, storeKey)
+			mu.Unlock()
+		}()
+
+		ctx := c.Request.Context()
+		raw, ok, err := store.Get(ctx, storeKey)
+		if err != nil {
+			// without the store the request is processed as if new
+			slog.WarnContext(ctx, "Idempotency store lookup failed", "error", err)
+		}
+		if ok {
+			var prev idempotentResponse
+			if err := json.Unmarshal(raw, &prev); err == nil {
+				if prev.Method != c.Request.Method || prev.Route != c.FullPath() {
+					c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+						Error:   dto.CodeIdempotencyKeyReused,
+						Message: IdempotencyKeyHeader + " was already used for " + prev.Method + " " + prev.Route,
+						Code:    http.StatusUnprocessableEntity,
+					})
+					return
+				}
+				slog.InfoContext(ctx, "Replaying idempotent response", "status", prev.Status)
+				c.Header(IdempotentReplayedHeader, "true")
+				c.Data(prev.Status, prev.ContentType, prev.Body)
+				c.Abort()
+				return
+			}
+		}
+
+		w := &teeWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		if c.Writer.Status() >= http.StatusInternalServerError || c.GetBool(piiClearKey) {
+			return
+		}
+		contentType := c.Writer.Header().Get("Content-Type")
+		body := w.body.Bytes()
+		if strings.Contains(contentType, "json") {
+			if body, err = redactor.JSON(ctx, body, false); err != nil {
+				slog.WarnContext(ctx, "Idempotent response not stored: redaction failed", "error", err)
+				return
+			}
+		}
+		raw, err = json.Marshal(idempotentResponse{
+			Method:      c.Request.Method,
+			Route:       c.FullPath(),
+			Status:      c.Writer.Status(),
+			ContentType: contentType,
+			Body:        body,
+		})
+		if err == nil {
+			err = store.Set(context.WithoutCancel(ctx), storeKey, raw, ttl)
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Idempotent response not stored", "error", err)
+		}
+	}
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/vault"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIdempotencyStoresRedactedResponse(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	redactor := vault.NewRedactor([]string{dto.PIITypeAadhaar}, nil)
+	store := cache.NewLRU(10)
+
+	calls := 0
+	router := gin.New()
+	router.Use(ProtectPII(redactor))
+	router.Use(Idempotency(store, time.Minute, redactor))
+	router.POST("/aadhaar", func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"aadhaar_number": "234567890123"})
+	})
+
+	send := func() *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/aadhaar", nil)
+		req.Header.Set(IdempotencyKeyHeader, "key-1")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+	first := send()
+	require.Equal(t, http.StatusOK, first.Code)
+	assert.NotContains(t, first.Body.String(), "234567890123")
+
+	raw, ok, err := store.Get(context.Background(), "idempotency::key-1")
+	require.NoError(t, err)
+	require.True(t, ok)
+	var stored idempotentResponse
+	require.NoError(t, json.Unmarshal(raw, &stored))
+	assert.NotContains(t, string(stored.Body), "234567890123")
+	assert.Contains(t, string(stored.Body), "0123")
+
+	replay := send()
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "true", replay.Header().Get(IdempotentReplayedHeader))
+	assert.JSONEq(t, first.Body.String(), replay.Body.String())
+}
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConvertImages replaces HEIC/HEIF and WebP uploads (the default formats of
+// many phone cameras) with PNG conversions, renamed to .png, before the
+// handler runs. Like DocumentURLs it rewrites the request only when there is
+// something to convert, so handlers never see those formats.
+func ConvertImages() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		if err := c.Request.ParseMultipartForm(32 << 20); err != nil && !errors.Is(err, http.ErrNotMultipart) {
+			abortUnsupportedImage(c, http.StatusBadRequest, err)
+			return
+		}
+		form := c.Request.MultipartForm
+		if form == nil || !needsConversion(form) {
+			c.Next()
+			return
+		}
+		defer form.RemoveAll()
+
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		for key, values := range c.Request.PostForm {
+			for _, v := range values {
+				w.WriteField(key, v)
+			}
+		}
+		for field, files := range form.File {
+			for _, fh := range files {
+				if err := convertFormFile(c, w, field, fh); err != nil {
+					abortUnsupportedImage(c, http.StatusUnsupportedMediaType, fmt.Errorf("%s: %w", fh.Filename, err))
+					return
+				}
+			}
+		}
+		w.Close()
+
+		c.Request.Body = io.NopCloser(&body)
+		c.Request.ContentLength = int64(body.Len())
+		c.Request.Header.Set("Content-Type", w.FormDataContentType())
+		c.Request.Form, c.Request.PostForm, c.Request.MultipartForm = nil, nil, nil
+		c.Next()
+	}
+}
+
+// needsConversion reports whether any uploaded file is HEIF or WebP, judged
+// by its first bytes rather than its name or declared type.
+func needsConversion(form *multipart.Form) bool {
+	for _, files := range form.File {
+		for _, fh := range files {
+			f, err := fh.Open()
+			if err != nil {
+				continue
+			}
+			head := make([]byte, 12)
+			n, _ := io.ReadFull(f, head)
+			f.Close()
+			if imageconv.Detect(head[:n]) != "" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// quoteEscaper escapes form field and file names as multipart.Writer does.
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// convertFormFile writes fh to w, as PNG if it is HEIF or WebP.
+func convertFormFile(c *gin.Context, w *multipart.Writer, field string, fh *multipart.FileHeader) error {
+	data, err := readFormFile(fh)
+	if err != nil {
+		return err
+	}
+
+	data, converted, err := imageconv.ToPNG(c.Request.Context(), data)
+	if err != nil {
+		return err
+	}
+	name, contentType := fh.Filename, fh.Header.Get("Content-Type")
+	if converted {
+		name, contentType = strings.TrimSuffix(name, filepath.Ext(name))+".png", "image/png"
+	}
+	return writeFormPart(w, field, name, contentType, data)
+}
+
+func abortUnsupportedImage(c *gin.Context, status int, err error) {
+	c.AbortWithStatusJSON(status, dto.ErrorResponse{
+		Error:   dto.CodeUnsupportedType,
+		Message: err.Error(),
+		Code:    status,
+	})
+}
@@ -1,47 +1,52 @@
 package handler
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
 
+	"github.com/Aashish23092/ocr-income-verification/client"
 	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/Aashish23092/ocr-income-verification/service"
-
+	"github.com/Aashish23092/ocr-income-verification/store"
 	"github.com/gin-gonic/gin"
 )
 
 type IncomeHandler struct {
 	incomeService *service.IncomeService
+	webhooks      *client.WebhookClient
 }
 
-func NewIncomeHandler(incomeService *service.IncomeService) *IncomeHandler {
+func NewIncomeHandler(incomeService *service.IncomeService, webhooks *client.WebhookClient) *IncomeHandler {
 	return &IncomeHandler{
 		incomeService: incomeService,
+		webhooks:      webhooks,
 	}
 }
 
 // VerifyIncome handles the POST /income/verify endpoint
 func (h *IncomeHandler) VerifyIncome(c *gin.Context) {
-	log.Println("Received income verification request")
+	slog.InfoContext(c.Request.Context(), "Received income verification request")
 
 	// Parse multipart form
 	form, err := c.MultipartForm()
 	if err != nil {
-		h.sendError(c, http.StatusBadRequest, "Failed to parse multipart form", err)
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "Failed to parse multipart form")
 		return
 	}
 
 	// Extract files
 	files := form.File["files[]"]
 	if len(files) == 0 {
-		h.sendError(c, http.StatusBadRequest, "No files provided", nil)
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "No files provided")
 		return
 	}
 
 	// Extract metadata
 	metadata := c.PostForm("metadata")
 	if metadata == "" {
-		h.sendError(c, http.StatusBadRequest, "Metadata is required", nil)
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "Metadata is required")
 		return
 	}
 
@@ -49,64 +54,207 @@ func (h *IncomeHandler) VerifyIncome(c *gin.Context) {
 	request := &dto.IncomeVerificationRequest{
 		Files:    files,
 		Metadata: metadata,
+		TenantID: c.GetHeader("X-Tenant-ID"),
+		Client:   apiClientName(c),
 	}
 
 	// Validate request
 	if err := request.Validate(); err != nil {
-		h.sendError(c, http.StatusBadRequest, err.Error(), err)
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
 		return
 	}
 
-	log.Printf("Processing %d files", len(files))
+	slog.InfoContext(c.Request.Context(), "Processing income documents", "files", len(files))
 
 	// Call service layer
-	response, err := h.incomeService.VerifyIncome(request)
+	response, err := h.incomeService.VerifyIncome(c.Request.Context(), request)
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Failed to verify income", err)
+		respondServiceError(c, err, "Failed to verify income")
 		return
 	}
 
-	// Send success response
-	log.Println("Income verification completed successfully")
-	c.JSON(http.StatusOK, response)
+	slog.InfoContext(c.Request.Context(), "Income verification completed", "status", response.Status)
+	c.JSON(verificationStatusCode(response.Status), response)
+}
+
+// verificationStatusCode maps the overall verification status to the HTTP
+// status: 200 when every document was processed, 207 when the results cover
+// only some of them, and 422 when none could be processed. The body always
+// reports each document's outcome.
+func verificationStatusCode(status string) int {
+	switch status {
+	case dto.VerificationPartial:
+		return http.StatusMultiStatus
+	case dto.VerificationFailed:
+		return http.StatusUnprocessableEntity
+	}
+	return http.StatusOK
 }
 
 // AnalyzeITR handles the POST /itr/analyze endpoint
 func (h *IncomeHandler) AnalyzeITR(c *gin.Context) {
-	log.Println("Received ITR analysis request")
+	slog.InfoContext(c.Request.Context(), "Received ITR analysis request")
 
 	// Parse file upload
 	file, err := c.FormFile("file")
 	if err != nil {
-		h.sendError(c, http.StatusBadRequest, "No file provided", err)
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "No file provided")
+		return
+	}
+
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "Invalid callback_url: "+err.Error())
 		return
 	}
 
-	log.Printf("Processing ITR file: %s (size: %d bytes)", file.Filename, file.Size)
+	slog.InfoContext(c.Request.Context(), "Processing ITR file", "file", file.Filename, "size", file.Size)
 
 	// Call service layer
-	result, err := h.incomeService.AnalyzeITR(file)
+	result, err := h.incomeService.AnalyzeITR(c.Request.Context(), file)
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("itr", result, err))
 	if err != nil {
-		h.sendError(c, http.StatusInternalServerError, "Failed to analyze ITR", err)
+		respondServiceError(c, err, "Failed to analyze ITR")
 		return
 	}
 
 	// Send success response
-	log.Println("ITR analysis completed successfully")
+	slog.InfoContext(c.Request.Context(), "ITR analysis completed")
+	c.JSON(http.StatusOK, result)
+}
+
+// AnalyzeForm16 handles the POST /form16/analyze endpoint
+func (h *IncomeHandler) AnalyzeForm16(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Received Form-16 analysis request")
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "No file provided")
+		return
+	}
+
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "Invalid callback_url: "+err.Error())
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Processing Form-16 file", "file", file.Filename, "size", file.Size)
+
+	result, err := h.incomeService.AnalyzeForm16(c.Request.Context(), file)
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("form16", result, err))
+	if err != nil {
+		respondServiceError(c, err, "Failed to analyze Form-16")
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Form-16 analysis completed")
 	c.JSON(http.StatusOK, result)
 }
 
-// sendError sends a structured error response
-func (h *IncomeHandler) sendError(c *gin.Context, statusCode int, message string, err error) {
-	errorMsg := message
+// AnalyzeForm26AS handles the POST /form26as/analyze endpoint
+func (h *IncomeHandler) AnalyzeForm26AS(c *gin.Context) {
+	slog.InfoContext(c.Request.Context(), "Received Form 26AS analysis request")
+
+	file, err := c.FormFile("file")
 	if err != nil {
-		errorMsg = err.Error()
-		log.Printf("Error: %s - %v", message, err)
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "No file provided")
+		return
 	}
 
-	c.JSON(statusCode, dto.ErrorResponse{
-		Error:   "VERIFICATION_FAILED",
-		Message: errorMsg,
-		Code:    statusCode,
-	})
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "Invalid callback_url: "+err.Error())
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Processing Form 26AS file", "file", file.Filename, "size", file.Size)
+
+	result, err := h.incomeService.AnalyzeForm26AS(c.Request.Context(), file)
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("form26as", result, err))
+	if err != nil {
+		respondServiceError(c, err, "Failed to analyze Form 26AS")
+		return
+	}
+
+	slog.InfoContext(c.Request.Context(), "Form 26AS analysis completed")
+	c.JSON(http.StatusOK, result)
+}
+
+// GetVerification handles the GET /income/verifications/:id and
+// /verifications/:id endpoints; a client reads its own verifications only.
+func (h *IncomeHandler) GetVerification(c *gin.Context) {
+	record, err := h.incomeService.GetVerification(c.Param("id"), apiClientName(c))
+	if err != nil {
+		respondServiceError(c, err, "Failed to load verification")
+		return
+	}
+	c.JSON(http.StatusOK, record)
+}
+
+// ListVerifications handles the GET /verifications endpoint: the calling
+// client's stored verifications filtered by applicant_id and the X-Tenant-ID
+// header, newest first, paged by limit and offset. Without API keys there is
+// no client to scope by, so applicant_id or X-Tenant-ID is required.
+func (h *IncomeHandler) ListVerifications(c *gin.Context) {
+	filter := store.ListFilter{Client: apiClientName(c), ApplicantID: c.Query("applicant_id"), TenantID: c.GetHeader("X-Tenant-ID")}
+	if filter.Client == "" && filter.ApplicantID == "" && filter.TenantID == "" {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "applicant_id or the X-Tenant-ID header is required")
+		return
+	}
+	var err error
+	if v := c.Query("limit"); v != "" {
+		if filter.Limit, err = strconv.Atoi(v); err != nil || filter.Limit < 1 || filter.Limit > maxListLimit {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, fmt.Sprintf("limit must be 1 to %d", maxListLimit))
+			return
+		}
+	}
+	if v := c.Query("offset"); v != "" {
+		if filter.Offset, err = strconv.Atoi(v); err != nil || filter.Offset < 0 {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "offset must be a non-negative number")
+			return
+		}
+	}
+
+	records, err := h.incomeService.ListVerifications(filter)
+	if err != nil {
+		respondServiceError(c, err, "Failed to list verifications")
+		return
+	}
+	if records == nil {
+		records = []*dto.VerificationRecord{}
+	}
+	c.JSON(http.StatusOK, dto.VerificationList{Verifications: records})
+}
+
+// maxListLimit bounds the page size of GET /verifications.
+const maxListLimit = 500
+
+// GetVerificationScore handles the GET /verifications/:id/score endpoint
+func (h *IncomeHandler) GetVerificationScore(c *gin.Context) {
+	score, err := h.incomeService.ScoreVerification(c.Param("id"), apiClientName(c))
+	if err != nil {
+		respondServiceError(c, err, "Failed to score verification")
+		return
+	}
+	c.JSON(http.StatusOK, score)
+}
+
+// OverrideFields handles the PATCH /income/verifications/:id endpoint
+func (h *IncomeHandler) OverrideFields(c *gin.Context) {
+	var req dto.FieldOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "Invalid override request: "+err.Error())
+		return
+	}
+
+	reviewer := c.GetString(reviewerKey)
+	slog.InfoContext(c.Request.Context(), "Reviewer overriding fields", "reviewer", reviewer, "fields", len(req.Overrides), "verification_id", c.Param("id"))
+
+	record, err := h.incomeService.OverrideFields(c.Param("id"), apiClientName(c), reviewer, req.Overrides)
+	if err != nil {
+		respondServiceError(c, err, "Failed to apply overrides")
+		return
+	}
+	c.JSON(http.StatusOK, record)
 }
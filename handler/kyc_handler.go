@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type KYCHandler struct {
+	kycService *service.KYCService
+}
+
+func NewKYCHandler(kycService *service.KYCService) *KYCHandler {
+	return &KYCHandler{kycService: kycService}
+}
+
+// VerifyKYC handles POST /api/v1/kyc/verify: multipart "aadhaar", "pan" and
+// "income_document" files, "income_doc_type" (salary_slip or bank_statement),
+// "aadhaar_password" / "income_password" for protected PDFs, optional
+// "driving_license" and "passport" images, and an optional "address_proof"
+// utility bill ("address_proof_password" for a protected PDF). The response status follows the
+// income verification policy (200/207/422) on how many of the documents could
+// be read.
+func (h *KYCHandler) VerifyKYC(c *gin.Context) {
+	incomeType := dto.DocumentType(c.PostForm("income_doc_type"))
+	if incomeType != dto.DocTypeSalarySlip && incomeType != dto.DocTypeBankStatement {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "income_doc_type must be salary_slip or bank_statement")
+		return
+	}
+
+	req := service.KYCRequest{IncomeType: incomeType, TenantID: c.GetHeader("X-Tenant-ID")}
+	for _, doc := range []struct {
+		field    string
+		password string
+		into     *service.KYCDocument
+	}{
+		{"aadhaar", "aadhaar_password", &req.Aadhaar},
+		{"pan", "", &req.PAN},
+		{"income_document", "income_password", &req.Income},
+	} {
+		header, err := c.FormFile(doc.field)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeFileMissing, fmt.Sprintf("%s file missing", doc.field))
+			return
+		}
+		data, err := formFileBytes(c, doc.field)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, fmt.Sprintf("failed to read %s file", doc.field))
+			return
+		}
+		*doc.into = service.KYCDocument{Filename: header.Filename, Data: data, MimeType: header.Header.Get("Content-Type")}
+		if doc.password != "" {
+			doc.into.Password = c.PostForm(doc.password)
+		}
+	}
+
+	for _, doc := range []struct {
+		field    string
+		password string
+		into     **service.KYCDocument
+	}{
+		{"driving_license", "", &req.DrivingLicense},
+		{"passport", "", &req.Passport},
+		{"address_proof", "address_proof_password", &req.AddressProof},
+	} {
+		header, err := c.FormFile(doc.field)
+		if err != nil {
+			continue // optional
+		}
+		data, err := formFileBytes(c, doc.field)
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, fmt.Sprintf("failed to read %s file", doc.field))
+			return
+		}
+		*doc.into = &service.KYCDocument{Filename: header.Filename, Data: data}
+		if doc.password != "" {
+			(*doc.into).Password = c.PostForm(doc.password)
+		}
+	}
+
+	report, err := h.kycService.Verify(c.Request.Context(), req)
+	if err != nil {
+		respondServiceError(c, err, "failed to verify KYC")
+		return
+	}
+	slog.InfoContext(c.Request.Context(), "KYC verification completed", "status", report.Status, "verdict", report.Verdict)
+	c.JSON(verificationStatusCode(report.Status), report)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LanguageHint reads the OCR language hint from the "lang" query or form field
+// (e.g. lang=hin+eng for Devanagari Aadhaar/DL cards), checks it with validate
+// and passes it down to the pipelines through the request context.
+func LanguageHint(validate func(lang string) error) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		lang := c.Query("lang")
+		if lang == "" {
+			lang = c.PostForm("lang")
+		}
+		if lang == "" {
+			c.Next()
+			return
+		}
+
+		if err := validate(lang); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidLanguage,
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		c.Request = c.Request.WithContext(pipeline.WithLanguage(c.Request.Context(), lang))
+		c.Next()
+	}
+}
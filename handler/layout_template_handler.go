@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/gin-gonic/gin"
+)
+
+type LayoutTemplateHandler struct {
+	service *service.LayoutTemplateService
+}
+
+func NewLayoutTemplateHandler(s *service.LayoutTemplateService) *LayoutTemplateHandler {
+	return &LayoutTemplateHandler{service: s}
+}
+
+// RegisterTemplate handles POST /admin/templates: a sample document ("file",
+// "password" for protected PDFs) and its annotation ("template", a JSON
+// dto.LayoutTemplateRequest).
+func (h *LayoutTemplateHandler) RegisterTemplate(c *gin.Context) {
+	var req dto.LayoutTemplateRequest
+	if err := json.Unmarshal([]byte(c.PostForm("template")), &req); err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, "invalid template: "+err.Error())
+		return
+	}
+
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "sample file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	tpl, err := h.service.Register(c.Request.Context(), data, header.Filename, c.PostForm("password"), req, c.GetString(adminKey))
+	if err != nil {
+		respondServiceError(c, err, "failed to register template")
+		return
+	}
+	c.JSON(http.StatusCreated, tpl)
+}
+
+// ListTemplates handles GET /admin/templates, optionally filtered by the
+// doc_type query parameter.
+func (h *LayoutTemplateHandler) ListTemplates(c *gin.Context) {
+	templates, err := h.service.List(c.Query("doc_type"))
+	if err != nil {
+		respondServiceError(c, err, "failed to list templates")
+		return
+	}
+	c.JSON(http.StatusOK, templates)
+}
+
+// DeleteTemplate handles DELETE /admin/templates/:id.
+func (h *LayoutTemplateHandler) DeleteTemplate(c *gin.Context) {
+	err := h.service.Delete(c.Param("id"))
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		respondError(c, http.StatusNotFound, dto.CodeNotFound, "template not found")
+		return
+	case err != nil:
+		respondServiceError(c, err, "failed to delete template")
+		return
+	}
+	c.Status(http.StatusNoContent)
+}
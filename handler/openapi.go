@@ -0,0 +1,464 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/openapi"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Form fields and parameters shared by the document endpoints
+var (
+	fileField      = openapi.Field{Name: "file", File: true, Required: true, Description: "Document (PDF, or PNG, JPEG, TIFF, HEIC or WebP image); may be replaced by document_url"}
+	passwordField  = openapi.Field{Name: "password", Description: "Password of a protected PDF"}
+	callbackField  = openapi.Field{Name: "callback_url", Description: "https URL that receives the result as a webhook"}
+	urlField       = openapi.Field{Name: "document_url", Description: "https URL (e.g. pre-signed S3/GCS) to download the document from instead of uploading it"}
+	langField      = openapi.Field{Name: "lang", Description: "OCR language hint, e.g. eng+hin"}
+	budgetField    = openapi.Field{Name: "max_processing_ms", Description: "OCR time budget; pages not read within it are left out and the response is marked partial with a continuation_token"}
+	applicantField = openapi.Field{Name: "applicant_id", Description: "Files the result under this applicant for GET /applicants/{id}/summary; or send X-Applicant-ID"}
+
+	tenantHeader = openapi.Param{Name: "X-Tenant-ID", In: "header", Description: "Selects the tenant's templates and decision rules"}
+	photoQuery   = openapi.Param{Name: "include_photo", In: "query", Description: "true to return the holder's cropped portrait as a base64 JPEG"}
+	// Aadhaar only
+	maskedImageQuery = openapi.Param{Name: "masked_image", In: "query", Description: "true to return the pages as base64 JPEGs with the first eight digits of the number blacked out"}
+)
+
+// detokenizeResponse is the body of GET /tokens/:token.
+type detokenizeResponse struct {
+	Token string `json:"token"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// healthResponse is the body of GET /health.
+type healthResponse struct {
+	Status  string      `json:"status"`
+	Service string      `json:"service"`
+	Paddle  interface{} `json:"paddle"`
+}
+
+// APIRoutes documents the REST API for the OpenAPI spec. Keep it in step
+// with the routes registered in main.
+var APIRoutes = []openapi.Route{
+	{
+		Method: http.MethodGet, Path: "/health", Tag: "service",
+		Summary:  "Service health, including the PaddleOCR circuit breaker",
+		Response: healthResponse{},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/usage", Tag: "service",
+		Summary:  "Request accounting of the calling API client",
+		Response: dto.APIUsage{},
+		Errors:   []int{http.StatusUnauthorized},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/feedback", Tag: "service",
+		Summary: "Report the values finally used for the fields of an earlier extraction",
+		Description: "`request_id` is the `X-Request-ID` of the extraction response; `fields` maps dot paths into that response " +
+			"(e.g. `name`, `salary_slips.0.net_salary`) to the correct values. Extractions are kept for a limited time " +
+			"and only as hashes, so unknown or expired request IDs give 404. Sending feedback again replaces the earlier.",
+		Body:     dto.FeedbackRequest{},
+		Response: dto.FeedbackResult{},
+		Errors:   []int{http.StatusBadRequest, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/feedback/accuracy", Tag: "service",
+		Summary: "Extraction accuracy from feedback, per release, document type and field",
+		Params: []openapi.Param{
+			{Name: "release", In: "query", Description: "Only this release"},
+			{Name: "doc_type", In: "query", Description: "Only this document type, e.g. aadhaar or income"},
+		},
+		Response: dto.AccuracyReport{},
+		Errors:   []int{http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/tokens/:token", Tag: "service",
+		Summary:     "Original value of a PII token",
+		Description: "Requires `Authorization: Bearer <detokenization token>`.",
+		Response:    detokenizeResponse{},
+		Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusBadGateway},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/continuations/:token", Tag: "service",
+		Summary: "Full response of a request answered partially within max_processing_ms",
+		Description: "202 with `status: pending` (and a Retry-After header) while the request is still being processed " +
+			"in full, then its response, with the status and body it would have had without a budget. " +
+			"Tokens are kept for a limited time per API client.",
+		Response: dto.ContinuationPending{},
+		Errors:   []int{http.StatusNotFound, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/admin/templates", Tag: "service",
+		Summary: "Register a layout template from an annotated sample",
+		Description: "Requires `Authorization: Bearer <admin token>`. For salary slip and bank statement layouts the generic parsers " +
+			"get wrong: each field of `template` names the result field it sets and its box on the sample page, as fractions " +
+			"of the page width and height. Later documents whose first page looks like the sample's (and whose text has every " +
+			"keyword) have those fields read from their boxes, and report the template's name in `template`. " +
+			"The response gives what each box reads on the sample. Results cached before registration are not re-read; " +
+			"send `Cache-Control: no-cache` to re-read a document.",
+		Form: []openapi.Field{
+			{Name: "file", File: true, Required: true, Description: "Sample document (PDF or image)"},
+			{Name: "template", JSON: dto.LayoutTemplateRequest{}, Required: true},
+			passwordField,
+		},
+		Response: dto.LayoutTemplate{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/admin/templates", Tag: "service",
+		Summary:     "Registered layout templates",
+		Description: "Requires `Authorization: Bearer <admin token>`.",
+		Params:      []openapi.Param{{Name: "doc_type", In: "query", Description: "Only this document type"}},
+		Response:    []dto.LayoutTemplate{},
+		Errors:      []int{http.StatusUnauthorized, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodDelete, Path: "/api/v1/admin/templates/:id", Tag: "service",
+		Summary:     "Delete a layout template",
+		Description: "Requires `Authorization: Bearer <admin token>`.",
+		Errors:      []int{http.StatusUnauthorized, http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/income/verify", Tag: "income",
+		Summary: "Verify income from salary slips and bank statements",
+		Description: "Each entry of `metadata.documents` describes the file of the same filename in `files[]`. " +
+			"The response reports each document's outcome in `documents`: the status is 200 when all were processed, " +
+			"207 when the results cover only some of them (`status: partial`) and 422 when none could be processed. " +
+			"Documents repeating another of the request (the same file or image, a salary slip of the same pay month, " +
+			"a statement of the same account and period) are reported as `duplicate` and left out of the results.",
+		Params: []openapi.Param{tenantHeader},
+		Form: []openapi.Field{
+			{Name: "files[]", File: true, Multiple: true, Required: true, Description: "Salary slips and bank statements; may be replaced by files[]_url or document_url"},
+			{Name: "metadata", JSON: dto.UploadMetadata{}, Required: true},
+			urlField, langField, budgetField,
+		},
+		Response: dto.IncomeVerificationResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/income/verifications/:id", Tag: "income",
+		Summary:  "Stored verification with its override audit trail",
+		Response: dto.VerificationRecord{},
+		Errors:   []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPatch, Path: "/api/v1/income/verifications/:id", Tag: "income",
+		Summary:     "Override extracted fields of a verification",
+		Description: "Requires `Authorization: Bearer <reviewer token>`.",
+		Body:        dto.FieldOverrideRequest{},
+		Response:    dto.VerificationRecord{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/verifications", Tag: "income",
+		Summary: "Stored verifications of an applicant, newest first",
+		Description: "`applicant_id` is the one given in the verification's metadata. " +
+			"Only the calling API client's verifications are listed; without an API key, `applicant_id` or `X-Tenant-ID` is required. " +
+			"Verifications are kept in Postgres when the service has a database configured, otherwise in memory until restart.",
+		Params: []openapi.Param{
+			{Name: "applicant_id", In: "query", Description: "Only this applicant's verifications"},
+			{Name: "limit", In: "query", Description: "Page size, 1 to 500 (default 50)"},
+			{Name: "offset", In: "query", Description: "Verifications to skip"},
+			{Name: "X-Tenant-ID", In: "header", Description: "Only this tenant's verifications"},
+		},
+		Response: dto.VerificationList{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/verifications/:id", Tag: "income",
+		Summary:  "Stored verification: its documents, extracted data, cross-check outcome and override audit trail",
+		Response: dto.VerificationRecord{},
+		Errors:   []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/applicants/:id/summary", Tag: "income",
+		Summary: "Identity and income profile consolidated from every document processed for an applicant",
+		Description: "Documents count towards an applicant when sent with `applicant_id` (form field, `X-Applicant-ID` header, " +
+			"or the metadata of an income verification). The latest document of each type is used; `score` averages the identity " +
+			"checks and the latest income verification's quick-screen score. Only the calling API client's documents for the " +
+			"`X-Tenant-ID` tenant count, or those sent without a tenant when the header is absent.",
+		Params:   []openapi.Param{{Name: "X-Tenant-ID", In: "header", Description: "The tenant the documents were sent for"}},
+		Response: dto.ApplicantSummary{},
+		Errors:   []int{http.StatusNotFound, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodGet, Path: "/api/v1/verifications/:id/score", Tag: "income",
+		Summary:  "Quick-screen score of a stored verification",
+		Response: dto.VerificationScore{},
+		Errors:   []int{http.StatusNotFound},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/itr/analyze", Tag: "income",
+		Summary: "Analyze an income tax return",
+		Description: "`file` is an ITR-V or acknowledgement (PDF or image), read by OCR, or the JSON/XML the e-filing utility " +
+			"prepares for upload (ITR-1 to ITR-7), read directly: its result has `source: structured` and confidence 99. " +
+			"A PDF with the computation sheet or full form, or a structured file, also gives `income_composition`: " +
+			"income by head, 80C/80D and Chapter VI-A deductions, and tax computed. " +
+			"JSON or XML that is not such a return gives 400.",
+		Form:     []openapi.Field{fileField, urlField, callbackField, langField, budgetField, applicantField},
+		Response: dto.ITRResult{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/form16/analyze", Tag: "income",
+		Summary:  "Analyze a Form-16",
+		Form:     []openapi.Field{fileField, urlField, callbackField, langField, budgetField, applicantField},
+		Response: dto.Form16Result{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/form26as/analyze", Tag: "income",
+		Summary:     "Analyze a Form 26AS or AIS",
+		Description: "TDS entries by deductor (name, TAN, section, amount, quarter) and the SFT high-value transactions reported for the PAN.",
+		Form:        []openapi.Field{fileField, urlField, callbackField, langField, budgetField, applicantField},
+		Response:    dto.Form26ASData{},
+		Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/gst/analyze", Tag: "income",
+		Summary:  "Analyze GST returns (self-employed income)",
+		Params:   []openapi.Param{tenantHeader},
+		Form:     []openapi.Field{fileField, passwordField, urlField, langField, budgetField},
+		Response: dto.GSTData{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/rent/analyze", Tag: "income",
+		Summary:  "Analyze a rent receipt or rental agreement (housing expense)",
+		Params:   []openapi.Param{tenantHeader},
+		Form:     []openapi.Field{fileField, passwordField, urlField, callbackField, langField, budgetField},
+		Response: dto.RentData{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/aadhaar/extract", Tag: "kyc",
+		Summary:     "Extract Aadhaar details",
+		Description: "One PDF or image, or the front and back images as two `file` fields. With AADHAAR_REJECT_UNMASKED=true a card printing the full number is rejected (422); masked reports whether it is masked.",
+		Params:      []openapi.Param{photoQuery, maskedImageQuery},
+		Form: []openapi.Field{
+			{Name: "file", File: true, Multiple: true, Required: true, Description: "Aadhaar PDF or image(s); may be replaced by document_url"},
+			passwordField, urlField, callbackField, langField, budgetField, applicantField,
+		},
+		Response: dto.AadhaarExtractResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/aadhaar/ekyc", Tag: "kyc",
+		Summary:     "Verify an Aadhaar Paperless Offline e-KYC",
+		Description: "The ZIP downloaded from UIDAI is decrypted with its share code and the XML's UIDAI signature checked (AADHAAR_EKYC_CERT_FILE); an invalid signature is rejected. The email and mobile number, when sent, are checked against the hashes in the XML.",
+		Params:      []openapi.Param{photoQuery},
+		Form: []openapi.Field{
+			{Name: "file", File: true, Required: true, Description: "Offline e-KYC ZIP"},
+			{Name: "share_code", Required: true, Description: "The 4-character share code the ZIP is encrypted with"},
+			{Name: "email", Description: "Email to check against the one registered with UIDAI"},
+			{Name: "mobile", Description: "Mobile number to check against the one registered with UIDAI"},
+			callbackField, applicantField,
+		},
+		Response: dto.AadhaarExtractResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusRequestEntityTooLarge, http.StatusUnprocessableEntity},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/pan/ocr", Tag: "kyc",
+		Summary:  "Extract PAN card details",
+		Params:   []openapi.Param{photoQuery},
+		Form:     []openapi.Field{fileField, urlField, langField, budgetField, applicantField},
+		Response: dto.PANResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/driving-license/ocr", Tag: "kyc",
+		Summary:  "Extract driving licence details",
+		Params:   []openapi.Param{photoQuery},
+		Form:     []openapi.Field{fileField, urlField, langField, budgetField, applicantField},
+		Response: dto.DLResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/dl/extract", Tag: "kyc",
+		Summary:     "Extract driving licence details",
+		Description: "Same as /api/v1/driving-license/ocr.",
+		Params:      []openapi.Param{photoQuery},
+		Form:        []openapi.Field{fileField, urlField, langField, budgetField, applicantField},
+		Response:    dto.DLResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/voterid/extract", Tag: "kyc",
+		Summary: "Extract voter ID (EPIC) details",
+		Params:  []openapi.Param{photoQuery},
+		Form: []openapi.Field{
+			{Name: "file", File: true, Multiple: true, Required: true, Description: "Front (and back) images; may be replaced by document_url"},
+			urlField, langField, budgetField,
+		},
+		Response: dto.VoterIDResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/passport/extract", Tag: "kyc",
+		Summary:  "Extract passport details from the MRZ",
+		Params:   []openapi.Param{photoQuery},
+		Form:     []openapi.Field{fileField, urlField, langField, budgetField, applicantField},
+		Response: dto.PassportResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/addressproof/extract", Tag: "kyc",
+		Summary:  "Extract an electricity, telecom or gas bill for address proof",
+		Form:     []openapi.Field{fileField, passwordField, urlField, langField, budgetField, applicantField},
+		Response: dto.AddressProofResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/cheque/extract", Tag: "kyc",
+		Summary:  "Extract account holder, account number, IFSC and MICR line from a cancelled cheque",
+		Form:     []openapi.Field{fileField, passwordField, urlField, langField, budgetField},
+		Response: dto.ChequeResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/kyc/facematch", Tag: "kyc",
+		Summary: "Match a selfie against the photo of an ID document",
+		Form: []openapi.Field{
+			{Name: "document", File: true, Required: true, Description: "ID card image or PDF; may be replaced by document_url"},
+			{Name: "selfie", File: true, Required: true, Description: "May be replaced by selfie_url"},
+			{Name: "doc_type", Required: true, Description: "aadhaar, pan, driving_license or passport"},
+			passwordField, urlField,
+		},
+		Response: dto.FaceMatchResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError, http.StatusServiceUnavailable},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/kyc/verify", Tag: "kyc",
+		Summary: "Cross-verify name, DOB and address across Aadhaar, PAN, an income document and optionally a driving licence, passport and utility bill",
+		Description: "The status is 200 when all documents were read, 207 when only some were (`status: partial`) " +
+			"and 422 when none could be; `verdict` gives the outcome of the checks.",
+		Params: []openapi.Param{tenantHeader},
+		Form: []openapi.Field{
+			{Name: "aadhaar", File: true, Required: true, Description: "Aadhaar PDF or image; may be replaced by document_url"},
+			{Name: "pan", File: true, Required: true, Description: "May be replaced by pan_url"},
+			{Name: "income_document", File: true, Required: true, Description: "Salary slip or bank statement; may be replaced by income_document_url"},
+			{Name: "income_doc_type", Required: true, Description: "salary_slip or bank_statement"},
+			{Name: "aadhaar_password", Description: "Password of a protected Aadhaar PDF"},
+			{Name: "income_password", Description: "Password of a protected income document PDF"},
+			{Name: "driving_license", File: true, Description: "Optional driving licence image; may be replaced by driving_license_url"},
+			{Name: "passport", File: true, Description: "Optional passport image; may be replaced by passport_url"},
+			{Name: "address_proof", File: true, Description: "Optional utility bill (address only); may be replaced by address_proof_url"},
+			{Name: "address_proof_password", Description: "Password of a protected utility bill PDF"},
+			urlField, applicantField,
+		},
+		Response: dto.KYCReport{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/documents/batch", Tag: "documents",
+		Summary:     "Process several documents of different types",
+		Description: "Each entry of `metadata.documents` gives the doc_type (and password) of the file of the same filename.",
+		Params:      []openapi.Param{tenantHeader},
+		Form: []openapi.Field{
+			{Name: "files[]", File: true, Multiple: true, Required: true, Description: "May be replaced by files[]_url or document_url"},
+			{Name: "metadata", JSON: dto.BatchMetadata{}, Required: true},
+			urlField, langField, budgetField,
+		},
+		Response: dto.BatchResponse{},
+		Errors:   []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/documents/quality", Tag: "documents",
+		Summary:     "Check a camera image for blur, glare, cropping and resolution",
+		Description: "Image checks only, no OCR: meant to run on a photo before it is submitted, so the user can retake it. A preview-sized frame (long side about 1600 px) is checked in well under 200 ms; full camera resolution mostly adds JPEG decoding time.",
+		Form:        []openapi.Field{{Name: "file", File: true, Required: true, Description: "Camera image (JPEG, PNG, HEIC or WebP)"}},
+		Response:    dto.CaptureQualityResponse{},
+		Errors:      []int{http.StatusBadRequest},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/documents/extract-fields", Tag: "documents",
+		Summary:     "Read caller-described fields from any document",
+		Description: "For document types without a parser: the schema lists each field with its label synonyms and type (string, date, amount or regex), and the value is read next to a label, to its right or on the line below. Regex fields without labels are matched anywhere in the text. The OCR reading is cached, so the same document can be asked for other fields cheaply.",
+		Form: []openapi.Field{
+			fileField,
+			{Name: "schema", JSON: dto.FieldSchema{}, Required: true},
+			passwordField, urlField, langField, budgetField,
+		},
+		Response: dto.ExtractedFields{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/documents/redact", Tag: "documents",
+		Summary: "Redact a document before archiving",
+		Description: "Every page is OCRed and the sensitive regions found are blacked out or blurred: the first eight digits of a valid Aadhaar number, " +
+			"the space above (and after a colon, beside) a signature caption, and the portrait photo. " +
+			"A PDF or multi-page upload comes back as a PDF of the redacted page images, without its text layer; a single image as a JPEG.",
+		Form: []openapi.Field{
+			fileField, passwordField,
+			{Name: "mode", Description: "blackout (default) or blur"},
+			{Name: "regions", Description: "Comma-separated: aadhaar_number, signature, photo (default all)"},
+			{Name: "doc_type", Description: "aadhaar, pan, driving_license or passport: where to look for the photo (default the whole page)"},
+		},
+		Response: dto.RedactedDocument{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/employee/verify", Tag: "documents",
+		Summary: "Verify employment from an ID card and appointment letter",
+		Description: "A QR code or barcode on the ID card (vCard, JSON, labelled text or the bare employee ID) is decoded, " +
+			"and the fields it carries are taken over the OCR text's.",
+		Form: []openapi.Field{
+			{Name: "employee_id_card", File: true, Required: true, Description: "May be replaced by employee_id_card_url or document_url"},
+			{Name: "appointment_letter", File: true, Required: true, Description: "May be replaced by appointment_letter_url"},
+		},
+		Response: dto.EmployeeVerifyResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+	{
+		Method: http.MethodPost, Path: "/api/v1/handwriting/extract", Tag: "documents",
+		Summary: "Read the handwritten fields of a document",
+		Form: []openapi.Field{
+			fileField,
+			{Name: "doc_type", Required: true, Description: "Document type with handwritten regions, e.g. cheque"},
+			urlField,
+		},
+		Response: dto.HandwritingResponse{},
+		Errors:   []int{http.StatusBadRequest, http.StatusInternalServerError},
+	},
+}
+
+// OpenAPISpec serves the OpenAPI document of routes as JSON.
+func OpenAPISpec(info openapi.Info, routes []openapi.Route) gin.HandlerFunc {
+	spec, err := json.Marshal(openapi.Build(info, routes, dto.ErrorResponse{}))
+	if err != nil {
+		panic(err) // the route table is static
+	}
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	}
+}
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at the spec.
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>OCR Income Verification API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => { window.ui = SwaggerUIBundle({url: %q, dom_id: "#swagger-ui"}); };
+  </script>
+</body>
+</html>
+`
+
+// SwaggerUI serves a Swagger UI page for the spec at specURL.
+func SwaggerUI(specURL string) gin.HandlerFunc {
+	page := []byte(fmt.Sprintf(swaggerUIPage, specURL))
+	return func(c *gin.Context) {
+		c.Data(http.StatusOK, "text/html; charset=utf-8", page)
+	}
+}
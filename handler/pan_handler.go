@@ -3,43 +3,49 @@ package handler
 import (
 	"io"
 	"net/http"
-	"os"
-	"path/filepath"
 
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/Aashish23092/ocr-income-verification/service"
 	"github.com/gin-gonic/gin"
 )
 
 type PANHandler struct {
 	PANService *service.PANService
+	webhooks   *client.WebhookClient
 }
 
-func NewPANHandler(panService *service.PANService) *PANHandler {
+func NewPANHandler(panService *service.PANService, webhooks *client.WebhookClient) *PANHandler {
 	return &PANHandler{
 		PANService: panService,
+		webhooks:   webhooks,
 	}
 }
 
 func (h *PANHandler) ExtractPAN(c *gin.Context) {
 	file, header, err := c.Request.FormFile("file")
 	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "file missing"})
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
 		return
 	}
 	defer file.Close()
 
-	tempDir := "./uploads"
-	os.MkdirAll(tempDir, 0755)
-
-	filePath := filepath.Join(tempDir, header.Filename)
-	out, _ := os.Create(filePath)
-	defer out.Close()
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
+		return
+	}
 
-	_, _ = io.Copy(out, file)
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
 
-	result, err := h.PANService.ExtractPANData(filePath)
+	result, err := h.PANService.ExtractPANFromBytes(c.Request.Context(), data, header.Filename)
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("pan", result, err))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		respondServiceError(c, err, "failed to extract PAN")
 		return
 	}
 
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type PassportHandler struct {
+	service *service.PassportService
+}
+
+func NewPassportHandler(s *service.PassportService) *PassportHandler {
+	return &PassportHandler{service: s}
+}
+
+func (h *PassportHandler) ExtractPassport(c *gin.Context) {
+	file, _, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.ExtractPassport(c.Request.Context(), data)
+	if err != nil {
+		respondServiceError(c, err, "failed to extract passport")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,63 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PhotoToggle reads include_photo=true from the query string; ID document
+// responses then carry the holder's cropped portrait as a base64 JPEG.
+func PhotoToggle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query("include_photo")
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		include, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidRequest,
+				Message: "include_photo must be true or false",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if include {
+			c.Request = c.Request.WithContext(pipeline.WithPhoto(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
+
+// MaskedImageToggle reads masked_image=true from the query string; Aadhaar
+// responses then carry the uploaded pages with the number masked.
+func MaskedImageToggle() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		raw := c.Query("masked_image")
+		if raw == "" {
+			c.Next()
+			return
+		}
+
+		masked, err := strconv.ParseBool(raw)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidRequest,
+				Message: "masked_image must be true or false",
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		if masked {
+			c.Request = c.Request.WithContext(pipeline.WithMaskedImage(c.Request.Context()))
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/vault"
+
+	"github.com/gin-gonic/gin"
+)
+
+// piiClearKey marks a response that may carry PII in the clear (detokenization).
+const piiClearKey = "pii_clear"
+
+// bufferedWriter holds the response body back until ProtectPII has redacted it.
+type bufferedWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *bufferedWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+func (w *bufferedWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ProtectPII masks Aadhaar numbers (and the other configured identity
+// numbers) in every JSON response. With tokenize_pii=true and a token store
+// configured, they are replaced by tokens that authorized systems can resolve
+// through the detokenize endpoint.
+func ProtectPII(r *vault.Redactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		w := &bufferedWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		c.Next()
+		c.Writer = w.ResponseWriter
+
+		body := w.body.Bytes()
+		if !c.GetBool(piiClearKey) && strings.Contains(w.Header().Get("Content-Type"), "json") {
+			ctx := c.Request.Context()
+			tokenize := c.Query("tokenize_pii") == "true" && r.CanTokenize()
+			protected, err := r.JSON(ctx, body, tokenize)
+			if err != nil && tokenize {
+				// the token store is unreachable: masking is still safe
+				slog.ErrorContext(ctx, "PII tokenization failed, masking instead", "error", err)
+				protected, err = r.JSON(ctx, body, false)
+			}
+			if err != nil {
+				slog.ErrorContext(ctx, "PII redaction failed", "error", err)
+				w.ResponseWriter.WriteHeader(http.StatusInternalServerError)
+				protected = []byte(`{"error":"PROCESSING_FAILED","message":"response could not be redacted","code":500}`)
+			}
+			body = protected
+		}
+		w.ResponseWriter.Write(body)
+	}
+}
+
+// Detokenize handles GET /api/v1/tokens/:token, returning the original value
+// of a PII token to an authorized system.
+func Detokenize(r *vault.Redactor) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !r.CanTokenize() {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   dto.CodeNotFound,
+				Message: "PII tokenization is not enabled",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+
+		token := c.Param("token")
+		secret, err := r.Detokenize(c.Request.Context(), token)
+		if errors.Is(err, vault.ErrNotFound) {
+			c.JSON(http.StatusNotFound, dto.ErrorResponse{
+				Error:   dto.CodeNotFound,
+				Message: "unknown token",
+				Code:    http.StatusNotFound,
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadGateway, dto.ErrorResponse{
+				Error:   dto.CodeServiceUnavailable,
+				Message: err.Error(),
+				Code:    http.StatusBadGateway,
+			})
+			return
+		}
+
+		slog.InfoContext(c.Request.Context(), "PII token detokenized", "system", c.GetString(detokenizerKey), "token", token, "type", secret.Type)
+		c.Set(piiClearKey, true)
+		c.JSON(http.StatusOK, gin.H{"token": token, "type": secret.Type, "value": secret.Value})
+	}
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RedactionHandler struct {
+	service *service.RedactionService
+}
+
+func NewRedactionHandler(s *service.RedactionService) *RedactionHandler {
+	return &RedactionHandler{service: s}
+}
+
+// Redact handles POST /documents/redact: "file" (PDF or image, "password"
+// for protected PDFs), "mode" (blackout or blur), "regions" (comma-separated,
+// default all) and "doc_type" (where to look for the photo).
+func (h *RedactionHandler) Redact(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	var regions []string
+	for _, r := range strings.Split(c.PostForm("regions"), ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			regions = append(regions, r)
+		}
+	}
+
+	result, err := h.service.Redact(c.Request.Context(), data, header.Filename, c.PostForm("password"),
+		strings.TrimSpace(c.PostForm("mode")), regions, strings.TrimSpace(c.PostForm("doc_type")))
+	if err != nil {
+		respondServiceError(c, err, "failed to redact document")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type RentHandler struct {
+	service  *service.RentService
+	webhooks *client.WebhookClient
+}
+
+func NewRentHandler(s *service.RentService, webhooks *client.WebhookClient) *RentHandler {
+	return &RentHandler{service: s, webhooks: webhooks}
+}
+
+// AnalyzeRent handles POST /rent/analyze for a rent receipt or rental agreement (PDF or image).
+func (h *RentHandler) AnalyzeRent(c *gin.Context) {
+	file, header, err := c.Request.FormFile("file")
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+	defer file.Close()
+
+	callback, err := callbackURL(c)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidRequest, err.Error())
+		return
+	}
+
+	data, err := io.ReadAll(file)
+	if err != nil {
+		respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+		return
+	}
+
+	result, err := h.service.Analyze(c.Request.Context(), data, header.Filename, c.PostForm("password"), c.GetHeader("X-Tenant-ID"))
+	h.webhooks.Notify(callback, dto.NewWebhookEvent("rent", result, err))
+	if err != nil {
+		respondServiceError(c, err, "failed to analyze rent document")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
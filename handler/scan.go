@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/scan"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadScanHeader carries the scan outcome of a request allowed through
+// with ActionFlag: "infected" or "unscanned" (the scanner failed).
+const UploadScanHeader = "X-Upload-Scan"
+
+// ScanUploads scans every file of a multipart request for malware before the
+// handler runs. With scan.ActionReject an infected file fails the request
+// with 422, and a scanner failure with 503, as nothing unscanned is parsed;
+// with scan.ActionFlag the request goes on, logged and marked in the
+// UploadScanHeader response header. It runs after UnpackArchives, so the
+// documents of encrypted archives, which a scanner cannot open, are scanned
+// too, and before ConvertImages, the first step decoding uploads.
+func ScanUploads(scanner scan.Scanner, action string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidUpload,
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		ctx := c.Request.Context()
+		for _, files := range form.File {
+			for _, fh := range files {
+				data, err := readFormFile(fh)
+				var v scan.Verdict
+				if err == nil {
+					v, err = scanner.Scan(ctx, fh.Filename, data)
+				}
+				switch {
+				case err != nil:
+					slog.ErrorContext(ctx, "Upload scan failed", "file", fh.Filename, "error", err)
+					if action != scan.ActionFlag {
+						c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+							Error:   dto.CodeServiceUnavailable,
+							Message: "upload could not be scanned",
+							Code:    http.StatusServiceUnavailable,
+						})
+						return
+					}
+					if c.Writer.Header().Get(UploadScanHeader) == "" {
+						c.Header(UploadScanHeader, "unscanned")
+					}
+				case v.Infected:
+					slog.WarnContext(ctx, "Malware detected in upload", "file", fh.Filename, "threat", v.Threat, "action", action)
+					if action != scan.ActionFlag {
+						c.AbortWithStatusJSON(http.StatusUnprocessableEntity, dto.ErrorResponse{
+							Error:   dto.CodeMalwareDetected,
+							Message: fmt.Sprintf("%s: %s", fh.Filename, v.Threat),
+							Code:    http.StatusUnprocessableEntity,
+						})
+						return
+					}
+					c.Header(UploadScanHeader, "infected")
+				}
+			}
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadFormats are the formats the documents of a route may be uploaded in,
+// by form field; the "" entry covers the route's other fields.
+type UploadFormats map[string][]filetype.Format
+
+// ValidateUploads checks each uploaded file against the formats its route
+// and field take (routes, by route path, else def), judging the file by its
+// bytes rather than its name or declared Content-Type; only a CSV, which has
+// no magic bytes, needs a .csv name or text/csv type as well. A file in
+// another format fails the request with 415 UNSUPPORTED_TYPE; an accepted
+// file's Content-Type is set to its format's, so handlers can go by it.
+func ValidateUploads(def []filetype.Format, routes map[string]UploadFormats) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		form, err := c.MultipartForm()
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, err.Error())
+			return
+		}
+
+		for field, files := range form.File {
+			allowed := RouteFormats(def, routes, c.FullPath(), field)
+			for _, fh := range files {
+				format, err := sniffFormFile(fh)
+				if err != nil {
+					respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, fmt.Sprintf("%s: %v", fh.Filename, err))
+					return
+				}
+				if !slices.Contains(allowed, format) {
+					respondError(c, http.StatusUnsupportedMediaType, dto.CodeUnsupportedType,
+						fmt.Sprintf("%s is %s; %s takes %s", fh.Filename, describeFormat(format), field, filetype.Join(allowed)))
+					return
+				}
+				fh.Header.Set("Content-Type", format.MimeType())
+			}
+		}
+		c.Next()
+	}
+}
+
+// RouteFormats are the formats field of route takes: its entry in routes,
+// else the route's "" entry, else def.
+func RouteFormats(def []filetype.Format, routes map[string]UploadFormats, route, field string) []filetype.Format {
+	if formats, ok := routes[route][field]; ok {
+		return formats
+	}
+	if formats, ok := routes[route][""]; ok {
+		return formats
+	}
+	return def
+}
+
+func sniffFormFile(fh *multipart.FileHeader) (filetype.Format, error) {
+	f, err := fh.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	return filetype.SniffFile(f, fh.Size, fh.Filename, fh.Header.Get("Content-Type")), nil
+}
+
+func describeFormat(f filetype.Format) string {
+	if f == "" {
+		return "not a supported file type"
+	}
+	return "a " + f.String() + " file"
+}
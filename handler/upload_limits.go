@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UploadLimit bounds the uploads of a route: the size of each file, the pages
+// of each PDF and the number of files. Zero fields take the default's value;
+// zero there means no limit (MaxBytes must be set).
+type UploadLimit struct {
+	MaxBytes int64
+	MaxPages int
+	MaxFiles int
+}
+
+// uploadFormOverhead is the room left in a request for form fields and
+// multipart framing beyond its files.
+const uploadFormOverhead = 1 << 20
+
+// LimitUploads caps the body of multipart requests at the route's limit
+// (routes, by route path, over def): MaxBytes per file allowed plus room for
+// the form. It only looks at Content-Length, answering 413 REQUEST_TOO_LARGE,
+// and wraps the body so reading past the cap fails; nothing is read or
+// buffered, so it is cheap enough to run before authentication and must run
+// before the first middleware reading the form. CheckUploads checks the form.
+func LimitUploads(def UploadLimit, routes map[string]UploadLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		maxBody := RouteUploadLimit(def, routes, c.FullPath()).MaxBody()
+		if c.Request.ContentLength > maxBody {
+			abortUploadLimit(c, dto.CodeRequestTooLarge, fmt.Sprintf("request of %d bytes exceeds the %d byte limit of %s", c.Request.ContentLength, maxBody, c.FullPath()))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBody)
+		c.Next()
+	}
+}
+
+// CheckUploads reads the form of multipart requests, within the cap
+// LimitUploads put on the body, and enforces the rest of the route's limits,
+// answering 413 with REQUEST_TOO_LARGE, FILE_TOO_LARGE, TOO_MANY_PAGES or
+// TOO_MANY_FILES: each file's size, each PDF's page count, the number of
+// files. Reading the form and counting pages is work for authenticated
+// clients only, so it runs after RequireAPIKey.
+func CheckUploads(def UploadLimit, routes map[string]UploadLimit) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		limit := RouteUploadLimit(def, routes, c.FullPath())
+
+		form, err := c.MultipartForm()
+		var tooLarge *http.MaxBytesError
+		switch {
+		case errors.As(err, &tooLarge):
+			abortUploadLimit(c, dto.CodeRequestTooLarge, fmt.Sprintf("request exceeds the %d byte limit of %s", tooLarge.Limit, c.FullPath()))
+			return
+		case err != nil:
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidUpload,
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+
+		files := 0
+		for _, fhs := range form.File {
+			for _, fh := range fhs {
+				files++
+				if fh.Size > limit.MaxBytes {
+					abortUploadLimit(c, dto.CodeFileTooLarge, fmt.Sprintf("%s is %d bytes; %s takes files of at most %d", fh.Filename, fh.Size, c.FullPath(), limit.MaxBytes))
+					return
+				}
+			}
+		}
+		if limit.MaxFiles > 0 && files > limit.MaxFiles {
+			abortUploadLimit(c, dto.CodeTooManyFiles, fmt.Sprintf("%d files; %s takes at most %d", files, c.FullPath(), limit.MaxFiles))
+			return
+		}
+		if limit.MaxPages > 0 {
+			for _, fhs := range form.File {
+				for _, fh := range fhs {
+					if pages := pdfPageCount(fh); pages > limit.MaxPages {
+						abortUploadLimit(c, dto.CodeTooManyPages, fmt.Sprintf("%s has %d pages; %s takes PDFs of at most %d", fh.Filename, pages, c.FullPath(), limit.MaxPages))
+						return
+					}
+				}
+			}
+		}
+		c.Next()
+	}
+}
+
+// RouteUploadLimit is the limit of route: its entry in routes over def.
+func RouteUploadLimit(def UploadLimit, routes map[string]UploadLimit, route string) UploadLimit {
+	limit := def
+	if l, ok := routes[route]; ok {
+		if l.MaxBytes > 0 {
+			limit.MaxBytes = l.MaxBytes
+		}
+		if l.MaxPages > 0 {
+			limit.MaxPages = l.MaxPages
+		}
+		if l.MaxFiles > 0 {
+			limit.MaxFiles = l.MaxFiles
+		}
+	}
+	return limit
+}
+
+// MaxBody is the largest request the limit allows: MaxBytes per file plus
+// room for the form.
+func (l UploadLimit) MaxBody() int64 {
+	if l.MaxFiles > 0 {
+		return l.MaxBytes*int64(l.MaxFiles) + uploadFormOverhead
+	}
+	return l.MaxBytes + uploadFormOverhead
+}
+
+// pdfPageCount returns the pages of an uploaded PDF, as PDFPageCount.
+func pdfPageCount(fh *multipart.FileHeader) int {
+	f, err := fh.Open()
+	if err != nil {
+		return 0
+	}
+	defer f.Close()
+	return PDFPageCount(f)
+}
+
+// PDFPageCount returns the pages of a PDF from its page tree, or 0 when r is
+// not a PDF or cannot be read without its password (the PDF processor's own
+// limits apply to it later).
+func PDFPageCount(r io.ReadSeeker) int {
+	head := make([]byte, 5)
+	if n, _ := io.ReadFull(r, head); !bytes.HasPrefix(head[:n], []byte("%PDF")) {
+		return 0
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0
+	}
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	n, err := api.PageCount(r, conf)
+	if err != nil {
+		return 0
+	}
+	return n
+}
+
+func abortUploadLimit(c *gin.Context, code dto.ErrorCode, message string) {
+	c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, dto.ErrorResponse{
+		Error:   code,
+		Message: message,
+		Code:    http.StatusRequestEntityTooLarge,
+	})
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF builds a minimal PDF with the given number of blank pages.
+func testPDF(pages int) []byte {
+	objs := []string{"<< /Type /Catalog /Pages 2 0 R >>"}
+	kids := make([]string, pages)
+	for i := range kids {
+		kids[i] = fmt.Sprintf("%d 0 R", i+3)
+	}
+	objs = append(objs, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), pages))
+	for range pages {
+		objs = append(objs, "<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] >>")
+	}
+
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objs))
+	for i, obj := range objs {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objs)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objs)+1, xref)
+	return b.Bytes()
+}
+
+// uploadLimitsRouter serves POST /upload and /statements behind LimitUploads,
+// an API key check (header X-API-Key: ok) and CheckUploads.
+func uploadLimitsRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	def := UploadLimit{MaxBytes: 1 << 10, MaxPages: 2, MaxFiles: 2}
+	routes := map[string]UploadLimit{"/statements": {MaxBytes: 4 << 10, MaxPages: 5, MaxFiles: 4}}
+
+	router := gin.New()
+	router.Use(LimitUploads(def, routes))
+	router.Use(func(c *gin.Context) {
+		if c.GetHeader("X-API-Key") != "ok" {
+			respondError(c, http.StatusUnauthorized, dto.CodeUnauthorized, "missing or invalid API key")
+			return
+		}
+	})
+	router.Use(CheckUploads(def, routes))
+	ok := func(c *gin.Context) { c.JSON(http.StatusOK, gin.H{"status": "ok"}) }
+	router.POST("/upload", ok)
+	router.POST("/statements", ok)
+	return router
+}
+
+func postFiles(router http.Handler, path, key string, files map[string][]byte) (int, dto.ErrorResponse) {
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	for name, data := range files {
+		fw, _ := mw.CreateFormFile("files[]", name)
+		fw.Write(data)
+	}
+	mw.Close()
+	req := httptest.NewRequest(http.MethodPost, path, &body)
+	req.Header.Set("Content-Type", mw.FormDataContentType())
+	req.Header.Set("X-API-Key", key)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	var resp dto.ErrorResponse
+	json.Unmarshal(w.Body.Bytes(), &resp)
+	return w.Code, resp
+}
+
+func TestUploadLimits(t *testing.T) {
+	router := uploadLimitsRouter()
+	small := []byte("%PDF-1.4 small")
+
+	tests := []struct {
+		name  string
+		path  string
+		files map[string][]byte
+		code  dto.ErrorCode
+	}{
+		{"request over the body cap", "/upload", map[string][]byte{"big.pdf": bytes.Repeat([]byte("x"), 2<<20)}, dto.CodeRequestTooLarge},
+		{"file over the size limit", "/upload", map[string][]byte{"big.pdf": bytes.Repeat([]byte("x"), 2<<10)}, dto.CodeFileTooLarge},
+		{"too many files", "/upload", map[string][]byte{"a.pdf": small, "b.pdf": small, "c.pdf": small}, dto.CodeTooManyFiles},
+		{"PDF with too many pages", "/upload", map[string][]byte{"long.pdf": testPDF(3)}, dto.CodeTooManyPages},
+		{"within the limits", "/upload", map[string][]byte{"a.pdf": testPDF(2), "b.pdf": small}, ""},
+		// the route's own limits replace the default ones
+		{"route override: larger files", "/statements", map[string][]byte{"big.pdf": bytes.Repeat([]byte("x"), 2<<10)}, ""},
+		{"route override: more files", "/statements", map[string][]byte{"a.pdf": small, "b.pdf": small, "c.pdf": small}, ""},
+		{"route override: more pages", "/statements", map[string][]byte{"long.pdf": testPDF(3)}, ""},
+		{"route override: its page limit", "/statements", map[string][]byte{"long.pdf": testPDF(6)}, dto.CodeTooManyPages},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := postFiles(router, tt.path, "ok", tt.files)
+			if tt.code == "" {
+				assert.Equal(t, http.StatusOK, status)
+				return
+			}
+			assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+			assert.Equal(t, tt.code, resp.Error)
+		})
+	}
+}
+
+func TestUploadLimitsCheckFormAfterAuthentication(t *testing.T) {
+	router := uploadLimitsRouter()
+
+	// the body cap holds for everyone
+	status, resp := postFiles(router, "/upload", "", map[string][]byte{"big.pdf": bytes.Repeat([]byte("x"), 2<<20)})
+	assert.Equal(t, http.StatusRequestEntityTooLarge, status)
+	assert.Equal(t, dto.CodeRequestTooLarge, resp.Error)
+
+	// the form of an unauthenticated request is never read
+	status, resp = postFiles(router, "/upload", "", map[string][]byte{"long.pdf": testPDF(3)})
+	assert.Equal(t, http.StatusUnauthorized, status)
+	require.Equal(t, dto.CodeUnauthorized, resp.Error)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StageUploads puts every file of a multipart request into the upload
+// storage before the handler runs. When the request completes the staged
+// copies are deleted, unless retain is set (storage.Janitor then removes
+// them once they expire), and the multipart spool files are removed either
+// way, so no handler leaves documents on disk.
+func StageUploads(s storage.Storage, retain bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.HasPrefix(c.ContentType(), "multipart/") {
+			c.Next()
+			return
+		}
+		form, err := c.MultipartForm()
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, dto.ErrorResponse{
+				Error:   dto.CodeInvalidUpload,
+				Message: err.Error(),
+				Code:    http.StatusBadRequest,
+			})
+			return
+		}
+		defer form.RemoveAll()
+
+		var keys []string
+		defer func() {
+			if retain {
+				return
+			}
+			// The request context may be cancelled already
+			ctx := context.WithoutCancel(c.Request.Context())
+			for _, key := range keys {
+				if err := s.Delete(ctx, key); err != nil {
+					slog.WarnContext(ctx, "Failed to delete staged upload", "key", key, "error", err)
+				}
+			}
+		}()
+
+		for _, files := range form.File {
+			for _, fh := range files {
+				f, err := fh.Open()
+				if err != nil {
+					continue
+				}
+				data, err := io.ReadAll(f)
+				f.Close()
+				if err != nil {
+					continue
+				}
+				key, err := s.Put(c.Request.Context(), fh.Filename, data)
+				if err != nil {
+					slog.ErrorContext(c.Request.Context(), "Failed to stage upload", "file", fh.Filename, "error", err)
+					c.AbortWithStatusJSON(http.StatusServiceUnavailable, dto.ErrorResponse{
+						Error:   dto.CodeServiceUnavailable,
+						Message: "upload could not be stored",
+						Code:    http.StatusServiceUnavailable,
+					})
+					return
+				}
+				keys = append(keys, key)
+			}
+		}
+		c.Next()
+	}
+}
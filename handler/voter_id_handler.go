@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/gin-gonic/gin"
+)
+
+type VoterIDHandler struct {
+	service *service.VoterIDService
+}
+
+func NewVoterIDHandler(s *service.VoterIDService) *VoterIDHandler {
+	return &VoterIDHandler{service: s}
+}
+
+// ExtractVoterID handles POST /voterid/extract. The front of the card is sent
+// as "file"; repeat the field to include the back (address side).
+func (h *VoterIDHandler) ExtractVoterID(c *gin.Context) {
+	form, err := c.MultipartForm()
+	if err != nil || len(form.File["file"]) == 0 {
+		respondError(c, http.StatusBadRequest, dto.CodeFileMissing, "file missing")
+		return
+	}
+
+	var images [][]byte
+	for _, fh := range form.File["file"] {
+		f, err := fh.Open()
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to open file")
+			return
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			respondError(c, http.StatusBadRequest, dto.CodeInvalidUpload, "failed to read file")
+			return
+		}
+		images = append(images, data)
+	}
+
+	result, err := h.service.ExtractVoterID(c.Request.Context(), images)
+	if err != nil {
+		respondServiceError(c, err, "failed to extract voter ID")
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
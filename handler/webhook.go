@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/Aashish23092/ocr-income-verification/client"
+
+	"github.com/gin-gonic/gin"
+)
+
+// callbackURL reads the optional callback_url form field and validates it.
+func callbackURL(c *gin.Context) (string, error) {
+	u := c.PostForm("callback_url")
+	if u == "" {
+		return "", nil
+	}
+	return u, client.ValidateCallbackURL(u)
+}
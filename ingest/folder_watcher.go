@@ -0,0 +1,372 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/scan"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+)
+
+// File suffixes used in the watched directory.
+//
+// A producer drops the documents first and the manifest last:
+//
+//	batch42.manifest.json     {"documents": [{"filename": "slip1.pdf", "doc_type": "salary_slip"}, ...]}
+//
+// The watcher claims the manifest by renaming it to *.processing, then writes
+// batch42.result.json (or moves everything to quarantine/ on repeated failure).
+// The claim is a lease: its holder touches it while processing, and a claim
+// not touched for claimLease, left by a crashed instance, is re-queued.
+const (
+	manifestSuffix   = ".manifest.json"
+	processingSuffix = ".manifest.processing"
+	doneSuffix       = ".manifest.done"
+	resultSuffix     = ".result.json"
+	quarantineDir    = "quarantine"
+)
+
+// claimLease is how long a claim stays held without being touched.
+const claimLease = 2 * time.Minute
+
+// FolderWatcher polls a directory for manifests and runs them through IncomeService.
+// Only as many manifests are claimed as there are free workers, so a backlog stays
+// on disk (visible to other instances) instead of piling up in memory. Documents
+// go through the checks of uploads to the REST API first: the malware scan and
+// the formats income verification takes.
+type FolderWatcher struct {
+	dir         string
+	interval    time.Duration
+	workers     int
+	maxAttempts int
+	lease       time.Duration
+	income      *service.IncomeService
+	scanner     scan.Scanner
+	scanAction  string
+	formats     []filetype.Format
+
+	mu       sync.Mutex
+	attempts map[string]int
+}
+
+// NewFolderWatcher creates a FolderWatcher for dir. Documents are scanned
+// with scanner, when set, infected ones handled by scanAction (scan.ActionReject
+// or scan.ActionFlag) as for uploads, and must be in one of formats.
+func NewFolderWatcher(dir string, interval time.Duration, workers, maxAttempts int, income *service.IncomeService, scanner scan.Scanner, scanAction string, formats []filetype.Format) *FolderWatcher {
+	if workers < 1 {
+		workers = 1
+	}
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+	return &FolderWatcher{
+		dir:         dir,
+		interval:    interval,
+		workers:     workers,
+		maxAttempts: maxAttempts,
+		lease:       claimLease,
+		income:      income,
+		scanner:     scanner,
+		scanAction:  scanAction,
+		formats:     formats,
+		attempts:    make(map[string]int),
+	}
+}
+
+// Run polls until ctx is cancelled, then waits for in-flight manifests to finish.
+func (w *FolderWatcher) Run(ctx context.Context) error {
+	if err := os.MkdirAll(filepath.Join(w.dir, quarantineDir), 0755); err != nil {
+		return fmt.Errorf("failed to prepare watch dir: %w", err)
+	}
+
+	slots := make(chan struct{}, w.workers)
+	var wg sync.WaitGroup
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	slog.Info("Folder watcher started", "dir", w.dir, "workers", w.workers)
+	for {
+		// Manifests left in *.processing by a crashed instance are retried
+		w.recoverStale()
+
+	claimLoop:
+		for _, manifest := range w.pendingManifests() {
+			select {
+			case slots <- struct{}{}:
+			default:
+				// All workers busy: leave the rest on disk for the next tick
+				break claimLoop
+			}
+
+			claimed, ok := w.claim(manifest)
+			if !ok {
+				<-slots
+				continue
+			}
+
+			wg.Add(1)
+			go func(path string) {
+				defer wg.Done()
+				defer func() { <-slots }()
+				w.process(path)
+			}(claimed)
+		}
+
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			slog.Info("Folder watcher stopped")
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *FolderWatcher) pendingManifests() []string {
+	matches, err := filepath.Glob(filepath.Join(w.dir, "*"+manifestSuffix))
+	if err != nil {
+		slog.Error("Folder watcher: glob failed", "error", err)
+		return nil
+	}
+	sort.Strings(matches)
+	return matches
+}
+
+// claim locks a manifest by renaming it; rename is atomic, so only one
+// watcher instance sharing the directory wins. The rename keeps the
+// manifest's modification time, so the lease starts with a touch.
+func (w *FolderWatcher) claim(manifest string) (string, bool) {
+	claimed := strings.TrimSuffix(manifest, manifestSuffix) + processingSuffix
+	if err := os.Rename(manifest, claimed); err != nil {
+		return "", false
+	}
+	touch(claimed)
+	return claimed, true
+}
+
+// recoverStale re-queues claims whose lease ran out; claims other instances
+// are still touching are left alone.
+func (w *FolderWatcher) recoverStale() {
+	matches, _ := filepath.Glob(filepath.Join(w.dir, "*"+processingSuffix))
+	for _, m := range matches {
+		info, err := os.Stat(m)
+		if err != nil || time.Since(info.ModTime()) < w.lease {
+			continue
+		}
+		orig := strings.TrimSuffix(m, processingSuffix) + manifestSuffix
+		if err := os.Rename(m, orig); err == nil {
+			slog.Info("Folder watcher: re-queued interrupted manifest", "manifest", filepath.Base(orig))
+		}
+	}
+}
+
+// holdClaim touches a claim every third of the lease until done is closed.
+func (w *FolderWatcher) holdClaim(claimed string, done <-chan struct{}) {
+	ticker := time.NewTicker(w.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			touch(claimed)
+		}
+	}
+}
+
+func touch(path string) {
+	now := time.Now()
+	os.Chtimes(path, now, now)
+}
+
+// process runs one claimed manifest. Failures are retried on later ticks until
+// maxAttempts, after which the manifest and its documents are quarantined.
+func (w *FolderWatcher) process(claimed string) {
+	base := strings.TrimSuffix(claimed, processingSuffix)
+	held := make(chan struct{})
+	go w.holdClaim(claimed, held)
+	defer close(held)
+
+	var metadata dto.UploadMetadata
+	var procErr error
+	func() {
+		// A document that crashes a parser must not take the watcher down
+		defer func() {
+			if r := recover(); r != nil {
+				procErr = fmt.Errorf("panic: %v", r)
+				w.recordAttempt(base, w.maxAttempts)
+			}
+		}()
+
+		raw, err := os.ReadFile(claimed)
+		if err != nil {
+			procErr = err
+			return
+		}
+		if err := json.Unmarshal(raw, &metadata); err != nil {
+			// Malformed manifests never succeed; quarantine immediately
+			procErr = fmt.Errorf("invalid manifest: %w", err)
+			w.recordAttempt(base, w.maxAttempts)
+			return
+		}
+
+		files, err := w.readDocuments(metadata)
+		if err != nil {
+			procErr = err
+			return
+		}
+		if err := w.checkDocuments(files); err != nil {
+			procErr = err
+			if errors.Is(err, errRejected) {
+				w.recordAttempt(base, w.maxAttempts)
+			}
+			return
+		}
+
+		// In-flight manifests run to completion on shutdown, so not tied to Run's ctx
+		result, err := w.income.VerifyDocuments(context.Background(), metadata, files)
+		if err != nil {
+			procErr = err
+			return
+		}
+		if result.Status == dto.VerificationFailed {
+			var reasons []string
+			for _, d := range result.Documents {
+				reasons = append(reasons, d.Filename+": "+d.Error)
+			}
+			procErr = fmt.Errorf("no document could be processed: %s", strings.Join(reasons, "; "))
+			return
+		}
+		procErr = writeJSONAtomic(base+resultSuffix, result)
+	}()
+
+	if procErr == nil {
+		w.clearAttempts(base)
+		os.Rename(claimed, base+doneSuffix)
+		slog.Info("Folder watcher: processed", "manifest", filepath.Base(base))
+		return
+	}
+
+	attempts := w.recordAttempt(base, 1)
+	slog.Warn("Folder watcher: processing failed", "manifest", filepath.Base(base), "attempt", attempts, "max_attempts", w.maxAttempts, "error", procErr)
+	if attempts >= w.maxAttempts {
+		w.quarantine(claimed, metadata, procErr)
+		w.clearAttempts(base)
+		return
+	}
+	// Release the lock so the next tick retries it
+	os.Rename(claimed, base+manifestSuffix)
+}
+
+func (w *FolderWatcher) readDocuments(metadata dto.UploadMetadata) (map[string][]byte, error) {
+	files := make(map[string][]byte)
+	for _, name := range documentNames(metadata) {
+		data, err := os.ReadFile(filepath.Join(w.dir, filepath.Base(name)))
+		if err != nil {
+			return nil, fmt.Errorf("document %s: %w", name, err)
+		}
+		files[name] = data
+	}
+	return files, nil
+}
+
+// errRejected marks documents no retry will accept: infected, or in a format
+// income verification does not take.
+var errRejected = errors.New("document rejected")
+
+// checkDocuments scans the documents for malware and checks their formats,
+// by content, as ScanUploads and ValidateUploads do for uploads. A scanner
+// failure is retried, unless scanAction is scan.ActionFlag; then, as with an
+// infected document, it is only logged.
+func (w *FolderWatcher) checkDocuments(files map[string][]byte) error {
+	for name, data := range files {
+		if w.scanner != nil {
+			v, err := w.scanner.Scan(context.Background(), name, data)
+			switch {
+			case err != nil:
+				slog.Error("Folder watcher: document scan failed", "file", name, "error", err)
+				if w.scanAction != scan.ActionFlag {
+					return fmt.Errorf("document %s could not be scanned: %w", name, err)
+				}
+			case v.Infected:
+				slog.Warn("Folder watcher: malware detected", "file", name, "threat", v.Threat, "action", w.scanAction)
+				if w.scanAction != scan.ActionFlag {
+					return fmt.Errorf("%w: malware detected in %s: %s", errRejected, name, v.Threat)
+				}
+			}
+		}
+		format := filetype.SniffFile(bytes.NewReader(data), int64(len(data)), name, "")
+		if !slices.Contains(w.formats, format) {
+			return fmt.Errorf("%w: %s is not in a supported format; income verification takes %s", errRejected, name, filetype.Join(w.formats))
+		}
+	}
+	return nil
+}
+
+// quarantine moves a poison manifest and its documents aside with the error next to them.
+func (w *FolderWatcher) quarantine(claimed string, metadata dto.UploadMetadata, cause error) {
+	qdir := filepath.Join(w.dir, quarantineDir)
+	base := filepath.Base(strings.TrimSuffix(claimed, processingSuffix))
+
+	os.Rename(claimed, filepath.Join(qdir, base+manifestSuffix))
+	for _, name := range documentNames(metadata) {
+		name = filepath.Base(name)
+		os.Rename(filepath.Join(w.dir, name), filepath.Join(qdir, name))
+	}
+	os.WriteFile(filepath.Join(qdir, base+".error.txt"), []byte(cause.Error()+"\n"), 0644)
+
+	slog.Error("Folder watcher: quarantined", "manifest", base, "error", cause)
+}
+
+func (w *FolderWatcher) recordAttempt(base string, n int) int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.attempts[base] += n
+	return w.attempts[base]
+}
+
+func (w *FolderWatcher) clearAttempts(base string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.attempts, base)
+}
+
+func documentNames(metadata dto.UploadMetadata) []string {
+	var names []string
+	for _, d := range metadata.Documents {
+		if len(d.Pages) > 0 {
+			names = append(names, d.Pages...)
+		} else {
+			names = append(names, d.Filename)
+		}
+	}
+	return names
+}
+
+// writeJSONAtomic writes v next to path and renames it into place, so readers
+// never see a half-written result.
+func writeJSONAtomic(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
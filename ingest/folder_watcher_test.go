@@ -0,0 +1,89 @@
+package ingest
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/scan"
+	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFolderWatcherProcessesAndQuarantines(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "good"+manifestSuffix), []byte(`{"documents": []}`), 0644)
+	os.WriteFile(filepath.Join(dir, "bad"+manifestSuffix), []byte(`{not json`), 0644)
+
+	w := NewFolderWatcher(dir, 10*time.Millisecond, 1, 3, &service.IncomeService{}, nil, "", filetype.Documents)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, w.Run(ctx))
+
+	assert.FileExists(t, filepath.Join(dir, "good"+resultSuffix))
+	assert.FileExists(t, filepath.Join(dir, "good"+doneSuffix))
+	assert.FileExists(t, filepath.Join(dir, quarantineDir, "bad"+manifestSuffix))
+	assert.FileExists(t, filepath.Join(dir, quarantineDir, "bad.error.txt"))
+	assert.NoFileExists(t, filepath.Join(dir, "bad"+manifestSuffix))
+}
+
+func TestFolderWatcherRecoversOnlyStaleClaims(t *testing.T) {
+	dir := t.TempDir()
+	live := filepath.Join(dir, "live"+processingSuffix)
+	dead := filepath.Join(dir, "dead"+processingSuffix)
+	require.NoError(t, os.WriteFile(live, []byte(`{"documents": []}`), 0644))
+	require.NoError(t, os.WriteFile(dead, []byte(`{"documents": []}`), 0644))
+	old := time.Now().Add(-2 * claimLease)
+	require.NoError(t, os.Chtimes(dead, old, old))
+
+	w := NewFolderWatcher(dir, time.Second, 1, 3, &service.IncomeService{}, nil, "", filetype.Documents)
+	w.recoverStale()
+
+	// another instance still holds the live claim
+	assert.FileExists(t, live)
+	assert.NoFileExists(t, dead)
+	assert.FileExists(t, filepath.Join(dir, "dead"+manifestSuffix))
+}
+
+// eicarScanner reports files containing "EICAR" as infected.
+type eicarScanner struct{}
+
+func (eicarScanner) Scan(_ context.Context, _ string, data []byte) (scan.Verdict, error) {
+	if bytes.Contains(data, []byte("EICAR")) {
+		return scan.Verdict{Infected: true, Threat: "Eicar-Test-Signature"}, nil
+	}
+	return scan.Verdict{}, nil
+}
+
+func TestFolderWatcherRejectsInfectedAndUnsupportedDocuments(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "infected.pdf"), []byte("%PDF-1.4 EICAR"), 0644)
+	os.WriteFile(filepath.Join(dir, "infected"+manifestSuffix), []byte(`{"documents": [{"filename": "infected.pdf", "doc_type": "salary_slip"}]}`), 0644)
+	os.WriteFile(filepath.Join(dir, "program.pdf"), []byte("MZ\x90\x00program"), 0644)
+	os.WriteFile(filepath.Join(dir, "program"+manifestSuffix), []byte(`{"documents": [{"filename": "program.pdf", "doc_type": "salary_slip"}]}`), 0644)
+
+	w := NewFolderWatcher(dir, 10*time.Millisecond, 1, 50, &service.IncomeService{}, eicarScanner{}, scan.ActionReject, filetype.Documents)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	assert.NoError(t, w.Run(ctx))
+
+	// neither is retried: both are quarantined on the first attempt
+	for _, name := range []string{"infected", "program"} {
+		assert.FileExists(t, filepath.Join(dir, quarantineDir, name+manifestSuffix))
+		assert.FileExists(t, filepath.Join(dir, quarantineDir, name+".pdf"))
+		assert.NoFileExists(t, filepath.Join(dir, name+resultSuffix))
+	}
+	cause, err := os.ReadFile(filepath.Join(dir, quarantineDir, "infected.error.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(cause), "malware detected")
+	cause, err = os.ReadFile(filepath.Join(dir, quarantineDir, "program.error.txt"))
+	require.NoError(t, err)
+	assert.Contains(t, string(cause), "not in a supported format")
+}
@@ -0,0 +1,136 @@
+// Package logging configures the service's structured logger: leveled
+// text/JSON output via log/slog, the request ID of the HTTP request being
+// served, and masking of identity numbers before anything reaches the log.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+type requestIDKey struct{}
+
+// WithRequestID returns a context whose log records carry id.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID returns the request ID stored in ctx, or "".
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// New builds a logger writing to w. level is debug | info | warn | error and
+// format is text | json. With redact set, Aadhaar numbers, PANs, account and
+// phone numbers and email addresses in messages and string attributes are
+// masked to their last four characters.
+func New(w io.Writer, level, format string, redact bool) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid log level %q", level)
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var h slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		h = slog.NewTextHandler(w, opts)
+	case "json":
+		h = slog.NewJSONHandler(w, opts)
+	default:
+		return nil, fmt.Errorf("invalid log format %q", format)
+	}
+	return slog.New(&handler{next: h, redact: redact}), nil
+}
+
+// Redact masks the PII found by utils.ScanPII in s.
+func Redact(s string) string {
+	findings := utils.ScanPII(s)
+	if len(findings) == 0 {
+		return s
+	}
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		b.WriteString(s[last:f.Start])
+		b.WriteString(f.Masked)
+		last = f.End
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+// handler adds the request ID and applies redaction on top of a slog handler.
+type handler struct {
+	next   slog.Handler
+	redact bool
+}
+
+func (h *handler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+func (h *handler) Handle(ctx context.Context, r slog.Record) error {
+	out := slog.NewRecord(r.Time, r.Level, h.scrub(r.Message), r.PC)
+	if ctx != nil {
+		if id := RequestID(ctx); id != "" {
+			out.AddAttrs(slog.String("request_id", id))
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		out.AddAttrs(h.scrubAttr(a))
+		return true
+	})
+	return h.next.Handle(ctx, out)
+}
+
+func (h *handler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	scrubbed := make([]slog.Attr, len(attrs))
+	for i, a := range attrs {
+		scrubbed[i] = h.scrubAttr(a)
+	}
+	return &handler{next: h.next.WithAttrs(scrubbed), redact: h.redact}
+}
+
+func (h *handler) WithGroup(name string) slog.Handler {
+	return &handler{next: h.next.WithGroup(name), redact: h.redact}
+}
+
+func (h *handler) scrub(s string) string {
+	if !h.redact {
+		return s
+	}
+	return Redact(s)
+}
+
+func (h *handler) scrubAttr(a slog.Attr) slog.Attr {
+	if !h.redact {
+		return a
+	}
+	v := a.Value.Resolve()
+	switch v.Kind() {
+	case slog.KindString:
+		return slog.String(a.Key, Redact(v.String()))
+	case slog.KindGroup:
+		group := v.Group()
+		attrs := make([]slog.Attr, len(group))
+		for i, g := range group {
+			attrs[i] = h.scrubAttr(g)
+		}
+		return slog.Attr{Key: a.Key, Value: slog.GroupValue(attrs...)}
+	case slog.KindAny:
+		if err, ok := v.Any().(error); ok {
+			return slog.String(a.Key, Redact(err.Error()))
+		}
+		if s, ok := v.Any().(fmt.Stringer); ok {
+			return slog.String(a.Key, Redact(s.String()))
+		}
+	}
+	return slog.Attr{Key: a.Key, Value: v}
+}
@@ -0,0 +1,40 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoggerRedactsAndTagsRequest(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", "json", true)
+	require.NoError(t, err)
+
+	ctx := WithRequestID(context.Background(), "req-42")
+	logger.InfoContext(ctx, "parsed PAN ABCDE1234F", "text", "Aadhaar 2345 6789 0123", "error", errors.New("account 123456789012345"))
+	logger.Debug("hidden")
+
+	out := buf.String()
+	assert.Contains(t, out, `"request_id":"req-42"`)
+	assert.Contains(t, out, "XXXXXX234F")
+	assert.Contains(t, out, "XXXXXXXX0123")
+	assert.Contains(t, out, "XXXXXXXXXXX2345")
+	for _, pii := range []string{"ABCDE1234F", "2345 6789 0123", "123456789012345"} {
+		assert.NotContains(t, out, pii)
+	}
+	assert.NotContains(t, out, "hidden")
+	assert.Equal(t, 1, strings.Count(out, "\n"))
+}
+
+func TestNewRejectsBadConfig(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "loud", "text", false)
+	assert.Error(t, err)
+	_, err = New(&bytes.Buffer{}, "info", "xml", false)
+	assert.Error(t, err)
+}
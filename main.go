@@ -1,105 +1,754 @@
 package main
 
 import (
-	"log"
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
+	"github.com/Aashish23092/ocr-income-verification/auth"
+	"github.com/Aashish23092/ocr-income-verification/cache"
 	"github.com/Aashish23092/ocr-income-verification/client"
 	"github.com/Aashish23092/ocr-income-verification/config"
+	"github.com/Aashish23092/ocr-income-verification/container"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/grpcserver"
 	"github.com/Aashish23092/ocr-income-verification/handler"
+	"github.com/Aashish23092/ocr-income-verification/ingest"
+	"github.com/Aashish23092/ocr-income-verification/logging"
+	"github.com/Aashish23092/ocr-income-verification/openapi"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	ocrv1 "github.com/Aashish23092/ocr-income-verification/proto/ocr/v1"
+	"github.com/Aashish23092/ocr-income-verification/scan"
 	"github.com/Aashish23092/ocr-income-verification/service"
+	"github.com/Aashish23092/ocr-income-verification/storage"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils/fieldtemplate"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/Aashish23092/ocr-income-verification/utils/rules"
+	"github.com/Aashish23092/ocr-income-verification/utils/scoring"
+	"github.com/Aashish23092/ocr-income-verification/utils/secureqr"
+	"github.com/Aashish23092/ocr-income-verification/vault"
 
 	"github.com/gin-gonic/gin"
+	"google.golang.org/grpc"
 )
 
 func main() {
 
 	// Tesseract configuration
 	os.Setenv("TESSDATA_PREFIX", "/usr/share/tesseract-ocr/5/tessdata/")
-	log.Println("TESSDATA_PREFIX set to:", os.Getenv("TESSDATA_PREFIX"))
 
 	// Load application config
 	cfg := config.LoadConfig()
 
+	// Structured logger; the standard log package is routed through it too
+	logger, err := logging.New(os.Stderr, cfg.LogLevel, cfg.LogFormat, cfg.LogRedactPII)
+	if err != nil {
+		fatal("Invalid logging config", err)
+	}
+	slog.SetDefault(logger)
+	slog.Info("TESSDATA_PREFIX set", "path", os.Getenv("TESSDATA_PREFIX"))
+
 	// Initialize Tesseract client
-	tesseractClient := client.NewTesseractClient(cfg.TesseractDataPath)
+	tesseractClient := client.NewTesseractClient(cfg.TesseractDataPath, cfg.TesseractPoolSize, cfg.TesseractLang)
 	defer tesseractClient.Close()
 
 	// Initialize PDF processor
-	pdfProcessor := service.NewPDFProcessor()
+	pdfProcessor := service.NewPDFProcessor(service.PDFLimits{
+		MaxPages:      cfg.PDFMaxPages,
+		MaxPagePixels: int64(cfg.PDFMaxPageMegapixels * 1e6),
+		PageTimeout:   time.Duration(cfg.PDFPageTimeoutSecs) * time.Second,
+		RenderBudget:  time.Duration(cfg.PDFRenderBudgetSecs) * time.Second,
+		MaxPageBytes:  int64(cfg.PDFMaxPageMB) << 20,
+	}, service.PDFRendering{Backend: cfg.PDFRenderer, DPI: cfg.PDFRenderDPI})
+	if cfg.PDFRenderer != service.RendererPdftoppm && cfg.PDFRenderer != service.RendererMuPDF {
+		fatal("Invalid PDF_RENDERER", fmt.Errorf("%q is neither %s nor %s", cfg.PDFRenderer, service.RendererPdftoppm, service.RendererMuPDF))
+	}
+	slog.Info("PDF rendering", "backend", cfg.PDFRenderer, "dpi", cfg.PDFRenderDPI)
+	if cfg.PDFTrustedCertsDir != "" {
+		n, err := service.LoadTrustedCertificates(cfg.PDFTrustedCertsDir)
+		if err != nil {
+			fatal("Failed to load trusted PDF signing certificates", err)
+		}
+		slog.Info("Loaded trusted PDF signing certificates", "count", n, "dir", cfg.PDFTrustedCertsDir)
+	}
 
 	// ------------------------------------------
 	// ⭐ Initialize PaddleOCR Client
 	// ------------------------------------------
 	paddleClient, err := client.NewPaddleClient()
 	if err != nil {
-		log.Printf("WARNING: PaddleOCR client could not initialize: %v", err)
+		slog.Warn("PaddleOCR client could not initialize", "error", err)
 		paddleClient = nil
 	} else {
-		log.Println("PaddleOCR client initialized successfully")
+		slog.Info("PaddleOCR client initialized")
+	}
+	// Services take the engine interface, which must be nil itself (not hold
+	// a nil client) when PaddleOCR is not configured
+	var paddleOCR client.PaddleEngine
+	if paddleClient != nil {
+		paddleOCR = paddleClient
 	}
 
 	// ------------------------------------------
-	// Income Service
+	// Field extraction templates (YAML)
 	// ------------------------------------------
-	incomeService := service.NewIncomeService(
-		tesseractClient,
-		pdfProcessor,
-		paddleClient,
-	)
-	incomeHandler := handler.NewIncomeHandler(incomeService)
+	templates, err := fieldtemplate.LoadDir(cfg.TemplateDir)
+	if err != nil {
+		fatal("Failed to load extraction templates", err)
+	}
+	if cfg.BuiltinTemplates {
+		if err := templates.AddBuiltin(); err != nil {
+			fatal("Failed to load built-in extraction templates", err)
+		}
+	}
+	slog.Info("Loaded extraction templates", "count", len(templates.Templates()), "dir", cfg.TemplateDir)
 
 	// ------------------------------------------
-	// Aadhaar Service
+	// Tenant decision rules (expr)
 	// ------------------------------------------
-	aadhaarService := service.NewAadhaarService(tesseractClient, pdfProcessor)
-	aadhaarHandler := handler.NewAadhaarHandler(aadhaarService)
+	decisionRules, err := rules.LoadDir(cfg.RulesDir)
+	if err != nil {
+		fatal("Failed to load decision rules", err)
+	}
+
+	scoreWeights, err := scoring.LoadFile(cfg.ScoreWeightsFile)
+	if err != nil {
+		fatal("Failed to load score weights", err)
+	}
 
 	// ------------------------------------------
-	// PAN OCR Service + Handler
+	// Processing pipelines (per document type / tenant)
 	// ------------------------------------------
-	panService := service.NewPANService(paddleClient)
-	panHandler := handler.NewPANHandler(panService)
+	pipelineDefs, err := pipeline.LoadFile(cfg.PipelinesFile)
+	if err != nil {
+		fatal("Failed to load pipelines", err)
+	}
+	pipelines := pipeline.NewOrchestrator(pipelineDefs, service.PipelineSteps(pdfProcessor, paddleOCR, tesseractClient))
 
-	dlService := service.NewDrivingLicenseService(paddleClient, tesseractClient)
-	dlHandler := handler.NewDrivingLicenseHandler(dlService)
+	// At most OCR_CONCURRENCY documents are OCRed at once; the rest queue
+	ocrLimiter := pipeline.NewLimiter(cfg.OCRConcurrency)
+	pipelines = pipelines.WithLimiter(ocrLimiter)
+
+	// Parsed results of identical uploads (file hash + doc type)
+	resultCache, err := cache.New(cfg.CacheBackend, cfg.CacheSize, cfg.RedisURL)
+	if err != nil {
+		fatal("Failed to initialize result cache", err)
+	}
+	if resultCache != nil {
+		pipelines = pipelines.WithCache(resultCache, time.Duration(cfg.CacheTTLSecs)*time.Second)
+		slog.Info("Result cache enabled", "backend", cfg.CacheBackend)
+	}
+
+	// Aadhaar (and other configured) numbers are masked in every response and
+	// webhook; tokenize_pii=true swaps them for tokens kept in TOKEN_STORE
+	tokenStore, err := vault.NewStore(cfg.TokenStore, cfg.VaultAddr, cfg.VaultToken, cfg.VaultMount)
+	if err != nil {
+		fatal("Failed to initialize PII token store", err)
+	}
+	redactor := vault.NewRedactor(cfg.PIIMaskTypes, tokenStore)
+
+	// Completion webhooks (callback_url)
+	webhooks := client.NewWebhookClient()
+	webhooks.Redact = func(body []byte) ([]byte, error) {
+		return redactor.JSON(context.Background(), body, false)
+	}
+
+	// Stored verifications, applicant documents and layout templates: Postgres
+	// when DATABASE_URL is set, else in memory within bounds
+	memoryTTL := time.Duration(cfg.MemoryStoreTTLSecs) * time.Second
+	var verificationStore store.VerificationStore = store.NewMemoryStore(cfg.MemoryStoreMaxRecords, memoryTTL)
+	var applicantStore store.ApplicantStore = store.NewMemoryApplicantStore(cfg.MemoryStoreMaxRecords, memoryTTL)
+	var layoutTemplateStore store.LayoutTemplateStore = store.NewMemoryLayoutTemplateStore()
+	if cfg.DatabaseURL != "" {
+		pg, err := store.NewPostgresStore(cfg.DatabaseURL)
+		if err != nil {
+			fatal("Failed to initialize verification database", err)
+		}
+		defer pg.Close()
+		verificationStore, applicantStore, layoutTemplateStore = pg, pg, pg
+		slog.Info("Verifications stored in Postgres")
+	}
 
 	// ------------------------------------------
-	// Employee Verification OCR Service
+	// UIDAI certificates (Aadhaar secure QR and offline e-KYC)
 	// ------------------------------------------
-	employeeService := service.NewEmployeeService(paddleClient)
-	employeeHandler := handler.NewEmployeeHandler(employeeService)
+	var aadhaarQRKey *rsa.PublicKey
+	if cfg.AadhaarQRCertFile != "" {
+		aadhaarQRKey, err = secureqr.LoadPublicKey(cfg.AadhaarQRCertFile)
+		if err != nil {
+			fatal("Failed to load UIDAI certificate", err)
+		}
+	} else {
+		slog.Warn("AADHAAR_QR_CERT_FILE not set; Aadhaar secure QR signatures will not be verified")
+	}
+	var aadhaarEKYCKey *rsa.PublicKey
+	if cfg.AadhaarEKYCCertFile != "" {
+		aadhaarEKYCKey, err = secureqr.LoadPublicKey(cfg.AadhaarEKYCCertFile)
+		if err != nil {
+			fatal("Failed to load UIDAI offline e-KYC certificate", err)
+		}
+	} else {
+		slog.Warn("AADHAAR_EKYC_CERT_FILE not set; Aadhaar offline e-KYC signatures will not be verified")
+	}
+
+	// Selfie-to-document face match (FACE_BACKEND=off disables it)
+	faceClient, err := client.NewFaceClient()
+	if err != nil {
+		fatal("Failed to initialize face embedding client", err)
+	}
+
+	// Integrator feedback on extractions (per-field accuracy by release)
+	feedbackKey := []byte(cfg.FeedbackHashKey)
+	if len(feedbackKey) == 0 {
+		feedbackKey = make([]byte, 32)
+		rand.Read(feedbackKey)
+	}
+
+	// ------------------------------------------
+	// Services and handlers
+	// ------------------------------------------
+	// Each service and handler registers its constructor with the container
+	// and takes what it depends on from it by type; a new service is one more
+	// Provide. The engines, stores and clients above go in as values.
+	deps := container.New()
+	container.Value(deps, cfg)
+	container.Value[client.TesseractEngine](deps, tesseractClient)
+	container.Value(deps, paddleOCR)
+	container.Value[service.PDFProcessor](deps, pdfProcessor)
+	container.Value(deps, pipelines)
+	container.Value(deps, webhooks)
+	container.Value(deps, verificationStore)
+	container.Value(deps, applicantStore)
+	container.Value(deps, layoutTemplateStore)
+
+	// Layout templates registered from annotated samples (admin API)
+	container.Provide(deps, func(c *container.Container) (*service.LayoutTemplateService, error) {
+		return service.NewLayoutTemplateService(container.Get[store.LayoutTemplateStore](c), container.Get[service.PDFProcessor](c), container.Get[client.TesseractEngine](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.LayoutTemplateHandler, error) {
+		return handler.NewLayoutTemplateHandler(container.Get[*service.LayoutTemplateService](c)), nil
+	})
+
+	// Income verification, and the ITR, Form 16, GST and rent analyses on it
+	container.Provide(deps, func(c *container.Container) (*service.IncomeService, error) {
+		return service.NewIncomeService(
+			container.Get[client.TesseractEngine](c),
+			container.Get[service.PDFProcessor](c),
+			container.Get[client.PaddleEngine](c),
+			templates,
+			container.Get[*service.LayoutTemplateService](c),
+			decisionRules,
+			container.Get[store.VerificationStore](c),
+			scoreWeights,
+			container.Get[*pipeline.Orchestrator](c),
+			container.Get[*client.WebhookClient](c),
+			client.NewIFSCClient(),
+			client.NewCompanyRegistryClient(),
+			container.Get[*config.Config](c),
+		)
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.IncomeHandler, error) {
+		return handler.NewIncomeHandler(container.Get[*service.IncomeService](c), container.Get[*client.WebhookClient](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.GSTHandler, error) {
+		return handler.NewGSTHandler(service.NewGSTService(container.Get[*service.IncomeService](c)), container.Get[*client.WebhookClient](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.RentHandler, error) {
+		return handler.NewRentHandler(service.NewRentService(container.Get[*service.IncomeService](c)), container.Get[*client.WebhookClient](c)), nil
+	})
+
+	// ID documents
+	container.Provide(deps, func(c *container.Container) (*service.AadhaarService, error) {
+		return service.NewAadhaarService(container.Get[client.TesseractEngine](c), container.Get[service.PDFProcessor](c), container.Get[*pipeline.Orchestrator](c), aadhaarQRKey, aadhaarEKYCKey, cfg.AadhaarRejectUnmasked)
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.AadhaarHandler, error) {
+		return handler.NewAadhaarHandler(container.Get[*service.AadhaarService](c), container.Get[*client.WebhookClient](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.PANService, error) {
+		return service.NewPANService(container.Get[client.PaddleEngine](c), container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.PANHandler, error) {
+		return handler.NewPANHandler(container.Get[*service.PANService](c), container.Get[*client.WebhookClient](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.DrivingLicenseService, error) {
+		return service.NewDrivingLicenseService(container.Get[client.PaddleEngine](c), container.Get[client.TesseractEngine](c), container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.DrivingLicenseHandler, error) {
+		return handler.NewDrivingLicenseHandler(container.Get[*service.DrivingLicenseService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.VoterIDService, error) {
+		return service.NewVoterIDService(container.Get[client.PaddleEngine](c), container.Get[client.TesseractEngine](c), container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.VoterIDHandler, error) {
+		return handler.NewVoterIDHandler(container.Get[*service.VoterIDService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.PassportService, error) {
+		return service.NewPassportService(container.Get[client.TesseractEngine](c), container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.PassportHandler, error) {
+		return handler.NewPassportHandler(container.Get[*service.PassportService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.AddressProofService, error) {
+		return service.NewAddressProofService(container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.AddressProofHandler, error) {
+		return handler.NewAddressProofHandler(container.Get[*service.AddressProofService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.EmployeeService, error) {
+		return service.NewEmployeeService(container.Get[client.PaddleEngine](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.EmployeeHandler, error) {
+		return handler.NewEmployeeHandler(container.Get[*service.EmployeeService](c)), nil
+	})
+
+	// Generic fields, redaction, cheques and handwritten regions
+	container.Provide(deps, func(c *container.Container) (*service.FieldsService, error) {
+		return service.NewFieldsService(container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.FieldsHandler, error) {
+		return handler.NewFieldsHandler(container.Get[*service.FieldsService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.RedactionService, error) {
+		return service.NewRedactionService(container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.RedactionHandler, error) {
+		return handler.NewRedactionHandler(container.Get[*service.RedactionService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.ChequeService, error) {
+		return service.NewChequeService(container.Get[client.TesseractEngine](c), cfg.MICRLang, container.Get[*pipeline.Orchestrator](c))
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.ChequeHandler, error) {
+		return handler.NewChequeHandler(container.Get[*service.ChequeService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*service.HandwritingService, error) {
+		return service.NewHandwritingService(client.NewHandwritingClient()), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.HandwritingHandler, error) {
+		return handler.NewHandwritingHandler(container.Get[*service.HandwritingService](c)), nil
+	})
+
+	// Selfie-to-document face match; nil without a face embedding client
+	container.Provide(deps, func(c *container.Container) (*service.FaceMatchService, error) {
+		if faceClient == nil {
+			return nil, nil
+		}
+		return service.NewFaceMatchService(faceClient, container.Get[service.PDFProcessor](c), cfg.FaceMatchThreshold)
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.FaceMatchHandler, error) {
+		return handler.NewFaceMatchHandler(container.Get[*service.FaceMatchService](c)), nil
+	})
+
+	// KYC cross-verification of Aadhaar, PAN and an income document
+	container.Provide(deps, func(c *container.Container) (*handler.KYCHandler, error) {
+		return handler.NewKYCHandler(service.NewKYCService(
+			container.Get[*service.AadhaarService](c),
+			container.Get[*service.PANService](c),
+			container.Get[*service.DrivingLicenseService](c),
+			container.Get[*service.PassportService](c),
+			container.Get[*service.AddressProofService](c),
+			container.Get[*service.IncomeService](c),
+		)), nil
+	})
+
+	// Batch (several documents of one applicant in one request)
+	container.Provide(deps, func(c *container.Container) (*handler.BatchHandler, error) {
+		return handler.NewBatchHandler(service.NewBatchService(
+			container.Get[*service.AadhaarService](c),
+			container.Get[*service.PANService](c),
+			container.Get[*service.DrivingLicenseService](c),
+			container.Get[*service.IncomeService](c),
+		)), nil
+	})
+
+	// Capture quality pre-check (image checks only, no OCR)
+	container.Provide(deps, func(c *container.Container) (*handler.CaptureQualityHandler, error) {
+		return handler.NewCaptureQualityHandler(service.NewCaptureQualityService()), nil
+	})
+
+	// Documents consolidated per applicant (applicant_id)
+	container.Provide(deps, func(c *container.Container) (*service.ApplicantService, error) {
+		return service.NewApplicantService(container.Get[store.ApplicantStore](c), container.Get[*service.IncomeService](c)), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.ApplicantHandler, error) {
+		return handler.NewApplicantHandler(container.Get[*service.ApplicantService](c)), nil
+	})
+
+	container.Provide(deps, func(c *container.Container) (*service.FeedbackService, error) {
+		return service.NewFeedbackService(store.NewMemoryFeedbackStore(cfg.FeedbackMaxExtractions), feedbackKey, cfg.Release), nil
+	})
+	container.Provide(deps, func(c *container.Container) (*handler.FeedbackHandler, error) {
+		return handler.NewFeedbackHandler(container.Get[*service.FeedbackService](c)), nil
+	})
+
+	incomeService := resolve[*service.IncomeService](deps)
+	aadhaarService := resolve[*service.AadhaarService](deps)
+	panService := resolve[*service.PANService](deps)
+	dlService := resolve[*service.DrivingLicenseService](deps)
+	applicantService := resolve[*service.ApplicantService](deps)
+	feedbackService := resolve[*service.FeedbackService](deps)
+
+	incomeHandler := resolve[*handler.IncomeHandler](deps)
+	layoutTemplateHandler := resolve[*handler.LayoutTemplateHandler](deps)
+	gstHandler := resolve[*handler.GSTHandler](deps)
+	rentHandler := resolve[*handler.RentHandler](deps)
+	aadhaarHandler := resolve[*handler.AadhaarHandler](deps)
+	panHandler := resolve[*handler.PANHandler](deps)
+	dlHandler := resolve[*handler.DrivingLicenseHandler](deps)
+	voterIDHandler := resolve[*handler.VoterIDHandler](deps)
+	passportHandler := resolve[*handler.PassportHandler](deps)
+	addressProofHandler := resolve[*handler.AddressProofHandler](deps)
+	employeeHandler := resolve[*handler.EmployeeHandler](deps)
+	fieldsHandler := resolve[*handler.FieldsHandler](deps)
+	redactionHandler := resolve[*handler.RedactionHandler](deps)
+	chequeHandler := resolve[*handler.ChequeHandler](deps)
+	handwritingHandler := resolve[*handler.HandwritingHandler](deps)
+	faceMatchHandler := resolve[*handler.FaceMatchHandler](deps)
+	kycHandler := resolve[*handler.KYCHandler](deps)
+	batchHandler := resolve[*handler.BatchHandler](deps)
+	captureQualityHandler := resolve[*handler.CaptureQualityHandler](deps)
+	applicantHandler := resolve[*handler.ApplicantHandler](deps)
+	feedbackHandler := resolve[*handler.FeedbackHandler](deps)
+
+	// ------------------------------------------
+	// Upload storage (UPLOAD_STORAGE)
+	// ------------------------------------------
+	uploads, err := storage.New(storage.Config{
+		Backend:   cfg.UploadStorage,
+		Dir:       cfg.UploadDir,
+		Bucket:    cfg.StorageBucket,
+		Prefix:    cfg.StoragePrefix,
+		Endpoint:  cfg.StorageEndpoint,
+		Region:    cfg.StorageRegion,
+		AccessKey: cfg.StorageAccessKey,
+		SecretKey: cfg.StorageSecretKey,
+	})
+	if err != nil {
+		fatal("Failed to initialize upload storage", err)
+	}
+	if uploads != nil {
+		// Retained uploads expire after the retention period; otherwise only
+		// those of requests that crashed mid-way are left to sweep
+		maxAge := time.Duration(cfg.UploadRetentionSecs) * time.Second
+		if maxAge == 0 {
+			maxAge = time.Hour
+		}
+		go storage.Janitor(context.Background(), uploads, maxAge, uploadSweepInterval)
+		slog.Info("Upload storage enabled", "backend", cfg.UploadStorage, "retention_seconds", cfg.UploadRetentionSecs)
+	}
+
+	// ------------------------------------------
+	// Upload malware scanning (UPLOAD_SCANNER)
+	// ------------------------------------------
+	scanner, err := scan.New(scan.Config{
+		Backend:    cfg.UploadScanner,
+		ClamAVAddr: cfg.ClamAVAddr,
+		URL:        cfg.UploadScanURL,
+		Token:      cfg.UploadScanToken,
+		Timeout:    time.Duration(cfg.UploadScanTimeout) * time.Second,
+	})
+	if err != nil {
+		fatal("Failed to initialize upload scanning", err)
+	}
+	if cfg.UploadScanAction != scan.ActionReject && cfg.UploadScanAction != scan.ActionFlag {
+		fatal("Invalid UPLOAD_SCAN_ACTION", fmt.Errorf("%q is neither %s nor %s", cfg.UploadScanAction, scan.ActionReject, scan.ActionFlag))
+	}
+	if scanner != nil {
+		slog.Info("Upload scanning enabled", "backend", cfg.UploadScanner, "action", cfg.UploadScanAction)
+	}
+
+	// income verification takes statements downloaded as CSV or XLSX and
+	// Account Aggregator FI JSON next to PDFs and images
+	incomeFormats := slices.Concat(filetype.Documents, filetype.Spreadsheets, []filetype.Format{filetype.JSON})
+	// formats of uploads by route and field, over PDFs and images
+	uploadFormats := map[string]handler.UploadFormats{
+		"/api/v1/income/verify":       {"": incomeFormats},
+		"/api/v1/aadhaar/ekyc":        {"": {filetype.ZIP}},
+		"/api/v1/kyc/facematch":       {"selfie": filetype.Photos},
+		"/api/v1/documents/quality":   {"": filetype.Photos},
+		"/api/v1/handwriting/extract": {"": filetype.Photos},
+	}
+
+	// ------------------------------------------
+	// Folder watcher (optional, WATCH_DIR)
+	// ------------------------------------------
+	if cfg.WatchDir != "" {
+		watcher := ingest.NewFolderWatcher(
+			cfg.WatchDir,
+			time.Duration(cfg.WatchIntervalSecs)*time.Second,
+			cfg.WatchWorkers,
+			cfg.WatchMaxAttempts,
+			incomeService,
+			scanner,
+			cfg.UploadScanAction,
+			incomeFormats,
+		)
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		if cfg.WatchOnly {
+			if err := watcher.Run(ctx); err != nil {
+				fatal("Folder watcher failed", err)
+			}
+			return
+		}
+		go func() {
+			if err := watcher.Run(ctx); err != nil {
+				slog.Error("Folder watcher failed", "error", err)
+			}
+		}()
+	}
+
 	// ------------------------------------------
 	// Gin Router
 	// ------------------------------------------
-	router := gin.Default()
+	// Upload limits per route, over MAX_FILE_SIZE_MB and ten files a request:
+	// ID cards are small photos or one- or two-page PDFs, statements long
+	idCard := handler.UploadLimit{MaxBytes: 5 << 20, MaxPages: 4}
+	uploadLimits := map[string]handler.UploadLimit{
+		"/api/v1/income/verify":        {MaxBytes: 25 << 20, MaxPages: 500, MaxFiles: 20},
+		"/api/v1/documents/batch":      handler.BatchUploadLimit,
+		"/api/v1/itr/analyze":          {MaxBytes: 25 << 20},
+		"/api/v1/form26as/analyze":     {MaxBytes: 25 << 20},
+		"/api/v1/gst/analyze":          {MaxBytes: 25 << 20},
+		"/api/v1/aadhaar/extract":      idCard,
+		"/api/v1/pan/ocr":              idCard,
+		"/api/v1/driving-license/ocr":  idCard,
+		"/api/v1/dl/extract":           idCard,
+		"/api/v1/voterid/extract":      idCard,
+		"/api/v1/passport/extract":     idCard,
+		"/api/v1/cheque/extract":       idCard,
+		"/api/v1/addressproof/extract": {MaxPages: 10},
+	}
+	for route, limit := range cfg.UploadLimits {
+		uploadLimits[route] = handler.UploadLimit(limit)
+	}
+
+	router := gin.New()
 	router.MaxMultipartMemory = 32 << 20
+	// request bodies are capped before anything reads them; the form is only
+	// read, and its files checked, once the client is authenticated
+	defaultUploadLimit := handler.UploadLimit{MaxBytes: cfg.MaxFileSize, MaxFiles: 10}
+	router.Use(gin.Recovery(), handler.RequestID(), handler.LimitUploads(defaultUploadLimit, uploadLimits), handler.ProtectPII(redactor), handler.CacheBypass(), handler.PhotoToggle())
 
 	router.GET("/health", func(c *gin.Context) {
+		status := "healthy"
+		var paddle interface{} = "not_configured"
+		if paddleClient != nil {
+			health := paddleClient.Health()
+			if health.State != client.BreakerClosed {
+				status = "degraded" // PaddleOCR is failing: Tesseract-only mode
+			}
+			paddle = health
+		}
 		c.JSON(200, gin.H{
-			"status":  "healthy",
-			"service": "OCR Income Verification",
+			"status":   status,
+			"service":  "OCR Income Verification",
+			"paddle":   paddle,
+			"ocr_pool": ocrLimiter.Stats(),
 		})
 	})
 
+	// OpenAPI spec and Swagger UI
+	router.GET("/docs/openapi.json", handler.OpenAPISpec(openapi.Info{
+		Title:   "OCR Income Verification API",
+		Version: "1.0",
+		Description: "Income verification and KYC document extraction. /api/v1 requires an X-API-Key header unless authentication is disabled. " +
+			"POST, PUT and PATCH requests may carry an Idempotency-Key header: a retry with the same key returns the first response " +
+			"(marked Idempotent-Replayed: true) instead of processing the documents again. " +
+			"Any upload may be a ZIP archive, encrypted with the password in the archive_password field or not: " +
+			"the PDFs and images in it are processed as if uploaded in its place under their own names. " +
+			"Where upload scanning is enabled, an upload with malware fails with 422 MALWARE_DETECTED, " +
+			"or is processed with an X-Upload-Scan: infected response header when the service only flags it. " +
+			"A PDF beyond the processing limits (pages, page size, render time) fails with 422 and the error PDF_TOO_MANY_PAGES, " +
+			"PDF_PAGE_TOO_LARGE, PDF_RENDER_TIMEOUT or PDF_RENDER_TOO_LARGE. " +
+			"Uploads over the route's size, page or file count limits fail with 413 and REQUEST_TOO_LARGE, FILE_TOO_LARGE, " +
+			"TOO_MANY_PAGES or TOO_MANY_FILES. " +
+			"Uploads are identified by their content, not their name or Content-Type: a file in a format its document type " +
+			"does not take (PDF, PNG, JPEG, TIFF, HEIC or WebP; photos only for selfies, capture quality checks and handwriting; the ZIP for the offline e-KYC) " +
+			"fails with 415 UNSUPPORTED_TYPE. " +
+			"Document requests may set max_processing_ms (query or form field): pages OCR has not read by then are left out, " +
+			"and such a response has partial: true and a continuation_token; GET /api/v1/continuations/{token} returns 202 " +
+			"until the full response of the request is ready, then that response. " +
+			"Every error has the same body on every endpoint, its error one of the ErrorCode values: " +
+			"a document that cannot be read fails with 422 and OCR_FAILED, LOW_QUALITY, PARSE_EMPTY or PDF_ENCRYPTED.",
+	}, handler.APIRoutes))
+	router.GET("/docs", handler.SwaggerUI("/docs/openapi.json"))
+
+	// API key authentication, rate limiting and usage accounting for /api/v1
+	apiKeys := make(map[string]auth.Client, len(cfg.APIKeys))
+	for key, k := range cfg.APIKeys {
+		apiKeys[key] = auth.Client{Name: k.Client, RatePerMinute: k.RatePerMinute}
+	}
+	keyring := auth.NewKeyring(apiKeys)
+	if keyring.Len() == 0 && !cfg.APIAuthDisabled {
+		fatal("API_KEYS not set", errors.New("set API_KEYS, or API_AUTH_DISABLED=true to serve the API without authentication"))
+	}
+
 	api := router.Group("/api/v1")
+	if keyring.Len() > 0 {
+		api.Use(handler.RequireAPIKey(keyring))
+		slog.Info("API key authentication enabled", "keys", keyring.Len())
+	} else {
+		slog.Warn("API_AUTH_DISABLED set; /api/v1 and gRPC are served without authentication")
+	}
+	// upload limits go first: LanguageHint already reads the form
+	api.Use(handler.CheckUploads(defaultUploadLimit, uploadLimits), handler.LanguageHint(tesseractClient.ValidateLang))
+	// extraction responses kept for POST /feedback, route -> document type
+	api.Use(handler.RecordExtractions(feedbackService, map[string]string{
+		"/api/v1/income/verify":        "income",
+		"/api/v1/itr/analyze":          "itr",
+		"/api/v1/form16/analyze":       "form16",
+		"/api/v1/form26as/analyze":     string(dto.DocTypeForm26AS),
+		"/api/v1/gst/analyze":          string(dto.DocTypeGSTReturn),
+		"/api/v1/rent/analyze":         string(dto.DocTypeRent),
+		"/api/v1/aadhaar/extract":      dto.KYCDocAadhaar,
+		"/api/v1/aadhaar/ekyc":         dto.KYCDocAadhaar,
+		"/api/v1/pan/ocr":              dto.KYCDocPAN,
+		"/api/v1/driving-license/ocr":  dto.KYCDocDL,
+		"/api/v1/dl/extract":           dto.KYCDocDL,
+		"/api/v1/voterid/extract":      "voter_id",
+		"/api/v1/passport/extract":     dto.KYCDocPassport,
+		"/api/v1/addressproof/extract": dto.KYCDocBill,
+		"/api/v1/cheque/extract":       "cheque",
+		"/api/v1/kyc/verify":           "kyc",
+		"/api/v1/employee/verify":      "employee",
+		"/api/v1/handwriting/extract":  "handwriting",
+	}))
+	// Idempotency-Key: retried requests get the first response back
+	if cfg.IdempotencyTTLSecs > 0 {
+		var idempotencyStore cache.Cache = cache.NewLRU(cfg.CacheSize)
+		if cfg.CacheBackend == "redis" {
+			idempotencyStore = resultCache // shared by the replicas
+		}
+		api.Use(handler.Idempotency(idempotencyStore, time.Duration(cfg.IdempotencyTTLSecs)*time.Second, redactor))
+	}
+	// results of requests naming an applicant, route -> document type
+	api.Use(handler.RecordApplicantDocuments(applicantService, map[string]string{
+		"/api/v1/itr/analyze":          service.ApplicantDocITR,
+		"/api/v1/form16/analyze":       service.ApplicantDocForm16,
+		"/api/v1/form26as/analyze":     string(dto.DocTypeForm26AS),
+		"/api/v1/aadhaar/extract":      dto.KYCDocAadhaar,
+		"/api/v1/aadhaar/ekyc":         dto.KYCDocAadhaar,
+		"/api/v1/pan/ocr":              dto.KYCDocPAN,
+		"/api/v1/driving-license/ocr":  dto.KYCDocDL,
+		"/api/v1/dl/extract":           dto.KYCDocDL,
+		"/api/v1/passport/extract":     dto.KYCDocPassport,
+		"/api/v1/addressproof/extract": dto.KYCDocBill,
+		"/api/v1/kyc/verify":           service.ApplicantDocKYC,
+	}))
+	// document_url instead of an upload; routes with upload fields other than "file"
+	api.Use(handler.DocumentURLs(client.NewDocumentFetcher(cfg.MaxFileSize), map[string][]string{
+		"/api/v1/income/verify":   {"files[]"},
+		"/api/v1/documents/batch": {"files[]"},
+		"/api/v1/kyc/facematch":   {"document", "selfie"},
+		"/api/v1/kyc/verify":      {"aadhaar", "pan", "income_document", "driving_license", "passport", "address_proof"},
+		"/api/v1/employee/verify": {"employee_id_card", "appointment_letter"},
+	}))
+	// ZIP uploads (password in archive_password) stand for the documents in them,
+	// up to five files' worth; the offline e-KYC ZIP is the document itself
+	api.Use(handler.UnpackArchives(5*cfg.MaxFileSize, "/api/v1/aadhaar/ekyc"))
+	// every document is scanned for malware before anything decodes it
+	if scanner != nil {
+		api.Use(handler.ScanUploads(scanner, cfg.UploadScanAction))
+	}
+	// uploads must be in a format of their document type, judged by content;
+	// routes and fields not listed take PDFs and images
+	api.Use(handler.ValidateUploads(filetype.Documents, uploadFormats))
+	// HEIC/HEIF and WebP photos are converted to PNG for the OCR engines
+	api.Use(handler.ConvertImages())
+	if uploads != nil {
+		api.Use(handler.StageUploads(uploads, cfg.UploadRetentionSecs > 0))
+	}
+	// max_processing_ms: responses cut short carry a token for the full one;
+	// registered last, so only the route's handler runs again for it
+	var continuationStore cache.Cache = cache.NewLRU(cfg.CacheSize)
+	if cfg.CacheBackend == "redis" {
+		continuationStore = resultCache
+	}
+	api.Use(handler.ProcessingBudget(router, continuationStore, time.Duration(cfg.ContinuationTTLSecs)*time.Second, redactor))
 	{
+		// Calling client's usage
+		api.GET("/usage", handler.APIUsage(keyring))
+
+		// Ground-truth feedback on extractions and the resulting accuracy
+		api.POST("/feedback", feedbackHandler.SubmitFeedback)
+		api.GET("/feedback/accuracy", feedbackHandler.GetAccuracy)
+
+		// Original value of a PII token, for authorized systems only
+		api.GET("/tokens/:token", handler.RequireDetokenizer(cfg.DetokenizeTokens), handler.Detokenize(redactor))
+
+		// Full response of a request answered partially within max_processing_ms
+		api.GET("/continuations/:token", handler.Continuation(continuationStore))
+
+		// Layout templates for unusual document layouts, for administrators only
+		admin := api.Group("/admin", handler.RequireAdmin(cfg.AdminTokens))
+		{
+			admin.POST("/templates", layoutTemplateHandler.RegisterTemplate)
+			admin.GET("/templates", layoutTemplateHandler.ListTemplates)
+			admin.DELETE("/templates/:id", layoutTemplateHandler.DeleteTemplate)
+		}
+
 		// Income
 		income := api.Group("/income")
 		{
 			income.POST("/verify", incomeHandler.VerifyIncome)
+			income.GET("/verifications/:id", incomeHandler.GetVerification)
+			income.PATCH("/verifications/:id", handler.RequireReviewer(cfg.ReviewerTokens), incomeHandler.OverrideFields)
 		}
 
+		// Stored verifications, by ID or applicant, and their quick-screen score
+		api.GET("/verifications", incomeHandler.ListVerifications)
+		api.GET("/verifications/:id", incomeHandler.GetVerification)
+		api.GET("/verifications/:id/score", incomeHandler.GetVerificationScore)
+
+		// Identity and income profile of an applicant across documents
+		api.GET("/applicants/:id/summary", applicantHandler.GetSummary)
+
 		// ITR
 		itr := api.Group("/itr")
 		{
 			itr.POST("/analyze", incomeHandler.AnalyzeITR)
 		}
 
+		// Form-16
+		form16 := api.Group("/form16")
+		{
+			form16.POST("/analyze", incomeHandler.AnalyzeForm16)
+		}
+
+		// Form 26AS / AIS
+		form26AS := api.Group("/form26as")
+		{
+			form26AS.POST("/analyze", incomeHandler.AnalyzeForm26AS)
+		}
+
+		// GST (self-employed income)
+		gst := api.Group("/gst")
+		{
+			gst.POST("/analyze", gstHandler.AnalyzeGST)
+		}
+
+		// Rent receipts and agreements (housing expense)
+		rent := api.Group("/rent")
+		{
+			rent.POST("/analyze", rentHandler.AnalyzeRent)
+		}
+
 		// Aadhaar
-		aadhaar := api.Group("/aadhaar")
+		aadhaar := api.Group("/aadhaar", handler.MaskedImageToggle())
 		{
 			aadhaar.POST("/extract", aadhaarHandler.ExtractAadhaar)
+			aadhaar.POST("/ekyc", aadhaarHandler.ExtractEKYC)
 		}
 
 		//  PAN OCR API
@@ -112,16 +761,116 @@ func main() {
 		{
 			dl.POST("/ocr", dlHandler.ExtractDL)
 		}
+		// the same, named like the other KYC extraction routes
+		api.POST("/dl/extract", dlHandler.ExtractDL)
+		// Voter ID (EPIC) OCR API
+		voterID := api.Group("/voterid")
+		{
+			voterID.POST("/extract", voterIDHandler.ExtractVoterID)
+		}
+		// Passport (MRZ) API
+		passport := api.Group("/passport")
+		{
+			passport.POST("/extract", passportHandler.ExtractPassport)
+		}
+
+		// Utility bills as address proof
+		addressProof := api.Group("/addressproof")
+		{
+			addressProof.POST("/extract", addressProofHandler.ExtractAddressProof)
+		}
+		// Cancelled cheques as account ownership proof
+		cheque := api.Group("/cheque")
+		{
+			cheque.POST("/extract", chequeHandler.ExtractCheque)
+		}
+		// KYC: selfie vs ID document photo, and cross-verification of documents
+		kyc := api.Group("/kyc")
+		{
+			kyc.POST("/facematch", faceMatchHandler.FaceMatch)
+			kyc.POST("/verify", kycHandler.VerifyKYC)
+		}
+		// Batch document processing
+		documents := api.Group("/documents")
+		{
+			documents.POST("/batch", batchHandler.ProcessBatch)
+			documents.POST("/quality", captureQualityHandler.CheckQuality)
+			documents.POST("/extract-fields", fieldsHandler.ExtractFields)
+			documents.POST("/redact", redactionHandler.Redact)
+		}
 		// Employee OCR API
 		employee := api.Group("/employee")
 		{
 			employee.POST("/verify", employeeHandler.VerifyEmployee)
 		}
+		// Handwritten field OCR
+		hw := api.Group("/handwriting")
+		{
+			hw.POST("/extract", handwritingHandler.ExtractHandwriting)
+		}
 
 	}
 
-	log.Printf("Starting OCR Income Verification Service on port %s", cfg.ServerPort)
+	// ------------------------------------------
+	// gRPC API (GRPC_PORT) for internal services
+	// ------------------------------------------
+	if cfg.GRPCPort != "" {
+		opts := []grpc.ServerOption{grpc.MaxRecvMsgSize(grpcMaxMessageSize)}
+		if keyring.Len() > 0 {
+			unary, stream := grpcserver.APIKeyInterceptors(keyring)
+			opts = append(opts, grpc.UnaryInterceptor(unary), grpc.StreamInterceptor(stream))
+		}
+		grpcServer := grpc.NewServer(opts...)
+		ocrv1.RegisterOCRServiceServer(grpcServer, &grpcserver.Server{
+			Income:          incomeService,
+			Aadhaar:         aadhaarService,
+			PAN:             panService,
+			DL:              dlService,
+			Redactor:        redactor,
+			ValidateLang:    tesseractClient.ValidateLang,
+			UploadLimit:     defaultUploadLimit,
+			UploadLimits:    uploadLimits,
+			DocumentFormats: filetype.Documents,
+			UploadFormats:   uploadFormats,
+			Scanner:         scanner,
+			ScanAction:      cfg.UploadScanAction,
+		})
+		lis, err := net.Listen("tcp", ":"+cfg.GRPCPort)
+		if err != nil {
+			fatal("Failed to listen for gRPC", err)
+		}
+		go func() {
+			if err := grpcServer.Serve(lis); err != nil {
+				slog.Error("gRPC server stopped", "error", err)
+			}
+		}()
+		slog.Info("Starting gRPC API", "port", cfg.GRPCPort)
+	}
+
+	slog.Info("Starting OCR Income Verification Service", "port", cfg.ServerPort)
 	if err := router.Run(":" + cfg.ServerPort); err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+		fatal("Failed to start server", err)
 	}
 }
+
+// grpcMaxMessageSize bounds unary gRPC requests, which carry whole files;
+// larger uploads go through the streaming Upload call.
+const grpcMaxMessageSize = 64 << 20
+
+// uploadSweepInterval is how often expired uploads are swept.
+const uploadSweepInterval = 10 * time.Minute
+
+// fatal logs a startup failure and exits.
+// resolve returns the T of deps, exiting when it cannot be built.
+func resolve[T any](deps *container.Container) T {
+	v, err := container.Resolve[T](deps)
+	if err != nil {
+		fatal("Failed to initialize services", err)
+	}
+	return v
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
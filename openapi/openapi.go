@@ -0,0 +1,340 @@
+// Package openapi builds the OpenAPI 3 description of the REST API. Routes
+// are declared next to the handlers; request and response schemas are derived
+// from the Go types by reflection over their json tags, so the document
+// describes exactly what the handlers decode and encode.
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Info is the title, version and description of the API.
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Route documents one operation.
+type Route struct {
+	Method  string
+	Path    string // gin syntax, e.g. /api/v1/income/verifications/:id
+	Tag     string
+	Summary string
+	// Description is shown below the summary (markdown)
+	Description string
+	Params      []Param
+	// Form lists the fields of a multipart/form-data request body
+	Form []Field
+	// Body is a value of the JSON request body's type
+	Body interface{}
+	// Response is a value of the 200 response's type
+	Response interface{}
+	// Errors lists the documented error statuses (dto.ErrorResponse bodies)
+	Errors []int
+}
+
+// Param is a path, query or header parameter.
+type Param struct {
+	Name        string
+	In          string // path, query or header
+	Description string
+	Required    bool
+}
+
+// Field is a multipart form field: a file upload, a plain value, or a JSON
+// document when JSON is set.
+type Field struct {
+	Name        string
+	Description string
+	Required    bool
+	File        bool
+	// Multiple allows repeating the field (several files)
+	Multiple bool
+	// JSON is a value of the type the field's JSON content decodes into
+	JSON interface{}
+}
+
+// Build returns the OpenAPI 3 document for routes, ready to be marshalled.
+// errorBody is the type of every error response.
+func Build(info Info, routes []Route, errorBody interface{}) map[string]interface{} {
+	g := &generator{schemas: map[string]interface{}{}, types: map[string]reflect.Type{}}
+	errorSchema := g.schemaOf(reflect.TypeOf(errorBody))
+
+	paths := map[string]map[string]interface{}{}
+	for _, r := range routes {
+		path, pathParams := convertPath(r.Path)
+		if paths[path] == nil {
+			paths[path] = map[string]interface{}{}
+		}
+		op := map[string]interface{}{
+			"summary":     r.Summary,
+			"operationId": operationID(r.Method, r.Path),
+		}
+		if r.Tag != "" {
+			op["tags"] = []string{r.Tag}
+		}
+		if r.Description != "" {
+			op["description"] = r.Description
+		}
+
+		var params []interface{}
+		for _, name := range pathParams {
+			params = append(params, parameter(Param{Name: name, In: "path", Required: true}, r.Params))
+		}
+		for _, p := range r.Params {
+			if p.In != "path" {
+				params = append(params, parameter(p, nil))
+			}
+		}
+		if len(params) > 0 {
+			op["parameters"] = params
+		}
+
+		switch {
+		case len(r.Form) > 0:
+			op["requestBody"] = g.formBody(r.Form)
+		case r.Body != nil:
+			op["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(g.schemaOf(reflect.TypeOf(r.Body))),
+			}
+		}
+
+		responses := map[string]interface{}{}
+		ok := map[string]interface{}{"description": "OK"}
+		if r.Response != nil {
+			ok["content"] = jsonContent(g.schemaOf(reflect.TypeOf(r.Response)))
+		}
+		responses["200"] = ok
+		for _, status := range r.Errors {
+			responses[strconv.Itoa(status)] = map[string]interface{}{
+				"description": http.StatusText(status),
+				"content":     jsonContent(errorSchema),
+			}
+		}
+		op["responses"] = responses
+		paths[path][strings.ToLower(r.Method)] = op
+	}
+
+	return map[string]interface{}{
+		"openapi":    "3.0.3",
+		"info":       info,
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}
+}
+
+// convertPath turns gin's :name segments into {name} and returns the names.
+func convertPath(path string) (string, []string) {
+	segments := strings.Split(path, "/")
+	var names []string
+	for i, seg := range segments {
+		if name, ok := strings.CutPrefix(seg, ":"); ok {
+			names = append(names, name)
+			segments[i] = "{" + name + "}"
+		}
+	}
+	return strings.Join(segments, "/"), names
+}
+
+// operationID derives a stable id such as post_income_verify.
+func operationID(method, path string) string {
+	path = strings.TrimPrefix(path, "/api/v1")
+	var parts []string
+	for _, seg := range strings.Split(path, "/") {
+		seg = strings.TrimPrefix(seg, ":")
+		if seg != "" {
+			parts = append(parts, strings.ReplaceAll(seg, "-", "_"))
+		}
+	}
+	return strings.ToLower(method) + "_" + strings.Join(parts, "_")
+}
+
+// parameter describes p, taking the description of a path parameter from
+// the route's declared params when present.
+func parameter(p Param, declared []Param) map[string]interface{} {
+	for _, d := range declared {
+		if d.In == p.In && d.Name == p.Name {
+			p.Description = d.Description
+		}
+	}
+	out := map[string]interface{}{
+		"name":     p.Name,
+		"in":       p.In,
+		"required": p.Required || p.In == "path",
+		"schema":   map[string]interface{}{"type": "string"},
+	}
+	if p.Description != "" {
+		out["description"] = p.Description
+	}
+	return out
+}
+
+func (g *generator) formBody(fields []Field) map[string]interface{} {
+	properties := map[string]interface{}{}
+	encoding := map[string]interface{}{}
+	var required []string
+	for _, f := range fields {
+		var schema map[string]interface{}
+		switch {
+		case f.File:
+			schema = map[string]interface{}{"type": "string", "format": "binary"}
+		case f.JSON != nil:
+			schema = g.schemaOf(reflect.TypeOf(f.JSON))
+			encoding[f.Name] = map[string]interface{}{"contentType": "application/json"}
+		default:
+			schema = map[string]interface{}{"type": "string"}
+		}
+		if f.Multiple {
+			schema = map[string]interface{}{"type": "array", "items": schema}
+		}
+		if f.Description != "" {
+			// $ref siblings are ignored, so wrap referenced schemas
+			if _, ok := schema["$ref"]; ok {
+				schema = map[string]interface{}{"allOf": []interface{}{schema}}
+			}
+			schema["description"] = f.Description
+		}
+		properties[f.Name] = schema
+		if f.Required {
+			required = append(required, f.Name)
+		}
+	}
+
+	object := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		object["required"] = required
+	}
+	media := map[string]interface{}{"schema": object}
+	if len(encoding) > 0 {
+		media["encoding"] = encoding
+	}
+	return map[string]interface{}{
+		"required": true,
+		"content":  map[string]interface{}{"multipart/form-data": media},
+	}
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// generator collects the named struct schemas into components.
+type generator struct {
+	schemas map[string]interface{}
+	types   map[string]reflect.Type
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// enumerated is a string type with a fixed set of values, such as
+// dto.ErrorCode.
+type enumerated interface{ Enum() []string }
+
+// schemaOf returns the schema of t; named structs become $refs to components.
+func (g *generator) schemaOf(t reflect.Type) map[string]interface{} {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.String:
+		if e, ok := reflect.Zero(t).Interface().(enumerated); ok {
+			return map[string]interface{}{"type": "string", "enum": e.Enum()}
+		}
+		return map[string]interface{}{"type": "string"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}
+		}
+		return map[string]interface{}{"type": "array", "items": g.schemaOf(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.structSchema(t)
+		}
+		name := g.componentName(t)
+		if _, done := g.schemas[name]; !done {
+			g.schemas[name] = nil // placeholder for recursive types
+			g.schemas[name] = g.structSchema(t)
+		}
+		return map[string]interface{}{"$ref": "#/components/schemas/" + name}
+	}
+	// interface{}: any JSON value
+	return map[string]interface{}{}
+}
+
+// componentName is the type name, qualified by its package when two
+// packages define types of the same name.
+func (g *generator) componentName(t reflect.Type) string {
+	name := t.Name()
+	if other, ok := g.types[name]; ok && other != t {
+		pkg := t.PkgPath()
+		name = pkg[strings.LastIndex(pkg, "/")+1:] + "." + name
+	}
+	g.types[name] = t
+	return name
+}
+
+// structSchema follows encoding/json: exported fields by their json names,
+// embedded structs flattened, "-" skipped. Fields without omitempty are
+// always present, so they are listed as required.
+func (g *generator) structSchema(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	var addFields func(t reflect.Type)
+	addFields = func(t reflect.Type) {
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			tag := f.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			name, opts, _ := strings.Cut(tag, ",")
+			if f.Anonymous && name == "" {
+				ft := f.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					addFields(ft)
+					continue
+				}
+			}
+			if !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = f.Name
+			}
+			properties[name] = g.schemaOf(f.Type)
+			if !strings.Contains(opts, "omitempty") {
+				required = append(required, name)
+			}
+		}
+	}
+	addFields(t)
+
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
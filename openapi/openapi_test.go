@@ -0,0 +1,105 @@
+package openapi
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCode string
+
+func (testCode) Enum() []string { return []string{"A", "B"} }
+
+type testError struct {
+	Error testCode `json:"error"`
+}
+
+type testMeta struct {
+	Documents []testDoc `json:"documents"`
+	Tenant    string    `json:"tenant_id,omitempty"`
+}
+
+type testDoc struct {
+	Name    string            `json:"filename"`
+	Seen    *time.Time        `json:"seen,omitempty"`
+	Extra   map[string]string `json:"extra,omitempty"`
+	Value   interface{}       `json:"value"`
+	Next    *testDoc          `json:"next,omitempty"`
+	Skipped string            `json:"-"`
+	hidden  string
+}
+
+func TestBuild(t *testing.T) {
+	doc := Build(Info{Title: "t", Version: "1"}, []Route{{
+		Method: "POST", Path: "/api/v1/items/:id", Summary: "create",
+		Form: []Field{
+			{Name: "files[]", File: true, Multiple: true, Required: true},
+			{Name: "metadata", JSON: testMeta{}, Required: true},
+		},
+		Response: testDoc{},
+		Errors:   []int{400},
+	}}, testError{})
+
+	// round-trip through JSON to inspect the document as a client would
+	raw, err := json.Marshal(doc)
+	require.NoError(t, err)
+	var spec struct {
+		Paths map[string]map[string]struct {
+			OperationID string `json:"operationId"`
+			Parameters  []struct {
+				Name, In string
+				Required bool
+			}
+			RequestBody struct {
+				Content map[string]struct {
+					Schema struct {
+						Properties map[string]map[string]interface{}
+						Required   []string
+					}
+					Encoding map[string]map[string]string
+				}
+			} `json:"requestBody"`
+			Responses map[string]json.RawMessage
+		}
+		Components struct {
+			Schemas map[string]struct {
+				Properties map[string]map[string]interface{}
+				Required   []string
+			}
+		}
+	}
+	require.NoError(t, json.Unmarshal(raw, &spec))
+
+	op, ok := spec.Paths["/api/v1/items/{id}"]["post"]
+	require.True(t, ok)
+	assert.Equal(t, "post_items_id", op.OperationID)
+	require.Len(t, op.Parameters, 1)
+	assert.Equal(t, "id", op.Parameters[0].Name)
+	assert.True(t, op.Parameters[0].Required)
+	assert.Contains(t, op.Responses, "400")
+
+	form := op.RequestBody.Content["multipart/form-data"]
+	assert.Equal(t, []string{"files[]", "metadata"}, form.Schema.Required)
+	assert.Equal(t, "array", form.Schema.Properties["files[]"]["type"])
+	assert.Equal(t, "#/components/schemas/testMeta", form.Schema.Properties["metadata"]["$ref"])
+	assert.Equal(t, "application/json", form.Encoding["metadata"]["contentType"])
+
+	d := spec.Components.Schemas["testDoc"]
+	assert.ElementsMatch(t, []string{"filename", "seen", "extra", "value", "next"}, keys(d.Properties))
+	assert.Equal(t, []string{"filename", "value"}, d.Required)
+	assert.Equal(t, "date-time", d.Properties["seen"]["format"])
+	assert.Equal(t, "#/components/schemas/testDoc", d.Properties["next"]["$ref"])
+	require.Contains(t, spec.Components.Schemas, "testError")
+	assert.Equal(t, []interface{}{"A", "B"}, spec.Components.Schemas["testError"].Properties["error"]["enum"])
+}
+
+func keys(m map[string]map[string]interface{}) []string {
+	var out []string
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
@@ -5,6 +5,7 @@ import cv2
 from PIL import Image
 import io
 import json
+import os
 
 app = Flask(__name__)
 
@@ -16,6 +17,26 @@ ocr = PaddleOCR(
 )
 # ocr = None
 
+# Recognition-only model for handwritten regions (cheque amounts, signed dates).
+# Point HANDWRITING_REC_MODEL_DIR at a handwriting-trained rec model; without it
+# the printed-text model is used, which is noticeably weaker on handwriting.
+handwriting_ocr = PaddleOCR(
+    use_angle_cls=False,
+    lang='en',
+    ocr_version='PP-OCRv3',
+    rec_model_dir=os.environ.get("HANDWRITING_REC_MODEL_DIR") or None,
+    show_log=False
+)
+
+# Face embedding model (ArcFace-style ONNX, 112x112 RGB input). Set
+# FACE_MODEL_PATH to the .onnx file; without it /face/embed answers 503.
+face_session = None
+if os.environ.get("FACE_MODEL_PATH"):
+    import onnxruntime
+    face_session = onnxruntime.InferenceSession(
+        os.environ["FACE_MODEL_PATH"], providers=["CPUExecutionProvider"]
+    )
+
 def load_image_safely(file_bytes):
     np_img = np.frombuffer(file_bytes, np.uint8)
     img = cv2.imdecode(np_img, cv2.IMREAD_COLOR)
@@ -58,24 +79,80 @@ def ocr_route():
             blocks = result[0]
         
         if not blocks:
-             return jsonify({"text": ""}), 200
+             return jsonify({"text": "", "lines": []}), 200
 
-        # Extract ALL text from the blocks
+        # Extract ALL text from the blocks, keeping each line's box and score
         extracted = []
+        lines = []
         for block in blocks:
             if isinstance(block, (list, tuple)) and len(block) >= 2:
                 text_block = block[1]
                 if isinstance(text_block, (list, tuple)) and len(text_block) >= 1:
                     text = str(text_block[0])
                     extracted.append(text)
+                    score = float(text_block[1]) if len(text_block) > 1 else 0.0
+                    box = [[float(x), float(y)] for x, y in block[0]]
+                    lines.append({"text": text, "confidence": score, "box": box})
 
         final_text = "\n".join(extracted)
-        return jsonify({"text": final_text}), 200
+        return jsonify({"text": final_text, "lines": lines}), 200
 
     except Exception as e:
         print("OCR ERROR:", e)
         return jsonify({"error": str(e)}), 500
 
 
+@app.route("/handwriting", methods=["POST"])
+def handwriting_route():
+    if "image" not in request.files:
+        return jsonify({"error": "image field missing"}), 400
+
+    img = load_image_safely(request.files["image"].read())
+    if img is None:
+        return jsonify({"error": "failed to decode image"}), 400
+
+    try:
+        # Regions are already cropped: skip detection, run recognition only
+        result = handwriting_ocr.ocr(img, det=False, cls=False)
+        lines = result[0] if result and isinstance(result[0], list) else result
+        if not lines:
+            return jsonify({"text": "", "confidence": 0.0}), 200
+
+        text, confidence = lines[0]
+        return jsonify({"text": str(text), "confidence": float(confidence)}), 200
+
+    except Exception as e:
+        print("HANDWRITING OCR ERROR:", e)
+        return jsonify({"error": str(e)}), 500
+
+
+@app.route("/face/embed", methods=["POST"])
+def face_embed_route():
+    if face_session is None:
+        return jsonify({"error": "face model not configured (FACE_MODEL_PATH)"}), 503
+    if "image" not in request.files:
+        return jsonify({"error": "image field missing"}), 400
+
+    img = load_image_safely(request.files["image"].read())
+    if img is None:
+        return jsonify({"error": "failed to decode image"}), 400
+
+    try:
+        # The caller sends a cropped face; normalize the way ArcFace was trained
+        face = cv2.resize(img, (112, 112))
+        face = cv2.cvtColor(face, cv2.COLOR_BGR2RGB).astype(np.float32)
+        face = (face - 127.5) / 127.5
+        blob = np.transpose(face, (2, 0, 1))[np.newaxis, :]
+
+        name = face_session.get_inputs()[0].name
+        emb = face_session.run(None, {name: blob})[0][0]
+        emb = emb / (np.linalg.norm(emb) + 1e-10)
+        return jsonify({"embedding": emb.astype(float).tolist()}), 200
+
+    except Exception as e:
+        print("FACE EMBED ERROR:", e)
+        return jsonify({"error": str(e)}), 500
+
+
 if __name__ == "__main__":
     app.run(host="0.0.0.0", port=8866)
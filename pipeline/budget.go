@@ -0,0 +1,46 @@
+package pipeline
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// OCRBudget is the time a request in SLA mode gives OCR: pages are read until
+// its deadline and the rest of a document is left unread, the document
+// marked Partial. One budget covers every document of the request.
+type OCRBudget struct {
+	deadline  time.Time
+	exhausted atomic.Bool
+}
+
+// NewOCRBudget starts a budget of d from now.
+func NewOCRBudget(d time.Duration) *OCRBudget {
+	return &OCRBudget{deadline: time.Now().Add(d)}
+}
+
+// Spent reports whether the deadline has passed; a nil budget never is.
+func (b *OCRBudget) Spent() bool {
+	return b != nil && time.Now().After(b.deadline)
+}
+
+// Exhaust records that pages were left unread for lack of time.
+func (b *OCRBudget) Exhaust() { b.exhausted.Store(true) }
+
+// Exhausted reports whether any document of the request was cut short.
+func (b *OCRBudget) Exhausted() bool {
+	return b != nil && b.exhausted.Load()
+}
+
+type budgetKey struct{}
+
+// WithOCRBudget attaches b to ctx for the OCR steps.
+func WithOCRBudget(ctx context.Context, b *OCRBudget) context.Context {
+	return context.WithValue(ctx, budgetKey{}, b)
+}
+
+// OCRBudgetFrom returns the budget of ctx, nil when there is none.
+func OCRBudgetFrom(ctx context.Context) *OCRBudget {
+	b, _ := ctx.Value(budgetKey{}).(*OCRBudget)
+	return b
+}
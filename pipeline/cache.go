@@ -0,0 +1,88 @@
+package pipeline
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+)
+
+type noCacheKey struct{}
+
+// WithoutCache marks ctx so that results are neither read from nor written to
+// the result cache (the caller asked for a fresh run).
+func WithoutCache(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noCacheKey{}, true)
+}
+
+func cacheDisabled(ctx context.Context) bool {
+	skip, _ := ctx.Value(noCacheKey{}).(bool)
+	return skip
+}
+
+// WithCache returns an orchestrator that caches parsed results in c for ttl.
+// Orchestrators derived from it with Extend share the cache.
+func (o *Orchestrator) WithCache(c cache.Cache, ttl time.Duration) *Orchestrator {
+	return &Orchestrator{defs: o.defs, registry: o.registry, cache: c, cacheTTL: ttl, limiter: o.limiter}
+}
+
+// CacheKey identifies a document by the SHA-256 of its inputs and its type.
+// The tenant (whose pipeline may differ), the OCR language and the password
+// and password hints are part of the key, so a cached result is never
+// returned for a wrong PDF password.
+func CacheKey(doc *Doc) string {
+	h := sha256.New()
+	var n [8]byte
+	for _, in := range doc.Inputs {
+		binary.BigEndian.PutUint64(n[:], uint64(len(in)))
+		h.Write(n[:])
+		h.Write(in)
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(doc.Password))
+	if p := doc.PasswordHints; p != nil {
+		h.Write([]byte{0})
+		h.Write([]byte(p.PAN + "\x00" + p.DOB + "\x00" + p.Phone + "\x00" + p.Name))
+	}
+	return "ocr:" + doc.DocType + ":" + doc.TenantID + ":" + doc.Language() + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// Cached returns the cached result for doc when there is one, otherwise calls
+// run and caches what it returns. Cache errors are logged and never fail the
+// document.
+func Cached[T any](o *Orchestrator, doc *Doc, run func() (T, error)) (T, error) {
+	if doc.Ctx == nil {
+		doc.Ctx = context.Background()
+	}
+	if o.cache == nil || cacheDisabled(doc.Ctx) {
+		return run()
+	}
+
+	key := CacheKey(doc)
+	if data, ok, err := o.cache.Get(doc.Ctx, key); err != nil {
+		slog.WarnContext(doc.Ctx, "Result cache: get failed", "error", err)
+	} else if ok {
+		var result T
+		if err := json.Unmarshal(data, &result); err == nil {
+			slog.InfoContext(doc.Ctx, "Result cache: hit", "doc_type", doc.DocType, "file", doc.Filename)
+			return result, nil
+		}
+		slog.WarnContext(doc.Ctx, "Result cache: discarding undecodable entry", "doc_type", doc.DocType)
+	}
+
+	result, err := run()
+	if err != nil || doc.Partial {
+		return result, err
+	}
+	if data, err := json.Marshal(result); err != nil {
+		slog.WarnContext(doc.Ctx, "Result cache: encode failed", "error", err)
+	} else if err := o.cache.Set(doc.Ctx, key, data, o.cacheTTL); err != nil {
+		slog.WarnContext(doc.Ctx, "Result cache: set failed", "error", err)
+	}
+	return result, nil
+}
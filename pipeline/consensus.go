@@ -0,0 +1,155 @@
+package pipeline
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// OCRCandidate is one engine's reading of a document in consensus OCR mode
+// ("ocr:paddle+tesseract").
+type OCRCandidate struct {
+	Engine     string
+	Text       string
+	PageTexts  []string
+	Words      []dto.OCRWord
+	Confidence float64 // 0-100
+}
+
+// fieldValidators recognize a correctly read value of a field (by json name);
+// in consensus mode a value that validates beats one that does not.
+var fieldValidators = map[string]*regexp.Regexp{
+	"pan":             regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`),
+	"employee_pan":    regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`),
+	"gstin":           regexp.MustCompile(`^[0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z]$`),
+	"ifsc":            regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`),
+	"aadhaar_last4":   regexp.MustCompile(`^[0-9]{4}$`),
+	"aadhaar_number":  regexp.MustCompile(`^[2-9][0-9]{11}$`),
+	"passport_number": regexp.MustCompile(`^[A-Z][0-9]{7}$`),
+	"epic_number":     regexp.MustCompile(`^[A-Z]{3}[0-9]{7}$`),
+	"dl_number":       regexp.MustCompile(`^[A-Z]{2}[- ]?[0-9]{2}[- ]?[0-9]{4}[- ]?[0-9]{7}$`),
+	"dob":             regexp.MustCompile(`^[0-9]{2}[/-][0-9]{2}[/-][0-9]{4}$`),
+	"account_number":  regexp.MustCompile(`^[0-9]{9,18}$`),
+}
+
+// runConsensus runs the parse step once per OCR candidate and merges the
+// results field by field (see mergeResults). doc keeps the best candidate's
+// text; its Result becomes the merged result.
+func runConsensus(doc *Doc, parse Step) error {
+	cands := append([]OCRCandidate(nil), doc.Candidates...)
+	sort.SliceStable(cands, func(i, j int) bool { return cands[i].Confidence > cands[j].Confidence })
+
+	prior := doc.Result
+	var results []interface{}
+	var engines []string
+	var firstErr error
+	for _, c := range cands {
+		alt := *doc
+		alt.Text, alt.PageTexts, alt.Words, alt.Result = c.Text, c.PageTexts, c.Words, prior
+		if err := parse.Run(&alt); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			continue
+		}
+		results = append(results, alt.Result)
+		engines = append(engines, c.Engine)
+	}
+	if len(results) == 0 {
+		return firstErr
+	}
+
+	doc.Result = mergeResults(results, engines)
+	return nil
+}
+
+// mergeResults combines parse results of the same type, ordered best engine
+// first. For every top-level field the value comes from the first engine whose
+// value passes the field's validator, else from the first engine with a
+// non-zero value; lists, maps and nested structs come whole from the first
+// engine that has them. A FieldSources map[string]string field on the result,
+// when present, is set to json field name -> engine.
+func mergeResults(results []interface{}, engines []string) interface{} {
+	base := reflect.ValueOf(results[0])
+	ptr := base.Kind() == reflect.Pointer
+	if ptr {
+		if base.IsNil() {
+			return results[0]
+		}
+		base = base.Elem()
+	}
+	if base.Kind() != reflect.Struct {
+		return results[0]
+	}
+
+	merged := reflect.New(base.Type()).Elem()
+	merged.Set(base)
+
+	values := make([]reflect.Value, len(results))
+	for i, r := range results {
+		v := reflect.ValueOf(r)
+		if ptr {
+			if v.IsNil() {
+				continue
+			}
+			v = v.Elem()
+		}
+		if v.Type() == base.Type() {
+			values[i] = v
+		}
+	}
+
+	sources := map[string]string{}
+	t := base.Type()
+	for f := 0; f < t.NumField(); f++ {
+		field := t.Field(f)
+		if !field.IsExported() || field.Name == "FieldSources" {
+			continue
+		}
+		name := jsonName(field)
+		validate := fieldValidators[name]
+
+		pick := -1
+		for i, v := range values {
+			if !v.IsValid() || v.Field(f).IsZero() {
+				continue
+			}
+			if pick < 0 {
+				pick = i
+			}
+			if validate == nil || field.Type.Kind() != reflect.String {
+				break
+			}
+			if validate.MatchString(v.Field(f).String()) {
+				pick = i
+				break
+			}
+		}
+		if pick < 0 {
+			continue
+		}
+		merged.Field(f).Set(values[pick].Field(f))
+		if name != "-" && name != "raw_text" {
+			sources[name] = engines[pick]
+		}
+	}
+
+	if fs := merged.FieldByName("FieldSources"); fs.IsValid() && fs.Type() == reflect.TypeOf(sources) {
+		fs.Set(reflect.ValueOf(sources))
+	}
+	if ptr {
+		return merged.Addr().Interface()
+	}
+	return merged.Interface()
+}
+
+func jsonName(f reflect.StructField) string {
+	name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+	if name == "" {
+		return f.Name
+	}
+	return name
+}
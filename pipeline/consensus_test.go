@@ -0,0 +1,33 @@
+package pipeline
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panResult struct {
+	PAN          string            `json:"pan"`
+	Name         string            `json:"name"`
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+}
+
+func TestConsensusPrefersValidatedFields(t *testing.T) {
+	parse := StepFunc(func(doc *Doc) error {
+		lines := strings.Split(doc.Text, "\n")
+		doc.Result = &panResult{PAN: lines[0], Name: lines[1]}
+		return nil
+	})
+	doc := &Doc{Candidates: []OCRCandidate{
+		{Engine: "tesseract", Text: "ABCDE1234F\n", Confidence: 70},
+		{Engine: "paddle", Text: "ABCDE12З4F\nRAVI KUMAR", Confidence: 90},
+	}}
+
+	require.NoError(t, runConsensus(doc, parse))
+	got := doc.Result.(*panResult)
+	assert.Equal(t, "ABCDE1234F", got.PAN)
+	assert.Equal(t, "RAVI KUMAR", got.Name)
+	assert.Equal(t, map[string]string{"pan": "tesseract", "name": "paddle"}, got.FieldSources)
+}
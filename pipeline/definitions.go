@@ -0,0 +1,86 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// DefaultPipelines are used for any document type a definitions file does not override.
+var DefaultPipelines = map[string][]string{
+	"salary_slip":     {"decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "employer", "validate", "score"},
+	"bank_statement":  {"spreadsheet", "aa", "decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "textlayer", "integrity", "validate", "score"},
+	"gst_return":      {"decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "score"},
+	"form_26as":       {"decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "score"},
+	"rent":            {"decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "score"},
+	"epf_passbook":    {"decrypt", "metadata", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse", "score"},
+	"aadhaar":         {"decrypt", "rasterize:pages=1-2", "qr", "orient", "ocr:paddle", "parse", "validate"},
+	"pan":             {"orient", "ocr:paddle", "parse"},
+	"driving_license": {"orient", "ocr:paddle|tesseract", "parse"},
+	"voter_id":        {"orient", "ocr:paddle|tesseract", "parse"},
+	"passport":        {"orient", "ocr:paddle|tesseract", "mrz", "parse"},
+	"address_proof":   {"decrypt", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "parse"},
+	"cheque":          {"decrypt", "rasterize:pages=1", "orient", "ocr:paddle|tesseract", "parse", "micr"},
+	// any document, read for caller-described fields
+	"document": {"decrypt", "pdftext", "rasterize", "orient", "ocr:paddle|tesseract", "score"},
+	// page images kept for POST /documents/redact; no text layer, every page is OCRed
+	"redact": {"decrypt", "rasterize", "pages", "orient", "ocr:paddle|tesseract"},
+}
+
+// Definitions hold the step lists per document type, with per-tenant overrides:
+//
+//	default:
+//	  salary_slip: [decrypt, metadata, pdftext, rasterize, "preprocess:binarize", "orient", "ocr:tesseract", parse, validate, score]
+//	tenants:
+//	  acme:
+//	    pan: ["orient", "ocr:paddle|tesseract", parse]
+type Definitions struct {
+	Default map[string][]string            `yaml:"default"`
+	Tenants map[string]map[string][]string `yaml:"tenants"`
+}
+
+// LoadFile reads pipeline definitions from path. A missing file yields the defaults.
+func LoadFile(path string) (*Definitions, error) {
+	defs := &Definitions{}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return defs, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pipelines: %w", err)
+	}
+	if err := yaml.UnmarshalWithOptions(data, defs, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid pipelines: %w", err)
+	}
+	return defs, nil
+}
+
+// StepsFor returns the step list for a tenant's document type: the tenant's own
+// definition, else the file default, else the built-in default.
+func (d *Definitions) StepsFor(tenantID, docType string) ([]string, bool) {
+	if d != nil {
+		if steps, ok := d.Tenants[tenantID][docType]; ok {
+			return steps, true
+		}
+		if steps, ok := d.Default[docType]; ok {
+			return steps, true
+		}
+	}
+	steps, ok := DefaultPipelines[docType]
+	return steps, ok
+}
+
+func (d *Definitions) tenantIDs() []string {
+	if d == nil {
+		return nil
+	}
+	ids := make([]string, 0, len(d.Tenants))
+	for id := range d.Tenants {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
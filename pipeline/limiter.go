@@ -0,0 +1,100 @@
+package pipeline
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Limiter is a counting semaphore bounding how many documents are OCRed at
+// once. Waiters queue until a slot frees up or their context is done, and the
+// limiter keeps queueing statistics for the health endpoint.
+type Limiter struct {
+	slots chan struct{}
+
+	mu        sync.Mutex
+	queued    int
+	completed int64
+	abandoned int64 // gave up waiting (request cancelled)
+	waitTotal time.Duration
+	waitMax   time.Duration
+}
+
+// LimiterStats is a snapshot of a Limiter.
+type LimiterStats struct {
+	Limit     int   `json:"limit"`
+	Running   int   `json:"running"`
+	Queued    int   `json:"queued"`
+	Completed int64 `json:"completed"`
+	Abandoned int64 `json:"abandoned"`
+	// AvgWaitMs and MaxWaitMs are the time documents spent queued for a slot
+	AvgWaitMs float64 `json:"avg_wait_ms"`
+	MaxWaitMs float64 `json:"max_wait_ms"`
+}
+
+// NewLimiter returns a limiter admitting n concurrent holders; n < 1 means 1.
+func NewLimiter(n int) *Limiter {
+	return &Limiter{slots: make(chan struct{}, max(n, 1))}
+}
+
+// Acquire waits for a slot until ctx is done. The returned func releases it.
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	start := time.Now()
+	select {
+	case l.slots <- struct{}{}:
+		l.record(0, false)
+		return l.release, nil
+	default:
+	}
+
+	l.mu.Lock()
+	l.queued++
+	l.mu.Unlock()
+
+	select {
+	case l.slots <- struct{}{}:
+		l.record(time.Since(start), true)
+		return l.release, nil
+	case <-ctx.Done():
+		l.mu.Lock()
+		l.queued--
+		l.abandoned++
+		l.mu.Unlock()
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Limiter) record(wait time.Duration, wasQueued bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if wasQueued {
+		l.queued--
+	}
+	l.waitTotal += wait
+	l.waitMax = max(l.waitMax, wait)
+}
+
+func (l *Limiter) release() {
+	<-l.slots
+	l.mu.Lock()
+	l.completed++
+	l.mu.Unlock()
+}
+
+// Stats returns the current occupancy and the queueing statistics so far.
+func (l *Limiter) Stats() LimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	st := LimiterStats{
+		Limit:     cap(l.slots),
+		Running:   len(l.slots),
+		Queued:    l.queued,
+		Completed: l.completed,
+		Abandoned: l.abandoned,
+		MaxWaitMs: float64(l.waitMax) / float64(time.Millisecond),
+	}
+	if admitted := l.completed + int64(st.Running); admitted > 0 {
+		st.AvgWaitMs = float64(l.waitTotal) / float64(time.Millisecond) / float64(admitted)
+	}
+	return st
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterQueuesBeyondLimit(t *testing.T) {
+	l := NewLimiter(1)
+	release, err := l.Acquire(context.Background())
+	require.NoError(t, err)
+
+	admitted := make(chan func())
+	go func() {
+		r, _ := l.Acquire(context.Background())
+		admitted <- r
+	}()
+	require.Eventually(t, func() bool { return l.Stats().Queued == 1 }, time.Second, time.Millisecond)
+	select {
+	case <-admitted:
+		t.Fatal("second holder admitted past the limit")
+	default:
+	}
+
+	release()
+	second := <-admitted
+	st := l.Stats()
+	assert.Equal(t, 1, st.Running)
+	assert.Equal(t, 0, st.Queued)
+	assert.Greater(t, st.MaxWaitMs, 0.0)
+	second()
+
+	// A waiter whose request is cancelled leaves the queue
+	release, _ = l.Acquire(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+	defer cancel()
+	_, err = l.Acquire(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	release()
+
+	st = l.Stats()
+	assert.Equal(t, 0, st.Queued)
+	assert.EqualValues(t, 3, st.Completed)
+	assert.EqualValues(t, 1, st.Abandoned)
+}
@@ -0,0 +1,273 @@
+// Package pipeline runs a document through an ordered list of named steps
+// (decrypt → rasterize → preprocess → ocr → parse → validate → score). Which
+// steps run is configured per document type and tenant; what each step does is
+// registered by the service that owns the document type.
+package pipeline
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"iter"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Doc is the state threaded through a pipeline. Steps read what earlier steps
+// produced and fill in their own part.
+type Doc struct {
+	Ctx      context.Context
+	TenantID string
+	DocType  string
+	Filename string
+	MimeType string
+	Password string
+	// PasswordHints, when set, derive passwords tried on a protected PDF
+	// whose Password is empty or wrong.
+	PasswordHints *dto.PasswordHints
+	// Lang is the OCR language hint ("hin+eng", "tam", ...); empty = engine default.
+	Lang string
+
+	// Inputs are the uploaded bytes: a single PDF or image, or several page images.
+	Inputs [][]byte
+	// Images are rendered PDF pages or decoded input images.
+	Images []image.Image
+	// Pages streams rendered PDF pages. The OCR step reads them one at a time so
+	// a long statement never has every page bitmap in memory; steps that need all
+	// pages at once collect them into Images instead.
+	Pages iter.Seq2[image.Image, error]
+	// OnPage, when set, receives each page's OCR text as soon as it is read, so
+	// parsing can proceed page by page alongside OCR.
+	OnPage func(text string)
+
+	Text      string
+	PageTexts []string
+	TextLayer bool // Text came from the PDF text layer rather than OCR
+	// Words are the OCRed words with their page and bounding box, for parsers
+	// that read layout (columns, label → value) rather than line order.
+	Words []dto.OCRWord
+	// Candidates hold each engine's reading in consensus OCR mode; Text,
+	// PageTexts and Words are then those of the most confident engine, and
+	// the parse step runs once per candidate with the results merged.
+	Candidates []OCRCandidate
+
+	ScanDate *time.Time
+	Quality  dto.DocumentQuality
+	Result   interface{}
+
+	// Provenance is the metadata forensics of a PDF input, including its
+	// information dictionary and fonts.
+	Provenance *dto.DocumentProvenance
+
+	// Done stops the pipeline after the current step (e.g. an Aadhaar QR code
+	// already yielded the full result).
+	Done bool
+	// Partial means the request's OCR budget ran out before every page was
+	// read; the result covers the pages read and is not cached.
+	Partial bool
+}
+
+// IsPDF reports whether the input is a single PDF.
+func (d *Doc) IsPDF() bool {
+	if len(d.Inputs) != 1 {
+		return false
+	}
+	return bytes.HasPrefix(d.Inputs[0], []byte("%PDF")) ||
+		strings.HasSuffix(strings.ToLower(d.Filename), ".pdf") ||
+		strings.Contains(d.MimeType, "pdf")
+}
+
+// Language returns the OCR language hint of the document, or of its request context.
+func (d *Doc) Language() string {
+	if d.Lang != "" || d.Ctx == nil {
+		return d.Lang
+	}
+	lang, _ := d.Ctx.Value(langKey{}).(string)
+	return lang
+}
+
+type langKey struct{}
+
+// WithLanguage attaches an OCR language hint to ctx for documents that do not set Lang.
+func WithLanguage(ctx context.Context, lang string) context.Context {
+	return context.WithValue(ctx, langKey{}, lang)
+}
+
+type photoKey struct{}
+
+// WithPhoto marks ctx as asking for the holder's portrait to be returned with
+// ID document results.
+func WithPhoto(ctx context.Context) context.Context {
+	return context.WithValue(ctx, photoKey{}, true)
+}
+
+// PhotoRequested reports whether ctx was marked by WithPhoto.
+func PhotoRequested(ctx context.Context) bool {
+	want, _ := ctx.Value(photoKey{}).(bool)
+	return want
+}
+
+type maskedImageKey struct{}
+
+// WithMaskedImage marks ctx as asking for Aadhaar results to carry a copy of
+// the card with the number masked.
+func WithMaskedImage(ctx context.Context) context.Context {
+	return context.WithValue(ctx, maskedImageKey{}, true)
+}
+
+// MaskedImageRequested reports whether ctx was marked by WithMaskedImage.
+func MaskedImageRequested(ctx context.Context) bool {
+	want, _ := ctx.Value(maskedImageKey{}).(bool)
+	return want
+}
+
+// AddIssue records a quality issue.
+func (d *Doc) AddIssue(issue string) {
+	d.Quality.Issues = append(d.Quality.Issues, issue)
+}
+
+// Step is one stage of a pipeline.
+type Step interface {
+	Run(doc *Doc) error
+}
+
+// StepFunc adapts a function to Step.
+type StepFunc func(doc *Doc) error
+
+func (f StepFunc) Run(doc *Doc) error { return f(doc) }
+
+// Factory builds a step from the argument after the colon in its definition,
+// e.g. "ocr:paddle|tesseract" calls the "ocr" factory with "paddle|tesseract".
+type Factory func(arg string) (Step, error)
+
+// Registry maps step names to factories.
+type Registry map[string]Factory
+
+// With returns a copy of r extended (or overridden) by extra.
+func (r Registry) With(extra Registry) Registry {
+	out := make(Registry, len(r)+len(extra))
+	for k, v := range r {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
+
+// Orchestrator resolves the pipeline for a document and runs it.
+type Orchestrator struct {
+	defs     *Definitions
+	registry Registry
+
+	cache    cache.Cache // nil = results are not cached
+	cacheTTL time.Duration
+
+	// limiter bounds the documents running at once; nil = unbounded
+	limiter *Limiter
+}
+
+// NewOrchestrator creates an orchestrator over the shared step registry.
+func NewOrchestrator(defs *Definitions, registry Registry) *Orchestrator {
+	return &Orchestrator{defs: defs, registry: registry}
+}
+
+// Extend returns an orchestrator that additionally knows a service's own steps
+// (typically parse and validate). It checks that every pipeline defined for
+// docTypes, for every tenant, only uses known steps, so misconfiguration fails
+// at startup rather than per request.
+func (o *Orchestrator) Extend(extra Registry, docTypes ...string) (*Orchestrator, error) {
+	ext := &Orchestrator{defs: o.defs, registry: o.registry.With(extra), cache: o.cache, cacheTTL: o.cacheTTL, limiter: o.limiter}
+	for _, docType := range docTypes {
+		for _, tenant := range append([]string{""}, o.defs.tenantIDs()...) {
+			if _, err := ext.build(tenant, docType); err != nil {
+				if tenant != "" {
+					return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+				}
+				return nil, err
+			}
+		}
+	}
+	return ext, nil
+}
+
+// WithLimiter returns an orchestrator that runs at most l's limit of documents
+// at once; the others queue in Run. Orchestrators derived from it with Extend
+// share the limiter.
+func (o *Orchestrator) WithLimiter(l *Limiter) *Orchestrator {
+	return &Orchestrator{defs: o.defs, registry: o.registry, cache: o.cache, cacheTTL: o.cacheTTL, limiter: l}
+}
+
+// Acquire takes a slot of the orchestrator's limiter for OCR work done outside
+// Run. The returned func releases it.
+func (o *Orchestrator) Acquire(ctx context.Context) (func(), error) {
+	if o == nil || o.limiter == nil {
+		return func() {}, nil
+	}
+	return o.limiter.Acquire(ctx)
+}
+
+// Run executes the pipeline configured for doc.TenantID and doc.DocType,
+// first waiting for a slot when the orchestrator has a limiter.
+func (o *Orchestrator) Run(doc *Doc) error {
+	steps, err := o.build(doc.TenantID, doc.DocType)
+	if err != nil {
+		return err
+	}
+	if doc.Ctx == nil {
+		doc.Ctx = context.Background()
+	}
+	release, err := o.Acquire(doc.Ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	for _, s := range steps {
+		if err := doc.Ctx.Err(); err != nil {
+			return err
+		}
+		run := s.step.Run
+		if s.name == "parse" && len(doc.Candidates) > 1 {
+			run = func(doc *Doc) error { return runConsensus(doc, s.step) }
+		}
+		if err := run(doc); err != nil {
+			return fmt.Errorf("%s: %w", s.name, err)
+		}
+		if doc.Done {
+			break
+		}
+	}
+	return nil
+}
+
+type namedStep struct {
+	name string
+	step Step
+}
+
+func (o *Orchestrator) build(tenantID, docType string) ([]namedStep, error) {
+	defs, ok := o.defs.StepsFor(tenantID, docType)
+	if !ok {
+		return nil, fmt.Errorf("no pipeline defined for document type %q", docType)
+	}
+
+	steps := make([]namedStep, 0, len(defs))
+	for _, def := range defs {
+		name, arg, _ := strings.Cut(def, ":")
+		factory, ok := o.registry[name]
+		if !ok {
+			return nil, fmt.Errorf("%s pipeline: unknown step %q", docType, name)
+		}
+		step, err := factory(arg)
+		if err != nil {
+			return nil, fmt.Errorf("%s pipeline: step %q: %w", docType, def, err)
+		}
+		steps = append(steps, namedStep{name: def, step: step})
+	}
+	return steps, nil
+}
@@ -0,0 +1,127 @@
+package pipeline
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/cache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func recorder(trace *[]string) Registry {
+	step := func(name string) Factory {
+		return func(arg string) (Step, error) {
+			return StepFunc(func(doc *Doc) error {
+				*trace = append(*trace, name+arg)
+				if name == "qr" {
+					doc.Done = true
+				}
+				return nil
+			}), nil
+		}
+	}
+	return Registry{"orient": step("orient"), "ocr": step("ocr"), "parse": step("parse"), "qr": step("qr")}
+}
+
+func TestOrchestratorRunsTenantPipeline(t *testing.T) {
+	var trace []string
+	defs := &Definitions{
+		Default: map[string][]string{"pan": {"ocr:paddle", "parse"}},
+		Tenants: map[string]map[string][]string{"acme": {"pan": {"ocr:tesseract", "parse"}}},
+	}
+	o, err := NewOrchestrator(defs, recorder(&trace)).Extend(nil, "pan")
+	require.NoError(t, err)
+
+	require.NoError(t, o.Run(&Doc{DocType: "pan"}))
+	require.NoError(t, o.Run(&Doc{DocType: "pan", TenantID: "acme"}))
+	assert.Equal(t, []string{"ocrpaddle", "parse", "ocrtesseract", "parse"}, trace)
+}
+
+func TestOrchestratorStopsWhenDone(t *testing.T) {
+	var trace []string
+	defs := &Definitions{Default: map[string][]string{"aadhaar": {"qr", "ocr", "parse"}}}
+	o, err := NewOrchestrator(defs, recorder(&trace)).Extend(nil, "aadhaar")
+	require.NoError(t, err)
+
+	require.NoError(t, o.Run(&Doc{DocType: "aadhaar"}))
+	assert.Equal(t, []string{"qr"}, trace)
+}
+
+func TestExtendRejectsUnknownSteps(t *testing.T) {
+	var trace []string
+	defs := &Definitions{Tenants: map[string]map[string][]string{"acme": {"pan": {"ocr", "sharpen"}}}}
+
+	_, err := NewOrchestrator(defs, recorder(&trace)).Extend(nil, "pan")
+	assert.ErrorContains(t, err, "tenant acme")
+
+	_, err = NewOrchestrator(&Definitions{}, recorder(&trace)).Extend(nil, "utility_bill")
+	assert.ErrorContains(t, err, "no pipeline defined")
+}
+
+func TestShippedPipelinesLoad(t *testing.T) {
+	defs, err := LoadFile("../pipelines.yaml")
+	require.NoError(t, err)
+
+	steps, ok := defs.StepsFor("example-tenant", "salary_slip")
+	require.True(t, ok)
+	assert.Contains(t, steps, "preprocess:binarize")
+
+	steps, _ = defs.StepsFor("example-tenant", "bank_statement")
+	assert.Equal(t, DefaultPipelines["bank_statement"], steps)
+}
+
+func TestCachedServesIdenticalInputs(t *testing.T) {
+	o := NewOrchestrator(&Definitions{}, Registry{}).WithCache(cache.NewLRU(10), time.Hour)
+	runs := 0
+	run := func() (*struct{ PAN string }, error) {
+		runs++
+		return &struct{ PAN string }{PAN: "ABCPS1234K"}, nil
+	}
+
+	_, err := Cached(o, &Doc{DocType: "pan", Inputs: [][]byte{[]byte("img")}}, run)
+	require.NoError(t, err)
+	_, err = Cached(o, &Doc{DocType: "pan", Inputs: [][]byte{[]byte("img")}}, run)
+	require.NoError(t, err)
+	assert.Equal(t, 1, runs)
+
+	_, _ = Cached(o, &Doc{DocType: "pan", Inputs: [][]byte{[]byte("img")}, Password: "x"}, run)
+	assert.Equal(t, 2, runs, "password is part of the key")
+
+	_, _ = Cached(o, &Doc{Ctx: WithoutCache(context.Background()), DocType: "pan", Inputs: [][]byte{[]byte("img")}}, run)
+	assert.Equal(t, 3, runs, "opt-out skips the cache")
+}
+
+func TestCachedSkipsPartialResults(t *testing.T) {
+	o := NewOrchestrator(&Definitions{}, Registry{}).WithCache(cache.NewLRU(10), time.Hour)
+	runs := 0
+	doc := func() *Doc { return &Doc{DocType: "itr", Inputs: [][]byte{[]byte("pdf")}} }
+	partial := doc()
+	_, err := Cached(o, partial, func() (*struct{ Pages int }, error) {
+		runs++
+		partial.Partial = true
+		return &struct{ Pages int }{Pages: 1}, nil
+	})
+	require.NoError(t, err)
+
+	got, err := Cached(o, doc(), func() (*struct{ Pages int }, error) {
+		runs++
+		return &struct{ Pages int }{Pages: 3}, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, runs)
+	assert.Equal(t, 3, got.Pages)
+}
+
+func TestOCRBudget(t *testing.T) {
+	assert.False(t, OCRBudgetFrom(context.Background()).Spent(), "no budget never runs out")
+	assert.False(t, OCRBudgetFrom(context.Background()).Exhausted())
+
+	b := NewOCRBudget(-time.Millisecond)
+	ctx := WithOCRBudget(context.Background(), b)
+	assert.True(t, OCRBudgetFrom(ctx).Spent())
+	assert.False(t, b.Exhausted(), "only exhausted once pages were left unread")
+	b.Exhaust()
+	assert.True(t, OCRBudgetFrom(ctx).Exhausted())
+}
@@ -0,0 +1,649 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: proto/ocr/v1/ocr.proto
+
+// OCR income verification and KYC extraction over gRPC, for internal services.
+// Operations match the REST endpoints under /api/v1 and return the same JSON
+// documents, so callers can share response types with REST clients.
+
+package ocrv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type Operation int32
+
+const (
+	Operation_OPERATION_UNSPECIFIED     Operation = 0
+	Operation_OPERATION_VERIFY_INCOME   Operation = 1
+	Operation_OPERATION_EXTRACT_AADHAAR Operation = 2
+	Operation_OPERATION_EXTRACT_PAN     Operation = 3
+	Operation_OPERATION_ANALYZE_ITR     Operation = 4
+	Operation_OPERATION_EXTRACT_DL      Operation = 5
+)
+
+// Enum value maps for Operation.
+var (
+	Operation_name = map[int32]string{
+		0: "OPERATION_UNSPECIFIED",
+		1: "OPERATION_VERIFY_INCOME",
+		2: "OPERATION_EXTRACT_AADHAAR",
+		3: "OPERATION_EXTRACT_PAN",
+		4: "OPERATION_ANALYZE_ITR",
+		5: "OPERATION_EXTRACT_DL",
+	}
+	Operation_value = map[string]int32{
+		"OPERATION_UNSPECIFIED":     0,
+		"OPERATION_VERIFY_INCOME":   1,
+		"OPERATION_EXTRACT_AADHAAR": 2,
+		"OPERATION_EXTRACT_PAN":     3,
+		"OPERATION_ANALYZE_ITR":     4,
+		"OPERATION_EXTRACT_DL":      5,
+	}
+)
+
+func (x Operation) Enum() *Operation {
+	p := new(Operation)
+	*p = x
+	return p
+}
+
+func (x Operation) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Operation) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_ocr_v1_ocr_proto_enumTypes[0].Descriptor()
+}
+
+func (Operation) Type() protoreflect.EnumType {
+	return &file_proto_ocr_v1_ocr_proto_enumTypes[0]
+}
+
+func (x Operation) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Operation.Descriptor instead.
+func (Operation) EnumDescriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{0}
+}
+
+type File struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"` // the extension tells PDFs from images
+	Content       []byte                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *File) Reset() {
+	*x = File{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *File) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*File) ProtoMessage() {}
+
+func (x *File) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use File.ProtoReflect.Descriptor instead.
+func (*File) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *File) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *File) GetContent() []byte {
+	if x != nil {
+		return x.Content
+	}
+	return nil
+}
+
+type DocumentRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// One file; Aadhaar also takes front and back images as two files.
+	Files         []*File `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	Password      string  `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"` // for protected PDFs
+	Lang          string  `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"`         // OCR language hint, e.g. "hin+eng"
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DocumentRequest) Reset() {
+	*x = DocumentRequest{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DocumentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DocumentRequest) ProtoMessage() {}
+
+func (x *DocumentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DocumentRequest.ProtoReflect.Descriptor instead.
+func (*DocumentRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DocumentRequest) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *DocumentRequest) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *DocumentRequest) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+type VerifyIncomeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Files []*File                `protobuf:"bytes,1,rep,name=files,proto3" json:"files,omitempty"`
+	// UploadMetadata as JSON, as the REST "metadata" field
+	MetadataJson  string `protobuf:"bytes,2,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"`
+	TenantId      string `protobuf:"bytes,3,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *VerifyIncomeRequest) Reset() {
+	*x = VerifyIncomeRequest{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *VerifyIncomeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*VerifyIncomeRequest) ProtoMessage() {}
+
+func (x *VerifyIncomeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use VerifyIncomeRequest.ProtoReflect.Descriptor instead.
+func (*VerifyIncomeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *VerifyIncomeRequest) GetFiles() []*File {
+	if x != nil {
+		return x.Files
+	}
+	return nil
+}
+
+func (x *VerifyIncomeRequest) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *VerifyIncomeRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type Result struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The operation's response document, identical to the REST response body
+	Json          string `protobuf:"bytes,1,opt,name=json,proto3" json:"json,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Result) Reset() {
+	*x = Result{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Result) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Result) ProtoMessage() {}
+
+func (x *Result) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Result) GetJson() string {
+	if x != nil {
+		return x.Json
+	}
+	return ""
+}
+
+type UploadStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Operation     Operation              `protobuf:"varint,1,opt,name=operation,proto3,enum=ocr.v1.Operation" json:"operation,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Lang          string                 `protobuf:"bytes,3,opt,name=lang,proto3" json:"lang,omitempty"`
+	MetadataJson  string                 `protobuf:"bytes,4,opt,name=metadata_json,json=metadataJson,proto3" json:"metadata_json,omitempty"` // VerifyIncome only
+	TenantId      string                 `protobuf:"bytes,5,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`             // VerifyIncome only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadStart) Reset() {
+	*x = UploadStart{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadStart) ProtoMessage() {}
+
+func (x *UploadStart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadStart.ProtoReflect.Descriptor instead.
+func (*UploadStart) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *UploadStart) GetOperation() Operation {
+	if x != nil {
+		return x.Operation
+	}
+	return Operation_OPERATION_UNSPECIFIED
+}
+
+func (x *UploadStart) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *UploadStart) GetLang() string {
+	if x != nil {
+		return x.Lang
+	}
+	return ""
+}
+
+func (x *UploadStart) GetMetadataJson() string {
+	if x != nil {
+		return x.MetadataJson
+	}
+	return ""
+}
+
+func (x *UploadStart) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type FileStart struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *FileStart) Reset() {
+	*x = FileStart{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *FileStart) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*FileStart) ProtoMessage() {}
+
+func (x *FileStart) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use FileStart.ProtoReflect.Descriptor instead.
+func (*FileStart) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *FileStart) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+type UploadChunk struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Part:
+	//
+	//	*UploadChunk_Start
+	//	*UploadChunk_File
+	//	*UploadChunk_Data
+	Part          isUploadChunk_Part `protobuf_oneof:"part"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadChunk) Reset() {
+	*x = UploadChunk{}
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadChunk) ProtoMessage() {}
+
+func (x *UploadChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_ocr_v1_ocr_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadChunk.ProtoReflect.Descriptor instead.
+func (*UploadChunk) Descriptor() ([]byte, []int) {
+	return file_proto_ocr_v1_ocr_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *UploadChunk) GetPart() isUploadChunk_Part {
+	if x != nil {
+		return x.Part
+	}
+	return nil
+}
+
+func (x *UploadChunk) GetStart() *UploadStart {
+	if x != nil {
+		if x, ok := x.Part.(*UploadChunk_Start); ok {
+			return x.Start
+		}
+	}
+	return nil
+}
+
+func (x *UploadChunk) GetFile() *FileStart {
+	if x != nil {
+		if x, ok := x.Part.(*UploadChunk_File); ok {
+			return x.File
+		}
+	}
+	return nil
+}
+
+func (x *UploadChunk) GetData() []byte {
+	if x != nil {
+		if x, ok := x.Part.(*UploadChunk_Data); ok {
+			return x.Data
+		}
+	}
+	return nil
+}
+
+type isUploadChunk_Part interface {
+	isUploadChunk_Part()
+}
+
+type UploadChunk_Start struct {
+	Start *UploadStart `protobuf:"bytes,1,opt,name=start,proto3,oneof"`
+}
+
+type UploadChunk_File struct {
+	File *FileStart `protobuf:"bytes,2,opt,name=file,proto3,oneof"`
+}
+
+type UploadChunk_Data struct {
+	Data []byte `protobuf:"bytes,3,opt,name=data,proto3,oneof"`
+}
+
+func (*UploadChunk_Start) isUploadChunk_Part() {}
+
+func (*UploadChunk_File) isUploadChunk_Part() {}
+
+func (*UploadChunk_Data) isUploadChunk_Part() {}
+
+var File_proto_ocr_v1_ocr_proto protoreflect.FileDescriptor
+
+const file_proto_ocr_v1_ocr_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/ocr/v1/ocr.proto\x12\x06ocr.v1\"<\n" +
+	"\x04File\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12\x18\n" +
+	"\acontent\x18\x02 \x01(\fR\acontent\"e\n" +
+	"\x0fDocumentRequest\x12\"\n" +
+	"\x05files\x18\x01 \x03(\v2\f.ocr.v1.FileR\x05files\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04lang\x18\x03 \x01(\tR\x04lang\"{\n" +
+	"\x13VerifyIncomeRequest\x12\"\n" +
+	"\x05files\x18\x01 \x03(\v2\f.ocr.v1.FileR\x05files\x12#\n" +
+	"\rmetadata_json\x18\x02 \x01(\tR\fmetadataJson\x12\x1b\n" +
+	"\ttenant_id\x18\x03 \x01(\tR\btenantId\"\x1c\n" +
+	"\x06Result\x12\x12\n" +
+	"\x04json\x18\x01 \x01(\tR\x04json\"\xb0\x01\n" +
+	"\vUploadStart\x12/\n" +
+	"\toperation\x18\x01 \x01(\x0e2\x11.ocr.v1.OperationR\toperation\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x12\n" +
+	"\x04lang\x18\x03 \x01(\tR\x04lang\x12#\n" +
+	"\rmetadata_json\x18\x04 \x01(\tR\fmetadataJson\x12\x1b\n" +
+	"\ttenant_id\x18\x05 \x01(\tR\btenantId\"'\n" +
+	"\tFileStart\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\"\x81\x01\n" +
+	"\vUploadChunk\x12+\n" +
+	"\x05start\x18\x01 \x01(\v2\x13.ocr.v1.UploadStartH\x00R\x05start\x12'\n" +
+	"\x04file\x18\x02 \x01(\v2\x11.ocr.v1.FileStartH\x00R\x04file\x12\x14\n" +
+	"\x04data\x18\x03 \x01(\fH\x00R\x04dataB\x06\n" +
+	"\x04part*\xb2\x01\n" +
+	"\tOperation\x12\x19\n" +
+	"\x15OPERATION_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17OPERATION_VERIFY_INCOME\x10\x01\x12\x1d\n" +
+	"\x19OPERATION_EXTRACT_AADHAAR\x10\x02\x12\x19\n" +
+	"\x15OPERATION_EXTRACT_PAN\x10\x03\x12\x19\n" +
+	"\x15OPERATION_ANALYZE_ITR\x10\x04\x12\x18\n" +
+	"\x14OPERATION_EXTRACT_DL\x10\x052\xd9\x02\n" +
+	"\n" +
+	"OCRService\x12;\n" +
+	"\fVerifyIncome\x12\x1b.ocr.v1.VerifyIncomeRequest\x1a\x0e.ocr.v1.Result\x129\n" +
+	"\x0eExtractAadhaar\x12\x17.ocr.v1.DocumentRequest\x1a\x0e.ocr.v1.Result\x125\n" +
+	"\n" +
+	"ExtractPAN\x12\x17.ocr.v1.DocumentRequest\x1a\x0e.ocr.v1.Result\x125\n" +
+	"\n" +
+	"AnalyzeITR\x12\x17.ocr.v1.DocumentRequest\x1a\x0e.ocr.v1.Result\x124\n" +
+	"\tExtractDL\x12\x17.ocr.v1.DocumentRequest\x1a\x0e.ocr.v1.Result\x12/\n" +
+	"\x06Upload\x12\x13.ocr.v1.UploadChunk\x1a\x0e.ocr.v1.Result(\x01BDZBgithub.com/Aashish23092/ocr-income-verification/proto/ocr/v1;ocrv1b\x06proto3"
+
+var (
+	file_proto_ocr_v1_ocr_proto_rawDescOnce sync.Once
+	file_proto_ocr_v1_ocr_proto_rawDescData []byte
+)
+
+func file_proto_ocr_v1_ocr_proto_rawDescGZIP() []byte {
+	file_proto_ocr_v1_ocr_proto_rawDescOnce.Do(func() {
+		file_proto_ocr_v1_ocr_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_ocr_v1_ocr_proto_rawDesc), len(file_proto_ocr_v1_ocr_proto_rawDesc)))
+	})
+	return file_proto_ocr_v1_ocr_proto_rawDescData
+}
+
+var file_proto_ocr_v1_ocr_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_proto_ocr_v1_ocr_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_proto_ocr_v1_ocr_proto_goTypes = []any{
+	(Operation)(0),              // 0: ocr.v1.Operation
+	(*File)(nil),                // 1: ocr.v1.File
+	(*DocumentRequest)(nil),     // 2: ocr.v1.DocumentRequest
+	(*VerifyIncomeRequest)(nil), // 3: ocr.v1.VerifyIncomeRequest
+	(*Result)(nil),              // 4: ocr.v1.Result
+	(*UploadStart)(nil),         // 5: ocr.v1.UploadStart
+	(*FileStart)(nil),           // 6: ocr.v1.FileStart
+	(*UploadChunk)(nil),         // 7: ocr.v1.UploadChunk
+}
+var file_proto_ocr_v1_ocr_proto_depIdxs = []int32{
+	1,  // 0: ocr.v1.DocumentRequest.files:type_name -> ocr.v1.File
+	1,  // 1: ocr.v1.VerifyIncomeRequest.files:type_name -> ocr.v1.File
+	0,  // 2: ocr.v1.UploadStart.operation:type_name -> ocr.v1.Operation
+	5,  // 3: ocr.v1.UploadChunk.start:type_name -> ocr.v1.UploadStart
+	6,  // 4: ocr.v1.UploadChunk.file:type_name -> ocr.v1.FileStart
+	3,  // 5: ocr.v1.OCRService.VerifyIncome:input_type -> ocr.v1.VerifyIncomeRequest
+	2,  // 6: ocr.v1.OCRService.ExtractAadhaar:input_type -> ocr.v1.DocumentRequest
+	2,  // 7: ocr.v1.OCRService.ExtractPAN:input_type -> ocr.v1.DocumentRequest
+	2,  // 8: ocr.v1.OCRService.AnalyzeITR:input_type -> ocr.v1.DocumentRequest
+	2,  // 9: ocr.v1.OCRService.ExtractDL:input_type -> ocr.v1.DocumentRequest
+	7,  // 10: ocr.v1.OCRService.Upload:input_type -> ocr.v1.UploadChunk
+	4,  // 11: ocr.v1.OCRService.VerifyIncome:output_type -> ocr.v1.Result
+	4,  // 12: ocr.v1.OCRService.ExtractAadhaar:output_type -> ocr.v1.Result
+	4,  // 13: ocr.v1.OCRService.ExtractPAN:output_type -> ocr.v1.Result
+	4,  // 14: ocr.v1.OCRService.AnalyzeITR:output_type -> ocr.v1.Result
+	4,  // 15: ocr.v1.OCRService.ExtractDL:output_type -> ocr.v1.Result
+	4,  // 16: ocr.v1.OCRService.Upload:output_type -> ocr.v1.Result
+	11, // [11:17] is the sub-list for method output_type
+	5,  // [5:11] is the sub-list for method input_type
+	5,  // [5:5] is the sub-list for extension type_name
+	5,  // [5:5] is the sub-list for extension extendee
+	0,  // [0:5] is the sub-list for field type_name
+}
+
+func init() { file_proto_ocr_v1_ocr_proto_init() }
+func file_proto_ocr_v1_ocr_proto_init() {
+	if File_proto_ocr_v1_ocr_proto != nil {
+		return
+	}
+	file_proto_ocr_v1_ocr_proto_msgTypes[6].OneofWrappers = []any{
+		(*UploadChunk_Start)(nil),
+		(*UploadChunk_File)(nil),
+		(*UploadChunk_Data)(nil),
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_ocr_v1_ocr_proto_rawDesc), len(file_proto_ocr_v1_ocr_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   7,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_proto_ocr_v1_ocr_proto_goTypes,
+		DependencyIndexes: file_proto_ocr_v1_ocr_proto_depIdxs,
+		EnumInfos:         file_proto_ocr_v1_ocr_proto_enumTypes,
+		MessageInfos:      file_proto_ocr_v1_ocr_proto_msgTypes,
+	}.Build()
+	File_proto_ocr_v1_ocr_proto = out.File
+	file_proto_ocr_v1_ocr_proto_goTypes = nil
+	file_proto_ocr_v1_ocr_proto_depIdxs = nil
+}
@@ -0,0 +1,325 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: proto/ocr/v1/ocr.proto
+
+// OCR income verification and KYC extraction over gRPC, for internal services.
+// Operations match the REST endpoints under /api/v1 and return the same JSON
+// documents, so callers can share response types with REST clients.
+
+package ocrv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	OCRService_VerifyIncome_FullMethodName   = "/ocr.v1.OCRService/VerifyIncome"
+	OCRService_ExtractAadhaar_FullMethodName = "/ocr.v1.OCRService/ExtractAadhaar"
+	OCRService_ExtractPAN_FullMethodName     = "/ocr.v1.OCRService/ExtractPAN"
+	OCRService_AnalyzeITR_FullMethodName     = "/ocr.v1.OCRService/AnalyzeITR"
+	OCRService_ExtractDL_FullMethodName      = "/ocr.v1.OCRService/ExtractDL"
+	OCRService_Upload_FullMethodName         = "/ocr.v1.OCRService/Upload"
+)
+
+// OCRServiceClient is the client API for OCRService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type OCRServiceClient interface {
+	// POST /api/v1/income/verify
+	VerifyIncome(ctx context.Context, in *VerifyIncomeRequest, opts ...grpc.CallOption) (*Result, error)
+	// POST /api/v1/aadhaar/extract
+	ExtractAadhaar(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error)
+	// POST /api/v1/pan/ocr
+	ExtractPAN(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error)
+	// POST /api/v1/itr/analyze
+	AnalyzeITR(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error)
+	// POST /api/v1/driving-license/ocr
+	ExtractDL(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error)
+	// Upload streams the documents of any operation in chunks instead of one
+	// message: an UploadStart, then for each file a FileStart followed by the
+	// file's content in data chunks.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, Result], error)
+}
+
+type oCRServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOCRServiceClient(cc grpc.ClientConnInterface) OCRServiceClient {
+	return &oCRServiceClient{cc}
+}
+
+func (c *oCRServiceClient) VerifyIncome(ctx context.Context, in *VerifyIncomeRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, OCRService_VerifyIncome_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCRServiceClient) ExtractAadhaar(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, OCRService_ExtractAadhaar_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCRServiceClient) ExtractPAN(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, OCRService_ExtractPAN_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCRServiceClient) AnalyzeITR(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, OCRService_AnalyzeITR_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCRServiceClient) ExtractDL(ctx context.Context, in *DocumentRequest, opts ...grpc.CallOption) (*Result, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Result)
+	err := c.cc.Invoke(ctx, OCRService_ExtractDL_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *oCRServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadChunk, Result], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OCRService_ServiceDesc.Streams[0], OCRService_Upload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadChunk, Result]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OCRService_UploadClient = grpc.ClientStreamingClient[UploadChunk, Result]
+
+// OCRServiceServer is the server API for OCRService service.
+// All implementations must embed UnimplementedOCRServiceServer
+// for forward compatibility.
+type OCRServiceServer interface {
+	// POST /api/v1/income/verify
+	VerifyIncome(context.Context, *VerifyIncomeRequest) (*Result, error)
+	// POST /api/v1/aadhaar/extract
+	ExtractAadhaar(context.Context, *DocumentRequest) (*Result, error)
+	// POST /api/v1/pan/ocr
+	ExtractPAN(context.Context, *DocumentRequest) (*Result, error)
+	// POST /api/v1/itr/analyze
+	AnalyzeITR(context.Context, *DocumentRequest) (*Result, error)
+	// POST /api/v1/driving-license/ocr
+	ExtractDL(context.Context, *DocumentRequest) (*Result, error)
+	// Upload streams the documents of any operation in chunks instead of one
+	// message: an UploadStart, then for each file a FileStart followed by the
+	// file's content in data chunks.
+	Upload(grpc.ClientStreamingServer[UploadChunk, Result]) error
+	mustEmbedUnimplementedOCRServiceServer()
+}
+
+// UnimplementedOCRServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOCRServiceServer struct{}
+
+func (UnimplementedOCRServiceServer) VerifyIncome(context.Context, *VerifyIncomeRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyIncome not implemented")
+}
+func (UnimplementedOCRServiceServer) ExtractAadhaar(context.Context, *DocumentRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractAadhaar not implemented")
+}
+func (UnimplementedOCRServiceServer) ExtractPAN(context.Context, *DocumentRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractPAN not implemented")
+}
+func (UnimplementedOCRServiceServer) AnalyzeITR(context.Context, *DocumentRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeITR not implemented")
+}
+func (UnimplementedOCRServiceServer) ExtractDL(context.Context, *DocumentRequest) (*Result, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExtractDL not implemented")
+}
+func (UnimplementedOCRServiceServer) Upload(grpc.ClientStreamingServer[UploadChunk, Result]) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedOCRServiceServer) mustEmbedUnimplementedOCRServiceServer() {}
+func (UnimplementedOCRServiceServer) testEmbeddedByValue()                    {}
+
+// UnsafeOCRServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OCRServiceServer will
+// result in compilation errors.
+type UnsafeOCRServiceServer interface {
+	mustEmbedUnimplementedOCRServiceServer()
+}
+
+func RegisterOCRServiceServer(s grpc.ServiceRegistrar, srv OCRServiceServer) {
+	// If the following call pancis, it indicates UnimplementedOCRServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&OCRService_ServiceDesc, srv)
+}
+
+func _OCRService_VerifyIncome_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyIncomeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OCRServiceServer).VerifyIncome(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OCRService_VerifyIncome_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCRServiceServer).VerifyIncome(ctx, req.(*VerifyIncomeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OCRService_ExtractAadhaar_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OCRServiceServer).ExtractAadhaar(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OCRService_ExtractAadhaar_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCRServiceServer).ExtractAadhaar(ctx, req.(*DocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OCRService_ExtractPAN_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OCRServiceServer).ExtractPAN(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OCRService_ExtractPAN_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCRServiceServer).ExtractPAN(ctx, req.(*DocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OCRService_AnalyzeITR_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OCRServiceServer).AnalyzeITR(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OCRService_AnalyzeITR_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCRServiceServer).AnalyzeITR(ctx, req.(*DocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OCRService_ExtractDL_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DocumentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OCRServiceServer).ExtractDL(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OCRService_ExtractDL_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OCRServiceServer).ExtractDL(ctx, req.(*DocumentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OCRService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(OCRServiceServer).Upload(&grpc.GenericServerStream[UploadChunk, Result]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OCRService_UploadServer = grpc.ClientStreamingServer[UploadChunk, Result]
+
+// OCRService_ServiceDesc is the grpc.ServiceDesc for OCRService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var OCRService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "ocr.v1.OCRService",
+	HandlerType: (*OCRServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "VerifyIncome",
+			Handler:    _OCRService_VerifyIncome_Handler,
+		},
+		{
+			MethodName: "ExtractAadhaar",
+			Handler:    _OCRService_ExtractAadhaar_Handler,
+		},
+		{
+			MethodName: "ExtractPAN",
+			Handler:    _OCRService_ExtractPAN_Handler,
+		},
+		{
+			MethodName: "AnalyzeITR",
+			Handler:    _OCRService_AnalyzeITR_Handler,
+		},
+		{
+			MethodName: "ExtractDL",
+			Handler:    _OCRService_ExtractDL_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _OCRService_Upload_Handler,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "proto/ocr/v1/ocr.proto",
+}
@@ -0,0 +1,79 @@
+package scan
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+)
+
+// clamdChunk is the size of the INSTREAM chunks sent to clamd.
+const clamdChunk = 64 << 10
+
+// ClamAV scans with a clamd daemon over its INSTREAM command, so the daemon
+// needs no access to the service's files.
+type ClamAV struct {
+	Addr    string // "host:port" or "unix:/path/to/clamd.sock"
+	Timeout time.Duration
+}
+
+func (c *ClamAV) Scan(ctx context.Context, name string, data []byte) (Verdict, error) {
+	network, addr := "tcp", c.Addr
+	if path, ok := strings.CutPrefix(c.Addr, "unix:"); ok {
+		network, addr = "unix", strings.TrimPrefix(path, "//")
+	}
+	ctx, cancel := context.WithTimeout(ctx, c.Timeout)
+	defer cancel()
+	var d net.Dialer
+	conn, err := d.DialContext(ctx, network, addr)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// z-prefixed commands are NUL-terminated, and so are their replies
+	if _, err := conn.Write([]byte("zINSTREAM\x00")); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	size := make([]byte, 4)
+	for len(data) > 0 {
+		n := min(len(data), clamdChunk)
+		binary.BigEndian.PutUint32(size, uint32(n))
+		if _, err := conn.Write(size); err != nil {
+			return Verdict{}, fmt.Errorf("clamd: %w", err)
+		}
+		if _, err := conn.Write(data[:n]); err != nil {
+			return Verdict{}, fmt.Errorf("clamd: %w", err)
+		}
+		data = data[n:]
+	}
+	binary.BigEndian.PutUint32(size, 0)
+	if _, err := conn.Write(size); err != nil {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+
+	reply, err := bufio.NewReader(conn).ReadString(0)
+	if err != nil && reply == "" {
+		return Verdict{}, fmt.Errorf("clamd: %w", err)
+	}
+	return parseClamdReply(strings.TrimRight(reply, "\x00\n"))
+}
+
+// parseClamdReply reads "stream: OK", "stream: <threat> FOUND" or
+// "<message> ERROR" (such as the stream size limit being exceeded).
+func parseClamdReply(reply string) (Verdict, error) {
+	result := strings.TrimSpace(reply[strings.Index(reply, ":")+1:])
+	switch {
+	case result == "OK":
+		return Verdict{}, nil
+	case strings.HasSuffix(result, " FOUND"):
+		return Verdict{Infected: true, Threat: strings.TrimSuffix(result, " FOUND")}, nil
+	}
+	return Verdict{}, fmt.Errorf("clamd: %s", reply)
+}
@@ -0,0 +1,57 @@
+package scan
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// HTTP scans through an external scanning API: the file is POSTed as the
+// raw request body (its name in X-Filename, the token as a bearer token) and
+// the API answers 200 with {"infected": bool, "threat": "..."}. Vendor APIs
+// with other shapes sit behind a small adapter speaking this.
+type HTTP struct {
+	URL   string
+	Token string
+	HTTP  *http.Client
+}
+
+// NewHTTP returns a scanner for the API at url.
+func NewHTTP(url, token string, timeout time.Duration) *HTTP {
+	return &HTTP{URL: url, Token: token, HTTP: &http.Client{Timeout: timeout}}
+}
+
+func (s *HTTP) Scan(ctx context.Context, name string, data []byte) (Verdict, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(data))
+	if err != nil {
+		return Verdict{}, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("X-Filename", name)
+	if s.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.Token)
+	}
+	resp, err := s.HTTP.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("scanning API: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return Verdict{}, fmt.Errorf("scanning API returned %s", resp.Status)
+	}
+
+	var body struct {
+		Infected *bool  `json:"infected"`
+		Threat   string `json:"threat"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return Verdict{}, fmt.Errorf("scanning API: %w", err)
+	}
+	if body.Infected == nil {
+		return Verdict{}, fmt.Errorf("scanning API gave no verdict")
+	}
+	return Verdict{Infected: *body.Infected, Threat: body.Threat}, nil
+}
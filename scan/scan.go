@@ -0,0 +1,64 @@
+// Package scan checks uploaded files for malware before they are parsed.
+// Backends are a ClamAV daemon (clamd) and an external scanning API.
+package scan
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Actions taken on an infected upload.
+const (
+	ActionReject = "reject" // the request fails
+	ActionFlag   = "flag"   // the request proceeds, marked and logged
+)
+
+// Verdict is the outcome of scanning one file.
+type Verdict struct {
+	Infected bool
+	Threat   string // the scanner's name for what it found
+}
+
+// Scanner checks files for malware. An error means the file could not be
+// scanned, not that it is infected.
+type Scanner interface {
+	Scan(ctx context.Context, name string, data []byte) (Verdict, error)
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Backend string // clamav | http | off
+
+	// clamav: clamd address, "host:port" or "unix:/path/to/clamd.sock"
+	ClamAVAddr string
+
+	// http: the scanning API (see HTTP) and its bearer token
+	URL   string
+	Token string
+
+	Timeout time.Duration // per file, default 30s
+}
+
+// New builds the scanner selected by cfg.Backend; "off" or "" returns nil.
+func New(cfg Config) (Scanner, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	switch cfg.Backend {
+	case "", "off":
+		return nil, nil
+	case "clamav":
+		addr := cfg.ClamAVAddr
+		if addr == "" {
+			addr = "localhost:3310"
+		}
+		return &ClamAV{Addr: addr, Timeout: cfg.Timeout}, nil
+	case "http":
+		if cfg.URL == "" {
+			return nil, fmt.Errorf("scan backend http needs a URL")
+		}
+		return NewHTTP(cfg.URL, cfg.Token, cfg.Timeout), nil
+	}
+	return nil, fmt.Errorf("unknown scan backend %q", cfg.Backend)
+}
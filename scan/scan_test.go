@@ -0,0 +1,102 @@
+package scan
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const eicar = `X5O!P%@AP[4\PZX54(P^)7CC)7}$EICAR-STANDARD-ANTIVIRUS-TEST-FILE!$H+H*`
+
+// fakeClamd answers INSTREAM like clamd, finding the EICAR test string.
+func fakeClamd(t *testing.T) string {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { l.Close() })
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			r := bufio.NewReader(conn)
+			cmd, _ := r.ReadString(0)
+			var data []byte
+			for cmd == "zINSTREAM\x00" {
+				var n uint32
+				if binary.Read(r, binary.BigEndian, &n) != nil || n == 0 {
+					break
+				}
+				chunk := make([]byte, n)
+				io.ReadFull(r, chunk)
+				data = append(data, chunk...)
+			}
+			reply := "stream: OK\x00"
+			if bytes.Contains(data, []byte(eicar)) {
+				reply = "stream: Eicar-Test-Signature FOUND\x00"
+			}
+			conn.Write([]byte(reply))
+			conn.Close()
+		}
+	}()
+	return l.Addr().String()
+}
+
+func TestClamAV(t *testing.T) {
+	s, err := New(Config{Backend: "clamav", ClamAVAddr: fakeClamd(t)})
+	require.NoError(t, err)
+
+	v, err := s.Scan(context.Background(), "statement.pdf", bytes.Repeat([]byte("%PDF-1.7 "), 20000))
+	require.NoError(t, err)
+	assert.False(t, v.Infected)
+
+	v, err = s.Scan(context.Background(), "eicar.pdf", []byte(eicar))
+	require.NoError(t, err)
+	assert.Equal(t, Verdict{Infected: true, Threat: "Eicar-Test-Signature"}, v)
+
+	_, err = parseClamdReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+
+	_, err = (&ClamAV{Addr: "127.0.0.1:1", Timeout: time.Second}).Scan(context.Background(), "a.pdf", []byte("x"))
+	assert.Error(t, err, "clamd down is not a clean verdict")
+}
+
+func TestHTTP(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		json.NewEncoder(w).Encode(map[string]any{"infected": bytes.Contains(body, []byte(eicar)), "threat": "EICAR"})
+	}))
+	defer srv.Close()
+
+	s, err := New(Config{Backend: "http", URL: srv.URL, Token: "secret"})
+	require.NoError(t, err)
+	v, err := s.Scan(context.Background(), "eicar.pdf", []byte(eicar))
+	require.NoError(t, err)
+	assert.True(t, v.Infected)
+	v, err = s.Scan(context.Background(), "payslip.pdf", []byte("%PDF-1.4"))
+	require.NoError(t, err)
+	assert.False(t, v.Infected)
+
+	_, err = NewHTTP(srv.URL, "wrong", time.Second).Scan(context.Background(), "a.pdf", nil)
+	assert.Error(t, err)
+
+	off, err := New(Config{Backend: "off"})
+	assert.NoError(t, err)
+	assert.Nil(t, off)
+}
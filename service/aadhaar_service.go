@@ -1,150 +1,285 @@
 package service
 
 import (
-	"bytes"
+	"cmp"
 	"context"
+	"crypto/rsa"
+	"encoding/base64"
 	"encoding/xml"
+	"errors"
 	"fmt"
 	"image"
-	"image/jpeg"
-	"image/png"
-	"log"
-	"os"
+	_ "image/jpeg"
+	"log/slog"
 	"strings"
 
 	"github.com/Aashish23092/ocr-income-verification/client"
 	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
 	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/address"
+	"github.com/Aashish23092/ocr-income-verification/utils/ekyc"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+	"github.com/Aashish23092/ocr-income-verification/utils/redact"
+	"github.com/Aashish23092/ocr-income-verification/utils/secureqr"
 	"github.com/makiuchi-d/gozxing"
 	"github.com/makiuchi-d/gozxing/qrcode"
 )
 
 // AadhaarService handles Aadhaar card data extraction
 type AadhaarService struct {
-	tesseractClient *client.TesseractClient
+	tesseractClient client.TesseractEngine
 	pdfProcessor    PDFProcessor
-	paddleClient    *client.PaddleClient
+	paddleClient    client.PaddleEngine
+	pipelines       *pipeline.Orchestrator
+	// UIDAI public key for Secure QR signatures; nil leaves them unverified
+	qrKey *rsa.PublicKey
+	// UIDAI public key for offline e-KYC XML signatures; nil leaves them unverified
+	ekycKey *rsa.PublicKey
+	// rejectUnmasked fails cards printing the full number (compliance mode)
+	rejectUnmasked bool
 }
 
+const aadhaarDocType = "aadhaar"
+
+// ekycMaxSize bounds what an offline e-KYC ZIP unpacks to: an XML with a
+// small JPEG photo.
+const ekycMaxSize = 2 << 20
+
+// ErrEKYCSignatureInvalid means an offline e-KYC XML was not signed by UIDAI,
+// or was changed after it was.
+var ErrEKYCSignatureInvalid = errors.New("offline e-KYC signature is invalid")
+
+// ErrUnmaskedAadhaar means the card prints the full Aadhaar number while only
+// masked copies may be accepted.
+var ErrUnmaskedAadhaar = errors.New("Aadhaar number is not masked; upload the masked Aadhaar")
+
+// assuranceHigh marks details read from a verified UIDAI signature.
+const assuranceHigh = "high"
+
 // NewAadhaarService creates a new AadhaarService instance
-func NewAadhaarService(tesseractClient *client.TesseractClient, pdfProcessor PDFProcessor) *AadhaarService {
+func NewAadhaarService(tesseractClient client.TesseractEngine, pdfProcessor PDFProcessor, pipelines *pipeline.Orchestrator, qrKey, ekycKey *rsa.PublicKey, rejectUnmasked bool) (*AadhaarService, error) {
 	// Initialize PaddleOCR client (optional, falls back to Tesseract if unavailable)
-	paddle, err := client.NewPaddleClient()
-	if err != nil {
-		log.Printf("Warning: PaddleOCR client initialization failed: %v. Will use Tesseract only.", err)
-		paddle = nil
+	var paddle client.PaddleEngine
+	if p, err := client.NewPaddleClient(); err != nil {
+		slog.Warn("PaddleOCR client initialization failed, using Tesseract only", "error", err)
+	} else {
+		paddle = p
 	}
 
-	return &AadhaarService{
+	s := &AadhaarService{
 		tesseractClient: tesseractClient,
 		pdfProcessor:    pdfProcessor,
 		paddleClient:    paddle,
+		qrKey:           qrKey,
+		ekycKey:         ekycKey,
+		rejectUnmasked:  rejectUnmasked,
+	}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"qr":       noArg(s.qrStep),
+		"parse":    noArg(s.parseStep),
+		"validate": noArg(s.validateStep),
+	}, aadhaarDocType)
+	if err != nil {
+		return nil, err
 	}
+	return s, nil
 }
 
 // ExtractFromFile extracts Aadhaar data from a file (PDF or image)
 func (s *AadhaarService) ExtractFromFile(ctx context.Context, fileData []byte, mimeType, password string) (*dto.AadhaarExtractResponse, error) {
-	var images []image.Image
-	var img image.Image
-	var err error
+	return s.run(&pipeline.Doc{
+		Ctx:      ctx,
+		DocType:  aadhaarDocType,
+		MimeType: mimeType,
+		Password: password,
+		Inputs:   [][]byte{fileData},
+	})
+}
 
-	// ---------------------------------------------
-	// 1️⃣ PDF → extract ALL pages as images
-	// ---------------------------------------------
-	if strings.Contains(mimeType, "pdf") {
-		log.Println("Processing PDF file for Aadhaar extraction")
+// ExtractFromImages processes 2 or more Aadhaar images (front + back)
+func (s *AadhaarService) ExtractFromImages(
+	ctx context.Context,
+	imagesData [][]byte,
+	mimeTypes []string,
+	password string,
+) (*dto.AadhaarExtractResponse, error) {
 
-		images, err = s.pdfProcessor.ExtractImages(fileData, password)
-		if err != nil {
-			return nil, fmt.Errorf("failed to extract images from PDF: %w", err)
-		}
+	if len(imagesData) == 0 {
+		return nil, fmt.Errorf("no images provided")
+	}
 
-		if len(images) == 0 {
-			return nil, fmt.Errorf("no images found in PDF")
-		}
+	return s.run(&pipeline.Doc{
+		Ctx:      ctx,
+		DocType:  aadhaarDocType,
+		Password: password,
+		Inputs:   imagesData,
+	})
+}
 
-		// Aadhaar identity info is almost always on page 2
-		if len(images) > 1 {
-			log.Println("Using page 2 (Aadhaar front side with Name/DOB/Gender)")
-			img = images[1]
-		} else {
-			log.Println("PDF has only one page; using page 1")
-			img = images[0]
-		}
+// ExtractFromEKYC reads an Aadhaar Paperless Offline e-KYC ZIP, decrypted
+// with its share code. The email and mobile number, when given, are checked
+// against the hashes UIDAI put in the XML.
+func (s *AadhaarService) ExtractFromEKYC(ctx context.Context, zipData []byte, shareCode, email, mobile string) (*dto.AadhaarExtractResponse, error) {
+	data, err := ekyc.Open(zipData, shareCode, s.ekycKey, ekycMaxSize)
+	if err != nil {
+		return nil, err
+	}
+	if data.Signature == secureqr.SignatureInvalid {
+		return nil, ErrEKYCSignatureInvalid
+	}
+
+	result := &dto.AadhaarExtractResponse{
+		Name:          data.Name,
+		DOB:           data.DOB,
+		Gender:        data.Gender,
+		Address:       data.Address(),
+		AadhaarLast4:  data.Last4(),
+		Source:        "ekyc_xml",
+		EKYCSignature: data.Signature,
+	}
+	if data.Signature == secureqr.SignatureVerified {
+		result.Assurance = assuranceHigh
 	} else {
-		// ---------------------------------------------
-		// 2️⃣ PNG/JPEG case
-		// ---------------------------------------------
-		log.Println("Processing image file for Aadhaar extraction")
-		img, err = decodeImage(fileData, mimeType)
-		if err != nil {
-			return nil, fmt.Errorf("failed to decode image: %w", err)
+		slog.WarnContext(ctx, "Aadhaar offline e-KYC signature not verified; no UIDAI certificate configured")
+	}
+	if email != "" {
+		if match, ok := data.VerifyEmail(email, shareCode); ok {
+			result.EmailVerified = &match
 		}
 	}
+	if mobile != "" {
+		if match, ok := data.VerifyMobile(mobile, shareCode); ok {
+			result.MobileVerified = &match
+		}
+	}
+	if pipeline.PhotoRequested(ctx) && len(data.Photo) > 0 {
+		result.Photo = base64.StdEncoding.EncodeToString(data.Photo)
+	}
+	result.DOBISO, _ = utils.NormalizeDOB(result.DOB)
+	if result.Address != "" {
+		parts := address.Parse(result.Address)
+		result.AddressParts = &parts
+	}
+	return result, nil
+}
 
-	// ---------------------------------------------
-	// 3️⃣ QR extraction (first attempt)
-	// ---------------------------------------------
-	log.Println("Attempting QR code extraction...")
-	qrResult, err := s.extractFromQR(img)
-	if err == nil && qrResult != nil {
-		log.Println("Successfully extracted data from QR code")
-		return qrResult, nil
+func (s *AadhaarService) run(doc *pipeline.Doc) (*dto.AadhaarExtractResponse, error) {
+	if pipeline.MaskedImageRequested(doc.Ctx) {
+		doc.Ctx = pipeline.WithoutCache(doc.Ctx) // masking needs this run's pages and word boxes
 	}
-	log.Printf("QR extraction failed or no QR found: %v. Falling back to OCR...", err)
-
-	// ---------------------------------------------
-	// 4️⃣ OCR on ALL PAGES (Name/DOB/Gender often exist on page 2)
-	// ---------------------------------------------
-	var fullText strings.Builder
-
-	if len(images) > 0 {
-		log.Printf("Running OCR on %d pages...", len(images))
-		for idx, page := range images {
-			log.Printf("OCR on page %d...", idx+1)
-
-			buf := new(bytes.Buffer)
-			if err := png.Encode(buf, page); err != nil {
-				log.Printf("Failed to encode page %d: %v", idx+1, err)
-				continue
-			}
+	result, err := pipeline.Cached(s.pipelines, doc, func() (*dto.AadhaarExtractResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.AadhaarExtractResponse)
+		if !ok {
+			return nil, fmt.Errorf("aadhaar pipeline produced no result")
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if s.rejectUnmasked && result.AadhaarNumber != "" {
+		return nil, ErrUnmaskedAadhaar
+	}
+	if pipeline.MaskedImageRequested(doc.Ctx) {
+		result.MaskedImages = maskedCardImages(doc, result.AadhaarNumber)
+	}
+	result.Photo = portraitPhoto(doc.Ctx, face.DocAadhaar, doc.Inputs, s.pdfProcessor, doc.Password)
+	result.DOBISO, _ = utils.NormalizeDOB(result.DOB)
+	if result.Address != "" {
+		parts := address.Parse(result.Address)
+		result.AddressParts = &parts
+	}
+	return result, nil
+}
 
-			pageText, err := s.paddleClient.ExtractText(buf.Bytes())
-			if err != nil {
-				log.Printf("Page %d OCR failed: %v", idx+1, err)
-				continue
-			}
+// readsPrintedNumber reports whether the card's printed number must be read
+// even when its QR is: to check it is masked, or to mask it.
+func (s *AadhaarService) readsPrintedNumber(ctx context.Context) bool {
+	return s.rejectUnmasked || pipeline.MaskedImageRequested(ctx)
+}
 
-			fullText.WriteString("\n")
-			fullText.WriteString(pageText)
+// maskedCardImages returns the pages as base64 JPEGs with the first eight
+// digits of number blacked out where OCR found them; pages without them are
+// returned as they are.
+func maskedCardImages(doc *pipeline.Doc, number string) []string {
+	boxes := utils.AadhaarMaskBoxes(doc.Words, number)
+	var out []string
+	for i, img := range doc.Images {
+		var page []dto.BoundingBox
+		for _, w := range boxes {
+			if w.Page == i+1 {
+				page = append(page, w.Box)
+			}
 		}
-	} else {
-		// Single image case
-		pageText, err := s.paddleClient.ExtractText(fileData)
+		masked, err := face.EncodeJPEG(redact.BlackOut(img, page, 2))
 		if err != nil {
-			return nil, fmt.Errorf("OCR extraction failed: %w", err)
+			slog.WarnContext(doc.Ctx, "Masked Aadhaar: JPEG encoding failed", "page", i+1, "error", err)
+			continue
 		}
-		fullText.WriteString(pageText)
+		out = append(out, masked)
 	}
+	return out
+}
 
-	ocrText := fullText.String()
-
-	// Debug dump
-	log.Println("=========== OCR RAW OUTPUT BEGIN ===========")
-	log.Println(ocrText)
-	log.Println("=========== OCR RAW OUTPUT END =============")
+// qrStep tries the secure QR code on every page (it is often on the back side).
+// A decoded QR is authoritative, so OCR is skipped unless the printed number
+// must be read too.
+func (s *AadhaarService) qrStep(doc *pipeline.Doc) error {
+	images, err := pageImages(doc)
+	if err != nil {
+		return err
+	}
 
-	// ---------------------------------------------
-	// 5️⃣ Parse Aadhaar info from combined OCR text
-	// ---------------------------------------------
-	result := utils.ParseAadhaarFromText(ocrText)
+	for i, img := range images {
+		slog.DebugContext(doc.Ctx, "Trying Aadhaar QR extraction", "image", i+1)
+		qr, err := s.extractFromQR(img)
+		if err == nil && qr != nil && qr.QRSignature == secureqr.SignatureInvalid {
+			// Tampered or forged QR: do not trust it, read the printed card instead
+			slog.WarnContext(doc.Ctx, "Aadhaar secure QR signature is invalid; ignoring QR")
+			doc.Result = &dto.AadhaarExtractResponse{QRSignature: secureqr.SignatureInvalid}
+			continue
+		}
+		if err == nil && qr != nil {
+			slog.InfoContext(doc.Ctx, "Extracted Aadhaar data from QR code")
+			doc.Result = qr
+			doc.Done = !s.readsPrintedNumber(doc.Ctx)
+			return nil
+		}
+	}
+	slog.InfoContext(doc.Ctx, "No usable Aadhaar QR, falling back to OCR")
+	return nil
+}
 
-	// If even combined OCR yields nothing meaningful → error
-	if result.Name == "" && result.AadhaarLast4 == "" {
-		return nil, fmt.Errorf("could not extract meaningful Aadhaar data from OCR text")
+// parseStep parses Aadhaar details from the combined OCR text.
+func (s *AadhaarService) parseStep(doc *pipeline.Doc) error {
+	slog.DebugContext(doc.Ctx, "Aadhaar OCR text", "chars", len(doc.Text), "lines", strings.Count(doc.Text, "\n")+1)
+
+	result := utils.ParseAadhaarFromText(doc.Text)
+	if prev, ok := doc.Result.(*dto.AadhaarExtractResponse); ok && prev.Source == "qr" {
+		// The QR's details stand; OCR only tells how the number is printed
+		prev.Masked = result.Masked
+		prev.AadhaarNumber = cmp.Or(prev.AadhaarNumber, result.AadhaarNumber)
+		return nil
+	} else if ok {
+		result.QRSignature = prev.QRSignature
 	}
+	doc.Result = &result
+	return nil
+}
 
-	return &result, nil
+// validateStep rejects OCR output with no usable identity details.
+func (s *AadhaarService) validateStep(doc *pipeline.Doc) error {
+	result, ok := doc.Result.(*dto.AadhaarExtractResponse)
+	if !ok || (result.Name == "" && result.AadhaarLast4 == "") {
+		return fmt.Errorf("%w: no Aadhaar name or number", ErrParseEmpty)
+	}
+	return nil
 }
 
 // extractFromQR attempts to extract Aadhaar data from QR code
@@ -164,9 +299,31 @@ func (s *AadhaarService) extractFromQR(img image.Image) (*dto.AadhaarExtractResp
 		return nil, fmt.Errorf("failed to decode QR code: %w", err)
 	}
 
-	// Parse XML from QR code
 	qrText := result.GetText()
-	log.Printf("QR code decoded, length: %d bytes", len(qrText))
+	slog.Debug("QR code decoded", "bytes", len(qrText))
+
+	// Cards issued since 2019 carry the numeric Secure QR
+	if secureqr.IsSecureQR(qrText) {
+		data, err := secureqr.Decode(qrText, s.qrKey)
+		if err != nil {
+			return nil, err
+		}
+		response := &dto.AadhaarExtractResponse{
+			Name:         data.Name,
+			DOB:          data.DOB,
+			Gender:       data.Gender,
+			Address:      data.Address(),
+			AadhaarLast4: data.Last4(),
+			Source:       "qr",
+			QRSignature:  data.Signature,
+		}
+		if data.Signature == secureqr.SignatureVerified {
+			response.Assurance = assuranceHigh
+		}
+		return response, nil
+	}
+
+	// Older cards: PrintLetterBarcodeData XML
 
 	var qrData dto.AadhaarQRData
 	if err := xml.Unmarshal([]byte(qrText), &qrData); err != nil {
@@ -182,189 +339,10 @@ func (s *AadhaarService) extractFromQR(img image.Image) (*dto.AadhaarExtractResp
 		AadhaarLast4: qrData.GetLast4Digits(),
 		Source:       "qr",
 	}
-
-	return response, nil
-}
-
-// extractFromOCR attempts to extract Aadhaar data using PaddleOCR (primary) or Tesseract (fallback)
-func (s *AadhaarService) extractFromOCR(img image.Image) (*dto.AadhaarExtractResponse, error) {
-	var text string
-	var err error
-
-	// Try PaddleOCR first if available
-	if s.paddleClient != nil {
-		log.Println("Attempting PaddleOCR extraction...")
-		// Convert image.Image → PNG bytes before sending to PaddleOCR
-		buf := new(bytes.Buffer)
-		if err := png.Encode(buf, img); err != nil {
-			log.Printf("failed to encode image for PaddleOCR: %v", err)
-		} else {
-			text, err = s.paddleClient.ExtractText(buf.Bytes())
-		}
-
-		if err != nil || len(strings.TrimSpace(text)) < 50 {
-			log.Printf("PaddleOCR failed or extracted insufficient text (len=%d): %v. Falling back to Tesseract...", len(text), err)
-			// Fall through to Tesseract
-		} else {
-			log.Printf("PaddleOCR succeeded, extracted %d characters", len(text))
-			// PaddleOCR succeeded, skip Tesseract
-			goto ParseText
-		}
-	} else {
-		log.Println("PaddleOCR client not available, using Tesseract directly")
-	}
-
-	// Fallback to Tesseract
-	{
-		log.Println("Using Tesseract OCR...")
-		// Save image to temporary file for Tesseract
-		tempFile, err := saveAadhaarImageToTempFile(img)
-		if err != nil {
-			return nil, fmt.Errorf("failed to save temp image: %w", err)
-		}
-		defer os.Remove(tempFile)
-
-		// Extract text using Tesseract
-		text, _, err = s.tesseractClient.ExtractTextAndQuality(tempFile)
-		if err != nil {
-			return nil, fmt.Errorf("OCR extraction failed: %w", err)
-		}
-		log.Printf("Tesseract extracted %d characters", len(text))
-	}
-
-ParseText:
-	// 🔥🔥 OCR DEBUG DUMP 🔥🔥
-	log.Println("=========== OCR RAW OUTPUT BEGIN ===========")
-	log.Println(text)
-	log.Println("============ OCR RAW OUTPUT END ============")
-
-	// FORCE PRINTF (Docker always shows this)
-	fmt.Printf("\n\n----- OCR RAW TEXT (FORCE DUMP) -----\n%s\n----- END OCR RAW TEXT -----\n\n", text)
-
-	// SAVE TO FILE (failsafe)
-	os.WriteFile("/tmp/ocr_dump.txt", []byte(text), 0644)
-	log.Println("OCR dump saved to /tmp/ocr_dump.txt")
-
-	log.Printf("OCR extracted %d characters of text", len(text))
-
-	// Parse Aadhaar data from OCR text
-	result := utils.ParseAadhaarFromText(text)
-
-	// Validate that we got at least some data
-	if result.Name == "" && result.AadhaarLast4 == "" {
-		return nil, fmt.Errorf("could not extract meaningful Aadhaar data from OCR text")
-	}
-
-	return &result, nil
-}
-
-// decodeImage decodes an image from bytes based on MIME type
-func decodeImage(data []byte, mimeType string) (image.Image, error) {
-	reader := bytes.NewReader(data)
-
-	if strings.Contains(mimeType, "png") {
-		return png.Decode(reader)
-	} else if strings.Contains(mimeType, "jpeg") || strings.Contains(mimeType, "jpg") {
-		return jpeg.Decode(reader)
-	}
-
-	// Try to decode anyway
-	img, _, err := image.Decode(reader)
-	return img, err
-}
-
-// saveAadhaarImageToTempFile saves an image to a temporary PNG file for OCR processing
-func saveAadhaarImageToTempFile(img image.Image) (string, error) {
-	tempFile, err := os.CreateTemp("", "aadhaar-ocr-*.png")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp image file: %w", err)
-	}
-	defer tempFile.Close()
-
-	if err := png.Encode(tempFile, img); err != nil {
-		return "", fmt.Errorf("failed to encode image to PNG: %w", err)
-	}
-
-	return tempFile.Name(), nil
-}
-
-// ExtractFromImages processes 2 or more Aadhaar images (front + back)
-func (s *AadhaarService) ExtractFromImages(
-	ctx context.Context,
-	imagesData [][]byte,
-	mimeTypes []string,
-	password string,
-) (*dto.AadhaarExtractResponse, error) {
-
-	if len(imagesData) == 0 {
-		return nil, fmt.Errorf("no images provided")
-	}
-
-	// Decode all images
-	var images []image.Image
-	for i := range imagesData {
-		img, err := decodeImage(imagesData[i], mimeTypes[i])
-		if err != nil {
-			log.Printf("Failed to decode image %d: %v", i+1, err)
-			continue
-		}
-		images = append(images, img)
-	}
-
-	if len(images) == 0 {
-		return nil, fmt.Errorf("failed to decode any Aadhaar image")
+	// The legacy XML QR carries the full number
+	if utils.ValidAadhaar(qrData.UID) {
+		response.AadhaarNumber = qrData.UID
 	}
 
-	// -------------------------------------------------------------
-	// 1️⃣ Try QR extraction from ALL pages (QR often on back side)
-	// -------------------------------------------------------------
-	for i, img := range images {
-		log.Printf("Trying QR extraction on image %d...", i+1)
-		qr, err := s.extractFromQR(img)
-		if err == nil && qr != nil {
-			log.Println("QR extraction succeeded")
-			return qr, nil
-		}
-	}
-
-	// -------------------------------------------------------------
-	// 2️⃣ OCR on ALL images → Combine text intelligently
-	// -------------------------------------------------------------
-	var combined strings.Builder
-
-	for i, img := range images {
-		log.Printf("Running OCR on image %d...", i+1)
-
-		buf := new(bytes.Buffer)
-		if err := png.Encode(buf, img); err != nil {
-			log.Printf("PNG encode failed for image %d: %v", i+1, err)
-			continue
-		}
-
-		pageText, err := s.paddleClient.ExtractText(buf.Bytes())
-		if err != nil {
-			log.Printf("OCR failed for image %d: %v", i+1, err)
-			continue
-		}
-
-		combined.WriteString("\n")
-		combined.WriteString(pageText)
-	}
-
-	fullText := combined.String()
-
-	log.Println("=========== OCR RAW OUTPUT BEGIN ===========")
-	log.Println(fullText)
-	log.Println("=========== OCR RAW OUTPUT END =============")
-
-	// -------------------------------------------------------------
-	// 3️⃣ Parse combined OCR text for Aadhaar data
-	// -------------------------------------------------------------
-	result := utils.ParseAadhaarFromText(fullText)
-
-	if result.Name == "" && result.AadhaarLast4 == "" {
-		return nil, fmt.Errorf("could not extract valid Aadhaar details from OCR text")
-	}
-
-	return &result, nil
+	return response, nil
 }
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/address"
+)
+
+// AddressProofService reads utility bills (electricity, telecom, gas), as PDFs
+// or photos, for address proof.
+type AddressProofService struct {
+	pipelines *pipeline.Orchestrator
+}
+
+func NewAddressProofService(pipelines *pipeline.Orchestrator) (*AddressProofService, error) {
+	s := &AddressProofService{}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"parse": noArg(s.parseStep),
+	}, "address_proof")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *AddressProofService) ExtractBill(ctx context.Context, data []byte, filename, password string) (*dto.AddressProofResponse, error) {
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "address_proof", Filename: filename, Password: password, Inputs: [][]byte{data}}
+	return pipeline.Cached(s.pipelines, doc, func() (*dto.AddressProofResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.AddressProofResponse)
+		if !ok {
+			return nil, fmt.Errorf("address proof pipeline produced no result")
+		}
+		return result, nil
+	})
+}
+
+func (s *AddressProofService) parseStep(doc *pipeline.Doc) error {
+	bill := utils.ParseUtilityBill(doc.Text)
+	if bill.Address != "" {
+		parts := address.Parse(bill.Address)
+		bill.AddressParts = &parts
+	}
+	doc.Result = &bill
+	return nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/address"
+)
+
+// Document types of applicant documents besides the KYC ones.
+const (
+	ApplicantDocITR    = "itr"
+	ApplicantDocForm16 = "form16"
+	ApplicantDocKYC    = "kyc" // a KYC report, several documents at once
+	ApplicantDocIncome = "income_verification"
+)
+
+// ApplicantService keeps the documents extracted for an applicant and
+// consolidates them, with the applicant's income verifications, into one
+// profile.
+type ApplicantService struct {
+	documents store.ApplicantStore
+	income    *IncomeService
+}
+
+func NewApplicantService(documents store.ApplicantStore, income *IncomeService) *ApplicantService {
+	return &ApplicantService{documents: documents, income: income}
+}
+
+// Record keeps the JSON result of a document extraction that client made
+// for an applicant.
+func (s *ApplicantService) Record(applicantID, tenantID, client, docType, requestID string, result []byte) error {
+	result, err := stripApplicantDocument(result)
+	if err != nil {
+		return err
+	}
+	return s.documents.AddApplicantDocument(&dto.ApplicantDocument{
+		ApplicantID: applicantID,
+		TenantID:    tenantID,
+		Client:      client,
+		DocType:     docType,
+		RequestID:   requestID,
+		Result:      result,
+		ProcessedAt: time.Now().UTC(),
+	})
+}
+
+// applicantDropFields are left out of stored results: full Aadhaar numbers
+// must not be kept, and portraits and raw OCR text are large and unused.
+var applicantDropFields = []string{"aadhaar_number", "photo", "raw_text"}
+
+// stripApplicantDocument removes applicantDropFields from a result and from
+// the documents nested in it (those of a KYC report).
+func stripApplicantDocument(result []byte) ([]byte, error) {
+	var tree map[string]interface{}
+	if err := json.Unmarshal(result, &tree); err != nil {
+		return nil, err
+	}
+	strip := func(m map[string]interface{}) {
+		for _, f := range applicantDropFields {
+			delete(m, f)
+		}
+	}
+	strip(tree)
+	for _, v := range tree {
+		if nested, ok := v.(map[string]interface{}); ok {
+			strip(nested)
+		}
+	}
+	return json.Marshal(tree)
+}
+
+// applicantDocs are the latest documents of each type of an applicant.
+type applicantDocs struct {
+	kyc    dto.KYCReport
+	itr    *dto.ITRResult
+	form16 *dto.Form16Result
+	tds    *dto.Form26ASData
+	income *dto.VerificationRecord
+}
+
+// Summary consolidates every document client processed for the applicant
+// of tenantID; the documents of other clients and tenants are left out. It
+// returns store.ErrNotFound when there are none.
+func (s *ApplicantService) Summary(applicantID, tenantID, client string) (*dto.ApplicantSummary, error) {
+	documents, err := s.documents.ApplicantDocuments(applicantID, tenantID, client)
+	if err != nil {
+		return nil, err
+	}
+	verifications, err := s.income.ListVerifications(store.ListFilter{
+		Client:      client,
+		ApplicantID: applicantID,
+		TenantID:    tenantID,
+		ExactTenant: true,
+		Limit:       1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(documents) == 0 && len(verifications) == 0 {
+		return nil, store.ErrNotFound
+	}
+
+	summary := &dto.ApplicantSummary{ApplicantID: applicantID, Documents: []dto.ApplicantDocumentRef{}, Components: []dto.ScoreComponent{}}
+	var docs applicantDocs
+	for _, doc := range documents {
+		if err := docs.add(doc); err != nil {
+			slog.Warn("Applicant document not readable", "applicant_id", applicantID, "doc_type", doc.DocType, "error", err)
+			continue
+		}
+		summary.Documents = append(summary.Documents, dto.ApplicantDocumentRef{DocType: doc.DocType, RequestID: doc.RequestID, ProcessedAt: doc.ProcessedAt})
+	}
+	if len(verifications) > 0 {
+		docs.income = verifications[0]
+		docs.addIncome(docs.income.Current)
+		summary.Documents = append(summary.Documents, dto.ApplicantDocumentRef{
+			DocType:        ApplicantDocIncome,
+			VerificationID: docs.income.ID,
+			ProcessedAt:    docs.income.CreatedAt,
+		})
+	}
+
+	crossVerifyKYC(&docs.kyc)
+	summary.IdentityChecks = docs.kyc.Fields
+	summary.IdentityVerdict = docs.kyc.Verdict
+	summary.Mismatches = docs.kyc.Mismatches
+	summary.Identity = docs.identity()
+	summary.Income = docs.incomeProfile()
+
+	if docs.income != nil {
+		score, err := s.income.ScoreVerification(docs.income.ID, client)
+		if err != nil {
+			return nil, err
+		}
+		summary.Income.VerificationScore = score
+	}
+
+	if docs.kyc.Verdict != dto.KYCInsufficient {
+		summary.Components = append(summary.Components, dto.ScoreComponent{Name: "identity", Score: docs.kyc.Score * 100})
+	}
+	if score := summary.Income.VerificationScore; score != nil {
+		summary.Components = append(summary.Components, dto.ScoreComponent{Name: "income", Score: score.Score})
+	}
+	for i := range summary.Components {
+		summary.Components[i].Weight = 1 / float64(len(summary.Components))
+		summary.Score += summary.Components[i].Score * summary.Components[i].Weight
+	}
+	return summary, nil
+}
+
+// add decodes a stored document over the earlier one of its type.
+func (d *applicantDocs) add(doc dto.ApplicantDocument) (err error) {
+	switch doc.DocType {
+	case dto.KYCDocAadhaar:
+		d.kyc.Aadhaar, err = decodeDocument(doc.Result, d.kyc.Aadhaar)
+	case dto.KYCDocPAN:
+		d.kyc.PAN, err = decodeDocument(doc.Result, d.kyc.PAN)
+	case dto.KYCDocDL:
+		d.kyc.DrivingLicense, err = decodeDocument(doc.Result, d.kyc.DrivingLicense)
+	case dto.KYCDocPassport:
+		d.kyc.Passport, err = decodeDocument(doc.Result, d.kyc.Passport)
+	case dto.KYCDocBill:
+		d.kyc.AddressProof, err = decodeDocument(doc.Result, d.kyc.AddressProof)
+	case ApplicantDocITR:
+		d.itr, err = decodeDocument(doc.Result, d.itr)
+	case ApplicantDocForm16:
+		d.form16, err = decodeDocument(doc.Result, d.form16)
+	case string(dto.DocTypeForm26AS):
+		d.tds, err = decodeDocument(doc.Result, d.tds)
+	case ApplicantDocKYC:
+		var report *dto.KYCReport
+		if report, err = decodeDocument(doc.Result, report); err == nil {
+			d.addKYC(*report)
+		}
+	}
+	// other types are listed, not consolidated
+	return err
+}
+
+// decodeDocument decodes raw, keeping prev when it cannot be decoded.
+func decodeDocument[T any](raw []byte, prev *T) (*T, error) {
+	v := new(T)
+	if err := json.Unmarshal(raw, v); err != nil {
+		return prev, err
+	}
+	return v, nil
+}
+
+// addKYC takes the documents a KYC report read.
+func (d *applicantDocs) addKYC(r dto.KYCReport) {
+	if r.Aadhaar != nil {
+		d.kyc.Aadhaar = r.Aadhaar
+	}
+	if r.PAN != nil {
+		d.kyc.PAN = r.PAN
+	}
+	if r.DrivingLicense != nil {
+		d.kyc.DrivingLicense = r.DrivingLicense
+	}
+	if r.Passport != nil {
+		d.kyc.Passport = r.Passport
+	}
+	if r.AddressProof != nil {
+		d.kyc.AddressProof = r.AddressProof
+	}
+	if r.SalarySlip != nil {
+		d.kyc.SalarySlip = r.SalarySlip
+	}
+	if r.BankStatement != nil {
+		d.kyc.BankStatement = r.BankStatement
+	}
+}
+
+// addIncome takes the latest salary slip and the first bank statement of an
+// income verification for the identity checks.
+func (d *applicantDocs) addIncome(resp dto.IncomeVerificationResponse) {
+	for i := range resp.SalarySlips {
+		if slip := &resp.SalarySlips[i]; d.kyc.SalarySlip == nil || slip.PayMonth >= d.kyc.SalarySlip.PayMonth {
+			d.kyc.SalarySlip = slip
+		}
+	}
+	if len(resp.BankStatements) > 0 {
+		d.kyc.BankStatement = &resp.BankStatements[0]
+	}
+}
+
+// identity takes each field from the first document, in order of trust,
+// that has it.
+func (d *applicantDocs) identity() dto.ApplicantIdentity {
+	id := dto.ApplicantIdentity{Sources: map[string]string{}}
+	pick := func(field string, dst *string, source, value string) {
+		if *dst == "" && value != "" {
+			*dst, id.Sources[field] = value, source
+		}
+	}
+	dob := func(s string) string {
+		iso, _ := utils.NormalizeDOB(s)
+		return iso
+	}
+
+	if a := d.kyc.Aadhaar; a != nil {
+		pick("name", &id.Name, dto.KYCDocAadhaar, a.Name)
+		pick("dob", &id.DOB, dto.KYCDocAadhaar, dob(a.DOB))
+		pick("gender", &id.Gender, dto.KYCDocAadhaar, a.Gender)
+		pick("aadhaar_last4", &id.AadhaarLast4, dto.KYCDocAadhaar, a.AadhaarLast4)
+		pick("address", &id.Address, dto.KYCDocAadhaar, a.Address)
+	}
+	if p := d.kyc.PAN; p != nil {
+		pick("name", &id.Name, dto.KYCDocPAN, p.Name)
+		pick("dob", &id.DOB, dto.KYCDocPAN, dob(p.DOB))
+		pick("pan", &id.PAN, dto.KYCDocPAN, p.PAN)
+	}
+	if p := d.kyc.Passport; p != nil {
+		pick("name", &id.Name, dto.KYCDocPassport, strings.TrimSpace(p.GivenNames+" "+p.Surname))
+		pick("dob", &id.DOB, dto.KYCDocPassport, dob(p.DOB))
+		pick("gender", &id.Gender, dto.KYCDocPassport, p.Sex)
+	}
+	if dl := d.kyc.DrivingLicense; dl != nil {
+		pick("name", &id.Name, dto.KYCDocDL, dl.Name)
+		pick("dob", &id.DOB, dto.KYCDocDL, dob(dl.DOB))
+		pick("address", &id.Address, dto.KYCDocDL, dl.Address)
+	}
+	if itr := d.itr; itr != nil {
+		pick("name", &id.Name, ApplicantDocITR, itr.Name)
+		pick("pan", &id.PAN, ApplicantDocITR, itr.PAN)
+	}
+	if f := d.form16; f != nil {
+		pick("pan", &id.PAN, ApplicantDocForm16, f.EmployeePAN)
+	}
+	if f := d.tds; f != nil {
+		pick("pan", &id.PAN, string(dto.DocTypeForm26AS), f.PAN)
+	}
+	if bill := d.kyc.AddressProof; bill != nil {
+		pick("address", &id.Address, dto.KYCDocBill, bill.Address)
+	}
+	if id.Address != "" {
+		parts := address.Parse(id.Address)
+		id.AddressParts = &parts
+	}
+	return id
+}
+
+// incomeProfile gathers the income figures of the latest income
+// verification, ITR, Form-16 and Form 26AS.
+func (d *applicantDocs) incomeProfile() dto.ApplicantIncome {
+	var inc dto.ApplicantIncome
+	if slip := d.kyc.SalarySlip; slip != nil {
+		inc.EmployerName, inc.MonthlyNetSalary = slip.EmployerName, slip.NetSalary
+	}
+	if v := d.income; v != nil {
+		if s := v.Current.MonthlyIncomeSummary; s != nil {
+			inc.AverageMonthlyIncome = s.AverageMonthlyIncome
+		}
+		if o := v.Current.DebtObligations; o != nil {
+			inc.TotalMonthlyObligation, inc.FOIR = o.TotalMonthlyObligation, o.FOIR
+		}
+		inc.MonthlyRent = v.Current.MonthlyRent
+	}
+	if itr := d.itr; itr != nil {
+		inc.AnnualIncome, inc.AssessmentYear = itr.TotalIncome, itr.AssessmentYear
+	}
+	if f := d.form16; f != nil {
+		inc.AnnualGrossSalary, inc.FinancialYear = f.GrossSalary, f.FinancialYear
+	}
+	if f := d.tds; f != nil {
+		for _, ded := range f.Deductors {
+			inc.TDSReportedIncome += ded.TotalAmountPaid
+		}
+		inc.TDSAssessmentYear = f.AssessmentYear
+	}
+	return inc
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+)
+
+func TestApplicantSummaryConsolidatesDocuments(t *testing.T) {
+	income := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	docs := store.NewMemoryApplicantStore(0, 0)
+	s := NewApplicantService(docs, income)
+
+	_, err := s.Summary("app-1", "", "")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+
+	require.NoError(t, s.Record("app-1", "", "", dto.KYCDocAadhaar, "r1",
+		[]byte(`{"name":"Ravi Kumar","dob":"14/08/1990","gender":"Male","aadhaar_last4":"9012","aadhaar_number":"234567789012","address":"12 MG Road, Indiranagar, Bengaluru, Karnataka - 560038"}`)))
+	require.NoError(t, s.Record("app-1", "", "", dto.KYCDocPAN, "r2",
+		[]byte(`{"pan":"ABCPK1234F","name":"RAVI KUMAR","dob":"14/08/1990","raw_text":"INCOME TAX DEPARTMENT"}`)))
+	require.NoError(t, s.Record("app-1", "", "", ApplicantDocITR, "r3",
+		[]byte(`{"pan":"ABCPK1234F","name":"RAVI KUMAR","assessment_year":"2024-25","total_income":1200000}`)))
+	require.NoError(t, s.Record("app-1", "", "", string(dto.DocTypeForm26AS), "r5",
+		[]byte(`{"kind":"26as","pan":"ABCPK1234F","assessment_year":"2025-26","deductors":[{"name":"ACME","tan":"BLRA12345B","total_amount_paid":984000},{"name":"HDFC BANK","tan":"MUMH03189E","total_amount_paid":4500}]}`)))
+	require.NoError(t, s.Record("app-2", "", "", dto.KYCDocPAN, "r4", []byte(`{"pan":"ZZZPZ9999Z","name":"OTHER"}`)))
+
+	stored, err := docs.ApplicantDocuments("app-1", "", "")
+	require.NoError(t, err)
+	assert.NotContains(t, string(stored[0].Result), "234567789012", "full Aadhaar numbers are not kept")
+	assert.NotContains(t, string(stored[1].Result), "raw_text")
+
+	resp := dto.IncomeVerificationResponse{
+		SalarySlips: []dto.SalarySlipData{
+			{EmployeeName: "Ravi Kumar", EmployerName: "Acme", PayMonth: "2025-03", NetSalary: 80000},
+			{EmployeeName: "Ravi Kumar", EmployerName: "Acme", PayMonth: "2025-04", NetSalary: 82000},
+		},
+	}
+	require.NoError(t, income.store.Save(&dto.VerificationRecord{
+		ID: "v1", ApplicantID: "app-1", Extracted: resp, Current: resp, CreatedAt: time.Now(),
+	}))
+
+	summary, err := s.Summary("app-1", "", "")
+	require.NoError(t, err)
+	assert.Len(t, summary.Documents, 5)
+	assert.Equal(t, "Ravi Kumar", summary.Identity.Name)
+	assert.Equal(t, dto.KYCDocAadhaar, summary.Identity.Sources["name"])
+	assert.Equal(t, "1990-08-14", summary.Identity.DOB)
+	assert.Equal(t, "ABCPK1234F", summary.Identity.PAN)
+	assert.Equal(t, "560038", summary.Identity.AddressParts.Pincode)
+	assert.Equal(t, dto.KYCMatch, summary.IdentityVerdict)
+
+	assert.Equal(t, 82000.0, summary.Income.MonthlyNetSalary)
+	assert.Equal(t, 1200000.0, summary.Income.AnnualIncome)
+	assert.Equal(t, 988500.0, summary.Income.TDSReportedIncome)
+	assert.Equal(t, "2025-26", summary.Income.TDSAssessmentYear)
+	require.NotNil(t, summary.Income.VerificationScore)
+	require.Len(t, summary.Components, 2)
+	assert.InDelta(t, (summary.Components[0].Score+summary.Components[1].Score)/2, summary.Score, 1e-9)
+}
+
+func TestApplicantSummaryScopedToClientAndTenant(t *testing.T) {
+	income := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	s := NewApplicantService(store.NewMemoryApplicantStore(0, 0), income)
+
+	require.NoError(t, s.Record("app-1", "bank-a", "lender-a", dto.KYCDocPAN, "r1", []byte(`{"pan":"ABCPK1234F","name":"RAVI KUMAR"}`)))
+	require.NoError(t, s.Record("app-1", "bank-b", "lender-a", dto.KYCDocPAN, "r2", []byte(`{"pan":"ZZZPZ9999Z","name":"OTHER"}`)))
+	resp := dto.IncomeVerificationResponse{SalarySlips: []dto.SalarySlipData{{EmployeeName: "Other", NetSalary: 1000}}}
+	require.NoError(t, income.store.Save(&dto.VerificationRecord{
+		ID: "v1", ApplicantID: "app-1", TenantID: "bank-b", Client: "lender-a", Extracted: resp, Current: resp, CreatedAt: time.Now(),
+	}))
+
+	summary, err := s.Summary("app-1", "bank-a", "lender-a")
+	require.NoError(t, err)
+	require.Len(t, summary.Documents, 1)
+	assert.Equal(t, "r1", summary.Documents[0].RequestID)
+	assert.Equal(t, "ABCPK1234F", summary.Identity.PAN)
+
+	for _, scope := range [][2]string{{"", "lender-a"}, {"bank-a", "lender-b"}, {"bank-a", ""}} {
+		_, err = s.Summary("app-1", scope[0], scope[1])
+		assert.ErrorIs(t, err, store.ErrNotFound, scope)
+	}
+}
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+)
+
+// BatchItem is one uploaded file of a batch request.
+type BatchItem struct {
+	File     *multipart.FileHeader
+	DocType  string
+	Password string
+	Lang     string // OCR language hint
+	// PasswordHints derive passwords to try on a protected income document
+	PasswordHints *dto.PasswordHints
+}
+
+// BatchService routes each file of a batch to the service for its document type.
+type BatchService struct {
+	aadhaar *AadhaarService
+	pan     *PANService
+	dl      *DrivingLicenseService
+	income  *IncomeService
+}
+
+func NewBatchService(aadhaar *AadhaarService, pan *PANService, dl *DrivingLicenseService, income *IncomeService) *BatchService {
+	return &BatchService{aadhaar: aadhaar, pan: pan, dl: dl, income: income}
+}
+
+// SupportsBatchDocType reports whether docType can be sent in a batch.
+func SupportsBatchDocType(docType string) bool {
+	switch docType {
+	case dto.BatchDocAadhaar, dto.BatchDocPAN, dto.BatchDocDL, dto.BatchDocITR,
+		string(dto.DocTypeSalarySlip), string(dto.DocTypeBankStatement):
+		return true
+	}
+	return false
+}
+
+// Process runs every item and collects the results keyed by filename. Items are
+// processed one after another (the OCR engines are shared); a failing item is
+// reported in its entry and does not stop the rest.
+func (s *BatchService) Process(ctx context.Context, items []BatchItem, tenantID string) *dto.BatchResponse {
+	resp := &dto.BatchResponse{Results: make(map[string]dto.BatchItemResult, len(items))}
+
+	for _, item := range items {
+		entry := dto.BatchItemResult{DocType: item.DocType}
+		result, err := s.processItem(ctx, item, tenantID)
+		if err != nil {
+			slog.WarnContext(ctx, "Batch item failed", "file", item.File.Filename, "doc_type", item.DocType, "error", err)
+			entry.Error = err.Error()
+			resp.Failed++
+		} else {
+			entry.Result = result
+			resp.Succeeded++
+		}
+		resp.Results[item.File.Filename] = entry
+	}
+	return resp
+}
+
+func (s *BatchService) processItem(ctx context.Context, item BatchItem, tenantID string) (interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if item.Lang != "" {
+		ctx = pipeline.WithLanguage(ctx, item.Lang)
+	}
+
+	// The ITR analysis reads the upload itself
+	if item.DocType == dto.BatchDocITR {
+		return s.income.AnalyzeITR(ctx, item.File)
+	}
+
+	f, err := item.File.Open()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	data, err := io.ReadAll(f)
+	f.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	switch item.DocType {
+	case dto.BatchDocAadhaar:
+		mimeType := item.File.Header.Get("Content-Type")
+		return s.aadhaar.ExtractFromFile(ctx, data, mimeType, item.Password)
+	case dto.BatchDocPAN:
+		return s.pan.ExtractPANFromBytes(ctx, data, item.File.Filename)
+	case dto.BatchDocDL:
+		return s.dl.ExtractDLText(ctx, data)
+	case string(dto.DocTypeSalarySlip), string(dto.DocTypeBankStatement):
+		return s.income.ProcessDocument(ctx, data, dto.DocumentMeta{
+			Filename: item.File.Filename,
+			DocType:  dto.DocumentType(item.DocType),
+			Password: item.Password,
+			Lang:     item.Lang,
+
+			PasswordHints: item.PasswordHints,
+		}, tenantID)
+	}
+	return nil, fmt.Errorf("unsupported doc_type %q", item.DocType)
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"mime/multipart"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// uploadedFiles returns the file headers of a multipart form holding files.
+func uploadedFiles(t *testing.T, files map[string]string) map[string]*multipart.FileHeader {
+	var body bytes.Buffer
+	w := multipart.NewWriter(&body)
+	for name, content := range files {
+		fw, err := w.CreateFormFile("files[]", name)
+		require.NoError(t, err)
+		fw.Write([]byte(content))
+	}
+	require.NoError(t, w.Close())
+
+	form, err := multipart.NewReader(&body, w.Boundary()).ReadForm(1 << 20)
+	require.NoError(t, err)
+	t.Cleanup(func() { form.RemoveAll() })
+	headers := make(map[string]*multipart.FileHeader)
+	for _, fh := range form.File["files[]"] {
+		headers[fh.Filename] = fh
+	}
+	return headers
+}
+
+func TestBatchProcessReportsEachFile(t *testing.T) {
+	// A stand-in pipeline: "bad" uploads fail to parse, anything else is a slip
+	defs := &pipeline.Definitions{Default: map[string][]string{"salary_slip": {"fake"}}}
+	income := &IncomeService{requestConcurrency: 2, pipelines: pipeline.NewOrchestrator(defs, pipeline.Registry{
+		"fake": func(string) (pipeline.Step, error) {
+			return pipeline.StepFunc(func(doc *pipeline.Doc) error {
+				if string(doc.Inputs[0]) == "bad" {
+					return errors.New("unreadable")
+				}
+				doc.Result = dto.SalarySlipData{EmployeeName: doc.Filename}
+				return nil
+			}), nil
+		},
+	})}
+	s := NewBatchService(nil, nil, nil, income)
+
+	files := uploadedFiles(t, map[string]string{"jan.pdf": "ok", "feb.pdf": "bad", "card.pdf": "ok"})
+	resp := s.Process(context.Background(), []BatchItem{
+		{File: files["jan.pdf"], DocType: string(dto.DocTypeSalarySlip)},
+		{File: files["feb.pdf"], DocType: string(dto.DocTypeSalarySlip)},
+		{File: files["card.pdf"], DocType: "voter_id"},
+	}, "")
+
+	// one failing file does not stop the others; each is reported on its own
+	assert.Equal(t, 1, resp.Succeeded)
+	assert.Equal(t, 2, resp.Failed)
+	require.Len(t, resp.Results, 3)
+	jan := resp.Results["jan.pdf"]
+	assert.Equal(t, string(dto.DocTypeSalarySlip), jan.DocType)
+	assert.Empty(t, jan.Error)
+	require.IsType(t, dto.SalarySlipData{}, jan.Result)
+	assert.Equal(t, "jan.pdf", jan.Result.(dto.SalarySlipData).EmployeeName)
+	assert.Nil(t, resp.Results["feb.pdf"].Result)
+	assert.Contains(t, resp.Results["feb.pdf"].Error, "unreadable")
+	assert.Contains(t, resp.Results["card.pdf"].Error, `unsupported doc_type "voter_id"`)
+
+	// a cancelled request fails every file rather than running them
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	resp = s.Process(ctx, []BatchItem{{File: files["jan.pdf"], DocType: string(dto.DocTypeSalarySlip)}}, "")
+	assert.Equal(t, 0, resp.Succeeded)
+	assert.Equal(t, 1, resp.Failed)
+	assert.Contains(t, resp.Results["jan.pdf"].Error, context.Canceled.Error())
+}
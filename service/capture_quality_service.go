@@ -0,0 +1,66 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageconv"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageprep"
+	"github.com/Aashish23092/ocr-income-verification/utils/imagequality"
+)
+
+// CaptureQualityService judges a camera frame with the image checks of the
+// OCR pipelines but no OCR, fast enough for a client to ask for a retake
+// before submitting the document.
+type CaptureQualityService struct{}
+
+func NewCaptureQualityService() *CaptureQualityService {
+	return &CaptureQualityService{}
+}
+
+// Check decodes an image (a TIFF by its first page) and scores it as a photo.
+// Images that cannot be decoded, PDFs among them, are an error.
+func (s *CaptureQualityService) Check(data []byte) (*dto.CaptureQualityResponse, error) {
+	img, err := decodeCapture(data)
+	if err != nil {
+		return nil, err
+	}
+
+	m := imagequality.Analyze(img, true)
+	b := img.Bounds()
+	resp := &dto.CaptureQualityResponse{
+		Acceptable:      len(m.Issues) == 0,
+		Width:           b.Dx(),
+		Height:          b.Dy(),
+		ResolutionScore: m.ResolutionScore,
+		ContrastScore:   m.ContrastScore,
+		SharpnessScore:  m.SharpnessScore,
+		Issues:          m.Issues,
+	}
+	if resp.Issues == nil {
+		resp.Issues = []string{}
+	}
+	return resp, nil
+}
+
+func decodeCapture(data []byte) (image.Image, error) {
+	if bytes.HasPrefix(data, []byte("%PDF")) {
+		return nil, fmt.Errorf("quality checks take a camera image, not a PDF")
+	}
+	if imageconv.IsTIFF(data) {
+		for img, err := range imageconv.TIFFPages(data) {
+			return img, err
+		}
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+	if exif, err := utils.ParseEXIF(data); err == nil && exif.Orientation > 1 {
+		img, _ = imageprep.Orient(img, exif.Orientation)
+	}
+	return img, nil
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"log/slog"
+	"math"
+	"net/http"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/imagehash"
+)
+
+// Why a document duplicates another of the request.
+const (
+	duplicateFile  = "identical file"
+	duplicateImage = "same image"
+)
+
+// resubmissionLookback is how many of an applicant's earlier verifications
+// are searched for resubmitted files.
+const resubmissionLookback = 50
+
+// uploadFingerprint identifies the file(s) of one metadata entry. The
+// perceptual hash of an image upload is computed on first use: most requests
+// never need it.
+type uploadFingerprint struct {
+	content string // SHA-256 of the files, hex
+	first   []byte
+	visual  *uint64
+}
+
+func newUploadFingerprint(pages [][]byte) *uploadFingerprint {
+	h := sha256.New()
+	for _, p := range pages {
+		h.Write(p)
+	}
+	return &uploadFingerprint{content: hex.EncodeToString(h.Sum(nil)), first: pages[0]}
+}
+
+// imageHash is the dHash of the first page, for image uploads; ok is false
+// for PDFs and images that do not decode.
+func (f *uploadFingerprint) imageHash() (hash uint64, ok bool) {
+	if f.visual == nil {
+		f.visual = new(uint64)
+		if strings.HasPrefix(http.DetectContentType(f.first), "image/") {
+			if img, _, err := image.Decode(bytes.NewReader(f.first)); err == nil {
+				*f.visual = imagehash.DHash(img)
+			}
+		}
+	}
+	return *f.visual, *f.visual != 0
+}
+
+// duplicateUpload returns the earlier entry of the same document type that
+// entry i repeats, byte for byte or as the same image, and how; -1 when none.
+// Nil fingerprints (entries whose files are missing) are skipped.
+func duplicateUpload(fps []*uploadFingerprint, types []dto.DocumentType, i int) (int, string) {
+	for j := range i {
+		if fps[j] != nil && types[j] == types[i] && fps[j].content == fps[i].content {
+			return j, duplicateFile
+		}
+	}
+	for j := range i {
+		if fps[j] == nil || types[j] != types[i] {
+			continue
+		}
+		a, ok := fps[i].imageHash()
+		if !ok {
+			break
+		}
+		if b, ok := fps[j].imageHash(); ok && imagehash.Distance(a, b) <= imagehash.DuplicateDistance {
+			return j, duplicateImage
+		}
+	}
+	return -1, ""
+}
+
+// markDuplicate drops result drop as a repeat of keep.
+func markDuplicate(results []interface{}, statuses []dto.DocumentStatus, drop, keep int, reason string) {
+	results[drop] = nil
+	statuses[drop].Status = dto.DocumentDuplicate
+	statuses[drop].DuplicateOf = statuses[keep].Filename
+	statuses[drop].DuplicateReason = reason
+}
+
+// dedupeResults drops parsed documents that cover what another already does,
+// so the same month does not count twice: salary slips of the same pay month
+// and employer (the better-quality one is kept), and bank statements of the
+// same account and period. It returns cross-check notes for repeats that
+// disagree.
+func dedupeResults(results []interface{}, statuses []dto.DocumentStatus) []string {
+	var notes []string
+	slipsByMonth := map[string][]int{}
+	statements := map[string]int{}
+	for i, r := range results {
+		switch v := r.(type) {
+		case dto.SalarySlipData:
+			if v.PayMonth == "" {
+				continue
+			}
+			kept := slipsByMonth[v.PayMonth]
+			k := -1
+			for n, j := range kept {
+				if sameEmployer(results[j].(dto.SalarySlipData).EmployerName, v.EmployerName) {
+					k = n
+					break
+				}
+			}
+			if k < 0 {
+				slipsByMonth[v.PayMonth] = append(kept, i)
+				continue
+			}
+			keep, drop := kept[k], i
+			prev := results[keep].(dto.SalarySlipData)
+			if v.Quality.FinalScore > prev.Quality.FinalScore {
+				keep, drop = i, keep
+				kept[k] = i
+			}
+			if math.Abs(prev.NetSalary-v.NetSalary) > 1 {
+				notes = append(notes, fmt.Sprintf("Salary slips %s and %s are both for %s but show net pay %.2f and %.2f",
+					statuses[kept[k]].Filename, statuses[drop].Filename, v.PayMonth,
+					results[keep].(dto.SalarySlipData).NetSalary, results[drop].(dto.SalarySlipData).NetSalary))
+			}
+			markDuplicate(results, statuses, drop, keep, "same pay month "+v.PayMonth)
+		case dto.BankStatementData:
+			if v.AccountNumber == "" || v.PeriodFrom == nil || v.PeriodTo == nil {
+				continue
+			}
+			key := v.AccountNumber + "|" + v.PeriodFrom.Format("2006-01-02") + "|" + v.PeriodTo.Format("2006-01-02")
+			if j, ok := statements[key]; ok {
+				markDuplicate(results, statuses, i, j, "same account and statement period")
+				continue
+			}
+			statements[key] = i
+		}
+	}
+	return notes
+}
+
+// sameEmployer treats slips without an employer as the same employer's.
+func sameEmployer(a, b string) bool {
+	return a == "" || b == "" || utils.NameMatchScore(a, b) >= utils.NameMatchThreshold
+}
+
+// markResubmissions points each document at the applicant's earlier
+// verification, requested by the same client, that had the identical file.
+func (s *IncomeService) markResubmissions(metadata dto.UploadMetadata, statuses []dto.DocumentStatus) {
+	earlier, err := s.store.List(store.ListFilter{
+		Client:      metadata.Client,
+		ApplicantID: metadata.ApplicantID,
+		TenantID:    metadata.TenantID,
+		Limit:       resubmissionLookback,
+	})
+	if err != nil {
+		slog.Warn("Earlier verifications not searched for resubmissions", "error", err)
+		return
+	}
+	seen := map[string]string{}
+	for _, rec := range earlier { // newest first: the latest one wins
+		for _, d := range rec.Extracted.Documents {
+			if _, ok := seen[d.ContentHash]; d.ContentHash != "" && !ok {
+				seen[d.ContentHash] = rec.ID
+			}
+		}
+	}
+	for i := range statuses {
+		if id, ok := seen[statuses[i].ContentHash]; ok && statuses[i].ContentHash != "" {
+			statuses[i].PreviousVerificationID = id
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+// DocumentParser reads the text of one income document type into its typed
+// result. layout holds the OCR words with their boxes, for parsers that use
+// columns; it is empty for PDFs read from their text layer.
+type DocumentParser[T any] interface {
+	Parse(text string, layout []dto.OCRWord) (T, error)
+}
+
+// ParserFunc adapts a function to DocumentParser.
+type ParserFunc[T any] func(text string, layout []dto.OCRWord) (T, error)
+
+func (f ParserFunc[T]) Parse(text string, layout []dto.OCRWord) (T, error) {
+	return f(text, layout)
+}
+
+// StreamingParser is a DocumentParser that can also read a document page by
+// page as OCR reads the pages, instead of all at once at the end.
+type StreamingParser[T any] interface {
+	DocumentParser[T]
+	NewStream() PageStream[T]
+}
+
+// PageStream parses a document a page at a time.
+type PageStream[T any] interface {
+	AddPage(text string)
+	Pages() int
+	// Result returns what the pages added so far read as; text and layout
+	// are the whole document's, as Parse would get them
+	Result(text string, layout []dto.OCRWord) T
+}
+
+// ParserRegistry maps income document types to their parsers, so that
+// IncomeService handles any type registered without knowing it. Register
+// parsers before the service is created; the registry is not safe for
+// concurrent registration.
+type ParserRegistry struct {
+	parsers map[dto.DocumentType]registeredParser
+}
+
+// registeredParser is a parser with its result type erased.
+type registeredParser struct {
+	// prepare readies doc before its pipeline runs
+	prepare func(doc *pipeline.Doc)
+	parse   func(doc *pipeline.Doc, text string) (interface{}, error)
+	// cached runs run through the result cache
+	cached func(o *pipeline.Orchestrator, doc *pipeline.Doc, run func() (interface{}, error)) (interface{}, error)
+	// finish gives the result the pipeline's assessment once it has run
+	finish func(result interface{}, doc *pipeline.Doc) (interface{}, error)
+}
+
+func NewParserRegistry() *ParserRegistry {
+	return &ParserRegistry{parsers: map[dto.DocumentType]registeredParser{}}
+}
+
+// RegisterParser adds the parser of docType, whose results are a T,
+// replacing any earlier one. attach, when not nil, gives a result the
+// document's quality assessment (and provenance) once the pipeline has run.
+func RegisterParser[T any](r *ParserRegistry, docType dto.DocumentType, p DocumentParser[T], attach func(result *T, doc *pipeline.Doc)) {
+	r.parsers[docType] = registeredParser{
+		prepare: func(doc *pipeline.Doc) {
+			if sp, ok := p.(StreamingParser[T]); ok {
+				stream := sp.NewStream()
+				doc.OnPage = stream.AddPage
+				doc.Result = stream
+			}
+		},
+		parse: func(doc *pipeline.Doc, text string) (interface{}, error) {
+			if stream, ok := doc.Result.(PageStream[T]); ok && stream.Pages() > 0 {
+				return stream.Result(text, doc.Words), nil
+			}
+			return p.Parse(text, doc.Words)
+		},
+		cached: func(o *pipeline.Orchestrator, doc *pipeline.Doc, run func() (interface{}, error)) (interface{}, error) {
+			return cachedAs[T](o, doc, run)
+		},
+		finish: func(result interface{}, doc *pipeline.Doc) (interface{}, error) {
+			v, ok := result.(T)
+			if !ok {
+				return nil, fmt.Errorf("unexpected %s result %T", doc.DocType, result)
+			}
+			if attach != nil {
+				attach(&v, doc)
+			}
+			return v, nil
+		},
+	}
+}
+
+func (r *ParserRegistry) lookup(docType string) (registeredParser, bool) {
+	p, ok := r.parsers[dto.DocumentType(docType)]
+	return p, ok
+}
+
+// Types returns the registered document types, sorted.
+func (r *ParserRegistry) Types() []string {
+	types := make([]string, 0, len(r.parsers))
+	for t := range r.parsers {
+		types = append(types, string(t))
+	}
+	sort.Strings(types)
+	return types
+}
+
+// DefaultParsers are the parsers of the income document types; register
+// further types here, with a pipeline for them, before creating the
+// IncomeService.
+var DefaultParsers = defaultParsers()
+
+func defaultParsers() *ParserRegistry {
+	r := NewParserRegistry()
+	RegisterParser(r, dto.DocTypeSalarySlip, ParserFunc[dto.SalarySlipData](func(text string, layout []dto.OCRWord) (dto.SalarySlipData, error) {
+		data := utils.ParseSalarySlip(text)
+		utils.RefineSalarySlipWithLayout(&data, layout)
+		data.CIN = utils.ExtractCIN(text)
+		data.PIIFound = utils.SummarizePII(utils.ScanPII(text))
+		return data, nil
+	}), func(v *dto.SalarySlipData, doc *pipeline.Doc) {
+		v.Quality, v.Provenance = doc.Quality, doc.Provenance
+	})
+	RegisterParser(r, dto.DocTypeBankStatement, bankStatementParser{}, func(v *dto.BankStatementData, doc *pipeline.Doc) {
+		v.Quality, v.Provenance = doc.Quality, doc.Provenance
+	})
+	RegisterParser(r, dto.DocTypeGSTReturn, textParser(utils.ParseGST, func(v *dto.GSTData, pii dto.PIISummary) { v.PIIFound = pii }),
+		func(v *dto.GSTData, doc *pipeline.Doc) { v.Quality = doc.Quality })
+	RegisterParser(r, dto.DocTypeForm26AS, textParser(utils.ParseForm26AS, func(v *dto.Form26ASData, pii dto.PIISummary) { v.PIIFound = pii }),
+		func(v *dto.Form26ASData, doc *pipeline.Doc) { v.Quality = doc.Quality })
+	RegisterParser(r, dto.DocTypeRent, textParser(utils.ParseRent, func(v *dto.RentData, pii dto.PIISummary) { v.PIIFound = pii }),
+		func(v *dto.RentData, doc *pipeline.Doc) { v.Quality = doc.Quality })
+	RegisterParser(r, dto.DocTypeEPFPassbook, textParser(utils.ParseEPFPassbook, func(v *dto.EPFPassbookData, pii dto.PIISummary) { v.PIIFound = pii }),
+		func(v *dto.EPFPassbookData, doc *pipeline.Doc) { v.Quality = doc.Quality })
+	return r
+}
+
+// textParser is a parser reading text only, its result given the PII found
+// in the text through setPII.
+func textParser[T any](parse func(text string) T, setPII func(*T, dto.PIISummary)) DocumentParser[T] {
+	return ParserFunc[T](func(text string, _ []dto.OCRWord) (T, error) {
+		data := parse(text)
+		setPII(&data, utils.SummarizePII(utils.ScanPII(text)))
+		return data, nil
+	})
+}
+
+// bankStatementParser parses statements page by page as OCR reads them;
+// word boxes, when there are any, put each amount under its debit, credit or
+// balance column.
+type bankStatementParser struct{}
+
+func (bankStatementParser) Parse(text string, layout []dto.OCRWord) (dto.BankStatementData, error) {
+	return withStatementTable(utils.ParseBankStatement(text), text, layout), nil
+}
+
+func (bankStatementParser) NewStream() PageStream[dto.BankStatementData] {
+	return statementStream{utils.NewStatementStream()}
+}
+
+type statementStream struct {
+	*utils.StatementStream
+}
+
+func (s statementStream) Result(text string, layout []dto.OCRWord) dto.BankStatementData {
+	return withStatementTable(s.StatementStream.Result(), text, layout)
+}
+
+func withStatementTable(data dto.BankStatementData, text string, layout []dto.OCRWord) dto.BankStatementData {
+	if tx := utils.ParseStatementTable(layout); len(tx) > 0 {
+		data.Transactions = tx
+	}
+	data.PIIFound = utils.SummarizePII(utils.ScanPII(text))
+	return data
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type form16Data struct {
+	Employer string
+	Score    float64
+}
+
+func TestRegisteredParser(t *testing.T) {
+	// A type the service knows nothing of, read by a stand-in OCR step
+	parsers := NewParserRegistry()
+	RegisterParser(parsers, "form_16", ParserFunc[form16Data](func(text string, _ []dto.OCRWord) (form16Data, error) {
+		return form16Data{Employer: text}, nil
+	}), func(v *form16Data, doc *pipeline.Doc) { v.Score = doc.Quality.FinalScore })
+
+	defs := &pipeline.Definitions{Default: map[string][]string{"form_16": {"fake", "parse"}}}
+	s := &IncomeService{parsers: parsers}
+	var err error
+	s.pipelines, err = pipeline.NewOrchestrator(defs, pipeline.Registry{
+		"fake": noArg(func(doc *pipeline.Doc) error {
+			doc.Text = string(doc.Inputs[0])
+			doc.Quality.FinalScore = 0.9
+			return nil
+		}),
+	}).Extend(pipeline.Registry{"parse": noArg(s.parseStep)}, parsers.Types()...)
+	require.NoError(t, err)
+
+	result, err := s.runPipeline(&pipeline.Doc{DocType: "form_16", Inputs: [][]byte{[]byte("Acme Ltd")}})
+	require.NoError(t, err)
+	assert.Equal(t, form16Data{Employer: "Acme Ltd", Score: 0.9}, result)
+
+	_, err = s.runPipeline(&pipeline.Doc{DocType: "gst_return", Inputs: [][]byte{[]byte("x")}})
+	assert.ErrorContains(t, err, "unknown document type")
+}
+
+func TestBankStatementParserStreams(t *testing.T) {
+	p, ok := DefaultParsers.lookup(string(dto.DocTypeBankStatement))
+	require.True(t, ok)
+	doc := &pipeline.Doc{DocType: string(dto.DocTypeBankStatement)}
+	p.prepare(doc)
+	require.NotNil(t, doc.OnPage)
+	doc.OnPage("Account Number: 1234567890\n01/01/2024 SALARY ACME 50,000.00 60,000.00")
+
+	result, err := p.parse(doc, "")
+	require.NoError(t, err)
+	assert.Equal(t, "1234567890", result.(dto.BankStatementData).AccountNumber)
+}
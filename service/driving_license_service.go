@@ -1,61 +1,62 @@
 package service
 
 import (
-	"log"
+	"context"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 
 	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	addressparser "github.com/Aashish23092/ocr-income-verification/utils/address"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
 )
 
 type DrivingLicenseService struct {
-	paddle    *client.PaddleClient
-	tesseract *client.TesseractClient
+	paddle    client.PaddleEngine
+	tesseract client.TesseractEngine
+	pipelines *pipeline.Orchestrator
 }
 
-func NewDrivingLicenseService(paddle *client.PaddleClient, tesseract *client.TesseractClient) *DrivingLicenseService {
-	return &DrivingLicenseService{
+func NewDrivingLicenseService(paddle client.PaddleEngine, tesseract client.TesseractEngine, pipelines *pipeline.Orchestrator) (*DrivingLicenseService, error) {
+	s := &DrivingLicenseService{
 		paddle:    paddle,
 		tesseract: tesseract,
 	}
-}
-
-type DLResult struct {
-	Name      string `json:"name"`
-	DLNumber  string `json:"dl_number"`
-	DOB       string `json:"dob"`
-	IssueDate string `json:"issue_date"`
-	ValidTill string `json:"valid_till"`
-	Address   string `json:"address"`
-	RawText   string `json:"raw_text"`
-}
 
-func (s *DrivingLicenseService) ExtractDLText(imageBytes []byte) (*DLResult, error) {
-	var raw string
 	var err error
-
-	// -----------------------------
-	// 1️⃣ Try PaddleOCR first
-	// -----------------------------
-	if s.paddle != nil {
-		raw, err = s.paddle.ExtractText(imageBytes)
-		if err == nil && len(raw) > 10 {
-			log.Println("Driving License: PaddleOCR succeeded")
-			return s.parseDL(raw), nil
-		}
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"parse": noArg(func(doc *pipeline.Doc) error {
+			doc.Result = s.parseDL(doc.Text)
+			return nil
+		}),
+	}, "driving_license")
+	if err != nil {
+		return nil, err
 	}
+	return s, nil
+}
 
-	// -----------------------------
-	// 2️⃣ Tesseract fallback
-	// -----------------------------
-	raw, err = s.tesseract.ExtractTextFromBytes(imageBytes)
+func (s *DrivingLicenseService) ExtractDLText(ctx context.Context, imageBytes []byte) (*dto.DLResponse, error) {
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "driving_license", Inputs: [][]byte{imageBytes}}
+	result, err := pipeline.Cached(s.pipelines, doc, func() (*dto.DLResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.DLResponse)
+		if !ok {
+			return nil, fmt.Errorf("driving license pipeline produced no result")
+		}
+		return result, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-
-	log.Println("Driving License: Fallback Tesseract used")
-	return s.parseDL(raw), nil
+	result.Photo = portraitPhoto(ctx, face.DocDL, doc.Inputs, nil, "")
+	return result, nil
 }
 
 // parseDate tries to parse dd/mm/yyyy into time.Time. Returns zero time on failure.
@@ -78,15 +79,14 @@ func parseDate(s string) (time.Time, bool) {
 	return t, true
 }
 
-func (s *DrivingLicenseService) parseDL(raw string) *DLResult {
+func (s *DrivingLicenseService) parseDL(raw string) *dto.DLResponse {
 	text := strings.ToUpper(raw)
 
 	// general date regex (DD/MM/YYYY)
 	reAnyDate := regexp.MustCompile(`\d{2}[/\-\.]\d{2}[/\-\.]\d{4}`)
 
-	// 1) DL Number: common formats (two letters + two digits + rest)
-	reDL := regexp.MustCompile(`\b[A-Z]{2}\s?\d{2}\s?\d{6,12}\b`)
-	dlNumber := reDL.FindString(text)
+	// 1) DL Number: the Sarathi format (state, RTO, year, serial), else as printed
+	dlNumber, dlNumberValid := utils.FindDLNumber(text)
 
 	// 2) All dates in order of appearance
 	allDates := reAnyDate.FindAllString(text, -1)
@@ -238,13 +238,22 @@ func (s *DrivingLicenseService) parseDL(raw string) *DLResult {
 		}
 	}
 
-	return &DLResult{
-		Name:      name,
-		DLNumber:  dlNumber,
-		DOB:       dobStr,
-		IssueDate: issueStr,
-		ValidTill: validStr,
-		Address:   address,
-		RawText:   raw,
+	result := &dto.DLResponse{
+		Name:           name,
+		DLNumber:       dlNumber,
+		DLNumberValid:  dlNumberValid,
+		DOB:            dobStr,
+		IssueDate:      issueStr,
+		ValidTill:      validStr,
+		VehicleClasses: utils.ParseDLClasses(text),
+		Address:        address,
+		RawText:        raw,
+	}
+	result.DOBISO, _ = utils.NormalizeDOB(dobStr)
+	result.IsExpired, _ = utils.DLExpired(validStr, time.Now())
+	if address != "" {
+		parts := addressparser.Parse(address)
+		result.AddressParts = &parts
 	}
+	return result
 }
@@ -1,18 +1,29 @@
 package service
 
 import (
-	"errors"
-	"log"
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"log/slog"
 	"strings"
 
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+
 	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils"
 
 	appointmentletter "github.com/Aashish23092/ocr-income-verification/utils/appointmentletter"
 	employeeid "github.com/Aashish23092/ocr-income-verification/utils/employeeid"
 )
 
 type PaddleOCR interface {
-	ExtractText([]byte) (string, error)
+	ExtractText(context.Context, []byte) (string, error)
 }
 
 type EmployeeService struct {
@@ -23,29 +34,26 @@ func NewEmployeeService(ocr PaddleOCR) *EmployeeService {
 	return &EmployeeService{ocr: ocr}
 }
 
-func (s *EmployeeService) ProcessEmployeeDocs(empCard, appLetter []byte) (*dto.EmployeeVerifyResponse, error) {
+func (s *EmployeeService) ProcessEmployeeDocs(ctx context.Context, empCard, appLetter []byte) (*dto.EmployeeVerifyResponse, error) {
 
 	// ------------------------
 	// OCR Employee ID Card
 	// ------------------------
-	empText, err := s.ocr.ExtractText(empCard)
+	empText, err := s.ocr.ExtractText(ctx, empCard)
 	if err != nil {
-		return nil, errors.New("failed to OCR employee ID card")
+		return nil, fmt.Errorf("employee ID card: %w", ErrOCRFailed)
 	}
-	log.Println("---- EMPLOYEE ID OCR TEXT ----")
-	log.Println(empText)
+	slog.Debug("Employee ID OCR text", "chars", len(empText), "lines", strings.Count(empText, "\n")+1)
 
 	// ------------------------
 	// OCR Appointment Letter
 	// ------------------------
-	appText, err := s.ocr.ExtractText(appLetter)
+	appText, err := s.ocr.ExtractText(ctx, appLetter)
 	if err != nil {
-		return nil, errors.New("failed to OCR appointment letter")
+		return nil, fmt.Errorf("appointment letter: %w", ErrOCRFailed)
 	}
 
-	// 🔥 ADD THIS
-	log.Println("---- APPOINTMENT LETTER OCR TEXT ----")
-	log.Println(appText)
+	slog.Debug("Appointment letter OCR text", "chars", len(appText), "lines", strings.Count(appText, "\n")+1)
 
 	// ------------------------
 	// Parse Employee ID Card
@@ -55,6 +63,16 @@ func (s *EmployeeService) ProcessEmployeeDocs(empCard, appLetter []byte) (*dto.E
 		EmployeeID:  employeeid.ParseEmployeeID(empText),
 		Company:     employeeid.ParseCompanyID(empText),
 		Designation: employeeid.ParseDesignationID(empText),
+		BloodGroup:  employeeid.ParseBloodGroup(empText),
+	}
+	empData.ValidFrom, empData.ValidUntil = employeeid.ParseValidity(empText)
+	if code := decodeBadgeCode(empCard); code != "" {
+		empData.BadgeCode = code
+		badge := employeeid.ParseBadgeCode(code)
+		empData.Name = cmp.Or(badge.Name, empData.Name)
+		empData.EmployeeID = cmp.Or(badge.EmployeeID, empData.EmployeeID)
+		empData.Company = cmp.Or(badge.Company, empData.Company)
+		empData.Designation = cmp.Or(badge.Designation, empData.Designation)
 	}
 
 	// ------------------------
@@ -72,8 +90,9 @@ func (s *EmployeeService) ProcessEmployeeDocs(empCard, appLetter []byte) (*dto.E
 	// Validation
 	// ------------------------
 	validation := dto.ValidationResult{
-		NameMatch:    strings.EqualFold(empData.Name, appData.Name),
-		CompanyMatch: strings.EqualFold(empData.Company, appData.Company),
+		NameMatch:    utils.NamesMatch(empData.Name, appData.Name),
+		NameScore:    utils.NameMatchScore(empData.Name, appData.Name),
+		CompanyMatch: empData.Company != "" && utils.SameParty(empData.Company, appData.Company),
 	}
 
 	// ------------------------
@@ -87,3 +106,34 @@ func (s *EmployeeService) ProcessEmployeeDocs(empCard, appLetter []byte) (*dto.E
 
 	return &resp, nil
 }
+
+// badgeReaders decode the codes printed on employee badges: QR codes, and the
+// 1D barcodes access-control systems use.
+var badgeReaders = []func() gozxing.Reader{
+	qrcode.NewQRCodeReader,
+	oned.NewCode128Reader,
+	oned.NewCode39Reader,
+	oned.NewITFReader,
+	oned.NewEAN13Reader,
+}
+
+// decodeBadgeCode returns the text of the first QR code or barcode found on
+// the badge image, or "" when there is none (or the upload is not an image).
+func decodeBadgeCode(data []byte) string {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return ""
+	}
+	bmp, err := gozxing.NewBinaryBitmapFromImage(img)
+	if err != nil {
+		return ""
+	}
+	hints := map[gozxing.DecodeHintType]interface{}{gozxing.DecodeHintType_TRY_HARDER: true}
+	for _, reader := range badgeReaders {
+		if result, err := reader().Decode(bmp, hints); err == nil && result.GetText() != "" {
+			slog.Debug("Badge code decoded", "format", result.GetBarcodeFormat().String(), "bytes", len(result.GetText()))
+			return result.GetText()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,31 @@
+package service
+
+import (
+	"bytes"
+	"image/png"
+	"testing"
+
+	"github.com/makiuchi-d/gozxing"
+	"github.com/makiuchi-d/gozxing/oned"
+	"github.com/makiuchi-d/gozxing/qrcode"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeBadgeCode(t *testing.T) {
+	encode := func(w gozxing.Writer, format gozxing.BarcodeFormat, text string, width, height int) []byte {
+		m, err := w.Encode(text, format, width, height, nil)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, m))
+		return buf.Bytes()
+	}
+
+	qr := encode(qrcode.NewQRCodeWriter(), gozxing.BarcodeFormat_QR_CODE, `{"emp_id":"EMP-10234"}`, 200, 200)
+	assert.Equal(t, `{"emp_id":"EMP-10234"}`, decodeBadgeCode(qr))
+
+	barcode := encode(oned.NewCode128Writer(), gozxing.BarcodeFormat_CODE_128, "EMP10234", 300, 80)
+	assert.Equal(t, "EMP10234", decodeBadgeCode(barcode))
+
+	assert.Empty(t, decodeBadgeCode([]byte("%PDF-1.7")))
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils/imagequality"
+)
+
+// Errors of the document rather than the service, shared by the document
+// types; handlers map them to their dto error codes.
+var (
+	// ErrPDFEncrypted means a PDF could not be opened with the password given.
+	ErrPDFEncrypted = errors.New("PDF is password-protected and the password is missing or wrong")
+	// ErrOCRFailed means no text could be read from the document.
+	ErrOCRFailed = errors.New("no text could be read from the document")
+	// ErrLowQuality is ErrOCRFailed on a capture too blurry or too small to read.
+	ErrLowQuality = errors.New("the document image is too blurry or too small to read")
+	// ErrParseEmpty means text was read, but none of the document's fields.
+	ErrParseEmpty = errors.New("none of the document's fields were found in its text")
+)
+
+// ocrFailed wraps the error of a document OCR could not read: ErrLowQuality
+// when its pages were found blurry or of low resolution, else ErrOCRFailed.
+func ocrFailed(doc *pipeline.Doc, err error) error {
+	cause := ErrOCRFailed
+	if slices.Contains(doc.Quality.Issues, imagequality.IssueBlurry) || slices.Contains(doc.Quality.Issues, imagequality.IssueLowResolution) {
+		cause = ErrLowQuality
+	}
+	if err == nil {
+		return cause
+	}
+	return fmt.Errorf("%w: %w", cause, err)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+)
+
+// ErrNoDocumentFace is returned when no portrait is found on the ID document.
+var ErrNoDocumentFace = errors.New("no face found on the ID document")
+
+// FaceMatchService matches the portrait of an ID document against a selfie.
+type FaceMatchService struct {
+	embedder  client.FaceEmbedder
+	pdf       PDFProcessor
+	threshold float64
+}
+
+// NewFaceMatchService returns an error when no embedding backend is configured.
+func NewFaceMatchService(embedder client.FaceEmbedder, pdf PDFProcessor, threshold float64) (*FaceMatchService, error) {
+	if embedder == nil {
+		return nil, fmt.Errorf("face match needs a face embedding backend")
+	}
+	return &FaceMatchService{embedder: embedder, pdf: pdf, threshold: threshold}, nil
+}
+
+// SupportsFaceMatchDocType reports whether docType carries a portrait.
+func SupportsFaceMatchDocType(docType string) bool {
+	switch docType {
+	case face.DocAadhaar, face.DocPAN, face.DocDL, face.DocPassport:
+		return true
+	}
+	return false
+}
+
+// Match crops the portrait from the document (first page of a PDF) and the
+// face from the selfie, and compares their embeddings.
+func (s *FaceMatchService) Match(ctx context.Context, document []byte, docType, password string, selfie []byte) (*dto.FaceMatchResponse, error) {
+	docImg, err := documentImage(ctx, document, s.pdf, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ID document: %w", err)
+	}
+	docFace, ok := face.Crop(docImg, docType)
+	if !ok {
+		return nil, ErrNoDocumentFace
+	}
+
+	selfieImg, err := documentImage(ctx, selfie, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read selfie: %w", err)
+	}
+	// A selfie is mostly face already; fall back to the whole frame
+	selfieFace, selfieFound := face.Crop(selfieImg, "")
+	if !selfieFound {
+		selfieFace = selfieImg
+	}
+
+	docEmb, err := s.embed(docFace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed document face: %w", err)
+	}
+	selfieEmb, err := s.embed(selfieFace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed selfie face: %w", err)
+	}
+
+	sim, err := cosineSimilarity(docEmb, selfieEmb)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.FaceMatchResponse{
+		DocType:         docType,
+		Similarity:      math.Round(sim*1000) / 1000,
+		Threshold:       s.threshold,
+		Match:           sim >= s.threshold,
+		SelfieFaceFound: selfieFound,
+	}, nil
+}
+
+func (s *FaceMatchService) embed(img image.Image) ([]float32, error) {
+	data, err := face.JPEG(img)
+	if err != nil {
+		return nil, err
+	}
+	return s.embedder.Embed(data)
+}
+
+func cosineSimilarity(a, b []float32) (float64, error) {
+	if len(a) != len(b) {
+		return 0, fmt.Errorf("embedding sizes differ (%d vs %d)", len(a), len(b))
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0, fmt.Errorf("zero embedding")
+	}
+	return dot / math.Sqrt(na*nb), nil
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeEmbedder returns the same embedding for every face.
+type fakeEmbedder struct{ calls int }
+
+func (f *fakeEmbedder) Embed([]byte) ([]float32, error) {
+	f.calls++
+	return []float32{0.6, 0.8, 0}, nil
+}
+
+func pngWithFace(t *testing.T, w, h int, face image.Rectangle) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	if !face.Empty() {
+		draw.Draw(img, face, image.NewUniform(color.RGBA{R: 198, G: 134, B: 104, A: 255}), image.Point{}, draw.Src)
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestFaceMatch(t *testing.T) {
+	emb := &fakeEmbedder{}
+	svc, err := NewFaceMatchService(emb, nil, 0.4)
+	require.NoError(t, err)
+
+	card := pngWithFace(t, 860, 540, image.Rect(110, 200, 230, 380))
+	selfie := pngWithFace(t, 400, 500, image.Rect(100, 100, 300, 380))
+
+	res, err := svc.Match(context.Background(), card, "aadhaar", "", selfie)
+	require.NoError(t, err)
+	assert.Equal(t, 1.0, res.Similarity)
+	assert.True(t, res.Match)
+	assert.True(t, res.SelfieFaceFound)
+	assert.Equal(t, 2, emb.calls)
+
+	_, err = svc.Match(context.Background(), pngWithFace(t, 860, 540, image.Rectangle{}), "pan", "", selfie)
+	assert.ErrorIs(t, err, ErrNoDocumentFace)
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"cmp"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+)
+
+// ErrInvalidFeedback is returned for feedback without fields, or on another
+// client's request.
+var ErrInvalidFeedback = errors.New("invalid feedback")
+
+// maxRecordedFields bounds the fields kept of one response (bank statements
+// run to thousands of transaction fields).
+const maxRecordedFields = 2000
+
+// FeedbackService records what every extraction request returned and scores
+// it against the values the integrator reports having used, so that parser
+// accuracy can be compared release over release. Values are stored as keyed
+// hashes: only equality is needed, and the extractions hold PII.
+type FeedbackService struct {
+	store   store.FeedbackStore
+	key     []byte
+	release string
+}
+
+// NewFeedbackService tags recorded extractions with release; key is the
+// HMAC key of the stored value hashes.
+func NewFeedbackService(st store.FeedbackStore, key []byte, release string) *FeedbackService {
+	return &FeedbackService{store: st, key: key, release: release}
+}
+
+// Record keeps the scalar fields of a JSON extraction response under the
+// request ID it was served for.
+func (s *FeedbackService) Record(requestID, client, docType string, body []byte) error {
+	var tree interface{}
+	if err := json.Unmarshal(body, &tree); err != nil {
+		return err
+	}
+	fields := map[string]string{}
+	flattenFields(tree, "", func(path string, v interface{}) bool {
+		fields[path] = s.hash(v)
+		return len(fields) < maxRecordedFields
+	})
+	return s.store.SaveExtraction(&store.Extraction{
+		RequestID:   requestID,
+		Client:      client,
+		DocType:     docType,
+		Release:     s.release,
+		Fields:      fields,
+		ExtractedAt: time.Now().UTC(),
+	})
+}
+
+// Submit compares the reported values with the recorded extraction and
+// stores the outcome. Feedback sent again for a request replaces the earlier.
+func (s *FeedbackService) Submit(client string, req dto.FeedbackRequest) (*dto.FeedbackResult, error) {
+	if len(req.Fields) == 0 {
+		return nil, fmt.Errorf("%w: no fields given", ErrInvalidFeedback)
+	}
+	ex, err := s.store.GetExtraction(req.RequestID)
+	if err != nil {
+		return nil, err
+	}
+	if ex.Client != client {
+		// another client's request IDs are not disclosed
+		return nil, store.ErrNotFound
+	}
+
+	res := &dto.FeedbackResult{RequestID: ex.RequestID, DocType: ex.DocType, Release: ex.Release}
+	for _, field := range slices.Sorted(maps.Keys(req.Fields)) {
+		if field == "" {
+			return nil, fmt.Errorf("%w: empty field path", ErrInvalidFeedback)
+		}
+		got, extracted := ex.Fields[field]
+		fb := dto.FieldFeedback{Field: field, Extracted: extracted}
+		fb.Correct = extracted && hmac.Equal([]byte(got), []byte(s.hash(req.Fields[field])))
+		if fb.Correct {
+			res.Correct++
+		}
+		res.Fields = append(res.Fields, fb)
+	}
+	res.Compared = len(res.Fields)
+
+	err = s.store.SaveFeedback(&dto.FeedbackRecord{
+		RequestID:  ex.RequestID,
+		Client:     client,
+		DocType:    ex.DocType,
+		Release:    ex.Release,
+		Fields:     res.Fields,
+		ReceivedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// Accuracy aggregates the stored feedback per release, document type and
+// field; release and docType, when not empty, filter the groups.
+func (s *FeedbackService) Accuracy(release, docType string) (*dto.AccuracyReport, error) {
+	records, err := s.store.ListFeedback()
+	if err != nil {
+		return nil, err
+	}
+
+	type groupKey struct{ release, docType string }
+	groups := map[groupKey]*dto.AccuracyGroup{}
+	fields := map[groupKey]map[string]*dto.FieldAccuracy{}
+	for _, rec := range records {
+		if (release != "" && rec.Release != release) || (docType != "" && rec.DocType != docType) {
+			continue
+		}
+		k := groupKey{rec.Release, rec.DocType}
+		g, ok := groups[k]
+		if !ok {
+			g = &dto.AccuracyGroup{Release: rec.Release, DocType: rec.DocType}
+			groups[k], fields[k] = g, map[string]*dto.FieldAccuracy{}
+		}
+		g.Requests++
+		for _, fb := range rec.Fields {
+			name := fieldPattern(fb.Field)
+			f, ok := fields[k][name]
+			if !ok {
+				f = &dto.FieldAccuracy{Field: name}
+				fields[k][name] = f
+			}
+			f.Compared++
+			g.Compared++
+			switch {
+			case fb.Correct:
+				f.Correct++
+				g.Correct++
+			case !fb.Extracted:
+				f.Missed++
+			}
+		}
+	}
+
+	report := &dto.AccuracyReport{Groups: []dto.AccuracyGroup{}}
+	for k, g := range groups {
+		g.Accuracy = ratio(g.Correct, g.Compared)
+		for _, f := range fields[k] {
+			f.Accuracy = ratio(f.Correct, f.Compared)
+			g.Fields = append(g.Fields, *f)
+		}
+		slices.SortFunc(g.Fields, func(a, b dto.FieldAccuracy) int { return strings.Compare(a.Field, b.Field) })
+		report.Groups = append(report.Groups, *g)
+	}
+	slices.SortFunc(report.Groups, func(a, b dto.AccuracyGroup) int {
+		return cmp.Or(strings.Compare(a.Release, b.Release), strings.Compare(a.DocType, b.DocType))
+	})
+	return report, nil
+}
+
+func (s *FeedbackService) hash(v interface{}) string {
+	mac := hmac.New(sha256.New, s.key)
+	mac.Write([]byte(normalizeFeedbackValue(v)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// flattenFields calls fn with the dot path of every scalar below node, array
+// indices as path segments, until fn returns false. Nulls are skipped: they
+// are fields the response did not fill.
+func flattenFields(node interface{}, path string, fn func(path string, v interface{}) bool) bool {
+	join := func(seg string) string {
+		if path == "" {
+			return seg
+		}
+		return path + "." + seg
+	}
+	switch n := node.(type) {
+	case map[string]interface{}:
+		for _, k := range slices.Sorted(maps.Keys(n)) {
+			if !flattenFields(n[k], join(k), fn) {
+				return false
+			}
+		}
+	case []interface{}:
+		for i, v := range n {
+			if !flattenFields(v, join(strconv.Itoa(i)), fn) {
+				return false
+			}
+		}
+	case nil:
+	default:
+		if path != "" {
+			return fn(path, n)
+		}
+	}
+	return true
+}
+
+// normalizeFeedbackValue makes values that differ only in case, spacing or
+// number formatting ("45000" and 45000.0) compare equal.
+func normalizeFeedbackValue(v interface{}) string {
+	switch x := v.(type) {
+	case nil:
+		return ""
+	case float64:
+		return strconv.FormatFloat(x, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(x)
+	case string:
+		s := strings.ToLower(strings.Join(strings.Fields(x), " "))
+		// only formatted amounts: account numbers keep their digits
+		if strings.ContainsAny(s, ".,") {
+			if f, err := strconv.ParseFloat(strings.ReplaceAll(s, ",", ""), 64); err == nil {
+				return strconv.FormatFloat(f, 'f', -1, 64)
+			}
+		}
+		return s
+	default:
+		raw, _ := json.Marshal(x)
+		return string(raw)
+	}
+}
+
+// fieldPattern replaces the array indices of a path by "*".
+func fieldPattern(path string) string {
+	segs := strings.Split(path, ".")
+	for i, seg := range segs {
+		if _, err := strconv.Atoi(seg); err == nil {
+			segs[i] = "*"
+		}
+	}
+	return strings.Join(segs, ".")
+}
+
+func ratio(n, d int) float64 {
+	if d == 0 {
+		return 0
+	}
+	return float64(n) / float64(d)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+)
+
+func TestFeedbackAccuracyPerField(t *testing.T) {
+	st := store.NewMemoryFeedbackStore(10)
+	s := NewFeedbackService(st, []byte("key"), "r1")
+
+	require.NoError(t, s.Record("req-1", "acme", "income",
+		[]byte(`{"salary_slips":[{"employee_name":"RAVI  KUMAR","net_salary":4520,"account_number":"001234567890123456"}],"notes":null}`)))
+	require.NoError(t, s.Record("req-2", "acme", "income",
+		[]byte(`{"salary_slips":[{"employee_name":"Asha Rao","net_salary":52000}]}`)))
+
+	ex, err := st.GetExtraction("req-1")
+	require.NoError(t, err)
+	assert.NotContains(t, ex.Fields["salary_slips.0.employee_name"], "RAVI", "values are stored hashed")
+	assert.NotContains(t, ex.Fields, "notes")
+
+	res, err := s.Submit("acme", dto.FeedbackRequest{RequestID: "req-1", Fields: map[string]interface{}{
+		"salary_slips.0.employee_name":  "Ravi Kumar",
+		"salary_slips.0.net_salary":     "45,200.00",
+		"salary_slips.0.account_number": "001234567890123456",
+		"salary_slips.0.ifsc":           "HDFC0001234",
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, 4, res.Compared)
+	assert.Equal(t, 2, res.Correct)
+	assert.Equal(t, dto.FieldFeedback{Field: "salary_slips.0.ifsc"}, res.Fields[2])
+
+	_, err = s.Submit("acme", dto.FeedbackRequest{RequestID: "req-2", Fields: map[string]interface{}{
+		"salary_slips.0.net_salary": 52000,
+	}})
+	require.NoError(t, err)
+
+	_, err = s.Submit("other", dto.FeedbackRequest{RequestID: "req-2", Fields: map[string]interface{}{"x": 1}})
+	assert.ErrorIs(t, err, store.ErrNotFound, "another client's request")
+
+	report, err := s.Accuracy("", "income")
+	require.NoError(t, err)
+	require.Len(t, report.Groups, 1)
+	g := report.Groups[0]
+	assert.Equal(t, 2, g.Requests)
+	assert.Equal(t, 5, g.Compared)
+	assert.Equal(t, 3, g.Correct)
+	require.Len(t, g.Fields, 4)
+	assert.Equal(t, dto.FieldAccuracy{Field: "salary_slips.*.net_salary", Compared: 2, Correct: 1, Accuracy: 0.5}, g.Fields[3])
+	assert.Equal(t, 1, g.Fields[2].Missed) // ifsc
+
+	report, err = s.Accuracy("r2", "")
+	require.NoError(t, err)
+	assert.Empty(t, report.Groups)
+}
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+// ErrInvalidSchema is returned for a field schema that cannot be used.
+var ErrInvalidSchema = errors.New("invalid field schema")
+
+// FieldsService reads caller-described fields (see dto.FieldSchema) from
+// documents of any type, by label proximity on the OCR output.
+type FieldsService struct {
+	pipelines *pipeline.Orchestrator
+}
+
+func NewFieldsService(pipelines *pipeline.Orchestrator) (*FieldsService, error) {
+	s := &FieldsService{}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{}, "document")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// documentText is the OCR reading of a document; it is what gets cached, so
+// the same document can be asked for other fields without OCRing it again.
+type documentText struct {
+	Text    string              `json:"text"`
+	Words   []dto.OCRWord       `json:"words"`
+	Quality dto.DocumentQuality `json:"quality"`
+}
+
+// ExtractFields reads schema's fields from a PDF or image.
+func (s *FieldsService) ExtractFields(ctx context.Context, data []byte, filename, password string, schema dto.FieldSchema) (*dto.ExtractedFields, error) {
+	extractor, err := utils.NewFieldExtractor(schema)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSchema, err)
+	}
+
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "document", Filename: filename, Password: password, Inputs: [][]byte{data}}
+	read, err := pipeline.Cached(s.pipelines, doc, func() (*documentText, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		return &documentText{Text: doc.Text, Words: doc.Words, Quality: doc.Quality}, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fields, missing := extractor.Extract(read.Text, read.Words)
+	result := &dto.ExtractedFields{Fields: fields, Missing: missing, Complete: true, Quality: read.Quality}
+	for _, f := range schema.Fields {
+		if f.Required && slices.Contains(missing, f.Name) {
+			result.Complete = false
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// GSTService analyzes GST registration certificates and GSTR-3B returns on
+// their own. The same documents uploaded to income verification as doc_type
+// "gst_return" go through the identical pipeline and join the cross-check.
+type GSTService struct {
+	income *IncomeService
+}
+
+func NewGSTService(income *IncomeService) *GSTService {
+	return &GSTService{income: income}
+}
+
+func (s *GSTService) Analyze(ctx context.Context, data []byte, filename, password, tenantID string) (*dto.GSTData, error) {
+	meta := dto.DocumentMeta{Filename: filename, DocType: dto.DocTypeGSTReturn, Password: password}
+	result, err := s.income.ProcessDocument(ctx, data, meta, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	gst, ok := result.(dto.GSTData)
+	if !ok {
+		return nil, fmt.Errorf("gst pipeline produced no result")
+	}
+	return &gst, nil
+}
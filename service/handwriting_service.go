@@ -0,0 +1,84 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"log/slog"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/handwriting"
+)
+
+// HandwritingService reads handwritten regions (cheque amounts, signed dates)
+// that the printed-text engines cannot. It only runs for document types that
+// declare handwritten regions.
+type HandwritingService struct {
+	client *client.HandwritingClient
+}
+
+func NewHandwritingService(c *client.HandwritingClient) *HandwritingService {
+	return &HandwritingService{client: c}
+}
+
+// Supports reports whether docType has handwritten regions.
+func (s *HandwritingService) Supports(docType string) bool {
+	return len(handwriting.Regions(docType)) > 0
+}
+
+// ExtractFields crops and recognizes every handwritten region of docType.
+func (s *HandwritingService) ExtractFields(ctx context.Context, imageBytes []byte, docType string) (*dto.HandwritingResponse, error) {
+	regions := handwriting.Regions(docType)
+	if len(regions) == 0 {
+		return nil, fmt.Errorf("document type %q has no handwritten regions", docType)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(imageBytes))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode image: %w", err)
+	}
+
+	resp := &dto.HandwritingResponse{DocType: strings.ToLower(docType), Fields: []dto.HandwrittenField{}}
+	for _, r := range regions {
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, handwriting.Crop(img, r)); err != nil {
+			return nil, err
+		}
+
+		text, conf, err := s.client.Recognize(ctx, buf.Bytes())
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Handwriting recognition failed", "field", r.Field, "error", err)
+			resp.Fields = append(resp.Fields, dto.HandwrittenField{Field: r.Field})
+			continue
+		}
+
+		resp.Fields = append(resp.Fields, normalizeHandwritten(r, text, conf))
+	}
+	return resp, nil
+}
+
+func normalizeHandwritten(r handwriting.Region, text string, conf float64) dto.HandwrittenField {
+	f := dto.HandwrittenField{Field: r.Field, RawText: text, Confidence: conf}
+	switch r.Kind {
+	case handwriting.KindAmount:
+		if v, ok := handwriting.NormalizeAmount(text); ok {
+			f.Value, f.Valid = v, true
+		}
+	case handwriting.KindDate:
+		if d, ok := handwriting.NormalizeDate(text); ok {
+			f.Value, f.Valid = d.Format("2006-01-02"), true
+		}
+	default:
+		if t := strings.TrimSpace(text); t != "" {
+			f.Value, f.Valid = t, true
+		}
+	}
+	return f
+}
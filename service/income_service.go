@@ -1,267 +1,814 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"image"
 	"image/png"
 	"io"
-	"log"
+	"log/slog"
+	"math"
 	"mime/multipart"
 	"os"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/config"
 	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/store"
 	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/accountaggregator"
+	"github.com/Aashish23092/ocr-income-verification/utils/fieldtemplate"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/Aashish23092/ocr-income-verification/utils/incomeanalysis"
+	"github.com/Aashish23092/ocr-income-verification/utils/integrity"
+	"github.com/Aashish23092/ocr-income-verification/utils/rules"
+	"github.com/Aashish23092/ocr-income-verification/utils/scoring"
 )
 
+// gstCreditRatio is the share of declared GSTR-3B turnover that must show up as
+// bank credits in the same month. Tax collected and timing differences keep it below 1.
+const gstCreditRatio = 0.5
+
+// tdsPayTolerance is how far a salary slip's net pay may exceed the salary the
+// employer reported in Form 26AS for the month (rounding, reimbursements paid
+// outside payroll) before the slip is flagged.
+const tdsPayTolerance = 0.05
+
 type IncomeService struct {
-	tesseractClient *client.TesseractClient
-	pdfProcessor    PDFProcessor
-	paddleClient    *client.PaddleClient
+	tesseractClient    client.TesseractEngine
+	pdfProcessor       PDFProcessor
+	paddleClient       client.PaddleEngine
+	templates          *fieldtemplate.Registry
+	layouts            *LayoutTemplateService // nil = no registered layout templates
+	rules              *rules.Engine
+	store              store.VerificationStore
+	scoreWeights       *scoring.Config
+	pipelines          *pipeline.Orchestrator
+	parsers            *ParserRegistry // nil = DefaultParsers
+	webhooks           *client.WebhookClient
+	ifscLookup         *client.IFSCClient // nil = bank code table only
+	maxDocumentAgeDays int
+	// Pages of a text bank statement PDF to re-read with OCR; 0 disables the check
+	textLayerPages int
+	// Documents of one request processed at once
+	requestConcurrency int
+
+	// Salary credit matching: amount tolerance in rupees or percent of the net
+	// pay (the larger applies), and days around the pay month
+	salaryTolerance    float64
+	salaryTolerancePct float64
+	salaryWindowDays   int
+
+	// Company registry for the employer step; nil = employers not checked
+	companies *client.CompanyRegistryClient
 }
 
 func NewIncomeService(
-	tesseractClient *client.TesseractClient,
+	tesseractClient client.TesseractEngine,
 	pdfProcessor PDFProcessor,
-	paddleClient *client.PaddleClient,
-) *IncomeService {
-	return &IncomeService{
-		tesseractClient: tesseractClient,
-		pdfProcessor:    pdfProcessor,
-		paddleClient:    paddleClient,
+	paddleClient client.PaddleEngine,
+	templates *fieldtemplate.Registry,
+	layouts *LayoutTemplateService,
+	decisionRules *rules.Engine,
+	verificationStore store.VerificationStore,
+	scoreWeights *scoring.Config,
+	pipelines *pipeline.Orchestrator,
+	webhooks *client.WebhookClient,
+	ifscLookup *client.IFSCClient,
+	companies *client.CompanyRegistryClient,
+	cfg *config.Config,
+) (*IncomeService, error) {
+	s := &IncomeService{
+		tesseractClient:    tesseractClient,
+		pdfProcessor:       pdfProcessor,
+		paddleClient:       paddleClient,
+		templates:          templates,
+		layouts:            layouts,
+		rules:              decisionRules,
+		store:              verificationStore,
+		scoreWeights:       scoreWeights,
+		parsers:            DefaultParsers,
+		webhooks:           webhooks,
+		ifscLookup:         ifscLookup,
+		companies:          companies,
+		maxDocumentAgeDays: cfg.MaxDocumentAgeDays,
+		textLayerPages:     cfg.TextLayerCheckPages,
+		requestConcurrency: cfg.OCRRequestConcurrency,
+		salaryTolerance:    cfg.SalaryMatchTolerance,
+		salaryTolerancePct: cfg.SalaryMatchTolerancePct,
+		salaryWindowDays:   cfg.SalaryMatchWindowDays,
+	}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"parse":       noArg(s.parseStep),
+		"spreadsheet": noArg(s.spreadsheetStep),
+		"aa":          noArg(s.accountAggregatorStep),
+		"validate":    noArg(s.validateStep),
+		"textlayer":   noArg(s.textLayerStep),
+		"integrity":   noArg(s.integrityStep),
+		"employer":    noArg(s.employerStep),
+	}, s.parsers.Types()...)
+	if err != nil {
+		return nil, err
 	}
+	return s, nil
 }
 
 // VerifyIncome processes salary slips and bank statement, performs OCR and cross-verification
-func (s *IncomeService) VerifyIncome(req *dto.IncomeVerificationRequest) (*dto.IncomeVerificationResponse, error) {
+func (s *IncomeService) VerifyIncome(ctx context.Context, req *dto.IncomeVerificationRequest) (*dto.IncomeVerificationResponse, error) {
 	// Parse metadata
 	var metadata dto.UploadMetadata
 	if err := json.Unmarshal([]byte(req.Metadata), &metadata); err != nil {
 		return nil, fmt.Errorf("invalid metadata JSON: %w", err)
 	}
+	if req.TenantID != "" {
+		metadata.TenantID = req.TenantID
+	}
+	metadata.Client = req.Client
 
-	// Map filenames to files for easy access
-	fileMap := make(map[string]*multipart.FileHeader)
+	// Read uploads into memory keyed by filename
+	files := make(map[string][]byte, len(req.Files))
 	for _, file := range req.Files {
-		fileMap[file.Filename] = file
+		f, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("failed to open file %s: %w", file.Filename, err)
+		}
+		fileBytes, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file %s: %w", file.Filename, err)
+		}
+		files[file.Filename] = fileBytes
 	}
 
-	var salarySlips []dto.SalarySlipData
-	var bankStatements []dto.BankStatementData
-	var mu sync.Mutex
+	return s.VerifyDocuments(ctx, metadata, files)
+}
+
+// VerifyDocuments runs OCR, parsing and cross-verification over documents already
+// in memory, keyed by filename. It backs both the HTTP endpoint and folder ingestion.
+// When metadata carries a callback_url the outcome is also delivered as a webhook.
+func (s *IncomeService) VerifyDocuments(ctx context.Context, metadata dto.UploadMetadata, files map[string][]byte) (*dto.IncomeVerificationResponse, error) {
+	if metadata.CallbackURL != "" {
+		if err := client.ValidateCallbackURL(metadata.CallbackURL); err != nil {
+			return nil, err
+		}
+	}
+
+	response, err := s.verifyDocuments(ctx, metadata, files)
+	if !SideEffects(ctx) {
+		return response, err
+	}
+	s.webhooks.Notify(metadata.CallbackURL, dto.NewWebhookEvent("income", response, err))
+	return response, err
+}
+
+func (s *IncomeService) verifyDocuments(ctx context.Context, metadata dto.UploadMetadata, files map[string][]byte) (*dto.IncomeVerificationResponse, error) {
 	var wg sync.WaitGroup
-	errors := make([]error, 0)
+	// A request with many files must not take every OCR slot of the service
+	slots := pipeline.NewLimiter(s.requestConcurrency)
+
+	// Each metadata entry succeeds or fails on its own; failures are reported
+	// per document and the others still make up the result. Results are kept
+	// by entry so they come out in metadata order.
+	statuses := make([]dto.DocumentStatus, len(metadata.Documents))
+	results := make([]interface{}, len(metadata.Documents))
+	fingerprints := make([]*uploadFingerprint, len(metadata.Documents))
+	docTypes := make([]dto.DocumentType, len(metadata.Documents))
+	for i, docMeta := range metadata.Documents {
+		statuses[i] = dto.DocumentStatus{Filename: docMeta.Filename, DocType: docMeta.DocType, Status: dto.DocumentSucceeded}
+		docTypes[i] = docMeta.DocType
+		if docMeta.PasswordHints == nil {
+			docMeta.PasswordHints = metadata.PasswordHints
+		}
+		fail := func(err error) {
+			statuses[i].Status = dto.DocumentFailed
+			statuses[i].Error = err.Error()
+		}
 
-	// Process each document defined in metadata
-	for _, docMeta := range metadata.Documents {
-		fileHeader, ok := fileMap[docMeta.Filename]
-		if !ok {
-			log.Printf("Warning: File %s mentioned in metadata not found in upload", docMeta.Filename)
+		names := docMeta.Pages
+		if len(names) == 0 {
+			names = []string{docMeta.Filename}
+		}
+
+		pages := make([][]byte, 0, len(names))
+		for _, name := range names {
+			data, ok := files[name]
+			if !ok {
+				slog.WarnContext(ctx, "File mentioned in metadata not found in upload", "file", name)
+				fail(fmt.Errorf("file %s not found in upload", name))
+				break
+			}
+			pages = append(pages, data)
+		}
+		if len(pages) != len(names) {
 			continue
 		}
 
+		// The same file, or a re-shot photo of it, is read once
+		fingerprints[i] = newUploadFingerprint(pages)
+		statuses[i].ContentHash = fingerprints[i].content
+		if j, reason := duplicateUpload(fingerprints, docTypes, i); j >= 0 {
+			markDuplicate(results, statuses, i, j, reason)
+			continue
+		}
+
+		release, err := slots.Acquire(ctx)
+		if err != nil {
+			break // cancelled: reported below
+		}
 		wg.Add(1)
-		go func(meta dto.DocumentMeta, file *multipart.FileHeader) {
+		go func(i int, meta dto.DocumentMeta, names []string, pages [][]byte) {
 			defer wg.Done()
+			defer release()
 
-			// Open file to read bytes (needed for PDF processing)
-			f, err := file.Open()
+			result, err := s.processUpload(ctx, meta, metadata.TenantID, names, pages)
 			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Errorf("failed to open file %s: %w", meta.Filename, err))
-				mu.Unlock()
+				slog.WarnContext(ctx, "Document failed", "file", meta.Filename, "doc_type", meta.DocType, "error", err)
+				fail(err)
 				return
 			}
-			defer f.Close()
+			results[i] = result
+		}(i, docMeta, names, pages)
+	}
 
-			fileBytes, err := io.ReadAll(f)
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Errorf("failed to read file %s: %w", meta.Filename, err))
-				mu.Unlock()
-				return
-			}
+	wg.Wait()
 
-			// Process document
-			result, err := s.ProcessDocument(context.Background(), file, fileBytes, meta)
-			if err != nil {
-				mu.Lock()
-				errors = append(errors, fmt.Errorf("failed to process file %s: %w", meta.Filename, err))
-				mu.Unlock()
-				return
-			}
+	// A cancelled request is not a document failure
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
 
-			mu.Lock()
-			switch v := result.(type) {
-			case dto.SalarySlipData:
-				salarySlips = append(salarySlips, v)
-			case dto.BankStatementData:
-				bankStatements = append(bankStatements, v)
-			}
-			mu.Unlock()
-		}(docMeta, fileHeader)
+	duplicateNotes := dedupeResults(results, statuses)
+	if metadata.ApplicantID != "" && s.store != nil && SideEffects(ctx) {
+		s.markResubmissions(metadata, statuses)
 	}
 
-	wg.Wait()
+	var salarySlips []dto.SalarySlipData
+	var bankStatements []dto.BankStatementData
+	var gstReturns []dto.GSTData
+	var tdsForms []dto.Form26ASData
+	var rents []dto.RentData
+	var passbooks []dto.EPFPassbookData
+	for _, result := range results {
+		switch v := result.(type) {
+		case dto.SalarySlipData:
+			salarySlips = append(salarySlips, v)
+		case dto.BankStatementData:
+			bankStatements = append(bankStatements, v)
+		case dto.GSTData:
+			gstReturns = append(gstReturns, v)
+		case dto.Form26ASData:
+			tdsForms = append(tdsForms, v)
+		case dto.RentData:
+			rents = append(rents, v)
+		case dto.EPFPassbookData:
+			passbooks = append(passbooks, v)
+		}
+	}
 
-	if len(errors) > 0 {
-		return nil, errors[0]
+	// Duplicates are not failures: what they repeat was read
+	succeeded := 0
+	for _, st := range statuses {
+		if st.Status != dto.DocumentFailed {
+			succeeded++
+		}
+	}
+	status := dto.VerificationComplete
+	switch {
+	case len(statuses) > 0 && succeeded == 0:
+		return &dto.IncomeVerificationResponse{
+			Status:         dto.VerificationFailed,
+			Documents:      statuses,
+			SalarySlips:    []dto.SalarySlipData{},
+			BankStatements: []dto.BankStatementData{},
+			ProcessedAt:    time.Now().Format(time.RFC3339),
+		}, nil
+	case succeeded < len(statuses):
+		status = dto.VerificationPartial
 	}
 
 	// Perform cross-verification
 	crossCheckResult := s.CrossCheck(salarySlips, bankStatements)
+	if len(gstReturns) > 0 {
+		s.crossCheckGST(&crossCheckResult, gstReturns, bankStatements)
+	}
+	if len(tdsForms) > 0 && len(salarySlips) > 0 {
+		s.crossCheckTDS(&crossCheckResult, salarySlips, tdsForms)
+	}
+	if len(rents) > 0 {
+		crossCheckRent(&crossCheckResult, rents, salarySlips, bankStatements)
+	}
+	if len(passbooks) > 0 && len(salarySlips) > 0 {
+		crossCheckEPF(&crossCheckResult, salarySlips, passbooks)
+	}
+	crossCheckResult.Notes = append(crossCheckResult.Notes, duplicateNotes...)
 
 	// Build response
 	response := &dto.IncomeVerificationResponse{
+		Status:          status,
+		Documents:       statuses,
 		SalarySlips:     salarySlips,
 		BankStatements:  bankStatements,
+		GSTReturns:      gstReturns,
+		Form26AS:        tdsForms,
+		Rent:            rents,
+		EPFPassbooks:    passbooks,
+		MonthlyRent:     monthlyRent(rents),
 		CrossCheck:      crossCheckResult,
 		MinQualityScore: 60.0, // Default threshold
 		ProcessedAt:     time.Now().Format(time.RFC3339),
 	}
+	if len(bankStatements) > 0 {
+		response.MonthlyIncomeSummary = incomeanalysis.Summarize(bankStatements)
+		var income float64
+		if response.MonthlyIncomeSummary != nil {
+			income = response.MonthlyIncomeSummary.AverageMonthlyIncome
+		}
+		response.DebtObligations = incomeanalysis.Obligations(bankStatements, income)
+	}
+	response.TamperRisk = tamperRisk(salarySlips, bankStatements)
+
+	// Tenant decision rules
+	if s.rules.HasRules(metadata.TenantID) {
+		decision, err := s.rules.Evaluate(metadata.TenantID, response)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate decision rules: %w", err)
+		}
+		response.Decision = decision
+	}
+
+	// Store so reviewers can correct fields later
+	if s.store != nil && SideEffects(ctx) {
+		response.VerificationID = store.NewID()
+		now := time.Now().UTC()
+		record := &dto.VerificationRecord{
+			ID:          response.VerificationID,
+			TenantID:    metadata.TenantID,
+			Client:      metadata.Client,
+			ApplicantID: metadata.ApplicantID,
+			Extracted:   *response,
+			Current:     *response,
+			Overrides:   []dto.FieldOverride{},
+			CreatedAt:   now,
+			UpdatedAt:   now,
+		}
+		if err := s.store.Save(record); err != nil {
+			return nil, fmt.Errorf("failed to store verification: %w", err)
+		}
+	}
 
 	return response, nil
 }
 
-func (s *IncomeService) ProcessDocument(ctx context.Context, fileHeader *multipart.FileHeader, data []byte, meta dto.DocumentMeta) (interface{}, error) {
-	var text string
+// processUpload dispatches one metadata entry to single-document or page-bundle processing.
+func (s *IncomeService) processUpload(ctx context.Context, meta dto.DocumentMeta, tenantID string, names []string, pages [][]byte) (interface{}, error) {
+	var result interface{}
 	var err error
-	var quality dto.DocumentQuality
+	if len(meta.Pages) > 0 {
+		result, err = s.ProcessDocumentBundle(ctx, names, pages, meta, tenantID)
+	} else {
+		result, err = s.ProcessDocument(ctx, pages[0], meta, tenantID)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to process file %s: %w", meta.Filename, err)
+	}
+	return result, nil
+}
 
-	// Detect type based on extension
-	isPDF := strings.HasSuffix(strings.ToLower(meta.Filename), ".pdf")
+// ProcessDocument runs one uploaded file through the pipeline configured for its document type.
+func (s *IncomeService) ProcessDocument(ctx context.Context, data []byte, meta dto.DocumentMeta, tenantID string) (interface{}, error) {
+	return s.runPipeline(&pipeline.Doc{
+		Ctx:      ctx,
+		TenantID: tenantID,
+		DocType:  string(meta.DocType),
+		Filename: meta.Filename,
+		Password: meta.Password,
+		Lang:     meta.Lang,
+		Inputs:   [][]byte{data},
+
+		PasswordHints: meta.PasswordHints,
+	})
+}
 
-	if isPDF {
-		// Try text extraction first
-		text, err = s.pdfProcessor.ExtractText(data, meta.Password)
+// ProcessDocumentBundle OCRs the ordered page images of one logical document and
+// parses them as a single unit, so transactions can continue across page boundaries.
+func (s *IncomeService) ProcessDocumentBundle(ctx context.Context, names []string, pages [][]byte, meta dto.DocumentMeta, tenantID string) (interface{}, error) {
+	for _, name := range names {
+		if strings.HasSuffix(strings.ToLower(name), ".pdf") {
+			return nil, fmt.Errorf("bundle page %s must be an image", name)
+		}
+	}
+
+	return s.runPipeline(&pipeline.Doc{
+		Ctx:      ctx,
+		TenantID: tenantID,
+		DocType:  string(meta.DocType),
+		Filename: meta.Filename,
+		Lang:     meta.Lang,
+		Inputs:   pages,
+	})
+}
+
+// runPipeline runs doc through its pipeline, or returns the cached result of an
+// identical earlier upload.
+func (s *IncomeService) runPipeline(doc *pipeline.Doc) (interface{}, error) {
+	run := func() (interface{}, error) { return s.runUncached(doc) }
+	if p, ok := s.parserRegistry().lookup(doc.DocType); ok {
+		return p.cached(s.pipelines, doc, run)
+	}
+	return run()
+}
+
+func (s *IncomeService) parserRegistry() *ParserRegistry {
+	if s.parsers == nil {
+		return DefaultParsers
+	}
+	return s.parsers
+}
+
+// cachedAs caches the result of run, which is known to be a T.
+func cachedAs[T any](o *pipeline.Orchestrator, doc *pipeline.Doc, run func() (interface{}, error)) (interface{}, error) {
+	return pipeline.Cached(o, doc, func() (T, error) {
+		var zero T
+		result, err := run()
 		if err != nil {
-			log.Printf("PDF text extraction failed for %s: %v", meta.Filename, err)
-			quality.Issues = append(quality.Issues, "pdf_text_extraction_failed")
-		}
-
-		// If text is empty or too short, try image extraction (scanned PDF)
-		if len(strings.TrimSpace(text)) < 20 {
-			log.Printf("PDF %s seems to be scanned or has minimal text, attempting image-based OCR", meta.Filename)
-
-			images, imgErr := s.pdfProcessor.ExtractImages(data, meta.Password)
-			if imgErr != nil || len(images) == 0 {
-				log.Printf("Failed to extract images from PDF %s: %v", meta.Filename, imgErr)
-				quality.Issues = append(quality.Issues, "pdf_image_extraction_failed")
-			} else {
-				// OCR each image and aggregate results
-				var combinedText strings.Builder
-				var totalConfidence float64
-				var imageCount int
-
-				for _, img := range images {
-					tempImgFile, err := saveImageToTempFile(img)
-					if err != nil {
-						log.Printf("Failed to save temporary image for OCR: %v", err)
-						continue
-					}
-
-					// Paddle first
-					pageText, ocrErr := s.paddleClient.ExtractTextFromFile(tempImgFile)
-					var pageConf float64 = 75.0
-
-					// If Paddle fails, fallback to Tesseract
-					if ocrErr != nil || len(strings.TrimSpace(pageText)) < 10 {
-						pageText, pageConf, ocrErr = s.tesseractClient.ExtractTextAndQuality(tempImgFile)
-					}
-					if ocrErr != nil {
-						log.Printf("OCR failed for a page in %s: %v", meta.Filename, ocrErr)
-						os.Remove(tempImgFile) // Clean up on error
-						continue
-					}
-
-					combinedText.WriteString(pageText)
-					combinedText.WriteString("\n") // Page break
-					totalConfidence += pageConf
-					imageCount++
-
-					os.Remove(tempImgFile) // Clean up immediately
-				}
+			return zero, err
+		}
+		typed, ok := result.(T)
+		if !ok {
+			return zero, fmt.Errorf("unexpected %s result %T", doc.DocType, result)
+		}
+		return typed, nil
+	})
+}
 
-				if imageCount > 0 {
-					text = combinedText.String()
-					quality.OcrConfidence = totalConfidence / float64(imageCount)
-					quality.ResolutionScore = 80.0 // Placeholder
-					quality.FinalScore = (quality.OcrConfidence + quality.ResolutionScore) / 2
-					if quality.FinalScore < 60 {
-						quality.Issues = append(quality.Issues, "low_quality_document")
-					}
-				} else {
-					quality.Issues = append(quality.Issues, "scanned_pdf_ocr_failed")
-				}
-			}
-		} else {
-			// Text-based PDF
-			quality.OcrConfidence = 100.0
-			quality.ResolutionScore = 100.0 // Vector PDF
-			quality.FinalScore = 100.0
+func (s *IncomeService) runUncached(doc *pipeline.Doc) (interface{}, error) {
+	p, ok := s.parserRegistry().lookup(doc.DocType)
+	if !ok {
+		return nil, fmt.Errorf("unknown document type: %s", doc.DocType)
+	}
+	p.prepare(doc)
+
+	if err := s.pipelines.Run(doc); err != nil {
+		return nil, err
+	}
+
+	// Attach the final quality block
+	return p.finish(doc.Result, doc)
+}
+
+// parseStep parses the document text with the registered parser for its
+// type. Salary slips and statements are refined by a matching layout
+// template, a YAML one then a registered one read by region, and get their
+// bank from the IFSC.
+func (s *IncomeService) parseStep(doc *pipeline.Doc) error {
+	text := doc.Text
+	if len(doc.PageTexts) > 1 {
+		text = utils.MergePageTexts(doc.PageTexts)
+	}
+
+	p, ok := s.parserRegistry().lookup(doc.DocType)
+	if !ok {
+		return fmt.Errorf("unknown document type: %s", doc.DocType)
+	}
+	result, err := p.parse(doc, text)
+	if err != nil {
+		return err
+	}
+
+	// Templates and the IFSC lookup are the service's own
+	switch data := result.(type) {
+	case dto.SalarySlipData:
+		data.Template = s.applyTemplate(text, dto.DocTypeSalarySlip, &data)
+		if name := s.layouts.Apply(doc, &data); name != "" {
+			data.Template = name
 		}
-	} else {
-		// ---------------------------
-		// 1. Try PaddleOCR first
-		// ---------------------------
-		paddleText, err := s.paddleClient.ExtractText(data)
-		if err == nil && len(strings.TrimSpace(paddleText)) > 5 {
-			text = paddleText
-
-			quality.OcrConfidence = 75.0 // Default for PaddleOCR
-			quality.ResolutionScore = 80.0
-			quality.FinalScore = (quality.OcrConfidence + quality.ResolutionScore) / 2
-
-			// Parse based on doc type
-			if meta.DocType == dto.DocTypeSalarySlip {
-				parsed := utils.ParseSalarySlip(text)
-				parsed.Quality = quality
-				return parsed, nil
-			} else if meta.DocType == dto.DocTypeBankStatement {
-				parsed := utils.ParseBankStatement(text)
-				parsed.Quality = quality
-				return parsed, nil
-			}
+		s.resolveIFSC(doc, &data.IFSC, &data.BankName, &data.BankBranch)
+		result = data
+	case dto.BankStatementData:
+		data.Template = s.applyTemplate(text, dto.DocTypeBankStatement, &data)
+		if name := s.layouts.Apply(doc, &data); name != "" {
+			data.Template = name
 		}
+		data.MonthlyBalances = incomeanalysis.Balances(data)
+		s.resolveIFSC(doc, &data.IFSC, &data.BankName, &data.BankBranch)
+		result = data
+	}
+	doc.Result = result
+	return nil
+}
 
-		// Image file
-		var conf float64
-		text, conf, err = s.tesseractClient.ExtractTextAndQualityFromFile(fileHeader)
-		if err != nil {
-			return nil, fmt.Errorf("image OCR failed: %w", err)
+// spreadsheetStep reads a bank statement downloaded as CSV or XLSX straight
+// from its columns, with full confidence, and ends the pipeline: there is
+// nothing to render or OCR. Other uploads go on to OCR.
+func (s *IncomeService) spreadsheetStep(doc *pipeline.Doc) error {
+	format := statementFormat(doc)
+	if !slices.Contains(filetype.Spreadsheets, format) {
+		return nil
+	}
+
+	rows, err := utils.StatementFileRows(doc.Inputs[0], format)
+	if err != nil {
+		return fmt.Errorf("failed to read %s statement: %w", format, err)
+	}
+	stmt, err := utils.ParseStatementRows(rows)
+	if err != nil {
+		return fmt.Errorf("failed to read %s statement: %w", format, err)
+	}
+	doc.Text = utils.StatementFileText(rows)
+	stmt.Source = string(format)
+	return s.completeStatement(doc, stmt, true)
+}
+
+// accountAggregatorStep reads a bank statement from the JSON FI data of an
+// Account Aggregator in place of an uploaded statement, and ends the
+// pipeline. The data comes from the bank, so the tamper heuristics are not
+// run on it. One upload is one account: FI data holding several deposit
+// accounts is to be sent as one file per account.
+func (s *IncomeService) accountAggregatorStep(doc *pipeline.Doc) error {
+	if statementFormat(doc) != filetype.JSON {
+		return nil
+	}
+
+	stmts, err := accountaggregator.Statements(doc.Inputs[0])
+	if err != nil {
+		return err
+	}
+	if len(stmts) > 1 {
+		accounts := make([]string, len(stmts))
+		for i, stmt := range stmts {
+			accounts[i] = stmt.AccountNumber
 		}
+		return fmt.Errorf("Account Aggregator data holds %d deposit accounts (%s); send each account as its own file", len(stmts), strings.Join(accounts, ", "))
+	}
+	doc.Text = string(doc.Inputs[0])
+	utils.EnrichUPI(stmts[0].Transactions)
+	return s.completeStatement(doc, stmts[0], false)
+}
 
-		quality.OcrConfidence = conf
-		quality.ResolutionScore = 80.0 // Placeholder, need image dimensions
-		quality.FinalScore = (quality.OcrConfidence + quality.ResolutionScore) / 2
+// statementFormat is the format of a bank statement upload of one file, ""
+// for other documents.
+func statementFormat(doc *pipeline.Doc) filetype.Format {
+	if doc.DocType != string(dto.DocTypeBankStatement) || len(doc.Inputs) != 1 {
+		return ""
+	}
+	data := doc.Inputs[0]
+	return filetype.SniffFile(bytes.NewReader(data), int64(len(data)), doc.Filename, doc.MimeType)
+}
 
-		if quality.FinalScore < 60 {
-			quality.Issues = append(quality.Issues, "low_quality_document")
+// completeStatement finishes a statement read without OCR, with full
+// confidence: what the parse step adds to a statement, then the checks that
+// apply to a document without pages (integrity when checkIntegrity, document
+// age). It ends the pipeline.
+func (s *IncomeService) completeStatement(doc *pipeline.Doc, stmt dto.BankStatementData, checkIntegrity bool) error {
+	stmt.PIIFound = utils.SummarizePII(utils.ScanPII(doc.Text))
+	stmt.MonthlyBalances = incomeanalysis.Balances(stmt)
+	s.resolveIFSC(doc, &stmt.IFSC, &stmt.BankName, &stmt.BankBranch)
+	doc.Quality.OcrConfidence, doc.Quality.ResolutionScore, doc.Quality.FinalScore = 100, 100, 100
+	doc.Result = stmt
+
+	if checkIntegrity {
+		if err := s.integrityStep(doc); err != nil {
+			return err
 		}
 	}
+	if err := s.validateStep(doc); err != nil {
+		return err
+	}
+	doc.Done = true
+	return nil
+}
+
+// validateStep checks how old the document is against its scan date.
+func (s *IncomeService) validateStep(doc *pipeline.Doc) error {
+	switch v := doc.Result.(type) {
+	case dto.SalarySlipData:
+		s.applyDocumentAge(&doc.Quality, utils.SlipContentDate(v), doc.ScanDate)
+	case dto.BankStatementData:
+		s.applyDocumentAge(&doc.Quality, utils.StatementContentDate(v), doc.ScanDate)
+	}
+	return nil
+}
+
+// textLayerStep cross-validates text bank statement PDFs; an edited text layer
+// over an original scan reads differently from the rendered page.
+func (s *IncomeService) textLayerStep(doc *pipeline.Doc) error {
+	stmt, ok := doc.Result.(dto.BankStatementData)
+	if !ok || !doc.TextLayer || s.textLayerPages <= 0 || signatureVerified(doc) {
+		return nil
+	}
+
+	check, err := s.crossValidateTextLayer(doc.Ctx, doc.Inputs[0], doc.Password)
+	if err != nil {
+		slog.WarnContext(doc.Ctx, "Text layer check failed", "file", doc.Filename, "error", err)
+		doc.AddIssue("text_layer_check_failed")
+		return nil
+	}
+	if len(check.Mismatches) > 0 {
+		doc.AddIssue("text_layer_mismatch")
+	}
+	stmt.TextLayerCheck = check
+	doc.Result = stmt
+	return nil
+}
+
+// integrityStep runs the bank statement tamper heuristics. Font checks apply to
+// text PDFs only; the metadata findings come from the metadata step. A
+// statement carrying the bank's valid signature needs neither.
+func (s *IncomeService) integrityStep(doc *pipeline.Doc) error {
+	stmt, ok := doc.Result.(dto.BankStatementData)
+	if !ok || signatureVerified(doc) {
+		return nil
+	}
+
+	stmt.FraudSignals = integrity.CheckStatement(stmt)
+	if doc.TextLayer && doc.Provenance != nil {
+		stmt.FraudSignals = append(stmt.FraudSignals, integrity.CheckFonts(doc.Provenance.Metadata)...)
+	}
+	seen := map[string]bool{}
+	for _, sig := range stmt.FraudSignals {
+		if !seen[sig.Type] {
+			seen[sig.Type] = true
+			doc.AddIssue(sig.Type)
+		}
+	}
+	doc.Result = stmt
+	return nil
+}
+
+// signatureVerified reports whether doc is a PDF whose digital signature
+// covers the whole file.
+func signatureVerified(doc *pipeline.Doc) bool {
+	return doc.Provenance != nil && doc.Provenance.SignatureVerified
+}
+
+// tamperRisk is the highest metadata tamper risk among the PDF uploads.
+func tamperRisk(slips []dto.SalarySlipData, stmts []dto.BankStatementData) int {
+	risk := 0
+	for _, slip := range slips {
+		if slip.Provenance != nil {
+			risk = max(risk, slip.Provenance.TamperRisk)
+		}
+	}
+	for _, stmt := range stmts {
+		if stmt.Provenance != nil {
+			risk = max(risk, stmt.Provenance.TamperRisk)
+		}
+	}
+	return risk
+}
+
+// resolveIFSC validates the IFSC a document yielded (templates may supply one
+// the extractor did not) and fills in its bank and, when a lookup API is
+// configured, its branch.
+func (s *IncomeService) resolveIFSC(doc *pipeline.Doc, ifsc, bank, branch *string) {
+	if *ifsc == "" {
+		return
+	}
+	*ifsc = strings.ToUpper(*ifsc)
+	if !utils.ValidateIFSC(*ifsc) {
+		doc.AddIssue("invalid_ifsc")
+		*ifsc = ""
+		return
+	}
+	if *bank == "" {
+		*bank = utils.BankForIFSC(*ifsc)
+	}
+	if s.ifscLookup == nil {
+		return
+	}
+
+	details, err := s.ifscLookup.Lookup(doc.Ctx, *ifsc)
+	switch {
+	case err != nil:
+		slog.WarnContext(doc.Ctx, "IFSC lookup failed", "ifsc", *ifsc, "error", err)
+	case details == nil:
+		doc.AddIssue("unknown_ifsc")
+	default:
+		*branch = details.Branch
+		if *bank == "" {
+			*bank = details.Bank
+		}
+	}
+}
+
+// employerStep checks a salary slip's employer against the company registry,
+// by the CIN printed on the slip or else by name. Slips from companies the
+// registry does not know, or lists as not active, get a quality issue and are
+// flagged in the cross-check. A failed lookup leaves the slip unchecked.
+func (s *IncomeService) employerStep(doc *pipeline.Doc) error {
+	slip, ok := doc.Result.(dto.SalarySlipData)
+	if !ok || s.companies == nil || (slip.CIN == "" && slip.EmployerName == "") {
+		return nil
+	}
+
+	var details *client.CompanyDetails
+	var err error
+	if slip.CIN != "" {
+		details, err = s.companies.LookupCIN(doc.Ctx, slip.CIN)
+	} else {
+		details, err = s.companies.LookupName(doc.Ctx, slip.EmployerName)
+	}
+	if err != nil {
+		slog.WarnContext(doc.Ctx, "Company registry lookup failed", "employer", slip.EmployerName, "cin", slip.CIN, "error", err)
+		return nil
+	}
+
+	reg := &dto.EmployerRegistration{}
+	switch {
+	case details == nil:
+		doc.AddIssue("unknown_employer")
+	default:
+		reg.Found = true
+		reg.CIN, reg.RegisteredName = details.CIN, details.Name
+		reg.Status, reg.IncorporatedOn = details.Status, details.IncorporatedOn
+		reg.Active = details.Active()
+		if !reg.Active {
+			doc.AddIssue("inactive_employer")
+		}
+	}
+	slip.EmployerRegistration = reg
+	doc.Result = slip
+	return nil
+}
+
+// applyTemplate overlays fields from a matching layout template onto the generic
+// parser output. Returns the template name, or "" when none matched or it
+// found none of its fields.
+func (s *IncomeService) applyTemplate(text string, docType dto.DocumentType, target interface{}) string {
+	tpl := s.templates.Match(string(docType), text)
+	if tpl == nil {
+		return ""
+	}
+	n, err := tpl.Apply(text, target)
+	if err != nil {
+		slog.Warn("Template failed to apply", "template", tpl.Name, "error", err)
+		return ""
+	}
+	if n == 0 {
+		return ""
+	}
+	return tpl.Name
+}
+
+// applyDocumentAge sets DocumentAgeDays from the content date (or scan date as fallback)
+// and flags documents older than the configured window.
+func (s *IncomeService) applyDocumentAge(quality *dto.DocumentQuality, contentDate, scanDate *time.Time) {
+	docDate := utils.ResolveDocumentDate(contentDate, scanDate)
+	if docDate == nil {
+		quality.Issues = append(quality.Issues, "document_date_unknown")
+		return
+	}
 
-	// Parse based on doc type
-	if meta.DocType == dto.DocTypeSalarySlip {
-		data := utils.ParseSalarySlip(text)
-		data.Quality = quality
-		return data, nil
-	} else if meta.DocType == dto.DocTypeBankStatement {
-		data := utils.ParseBankStatement(text)
-		data.Quality = quality
-		return data, nil
+	age := utils.DocumentAgeDays(*docDate, time.Now())
+	quality.DocumentAgeDays = &age
+
+	if s.maxDocumentAgeDays > 0 && age > s.maxDocumentAgeDays {
+		quality.Issues = append(quality.Issues, "stale_document")
 	}
 
-	return nil, fmt.Errorf("unknown document type: %s", meta.DocType)
+	// A scan created before the period it describes points at a back-dated or edited file
+	if contentDate != nil && scanDate != nil && utils.ScanPrecedesContent(*contentDate, *scanDate) {
+		quality.Issues = append(quality.Issues, "scan_date_precedes_content_date")
+	}
 }
 
 func (s *IncomeService) CrossCheck(slips []dto.SalarySlipData, stmts []dto.BankStatementData) dto.CrossCheckResult {
 	result := dto.CrossCheckResult{
-		Notes: []string{},
+		Notes:        []string{},
+		FraudSignals: []dto.FraudSignal{},
+	}
+	for _, stmt := range stmts {
+		for _, sig := range append(stmt.FraudSignals, integrity.ProvenanceSignals(stmt.Provenance)...) {
+			sig.Account = stmt.AccountNumber
+			result.FraudSignals = append(result.FraudSignals, sig)
+		}
+	}
+	for _, slip := range slips {
+		for _, sig := range integrity.ProvenanceSignals(slip.Provenance) {
+			sig.Account = slip.AccountNumber
+			result.FraudSignals = append(result.FraudSignals, sig)
+		}
+		if sig := employerSignal(slip); sig != nil {
+			result.FraudSignals = append(result.FraudSignals, *sig)
+		}
+	}
+
+	result.Bounces = incomeanalysis.Bounces(stmts)
+	for _, mb := range result.Bounces {
+		result.BounceCount += mb.ChequeReturns + mb.MandateReturns
+	}
+	if result.BounceCount > 0 {
+		result.Notes = append(result.Notes, fmt.Sprintf("%d cheque/ECS return(s) across %d month(s)", result.BounceCount, len(result.Bounces)))
 	}
 
 	if len(stmts) == 0 {
@@ -271,14 +818,11 @@ func (s *IncomeService) CrossCheck(slips []dto.SalarySlipData, stmts []dto.BankS
 
 	stmt := stmts[0] // Primary statement
 
-	// Name Match
+	// Name Match: the best-scoring slip
 	for _, slip := range slips {
-		if utils.CompareNames(slip.EmployeeName, stmt.AccountHolderName) {
-			result.NameMatch = true
-			result.NameSimilarity = 1.0 // Simplified
-			break
-		}
+		result.NameSimilarity = math.Max(result.NameSimilarity, utils.NameMatchScore(slip.EmployeeName, stmt.AccountHolderName))
 	}
+	result.NameMatch = result.NameSimilarity >= utils.NameMatchThreshold
 
 	// Account Match
 	for _, slip := range slips {
@@ -290,19 +834,34 @@ func (s *IncomeService) CrossCheck(slips []dto.SalarySlipData, stmts []dto.BankS
 		}
 	}
 
-	// Salary Credit Match (Simplified)
-	// Check if any credit matches net salary within a margin
+	// Salary Credit Match: a credit of the net pay within the tolerance, around
+	// the pay month, preferably described as salary or from the employer
 	for _, slip := range slips {
 		if slip.NetSalary > 0 {
-			found := false
-			for _, tx := range stmt.Transactions {
-				if tx.IsCredit && tx.Amount == slip.NetSalary {
-					found = true
+			var match *dto.BankTransaction
+			for i, tx := range stmt.Transactions {
+				if !tx.IsCredit || !s.salaryCreditMatches(tx, slip) {
+					continue
+				}
+				if payer := personalUPIPayer(tx, slip); payer != "" {
+					result.Notes = append(result.Notes, fmt.Sprintf("Credit of %.2f on %s matching %s salary is a UPI transfer from %s, not the employer",
+						tx.Amount, tx.Date.Format("2006-01-02"), slip.PayMonth, payer))
+					continue
+				}
+				if describesSalary(tx, slip) {
+					match = &stmt.Transactions[i]
 					break
 				}
+				if match == nil {
+					match = &stmt.Transactions[i]
+				}
 			}
-			if !found {
+			switch {
+			case match == nil:
 				result.MissingSalaryCredits = append(result.MissingSalaryCredits, fmt.Sprintf("Missing credit for %s: %.2f", slip.PayMonth, slip.NetSalary))
+			case !describesSalary(*match, slip):
+				result.Notes = append(result.Notes, fmt.Sprintf("Credit of %.2f on %s matching %s salary names neither salary nor %s",
+					match.Amount, match.Date.Format("2006-01-02"), slip.PayMonth, slip.EmployerName))
 			}
 		}
 	}
@@ -310,6 +869,309 @@ func (s *IncomeService) CrossCheck(slips []dto.SalarySlipData, stmts []dto.BankS
 	return result
 }
 
+// salaryCreditMatches reports whether the amount and date of a credit fit the
+// net pay of slip: the amount within the salary tolerance, and the date within
+// the pay month widened by the salary window. Undated transactions and slips
+// without a readable pay month are matched on the amount alone.
+func (s *IncomeService) salaryCreditMatches(tx dto.BankTransaction, slip dto.SalarySlipData) bool {
+	tolerance := math.Max(s.salaryTolerance, slip.NetSalary*s.salaryTolerancePct/100)
+	if math.Abs(tx.Amount-slip.NetSalary) > tolerance {
+		return false
+	}
+	end, ok := utils.ParsePayMonth(slip.PayMonth)
+	if !ok || tx.Date.IsZero() {
+		return true
+	}
+	window := time.Duration(s.salaryWindowDays) * 24 * time.Hour
+	from := time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC).Add(-window)
+	to := end.AddDate(0, 0, 1).Add(window)
+	return !tx.Date.Before(from) && tx.Date.Before(to)
+}
+
+// describesSalary reports whether a credit's description calls it salary or
+// names the slip's employer.
+func describesSalary(tx dto.BankTransaction, slip dto.SalarySlipData) bool {
+	return incomeanalysis.IsSalary(tx.Description) ||
+		(slip.EmployerName != "" && utils.SameParty(slip.EmployerName, tx.Description))
+}
+
+// employerSignal flags a slip whose employer the company registry does not
+// know or lists as not active; nil when the employer checks out or was not
+// checked.
+func employerSignal(slip dto.SalarySlipData) *dto.FraudSignal {
+	reg := slip.EmployerRegistration
+	switch {
+	case reg == nil || reg.Active:
+		return nil
+	case !reg.Found:
+		return &dto.FraudSignal{Type: dto.FraudUnknownEmployer, Account: slip.AccountNumber,
+			Detail: fmt.Sprintf("Employer %q of the %s salary slip is not in the company registry", slip.EmployerName, slip.PayMonth)}
+	default:
+		return &dto.FraudSignal{Type: dto.FraudUnknownEmployer, Account: slip.AccountNumber,
+			Detail: fmt.Sprintf("Employer %s (CIN %s) of the %s salary slip has registry status %q", reg.RegisteredName, reg.CIN, slip.PayMonth, reg.Status)}
+	}
+}
+
+// personalUPIPayer returns the counterparty of a UPI credit that comes from a
+// person rather than the employer: the applicant moving their own money, or
+// anyone whose name shares nothing with the employer on the payslip. It
+// returns "" for non-UPI credits and when the payer cannot be told apart.
+func personalUPIPayer(tx dto.BankTransaction, slip dto.SalarySlipData) string {
+	if tx.UPI == nil || tx.UPI.Counterparty == "" {
+		return ""
+	}
+	payer := tx.UPI.Counterparty
+	if slip.EmployeeName != "" && utils.SameParty(payer, slip.EmployeeName) {
+		return payer
+	}
+	if slip.EmployerName != "" && !utils.SameParty(payer, slip.EmployerName) {
+		return payer
+	}
+	return ""
+}
+
+// crossCheckGST brings self-employed income into the cross-check: the GST legal
+// or trade name must match the account holder, and each GSTR-3B month's declared
+// turnover should be reflected in that month's bank credits.
+func (s *IncomeService) crossCheckGST(result *dto.CrossCheckResult, gstReturns []dto.GSTData, stmts []dto.BankStatementData) {
+	if len(stmts) == 0 {
+		return
+	}
+	stmt := stmts[0] // Primary statement
+
+	for _, g := range gstReturns {
+		for _, name := range []string{g.LegalName, g.TradeName} {
+			if score := utils.NameMatchScore(name, stmt.AccountHolderName); score >= utils.NameMatchThreshold {
+				result.NameMatch = true
+				result.NameSimilarity = math.Max(result.NameSimilarity, score)
+			}
+		}
+	}
+	if !result.NameMatch {
+		result.Notes = append(result.Notes, "GST legal/trade name does not match bank account holder")
+	}
+
+	for _, g := range gstReturns {
+		if g.ReturnPeriod == "" || g.Turnover <= 0 {
+			continue
+		}
+		credits := 0.0
+		covered := false
+		for _, tx := range stmt.Transactions {
+			if tx.Date.Format("2006-01") != g.ReturnPeriod {
+				continue
+			}
+			covered = true
+			if tx.IsCredit {
+				credits += tx.Amount
+			}
+		}
+		if covered && credits < g.Turnover*gstCreditRatio {
+			result.Notes = append(result.Notes, fmt.Sprintf("GST turnover for %s (%.2f) not reflected in bank credits (%.2f)", g.ReturnPeriod, g.Turnover, credits))
+		}
+	}
+}
+
+// crossCheckTDS checks the salary slips against the salary TDS (section 192)
+// of Form 26AS / AIS: the slips' employer must be a deductor, and for each
+// slip month in a financial year the forms cover, the employer must have
+// reported salary of at least the slip's net pay. A slip that fails is flagged
+// as a possible fabrication.
+func (s *IncomeService) crossCheckTDS(result *dto.CrossCheckResult, slips []dto.SalarySlipData, forms []dto.Form26ASData) {
+	check := &dto.TDSCheck{Months: []dto.TDSMonthCheck{}, Consistent: true}
+	result.TDS = check
+	flag := func(detail string) {
+		check.Consistent = false
+		result.FraudSignals = append(result.FraudSignals, dto.FraudSignal{Type: dto.FraudTDSMismatch, Detail: detail})
+	}
+
+	var employers []dto.TDSDeductor
+	covered := map[string]bool{} // financial years the forms report
+	for _, f := range forms {
+		if fy := financialYearOfAY(f.AssessmentYear); fy != "" {
+			covered[fy] = true
+		}
+		for _, d := range f.Deductors {
+			salary := false
+			for _, e := range d.Entries {
+				covered[e.FinancialYear] = true
+				salary = salary || e.Section == dto.SectionSalary
+			}
+			if salary {
+				employers = append(employers, d)
+			}
+		}
+	}
+
+	for _, slip := range slips {
+		var employer *dto.TDSDeductor
+		for i := range employers {
+			if slip.EmployerName != "" && utils.SameParty(slip.EmployerName, employers[i].Name) {
+				employer = &employers[i]
+				break
+			}
+		}
+		if employer != nil && check.TAN == "" {
+			check.Deductor, check.TAN = employer.Name, employer.TAN
+		}
+
+		t, ok := utils.ParsePayMonth(slip.PayMonth)
+		if !ok {
+			result.Notes = append(result.Notes, fmt.Sprintf("Pay month %q of salary slip not checked against TDS", slip.PayMonth))
+			continue
+		}
+		month := t.Format("2006-01")
+		if _, fy := utils.TDSQuarter(t); !covered[fy] {
+			result.Notes = append(result.Notes, fmt.Sprintf("Form 26AS does not cover the %s salary slip (FY %s)", month, fy))
+			continue
+		}
+
+		mc := dto.TDSMonthCheck{Month: month, SlipNet: slip.NetSalary, Consistent: true}
+		if employer == nil {
+			mc.Consistent = false
+			flag(fmt.Sprintf("Employer %q of the %s salary slip deducted no salary TDS in Form 26AS", slip.EmployerName, month))
+			check.Months = append(check.Months, mc)
+			continue
+		}
+		for _, e := range employer.Entries {
+			if e.Section == dto.SectionSalary && strings.HasPrefix(e.TransactionDate, month) {
+				mc.ReportedPaid += e.AmountPaid
+			}
+		}
+		switch {
+		case mc.ReportedPaid == 0:
+			mc.Consistent = false
+			flag(fmt.Sprintf("%s (TAN %s) reported no salary for %s", employer.Name, employer.TAN, month))
+		case slip.NetSalary > mc.ReportedPaid*(1+tdsPayTolerance):
+			mc.Consistent = false
+			flag(fmt.Sprintf("Net pay %.2f on the %s salary slip exceeds the %.2f salary %s (TAN %s) reported", slip.NetSalary, month, mc.ReportedPaid, employer.Name, employer.TAN))
+		}
+		check.Months = append(check.Months, mc)
+	}
+}
+
+// crossCheckEPF checks employment continuity on the salary slips against the
+// EPF passbooks: the slips' employer must be an establishment, and it must
+// have contributed for each slip month up to the passbooks' latest
+// contribution. Later months are noted, not flagged: contributions are
+// posted the month after the wages.
+func crossCheckEPF(result *dto.CrossCheckResult, slips []dto.SalarySlipData, passbooks []dto.EPFPassbookData) {
+	check := &dto.EPFCheck{Months: []dto.EPFMonthCheck{}, Consistent: true}
+	result.EPF = check
+	flag := func(detail string) {
+		check.Consistent = false
+		result.FraudSignals = append(result.FraudSignals, dto.FraudSignal{Type: dto.FraudEPFMismatch, Detail: detail})
+	}
+
+	var establishments []dto.EPFEstablishment
+	latest := "" // YYYY-MM of the latest contribution
+	for _, p := range passbooks {
+		for _, e := range p.Establishments {
+			establishments = append(establishments, e)
+			if n := len(e.Contributions); n > 0 && e.Contributions[n-1].Month > latest {
+				latest = e.Contributions[n-1].Month
+			}
+		}
+	}
+
+	var matched *dto.EPFEstablishment
+	for _, slip := range slips {
+		var employer *dto.EPFEstablishment
+		for i := range establishments {
+			if slip.EmployerName != "" && utils.SameParty(slip.EmployerName, establishments[i].Name) {
+				employer = &establishments[i]
+				break
+			}
+		}
+		if employer != nil && matched == nil {
+			matched = employer
+			check.Establishment, check.EstablishmentID = employer.Name, employer.ID
+		}
+
+		t, ok := utils.ParsePayMonth(slip.PayMonth)
+		if !ok {
+			result.Notes = append(result.Notes, fmt.Sprintf("Pay month %q of salary slip not checked against EPF", slip.PayMonth))
+			continue
+		}
+		month := t.Format("2006-01")
+		if month > latest {
+			result.Notes = append(result.Notes, fmt.Sprintf("EPF passbook has no contributions yet for the %s salary slip", month))
+			continue
+		}
+
+		mc := dto.EPFMonthCheck{Month: month}
+		if employer == nil {
+			flag(fmt.Sprintf("Employer %q of the %s salary slip is not an establishment in the EPF passbook", slip.EmployerName, month))
+			check.Months = append(check.Months, mc)
+			continue
+		}
+		mc.Contributed = slices.ContainsFunc(employer.Contributions, func(c dto.EPFContribution) bool { return c.Month == month })
+		if !mc.Contributed {
+			flag(fmt.Sprintf("%s (%s) made no EPF contribution for %s", employer.Name, employer.ID, month))
+		}
+		check.Months = append(check.Months, mc)
+	}
+
+	if matched != nil && len(matched.Contributions) > 0 {
+		first, _ := time.Parse("2006-01", matched.Contributions[0].Month)
+		last := matched.Contributions[len(matched.Contributions)-1].Month
+		for t := first; t.Format("2006-01") < last; t = t.AddDate(0, 1, 0) {
+			month := t.Format("2006-01")
+			if !slices.ContainsFunc(matched.Contributions, func(c dto.EPFContribution) bool { return c.Month == month }) {
+				check.Gaps = append(check.Gaps, month)
+			}
+		}
+	}
+}
+
+// crossCheckRent notes rent documents whose tenant is not the applicant (the
+// salary slip employee or the account holder).
+func crossCheckRent(result *dto.CrossCheckResult, rents []dto.RentData, slips []dto.SalarySlipData, stmts []dto.BankStatementData) {
+	var applicants []string
+	for _, slip := range slips {
+		applicants = append(applicants, slip.EmployeeName)
+	}
+	for _, stmt := range stmts {
+		applicants = append(applicants, stmt.AccountHolderName)
+	}
+
+	for _, r := range rents {
+		if r.TenantName == "" || len(applicants) == 0 {
+			continue
+		}
+		named := false
+		for _, a := range applicants {
+			named = named || utils.NamesMatch(r.TenantName, a)
+		}
+		if !named {
+			result.Notes = append(result.Notes, fmt.Sprintf("Rent %s tenant %q does not match the applicant", r.Kind, r.TenantName))
+		}
+	}
+}
+
+// monthlyRent is the rent of the document whose period starts last; 0 when no
+// document states a rent.
+func monthlyRent(rents []dto.RentData) float64 {
+	var rent float64
+	latest := ""
+	for _, r := range rents {
+		if r.MonthlyRent > 0 && (rent == 0 || r.PeriodFrom > latest) {
+			rent, latest = r.MonthlyRent, r.PeriodFrom
+		}
+	}
+	return rent
+}
+
+// financialYearOfAY returns the financial year ("2024-25") an assessment year
+// ("2025-26") assesses.
+func financialYearOfAY(ay string) string {
+	var start int
+	if _, err := fmt.Sscanf(ay, "%4d-", &start); err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%02d", start-1, start%100)
+}
+
 // saveImageToTempFile saves an image.Image to a temporary PNG file.
 func saveImageToTempFile(img image.Image) (string, error) {
 	tempFile, err := os.CreateTemp("", "ocr-img-*.png")
@@ -326,27 +1188,133 @@ func saveImageToTempFile(img image.Image) (string, error) {
 }
 
 // AnalyzeITR processes an ITR document and extracts structured data
-func (s *IncomeService) AnalyzeITR(fileHeader *multipart.FileHeader) (*dto.ITRResult, error) {
-	log.Printf("Starting ITR analysis for file: %s", fileHeader.Filename)
+func (s *IncomeService) AnalyzeITR(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.ITRResult, error) {
+	data, err := readFileHeader(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	return s.AnalyzeITRData(ctx, fileHeader.Filename, data)
+}
+
+// AnalyzeITRData is AnalyzeITR for a document already in memory.
+func (s *IncomeService) AnalyzeITRData(ctx context.Context, filename string, data []byte) (*dto.ITRResult, error) {
+	slog.InfoContext(ctx, "Starting ITR analysis", "file", filename)
 
+	// The e-filing utility's JSON/XML is the return itself: no OCR
+	if utils.IsStructuredITR(data) {
+		result, err := utils.ParseStructuredITR(data)
+		if err != nil {
+			return nil, err
+		}
+		result.PIIFound = utils.SummarizePII(utils.ScanPII(string(data)))
+		slog.Info("ITR analysis done", "pan", result.PAN, "assessment_year", result.AssessmentYear, "form", result.Form, "source", result.Source)
+		return &result, nil
+	}
+
+	extractedText, provenance, err := s.extractTaxDocumentText(ctx, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := utils.ParseITR(extractedText)
+	result.PIIFound = utils.SummarizePII(utils.ScanPII(extractedText))
+	result.Provenance = provenance
+	result.Source, result.Confidence = dto.ITRSourceOCR, evaluateTextQuality(extractedText)
+
+	slog.Info("ITR analysis done", "pan", result.PAN, "assessment_year", result.AssessmentYear)
+
+	return &result, nil
+}
+
+// AnalyzeForm16 processes a Form-16 TDS certificate and extracts structured data
+func (s *IncomeService) AnalyzeForm16(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.Form16Result, error) {
+	data, err := readFileHeader(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	return s.AnalyzeForm16Data(ctx, fileHeader.Filename, data)
+}
+
+// AnalyzeForm16Data is AnalyzeForm16 for a document already in memory.
+func (s *IncomeService) AnalyzeForm16Data(ctx context.Context, filename string, data []byte) (*dto.Form16Result, error) {
+	slog.InfoContext(ctx, "Starting Form-16 analysis", "file", filename)
+
+	extractedText, provenance, err := s.extractTaxDocumentText(ctx, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := utils.ParseForm16(extractedText)
+	result.PIIFound = utils.SummarizePII(utils.ScanPII(extractedText))
+	result.Provenance = provenance
+
+	slog.Info("Form-16 analysis done", "tan", result.EmployerTAN, "pan", result.EmployeePAN, "financial_year", result.FinancialYear)
+
+	return &result, nil
+}
+
+// AnalyzeForm26AS processes a Form 26AS or Annual Information Statement and
+// extracts its TDS entries and SFT transactions.
+func (s *IncomeService) AnalyzeForm26AS(ctx context.Context, fileHeader *multipart.FileHeader) (*dto.Form26ASData, error) {
+	data, err := readFileHeader(fileHeader)
+	if err != nil {
+		return nil, err
+	}
+	return s.AnalyzeForm26ASData(ctx, fileHeader.Filename, data)
+}
+
+// AnalyzeForm26ASData is AnalyzeForm26AS for a document already in memory.
+func (s *IncomeService) AnalyzeForm26ASData(ctx context.Context, filename string, data []byte) (*dto.Form26ASData, error) {
+	slog.InfoContext(ctx, "Starting Form 26AS analysis", "file", filename)
+
+	extractedText, provenance, err := s.extractTaxDocumentText(ctx, filename, data)
+	if err != nil {
+		return nil, err
+	}
+
+	result := utils.ParseForm26AS(extractedText)
+	result.PIIFound = utils.SummarizePII(utils.ScanPII(extractedText))
+	result.Provenance = provenance
+
+	slog.Info("Form 26AS analysis done", "kind", result.Kind, "pan", result.PAN, "deductors", len(result.Deductors), "sft_transactions", len(result.SFTTransactions))
+
+	return &result, nil
+}
+
+func readFileHeader(fileHeader *multipart.FileHeader) ([]byte, error) {
 	file, err := fileHeader.Open()
 	if err != nil {
 		return nil, fmt.Errorf("failed to open file: %w", err)
 	}
 	defer file.Close()
 
-	fileBytes, err := io.ReadAll(file)
+	data, err := io.ReadAll(file)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read file: %w", err)
 	}
+	return data, nil
+}
+
+// extractTaxDocumentText reads the text of an ITR / Form-16 / Form 26AS upload: embedded
+// PDF text first, PaddleOCR on the page images when that is weak, and
+// Tesseract as the last resort. PDFs also yield their provenance (ITR-Vs and
+// TRACES Form-16s are digitally signed).
+func (s *IncomeService) extractTaxDocumentText(ctx context.Context, filename string, fileBytes []byte) (string, *dto.DocumentProvenance, error) {
+	release, err := s.pipelines.Acquire(ctx)
+	if err != nil {
+		return "", nil, err
+	}
+	defer release()
 
 	var extractedText string
-	isPDF := strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".pdf")
+	var provenance *dto.DocumentProvenance
+	isPDF := strings.HasSuffix(strings.ToLower(filename), ".pdf")
 
 	// ---------------------------------------------------
 	// CASE 1 — PDF (ITR files are ALWAYS PDF)
 	// ---------------------------------------------------
 	if isPDF {
+		provenance = pdfProvenance(s.pdfProcessor, fileBytes, "")
 
 		// 1) Try embedded PDF text
 		text, err := s.pdfProcessor.ExtractText(fileBytes, "")
@@ -356,39 +1324,49 @@ func (s *IncomeService) AnalyzeITR(fileHeader *multipart.FileHeader) (*dto.ITRRe
 
 		// 2) If extracted text is weak → use Paddle on PDF images
 		if evaluateTextQuality(extractedText) < 50 {
-			log.Println("PDF text is weak → using PaddleOCR on extracted images")
-
-			images, err := s.pdfProcessor.ExtractImages(fileBytes, "")
-			if err != nil || len(images) == 0 {
-				log.Printf("Failed to extract images from PDF: %v", err)
-			} else {
-				var combined strings.Builder
-				for _, img := range images {
-
-					tmp, err := saveImageToTempFile(img)
-					if err != nil {
-						continue
-					}
-
-					paddleText, err := s.paddleClient.ExtractTextFromFile(tmp)
-					os.Remove(tmp)
-
-					if err == nil && len(strings.TrimSpace(paddleText)) > 10 {
-						combined.WriteString(paddleText)
-						combined.WriteString("\n")
-					}
+			slog.Info("PDF text is weak, using PaddleOCR on extracted images", "file", filename)
+
+			var combined strings.Builder
+			for img, err := range s.pdfProcessor.ExtractImages(ctx, fileBytes, "", RenderOptions{}) {
+				if ctx.Err() != nil {
+					return "", nil, ctx.Err()
+				}
+				if budget := pipeline.OCRBudgetFrom(ctx); budget.Spent() {
+					budget.Exhaust()
+					slog.InfoContext(ctx, "OCR budget spent, leaving the rest of the document unread", "file", filename)
+					break
 				}
+				if errors.Is(err, ErrPDFLimit) {
+					return "", nil, err
+				}
+				if err != nil {
+					slog.Warn("Failed to extract image from PDF", "file", filename, "error", err)
+					continue
+				}
+
+				tmp, err := saveImageToTempFile(img)
+				if err != nil {
+					continue
+				}
+
+				paddleText, err := s.paddleClient.ExtractTextFromFile(ctx, tmp)
+				os.Remove(tmp)
 
-				// Use PaddleOCR result if it's meaningful
-				if len(strings.TrimSpace(combined.String())) > 20 {
-					extractedText = combined.String()
+				if err == nil && len(strings.TrimSpace(paddleText)) > 10 {
+					combined.WriteString(paddleText)
+					combined.WriteString("\n")
 				}
 			}
+
+			// Use PaddleOCR result if it's meaningful
+			if len(strings.TrimSpace(combined.String())) > 20 {
+				extractedText = combined.String()
+			}
 		}
 
-		// 3) If still empty → final fallback: Tesseract
-		if len(strings.TrimSpace(extractedText)) == 0 {
-			text, _, err := s.tesseractClient.ExtractTextAndQualityFromFile(fileHeader)
+		// 3) If still empty → final fallback: Tesseract, unless out of time
+		if len(strings.TrimSpace(extractedText)) == 0 && !pipeline.OCRBudgetFrom(ctx).Exhausted() {
+			text, _, err := s.tesseractClient.ExtractTextAndQualityFromBytes(ctx, fileBytes)
 			if err == nil {
 				extractedText = text
 			}
@@ -399,28 +1377,24 @@ func (s *IncomeService) AnalyzeITR(fileHeader *multipart.FileHeader) (*dto.ITRRe
 		// ---------------------------------------------------
 		// CASE 2 — Non-PDF → PNG/JPG → Paddle first
 		// ---------------------------------------------------
-		paddleText, err := s.paddleClient.ExtractText(fileBytes)
+		paddleText, err := s.paddleClient.ExtractText(ctx, fileBytes)
 		if err == nil && len(strings.TrimSpace(paddleText)) > 5 {
 			extractedText = paddleText
 		} else {
 			// fallback to Tesseract
-			text, _, err := s.tesseractClient.ExtractTextAndQualityFromFile(fileHeader)
+			text, _, err := s.tesseractClient.ExtractTextAndQualityFromBytes(ctx, fileBytes)
 			if err != nil {
-				return nil, fmt.Errorf("OCR failed: %w", err)
+				return "", nil, fmt.Errorf("%w: %w", ErrOCRFailed, err)
 			}
 			extractedText = text
 		}
 	}
 
 	if len(strings.TrimSpace(extractedText)) == 0 {
-		return nil, fmt.Errorf("no text could be extracted from the document")
+		return "", nil, ErrOCRFailed
 	}
 
-	result := utils.ParseITR(extractedText)
-
-	log.Printf("ITR analysis done → PAN=%s Name=%s AY=%s", result.PAN, result.Name, result.AssessmentYear)
-
-	return &result, nil
+	return extractedText, provenance, nil
 }
 
 // evaluateTextQuality evaluates the quality of extracted text
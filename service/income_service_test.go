@@ -1,10 +1,26 @@
 package service
 
 import (
+	"context"
+	"errors"
+	"image"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/client/ocrmock"
 	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/fieldtemplate"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCrossCheck(t *testing.T) {
@@ -72,3 +88,502 @@ func TestCrossCheckMismatch(t *testing.T) {
 	assert.False(t, result.AccountMatch)
 	assert.NotEmpty(t, result.MissingSalaryCredits)
 }
+
+func TestCrossCheckSalaryTolerance(t *testing.T) {
+	service := &IncomeService{salaryTolerance: 1, salaryTolerancePct: 0.5, salaryWindowDays: 7}
+	day := func(m time.Month, d int) time.Time { return time.Date(2025, m, d, 0, 0, 0, 0, time.UTC) }
+
+	slips := []dto.SalarySlipData{
+		{EmployerName: "Acme Software Pvt Ltd", NetSalary: 50000, PayMonth: "October 2025"},
+		{EmployerName: "Acme Software Pvt Ltd", NetSalary: 50000, PayMonth: "August 2025"},
+		{EmployerName: "Acme Software Pvt Ltd", NetSalary: 62000, PayMonth: "September 2025"},
+	}
+	stmts := []dto.BankStatementData{{
+		Transactions: []dto.BankTransaction{
+			{Date: day(time.November, 3), IsCredit: true, Amount: 49999.50, Description: "NEFT-ACME SOFTWARE"}, // October, paid in the window
+			{Date: day(time.July, 1), IsCredit: true, Amount: 50000, Description: "SALARY JUL"},                // before August's window
+			{Date: day(time.September, 30), IsCredit: true, Amount: 61800, Description: "NEFT-12345"},          // 0.32% short, undescribed
+		},
+	}}
+
+	result := service.CrossCheck(slips, stmts)
+
+	assert.Equal(t, []string{"Missing credit for August 2025: 50000.00"}, result.MissingSalaryCredits)
+	require.NotEmpty(t, result.Notes)
+	assert.Contains(t, result.Notes[len(result.Notes)-1], "September 2025 salary names neither salary nor")
+}
+
+func TestCrossCheckUnknownEmployer(t *testing.T) {
+	service := &IncomeService{}
+	slips := []dto.SalarySlipData{
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "October 2025",
+			EmployerRegistration: &dto.EmployerRegistration{Found: true, Status: "Active", Active: true}},
+		{EmployerName: "Ghost Infotech Pvt Ltd", PayMonth: "October 2025",
+			EmployerRegistration: &dto.EmployerRegistration{}},
+		{EmployerName: "Shell Traders Pvt Ltd", PayMonth: "October 2025",
+			EmployerRegistration: &dto.EmployerRegistration{Found: true, CIN: "U51909DL2015PTC123456", RegisteredName: "SHELL TRADERS PRIVATE LIMITED", Status: "Strike Off"}},
+		{EmployerName: "Unchecked Ltd", PayMonth: "October 2025"},
+	}
+
+	result := service.CrossCheck(slips, nil)
+
+	require.Len(t, result.FraudSignals, 2)
+	assert.Equal(t, dto.FraudUnknownEmployer, result.FraudSignals[0].Type)
+	assert.Contains(t, result.FraudSignals[0].Detail, "Ghost Infotech")
+	assert.Contains(t, result.FraudSignals[1].Detail, "Strike Off")
+}
+
+func TestCrossCheckRent(t *testing.T) {
+	rents := []dto.RentData{
+		{Kind: "agreement", TenantName: "Ravi Kumar", MonthlyRent: 25000, PeriodFrom: "2024-04-01"},
+		{Kind: "receipt", TenantName: "Anil Sharma", MonthlyRent: 27500, PeriodFrom: "2025-04-01"},
+	}
+	slips := []dto.SalarySlipData{{EmployeeName: "Ravi Kumar"}}
+
+	var result dto.CrossCheckResult
+	crossCheckRent(&result, rents, slips, nil)
+
+	require.Len(t, result.Notes, 1)
+	assert.Contains(t, result.Notes[0], "Anil Sharma")
+	assert.Equal(t, 27500.0, monthlyRent(rents))
+}
+
+func TestApplyTemplateNamesOnlyAppliedTemplates(t *testing.T) {
+	tpl, err := fieldtemplate.Parse([]byte(`
+name: acme_payslip
+doc_type: salary_slip
+match: ["ACME Technologies"]
+fields:
+  net_salary:
+    anchor: "Net Pay"
+    regex: 'Net Pay\s*:\s*([0-9,]+)'
+    validators: [amount]
+`))
+	require.NoError(t, err)
+	s := &IncomeService{templates: &fieldtemplate.Registry{}}
+	s.templates.Add(tpl)
+
+	var slip dto.SalarySlipData
+	assert.Equal(t, "acme_payslip", s.applyTemplate("ACME Technologies\nNet Pay: 72,450", dto.DocTypeSalarySlip, &slip))
+	assert.Equal(t, 72450.0, slip.NetSalary)
+	assert.Empty(t, s.applyTemplate("ACME Technologies\nNet Salary 72,450", dto.DocTypeSalarySlip, &slip), "no field found")
+}
+
+func TestSamplePages(t *testing.T) {
+	assert.Equal(t, []int{1, 5}, samplePages(5, 2))
+	assert.Equal(t, []int{1, 3, 5}, samplePages(5, 3))
+	assert.Equal(t, []int{4}, samplePages(4, 1))
+	assert.Equal(t, []int{1, 2}, samplePages(2, 5))
+	assert.Nil(t, samplePages(0, 2))
+}
+
+func TestRunOCRChainFallsBack(t *testing.T) {
+	empty := func(context.Context, []byte) (string, float64, error) { return "  ", 75, nil }
+	failing := func(context.Context, []byte) (string, float64, error) { return "", 0, errors.New("down") }
+	tess := func(context.Context, []byte) (string, float64, error) { return "NET PAY 45,200.00", 88, nil }
+
+	text, conf, err := runOCRChain(context.Background(), []ocrEngine{empty, tess}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "NET PAY 45,200.00", text)
+	assert.Equal(t, 88.0, conf)
+
+	_, _, err = runOCRChain(context.Background(), []ocrEngine{empty, failing}, nil)
+	assert.Error(t, err)
+
+	// A cancelled request does not fall through to the next engine
+	ctx, cancel := context.WithCancel(context.Background())
+	cancelling := func(context.Context, []byte) (string, float64, error) { cancel(); return "", 0, errors.New("canceled") }
+	_, _, err = runOCRChain(ctx, []ocrEngine{cancelling, tess}, nil)
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCrossCheckGST(t *testing.T) {
+	service := &IncomeService{}
+	jan := time.Date(2025, time.January, 10, 0, 0, 0, 0, time.UTC)
+
+	stmts := []dto.BankStatementData{
+		{
+			AccountHolderName: "Ravi Kumar",
+			Transactions: []dto.BankTransaction{
+				{Date: jan, IsCredit: true, Amount: 90000},
+			},
+		},
+	}
+	gst := []dto.GSTData{
+		{Kind: "gstr3b", LegalName: "RAVI KUMAR", TradeName: "KUMAR FOODS", ReturnPeriod: "2025-01", Turnover: 400000},
+		{Kind: "gstr3b", LegalName: "RAVI KUMAR", ReturnPeriod: "2025-02", Turnover: 400000}, // not covered by the statement
+	}
+
+	result := service.CrossCheck(nil, stmts)
+	service.crossCheckGST(&result, gst, stmts)
+
+	assert.True(t, result.NameMatch)
+	assert.Len(t, result.Notes, 1)
+	assert.Contains(t, result.Notes[0], "2025-01")
+}
+
+func TestCrossCheckTDS(t *testing.T) {
+	service := &IncomeService{}
+	forms := []dto.Form26ASData{{
+		Kind:           "26as",
+		AssessmentYear: "2025-26",
+		Deductors: []dto.TDSDeductor{{
+			Name: "ACME SOFTWARE PRIVATE LIMITED",
+			TAN:  "BLRA12345B",
+			Entries: []dto.TDSEntry{
+				{Section: "192", TransactionDate: "2024-10-31", Quarter: "Q3", FinancialYear: "2024-25", AmountPaid: 100000, TaxDeducted: 10000},
+				{Section: "192", TransactionDate: "2024-11-30", Quarter: "Q3", FinancialYear: "2024-25", AmountPaid: 100000, TaxDeducted: 10000},
+			},
+		}},
+	}}
+	slips := []dto.SalarySlipData{
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "October 2024", NetSalary: 85000},
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "November 2024", NetSalary: 150000}, // inflated
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "December 2024", NetSalary: 85000},  // not reported
+		{EmployerName: "Globex Corporation", PayMonth: "October 2024", NetSalary: 85000},      // not a deductor
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "October 2025", NetSalary: 85000},   // FY not covered
+	}
+
+	result := service.CrossCheck(slips, nil)
+	service.crossCheckTDS(&result, slips, forms)
+
+	require.NotNil(t, result.TDS)
+	assert.False(t, result.TDS.Consistent)
+	assert.Equal(t, "BLRA12345B", result.TDS.TAN)
+	require.Len(t, result.TDS.Months, 4)
+	assert.True(t, result.TDS.Months[0].Consistent)
+	assert.Equal(t, 100000.0, result.TDS.Months[0].ReportedPaid)
+
+	var signals []string
+	for _, sig := range result.FraudSignals {
+		if sig.Type == dto.FraudTDSMismatch {
+			signals = append(signals, sig.Detail)
+		}
+	}
+	require.Len(t, signals, 3)
+	assert.Contains(t, signals[0], "2024-11")
+	assert.Contains(t, signals[1], "2024-12")
+	assert.Contains(t, signals[2], "Globex")
+	assert.Contains(t, result.Notes[len(result.Notes)-1], "2025-26")
+}
+
+func TestCrossCheckEPF(t *testing.T) {
+	passbooks := []dto.EPFPassbookData{{
+		UAN: "100123456789",
+		Establishments: []dto.EPFEstablishment{{
+			ID:   "BGBNG0012345000",
+			Name: "ACME SOFTWARE PRIVATE LIMITED",
+			Contributions: []dto.EPFContribution{
+				{Month: "2024-09", EmployeeShare: 3600},
+				{Month: "2024-10", EmployeeShare: 3600},
+				{Month: "2024-12", EmployeeShare: 3600},
+			},
+		}},
+	}}
+	slips := []dto.SalarySlipData{
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "October 2024", NetSalary: 85000},
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "November 2024", NetSalary: 85000}, // no contribution
+		{EmployerName: "Globex Corporation", PayMonth: "December 2024", NetSalary: 85000},    // not an establishment
+		{EmployerName: "Acme Software Pvt Ltd", PayMonth: "January 2025", NetSalary: 85000},  // not yet posted
+	}
+
+	result := (&IncomeService{}).CrossCheck(slips, nil)
+	crossCheckEPF(&result, slips, passbooks)
+
+	require.NotNil(t, result.EPF)
+	assert.False(t, result.EPF.Consistent)
+	assert.Equal(t, "BGBNG0012345000", result.EPF.EstablishmentID)
+	assert.Equal(t, []dto.EPFMonthCheck{{Month: "2024-10", Contributed: true}, {Month: "2024-11"}, {Month: "2024-12"}}, result.EPF.Months)
+	assert.Equal(t, []string{"2024-11"}, result.EPF.Gaps)
+
+	var signals []string
+	for _, sig := range result.FraudSignals {
+		if sig.Type == dto.FraudEPFMismatch {
+			signals = append(signals, sig.Detail)
+		}
+	}
+	require.Len(t, signals, 2)
+	assert.Contains(t, signals[0], "2024-11")
+	assert.Contains(t, signals[1], "Globex")
+	assert.Contains(t, result.Notes[len(result.Notes)-1], "2025-01")
+}
+
+func TestVerifyDocumentsPartialSuccess(t *testing.T) {
+	// A stand-in pipeline: "bad" uploads fail to parse, anything else is a slip
+	defs := &pipeline.Definitions{Default: map[string][]string{"salary_slip": {"fake"}}}
+	s := &IncomeService{requestConcurrency: 2, pipelines: pipeline.NewOrchestrator(defs, pipeline.Registry{
+		"fake": func(string) (pipeline.Step, error) {
+			return pipeline.StepFunc(func(doc *pipeline.Doc) error {
+				if string(doc.Inputs[0]) == "bad" {
+					return errors.New("unreadable")
+				}
+				doc.Result = dto.SalarySlipData{EmployeeName: doc.Filename}
+				return nil
+			}), nil
+		},
+	})}
+
+	meta := dto.UploadMetadata{Documents: []dto.DocumentMeta{
+		{Filename: "jan.pdf", DocType: dto.DocTypeSalarySlip},
+		{Filename: "feb.pdf", DocType: dto.DocTypeSalarySlip},
+		{Filename: "mar.pdf", DocType: dto.DocTypeSalarySlip},
+	}}
+	resp, err := s.verifyDocuments(context.Background(), meta, map[string][]byte{"jan.pdf": []byte("ok"), "feb.pdf": []byte("bad")})
+	require.NoError(t, err)
+	assert.Equal(t, dto.VerificationPartial, resp.Status)
+	require.Len(t, resp.SalarySlips, 1)
+	assert.Equal(t, "jan.pdf", resp.SalarySlips[0].EmployeeName)
+	require.Len(t, resp.Documents, 3)
+	assert.Equal(t, dto.DocumentSucceeded, resp.Documents[0].Status)
+	assert.Equal(t, dto.DocumentFailed, resp.Documents[1].Status)
+	assert.Contains(t, resp.Documents[1].Error, "unreadable")
+	assert.Contains(t, resp.Documents[2].Error, "not found in upload")
+
+	resp, err = s.verifyDocuments(context.Background(), meta, map[string][]byte{"feb.pdf": []byte("bad")})
+	require.NoError(t, err)
+	assert.Equal(t, dto.VerificationFailed, resp.Status)
+	assert.Empty(t, resp.SalarySlips)
+}
+
+func TestVerifyDocumentsSkipsDuplicates(t *testing.T) {
+	// A stand-in pipeline reading "pay month,net pay,quality" slips
+	defs := &pipeline.Definitions{Default: map[string][]string{"salary_slip": {"fake"}}}
+	s := &IncomeService{requestConcurrency: 2, store: store.NewMemoryStore(0, 0), pipelines: pipeline.NewOrchestrator(defs, pipeline.Registry{
+		"fake": func(string) (pipeline.Step, error) {
+			return pipeline.StepFunc(func(doc *pipeline.Doc) error {
+				f := strings.Split(string(doc.Inputs[0]), ",")
+				net, _ := strconv.ParseFloat(f[1], 64)
+				doc.Quality.FinalScore, _ = strconv.ParseFloat(f[2], 64)
+				doc.Result = dto.SalarySlipData{EmployeeName: doc.Filename, EmployerName: "Acme", PayMonth: f[0], NetSalary: net}
+				return nil
+			}), nil
+		},
+	})}
+
+	meta := dto.UploadMetadata{ApplicantID: "app-1", Documents: []dto.DocumentMeta{
+		{Filename: "jan.pdf", DocType: dto.DocTypeSalarySlip},
+		{Filename: "jan-copy.pdf", DocType: dto.DocTypeSalarySlip},
+		{Filename: "feb-scan.pdf", DocType: dto.DocTypeSalarySlip},
+		{Filename: "feb.pdf", DocType: dto.DocTypeSalarySlip},
+	}}
+	files := map[string][]byte{
+		"jan.pdf":      []byte("2025-01,80000,90"),
+		"jan-copy.pdf": []byte("2025-01,80000,90"),
+		"feb-scan.pdf": []byte("2025-02,8000,40"),
+		"feb.pdf":      []byte("2025-02,80000,85"),
+	}
+	resp, err := s.verifyDocuments(context.Background(), meta, files)
+	require.NoError(t, err)
+	assert.Equal(t, dto.VerificationComplete, resp.Status, "duplicates do not make a verification partial")
+	require.Len(t, resp.SalarySlips, 2)
+	assert.Equal(t, "jan.pdf", resp.SalarySlips[0].EmployeeName)
+	assert.Equal(t, "feb.pdf", resp.SalarySlips[1].EmployeeName, "the better-quality slip of the month is kept")
+
+	docs := resp.Documents
+	assert.Equal(t, dto.DocumentDuplicate, docs[1].Status)
+	assert.Equal(t, "jan.pdf", docs[1].DuplicateOf)
+	assert.Equal(t, duplicateFile, docs[1].DuplicateReason)
+	assert.Equal(t, docs[0].ContentHash, docs[1].ContentHash)
+	assert.Equal(t, dto.DocumentDuplicate, docs[2].Status)
+	assert.Equal(t, "feb.pdf", docs[2].DuplicateOf)
+	assert.Equal(t, "same pay month 2025-02", docs[2].DuplicateReason)
+	assert.Equal(t, dto.DocumentSucceeded, docs[3].Status)
+	assert.Contains(t, resp.CrossCheck.Notes[len(resp.CrossCheck.Notes)-1], "net pay 80000.00 and 8000.00")
+
+	// Resubmitted for the same applicant: pointed at the earlier verification
+	first := resp.VerificationID
+	resp, err = s.verifyDocuments(context.Background(), dto.UploadMetadata{ApplicantID: "app-1", Documents: meta.Documents[:1]}, files)
+	require.NoError(t, err)
+	assert.Equal(t, first, resp.Documents[0].PreviousVerificationID)
+
+	// another API client's verifications are not searched
+	resp, err = s.verifyDocuments(context.Background(), dto.UploadMetadata{ApplicantID: "app-1", Client: "lender-b", Documents: meta.Documents[:1]}, files)
+	require.NoError(t, err)
+	assert.Empty(t, resp.Documents[0].PreviousVerificationID)
+}
+
+// passwordPDF opens only with its password.
+type passwordPDF struct {
+	PDFProcessor
+	password string
+	tried    []string
+}
+
+func (p *passwordPDF) Decrypt(data []byte, password string) ([]byte, error) {
+	p.tried = append(p.tried, password)
+	if password != p.password {
+		return nil, errors.New("failed to decrypt PDF: wrong password")
+	}
+	return []byte("%PDF-1.7 decrypted"), nil
+}
+
+func (p *passwordPDF) VerifySignatures([]byte, string) ([]dto.DocumentSignature, error) {
+	return nil, nil
+}
+
+func TestDecryptStepTriesDerivedPasswords(t *testing.T) {
+	hints := &dto.PasswordHints{PAN: "ABCPK1234F", DOB: "14/08/1990"}
+	pdf := &passwordPDF{password: "14081990"}
+	doc := &pipeline.Doc{Ctx: context.Background(), Password: "wrong", PasswordHints: hints,
+		Inputs: [][]byte{[]byte("%PDF-1.7 /Encrypt")}}
+	require.NoError(t, decryptStep(pdf)(doc))
+	assert.Equal(t, []string{"wrong", "ABCPK1234F", "abcpk1234f", "14081990"}, pdf.tried)
+	assert.Equal(t, "%PDF-1.7 decrypted", string(doc.Inputs[0]))
+	assert.Empty(t, doc.Password)
+
+	pdf = &passwordPDF{password: "secret"}
+	doc = &pipeline.Doc{Ctx: context.Background(), PasswordHints: hints, Inputs: [][]byte{[]byte("%PDF-1.7 /Encrypt")}}
+	err := decryptStep(pdf)(doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "tried 5 passwords derived from the password hints")
+
+	// Unprotected files are left alone
+	pdf = &passwordPDF{}
+	doc = &pipeline.Doc{Ctx: context.Background(), PasswordHints: hints, Inputs: [][]byte{[]byte("%PDF-1.7")}}
+	require.NoError(t, decryptStep(pdf)(doc))
+	assert.Empty(t, pdf.tried)
+}
+
+func TestOCRStepFallsBackToTesseract(t *testing.T) {
+	paddle := &ocrmock.Paddle{Err: client.ErrPaddleUnavailable}
+	tesseract := &ocrmock.Tesseract{Result: dto.StructuredText{Text: "Net Pay 45,200.00", Confidence: 88}}
+	step, err := PipelineSteps(nil, paddle, tesseract)["ocr"]("paddle|tesseract")
+	require.NoError(t, err)
+
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	doc := &pipeline.Doc{Ctx: context.Background(), Images: []image.Image{img, img}}
+	require.NoError(t, step.Run(doc))
+	assert.Equal(t, []string{"Net Pay 45,200.00", "Net Pay 45,200.00"}, doc.PageTexts)
+	assert.Len(t, paddle.Calls(), 2)
+	assert.Len(t, tesseract.Calls(), 2)
+}
+
+func TestOCRStepRereadsPagesInTheirScript(t *testing.T) {
+	var langs []string
+	engine := func(lang string, words func([]dto.OCRWord)) ocrEngine {
+		return func(context.Context, []byte) (string, float64, error) {
+			langs = append(langs, lang)
+			if lang == "hin+eng" {
+				return "भारत सरकार नाम रवि कुमार", 90, nil
+			}
+			return "भारत सरकार नम रव", 40, nil // the default language misreads it
+		}
+	}
+	img := image.NewGray(image.Rect(0, 0, 4, 4))
+	doc := &pipeline.Doc{Ctx: context.Background(), Images: []image.Image{img, img}}
+	require.NoError(t, ocrStep([]langOCREngine{engine}, &ocrmock.Tesseract{})(doc))
+	assert.Equal(t, []string{"", "hin+eng", "hin+eng"}, langs, "later pages are read in the detected language")
+	assert.Equal(t, "Devanagari", doc.Quality.Script)
+	assert.Equal(t, "hin+eng", doc.Quality.OCRLang)
+	assert.Equal(t, "भारत सरकार नाम रवि कुमार", doc.PageTexts[0])
+
+	// A language hint is kept
+	langs = nil
+	doc = &pipeline.Doc{Ctx: context.Background(), Lang: "eng", Images: []image.Image{img}}
+	require.NoError(t, ocrStep([]langOCREngine{engine}, &ocrmock.Tesseract{})(doc))
+	assert.Equal(t, []string{"eng"}, langs)
+	assert.Empty(t, doc.Quality.OCRLang)
+}
+
+func TestSpreadsheetStepReadsCSVStatement(t *testing.T) {
+	s := &IncomeService{}
+	csv := "Account No : 50100123456789\n" +
+		"Date,Narration,Withdrawal Amt.,Deposit Amt.,Closing Balance\n" +
+		"01/02/2024,NEFT CR-ACME TECHNOLOGIES-SALARY FEB,,\"74,050.00\",\"84,050.00\"\n"
+	doc := &pipeline.Doc{Ctx: context.Background(), DocType: string(dto.DocTypeBankStatement), Filename: "feb.csv", Inputs: [][]byte{[]byte(csv)}}
+	require.NoError(t, s.spreadsheetStep(doc))
+	assert.True(t, doc.Done, "nothing is left to OCR")
+
+	stmt, ok := doc.Result.(dto.BankStatementData)
+	require.True(t, ok)
+	assert.Equal(t, "csv", stmt.Source)
+	require.Len(t, stmt.Transactions, 1)
+	assert.Equal(t, 74050.0, stmt.Transactions[0].Amount)
+	assert.Equal(t, 100.0, doc.Quality.FinalScore)
+
+	// a scan goes on to OCR
+	doc = &pipeline.Doc{Ctx: context.Background(), DocType: string(dto.DocTypeBankStatement), Filename: "feb.pdf", Inputs: [][]byte{[]byte("%PDF-1.7")}}
+	require.NoError(t, s.spreadsheetStep(doc))
+	assert.False(t, doc.Done)
+	assert.Nil(t, doc.Result)
+}
+
+func TestAccountAggregatorStep(t *testing.T) {
+	s := &IncomeService{}
+	fi := `{"Account": {"type": "deposit", "maskedAccNumber": "XXXX6789",
+	  "Transactions": {"Transaction": [{"type": "CREDIT", "amount": "74050.00", "currentBalance": "84050.00", "valueDate": "2024-02-01", "narration": "NEFT CR-ACME-SALARY FEB"}]}}}`
+	doc := &pipeline.Doc{Ctx: context.Background(), DocType: string(dto.DocTypeBankStatement), Filename: "aa-fi.json", Inputs: [][]byte{[]byte(fi)}}
+	require.NoError(t, s.accountAggregatorStep(doc))
+	assert.True(t, doc.Done)
+	stmt, ok := doc.Result.(dto.BankStatementData)
+	require.True(t, ok)
+	assert.Equal(t, "account_aggregator", stmt.Source)
+	require.Len(t, stmt.Transactions, 1)
+	assert.Equal(t, 100.0, doc.Quality.FinalScore)
+
+	// one upload is one account
+	two := `[` + fi + `,` + strings.Replace(fi, "XXXX6789", "XXXX1111", 1) + `]`
+	doc = &pipeline.Doc{Ctx: context.Background(), DocType: string(dto.DocTypeBankStatement), Filename: "aa-fi.json", Inputs: [][]byte{[]byte(two)}}
+	err := s.accountAggregatorStep(doc)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "XXXX6789, XXXX1111")
+}
+
+func TestVerifyDocumentsWithoutSideEffects(t *testing.T) {
+	t.Setenv("WEBHOOK_ALLOW_PRIVATE", "true")
+	var delivered atomic.Int32
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		delivered.Add(1)
+	}))
+	defer receiver.Close()
+
+	defs := &pipeline.Definitions{Default: map[string][]string{"salary_slip": {"fake"}}}
+	records := store.NewMemoryStore(0, 0)
+	s := &IncomeService{requestConcurrency: 2, store: records, webhooks: client.NewWebhookClient(), pipelines: pipeline.NewOrchestrator(defs, pipeline.Registry{
+		"fake": func(string) (pipeline.Step, error) {
+			return pipeline.StepFunc(func(doc *pipeline.Doc) error {
+				doc.Result = dto.SalarySlipData{EmployeeName: doc.Filename}
+				return nil
+			}), nil
+		},
+	})}
+	meta := dto.UploadMetadata{
+		Client:      "acme",
+		CallbackURL: receiver.URL,
+		Documents:   []dto.DocumentMeta{{Filename: "jan.pdf", DocType: dto.DocTypeSalarySlip}},
+	}
+	files := map[string][]byte{"jan.pdf": []byte("ok")}
+
+	// a partial response, then the same request continued in full
+	first, err := s.VerifyDocuments(context.Background(), meta, files)
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.VerificationID)
+	again, err := s.VerifyDocuments(WithoutSideEffects(context.Background()), meta, files)
+	require.NoError(t, err)
+	assert.Empty(t, again.VerificationID)
+
+	recs, err := records.List(store.ListFilter{Client: "acme"})
+	require.NoError(t, err)
+	assert.Len(t, recs, 1)
+	assert.Eventually(t, func() bool { return delivered.Load() == 1 }, 2*time.Second, 10*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), delivered.Load())
+}
+
+func TestApplyDocumentAgeMidMonthScan(t *testing.T) {
+	s := &IncomeService{}
+	content, ok := utils.ParsePayMonth("October 2025")
+	require.True(t, ok)
+
+	// a slip for October scanned on the 15th, before the month's last day
+	var quality dto.DocumentQuality
+	midMonth := time.Date(2025, time.October, 15, 9, 30, 0, 0, time.UTC)
+	s.applyDocumentAge(&quality, &content, &midMonth)
+	assert.NotContains(t, quality.Issues, "scan_date_precedes_content_date")
+
+	// scanned in September, before its pay month began
+	quality = dto.DocumentQuality{}
+	before := time.Date(2025, time.September, 20, 0, 0, 0, 0, time.UTC)
+	s.applyDocumentAge(&quality, &content, &before)
+	assert.Contains(t, quality.Issues, "scan_date_precedes_content_date")
+}
@@ -0,0 +1,256 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/address"
+)
+
+// KYCDocument is one uploaded file of a KYC verification.
+type KYCDocument struct {
+	Filename string
+	Data     []byte
+	MimeType string
+	Password string
+}
+
+// KYCRequest holds the documents of one applicant: Aadhaar, PAN, a salary
+// slip or bank statement (IncomeType), and optionally a driving licence, a
+// passport and a utility bill as address proof.
+type KYCRequest struct {
+	Aadhaar    KYCDocument
+	PAN        KYCDocument
+	Income     KYCDocument
+	IncomeType dto.DocumentType
+	TenantID   string
+
+	DrivingLicense *KYCDocument
+	Passport       *KYCDocument
+	AddressProof   *KYCDocument
+}
+
+// KYCService extracts an applicant's identity and income documents and
+// cross-verifies the name, date of birth and address they give.
+type KYCService struct {
+	aadhaar  *AadhaarService
+	pan      *PANService
+	dl       *DrivingLicenseService
+	passport *PassportService
+	bills    *AddressProofService
+	income   *IncomeService
+}
+
+func NewKYCService(aadhaar *AadhaarService, pan *PANService, dl *DrivingLicenseService, passport *PassportService, bills *AddressProofService, income *IncomeService) *KYCService {
+	return &KYCService{aadhaar: aadhaar, pan: pan, dl: dl, passport: passport, bills: bills, income: income}
+}
+
+// Verify runs the extractors one after another (the OCR engines are shared).
+// A document that cannot be read is reported in the report's Documents and
+// left out of the checks; only a cancelled request is an error.
+func (s *KYCService) Verify(ctx context.Context, req KYCRequest) (*dto.KYCReport, error) {
+	report := &dto.KYCReport{}
+	record := func(doc KYCDocument, docType dto.DocumentType, err error) {
+		st := dto.DocumentStatus{Filename: doc.Filename, DocType: docType, Status: dto.DocumentSucceeded}
+		if err != nil {
+			slog.WarnContext(ctx, "KYC document failed", "file", doc.Filename, "doc_type", docType, "error", err)
+			st.Status, st.Error = dto.DocumentFailed, err.Error()
+		}
+		report.Documents = append(report.Documents, st)
+	}
+
+	aadhaar, err := s.aadhaar.ExtractFromFile(ctx, req.Aadhaar.Data, req.Aadhaar.MimeType, req.Aadhaar.Password)
+	if err == nil {
+		report.Aadhaar = aadhaar
+	}
+	record(req.Aadhaar, dto.KYCDocAadhaar, err)
+
+	pan, err := s.pan.ExtractPANFromBytes(ctx, req.PAN.Data, req.PAN.Filename)
+	if err == nil {
+		report.PAN = pan
+	}
+	record(req.PAN, dto.KYCDocPAN, err)
+
+	result, err := s.income.ProcessDocument(ctx, req.Income.Data, dto.DocumentMeta{
+		Filename: req.Income.Filename,
+		DocType:  req.IncomeType,
+		Password: req.Income.Password,
+	}, req.TenantID)
+	if err == nil {
+		switch r := result.(type) {
+		case dto.SalarySlipData:
+			report.SalarySlip = &r
+		case dto.BankStatementData:
+			report.BankStatement = &r
+		default:
+			err = fmt.Errorf("unexpected result %T for %s", result, req.IncomeType)
+		}
+	}
+	record(req.Income, req.IncomeType, err)
+
+	if doc := req.DrivingLicense; doc != nil {
+		dl, err := s.dl.ExtractDLText(ctx, doc.Data)
+		if err == nil {
+			report.DrivingLicense = dl
+		}
+		record(*doc, dto.KYCDocDL, err)
+	}
+	if doc := req.Passport; doc != nil {
+		passport, err := s.passport.ExtractPassport(ctx, doc.Data)
+		if err == nil {
+			report.Passport = passport
+		}
+		record(*doc, dto.KYCDocPassport, err)
+	}
+	if doc := req.AddressProof; doc != nil {
+		bill, err := s.bills.ExtractBill(ctx, doc.Data, doc.Filename, doc.Password)
+		if err == nil {
+			report.AddressProof = bill
+		}
+		record(*doc, dto.KYCDocBill, err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	failed := 0
+	for _, d := range report.Documents {
+		if d.Status == dto.DocumentFailed {
+			failed++
+		}
+	}
+	switch {
+	case failed == len(report.Documents):
+		report.Status = dto.VerificationFailed
+	case failed > 0:
+		report.Status = dto.VerificationPartial
+	default:
+		report.Status = dto.VerificationComplete
+	}
+
+	crossVerifyKYC(report)
+	return report, nil
+}
+
+// crossVerifyKYC fills the field checks, score, verdict and mismatches of
+// report from the documents it holds. Dates of birth are compared in their
+// normalized form; one that is not a valid date is left out.
+func crossVerifyKYC(report *dto.KYCReport) {
+	names := map[string]string{}
+	dobs := map[string]string{}
+	addresses := map[string]string{}
+	if a := report.Aadhaar; a != nil {
+		names[dto.KYCDocAadhaar] = a.Name
+		dobs[dto.KYCDocAadhaar], _ = utils.NormalizeDOB(a.DOB)
+		addresses[dto.KYCDocAadhaar] = a.Address
+	}
+	if p := report.PAN; p != nil {
+		names[dto.KYCDocPAN] = p.Name
+		dobs[dto.KYCDocPAN], _ = utils.NormalizeDOB(p.DOB)
+	}
+	if dl := report.DrivingLicense; dl != nil {
+		names[dto.KYCDocDL] = dl.Name
+		dobs[dto.KYCDocDL], _ = utils.NormalizeDOB(dl.DOB)
+		addresses[dto.KYCDocDL] = dl.Address
+	}
+	if p := report.Passport; p != nil {
+		names[dto.KYCDocPassport] = p.GivenNames + " " + p.Surname
+		dobs[dto.KYCDocPassport], _ = utils.NormalizeDOB(p.DOB)
+	}
+	if slip := report.SalarySlip; slip != nil {
+		names[string(dto.DocTypeSalarySlip)] = slip.EmployeeName
+	}
+	if stmt := report.BankStatement; stmt != nil {
+		names[string(dto.DocTypeBankStatement)] = stmt.AccountHolderName
+		addresses[string(dto.DocTypeBankStatement)] = stmt.Address
+	}
+	if bill := report.AddressProof; bill != nil {
+		// the bill may be in a family member's or landlord's name: address only
+		addresses[dto.KYCDocBill] = bill.Address
+	}
+
+	report.Fields = []dto.KYCFieldCheck{
+		checkKYCField(dto.KYCFieldName, names, func(a, b string) (float64, bool) {
+			score := utils.NameMatchScore(a, b)
+			return score, score >= utils.NameMatchThreshold
+		}),
+		checkKYCField(dto.KYCFieldDOB, dobs, func(a, b string) (float64, bool) {
+			if utils.SameDOB(a, b) {
+				return 1, true
+			}
+			return 0, false
+		}),
+		checkKYCField(dto.KYCFieldAddress, addresses, func(a, b string) (float64, bool) {
+			score := address.Similarity(address.Parse(a), address.Parse(b))
+			return score, score >= address.MatchThreshold
+		}),
+	}
+
+	report.Verdict = dto.KYCInsufficient
+	report.Mismatches = nil
+	var total float64
+	checked := 0
+	for _, f := range report.Fields {
+		if f.Status == dto.KYCInsufficient {
+			continue
+		}
+		total += f.Score
+		checked++
+		if report.Verdict != dto.KYCMismatch {
+			report.Verdict = f.Status
+		}
+		for _, c := range f.Comparisons {
+			if !c.Match {
+				report.Mismatches = append(report.Mismatches, fmt.Sprintf("%s: %s %q vs %s %q",
+					f.Field, c.Documents[0], f.Values[c.Documents[0]], c.Documents[1], f.Values[c.Documents[1]]))
+			}
+		}
+	}
+	if checked > 0 {
+		report.Score = total / float64(checked)
+	}
+}
+
+// kycDocumentOrder fixes the order of the comparisons in a report.
+var kycDocumentOrder = []string{
+	dto.KYCDocAadhaar, dto.KYCDocPAN, dto.KYCDocDL, dto.KYCDocPassport,
+	string(dto.DocTypeSalarySlip), string(dto.DocTypeBankStatement), dto.KYCDocBill,
+}
+
+// checkKYCField compares every pair of the non-empty values (document ->
+// value) with compare.
+func checkKYCField(field string, values map[string]string, compare func(a, b string) (float64, bool)) dto.KYCFieldCheck {
+	check := dto.KYCFieldCheck{Field: field, Values: map[string]string{}, Status: dto.KYCInsufficient}
+	var docs []string
+	for _, doc := range kycDocumentOrder {
+		if v := strings.TrimSpace(values[doc]); v != "" {
+			check.Values[doc] = v
+			docs = append(docs, doc)
+		}
+	}
+	if len(docs) < 2 {
+		return check
+	}
+
+	check.Score, check.Status = 1, dto.KYCMatch
+	for i := range docs {
+		for _, other := range docs[i+1:] {
+			score, match := compare(check.Values[docs[i]], check.Values[other])
+			check.Comparisons = append(check.Comparisons, dto.KYCComparison{
+				Documents: []string{docs[i], other},
+				Score:     score,
+				Match:     match,
+			})
+			check.Score = min(check.Score, score)
+			if !match {
+				check.Status = dto.KYCMismatch
+			}
+		}
+	}
+	return check
+}
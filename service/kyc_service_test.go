@@ -0,0 +1,68 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrossVerifyKYC(t *testing.T) {
+	report := &dto.KYCReport{
+		Aadhaar: &dto.AadhaarExtractResponse{
+			Name:    "Ravi Kumar",
+			DOB:     "1990",
+			Address: "C/O Suresh Kumar, 12 MG Road, Indiranagar, Bengaluru, Karnataka, 560038",
+		},
+		PAN:           &dto.PANResponse{Name: "RAVI KUMAR", DOB: "14/08/1990"},
+		BankStatement: &dto.BankStatementData{AccountHolderName: "Ravi Kumar", Address: "12, MG Road, Indiranagar, Bengaluru 560038"},
+	}
+	crossVerifyKYC(report)
+
+	require.Len(t, report.Fields, 3)
+	name, dob, address := report.Fields[0], report.Fields[1], report.Fields[2]
+	assert.Equal(t, dto.KYCMatch, name.Status)
+	assert.Len(t, name.Comparisons, 3)
+	// Aadhaar shows only the year of birth
+	assert.Equal(t, dto.KYCMatch, dob.Status)
+	assert.Equal(t, dto.KYCMatch, address.Status)
+	assert.Equal(t, []string{dto.KYCDocAadhaar, string(dto.DocTypeBankStatement)}, address.Comparisons[0].Documents)
+	assert.Equal(t, dto.KYCMatch, report.Verdict)
+	assert.Empty(t, report.Mismatches)
+
+	// A different PIN code and a different holder fail their checks
+	report.BankStatement = &dto.BankStatementData{AccountHolderName: "Anita Sharma", Address: "12 MG Road, Indiranagar, Bengaluru 560001"}
+	report.PAN.DOB = "14/08/1991"
+	report.DrivingLicense = &dto.DLResponse{Name: "RAVI KUMAR", DOB: "14-O8-1990"} // OCR read 0 as O
+	crossVerifyKYC(report)
+	for _, f := range report.Fields {
+		assert.Equal(t, dto.KYCMismatch, f.Status, f.Field)
+	}
+	assert.Equal(t, 0.0, report.Fields[2].Score)
+	assert.Equal(t, dto.KYCMismatch, report.Verdict)
+	assert.Contains(t, report.Mismatches, `dob: pan "1991-08-14" vs driving_license "1990-08-14"`)
+
+	// Only one document read: nothing to compare
+	report = &dto.KYCReport{PAN: &dto.PANResponse{Name: "RAVI KUMAR"}}
+	crossVerifyKYC(report)
+	assert.Equal(t, dto.KYCInsufficient, report.Verdict)
+	assert.Equal(t, "RAVI KUMAR", report.Fields[0].Values[dto.KYCDocPAN])
+}
+
+func TestCrossVerifyKYCAddressProof(t *testing.T) {
+	report := &dto.KYCReport{
+		Aadhaar: &dto.AadhaarExtractResponse{
+			Name:    "Ravi Kumar",
+			Address: "S/O Suresh Kumar, No 12, 4th Cross, HSR Layout Sector 2, Bengaluru, Karnataka, 560102",
+		},
+		// a bill in the father's name proves the address, not the name
+		AddressProof: &dto.AddressProofResponse{ConsumerName: "Suresh Kumar", Address: "No 12, 4th Cross, HSR Layout Sector 2, Bengaluru 560102"},
+	}
+	crossVerifyKYC(report)
+
+	name, address := report.Fields[0], report.Fields[2]
+	assert.Equal(t, dto.KYCInsufficient, name.Status)
+	assert.Equal(t, dto.KYCMatch, address.Status)
+	assert.Equal(t, []string{dto.KYCDocAadhaar, dto.KYCDocBill}, address.Comparisons[0].Documents)
+}
@@ -0,0 +1,364 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"image/draw"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/imagehash"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageprep"
+)
+
+// ErrInvalidTemplate is returned for a layout template that cannot be
+// registered, or a sample it cannot be registered from.
+var ErrInvalidTemplate = errors.New("invalid layout template")
+
+// layoutMatchDistance is the imagehash.Distance up to which a first page is
+// taken to be in a template's layout. Pages of one layout differ in their
+// content, so it is looser than imagehash.DuplicateDistance.
+const layoutMatchDistance = 12
+
+// layoutAspectTolerance is the relative difference in page aspect ratio a
+// document in a template's layout may have (A4 vs Letter is 6%).
+const layoutAspectTolerance = 0.04
+
+// regionMinHeight is the height in pixels below which a region is enlarged
+// before OCR; Tesseract misreads text under about 20 px tall.
+const regionMinHeight = 60
+
+// layoutResultTypes are the document types layout templates apply to, with
+// the extraction result their fields are set in.
+var layoutResultTypes = map[string]func() interface{}{
+	string(dto.DocTypeSalarySlip):    func() interface{} { return &dto.SalarySlipData{} },
+	string(dto.DocTypeBankStatement): func() interface{} { return &dto.BankStatementData{} },
+}
+
+// LayoutTemplateService registers layout templates from annotated samples and
+// reads the fields of documents in a registered layout from their regions,
+// for layouts the generic parsers get wrong.
+type LayoutTemplateService struct {
+	store store.LayoutTemplateStore
+	pdf   PDFProcessor
+	// ocr reads the text of a region image
+	ocr func(ctx context.Context, png []byte, lang string) (string, error)
+	now func() time.Time
+}
+
+func NewLayoutTemplateService(templates store.LayoutTemplateStore, pdf PDFProcessor, tesseract client.TesseractEngine) *LayoutTemplateService {
+	return &LayoutTemplateService{
+		store: templates,
+		pdf:   pdf,
+		ocr: func(ctx context.Context, png []byte, lang string) (string, error) {
+			text, _, err := tesseract.ExtractTextAndQualityFromBytesLang(ctx, png, lang)
+			return text, err
+		},
+		now: time.Now,
+	}
+}
+
+// Register checks req, fingerprints the sample's first page and reads every
+// region on the sample (returned as SampleValue, for the annotator to check
+// the boxes), then stores the template.
+func (s *LayoutTemplateService) Register(ctx context.Context, data []byte, filename, password string, req dto.LayoutTemplateRequest, createdBy string) (*dto.LayoutTemplate, error) {
+	if err := checkLayoutTemplate(&req); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+
+	sample := &pipeline.Doc{Ctx: ctx, DocType: req.DocType, Filename: filename, Password: password, Inputs: [][]byte{data}}
+	first, err := s.page(sample, 1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: sample: %v", ErrInvalidTemplate, err)
+	}
+	tpl := &dto.LayoutTemplate{
+		ID:                    store.NewID(),
+		LayoutTemplateRequest: req,
+		Fingerprint:           layoutFingerprint(first),
+		CreatedBy:             createdBy,
+		CreatedAt:             s.now().UTC(),
+	}
+	for i, r := range tpl.Fields {
+		img, err := s.page(sample, r.Page)
+		if err != nil {
+			return nil, fmt.Errorf("%w: field %s: sample page %d: %v", ErrInvalidTemplate, r.Name, r.Page, err)
+		}
+		if tpl.Fields[i].SampleValue, err = s.readRegion(ctx, img, r, ""); err != nil {
+			return nil, fmt.Errorf("read field %s on the sample: %w", r.Name, err)
+		}
+	}
+
+	if err := s.store.SaveLayoutTemplate(tpl); err != nil {
+		return nil, err
+	}
+	slog.InfoContext(ctx, "Layout template registered", "id", tpl.ID, "name", tpl.Name, "doc_type", tpl.DocType, "by", createdBy)
+	return tpl, nil
+}
+
+// List returns the registered templates of docType, or all when it is empty.
+func (s *LayoutTemplateService) List(docType string) ([]dto.LayoutTemplate, error) {
+	templates, err := s.store.LayoutTemplates(docType)
+	if templates == nil {
+		templates = []dto.LayoutTemplate{}
+	}
+	return templates, err
+}
+
+// Delete removes a template; store.ErrNotFound for an unknown id.
+func (s *LayoutTemplateService) Delete(id string) error {
+	return s.store.DeleteLayoutTemplate(id)
+}
+
+// Apply reads the fields of the registered template doc is laid out like, if
+// any, from their regions into target (a pointer to doc's result) and
+// returns the template's name. Fields whose region reads nothing usable keep
+// the parsed value.
+func (s *LayoutTemplateService) Apply(doc *pipeline.Doc, target interface{}) string {
+	if s == nil {
+		return ""
+	}
+	templates, err := s.store.LayoutTemplates(doc.DocType)
+	if err != nil {
+		slog.WarnContext(doc.Ctx, "Layout templates not loaded", "error", err)
+		return ""
+	}
+	text := strings.ToLower(doc.Text)
+	var candidates []dto.LayoutTemplate
+	for _, tpl := range templates {
+		if (tpl.TenantID == "" || tpl.TenantID == doc.TenantID) && hasKeywords(text, tpl.Keywords) {
+			candidates = append(candidates, tpl)
+		}
+	}
+	if len(candidates) == 0 {
+		return "" // no page is rendered for documents no template can match
+	}
+
+	first, err := s.page(doc, 1)
+	if err != nil {
+		slog.WarnContext(doc.Ctx, "Layout template matching skipped: no first page", "file", doc.Filename, "error", err)
+		return ""
+	}
+	tpl, ok := matchLayout(candidates, layoutFingerprint(first))
+	if !ok {
+		return ""
+	}
+
+	read := 0
+	for _, r := range tpl.Fields {
+		img, err := s.page(doc, r.Page)
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Layout template field skipped: no page", "template", tpl.Name, "field", r.Name, "page", r.Page, "error", err)
+			continue
+		}
+		raw, err := s.readRegion(doc.Ctx, img, r, doc.Language())
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Layout template region OCR failed", "template", tpl.Name, "field", r.Name, "error", err)
+			continue
+		}
+		value, ok := utils.ParseFieldValue(r.Type, raw)
+		if !ok {
+			continue
+		}
+		if err := setResultField(target, r.Name, value); err != nil {
+			slog.WarnContext(doc.Ctx, "Layout template field not set", "template", tpl.Name, "field", r.Name, "error", err)
+			continue
+		}
+		read++
+	}
+	slog.InfoContext(doc.Ctx, "Layout template applied", "file", doc.Filename, "template", tpl.Name, "fields", read, "of", len(tpl.Fields))
+	return tpl.Name
+}
+
+// checkLayoutTemplate validates req and fills in its defaults.
+func checkLayoutTemplate(req *dto.LayoutTemplateRequest) error {
+	newResult, ok := layoutResultTypes[req.DocType]
+	switch {
+	case req.Name == "":
+		return errors.New("name is required")
+	case !ok:
+		return fmt.Errorf("doc_type must be %s or %s", dto.DocTypeSalarySlip, dto.DocTypeBankStatement)
+	case len(req.Fields) == 0:
+		return errors.New("at least one field is required")
+	}
+
+	var keywords []string
+	for _, k := range req.Keywords {
+		if k = strings.TrimSpace(k); k != "" {
+			keywords = append(keywords, k)
+		}
+	}
+	req.Keywords = keywords
+
+	seen := map[string]bool{}
+	for i := range req.Fields {
+		r := &req.Fields[i]
+		if seen[r.Name] {
+			return fmt.Errorf("field %s is given twice", r.Name)
+		}
+		seen[r.Name] = true
+		if r.Type == "" {
+			r.Type = dto.FieldTypeString
+		}
+		if r.Page == 0 {
+			r.Page = 1
+		}
+		r.SampleValue = ""
+
+		var example interface{}
+		switch r.Type {
+		case dto.FieldTypeString:
+			example = "x"
+		case dto.FieldTypeAmount:
+			example = 1.0
+		case dto.FieldTypeDate:
+			example = "2024-01-31"
+		default:
+			return fmt.Errorf("field %s: type must be %s, %s or %s", r.Name, dto.FieldTypeString, dto.FieldTypeDate, dto.FieldTypeAmount)
+		}
+		if err := setResultField(newResult(), r.Name, example); err != nil {
+			return fmt.Errorf("field %s is not a %s field of a %s result: %v", r.Name, r.Type, req.DocType, err)
+		}
+
+		x0, y0, x1, y1 := r.Box[0], r.Box[1], r.Box[2], r.Box[3]
+		if r.Page < 1 || x0 < 0 || y0 < 0 || x1 > 1 || y1 > 1 || x0 >= x1 || y0 >= y1 {
+			return fmt.Errorf("field %s: box must be [x0, y0, x1, y1] within 0-1 on a page from 1", r.Name)
+		}
+	}
+	return nil
+}
+
+// setResultField sets the field of target whose JSON name is name to value,
+// as decoding {name: value} would; a YYYY-MM-DD date also sets a time field.
+func setResultField(target interface{}, name string, value interface{}) error {
+	decode := func(v interface{}) error {
+		raw, err := json.Marshal(map[string]interface{}{name: v})
+		if err != nil {
+			return err
+		}
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		return dec.Decode(target)
+	}
+	err := decode(value)
+	if iso, ok := value.(string); ok && err != nil {
+		if t, perr := time.Parse("2006-01-02", iso); perr == nil {
+			if decode(t) == nil {
+				return nil
+			}
+		}
+	}
+	return err
+}
+
+func hasKeywords(text string, keywords []string) bool {
+	for _, k := range keywords {
+		if !strings.Contains(text, strings.ToLower(k)) {
+			return false
+		}
+	}
+	return true
+}
+
+func layoutFingerprint(page image.Image) dto.LayoutFingerprint {
+	b := page.Bounds()
+	fp := dto.LayoutFingerprint{ImageHash: fmt.Sprintf("%016x", imagehash.DHash(page))}
+	if b.Dy() > 0 {
+		fp.AspectRatio = float64(b.Dx()) / float64(b.Dy())
+	}
+	return fp
+}
+
+// matchLayout returns the template whose fingerprint is nearest fp, within
+// layoutMatchDistance and of about the same aspect ratio; the earliest
+// registered on a tie.
+func matchLayout(templates []dto.LayoutTemplate, fp dto.LayoutFingerprint) (dto.LayoutTemplate, bool) {
+	hash, err := strconv.ParseUint(fp.ImageHash, 16, 64)
+	if err != nil {
+		return dto.LayoutTemplate{}, false
+	}
+	var best dto.LayoutTemplate
+	bestDistance := layoutMatchDistance + 1
+	for _, tpl := range templates {
+		h, err := strconv.ParseUint(tpl.Fingerprint.ImageHash, 16, 64)
+		if err != nil || tpl.Fingerprint.AspectRatio <= 0 {
+			continue
+		}
+		if diff := fp.AspectRatio/tpl.Fingerprint.AspectRatio - 1; diff > layoutAspectTolerance || diff < -layoutAspectTolerance {
+			continue
+		}
+		if d := imagehash.Distance(hash, h); d < bestDistance {
+			best, bestDistance = tpl, d
+		}
+	}
+	return best, bestDistance <= layoutMatchDistance
+}
+
+// page returns page n (1-based) of doc upright: an image the pipeline kept,
+// else the PDF page rendered again and turned as the orient step turned it,
+// else the decoded image upload.
+func (s *LayoutTemplateService) page(doc *pipeline.Doc, n int) (image.Image, error) {
+	if n <= len(doc.Images) {
+		return doc.Images[n-1], nil
+	}
+	if doc.IsPDF() {
+		img, err := s.pdf.RasterizePage(doc.Ctx, doc.Inputs[0], doc.Password, n)
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range doc.Quality.Rotations {
+			if r.Page == n && r.Source == dto.RotationOSD {
+				img = imageprep.Rotate(img, r.Degrees)
+			}
+		}
+		return img, nil
+	}
+	images, err := pageImages(doc)
+	if err != nil {
+		return nil, err
+	}
+	if n > len(images) {
+		return nil, fmt.Errorf("document has %d pages", len(images))
+	}
+	return images[n-1], nil
+}
+
+// readRegion OCRs the region r of page, enlarged when it is small, as one
+// line of text.
+func (s *LayoutTemplateService) readRegion(ctx context.Context, page image.Image, r dto.TemplateRegion, lang string) (string, error) {
+	b := page.Bounds()
+	rect := image.Rect(
+		b.Min.X+int(r.Box[0]*float64(b.Dx())),
+		b.Min.Y+int(r.Box[1]*float64(b.Dy())),
+		b.Min.X+int(r.Box[2]*float64(b.Dx())),
+		b.Min.Y+int(r.Box[3]*float64(b.Dy())),
+	).Intersect(b)
+	if rect.Empty() {
+		return "", nil
+	}
+	var crop image.Image = image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(crop.(draw.Image), crop.Bounds(), page, rect.Min, draw.Src)
+	if rect.Dy() < regionMinHeight {
+		crop = imageprep.Upscale(crop, min(4, (regionMinHeight+rect.Dy()-1)/rect.Dy()))
+	}
+
+	data, err := encodePNG(crop)
+	if err != nil {
+		return "", err
+	}
+	text, err := s.ocr(ctx, data, lang)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(strings.Fields(text), " "), nil
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/store"
+)
+
+// statementPage is a page with a dark letterhead band and table rules.
+func statementPage(t *testing.T, shade uint8) []byte {
+	img := image.NewGray(image.Rect(0, 0, 600, 850))
+	for y := 0; y < 850; y++ {
+		for x := 0; x < 600; x++ {
+			c := uint8(255)
+			switch {
+			case y < 120 && x < 300:
+				c = shade
+			case y > 300 && y%80 < 4, x > 400 && x < 404:
+				c = 40
+			}
+			img.SetGray(x, y, color.Gray{Y: c})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func TestLayoutTemplateRegisterAndApply(t *testing.T) {
+	var reads []string
+	s := &LayoutTemplateService{
+		store: store.NewMemoryLayoutTemplateStore(),
+		ocr: func(_ context.Context, _ []byte, _ string) (string, error) {
+			text := reads[0]
+			reads = reads[1:]
+			return text, nil
+		},
+		now: func() time.Time { return time.Date(2025, 5, 1, 10, 0, 0, 0, time.UTC) },
+	}
+	req := dto.LayoutTemplateRequest{
+		Name: "sahakari_statement", DocType: string(dto.DocTypeBankStatement), Keywords: []string{" Sahakari Bank "},
+		Fields: []dto.TemplateRegion{
+			{Name: "account_number", Box: [4]float64{0.55, 0.10, 0.95, 0.15}},
+			{Name: "period_from", Type: dto.FieldTypeDate, Box: [4]float64{0.55, 0.16, 0.75, 0.20}},
+		},
+	}
+	sample := statementPage(t, 30)
+
+	reads = []string{"0012 3456 7890", "01/04/2024"}
+	tpl, err := s.Register(context.Background(), sample, "sample.png", "", req, "ops")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Sahakari Bank"}, tpl.Keywords)
+	assert.Equal(t, "0012 3456 7890", tpl.Fields[0].SampleValue)
+	assert.Equal(t, 1, tpl.Fields[1].Page)
+	assert.Len(t, tpl.Fingerprint.ImageHash, 16)
+	assert.InDelta(t, 600.0/850, tpl.Fingerprint.AspectRatio, 1e-9)
+
+	// another statement in the layout
+	reads = []string{"001234567890", "01-04-2024"}
+	doc := &pipeline.Doc{Ctx: context.Background(), DocType: req.DocType, Text: "THE SAHAKARI BANK LTD\nStatement", Inputs: [][]byte{statementPage(t, 50)}}
+	data := dto.BankStatementData{AccountNumber: "0O1234S67890", AccountHolderName: "RAVI KUMAR"}
+	assert.Equal(t, "sahakari_statement", s.Apply(doc, &data))
+	assert.Equal(t, "001234567890", data.AccountNumber)
+	assert.Equal(t, "RAVI KUMAR", data.AccountHolderName, "fields without a region keep their parsed value")
+	require.NotNil(t, data.PeriodFrom)
+	assert.Equal(t, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC), *data.PeriodFrom)
+
+	doc.Text = "STATE BANK OF INDIA"
+	assert.Empty(t, s.Apply(doc, &data), "keywords missing")
+	doc.Text = "Sahakari Bank"
+	doc.Images = []image.Image{image.NewGray(image.Rect(0, 0, 850, 600))}
+	assert.Empty(t, s.Apply(doc, &data), "landscape page")
+	assert.Empty(t, reads)
+
+	templates, err := s.List("")
+	require.NoError(t, err)
+	assert.Len(t, templates, 1)
+	require.NoError(t, s.Delete(tpl.ID))
+	assert.ErrorIs(t, s.Delete(tpl.ID), store.ErrNotFound)
+}
+
+func TestLayoutTemplateRejectsInvalidRequests(t *testing.T) {
+	s := &LayoutTemplateService{store: store.NewMemoryLayoutTemplateStore()}
+	box := [4]float64{0.1, 0.1, 0.5, 0.2}
+	for _, req := range []dto.LayoutTemplateRequest{
+		{DocType: "bank_statement", Fields: []dto.TemplateRegion{{Name: "account_number", Box: box}}},
+		{Name: "t", DocType: "aadhaar", Fields: []dto.TemplateRegion{{Name: "account_number", Box: box}}},
+		{Name: "t", DocType: "bank_statement"},
+		{Name: "t", DocType: "bank_statement", Fields: []dto.TemplateRegion{{Name: "account_no", Box: box}}},
+		{Name: "t", DocType: "bank_statement", Fields: []dto.TemplateRegion{{Name: "account_number", Type: dto.FieldTypeAmount, Box: box}}},
+		{Name: "t", DocType: "salary_slip", Fields: []dto.TemplateRegion{{Name: "net_salary", Type: dto.FieldTypeAmount, Box: [4]float64{0.5, 0.1, 0.4, 0.2}}}},
+		{Name: "t", DocType: "salary_slip", Fields: []dto.TemplateRegion{{Name: "net_salary", Type: dto.FieldTypeRegex, Box: box}}},
+	} {
+		_, err := s.Register(context.Background(), nil, "", "", req, "")
+		assert.ErrorIs(t, err, ErrInvalidTemplate, "%+v", req)
+	}
+}
@@ -1,38 +1,81 @@
 package service
 
 import (
+	"context"
+	"fmt"
+	"os"
+
 	"github.com/Aashish23092/ocr-income-verification/client"
 	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
 	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
 )
 
 type PANService struct {
-	Paddle *client.PaddleClient
+	Paddle    client.PaddleEngine
+	pipelines *pipeline.Orchestrator
 }
 
-func NewPANService(paddle *client.PaddleClient) *PANService {
-	return &PANService{
+func NewPANService(paddle client.PaddleEngine, pipelines *pipeline.Orchestrator) (*PANService, error) {
+	s := &PANService{
 		Paddle: paddle,
 	}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"parse": noArg(s.parseStep),
+	}, "pan")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
 }
 
-func (s *PANService) ExtractPANData(imagePath string) (*dto.PANResponse, error) {
+func (s *PANService) ExtractPANData(ctx context.Context, imagePath string) (*dto.PANResponse, error) {
+	data, err := os.ReadFile(imagePath)
+	if err != nil {
+		return nil, err
+	}
+	return s.ExtractPANFromBytes(ctx, data, imagePath)
+}
 
-	// -----------------------------
-	// USE YOUR ACTUAL PADDLE CLIENT
-	// -----------------------------
-	rawText, err := s.Paddle.ExtractTextFromFile(imagePath)
+// ExtractPANFromBytes runs the PAN pipeline on an in-memory image.
+func (s *PANService) ExtractPANFromBytes(ctx context.Context, data []byte, filename string) (*dto.PANResponse, error) {
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "pan", Filename: filename, Inputs: [][]byte{data}}
+	result, err := pipeline.Cached(s.pipelines, doc, func() (*dto.PANResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.PANResponse)
+		if !ok {
+			return nil, fmt.Errorf("pan pipeline produced no result")
+		}
+		return result, nil
+	})
 	if err != nil {
 		return nil, err
 	}
+	result.Photo = portraitPhoto(ctx, face.DocPAN, doc.Inputs, nil, "")
+	return result, nil
+}
 
-	parsed := utils.ParsePANText(rawText)
+func (s *PANService) parseStep(doc *pipeline.Doc) error {
+	parsed := utils.ParsePANText(doc.Text)
+	validation := utils.ValidatePAN(parsed.PAN, parsed.Name)
 
-	return &dto.PANResponse{
+	result := &dto.PANResponse{
 		PAN:        parsed.PAN,
 		Name:       parsed.Name,
 		FatherName: parsed.FatherName,
 		DOB:        parsed.DOB,
 		RawText:    parsed.RawText,
-	}, nil
+
+		Valid:            validation.Valid,
+		HolderType:       validation.HolderType,
+		ValidationIssues: validation.Issues,
+	}
+	result.DOBISO, _ = utils.NormalizeDOB(result.DOB)
+	doc.Result = result
+	return nil
 }
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/png"
+	"log/slog"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+	"github.com/Aashish23092/ocr-income-verification/utils/mrz"
+)
+
+// mrzBandHeight is the bottom share of the data page searched for the MRZ
+// when full-page OCR did not pick it up.
+const mrzBandHeight = 0.3
+
+type PassportService struct {
+	tesseract client.TesseractEngine
+	pipelines *pipeline.Orchestrator
+}
+
+func NewPassportService(tesseract client.TesseractEngine, pipelines *pipeline.Orchestrator) (*PassportService, error) {
+	s := &PassportService{
+		tesseract: tesseract,
+	}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"mrz":   noArg(s.mrzStep),
+		"parse": noArg(s.parseStep),
+	}, "passport")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func (s *PassportService) ExtractPassport(ctx context.Context, imageBytes []byte) (*dto.PassportResponse, error) {
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "passport", Inputs: [][]byte{imageBytes}}
+	result, err := pipeline.Cached(s.pipelines, doc, func() (*dto.PassportResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.PassportResponse)
+		if !ok {
+			return nil, fmt.Errorf("passport pipeline produced no result")
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.Photo = portraitPhoto(ctx, face.DocPassport, doc.Inputs, nil, "")
+	return result, nil
+}
+
+// mrzStep decodes the MRZ from the page text, re-reading just the bottom band
+// of the page with Tesseract if the full-page OCR mangled it.
+func (s *PassportService) mrzStep(doc *pipeline.Doc) error {
+	line1, line2, ok := mrz.Find(doc.Text)
+	if !ok {
+		line1, line2, ok = s.readMRZBand(doc)
+	}
+	if !ok {
+		return nil
+	}
+
+	m, err := mrz.Parse(line1, line2)
+	if err != nil {
+		slog.InfoContext(doc.Ctx, "Passport MRZ could not be decoded", "error", err)
+		return nil
+	}
+
+	doc.Result = &dto.PassportResponse{
+		PassportNumber:  m.PassportNumber,
+		Surname:         m.Surname,
+		GivenNames:      m.GivenNames,
+		DOB:             m.DateOfBirth,
+		Sex:             m.Sex,
+		Nationality:     m.Nationality,
+		IssuingCountry:  m.IssuingCountry,
+		ExpiryDate:      m.ExpiryDate,
+		Source:          "mrz",
+		MRZValid:        m.Valid(),
+		MRZFailedChecks: m.FailedChecks,
+	}
+	return nil
+}
+
+func (s *PassportService) readMRZBand(doc *pipeline.Doc) (string, string, bool) {
+	if s.tesseract == nil {
+		return "", "", false
+	}
+	images, err := pageImages(doc)
+	if err != nil {
+		return "", "", false
+	}
+
+	for _, img := range images {
+		b := img.Bounds()
+		band := image.Rect(b.Min.X, b.Max.Y-int(float64(b.Dy())*mrzBandHeight), b.Max.X, b.Max.Y)
+		crop := image.NewRGBA(image.Rect(0, 0, band.Dx(), band.Dy()))
+		draw.Draw(crop, crop.Bounds(), img, band.Min, draw.Src)
+
+		buf := new(bytes.Buffer)
+		if err := png.Encode(buf, crop); err != nil {
+			continue
+		}
+		text, err := s.tesseract.ExtractTextFromBytes(doc.Ctx, buf.Bytes())
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Passport MRZ band OCR failed", "error", err)
+			continue
+		}
+		if line1, line2, ok := mrz.Find(text); ok {
+			return line1, line2, true
+		}
+	}
+	return "", "", false
+}
+
+// parseStep falls back to the printed fields, filling anything the MRZ did not provide.
+func (s *PassportService) parseStep(doc *pipeline.Doc) error {
+	result, ok := doc.Result.(*dto.PassportResponse)
+	if !ok {
+		result = &dto.PassportResponse{Source: "ocr"}
+	}
+	result.RawText = doc.Text
+
+	parsed := utils.ParsePassportText(doc.Text)
+	fill := func(dst *string, v string) {
+		if *dst == "" {
+			*dst = v
+		}
+	}
+	fill(&result.PassportNumber, parsed.PassportNumber)
+	fill(&result.Surname, parsed.Surname)
+	fill(&result.GivenNames, parsed.GivenNames)
+	fill(&result.DOB, parsed.DOB)
+	fill(&result.Sex, parsed.Sex)
+	fill(&result.Nationality, parsed.Nationality)
+	fill(&result.ExpiryDate, parsed.ExpiryDate)
+	result.DOBISO, _ = utils.NormalizeDOB(result.DOB)
+
+	doc.Result = result
+	return nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// PDFLimits bound the work a PDF can cause, against PDF bombs: thousands of
+// pages, huge page boxes, or content streams that take pdftoppm minutes to
+// draw. Zero means no limit.
+type PDFLimits struct {
+	MaxPages int
+	// MaxPagePixels bounds a rendered page (width × height at the render
+	// resolution), and so the memory and disk it takes.
+	MaxPagePixels int64
+	// PageTimeout bounds the rendering of one page, RenderBudget that of all
+	// pages of a document together.
+	PageTimeout  time.Duration
+	RenderBudget time.Duration
+	// MaxPageBytes bounds the PNG pdftoppm writes for a page.
+	MaxPageBytes int64
+}
+
+// DefaultPDFLimits fit any statement or payslip: 500 pages, an A3 page at
+// 300 DPI (17 MP) with room to spare, 60 s a page and 10 minutes a document.
+var DefaultPDFLimits = PDFLimits{
+	MaxPages:      500,
+	MaxPagePixels: 40_000_000,
+	PageTimeout:   time.Minute,
+	RenderBudget:  10 * time.Minute,
+	MaxPageBytes:  100 << 20,
+}
+
+// ErrPDFLimit matches every PDFLimitError.
+var ErrPDFLimit = errors.New("PDF exceeds a processing limit")
+
+// PDFLimitError is a PDF refused for exceeding a PDFLimits bound; Code is one
+// of the dto.CodePDF* error codes.
+type PDFLimitError struct {
+	Code   dto.ErrorCode
+	Detail string
+}
+
+func (e *PDFLimitError) Error() string {
+	return fmt.Sprintf("%s: %s", ErrPDFLimit, e.Detail)
+}
+
+func (e *PDFLimitError) Is(target error) bool { return target == ErrPDFLimit }
+
+func pdfLimitError(code dto.ErrorCode, format string, args ...any) error {
+	return &PDFLimitError{Code: code, Detail: fmt.Sprintf(format, args...)}
+}
@@ -2,30 +2,59 @@ package service
 
 import (
 	"bytes"
+	"context"
+	"crypto/x509"
+	"errors"
 	"fmt"
 	"image"
-
+	"iter"
+	"log/slog"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/integrity"
+	"github.com/Aashish23092/ocr-income-verification/utils/pdftext"
 	"github.com/ledongthuc/pdf"
 	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
 	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/model"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu/types"
 )
 
 // PDFProcessor defines the interface for processing PDF files.
 type PDFProcessor interface {
+	Decrypt(pdfData []byte, password string) ([]byte, error)
 	ExtractText(pdfData []byte, password string) (string, error)
-	ExtractImages(pdfData []byte, password string) ([]image.Image, error)
+	ExtractPageTexts(pdfData []byte, password string) ([]string, error)
+	ExtractImages(ctx context.Context, pdfData []byte, password string, opts RenderOptions) iter.Seq2[image.Image, error]
+	RasterizePage(ctx context.Context, pdfData []byte, password string, page int) (image.Image, error)
+	ExtractMetadata(pdfData []byte, password string) (*dto.PDFMetadata, error)
+	InspectMetadata(pdfData []byte, password string) (*dto.DocumentProvenance, error)
+	VerifySignatures(pdfData []byte, password string) ([]dto.DocumentSignature, error)
+}
+
+type pdfProcessor struct {
+	limits    PDFLimits
+	rendering PDFRendering
 }
 
-type pdfProcessor struct{}
+// NewPDFProcessor creates a new PDFProcessor instance rendering pages as
+// rendering says. PDFs beyond limits fail with a *PDFLimitError.
+func NewPDFProcessor(limits PDFLimits, rendering PDFRendering) PDFProcessor {
+	if rendering.DPI <= 0 {
+		rendering.DPI = defaultRenderDPI
+	}
+	return &pdfProcessor{limits: limits, rendering: rendering}
+}
 
-// NewPDFProcessor creates a new PDFProcessor instance.
-func NewPDFProcessor() PDFProcessor {
-	return &pdfProcessor{}
+// Decrypt returns the PDF with encryption removed, or the input unchanged when
+// no password is given or the file is not encrypted.
+func (p *pdfProcessor) Decrypt(pdfData []byte, password string) ([]byte, error) {
+	return p.decryptPDFBytes(pdfData, password)
 }
 
 // decryptPDFBytes attempts to decrypt a PDF using the provided password.
@@ -53,7 +82,7 @@ func (p *pdfProcessor) decryptPDFBytes(pdfData []byte, password string) ([]byte,
 		if strings.Contains(err.Error(), "not encrypted") {
 			return pdfData, nil
 		}
-		return nil, fmt.Errorf("failed to decrypt PDF: %w", err)
+		return nil, fmt.Errorf("%w: %w", ErrPDFEncrypted, err)
 	}
 
 	return out.Bytes(), nil
@@ -61,101 +90,397 @@ func (p *pdfProcessor) decryptPDFBytes(pdfData []byte, password string) ([]byte,
 
 // ExtractText extracts text from a PDF. It handles encrypted PDFs if a password is provided.
 func (p *pdfProcessor) ExtractText(pdfData []byte, password string) (string, error) {
+	pages, err := p.ExtractPageTexts(pdfData, password)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(pages, ""), nil
+}
+
+// ExtractPageTexts returns the text layer of each page, in page order, laid
+// out as on the page (see pdftext.Layout). Pages without a text layer yield
+// an empty string.
+func (p *pdfProcessor) ExtractPageTexts(pdfData []byte, password string) ([]string, error) {
 	decryptedData, err := p.decryptPDFBytes(pdfData, password)
 	if err != nil {
-		return "", fmt.Errorf("could not decrypt PDF for text extraction: %w", err)
+		return nil, fmt.Errorf("could not decrypt PDF for text extraction: %w", err)
 	}
 
 	r, err := pdf.NewReader(bytes.NewReader(decryptedData), int64(len(decryptedData)))
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
-	var textBuilder strings.Builder
 	totalPage := r.NumPage()
+	if err := p.checkPageCount(totalPage); err != nil {
+		return nil, err
+	}
+	pages := make([]string, 0, totalPage)
 
 	for pageIndex := 1; pageIndex <= totalPage; pageIndex++ {
 		page := r.Page(pageIndex)
 		if page.V.IsNull() {
+			pages = append(pages, "")
 			continue
 		}
 
-		rows, err := page.GetTextByRow()
+		text, err := pdftext.PageText(page)
 		if err != nil {
-			// Log the error but continue processing other pages.
-			fmt.Printf("Error getting text from page %d: %v\n", pageIndex, err)
-			continue
+			// the other pages are still read
+			slog.Warn("PDF page text not readable", "page", pageIndex, "error", err)
+		}
+		pages = append(pages, text)
+	}
+	return pages, nil
+}
+
+// ExtractImages renders PDF pages to images (pdftoppm or MuPDF, at the
+// configured DPI) lazily, one page per step of the returned sequence, so a
+// long scanned statement never has every page bitmap in memory at once. A PDF
+// that cannot be prepared yields its error as the only element; a page that
+// fails to render yields a *PageRenderError and iteration moves on to the
+// next page. Once ctx is done the running render is abandoned and the
+// sequence ends with ctx's error. The limits apply: a PDF
+// with too many pages yields only its *PDFLimitError, a page too large or too
+// slow to render yields one, and so does the page at which the document's
+// render budget runs out, ending the sequence. opts narrow the pages rendered
+// and may change their resolution.
+func (p *pdfProcessor) ExtractImages(ctx context.Context, pdfData []byte, password string, opts RenderOptions) iter.Seq2[image.Image, error] {
+	return func(yield func(image.Image, error) bool) {
+		decryptedData, err := p.decryptPDFBytes(pdfData, password)
+		if err != nil {
+			yield(nil, fmt.Errorf("could not decrypt PDF for image extraction: %w", err))
+			return
+		}
+
+		conf := model.NewDefaultConfiguration()
+		conf.ValidationMode = model.ValidationRelaxed
+		pageCount, err := api.PageCount(bytes.NewReader(decryptedData), conf)
+		if err != nil {
+			yield(nil, fmt.Errorf("failed to count PDF pages: %w", err))
+			return
+		}
+		if err := p.checkPageCount(pageCount); err != nil {
+			yield(nil, err)
+			return
+		}
+		// best effort: a PDF pdfcpu cannot size is still bounded by the
+		// check of the rendered image
+		dims, _ := api.PageDims(bytes.NewReader(decryptedData), conf)
+		var deadline time.Time
+		if p.limits.RenderBudget > 0 {
+			deadline = time.Now().Add(p.limits.RenderBudget)
 		}
 
-		for _, row := range rows {
-			for _, word := range row.Content {
-				textBuilder.WriteString(word.S)
+		renderer, err := p.openRenderer(decryptedData)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer renderer.Close() // once the caller stops reading pages
+
+		dpi := p.rendering.DPI
+		if opts.DPI > 0 {
+			dpi = opts.DPI
+		}
+		rendered := 0
+		for page := 1; page <= pageCount; page++ {
+			if !opts.Pages.Contains(page) {
+				continue
+			}
+			if opts.MaxPages > 0 && rendered == opts.MaxPages {
+				return
+			}
+			rendered++
+			if err := ctx.Err(); err != nil {
+				yield(nil, err)
+				return
+			}
+			if !deadline.IsZero() && time.Now().After(deadline) {
+				yield(nil, pdfLimitError(dto.CodePDFRenderTimeout, "rendering stopped at page %d of %d after %s", page, pageCount, p.limits.RenderBudget))
+				return
+			}
+			img, err := p.renderPage(ctx, renderer, page, dpi, pageDim(dims, page), deadline)
+			if !yield(img, err) {
+				return
 			}
-			textBuilder.WriteString("\n")
 		}
 	}
-	return textBuilder.String(), nil
 }
 
-// ExtractImages converts PDF pages to images. It's used for scanned PDFs.
-// It uses Poppler's pdftoppm tool.
-func (p *pdfProcessor) ExtractImages(pdfData []byte, password string) ([]image.Image, error) {
+// CollectImages drains a page sequence into memory, skipping pages that failed
+// to render; a PDF over its limits fails with the *PDFLimitError. For callers
+// that need random access to a short document's pages.
+func CollectImages(pages iter.Seq2[image.Image, error]) ([]image.Image, error) {
+	var images []image.Image
+	var lastErr error
+	for img, err := range pages {
+		if errors.Is(err, ErrPDFLimit) {
+			return nil, err
+		}
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		images = append(images, img)
+	}
+	if len(images) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("no images could be extracted from the PDF")
+	}
+	return images, nil
+}
+
+// RasterizePage renders a single 1-based page to an image at 300 DPI. Only what
+// is visibly drawn ends up in the image; invisible text layers do not.
+func (p *pdfProcessor) RasterizePage(ctx context.Context, pdfData []byte, password string, page int) (image.Image, error) {
 	decryptedData, err := p.decryptPDFBytes(pdfData, password)
 	if err != nil {
-		return nil, fmt.Errorf("could not decrypt PDF for image extraction: %w", err)
+		return nil, fmt.Errorf("could not decrypt PDF for rasterization: %w", err)
 	}
 
-	// Create a temporary directory for extraction
-	tempDir, err := os.MkdirTemp("", "pdf_images_")
+	renderer, err := p.openRenderer(decryptedData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+		return nil, err
+	}
+	defer renderer.Close()
+
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	dims, _ := api.PageDims(bytes.NewReader(decryptedData), conf)
+	return p.renderPage(ctx, renderer, page, 300, pageDim(dims, page), time.Time{})
+}
+
+func (p *pdfProcessor) checkPageCount(pages int) error {
+	if p.limits.MaxPages > 0 && pages > p.limits.MaxPages {
+		return pdfLimitError(dto.CodePDFTooManyPages, "%d pages, at most %d are processed", pages, p.limits.MaxPages)
+	}
+	return nil
+}
+
+// pageDim returns the size of a 1-based page, zero when unknown.
+func pageDim(dims []types.Dim, page int) types.Dim {
+	if page < 1 || page > len(dims) {
+		return types.Dim{}
+	}
+	return dims[page-1]
+}
+
+// writeTempPDF writes the PDF into a fresh temp directory for pdftoppm.
+// The caller removes the directory.
+func writeTempPDF(pdfData []byte, prefix string) (string, string, error) {
+	tempDir, err := os.MkdirTemp("", prefix)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create temp dir: %w", err)
 	}
-	defer os.RemoveAll(tempDir) // Cleanup
 
-	// Create a temporary file for the PDF
 	tempPDFPath := filepath.Join(tempDir, "doc.pdf")
-	if err := os.WriteFile(tempPDFPath, decryptedData, 0644); err != nil {
-		return nil, fmt.Errorf("failed to write temp PDF: %w", err)
+	if err := os.WriteFile(tempPDFPath, pdfData, 0644); err != nil {
+		os.RemoveAll(tempDir)
+		return "", "", fmt.Errorf("failed to write temp PDF: %w", err)
 	}
+	return tempDir, tempPDFPath, nil
+}
 
-	// Use pdftoppm to convert PDF to images
-	// pdftoppm -png input.pdf output_prefix
-	cmd := exec.Command("pdftoppm", "-png", tempPDFPath, filepath.Join(tempDir, "page"))
-	output, err := cmd.CombinedOutput()
+// renderPage renders one page at dpi. A page whose size (dim, in points;
+// zero when unknown) would render beyond the pixel limit is refused before
+// the backend runs, and so is a rendered image beyond it. The render is given
+// up when ctx is done, after the page timeout, or at deadline (zero for
+// none); other failures are a *PageRenderError.
+func (p *pdfProcessor) renderPage(ctx context.Context, r pageRenderer, page, dpi int, dim types.Dim, deadline time.Time) (image.Image, error) {
+	limit := p.limits.MaxPagePixels
+	if limit > 0 && dim.Width > 0 {
+		w, h := int64(dim.Width/72*float64(dpi)), int64(dim.Height/72*float64(dpi))
+		if w*h > limit {
+			return nil, pdfLimitError(dto.CodePDFPageTooLarge, "page %d would render at %dx%d pixels, more than %d", page, w, h, limit)
+		}
+	}
+
+	renderCtx := ctx
+	if p.limits.PageTimeout > 0 {
+		var cancel context.CancelFunc
+		renderCtx, cancel = context.WithTimeout(renderCtx, p.limits.PageTimeout)
+		defer cancel()
+	}
+	if !deadline.IsZero() {
+		var cancel context.CancelFunc
+		renderCtx, cancel = context.WithDeadline(renderCtx, deadline)
+		defer cancel()
+	}
+
+	img, err := r.render(renderCtx, page, dpi)
+	switch {
+	case err == nil:
+	case ctx.Err() != nil:
+		return nil, ctx.Err()
+	case renderCtx.Err() != nil:
+		return nil, pdfLimitError(dto.CodePDFRenderTimeout, "page %d took too long to render", page)
+	case errors.Is(err, ErrPDFLimit):
+		return nil, err
+	default:
+		return nil, &PageRenderError{Page: page, Backend: p.rendering.backendName(), Err: err}
+	}
+	if b := img.Bounds(); limit > 0 && int64(b.Dx())*int64(b.Dy()) > limit {
+		return nil, pdfLimitError(dto.CodePDFPageTooLarge, "page %d rendered at %dx%d pixels, more than %d", page, b.Dx(), b.Dy(), limit)
+	}
+	return img, nil
+}
+
+// ExtractMetadata reads the document information dictionary (producer, creation date, etc.).
+func (p *pdfProcessor) ExtractMetadata(pdfData []byte, password string) (*dto.PDFMetadata, error) {
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	if password != "" {
+		conf.UserPW = password
+		conf.OwnerPW = password
+	}
+
+	ctx, err := api.ReadAndValidate(bytes.NewReader(pdfData), conf)
 	if err != nil {
-		return nil, fmt.Errorf("pdftoppm failed: %v\nOutput: %s", err, string(output))
+		return nil, fmt.Errorf("failed to read PDF metadata: %w", err)
 	}
 
-	// Read extracted images
-	var images []image.Image
-	files, err := os.ReadDir(tempDir)
+	meta := &dto.PDFMetadata{
+		Title:     ctx.XRefTable.Title,
+		Author:    ctx.XRefTable.Author,
+		Creator:   ctx.XRefTable.Creator,
+		Producer:  ctx.XRefTable.Producer,
+		PageCount: ctx.XRefTable.PageCount,
+	}
+	meta.CreationDate = parsePDFDate(ctx.XRefTable.CreationDate)
+	meta.ModDate = parsePDFDate(ctx.XRefTable.ModDate)
+
+	// Optimizing indexes the fonts of every page; without it fonts stay unlisted
+	if err := api.OptimizeContext(ctx); err == nil {
+		meta.PageFonts = pageFonts(ctx)
+	}
+
+	return meta, nil
+}
+
+// InspectMetadata reads the metadata and judges what it says about the file's
+// history: the editing software that wrote it, modification after creation and
+// incremental saves (see integrity.InspectMetadata).
+func (p *pdfProcessor) InspectMetadata(pdfData []byte, password string) (*dto.DocumentProvenance, error) {
+	meta, err := p.ExtractMetadata(pdfData, password)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read temp dir: %w", err)
+		return nil, err
 	}
+	return integrity.InspectMetadata(meta, pdfData), nil
+}
 
-	for _, file := range files {
-		// We only care about the generated PNG files
-		if !strings.HasSuffix(file.Name(), ".png") {
-			continue
-		}
+// VerifySignatures validates the digital signatures of a PDF, including the
+// signer's certificate chain against the system roots and any certificates
+// loaded with LoadTrustedCertificates. Unsigned PDFs return none.
+func (p *pdfProcessor) VerifySignatures(pdfData []byte, password string) ([]dto.DocumentSignature, error) {
+	conf := model.NewDefaultConfiguration()
+	conf.ValidationMode = model.ValidationRelaxed
+	conf.Cmd = model.VALIDATESIGNATURE
+	if password != "" {
+		conf.UserPW = password
+		conf.OwnerPW = password
+	}
 
-		imgPath := filepath.Join(tempDir, file.Name())
-		imgFile, err := os.Open(imgPath)
-		if err != nil {
-			continue // Or log the error
-		}
+	rs := bytes.NewReader(pdfData)
+	ctx, err := api.ReadValidateAndOptimize(rs, conf)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read PDF: %w", err)
+	}
+	if len(ctx.Signatures) == 0 && !ctx.SignatureExist {
+		return nil, nil
+	}
 
-		img, _, err := image.Decode(imgFile)
-		imgFile.Close() // Ensure the file is closed
-		if err != nil {
-			continue // Or log the error
+	results, err := pdfcpu.ValidateSignatures(rs, ctx, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to validate signatures: %w", err)
+	}
+
+	var sigs []dto.DocumentSignature
+	for _, r := range results {
+		if !r.Signed {
+			continue // empty signature field
 		}
-		images = append(images, img)
+		sig := dto.DocumentSignature{
+			SignerName:  r.Details.SignerName,
+			Validity:    dto.SignatureUnknown,
+			Certified:   r.Certified(),
+			DocModified: r.DocModified == model.True,
+		}
+		switch r.Status {
+		case model.SignatureStatusValid:
+			sig.Validity = dto.SignatureValid
+		case model.SignatureStatusInvalid:
+			sig.Validity = dto.SignatureInvalid
+		}
+		if sig.Validity != dto.SignatureValid {
+			sig.Reason = r.Reason.String()
+		}
+		if !r.Details.SigningTime.IsZero() {
+			t := r.Details.SigningTime
+			sig.SigningTime = &t
+		}
+		if len(r.Details.Signers) > 0 && r.Details.Signers[0].Certificate != nil {
+			cert := r.Details.Signers[0].Certificate
+			sig.Issuer = cert.Issuer
+			if sig.SignerName == "" {
+				sig.SignerName = cert.Subject
+			}
+		}
+		if sig.SignerName == "" {
+			sig.SignerName = r.Details.SignerIdentity
+		}
+		sigs = append(sigs, sig)
 	}
+	return sigs, nil
+}
 
-	if len(images) == 0 {
-		return nil, fmt.Errorf("no images could be extracted from the PDF")
+// LoadTrustedCertificates adds the CA certificates (PEM, DER or P7C) in dir to
+// the roots signatures are validated against, e.g. the CCA India root for
+// e-signed statements. It returns how many were loaded.
+func LoadTrustedCertificates(dir string) (int, error) {
+	pool, err := x509.SystemCertPool()
+	if err != nil {
+		pool = x509.NewCertPool()
+	}
+	n, err := pdfcpu.LoadCertificatesToCertPool(dir, pool)
+	if err != nil {
+		return 0, err
 	}
+	model.UserCertPool = pool
+	return n, nil
+}
 
-	return images, nil
+// pageFonts lists the font names of each page of an optimized context.
+func pageFonts(ctx *model.Context) [][]string {
+	pages := make([][]string, len(ctx.Optimize.PageFonts))
+	for i, objNrs := range ctx.Optimize.PageFonts {
+		pages[i] = []string{}
+		for objNr := range objNrs {
+			fo, ok := ctx.Optimize.FontObjects[objNr]
+			if !ok {
+				continue
+			}
+			name := fo.FontName
+			if fo.Prefix != "" {
+				name = fo.Prefix + "+" + name
+			}
+			pages[i] = append(pages[i], name)
+		}
+		sort.Strings(pages[i])
+	}
+	return pages
+}
+
+// parsePDFDate decodes a PDF date string (D:YYYYMMDDHHmmSS...). Returns nil when absent or malformed.
+func parsePDFDate(s string) *time.Time {
+	if strings.TrimSpace(s) == "" {
+		return nil
+	}
+	t, ok := types.DateTime(s, true)
+	if !ok {
+		return nil
+	}
+	return &t
 }
@@ -0,0 +1,110 @@
+package service
+
+import (
+	"context"
+	"image"
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blankPDF returns a PDF of pages w×h points.
+func blankPDF(t *testing.T, pages, w, h int) []byte {
+	t.Helper()
+	page, err := face.JPEG(image.NewGray(image.Rect(0, 0, w, h)))
+	require.NoError(t, err)
+	jpegs := make([][]byte, pages)
+	for i := range jpegs {
+		jpegs[i] = page
+	}
+	pdf, err := imagesToPDF(jpegs)
+	require.NoError(t, err)
+	return pdf
+}
+
+func TestPDFLimits(t *testing.T) {
+	ctx := context.Background()
+	var limit *PDFLimitError
+
+	p := NewPDFProcessor(PDFLimits{MaxPages: 2}, PDFRendering{})
+	for _, err := range p.ExtractImages(ctx, blankPDF(t, 3, 100, 100), "", RenderOptions{}) {
+		require.ErrorAs(t, err, &limit)
+		assert.Equal(t, dto.CodePDFTooManyPages, limit.Code)
+	}
+	_, err := p.ExtractPageTexts(blankPDF(t, 3, 100, 100), "")
+	assert.ErrorIs(t, err, ErrPDFLimit)
+
+	// a 100"×100" page is 225 MP at pdftoppm's 150 DPI: refused unrendered
+	p = NewPDFProcessor(PDFLimits{MaxPagePixels: 40_000_000}, PDFRendering{})
+	_, err = CollectImages(p.ExtractImages(ctx, blankPDF(t, 1, 7200, 7200), "", RenderOptions{}))
+	require.ErrorAs(t, err, &limit)
+	assert.Equal(t, dto.CodePDFPageTooLarge, limit.Code)
+	_, err = p.RasterizePage(ctx, blankPDF(t, 1, 7200, 7200), "", 1)
+	assert.ErrorIs(t, err, ErrPDFLimit)
+}
+
+func TestMuPDFRendering(t *testing.T) {
+	ctx := context.Background()
+	p := NewPDFProcessor(DefaultPDFLimits, PDFRendering{Backend: RendererMuPDF, DPI: 100})
+
+	// 144×72 points at 100 DPI
+	images, err := CollectImages(p.ExtractImages(ctx, blankPDF(t, 2, 144, 72), "", RenderOptions{}))
+	require.NoError(t, err)
+	require.Len(t, images, 2)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), images[0].Bounds())
+
+	_, err = p.RasterizePage(ctx, blankPDF(t, 1, 144, 72), "", 3)
+	var pageErr *PageRenderError
+	require.ErrorAs(t, err, &pageErr)
+	assert.Equal(t, 3, pageErr.Page)
+	assert.Equal(t, RendererMuPDF, pageErr.Backend)
+}
+
+func TestMuPDFRenderWaitsForSlot(t *testing.T) {
+	r, err := openMuPDF(blankPDF(t, 1, 144, 72))
+	require.NoError(t, err)
+	defer r.Close()
+	r.renders = pipeline.NewLimiter(1)
+
+	// a render given up on still holds its slot
+	release, err := r.renders.Acquire(context.Background())
+	require.NoError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = r.render(ctx, 1, 100)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	img, err := r.render(context.Background(), 1, 100)
+	require.NoError(t, err)
+	assert.Equal(t, image.Rect(0, 0, 200, 100), img.Bounds())
+}
+
+func TestRenderOptions(t *testing.T) {
+	ctx := context.Background()
+	p := NewPDFProcessor(DefaultPDFLimits, PDFRendering{Backend: RendererMuPDF, DPI: 100})
+	pdf := blankPDF(t, 5, 144, 72)
+
+	opts, err := parseRenderOptions("pages=2,4-;dpi=50;max=2")
+	require.NoError(t, err)
+	images, err := CollectImages(p.ExtractImages(ctx, pdf, "", opts))
+	require.NoError(t, err)
+	require.Len(t, images, 2, "pages 2 and 4, max stops before 5")
+	assert.Equal(t, image.Rect(0, 0, 100, 50), images[0].Bounds())
+
+	set, err := ParsePageSet("2,4-")
+	require.NoError(t, err)
+	assert.True(t, set.Contains(2))
+	assert.False(t, set.Contains(3))
+	assert.True(t, set.Contains(40))
+
+	for _, bad := range []string{"pages=0", "pages=3-1", "pages=x", "pages=1;zoom=2", "dpi=5", "max=0"} {
+		_, err := parseRenderOptions(bad)
+		assert.Error(t, err, bad)
+	}
+}
@@ -0,0 +1,261 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/gen2brain/go-fitz"
+)
+
+// PDF rendering backends.
+const (
+	RendererPdftoppm = "pdftoppm" // Poppler's pdftoppm, run once per page
+	RendererMuPDF    = "mupdf"    // MuPDF, in process
+)
+
+// PDFRendering selects how ExtractImages renders pages. The zero value is
+// pdftoppm at 150 DPI.
+type PDFRendering struct {
+	Backend string // RendererPdftoppm or RendererMuPDF
+	DPI     int
+}
+
+func (r PDFRendering) backendName() string {
+	if r.Backend == "" {
+		return RendererPdftoppm
+	}
+	return r.Backend
+}
+
+// defaultRenderDPI is pdftoppm's default resolution, enough for OCR of
+// statements and payslips.
+const defaultRenderDPI = 150
+
+// PageRenderError is a page the backend failed to render; the other pages
+// of the document are unaffected.
+type PageRenderError struct {
+	Page    int
+	Backend string
+	Err     error
+}
+
+func (e *PageRenderError) Error() string {
+	return fmt.Sprintf("%s failed on page %d: %v", e.Backend, e.Page, e.Err)
+}
+
+func (e *PageRenderError) Unwrap() error { return e.Err }
+
+// pageRenderer renders the pages of one decrypted PDF. render gives up when
+// ctx is done.
+type pageRenderer interface {
+	render(ctx context.Context, page, dpi int) (image.Image, error)
+	Close() error
+}
+
+// openRenderer prepares pdfData for rendering with the configured backend.
+func (p *pdfProcessor) openRenderer(pdfData []byte) (pageRenderer, error) {
+	if p.rendering.Backend == RendererMuPDF {
+		r, err := openMuPDF(pdfData)
+		if err != nil {
+			return nil, err
+		}
+		return r, nil
+	}
+	r, err := openPdftoppm(pdfData, p.limits)
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// pdftoppmRenderer runs pdftoppm on a temp copy of the PDF. The PNG it
+// writes is checked against the byte and pixel limits before it is decoded,
+// and pdftoppm is killed when ctx is done.
+type pdftoppmRenderer struct {
+	limits  PDFLimits
+	dir     string
+	pdfPath string
+}
+
+func openPdftoppm(pdfData []byte, limits PDFLimits) (*pdftoppmRenderer, error) {
+	dir, pdfPath, err := writeTempPDF(pdfData, "pdf_render_")
+	if err != nil {
+		return nil, err
+	}
+	return &pdftoppmRenderer{limits: limits, dir: dir, pdfPath: pdfPath}, nil
+}
+
+func (r *pdftoppmRenderer) render(ctx context.Context, page, dpi int) (image.Image, error) {
+	// pdftoppm -png -r DPI -f N -l N -singlefile input.pdf output
+	n := strconv.Itoa(page)
+	outPrefix := filepath.Join(r.dir, "page")
+	imgPath := outPrefix + ".png"
+	defer os.Remove(imgPath)
+
+	cmd := exec.CommandContext(ctx, "pdftoppm", "-png", "-r", strconv.Itoa(dpi), "-f", n, "-l", n, "-singlefile", r.pdfPath, outPrefix)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("%v\nOutput: %s", err, string(output))
+	}
+
+	imgFile, err := os.Open(imgPath)
+	if err != nil {
+		return nil, fmt.Errorf("page was not rendered: %w", err)
+	}
+	defer imgFile.Close()
+
+	if limit := r.limits.MaxPageBytes; limit > 0 {
+		if info, err := imgFile.Stat(); err == nil && info.Size() > limit {
+			return nil, pdfLimitError(dto.CodePDFRenderTooLarge, "page %d rendered to %d bytes, more than %d", page, info.Size(), limit)
+		}
+	}
+	if limit := r.limits.MaxPagePixels; limit > 0 {
+		cfg, _, err := image.DecodeConfig(imgFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode the PNG: %w", err)
+		}
+		if int64(cfg.Width)*int64(cfg.Height) > limit {
+			return nil, pdfLimitError(dto.CodePDFPageTooLarge, "page %d rendered at %dx%d pixels, more than %d", page, cfg.Width, cfg.Height, limit)
+		}
+		if _, err := imgFile.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+	}
+
+	img, _, err := image.Decode(imgFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode the PNG: %w", err)
+	}
+	return img, nil
+}
+
+func (r *pdftoppmRenderer) Close() error { return os.RemoveAll(r.dir) }
+
+// mupdfRenders bounds the MuPDF renders running in the process, those
+// given up on included: a slot is held until MuPDF returns, so requests
+// that time out cannot pile up renders behind the request limiter's back.
+var mupdfRenders = pipeline.NewLimiter(runtime.NumCPU())
+
+// mupdfRenderer renders in process with MuPDF, without temp files or
+// Poppler. MuPDF cannot be interrupted: a render given up on when ctx is done
+// runs on in the background, and the document is freed once it finishes.
+type mupdfRenderer struct {
+	doc     *fitz.Document
+	renders *pipeline.Limiter
+	running sync.WaitGroup
+}
+
+func openMuPDF(pdfData []byte) (*mupdfRenderer, error) {
+	doc, err := fitz.NewFromMemory(pdfData)
+	if err != nil {
+		if doc != nil {
+			doc.Close()
+		}
+		return nil, fmt.Errorf("MuPDF cannot open the PDF: %w", err)
+	}
+	return &mupdfRenderer{doc: doc, renders: mupdfRenders}, nil
+}
+
+func (r *mupdfRenderer) render(ctx context.Context, page, dpi int) (image.Image, error) {
+	type rendered struct {
+		img *image.RGBA
+		err error
+	}
+	release, err := r.renders.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	done := make(chan rendered, 1)
+	r.running.Add(1)
+	go func() {
+		defer r.running.Done()
+		defer release()
+		img, err := r.doc.ImageDPI(page-1, float64(dpi))
+		done <- rendered{img, err}
+	}()
+
+	select {
+	case res := <-done:
+		if res.err != nil {
+			return nil, res.err
+		}
+		return res.img, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (r *mupdfRenderer) Close() error {
+	go func() {
+		r.running.Wait()
+		r.doc.Close()
+	}()
+	return nil
+}
+
+// RenderOptions narrow what ExtractImages renders, for documents whose
+// content is on known pages. The zero value renders every page at the
+// processor's DPI.
+type RenderOptions struct {
+	DPI   int // 0 = the processor's
+	Pages PageSet
+	// MaxPages ends the sequence after that many of the selected pages;
+	// 0 = no limit
+	MaxPages int
+}
+
+// PageRange is the 1-based pages From to To; To 0 runs to the last page.
+type PageRange struct {
+	From, To int
+}
+
+// PageSet selects pages by ranges; an empty set selects every page.
+type PageSet []PageRange
+
+// ParsePageSet parses a page selection such as "2", "1-3" or "1,4-" (page 4
+// to the end).
+func ParsePageSet(s string) (PageSet, error) {
+	var set PageSet
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		r := PageRange{}
+		var err error
+		if r.From, err = strconv.Atoi(strings.TrimSpace(from)); err != nil || r.From < 1 {
+			return nil, fmt.Errorf("invalid page range %q", part)
+		}
+		switch to = strings.TrimSpace(to); {
+		case !isRange:
+			r.To = r.From
+		case to != "":
+			if r.To, err = strconv.Atoi(to); err != nil || r.To < r.From {
+				return nil, fmt.Errorf("invalid page range %q", part)
+			}
+		}
+		set = append(set, r)
+	}
+	return set, nil
+}
+
+// Contains reports whether the 1-based page is selected.
+func (s PageSet) Contains(page int) bool {
+	if len(s) == 0 {
+		return true
+	}
+	for _, r := range s {
+		if page >= r.From && (r.To == 0 || page <= r.To) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"log/slog"
+
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+)
+
+// portraitPhoto crops the holder's portrait from the front side (first input)
+// of an ID document when the request asked for it, returning a base64 JPEG or
+// "" when none was requested or found. It runs outside the result cache, so
+// cached entries never carry photos. pdf may be nil for image-only documents.
+func portraitPhoto(ctx context.Context, docType string, inputs [][]byte, pdf PDFProcessor, password string) string {
+	if !pipeline.PhotoRequested(ctx) || len(inputs) == 0 {
+		return ""
+	}
+
+	img, err := documentImage(ctx, inputs[0], pdf, password)
+	if err != nil {
+		slog.WarnContext(ctx, "Portrait: could not read document image", "doc_type", docType, "error", err)
+		return ""
+	}
+
+	portrait, ok := face.Crop(img, docType)
+	if !ok {
+		slog.InfoContext(ctx, "Portrait: no face found", "doc_type", docType)
+		return ""
+	}
+	photo, err := face.EncodeJPEG(portrait)
+	if err != nil {
+		slog.WarnContext(ctx, "Portrait: JPEG encoding failed", "doc_type", docType, "error", err)
+		return ""
+	}
+	return photo
+}
+
+// documentImage decodes an uploaded image, or renders the first page of a PDF.
+func documentImage(ctx context.Context, data []byte, pdf PDFProcessor, password string) (image.Image, error) {
+	if bytes.HasPrefix(data, []byte("%PDF")) {
+		if pdf == nil {
+			return nil, fmt.Errorf("PDF documents are not supported here")
+		}
+		return pdf.RasterizePage(ctx, data, password, 1)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	return img, err
+}
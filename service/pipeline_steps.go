@@ -0,0 +1,899 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"iter"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageconv"
+	"github.com/Aashish23092/ocr-income-verification/utils/imageprep"
+	"github.com/Aashish23092/ocr-income-verification/utils/imagequality"
+	"github.com/Aashish23092/ocr-income-verification/utils/integrity"
+	"github.com/Aashish23092/ocr-income-verification/utils/script"
+)
+
+// PipelineSteps returns the steps shared by every document service. Services
+// add their own parse/validate steps via Orchestrator.Extend.
+//
+//	decrypt        remove PDF encryption using the upload password, or
+//	               passwords derived from the password hints
+//	metadata       scan date and provenance (forensics, digital signatures)
+//	               from the PDF, or scan date from image EXIF
+//	pdftext        use the PDF text layer when it has real content
+//	rasterize      render PDF pages when there is no usable text layer, or
+//	               split a multi-page TIFF into pages
+//	rasterize:O    the same, with options O separated by ";": pages=1-2 (page
+//	               ranges, as ParsePageSet), dpi=N, max=N (pages at most)
+//	preprocess:X   apply an imageprep step (grayscale, binarize) to page images
+//	orient         turn rotated page images upright (Tesseract OSD; photos
+//	               are already turned by their EXIF orientation when decoded)
+//	ocr:A|B        OCR each page with engine A, falling back to B; without a
+//	               language hint, pages in an Indian script are re-read in its
+//	               Tesseract language
+//	ocr:A+B        consensus: OCR each page with A and B concurrently; parse
+//	               results are merged field by field (pipeline.Doc.Candidates)
+//	score          combine OCR confidence and resolution into the final quality score
+func PipelineSteps(pdfProcessor PDFProcessor, paddle client.PaddleEngine, tesseract client.TesseractEngine) pipeline.Registry {
+	engines := map[string]langOCREngine{
+		// PaddleOCR serves a fixed model and ignores the language hint
+		"paddle": func(_ string, words func([]dto.OCRWord)) ocrEngine {
+			return func(ctx context.Context, img []byte) (string, float64, error) {
+				if paddle == nil {
+					return "", 0, fmt.Errorf("paddle OCR is not configured")
+				}
+				st, err := paddle.ExtractStructured(ctx, img)
+				if err != nil {
+					return "", 0, err
+				}
+				words(st.Words)
+				return st.Text, 75.0, nil // Default for PaddleOCR
+			}
+		},
+		"tesseract": func(lang string, words func([]dto.OCRWord)) ocrEngine {
+			return func(ctx context.Context, img []byte) (string, float64, error) {
+				st, err := tesseract.ExtractStructured(ctx, img, lang)
+				if err != nil {
+					return "", 0, err
+				}
+				words(st.Words)
+				return st.Text, st.Confidence, nil
+			}
+		},
+	}
+
+	return pipeline.Registry{
+		"decrypt":  noArg(decryptStep(pdfProcessor)),
+		"metadata": noArg(metadataStep(pdfProcessor)),
+		"pdftext":  noArg(pdfTextStep(pdfProcessor)),
+		"rasterize": func(arg string) (pipeline.Step, error) {
+			opts, err := parseRenderOptions(arg)
+			if err != nil {
+				return nil, err
+			}
+			return rasterizeStep(pdfProcessor, opts), nil
+		},
+		"orient": noArg(orientStep(tesseract)),
+		"preprocess": func(arg string) (pipeline.Step, error) {
+			prep, err := imageprep.Lookup(arg)
+			if err != nil {
+				return nil, err
+			}
+			return preprocessStep(prep), nil
+		},
+		"ocr": func(arg string) (pipeline.Step, error) {
+			if strings.Contains(arg, "+") {
+				names := strings.Split(arg, "+")
+				ensemble := make([]langOCREngine, len(names))
+				for i, name := range names {
+					eng, ok := engines[name]
+					if !ok {
+						return nil, fmt.Errorf("unknown OCR engine %q", name)
+					}
+					ensemble[i] = eng
+				}
+				return consensusOCRStep(names, ensemble), nil
+			}
+
+			var chain []langOCREngine
+			for _, name := range strings.Split(arg, "|") {
+				eng, ok := engines[name]
+				if !ok {
+					return nil, fmt.Errorf("unknown OCR engine %q", name)
+				}
+				chain = append(chain, eng)
+			}
+			return ocrStep(chain, tesseract), nil
+		},
+		"score": noArg(scoreStep),
+	}
+}
+
+// noArg wraps a step that takes no argument.
+func noArg(f pipeline.StepFunc) pipeline.Factory {
+	return func(arg string) (pipeline.Step, error) {
+		if arg != "" {
+			return nil, fmt.Errorf("step takes no argument")
+		}
+		return f, nil
+	}
+}
+
+func decryptStep(pdf PDFProcessor) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if !doc.IsPDF() {
+			return nil
+		}
+		passwords := pdfPasswords(doc)
+		if len(passwords) == 0 {
+			return nil
+		}
+
+		var data []byte
+		var err error
+		var opened utils.BankPassword
+		for _, opened = range passwords {
+			if data, err = pdf.Decrypt(doc.Inputs[0], opened.Password); err == nil {
+				break
+			}
+		}
+		if err != nil {
+			derived := len(passwords)
+			if doc.Password != "" {
+				derived--
+			}
+			if derived > 0 {
+				return fmt.Errorf("%w (tried %d passwords derived from the password hints)", err, derived)
+			}
+			return err
+		}
+		if opened.Format != "" {
+			slog.InfoContext(doc.Ctx, "Protected PDF opened with a derived password", "file", doc.Filename, "format", opened.Format)
+		}
+
+		// Decrypting rewrites the file, which breaks its signatures: check them first
+		sigs, err := pdf.VerifySignatures(doc.Inputs[0], opened.Password)
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Signature check failed", "file", doc.Filename, "error", err)
+		}
+		doc.Provenance = &dto.DocumentProvenance{Findings: []string{}, Signatures: sigs}
+
+		doc.Inputs[0] = data
+		doc.Password = ""
+		return nil
+	}
+}
+
+// pdfPasswords are the passwords to open doc with: the one given, then those
+// derived from the password hints when the file is protected. The given one
+// has no Format.
+func pdfPasswords(doc *pipeline.Doc) []utils.BankPassword {
+	var passwords []utils.BankPassword
+	if doc.Password != "" {
+		passwords = append(passwords, utils.BankPassword{Password: doc.Password})
+	}
+	if doc.PasswordHints != nil && pdfEncrypted(doc.Inputs[0]) {
+		for _, p := range utils.BankPasswords(*doc.PasswordHints) {
+			if p.Password != doc.Password {
+				passwords = append(passwords, p)
+			}
+		}
+	}
+	return passwords
+}
+
+// pdfEncrypted reports whether a PDF has an encryption dictionary; a false
+// positive only costs failed decryption attempts.
+func pdfEncrypted(data []byte) bool {
+	return bytes.Contains(data, []byte("/Encrypt"))
+}
+
+func metadataStep(pdf PDFProcessor) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.IsPDF() {
+			if prov, err := pdf.InspectMetadata(doc.Inputs[0], doc.Password); err == nil {
+				var sigs []dto.DocumentSignature
+				if doc.Provenance != nil {
+					sigs = doc.Provenance.Signatures // checked before decryption
+				} else if sigs, err = pdf.VerifySignatures(doc.Inputs[0], doc.Password); err != nil {
+					slog.WarnContext(doc.Ctx, "Signature check failed", "file", doc.Filename, "error", err)
+				}
+				integrity.ApplySignatures(prov, sigs)
+				doc.Provenance = prov
+				doc.ScanDate = prov.CreationDate
+				if len(prov.Findings) > 0 {
+					doc.AddIssue(dto.FraudMetadataAnomaly)
+				}
+			}
+			return nil
+		}
+
+		// Latest capture date across pages stands for the document
+		for _, data := range doc.Inputs {
+			if exif, err := utils.ParseEXIF(data); err == nil {
+				if d := exif.CaptureDate(); d != nil && (doc.ScanDate == nil || d.After(*doc.ScanDate)) {
+					doc.ScanDate = d
+				}
+			}
+		}
+		return nil
+	}
+}
+
+// pdfProvenance inspects a PDF's metadata and digital signatures; nil when the
+// metadata cannot be read.
+func pdfProvenance(pdf PDFProcessor, data []byte, password string) *dto.DocumentProvenance {
+	prov, err := pdf.InspectMetadata(data, password)
+	if err != nil {
+		return nil
+	}
+	sigs, err := pdf.VerifySignatures(data, password)
+	if err != nil {
+		slog.Warn("Signature check failed", "error", err)
+	}
+	integrity.ApplySignatures(prov, sigs)
+	return prov
+}
+
+func pdfTextStep(pdf PDFProcessor) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if !doc.IsPDF() {
+			return nil
+		}
+
+		text, err := pdf.ExtractText(doc.Inputs[0], doc.Password)
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "PDF text extraction failed", "file", doc.Filename, "error", err)
+			doc.AddIssue("pdf_text_extraction_failed")
+		}
+
+		// Too little text means a scanned PDF; leave it to rasterize + OCR
+		if len(strings.TrimSpace(text)) < 20 {
+			slog.InfoContext(doc.Ctx, "PDF has little or no text layer, using image OCR", "file", doc.Filename)
+			return nil
+		}
+
+		doc.Text = text
+		doc.TextLayer = true
+		doc.Quality.OcrConfidence = 100.0
+		doc.Quality.ResolutionScore = 100.0 // Vector PDF
+		doc.Quality.FinalScore = 100.0
+		return nil
+	}
+}
+
+// parseRenderOptions parses the rasterize step's argument, e.g.
+// "pages=2;dpi=300".
+func parseRenderOptions(arg string) (RenderOptions, error) {
+	var opts RenderOptions
+	if arg == "" {
+		return opts, nil
+	}
+	for _, opt := range strings.Split(arg, ";") {
+		key, value, _ := strings.Cut(opt, "=")
+		var err error
+		switch strings.TrimSpace(key) {
+		case "pages":
+			opts.Pages, err = ParsePageSet(value)
+		case "dpi":
+			if opts.DPI, err = strconv.Atoi(value); err == nil && (opts.DPI < 36 || opts.DPI > 1200) {
+				err = fmt.Errorf("dpi %d is outside 36-1200", opts.DPI)
+			}
+		case "max":
+			if opts.MaxPages, err = strconv.Atoi(value); err == nil && opts.MaxPages < 1 {
+				err = fmt.Errorf("max must be at least 1")
+			}
+		default:
+			err = fmt.Errorf("unknown option %q", key)
+		}
+		if err != nil {
+			return RenderOptions{}, err
+		}
+	}
+	return opts, nil
+}
+
+func rasterizeStep(pdf PDFProcessor, opts RenderOptions) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.Text != "" {
+			return nil
+		}
+
+		// Pages are rendered on demand as later steps read them
+		switch {
+		case doc.IsPDF():
+			doc.Pages = pdf.ExtractImages(doc.Ctx, doc.Inputs[0], doc.Password, opts)
+		case len(doc.Inputs) == 1 && imageconv.IsTIFF(doc.Inputs[0]):
+			// multi-page scans are read like PDF pages
+			doc.Pages = imageconv.TIFFPages(doc.Inputs[0])
+		}
+		return nil
+	}
+}
+
+// osdMinConfidence is the Tesseract orientation confidence from which a page
+// is turned; below it sparse pages such as ID cards are too often misjudged.
+const osdMinConfidence = 5.0
+
+func orientStep(tesseract client.TesseractEngine) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.Text != "" || tesseract == nil {
+			return nil
+		}
+		turn := func(page int, img image.Image) image.Image {
+			data, err := encodePNG(img)
+			if err != nil {
+				return img
+			}
+			rotate, confidence, err := tesseract.DetectOrientation(doc.Ctx, data)
+			if err != nil {
+				slog.DebugContext(doc.Ctx, "Orientation detection failed", "file", doc.Filename, "page", page, "error", err)
+				return img
+			}
+			if rotate == 0 || confidence < osdMinConfidence {
+				return img
+			}
+			doc.Quality.Rotations = append(doc.Quality.Rotations, dto.PageRotation{Page: page, Degrees: rotate, Source: dto.RotationOSD})
+			return imageprep.Rotate(img, rotate)
+		}
+
+		if doc.Pages != nil {
+			src := doc.Pages
+			doc.Pages = func(yield func(image.Image, error) bool) {
+				page := 0
+				for img, err := range src {
+					if err == nil {
+						page++
+						img = turn(page, img)
+					}
+					if !yield(img, err) {
+						return
+					}
+				}
+			}
+			return nil
+		}
+		images, err := pageImages(doc)
+		if err != nil {
+			return err
+		}
+		for i, img := range images {
+			doc.Images[i] = turn(i+1, img)
+		}
+		return nil
+	}
+}
+
+func preprocessStep(prep imageprep.Step) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.Text != "" {
+			return nil
+		}
+		if doc.Pages != nil {
+			src := doc.Pages
+			doc.Pages = func(yield func(image.Image, error) bool) {
+				for img, err := range src {
+					if err == nil {
+						img = prep(img)
+					}
+					if !yield(img, err) {
+						return
+					}
+				}
+			}
+			return nil
+		}
+		images, err := pageImages(doc)
+		if err != nil {
+			return err
+		}
+		for i, img := range images {
+			doc.Images[i] = prep(img)
+		}
+		return nil
+	}
+}
+
+// ocrEngine returns the text of one page image and a confidence (0-100).
+type ocrEngine func(ctx context.Context, img []byte) (string, float64, error)
+
+// langOCREngine binds an engine to a document's language hint and to a sink
+// receiving the word boxes of each page it reads.
+type langOCREngine func(lang string, words func([]dto.OCRWord)) ocrEngine
+
+// A page is re-read in the language of its script when most of its text is
+// in an Indian script, or when it was read with less than
+// scriptCheckBelowConfidence and Tesseract OSD names one with at least
+// scriptMinConfidence: English-only OCR of such a page returns garbled Latin
+// text rather than the script.
+const (
+	scriptMinShare             = 0.5
+	scriptCheckBelowConfidence = 60.0
+	scriptMinConfidence        = 1.0
+)
+
+// ocrStep reads the pages with the engine chain. Without a language hint,
+// from the first page found to be in an Indian script the Tesseract default
+// language gives way to that script's (tesseract nil turns this off).
+func ocrStep(langChain []langOCREngine, tesseract client.TesseractEngine) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.Text != "" {
+			return nil
+		}
+
+		var pageWords []dto.OCRWord
+		setWords := func(words []dto.OCRWord) { pageWords = words }
+		bindChain := func(lang string) []ocrEngine {
+			chain := make([]ocrEngine, len(langChain))
+			for i, bind := range langChain {
+				chain[i] = bind(lang, setWords)
+			}
+			return chain
+		}
+		chain := bindChain(doc.Language())
+		detectScript := doc.Language() == "" && tesseract != nil
+
+		if doc.IsPDF() && doc.Pages == nil && len(doc.Images) == 0 {
+			doc.AddIssue("scanned_pdf_ocr_failed")
+			return nil
+		}
+
+		var totalConfidence float64
+		var lastErr error
+		var failed []int
+		pageCount := 0
+		for page, err := range ocrInputs(doc) {
+			if doc.Ctx.Err() != nil {
+				return doc.Ctx.Err() // the client went away: stop reading pages
+			}
+			if errors.Is(err, ErrPDFLimit) {
+				return err // a PDF bomb: none of it is read
+			}
+			if ocrBudgetSpent(doc, pageCount) {
+				break
+			}
+			if err != nil {
+				slog.WarnContext(doc.Ctx, "Failed to read a page", "file", doc.Filename, "error", err)
+				lastErr = err
+				continue
+			}
+			pageCount++
+
+			pageWords = nil
+			text, conf, err := runOCRChain(doc.Ctx, chain, page)
+			if err != nil {
+				slog.WarnContext(doc.Ctx, "OCR failed for page", "file", doc.Filename, "page", pageCount, "error", err)
+				failed = append(failed, pageCount)
+				lastErr = err
+				continue
+			}
+			if detectScript {
+				if name, lang := pageScript(doc.Ctx, tesseract, page, text, conf); lang != "" {
+					detectScript = false
+					if err := tesseract.ValidateLang(lang); err != nil {
+						slog.WarnContext(doc.Ctx, "Document script has no OCR language installed", "file", doc.Filename, "script", name, "error", err)
+					} else {
+						slog.InfoContext(doc.Ctx, "Re-reading document in its script's language", "file", doc.Filename, "script", name, "lang", lang)
+						chain = bindChain(lang)
+						doc.Quality.Script, doc.Quality.OCRLang = name, lang
+						words := pageWords
+						if t, c, err := runOCRChain(doc.Ctx, chain, page); err == nil {
+							text, conf = t, c
+						} else {
+							pageWords = words
+							slog.WarnContext(doc.Ctx, "OCR in the script's language failed for page", "file", doc.Filename, "page", pageCount, "error", err)
+						}
+					}
+				}
+			}
+			doc.PageTexts = append(doc.PageTexts, text)
+			for _, w := range pageWords {
+				w.Page = len(doc.PageTexts)
+				doc.Words = append(doc.Words, w)
+			}
+			totalConfidence += conf
+			if doc.OnPage != nil {
+				doc.OnPage(text)
+			}
+		}
+		if pageCount > 1 {
+			for _, n := range failed {
+				doc.AddIssue(fmt.Sprintf("page_%d_ocr_failed", n))
+			}
+		}
+
+		if len(doc.PageTexts) == 0 {
+			switch {
+			case doc.Partial:
+				return nil // out of time before the first page
+			case doc.IsPDF():
+				if pageCount == 0 {
+					doc.AddIssue("pdf_image_extraction_failed")
+				}
+				doc.AddIssue("scanned_pdf_ocr_failed")
+				return nil
+			case pageCount > 1:
+				return ocrFailed(doc, fmt.Errorf("OCR failed for every page of %s", doc.Filename))
+			default:
+				return ocrFailed(doc, fmt.Errorf("image OCR failed: %w", lastErr))
+			}
+		}
+
+		doc.Text = strings.Join(doc.PageTexts, "\n")
+		doc.Quality.OcrConfidence = totalConfidence / float64(len(doc.PageTexts))
+		if doc.Quality.ResolutionScore == 0 {
+			doc.Quality.ResolutionScore = 80.0 // an image Go cannot decode, scored by the engines only
+		}
+		return nil
+	}
+}
+
+// ocrBudgetSpent reports whether the request's OCR budget has run out, read
+// pages into doc. The rest of doc is then left unread and doc marked Partial.
+func ocrBudgetSpent(doc *pipeline.Doc, read int) bool {
+	budget := pipeline.OCRBudgetFrom(doc.Ctx)
+	if !budget.Spent() {
+		return false
+	}
+	budget.Exhaust()
+	doc.Partial = true
+	doc.AddIssue("ocr_budget_exhausted")
+	slog.InfoContext(doc.Ctx, "OCR budget spent, leaving the rest of the document unread", "file", doc.Filename, "pages_read", read)
+	return true
+}
+
+// ocrInputs yields the encoded page images to OCR: streamed PDF pages,
+// rendered/preprocessed images, or else the uploaded image bytes, with TIFFs
+// split into their pages. Streamed pages are encoded one at a time and not
+// kept. Every page is inspected for image quality on the way.
+func ocrInputs(doc *pipeline.Doc) iter.Seq2[[]byte, error] {
+	return func(yield func([]byte, error) bool) {
+		switch {
+		case doc.Pages != nil:
+			for img, err := range doc.Pages {
+				if err != nil {
+					if !yield(nil, err) {
+						return
+					}
+					continue
+				}
+				inspectPage(doc, img)
+				if !yield(encodePNG(img)) {
+					return
+				}
+			}
+		case len(doc.Images) > 0:
+			for _, img := range doc.Images {
+				inspectPage(doc, img)
+				if !yield(encodePNG(img)) {
+					return
+				}
+			}
+		default:
+			for _, data := range doc.Inputs {
+				if !imageconv.IsTIFF(data) {
+					if img, _, err := image.Decode(bytes.NewReader(data)); err == nil {
+						inspectPage(doc, img)
+					}
+					if !yield(data, nil) {
+						return
+					}
+					continue
+				}
+				for img, err := range imageconv.TIFFPages(data) {
+					if err != nil {
+						if !yield(nil, err) {
+							return
+						}
+						continue
+					}
+					inspectPage(doc, img)
+					if !yield(encodePNG(img)) {
+						return
+					}
+				}
+			}
+		}
+	}
+}
+
+// inspectPage scores a page image into doc.Quality, which keeps the worst
+// page's scores and the issues of any page. Glare and cropping are only
+// looked for in uploaded images, not in rendered PDF pages.
+func inspectPage(doc *pipeline.Doc, img image.Image) {
+	m := imagequality.Analyze(img, !doc.IsPDF())
+	q := &doc.Quality
+	if q.ResolutionScore == 0 || m.ResolutionScore < q.ResolutionScore {
+		q.ResolutionScore = m.ResolutionScore
+	}
+	if q.ContrastScore == 0 || m.ContrastScore < q.ContrastScore {
+		q.ContrastScore = m.ContrastScore
+	}
+	if q.SharpnessScore == 0 || m.SharpnessScore < q.SharpnessScore {
+		q.SharpnessScore = m.SharpnessScore
+	}
+	for _, issue := range m.Issues {
+		if !slices.Contains(q.Issues, issue) {
+			doc.AddIssue(issue)
+		}
+	}
+}
+
+func encodePNG(img image.Image) ([]byte, error) {
+	buf := new(bytes.Buffer)
+	if err := png.Encode(buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode page image: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// runOCRChain tries each engine in turn; an engine's output is accepted when it
+// has real content or when it is the last engine left.
+// consensusOCRStep reads every page with all engines at once and keeps each
+// engine's reading as a candidate. The most confident one fills Text/Words, so
+// steps other than parse see a normal single-engine document.
+func consensusOCRStep(names []string, engines []langOCREngine) pipeline.StepFunc {
+	return func(doc *pipeline.Doc) error {
+		if doc.Text != "" {
+			return nil
+		}
+		if doc.IsPDF() && doc.Pages == nil && len(doc.Images) == 0 {
+			doc.AddIssue("scanned_pdf_ocr_failed")
+			return nil
+		}
+
+		type reading struct {
+			text  string
+			conf  float64
+			words []dto.OCRWord
+			err   error
+		}
+		cands := make([]pipeline.OCRCandidate, len(engines))
+		confSum := make([]float64, len(engines))
+		pagesRead := make([]int, len(engines))
+		var lastErr error
+		var failed []int
+		pageCount := 0
+
+		for page, err := range ocrInputs(doc) {
+			if doc.Ctx.Err() != nil {
+				return doc.Ctx.Err() // the client went away: stop reading pages
+			}
+			if ocrBudgetSpent(doc, pageCount) {
+				break
+			}
+			if err != nil {
+				slog.WarnContext(doc.Ctx, "Failed to read a page", "file", doc.Filename, "error", err)
+				lastErr = err
+				continue
+			}
+			pageCount++
+
+			out := make([]reading, len(engines))
+			var wg sync.WaitGroup
+			for i, bind := range engines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					r := &out[i]
+					eng := bind(doc.Language(), func(words []dto.OCRWord) { r.words = words })
+					r.text, r.conf, r.err = eng(doc.Ctx, page)
+					if r.err == nil && strings.TrimSpace(r.text) == "" {
+						r.err = fmt.Errorf("%s returned no text", names[i])
+					}
+				}()
+			}
+			wg.Wait()
+
+			read := false
+			for _, r := range out {
+				read = read || r.err == nil
+			}
+			if !read {
+				slog.WarnContext(doc.Ctx, "OCR failed for page", "file", doc.Filename, "page", pageCount, "error", out[0].err)
+				failed = append(failed, pageCount)
+				lastErr = out[0].err
+				continue
+			}
+
+			// An engine that missed this page gets an empty page, keeping page numbers aligned
+			for i, r := range out {
+				c := &cands[i]
+				c.Engine = names[i]
+				if r.err != nil {
+					slog.InfoContext(doc.Ctx, "Consensus OCR: engine failed on page", "engine", names[i], "page", pageCount, "error", r.err)
+					c.PageTexts = append(c.PageTexts, "")
+					continue
+				}
+				c.PageTexts = append(c.PageTexts, r.text)
+				for _, w := range r.words {
+					w.Page = len(c.PageTexts)
+					c.Words = append(c.Words, w)
+				}
+				confSum[i] += wordConfidence(r.words, r.conf)
+				pagesRead[i]++
+			}
+		}
+		if pageCount > 1 {
+			for _, n := range failed {
+				doc.AddIssue(fmt.Sprintf("page_%d_ocr_failed", n))
+			}
+		}
+
+		var valid []pipeline.OCRCandidate
+		for i, c := range cands {
+			if pagesRead[i] == 0 {
+				continue
+			}
+			c.Text = strings.Join(c.PageTexts, "\n")
+			// pages an engine missed count as zero confidence
+			c.Confidence = confSum[i] / float64(len(c.PageTexts))
+			valid = append(valid, c)
+		}
+		if len(valid) == 0 {
+			switch {
+			case doc.Partial:
+				return nil // out of time before the first page
+			case doc.IsPDF():
+				if pageCount == 0 {
+					doc.AddIssue("pdf_image_extraction_failed")
+				}
+				doc.AddIssue("scanned_pdf_ocr_failed")
+				return nil
+			case pageCount > 1:
+				return ocrFailed(doc, fmt.Errorf("OCR failed for every page of %s", doc.Filename))
+			default:
+				return ocrFailed(doc, fmt.Errorf("image OCR failed: %w", lastErr))
+			}
+		}
+
+		best := valid[0]
+		for _, c := range valid[1:] {
+			if c.Confidence > best.Confidence {
+				best = c
+			}
+		}
+		doc.Text, doc.PageTexts, doc.Words = best.Text, best.PageTexts, best.Words
+		if len(valid) > 1 {
+			doc.Candidates = valid
+		}
+		if doc.OnPage != nil {
+			for _, text := range best.PageTexts {
+				doc.OnPage(text)
+			}
+		}
+		doc.Quality.OcrConfidence = best.Confidence
+		if doc.Quality.ResolutionScore == 0 {
+			doc.Quality.ResolutionScore = 80.0 // an image Go cannot decode, scored by the engines only
+		}
+		return nil
+	}
+}
+
+// wordConfidence is the mean word confidence of a page, which both engines
+// report on the same 0-100 scale, or the engine's page figure without words.
+func wordConfidence(words []dto.OCRWord, page float64) float64 {
+	if len(words) == 0 {
+		return page
+	}
+	var sum float64
+	for _, w := range words {
+		sum += w.Confidence
+	}
+	return sum / float64(len(words))
+}
+
+// pageScript returns the Indian script a page is in and the Tesseract
+// language to read it with, when the default language does not already; both
+// are empty otherwise.
+func pageScript(ctx context.Context, tesseract client.TesseractEngine, page []byte, text string, conf float64) (name, lang string) {
+	detected := script.Detect(text)
+	name = detected.Script
+	if name == script.Latin || detected.Share < scriptMinShare {
+		if conf >= scriptCheckBelowConfidence {
+			return "", ""
+		}
+		osdScript, confidence, err := tesseract.DetectScript(ctx, page)
+		if err != nil || confidence < scriptMinConfidence {
+			return "", ""
+		}
+		name = osdScript
+	}
+	lang = script.Language(name)
+	if lang == "" || script.Covers(tesseract.Lang(), lang) {
+		return "", ""
+	}
+	return name, lang
+}
+
+func runOCRChain(ctx context.Context, chain []ocrEngine, page []byte) (string, float64, error) {
+	var err error
+	for i, eng := range chain {
+		if ctx.Err() != nil {
+			return "", 0, ctx.Err()
+		}
+		var text string
+		var conf float64
+		text, conf, err = eng(ctx, page)
+		if err == nil && (len(strings.TrimSpace(text)) > 5 || i == len(chain)-1) {
+			return text, conf, nil
+		}
+	}
+	if err == nil {
+		err = fmt.Errorf("no OCR engine returned text")
+	}
+	return "", 0, err
+}
+
+func scoreStep(doc *pipeline.Doc) error {
+	q := &doc.Quality
+	if q.OcrConfidence == 0 && q.ResolutionScore == 0 {
+		return nil // nothing was read
+	}
+	q.FinalScore = (q.OcrConfidence + q.ResolutionScore) / 2
+	if q.FinalScore < 60 {
+		doc.AddIssue("low_quality_document")
+	}
+	return nil
+}
+
+// pageImages returns every page image at once: streamed PDF pages are collected
+// (for short documents such as ID cards), and image inputs are decoded on first
+// use, TIFFs into all their pages, skipping ones that fail to decode.
+func pageImages(doc *pipeline.Doc) ([]image.Image, error) {
+	if doc.Pages != nil {
+		images, err := CollectImages(doc.Pages)
+		doc.Pages = nil
+		if errors.Is(err, ErrPDFLimit) {
+			return nil, err
+		}
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Failed to extract images from PDF", "file", doc.Filename, "error", err)
+			doc.AddIssue("pdf_image_extraction_failed")
+		}
+		doc.Images = images
+	}
+	if len(doc.Images) > 0 || doc.IsPDF() {
+		return doc.Images, nil
+	}
+	for i, data := range doc.Inputs {
+		if imageconv.IsTIFF(data) {
+			for img, err := range imageconv.TIFFPages(data) {
+				if err != nil {
+					slog.WarnContext(doc.Ctx, "Failed to decode TIFF page", "file", doc.Filename, "image", i+1, "error", err)
+					continue
+				}
+				doc.Images = append(doc.Images, img)
+			}
+			continue
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			slog.WarnContext(doc.Ctx, "Failed to decode image", "file", doc.Filename, "image", i+1, "error", err)
+			continue
+		}
+		// image.Decode ignores the EXIF orientation of camera photos
+		if exif, err := utils.ParseEXIF(data); err == nil && exif.Orientation > 1 {
+			var degrees int
+			img, degrees = imageprep.Orient(img, exif.Orientation)
+			doc.Quality.Rotations = append(doc.Quality.Rotations, dto.PageRotation{Page: len(doc.Images) + 1, Degrees: degrees, Source: dto.RotationEXIF})
+		}
+		doc.Images = append(doc.Images, img)
+	}
+	if len(doc.Images) == 0 {
+		return nil, fmt.Errorf("failed to decode image")
+	}
+	return doc.Images, nil
+}
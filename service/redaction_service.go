@@ -0,0 +1,158 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"slices"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+	"github.com/Aashish23092/ocr-income-verification/utils/face"
+	"github.com/Aashish23092/ocr-income-verification/utils/redact"
+	"github.com/pdfcpu/pdfcpu/pkg/api"
+	"github.com/pdfcpu/pdfcpu/pkg/pdfcpu"
+)
+
+const redactDocType = "redact"
+
+// ErrInvalidRedaction is returned for an unknown redaction mode or region.
+var ErrInvalidRedaction = errors.New("invalid redaction request")
+
+// redactPad is how far a redaction reaches past its box, in pixels.
+const redactPad = 4
+
+var redactRegions = []string{dto.RedactAadhaar, dto.RedactSignature, dto.RedactPhoto}
+
+// RedactionService hides the sensitive regions of a document before it is
+// archived: Aadhaar numbers, signatures and photos, located on the OCR
+// reading of the page images.
+type RedactionService struct {
+	pipelines *pipeline.Orchestrator
+}
+
+func NewRedactionService(pipelines *pipeline.Orchestrator) (*RedactionService, error) {
+	s := &RedactionService{}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"pages": noArg(s.pagesStep),
+	}, redactDocType)
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// pagesStep keeps every page image: the redaction is drawn on them once OCR
+// has found where to draw.
+func (s *RedactionService) pagesStep(doc *pipeline.Doc) error {
+	_, err := pageImages(doc)
+	return err
+}
+
+// Redact returns the PDF or image with regions (all when empty) blacked out
+// or blurred per mode. photoDocType, when a face package document type, says
+// where on the page the portrait is searched for.
+func (s *RedactionService) Redact(ctx context.Context, data []byte, filename, password, mode string, regions []string, photoDocType string) (*dto.RedactedDocument, error) {
+	var draw func(image.Image, []dto.BoundingBox, int) *image.RGBA
+	switch mode {
+	case "", dto.RedactModeBlackout:
+		mode, draw = dto.RedactModeBlackout, redact.BlackOut
+	case dto.RedactModeBlur:
+		draw = redact.Blur
+	default:
+		return nil, fmt.Errorf("%w: unknown mode %q", ErrInvalidRedaction, mode)
+	}
+	if len(regions) == 0 {
+		regions = redactRegions
+	}
+	for _, r := range regions {
+		if !slices.Contains(redactRegions, r) {
+			return nil, fmt.Errorf("%w: unknown region %q", ErrInvalidRedaction, r)
+		}
+	}
+
+	// Page images are needed, not a cacheable reading, so the pipeline always runs
+	doc := &pipeline.Doc{Ctx: ctx, DocType: redactDocType, Filename: filename, Password: password, Inputs: [][]byte{data}}
+	if err := s.pipelines.Run(doc); err != nil {
+		return nil, err
+	}
+	if len(doc.Images) == 0 {
+		return nil, errors.New("no page images could be read from the document")
+	}
+
+	found := redactionRegions(doc, regions, photoDocType)
+	result := &dto.RedactedDocument{Mode: mode, Pages: len(doc.Images), Regions: found}
+	pages := make([][]byte, len(doc.Images))
+	for i, img := range doc.Images {
+		var boxes []dto.BoundingBox
+		for _, r := range found {
+			if r.Page == i+1 {
+				boxes = append(boxes, r.Box)
+			}
+		}
+		page, err := face.JPEG(draw(img, boxes, redactPad))
+		if err != nil {
+			return nil, fmt.Errorf("encoding page %d: %w", i+1, err)
+		}
+		pages[i] = page
+	}
+
+	out, contentType := pages[0], "image/jpeg"
+	if doc.IsPDF() || len(pages) > 1 {
+		var err error
+		if out, err = imagesToPDF(pages); err != nil {
+			return nil, err
+		}
+		contentType = "application/pdf"
+	}
+	result.RedactedFile = base64.StdEncoding.EncodeToString(out)
+	result.ContentType = contentType
+	return result, nil
+}
+
+// redactionRegions locates the requested regions on doc's page images.
+func redactionRegions(doc *pipeline.Doc, regions []string, photoDocType string) []dto.RedactedRegion {
+	var found []dto.RedactedRegion
+	addWords := func(kind string, words []dto.OCRWord) {
+		for _, w := range words {
+			found = append(found, dto.RedactedRegion{Type: kind, Page: w.Page, Box: w.Box})
+		}
+	}
+	if slices.Contains(regions, dto.RedactAadhaar) {
+		for _, n := range utils.FindAadhaarNumbers(doc.Text) {
+			addWords(dto.RedactAadhaar, utils.AadhaarMaskBoxes(doc.Words, n))
+		}
+	}
+	if slices.Contains(regions, dto.RedactSignature) {
+		addWords(dto.RedactSignature, redact.SignatureBoxes(doc.Words))
+	}
+	if slices.Contains(regions, dto.RedactPhoto) {
+		for i, img := range doc.Images {
+			if r, ok := face.Detect(img, photoDocType); ok {
+				found = append(found, dto.RedactedRegion{Type: dto.RedactPhoto, Page: i + 1, Box: dto.BoundingBox{X0: r.Min.X, Y0: r.Min.Y, X1: r.Max.X, Y1: r.Max.Y}})
+			}
+		}
+	}
+	return found
+}
+
+// imagesToPDF builds a PDF with one JPEG per page, each page the size of its
+// image.
+func imagesToPDF(pages [][]byte) ([]byte, error) {
+	readers := make([]io.Reader, len(pages))
+	for i, p := range pages {
+		readers[i] = bytes.NewReader(p)
+	}
+	var buf bytes.Buffer
+	if err := api.ImportImages(nil, &buf, readers, pdfcpu.DefaultImportConfig(), nil); err != nil {
+		return nil, fmt.Errorf("building redacted PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// RentService analyzes rent receipts and rental agreements on their own. The
+// same documents uploaded to income verification as doc_type "rent" go through
+// the identical pipeline and set the response's monthly rent.
+type RentService struct {
+	income *IncomeService
+}
+
+func NewRentService(income *IncomeService) *RentService {
+	return &RentService{income: income}
+}
+
+func (s *RentService) Analyze(ctx context.Context, data []byte, filename, password, tenantID string) (*dto.RentData, error) {
+	meta := dto.DocumentMeta{Filename: filename, DocType: dto.DocTypeRent, Password: password}
+	result, err := s.income.ProcessDocument(ctx, data, meta, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	rent, ok := result.(dto.RentData)
+	if !ok {
+		return nil, fmt.Errorf("rent pipeline produced no result")
+	}
+	return &rent, nil
+}
@@ -0,0 +1,19 @@
+package service
+
+import "context"
+
+type sideEffectsOffKey struct{}
+
+// WithoutSideEffects marks ctx for work whose side effects were applied
+// already: a request run again in full after a partial response stores no
+// verification, marks no resubmissions and sends no webhook.
+func WithoutSideEffects(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sideEffectsOffKey{}, true)
+}
+
+// SideEffects reports whether work under ctx may store results and send
+// webhooks.
+func SideEffects(ctx context.Context) bool {
+	off, _ := ctx.Value(sideEffectsOffKey{}).(bool)
+	return !off
+}
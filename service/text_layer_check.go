@@ -0,0 +1,77 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+// textLayerTolerance absorbs rounding differences between the two readings (in rupees).
+const textLayerTolerance = 1.0
+
+// crossValidateTextLayer OCRs a sample of rendered pages of a text PDF and compares
+// their key figures with the embedded text layer. Pages where either reading has
+// no figures are still listed as checked but cannot produce mismatches.
+func (s *IncomeService) crossValidateTextLayer(ctx context.Context, data []byte, password string) (*dto.TextLayerCheck, error) {
+	pageTexts, err := s.pdfProcessor.ExtractPageTexts(data, password)
+	if err != nil {
+		return nil, err
+	}
+
+	check := &dto.TextLayerCheck{PagesChecked: []int{}, Mismatches: []dto.FigureMismatch{}}
+	for _, page := range samplePages(len(pageTexts), s.textLayerPages) {
+		img, err := s.pdfProcessor.RasterizePage(ctx, data, password, page)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render page %d: %w", page, err)
+		}
+		imgPath, err := saveImageToTempFile(img)
+		if err != nil {
+			return nil, err
+		}
+		ocrText, _, err := s.tesseractClient.ExtractTextAndQuality(ctx, imgPath)
+		os.Remove(imgPath)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			slog.WarnContext(ctx, "Text layer check: OCR failed", "page", page, "error", err)
+			continue
+		}
+
+		check.PagesChecked = append(check.PagesChecked, page)
+		check.Mismatches = append(check.Mismatches, utils.CompareStatementFigures(
+			page,
+			utils.ExtractStatementFigures(pageTexts[page-1]),
+			utils.ExtractStatementFigures(ocrText),
+			textLayerTolerance,
+		)...)
+	}
+	return check, nil
+}
+
+// samplePages picks up to n 1-based pages spread evenly from first to last.
+// The last page is always included because it carries the closing balance.
+func samplePages(total, n int) []int {
+	if total <= 0 || n <= 0 {
+		return nil
+	}
+	if n >= total {
+		n = total
+	}
+	if n == 1 {
+		return []int{total}
+	}
+
+	pages := make([]int, 0, n)
+	for i := 0; i < n; i++ {
+		p := 1 + i*(total-1)/(n-1)
+		if len(pages) == 0 || pages[len(pages)-1] != p {
+			pages = append(pages, p)
+		}
+	}
+	return pages
+}
@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+	"github.com/Aashish23092/ocr-income-verification/utils/scoring"
+)
+
+// ErrInvalidOverride is returned for overrides that name unknown or protected fields,
+// or whose value has the wrong type for the field.
+var ErrInvalidOverride = errors.New("invalid override")
+
+// Fields owned by the service itself rather than by OCR.
+var protectedOverrideRoots = map[string]bool{
+	"verification_id": true,
+	"processed_at":    true,
+	"decision":        true,
+}
+
+// GetVerification returns a stored verification with any overrides applied,
+// if client requested it; another client's reads as store.ErrNotFound.
+func (s *IncomeService) GetVerification(id, client string) (*dto.VerificationRecord, error) {
+	rec, err := s.store.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if rec.Client != client {
+		return nil, store.ErrNotFound
+	}
+	return rec, nil
+}
+
+// ListVerifications returns the stored verifications matching filter, newest
+// first, with any overrides applied.
+func (s *IncomeService) ListVerifications(filter store.ListFilter) ([]*dto.VerificationRecord, error) {
+	return s.store.List(filter)
+}
+
+// ScoreVerification computes the quick-screen score of a stored verification
+// client requested (overrides included) with the weights configured for its
+// tenant.
+func (s *IncomeService) ScoreVerification(id, client string) (*dto.VerificationScore, error) {
+	rec, err := s.GetVerification(id, client)
+	if err != nil {
+		return nil, err
+	}
+	score := scoring.Score(rec.Current, s.scoreWeights.WeightsFor(rec.TenantID))
+	score.VerificationID = rec.ID
+	return &score, nil
+}
+
+// OverrideFields records reviewer corrections against a stored verification
+// client requested and rebuilds its authoritative view; another client's reads
+// as store.ErrNotFound. The machine-extracted values are never modified.
+func (s *IncomeService) OverrideFields(id, client, reviewer string, inputs []dto.FieldOverrideInput) (*dto.VerificationRecord, error) {
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("%w: no overrides given", ErrInvalidOverride)
+	}
+
+	return s.store.Update(id, func(rec *dto.VerificationRecord) error {
+		if rec.Client != client {
+			return store.ErrNotFound
+		}
+		extracted, err := toTree(rec.Extracted)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		for _, in := range inputs {
+			path := strings.Split(in.Field, ".")
+			if in.Field == "" || protectedOverrideRoots[path[0]] {
+				return fmt.Errorf("%w: field %q cannot be overridden", ErrInvalidOverride, in.Field)
+			}
+			// Empty omitempty fields are absent from the tree; their machine value is null.
+			machine, _ := getPath(extracted, path)
+			rec.Overrides = append(rec.Overrides, dto.FieldOverride{
+				Field:        in.Field,
+				MachineValue: machine,
+				Value:        in.Value,
+				Reason:       in.Reason,
+				Reviewer:     reviewer,
+				OverriddenAt: now,
+			})
+		}
+
+		current, err := s.mergeOverrides(rec.Extracted, rec.Overrides)
+		if err != nil {
+			return err
+		}
+		current.VerificationID = rec.ID
+		if s.rules.HasRules(rec.TenantID) {
+			if current.Decision, err = s.rules.Evaluate(rec.TenantID, current); err != nil {
+				return err
+			}
+		}
+
+		rec.Current = *current
+		rec.UpdatedAt = now
+		return nil
+	})
+}
+
+// mergeOverrides replays the audit trail over the extracted result; later overrides win.
+func (s *IncomeService) mergeOverrides(extracted dto.IncomeVerificationResponse, overrides []dto.FieldOverride) (*dto.IncomeVerificationResponse, error) {
+	tree, err := toTree(extracted)
+	if err != nil {
+		return nil, err
+	}
+	for _, o := range overrides {
+		if !setPath(tree, strings.Split(o.Field, "."), o.Value) {
+			return nil, fmt.Errorf("%w: unknown field %q", ErrInvalidOverride, o.Field)
+		}
+	}
+
+	raw, err := json.Marshal(tree)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown fields must fail rather than vanish silently.
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.DisallowUnknownFields()
+	var merged dto.IncomeVerificationResponse
+	if err := dec.Decode(&merged); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOverride, err)
+	}
+	return &merged, nil
+}
+
+func toTree(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	tree := map[string]interface{}{}
+	return tree, json.Unmarshal(raw, &tree)
+}
+
+// getPath walks a decoded JSON tree; numeric segments index into arrays.
+func getPath(node interface{}, path []string) (interface{}, bool) {
+	for _, key := range path {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			v, ok := n[key]
+			if !ok {
+				return nil, false
+			}
+			node = v
+		case []interface{}:
+			i, err := strconv.Atoi(key)
+			if err != nil || i < 0 || i >= len(n) {
+				return nil, false
+			}
+			node = n[i]
+		default:
+			return nil, false
+		}
+	}
+	return node, true
+}
+
+// setPath sets the value at path. The parent must exist; a missing object key is
+// added so that empty omitempty fields can be filled in.
+func setPath(tree map[string]interface{}, path []string, value interface{}) bool {
+	parent, ok := getPath(tree, path[:len(path)-1])
+	if !ok {
+		return false
+	}
+	last := path[len(path)-1]
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		p[last] = value
+	case []interface{}:
+		i, err := strconv.Atoi(last)
+		if err != nil || i < 0 || i >= len(p) {
+			return false
+		}
+		p[i] = value
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/store"
+)
+
+func TestOverrideFieldsKeepsMachineValues(t *testing.T) {
+	s := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	resp := dto.IncomeVerificationResponse{
+		VerificationID: "v1",
+		SalarySlips:    []dto.SalarySlipData{{EmployeeName: "RAVI KUMAR", NetSalary: 4520}},
+	}
+	require.NoError(t, s.store.Save(&dto.VerificationRecord{ID: "v1", Extracted: resp, Current: resp}))
+
+	rec, err := s.OverrideFields("v1", "", "alice", []dto.FieldOverrideInput{
+		{Field: "salary_slips.0.net_salary", Value: 45200.0, Reason: "decimal misread"},
+		{Field: "salary_slips.0.ifsc", Value: "HDFC0001234"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4520.0, rec.Extracted.SalarySlips[0].NetSalary)
+	assert.Equal(t, 45200.0, rec.Current.SalarySlips[0].NetSalary)
+	assert.Equal(t, "HDFC0001234", rec.Current.SalarySlips[0].IFSC)
+	require.Len(t, rec.Overrides, 2)
+	assert.Equal(t, 4520.0, rec.Overrides[0].MachineValue)
+	assert.Nil(t, rec.Overrides[1].MachineValue)
+	assert.Equal(t, "alice", rec.Overrides[0].Reviewer)
+
+	// A later override of the same field wins; the trail keeps both.
+	rec, err = s.OverrideFields("v1", "", "bob", []dto.FieldOverrideInput{{Field: "salary_slips.0.net_salary", Value: 45000.0}})
+	require.NoError(t, err)
+	assert.Equal(t, 45000.0, rec.Current.SalarySlips[0].NetSalary)
+	assert.Len(t, rec.Overrides, 3)
+}
+
+func TestOverrideFieldsRejectsBadInput(t *testing.T) {
+	s := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	resp := dto.IncomeVerificationResponse{SalarySlips: []dto.SalarySlipData{{NetSalary: 1}}}
+	require.NoError(t, s.store.Save(&dto.VerificationRecord{ID: "v1", Extracted: resp, Current: resp}))
+
+	for _, in := range []dto.FieldOverrideInput{
+		{Field: "salary_slips.3.net_salary", Value: 1.0},
+		{Field: "salary_slips.0.no_such_field", Value: 1.0},
+		{Field: "salary_slips.0.net_salary", Value: "lots"},
+		{Field: "decision", Value: nil},
+	} {
+		_, err := s.OverrideFields("v1", "", "alice", []dto.FieldOverrideInput{in})
+		assert.ErrorIs(t, err, ErrInvalidOverride, in.Field)
+	}
+
+	rec, err := s.GetVerification("v1", "")
+	require.NoError(t, err)
+	assert.Empty(t, rec.Overrides)
+
+	_, err = s.OverrideFields("missing", "", "alice", []dto.FieldOverrideInput{{Field: "cross_check.name_match", Value: true}})
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestGetVerificationScopedToClient(t *testing.T) {
+	s := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	require.NoError(t, s.store.Save(&dto.VerificationRecord{ID: "v1", Client: "lender-a"}))
+
+	rec, err := s.GetVerification("v1", "lender-a")
+	require.NoError(t, err)
+	assert.Equal(t, "lender-a", rec.Client)
+
+	_, err = s.GetVerification("v1", "lender-b")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	_, err = s.ScoreVerification("v1", "")
+	assert.ErrorIs(t, err, store.ErrNotFound)
+}
+
+func TestOverrideFieldsScopedToClient(t *testing.T) {
+	s := &IncomeService{store: store.NewMemoryStore(0, 0)}
+	resp := dto.IncomeVerificationResponse{SalarySlips: []dto.SalarySlipData{{NetSalary: 4520}}}
+	require.NoError(t, s.store.Save(&dto.VerificationRecord{ID: "v1", Client: "lender-a", Extracted: resp, Current: resp}))
+
+	override := []dto.FieldOverrideInput{{Field: "salary_slips.0.net_salary", Value: 45200.0}}
+	_, err := s.OverrideFields("v1", "lender-b", "mallory", override)
+	assert.ErrorIs(t, err, store.ErrNotFound)
+	rec, err := s.GetVerification("v1", "lender-a")
+	require.NoError(t, err)
+	assert.Empty(t, rec.Overrides)
+	assert.Equal(t, 4520.0, rec.Current.SalarySlips[0].NetSalary)
+
+	rec, err = s.OverrideFields("v1", "lender-a", "alice", override)
+	require.NoError(t, err)
+	assert.Equal(t, 45200.0, rec.Current.SalarySlips[0].NetSalary)
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Aashish23092/ocr-income-verification/client"
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/pipeline"
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+type VoterIDService struct {
+	paddle    client.PaddleEngine
+	tesseract client.TesseractEngine
+	pipelines *pipeline.Orchestrator
+}
+
+func NewVoterIDService(paddle client.PaddleEngine, tesseract client.TesseractEngine, pipelines *pipeline.Orchestrator) (*VoterIDService, error) {
+	s := &VoterIDService{
+		paddle:    paddle,
+		tesseract: tesseract,
+	}
+
+	var err error
+	s.pipelines, err = pipelines.Extend(pipeline.Registry{
+		"parse": noArg(s.parseStep),
+	}, "voter_id")
+	if err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// ExtractVoterID parses an EPIC card image. Front and back may be sent as
+// separate images; their text is combined before parsing.
+func (s *VoterIDService) ExtractVoterID(ctx context.Context, images [][]byte) (*dto.VoterIDResponse, error) {
+	doc := &pipeline.Doc{Ctx: ctx, DocType: "voter_id", Inputs: images}
+	return pipeline.Cached(s.pipelines, doc, func() (*dto.VoterIDResponse, error) {
+		if err := s.pipelines.Run(doc); err != nil {
+			return nil, err
+		}
+		result, ok := doc.Result.(*dto.VoterIDResponse)
+		if !ok {
+			return nil, fmt.Errorf("voter id pipeline produced no result")
+		}
+		return result, nil
+	})
+}
+
+func (s *VoterIDService) parseStep(doc *pipeline.Doc) error {
+	parsed := utils.ParseVoterIDText(doc.Text)
+
+	doc.Result = &dto.VoterIDResponse{
+		EPICNumber:   parsed.EPICNumber,
+		Name:         parsed.Name,
+		RelationType: parsed.RelationType,
+		RelationName: parsed.RelationName,
+		Address:      parsed.Address,
+		RawText:      parsed.RawText,
+	}
+	return nil
+}
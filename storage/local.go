@@ -0,0 +1,71 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Local keeps uploads as files in a directory.
+type Local struct {
+	dir string
+}
+
+// NewLocal returns a store in dir, creating it if needed.
+func NewLocal(dir string) (*Local, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("create upload directory: %w", err)
+	}
+	return &Local{dir: dir}, nil
+}
+
+func (l *Local) Put(_ context.Context, name string, data []byte) (string, error) {
+	key := newKey(name)
+	if err := os.WriteFile(l.Path(key), data, 0600); err != nil {
+		return "", err
+	}
+	return key, nil
+}
+
+func (l *Local) Get(_ context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(l.Path(key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return data, err
+}
+
+func (l *Local) Delete(_ context.Context, key string) error {
+	if err := os.Remove(l.Path(key)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (l *Local) Sweep(_ context.Context, maxAge time.Duration) (int, error) {
+	entries, err := os.ReadDir(l.dir)
+	if err != nil {
+		return 0, err
+	}
+	cutoff := time.Now().Add(-maxAge)
+	n := 0
+	for _, e := range entries {
+		info, err := e.Info()
+		if err != nil || e.IsDir() || !info.ModTime().Before(cutoff) {
+			continue
+		}
+		if os.Remove(filepath.Join(l.dir, e.Name())) == nil {
+			n++
+		}
+	}
+	return n, nil
+}
+
+// Path is the file holding key, for tools that need a file name.
+func (l *Local) Path(key string) string {
+	return filepath.Join(l.dir, filepath.Base(key))
+}
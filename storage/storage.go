@@ -0,0 +1,103 @@
+// Package storage keeps uploaded documents while they are processed (and,
+// when configured, for a retention period after). Backends are a local
+// directory, process memory and S3-compatible object stores: AWS S3, and
+// Google Cloud Storage through its XML API with HMAC keys.
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+)
+
+// ErrNotFound is returned when no object exists for a key.
+var ErrNotFound = errors.New("object not found")
+
+// Storage holds uploaded documents by key.
+type Storage interface {
+	// Put stores data under a new key derived from name and returns the key.
+	Put(ctx context.Context, name string, data []byte) (string, error)
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes an object; deleting a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+	// Sweep deletes the objects stored more than maxAge ago and returns how
+	// many it deleted.
+	Sweep(ctx context.Context, maxAge time.Duration) (int, error)
+}
+
+// Config selects and configures a backend.
+type Config struct {
+	Backend string // local | memory | s3 | gcs | off
+	Dir     string // local: directory, default <tmp>/ocr-uploads
+
+	// s3 and gcs
+	Bucket    string
+	Prefix    string // key prefix inside the bucket, e.g. "uploads/"
+	Endpoint  string // default AWS (by region) or https://storage.googleapis.com
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+// New builds the storage selected by cfg.Backend; "off" or "" returns nil.
+func New(cfg Config) (Storage, error) {
+	switch cfg.Backend {
+	case "", "off":
+		return nil, nil
+	case "memory":
+		return NewMemory(), nil
+	case "local":
+		dir := cfg.Dir
+		if dir == "" {
+			dir = filepath.Join(os.TempDir(), "ocr-uploads")
+		}
+		return NewLocal(dir)
+	case "s3", "gcs":
+		return NewS3(cfg)
+	}
+	return nil, fmt.Errorf("unknown storage backend %q", cfg.Backend)
+}
+
+var unsafeName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// newKey makes a unique, time-ordered key that keeps a readable form of the
+// upload's file name: "20251018T101112Z-3f9a1c0e-statement.pdf".
+func newKey(name string) string {
+	b := make([]byte, 4)
+	rand.Read(b)
+	name = unsafeName.ReplaceAllString(filepath.Base(name), "_")
+	if len(name) > 64 {
+		name = name[len(name)-64:]
+	}
+	return time.Now().UTC().Format("20060102T150405Z") + "-" + hex.EncodeToString(b) + "-" + name
+}
+
+// Janitor sweeps s every interval until ctx is done, deleting objects older
+// than maxAge: uploads kept for retention, and those of requests that died
+// before cleaning up after themselves.
+func Janitor(ctx context.Context, s Storage, maxAge, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			n, err := s.Sweep(ctx, maxAge)
+			if err != nil {
+				slog.Warn("Upload storage sweep failed", "error", err)
+				continue
+			}
+			if n > 0 {
+				slog.Info("Swept expired uploads", "count", n)
+			}
+		}
+	}
+}
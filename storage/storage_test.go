@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testStorage(t *testing.T, s Storage) {
+	ctx := context.Background()
+	key, err := s.Put(ctx, "../statement (1).pdf", []byte("%PDF-1.7"))
+	require.NoError(t, err)
+	assert.True(t, strings.HasSuffix(key, "-statement_1_.pdf"), key)
+
+	data, err := s.Get(ctx, key)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7", string(data))
+
+	n, err := s.Sweep(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+
+	require.NoError(t, s.Delete(ctx, key))
+	require.NoError(t, s.Delete(ctx, key))
+	_, err = s.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = s.Put(ctx, "old.png", []byte("x"))
+	require.NoError(t, err)
+	n, err = s.Sweep(ctx, -time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+}
+
+func TestLocal(t *testing.T) {
+	s, err := NewLocal(t.TempDir())
+	require.NoError(t, err)
+	testStorage(t, s)
+}
+
+func TestMemory(t *testing.T) {
+	testStorage(t, NewMemory())
+}
+
+// fakeS3 is a bucket that accepts any SigV4-signed request.
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AK/") {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	key := strings.TrimPrefix(r.URL.Path, "/bucket/")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("list-type") == "2":
+		var out listResult
+		for k := range f.objects {
+			out.Contents = append(out.Contents, struct {
+				Key          string    `xml:"Key"`
+				LastModified time.Time `xml:"LastModified"`
+			}{k, time.Now()})
+		}
+		xml.NewEncoder(w).Encode(out)
+	case r.Method == http.MethodPut:
+		f.objects[key], _ = io.ReadAll(r.Body)
+	case r.Method == http.MethodGet:
+		data, ok := f.objects[key]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write(data)
+	case r.Method == http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestS3(t *testing.T) {
+	srv := httptest.NewServer(&fakeS3{objects: map[string][]byte{}})
+	defer srv.Close()
+
+	s, err := NewS3(Config{Backend: "s3", Endpoint: srv.URL, Bucket: "bucket", Prefix: "uploads/", AccessKey: "AK", SecretKey: "SK"})
+	require.NoError(t, err)
+	testStorage(t, s)
+}
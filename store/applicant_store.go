@@ -0,0 +1,110 @@
+package store
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// maxApplicantDocuments bounds the documents kept per applicant in memory.
+const maxApplicantDocuments = 200
+
+// ApplicantStore persists the document extractions made for applicants.
+type ApplicantStore interface {
+	AddApplicantDocument(doc *dto.ApplicantDocument) error
+	// ApplicantDocuments returns the applicant's documents sent by client
+	// for tenantID, oldest first. Both are matched exactly: an applicant ID
+	// names someone only within one client's tenant.
+	ApplicantDocuments(applicantID, tenantID, client string) ([]dto.ApplicantDocument, error)
+}
+
+// MemoryApplicantStore keeps the latest documents of each applicant in
+// process memory: for at most limit applicants, the least recently updated
+// evicted first, each document for ttl after it was processed.
+type MemoryApplicantStore struct {
+	mu    sync.Mutex
+	limit int
+	ttl   time.Duration
+	order []string // applicant IDs, least recently updated first
+	docs  map[string][]dto.ApplicantDocument
+}
+
+// NewMemoryApplicantStore keeps the documents of at most limit applicants
+// for ttl each; limit <= 0 or ttl <= 0 lifts that bound.
+func NewMemoryApplicantStore(limit int, ttl time.Duration) *MemoryApplicantStore {
+	return &MemoryApplicantStore{limit: limit, ttl: ttl, docs: map[string][]dto.ApplicantDocument{}}
+}
+
+func (m *MemoryApplicantStore) AddApplicantDocument(doc *dto.ApplicantDocument) error {
+	cp := *doc
+	cp.Result = slices.Clone(doc.Result)
+	if cp.ProcessedAt.IsZero() {
+		cp.ProcessedAt = time.Now().UTC()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	docs := append(m.docs[doc.ApplicantID], cp)
+	if len(docs) > maxApplicantDocuments {
+		docs = slices.Delete(docs, 0, len(docs)-maxApplicantDocuments)
+	}
+	m.docs[doc.ApplicantID] = docs
+	if i := slices.Index(m.order, doc.ApplicantID); i >= 0 {
+		m.order = slices.Delete(m.order, i, i+1)
+	}
+	m.order = append(m.order, doc.ApplicantID)
+	m.evict(time.Now())
+	return nil
+}
+
+// evict drops the applicants over the limit, and those whose latest
+// document has expired. m.mu is held.
+func (m *MemoryApplicantStore) evict(now time.Time) {
+	for len(m.order) > 0 {
+		id := m.order[0]
+		docs := m.docs[id]
+		expired := m.ttl > 0 && now.Sub(docs[len(docs)-1].ProcessedAt) >= m.ttl
+		if !(m.limit > 0 && len(m.order) > m.limit) && !expired {
+			return
+		}
+		delete(m.docs, id)
+		m.order = m.order[1:]
+	}
+}
+
+func (m *MemoryApplicantStore) ApplicantDocuments(applicantID, tenantID, client string) ([]dto.ApplicantDocument, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	m.evict(now)
+	m.expire(applicantID, now)
+	var out []dto.ApplicantDocument
+	for _, doc := range m.docs[applicantID] {
+		if doc.TenantID == tenantID && doc.Client == client {
+			doc.Result = slices.Clone(doc.Result)
+			out = append(out, doc)
+		}
+	}
+	return out, nil
+}
+
+// expire drops the applicant's documents older than the ttl, and the
+// applicant when none are left. m.mu is held.
+func (m *MemoryApplicantStore) expire(applicantID string, now time.Time) {
+	if m.ttl <= 0 {
+		return
+	}
+	docs := slices.DeleteFunc(m.docs[applicantID], func(d dto.ApplicantDocument) bool {
+		return now.Sub(d.ProcessedAt) >= m.ttl
+	})
+	if len(docs) > 0 {
+		m.docs[applicantID] = docs
+		return
+	}
+	if _, ok := m.docs[applicantID]; ok {
+		delete(m.docs, applicantID)
+		m.order = slices.DeleteFunc(m.order, func(id string) bool { return id == applicantID })
+	}
+}
@@ -0,0 +1,183 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// isolationStore is what the client isolation tests need of a backend.
+type isolationStore interface {
+	VerificationStore
+	ApplicantStore
+}
+
+// isolationBackends are the stores the client isolation tests run against:
+// memory always, Postgres when TEST_DATABASE_URL names a database. Its rows
+// outlive the test, so every test uses fresh IDs.
+func isolationBackends(t *testing.T) map[string]func(t *testing.T) isolationStore {
+	backends := map[string]func(t *testing.T) isolationStore{
+		"memory": func(t *testing.T) isolationStore {
+			return struct {
+				*MemoryStore
+				*MemoryApplicantStore
+			}{NewMemoryStore(0, 0), NewMemoryApplicantStore(0, 0)}
+		},
+	}
+	if url := os.Getenv("TEST_DATABASE_URL"); url != "" {
+		backends["postgres"] = func(t *testing.T) isolationStore {
+			p, err := NewPostgresStore(url)
+			require.NoError(t, err)
+			t.Cleanup(func() { p.Close() })
+			return p
+		}
+	} else {
+		t.Log("TEST_DATABASE_URL not set; Postgres store not tested")
+	}
+	return backends
+}
+
+func verificationRecord(client, applicantID, tenantID, contentHash string, created time.Time) *dto.VerificationRecord {
+	return &dto.VerificationRecord{
+		ID:          NewID(),
+		Client:      client,
+		ApplicantID: applicantID,
+		TenantID:    tenantID,
+		Extracted: dto.IncomeVerificationResponse{Documents: []dto.DocumentStatus{
+			{Filename: "slip.pdf", Status: "processed", ContentHash: contentHash},
+		}},
+		CreatedAt: created,
+		UpdatedAt: created,
+	}
+}
+
+func recordIDs(recs []*dto.VerificationRecord) []string {
+	ids := make([]string, len(recs))
+	for i, rec := range recs {
+		ids[i] = rec.ID
+	}
+	return ids
+}
+
+func TestStoreGetKeepsClient(t *testing.T) {
+	for name, open := range isolationBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			a := verificationRecord("lender-a", NewID(), "", "", now)
+			b := verificationRecord("lender-b", a.ApplicantID, "", "", now)
+			require.NoError(t, s.Save(a))
+			require.NoError(t, s.Save(b))
+
+			got, err := s.Get(a.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "lender-a", got.Client)
+			got, err = s.Get(b.ID)
+			require.NoError(t, err)
+			assert.Equal(t, "lender-b", got.Client)
+
+			_, err = s.Get(NewID())
+			assert.ErrorIs(t, err, ErrNotFound)
+		})
+	}
+}
+
+func TestStoreListByApplicantScopedToClient(t *testing.T) {
+	for name, open := range isolationBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			applicant := NewID()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			a1 := verificationRecord("lender-a", applicant, "", "", now.Add(-time.Minute))
+			a2 := verificationRecord("lender-a", applicant, "", "", now)
+			b := verificationRecord("lender-b", applicant, "", "", now)
+			keyless := verificationRecord("", applicant, "", "", now)
+			for _, rec := range []*dto.VerificationRecord{a1, a2, b, keyless} {
+				require.NoError(t, s.Save(rec))
+			}
+
+			recs, err := s.List(ListFilter{Client: "lender-a", ApplicantID: applicant})
+			require.NoError(t, err)
+			assert.Equal(t, []string{a2.ID, a1.ID}, recordIDs(recs))
+
+			recs, err = s.List(ListFilter{Client: "lender-b", ApplicantID: applicant})
+			require.NoError(t, err)
+			assert.Equal(t, []string{b.ID}, recordIDs(recs))
+
+			// Without API keys the client is "": only keyless records match.
+			recs, err = s.List(ListFilter{ApplicantID: applicant})
+			require.NoError(t, err)
+			assert.Equal(t, []string{keyless.ID}, recordIDs(recs))
+
+			recs, err = s.List(ListFilter{Client: "lender-c", ApplicantID: applicant})
+			require.NoError(t, err)
+			assert.Empty(t, recs)
+		})
+	}
+}
+
+func TestStoreResubmissionLookupScopedToClient(t *testing.T) {
+	for name, open := range isolationBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			applicant := NewID()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			own := verificationRecord("lender-a", applicant, "t1", "hash-own", now.Add(-time.Minute))
+			other := verificationRecord("lender-b", applicant, "t1", "hash-shared", now)
+			otherTenant := verificationRecord("lender-a", applicant, "t2", "hash-shared", now)
+			for _, rec := range []*dto.VerificationRecord{own, other, otherTenant} {
+				require.NoError(t, s.Save(rec))
+			}
+
+			// The query markResubmissions makes for lender-a in tenant t1.
+			recs, err := s.List(ListFilter{Client: "lender-a", ApplicantID: applicant, TenantID: "t1", Limit: 50})
+			require.NoError(t, err)
+			require.Equal(t, []string{own.ID}, recordIDs(recs))
+			var hashes []string
+			for _, d := range recs[0].Extracted.Documents {
+				hashes = append(hashes, d.ContentHash)
+			}
+			assert.Equal(t, []string{"hash-own"}, hashes)
+		})
+	}
+}
+
+func TestApplicantDocumentsScopedToClient(t *testing.T) {
+	for name, open := range isolationBackends(t) {
+		t.Run(name, func(t *testing.T) {
+			s := open(t)
+			applicant := NewID()
+			now := time.Now().UTC().Truncate(time.Millisecond)
+			add := func(client, tenant, docType string) {
+				require.NoError(t, s.AddApplicantDocument(&dto.ApplicantDocument{
+					ApplicantID: applicant, TenantID: tenant, Client: client,
+					DocType: docType, Result: json.RawMessage(`{}`), ProcessedAt: now,
+				}))
+			}
+			add("lender-a", "t1", "pan")
+			add("lender-b", "t1", "aadhaar")
+			add("lender-a", "t2", "salary_slip")
+
+			docs, err := s.ApplicantDocuments(applicant, "t1", "lender-a")
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "pan", docs[0].DocType)
+			assert.Equal(t, "lender-a", docs[0].Client)
+
+			docs, err = s.ApplicantDocuments(applicant, "t1", "lender-b")
+			require.NoError(t, err)
+			require.Len(t, docs, 1)
+			assert.Equal(t, "aadhaar", docs[0].DocType)
+
+			docs, err = s.ApplicantDocuments(applicant, "t1", "")
+			require.NoError(t, err)
+			assert.Empty(t, docs)
+		})
+	}
+}
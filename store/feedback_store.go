@@ -0,0 +1,104 @@
+package store
+
+import (
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Extraction is what one extraction request returned, kept until its
+// feedback arrives. Fields maps the dot path of every scalar in the response
+// to a keyed hash of its normalized value, so no extracted PII is stored.
+type Extraction struct {
+	RequestID   string
+	Client      string
+	DocType     string
+	Release     string
+	Fields      map[string]string
+	ExtractedAt time.Time
+}
+
+// FeedbackStore persists extractions and the integrators' feedback on them.
+type FeedbackStore interface {
+	SaveExtraction(ex *Extraction) error
+	// GetExtraction returns ErrNotFound for unknown (or evicted) request IDs.
+	GetExtraction(requestID string) (*Extraction, error)
+	// SaveFeedback replaces any earlier feedback for the same request ID.
+	SaveFeedback(rec *dto.FeedbackRecord) error
+	ListFeedback() ([]dto.FeedbackRecord, error)
+}
+
+// MemoryFeedbackStore keeps the latest extractions, up to a limit, and all
+// feedback in process memory.
+type MemoryFeedbackStore struct {
+	mu          sync.Mutex
+	limit       int
+	order       []string // request IDs, oldest first
+	extractions map[string]*Extraction
+	feedback    map[string]*dto.FeedbackRecord
+}
+
+// NewMemoryFeedbackStore keeps at most limit extractions (limit <= 0 means
+// no limit), evicting the oldest first.
+func NewMemoryFeedbackStore(limit int) *MemoryFeedbackStore {
+	return &MemoryFeedbackStore{
+		limit:       limit,
+		extractions: map[string]*Extraction{},
+		feedback:    map[string]*dto.FeedbackRecord{},
+	}
+}
+
+func (m *MemoryFeedbackStore) SaveExtraction(ex *Extraction) error {
+	cp := *ex
+	cp.Fields = maps.Clone(ex.Fields)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.extractions[ex.RequestID]; !ok {
+		m.order = append(m.order, ex.RequestID)
+	}
+	m.extractions[ex.RequestID] = &cp
+	for m.limit > 0 && len(m.order) > m.limit {
+		delete(m.extractions, m.order[0])
+		m.order = m.order[1:]
+	}
+	return nil
+}
+
+func (m *MemoryFeedbackStore) GetExtraction(requestID string) (*Extraction, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	ex, ok := m.extractions[requestID]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp := *ex
+	cp.Fields = maps.Clone(ex.Fields)
+	return &cp, nil
+}
+
+func (m *MemoryFeedbackStore) SaveFeedback(rec *dto.FeedbackRecord) error {
+	cp := *rec
+	cp.Fields = slices.Clone(rec.Fields)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.feedback[rec.RequestID] = &cp
+	return nil
+}
+
+func (m *MemoryFeedbackStore) ListFeedback() ([]dto.FeedbackRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	out := make([]dto.FeedbackRecord, 0, len(m.feedback))
+	for _, rec := range m.feedback {
+		cp := *rec
+		cp.Fields = slices.Clone(rec.Fields)
+		out = append(out, cp)
+	}
+	slices.SortFunc(out, func(a, b dto.FeedbackRecord) int { return a.ReceivedAt.Compare(b.ReceivedAt) })
+	return out, nil
+}
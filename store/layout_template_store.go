@@ -0,0 +1,68 @@
+package store
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// LayoutTemplateStore persists the layout templates registered through the
+// admin API.
+type LayoutTemplateStore interface {
+	SaveLayoutTemplate(tpl *dto.LayoutTemplate) error
+	// LayoutTemplates returns the templates of docType (all when empty),
+	// oldest first.
+	LayoutTemplates(docType string) ([]dto.LayoutTemplate, error)
+	// DeleteLayoutTemplate returns ErrNotFound for an unknown id.
+	DeleteLayoutTemplate(id string) error
+}
+
+// MemoryLayoutTemplateStore keeps layout templates in process memory.
+type MemoryLayoutTemplateStore struct {
+	mu        sync.Mutex
+	templates map[string]dto.LayoutTemplate
+}
+
+func NewMemoryLayoutTemplateStore() *MemoryLayoutTemplateStore {
+	return &MemoryLayoutTemplateStore{templates: map[string]dto.LayoutTemplate{}}
+}
+
+func (m *MemoryLayoutTemplateStore) SaveLayoutTemplate(tpl *dto.LayoutTemplate) error {
+	cp := *tpl
+	cp.Keywords = slices.Clone(tpl.Keywords)
+	cp.Fields = slices.Clone(tpl.Fields)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.templates[tpl.ID] = cp
+	return nil
+}
+
+func (m *MemoryLayoutTemplateStore) LayoutTemplates(docType string) ([]dto.LayoutTemplate, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var out []dto.LayoutTemplate
+	for _, tpl := range m.templates {
+		if docType == "" || tpl.DocType == docType {
+			tpl.Keywords = slices.Clone(tpl.Keywords)
+			tpl.Fields = slices.Clone(tpl.Fields)
+			out = append(out, tpl)
+		}
+	}
+	slices.SortFunc(out, func(a, b dto.LayoutTemplate) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return out, nil
+}
+
+func (m *MemoryLayoutTemplateStore) DeleteLayoutTemplate(id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.templates[id]; !ok {
+		return ErrNotFound
+	}
+	delete(m.templates, id)
+	return nil
+}
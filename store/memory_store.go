@@ -0,0 +1,183 @@
+package store
+
+import (
+	"cmp"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// ErrNotFound is returned when no verification exists for an ID.
+var ErrNotFound = errors.New("verification not found")
+
+// VerificationStore persists verification records.
+type VerificationStore interface {
+	Save(rec *dto.VerificationRecord) error
+	Get(id string) (*dto.VerificationRecord, error)
+	// Update loads the record, applies fn and saves the result atomically.
+	// Nothing is saved when fn returns an error.
+	Update(id string, fn func(rec *dto.VerificationRecord) error) (*dto.VerificationRecord, error)
+	// List returns the records matching filter, newest first.
+	List(filter ListFilter) ([]*dto.VerificationRecord, error)
+}
+
+// ListFilter selects stored verifications; empty fields match any record,
+// except Client: records are listed to the client that requested them only.
+type ListFilter struct {
+	Client      string
+	ApplicantID string
+	TenantID    string
+	ExactTenant bool // TenantID "" matches only records without a tenant
+	Limit       int  // 0 = DefaultListLimit
+	Offset      int
+}
+
+// DefaultListLimit is the page size of a List without a limit.
+const DefaultListLimit = 50
+
+func (f ListFilter) matches(rec *dto.VerificationRecord) bool {
+	return rec.Client == f.Client &&
+		(f.ApplicantID == "" || rec.ApplicantID == f.ApplicantID) &&
+		((f.TenantID == "" && !f.ExactTenant) || rec.TenantID == f.TenantID)
+}
+
+func (f ListFilter) limit() int {
+	if f.Limit <= 0 {
+		return DefaultListLimit
+	}
+	return f.Limit
+}
+
+// MemoryStore keeps records in process memory. Records are deep-copied on the
+// way in and out so callers can never mutate stored state by accident. They
+// hold PII, so at most limit are kept, each for ttl after it was first saved,
+// the oldest evicted first.
+type MemoryStore struct {
+	mu      sync.Mutex
+	limit   int
+	ttl     time.Duration
+	order   []string // record IDs, oldest first
+	saved   map[string]time.Time
+	records map[string]*dto.VerificationRecord
+}
+
+// NewMemoryStore keeps at most limit records for ttl each; limit <= 0 or
+// ttl <= 0 lifts that bound.
+func NewMemoryStore(limit int, ttl time.Duration) *MemoryStore {
+	return &MemoryStore{limit: limit, ttl: ttl, saved: map[string]time.Time{}, records: map[string]*dto.VerificationRecord{}}
+}
+
+// evict drops the expired records and those over the limit. m.mu is held.
+func (m *MemoryStore) evict(now time.Time) {
+	for len(m.order) > 0 {
+		id := m.order[0]
+		if !(m.limit > 0 && len(m.order) > m.limit) && !(m.ttl > 0 && now.Sub(m.saved[id]) >= m.ttl) {
+			return
+		}
+		delete(m.records, id)
+		delete(m.saved, id)
+		m.order = m.order[1:]
+	}
+}
+
+func (m *MemoryStore) Save(rec *dto.VerificationRecord) error {
+	cp, err := clone(rec)
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	now := time.Now()
+	if _, ok := m.records[rec.ID]; !ok {
+		m.order = append(m.order, rec.ID)
+		m.saved[rec.ID] = now
+	}
+	m.records[rec.ID] = cp
+	m.evict(now)
+	return nil
+}
+
+func (m *MemoryStore) Get(id string) (*dto.VerificationRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evict(time.Now())
+	rec, ok := m.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return clone(rec)
+}
+
+func (m *MemoryStore) Update(id string, fn func(rec *dto.VerificationRecord) error) (*dto.VerificationRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.evict(time.Now())
+	rec, ok := m.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	cp, err := clone(rec)
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(cp); err != nil {
+		return nil, err
+	}
+	stored, err := clone(cp)
+	if err != nil {
+		return nil, err
+	}
+	m.records[id] = stored
+	return cp, nil
+}
+
+func (m *MemoryStore) List(filter ListFilter) ([]*dto.VerificationRecord, error) {
+	m.mu.Lock()
+	m.evict(time.Now())
+	var matched []*dto.VerificationRecord
+	for _, rec := range m.records {
+		if filter.matches(rec) {
+			matched = append(matched, rec)
+		}
+	}
+	m.mu.Unlock()
+
+	slices.SortFunc(matched, func(a, b *dto.VerificationRecord) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), strings.Compare(a.ID, b.ID))
+	})
+	matched = matched[min(max(filter.Offset, 0), len(matched)):]
+	matched = matched[:min(filter.limit(), len(matched))]
+
+	out := make([]*dto.VerificationRecord, 0, len(matched))
+	for _, rec := range matched {
+		cp, err := clone(rec)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, cp)
+	}
+	return out, nil
+}
+
+// NewID returns a random 128-bit hex identifier.
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func clone(rec *dto.VerificationRecord) (*dto.VerificationRecord, error) {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	var cp dto.VerificationRecord
+	return &cp, json.Unmarshal(raw, &cp)
+}
@@ -0,0 +1,54 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestMemoryStoreEvictsOldest(t *testing.T) {
+	m := NewMemoryStore(2, time.Hour)
+	for _, id := range []string{"a", "b", "c"} {
+		require.NoError(t, m.Save(&dto.VerificationRecord{ID: id}))
+	}
+	_, err := m.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = m.Get("c")
+	assert.NoError(t, err)
+
+	recs, err := m.List(ListFilter{})
+	require.NoError(t, err)
+	assert.Len(t, recs, 2)
+}
+
+func TestMemoryStoreExpires(t *testing.T) {
+	m := NewMemoryStore(0, time.Nanosecond)
+	require.NoError(t, m.Save(&dto.VerificationRecord{ID: "a"}))
+	time.Sleep(time.Millisecond)
+	_, err := m.Get("a")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestMemoryApplicantStoreBounds(t *testing.T) {
+	m := NewMemoryApplicantStore(2, time.Hour)
+	for _, id := range []string{"a1", "a2", "a1", "a3"} {
+		require.NoError(t, m.AddApplicantDocument(&dto.ApplicantDocument{ApplicantID: id, DocType: "pan"}))
+	}
+	docs, err := m.ApplicantDocuments("a2", "", "")
+	require.NoError(t, err)
+	assert.Empty(t, docs, "the least recently updated applicant is evicted")
+	docs, err = m.ApplicantDocuments("a1", "", "")
+	require.NoError(t, err)
+	assert.Len(t, docs, 2)
+
+	m = NewMemoryApplicantStore(0, time.Hour)
+	require.NoError(t, m.AddApplicantDocument(&dto.ApplicantDocument{ApplicantID: "a1", ProcessedAt: time.Now().Add(-2 * time.Hour)}))
+	require.NoError(t, m.AddApplicantDocument(&dto.ApplicantDocument{ApplicantID: "a1", ProcessedAt: time.Now()}))
+	docs, err = m.ApplicantDocuments("a1", "", "")
+	require.NoError(t, err)
+	assert.Len(t, docs, 1, "expired documents are dropped")
+}
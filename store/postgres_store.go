@@ -0,0 +1,261 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+
+	_ "github.com/jackc/pgx/v5/stdlib" // registers the "pgx" database/sql driver
+)
+
+// postgresTimeout bounds each store call; the interface carries no context.
+const postgresTimeout = 10 * time.Second
+
+// postgresSchema is applied at startup. The record column holds the whole
+// VerificationRecord: the documents of the request, what was extracted from
+// them, the cross-check outcome and the override trail.
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS verifications (
+	id           TEXT PRIMARY KEY,
+	tenant_id    TEXT NOT NULL DEFAULT '',
+	applicant_id TEXT NOT NULL DEFAULT '',
+	status       TEXT NOT NULL DEFAULT '',
+	record       JSONB NOT NULL,
+	created_at   TIMESTAMPTZ NOT NULL,
+	updated_at   TIMESTAMPTZ NOT NULL
+);
+ALTER TABLE verifications ADD COLUMN IF NOT EXISTS client TEXT NOT NULL DEFAULT '';
+CREATE INDEX IF NOT EXISTS verifications_client_idx ON verifications (client, created_at DESC);
+CREATE INDEX IF NOT EXISTS verifications_applicant_idx ON verifications (applicant_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS verifications_tenant_idx ON verifications (tenant_id, created_at DESC);
+CREATE TABLE IF NOT EXISTS applicant_documents (
+	id           BIGSERIAL PRIMARY KEY,
+	applicant_id TEXT NOT NULL,
+	tenant_id    TEXT NOT NULL DEFAULT '',
+	doc_type     TEXT NOT NULL,
+	request_id   TEXT NOT NULL DEFAULT '',
+	result       JSONB NOT NULL,
+	processed_at TIMESTAMPTZ NOT NULL
+);
+CREATE INDEX IF NOT EXISTS applicant_documents_applicant_idx ON applicant_documents (applicant_id, processed_at);
+ALTER TABLE applicant_documents ADD COLUMN IF NOT EXISTS client TEXT NOT NULL DEFAULT '';
+CREATE TABLE IF NOT EXISTS layout_templates (
+	id         TEXT PRIMARY KEY,
+	doc_type   TEXT NOT NULL,
+	template   JSONB NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL
+);
+`
+
+// PostgresStore keeps verification records (applicants' documents and layout
+// templates too) in
+// Postgres tables, so they survive restarts and are shared by every replica.
+type PostgresStore struct {
+	db *sql.DB
+}
+
+// NewPostgresStore connects to the database at url (a postgres:// URL or
+// key=value DSN) and creates its tables if needed.
+func NewPostgresStore(url string) (*PostgresStore, error) {
+	db, err := sql.Open("pgx", url)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	if err := db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to verification database: %w", err)
+	}
+	if _, err := db.ExecContext(ctx, postgresSchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create verification schema: %w", err)
+	}
+	return &PostgresStore{db: db}, nil
+}
+
+// Close closes the connection pool.
+func (p *PostgresStore) Close() error {
+	return p.db.Close()
+}
+
+func (p *PostgresStore) Save(rec *dto.VerificationRecord) error {
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO verifications (id, tenant_id, applicant_id, status, record, created_at, updated_at, client)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (id) DO UPDATE SET
+			tenant_id = EXCLUDED.tenant_id, applicant_id = EXCLUDED.applicant_id, status = EXCLUDED.status,
+			record = EXCLUDED.record, updated_at = EXCLUDED.updated_at, client = EXCLUDED.client`,
+		rec.ID, rec.TenantID, rec.ApplicantID, rec.Current.Status, raw, rec.CreatedAt, rec.UpdatedAt, rec.Client)
+	return err
+}
+
+func (p *PostgresStore) Get(id string) (*dto.VerificationRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	return scanRecord(p.db.QueryRowContext(ctx, `SELECT record FROM verifications WHERE id = $1`, id))
+}
+
+func (p *PostgresStore) Update(id string, fn func(rec *dto.VerificationRecord) error) (*dto.VerificationRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	tx, err := p.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	rec, err := scanRecord(tx.QueryRowContext(ctx, `SELECT record FROM verifications WHERE id = $1 FOR UPDATE`, id))
+	if err != nil {
+		return nil, err
+	}
+	if err := fn(rec); err != nil {
+		return nil, err
+	}
+	raw, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	_, err = tx.ExecContext(ctx, `UPDATE verifications SET status = $2, record = $3, updated_at = $4 WHERE id = $1`,
+		id, rec.Current.Status, raw, rec.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return rec, tx.Commit()
+}
+
+func (p *PostgresStore) List(filter ListFilter) ([]*dto.VerificationRecord, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT record FROM verifications
+		WHERE client = $5 AND ($1 = '' OR applicant_id = $1) AND (($2 = '' AND NOT $6) OR tenant_id = $2)
+		ORDER BY created_at DESC, id
+		LIMIT $3 OFFSET $4`,
+		filter.ApplicantID, filter.TenantID, filter.limit(), max(filter.Offset, 0), filter.Client, filter.ExactTenant)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []*dto.VerificationRecord
+	for rows.Next() {
+		rec, err := scanRecord(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, rec)
+	}
+	return out, rows.Err()
+}
+
+func (p *PostgresStore) AddApplicantDocument(doc *dto.ApplicantDocument) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	_, err := p.db.ExecContext(ctx, `
+		INSERT INTO applicant_documents (applicant_id, tenant_id, client, doc_type, request_id, result, processed_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		doc.ApplicantID, doc.TenantID, doc.Client, doc.DocType, doc.RequestID, []byte(doc.Result), doc.ProcessedAt)
+	return err
+}
+
+func (p *PostgresStore) ApplicantDocuments(applicantID, tenantID, client string) ([]dto.ApplicantDocument, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT applicant_id, tenant_id, client, doc_type, request_id, result, processed_at FROM applicant_documents
+		WHERE applicant_id = $1 AND tenant_id = $2 AND client = $3
+		ORDER BY processed_at, id`,
+		applicantID, tenantID, client)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []dto.ApplicantDocument
+	for rows.Next() {
+		var doc dto.ApplicantDocument
+		var result []byte
+		if err := rows.Scan(&doc.ApplicantID, &doc.TenantID, &doc.Client, &doc.DocType, &doc.RequestID, &result, &doc.ProcessedAt); err != nil {
+			return nil, err
+		}
+		doc.Result = result
+		out = append(out, doc)
+	}
+	return out, rows.Err()
+}
+
+func (p *PostgresStore) SaveLayoutTemplate(tpl *dto.LayoutTemplate) error {
+	raw, err := json.Marshal(tpl)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	_, err = p.db.ExecContext(ctx, `
+		INSERT INTO layout_templates (id, doc_type, template, created_at) VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE SET doc_type = EXCLUDED.doc_type, template = EXCLUDED.template`,
+		tpl.ID, tpl.DocType, raw, tpl.CreatedAt)
+	return err
+}
+
+func (p *PostgresStore) LayoutTemplates(docType string) ([]dto.LayoutTemplate, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	rows, err := p.db.QueryContext(ctx, `
+		SELECT template FROM layout_templates WHERE ($1 = '' OR doc_type = $1) ORDER BY created_at, id`, docType)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []dto.LayoutTemplate
+	for rows.Next() {
+		var raw []byte
+		if err := rows.Scan(&raw); err != nil {
+			return nil, err
+		}
+		var tpl dto.LayoutTemplate
+		if err := json.Unmarshal(raw, &tpl); err != nil {
+			return nil, err
+		}
+		out = append(out, tpl)
+	}
+	return out, rows.Err()
+}
+
+func (p *PostgresStore) DeleteLayoutTemplate(id string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), postgresTimeout)
+	defer cancel()
+	res, err := p.db.ExecContext(ctx, `DELETE FROM layout_templates WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func scanRecord(row interface{ Scan(...any) error }) (*dto.VerificationRecord, error) {
+	var raw []byte
+	if err := row.Scan(&raw); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var rec dto.VerificationRecord
+	return &rec, json.Unmarshal(raw, &rec)
+}
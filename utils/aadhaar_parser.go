@@ -2,6 +2,7 @@ package utils
 
 import (
 	"regexp"
+	"slices"
 	"strings"
 	"unicode"
 
@@ -19,14 +20,27 @@ func ParseAadhaarFromText(text string) dto.AadhaarExtractResponse {
 	gender := extractGenderNearDOB(lines, dobIdx)
 	address := extractAddressBlock(lines)
 	aadhaarLast4 := extractAadhaarLast4(text)
+	number := extractAadhaarNumber(text)
+
+	// Masked cards and e-Aadhaar letters print "XXXX XXXX 1234"
+	var masked *bool
+	if m := aadhaarMaskedRe.FindStringSubmatch(text); number != "" || m != nil {
+		isMasked := number == ""
+		masked = &isMasked
+		if isMasked {
+			aadhaarLast4 = m[1]
+		}
+	}
 
 	return dto.AadhaarExtractResponse{
-		Name:         name,
-		DOB:          dob,
-		Gender:       gender,
-		Address:      address,
-		AadhaarLast4: aadhaarLast4,
-		Source:       "ocr",
+		Name:          name,
+		DOB:           dob,
+		Gender:        gender,
+		Address:       address,
+		AadhaarLast4:  aadhaarLast4,
+		AadhaarNumber: number,
+		Masked:        masked,
+		Source:        "ocr",
 	}
 }
 
@@ -213,12 +227,51 @@ func extractGenderNearDOB(lines []string, dobIdx int) string {
 
 // ---------------- Aadhaar last 4 ----------------
 
+// aadhaarGroupsRe matches a number printed on one line as groups of four
+// digits: the Aadhaar number's three, or a VID's four.
+var aadhaarGroupsRe = regexp.MustCompile(`\b\d{4}(?:[ \t]+\d{4}){2,3}\b`)
+
+// aadhaarMaskedRe matches a masked number: the first eight digits printed as
+// X (or * by some apps).
+var aadhaarMaskedRe = regexp.MustCompile(`[Xx*]{4}[ \t-]*[Xx*]{4}[ \t-]*(\d{4})\b`)
+
+// aadhaarCandidates returns the 12-digit numbers printed as three groups of
+// four, the first valid Aadhaar (Verhoeff checksum) first. A run of four
+// groups is a VID, but OCR may have merged it with the Aadhaar number on the
+// same line, so both of its three-group spans are candidates.
+func aadhaarCandidates(text string) []string {
+	var valid, invalid []string
+	for _, run := range aadhaarGroupsRe.FindAllString(text, -1) {
+		groups := strings.Fields(run)
+		for i := 0; i+3 <= len(groups); i++ {
+			n := strings.Join(groups[i:i+3], "")
+			if ValidAadhaar(n) {
+				valid = append(valid, n)
+			} else if len(groups) == 3 {
+				invalid = append(invalid, n)
+			}
+		}
+	}
+	return append(valid, invalid...)
+}
+
+// FindAadhaarNumbers returns the Aadhaar numbers printed in text that pass
+// the Verhoeff checksum, in order.
+func FindAadhaarNumbers(text string) []string {
+	var found []string
+	for _, n := range aadhaarCandidates(text) {
+		if ValidAadhaar(n) && !slices.Contains(found, n) {
+			found = append(found, n)
+		}
+	}
+	return found
+}
+
 func extractAadhaarLast4(text string) string {
-	// Prefer a 12-digit Aadhaar number (3 groups of 4 digits)
-	// e.g., "6260 7951 8316"
-	reAadhaar := regexp.MustCompile(`\b(\d{4})\s+(\d{4})\s+(\d{4})\b`)
-	if m := reAadhaar.FindStringSubmatch(text); len(m) == 4 {
-		return m[3]
+	// The number with a valid checksum tells which group is the last four
+	// (e.g., "6260 7951 8316"); without one, the first three groups printed
+	if c := aadhaarCandidates(text); len(c) > 0 {
+		return c[0][8:]
 	}
 
 	// Fallback: last 4 digits anywhere, but avoid obviously being part of VID
@@ -231,6 +284,53 @@ func extractAadhaarLast4(text string) string {
 	return all[len(all)-1][1]
 }
 
+// extractAadhaarNumber returns the full 12-digit number printed as three
+// groups of four ("6260 7951 8316"), or "" when no such number passes the
+// Verhoeff checksum: an OCR-mangled number is not reported.
+func extractAadhaarNumber(text string) string {
+	if c := aadhaarCandidates(text); len(c) > 0 && ValidAadhaar(c[0]) {
+		return c[0]
+	}
+	return ""
+}
+
+// AadhaarMaskBoxes locates the first eight digits of the Aadhaar number n
+// among OCR words, for masking the card image: a four-digit word reading
+// either group gives its whole box, a longer word holding the eight digits
+// (an engine reading the number, or the line, as one word) the part of its
+// box they take up, in proportion to the characters. Page is kept.
+func AadhaarMaskBoxes(words []dto.OCRWord, n string) []dto.OCRWord {
+	if len(n) != 12 {
+		return nil
+	}
+	var out []dto.OCRWord
+	for _, w := range words {
+		var digits []byte
+		var at []int // character index of each digit
+		chars := 0
+		for _, r := range w.Text {
+			if r >= '0' && r <= '9' {
+				digits = append(digits, byte(r))
+				at = append(at, chars)
+			}
+			chars++
+		}
+		switch ds := string(digits); {
+		case len(ds) == 4 && (ds == n[:4] || ds == n[4:8]):
+			out = append(out, w)
+		case strings.Contains(ds, n[:8]):
+			k := strings.Index(ds, n[:8])
+			from, to := at[k], at[k+7]+1
+			width := w.Box.X1 - w.Box.X0
+			part := w
+			part.Box.X0 = w.Box.X0 + width*from/chars
+			part.Box.X1 = w.Box.X0 + width*to/chars
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
 // ---------------- Address ----------------
 
 // extractAddressBlock reads lines starting from the line that contains "Address"
@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAadhaarMasked(t *testing.T) {
+	res := ParseAadhaarFromText("Anita Rao\nDOB: 05/06/1990\nFemale\nXXXX XXXX 8316\nVID : 9134 5678 9012 3456")
+	require.NotNil(t, res.Masked)
+	assert.True(t, *res.Masked)
+	assert.Equal(t, "8316", res.AadhaarLast4)
+	assert.Empty(t, res.AadhaarNumber)
+
+	res = ParseAadhaarFromText("Anita Rao\nDOB: 05/06/1990\n6260 7951 8316")
+	require.NotNil(t, res.Masked)
+	assert.False(t, *res.Masked)
+
+	assert.Nil(t, ParseAadhaarFromText("Anita Rao\nDOB: 05/06/1990").Masked)
+}
+
+func TestAadhaarMaskBoxes(t *testing.T) {
+	words := []dto.OCRWord{
+		{Text: "6260", Box: dto.BoundingBox{X0: 100, Y0: 50, X1: 140, Y1: 62}, Page: 1},
+		{Text: "7951", Box: dto.BoundingBox{X0: 150, Y0: 50, X1: 190, Y1: 62}, Page: 1},
+		{Text: "8316", Box: dto.BoundingBox{X0: 200, Y0: 50, X1: 240, Y1: 62}, Page: 1},
+		// the back side read as one line
+		{Text: "6260 7951 8316", Box: dto.BoundingBox{X0: 0, Y0: 10, X1: 140, Y1: 22}, Page: 2},
+	}
+	boxes := AadhaarMaskBoxes(words, "626079518316")
+	require.Len(t, boxes, 3)
+	assert.Equal(t, "6260", boxes[0].Text)
+	assert.Equal(t, "7951", boxes[1].Text)
+	assert.Equal(t, dto.BoundingBox{X0: 0, Y0: 10, X1: 90, Y1: 22}, boxes[2].Box, "the first nine of fourteen characters")
+	assert.Equal(t, 2, boxes[2].Page)
+}
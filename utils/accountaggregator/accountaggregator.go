@@ -0,0 +1,256 @@
+// Package accountaggregator reads the financial information (FI) data an
+// Account Aggregator delivers with the applicant's consent: deposit accounts
+// in the ReBIT schema, as JSON. Their transactions come from the bank itself,
+// so they stand in for an uploaded statement without OCR.
+//
+// The account may be sent as the FI document itself ({"Account": {...}}), or
+// as the AA's FI fetch response with each account's decrypted data
+// ({"FI": [{"fipID": ..., "data": [{"decryptedFI": {"account": {...}}}]}]}).
+// Field names are matched case-insensitively, as AAs render the schema's XML
+// names in either case.
+package accountaggregator
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// ErrNoDepositAccount is returned for JSON without a deposit account.
+var ErrNoDepositAccount = errors.New("no deposit account in the Account Aggregator data")
+
+// Source marks statements read from Account Aggregator data.
+const Source = "account_aggregator"
+
+// account is a deposit account in the ReBIT FI schema.
+type account struct {
+	Type            string `json:"type"`
+	MaskedAccNumber string `json:"maskedAccNumber"`
+	Profile         struct {
+		Holders struct {
+			Holder oneOrMany[holder] `json:"Holder"`
+		} `json:"Holders"`
+	} `json:"Profile"`
+	Summary struct {
+		Branch   string `json:"branch"`
+		IFSCCode string `json:"ifscCode"`
+	} `json:"Summary"`
+	Transactions struct {
+		StartDate   string                 `json:"startDate"`
+		EndDate     string                 `json:"endDate"`
+		Transaction oneOrMany[transaction] `json:"Transaction"`
+	} `json:"Transactions"`
+}
+
+type holder struct {
+	Name    string `json:"name"`
+	Address string `json:"address"`
+}
+
+type transaction struct {
+	Type                 string `json:"type"`
+	Amount               amount `json:"amount"`
+	CurrentBalance       amount `json:"currentBalance"`
+	TransactionTimestamp string `json:"transactionTimestamp"`
+	ValueDate            string `json:"valueDate"`
+	Narration            string `json:"narration"`
+}
+
+// oneOrMany is a list that JSON converted from XML gives as a bare object
+// when it has one element.
+type oneOrMany[T any] []T
+
+func (l *oneOrMany[T]) UnmarshalJSON(data []byte) error {
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		return json.Unmarshal(data, (*[]T)(l))
+	}
+	var one T
+	if err := json.Unmarshal(data, &one); err != nil {
+		return err
+	}
+	*l = oneOrMany[T]{one}
+	return nil
+}
+
+// amount is a decimal the schema gives as a string ("74050.00"); some AAs
+// send a number.
+type amount struct {
+	value float64
+	ok    bool
+}
+
+func (a *amount) UnmarshalJSON(data []byte) error {
+	s := strings.Trim(string(bytes.TrimSpace(data)), `"`)
+	s = strings.ReplaceAll(strings.TrimSpace(s), ",", "")
+	if s == "" || s == "null" {
+		return nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid amount %s", data)
+	}
+	*a = amount{value: f, ok: true}
+	return nil
+}
+
+// Statements converts every deposit account in data to a bank statement,
+// in the order they appear. Other FI types (term deposits, mutual funds, ...)
+// are skipped.
+func Statements(data []byte) ([]dto.BankStatementData, error) {
+	var doc interface{}
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), &doc); err != nil {
+		return nil, fmt.Errorf("invalid Account Aggregator JSON: %w", err)
+	}
+
+	var stmts []dto.BankStatementData
+	for _, raw := range findAccounts(doc) {
+		b, err := json.Marshal(raw)
+		if err != nil {
+			return nil, err
+		}
+		var acc account
+		if err := json.Unmarshal(b, &acc); err != nil {
+			return nil, fmt.Errorf("invalid Account Aggregator account: %w", err)
+		}
+		if acc.Type != "" && !strings.EqualFold(acc.Type, "deposit") {
+			continue
+		}
+		stmts = append(stmts, acc.statement())
+	}
+	if len(stmts) == 0 {
+		return nil, ErrNoDepositAccount
+	}
+	return stmts, nil
+}
+
+// findAccounts returns the account objects in a decoded JSON document, at
+// any depth: the objects with a masked account number and transactions.
+func findAccounts(v interface{}) []map[string]interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		var accounts []map[string]interface{}
+		for _, e := range v {
+			accounts = append(accounts, findAccounts(e)...)
+		}
+		return accounts
+	case map[string]interface{}:
+		if field(v, "maskedAccNumber") != nil && field(v, "Transactions") != nil {
+			return []map[string]interface{}{v}
+		}
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var accounts []map[string]interface{}
+		for _, k := range keys {
+			accounts = append(accounts, findAccounts(v[k])...)
+		}
+		return accounts
+	}
+	return nil
+}
+
+// field returns the value of key in m, matched case-insensitively.
+func field(m map[string]interface{}, key string) interface{} {
+	for k, v := range m {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (acc account) statement() dto.BankStatementData {
+	stmt := dto.BankStatementData{
+		AccountNumber: acc.MaskedAccNumber,
+		IFSC:          strings.ToUpper(strings.TrimSpace(acc.Summary.IFSCCode)),
+		BankBranch:    acc.Summary.Branch,
+		Transactions:  []dto.BankTransaction{},
+		Source:        Source,
+	}
+	// the first holder of a joint account is its primary holder
+	if len(acc.Profile.Holders.Holder) > 0 {
+		stmt.AccountHolderName = acc.Profile.Holders.Holder[0].Name
+		stmt.Address = acc.Profile.Holders.Holder[0].Address
+	}
+	if t, ok := parseDate(acc.Transactions.StartDate); ok {
+		stmt.PeriodFrom = &t
+	}
+	if t, ok := parseDate(acc.Transactions.EndDate); ok {
+		stmt.PeriodTo = &t
+	}
+
+	var prevBalance amount
+	for _, t := range acc.Transactions.Transaction {
+		tx, ok := t.bankTransaction(prevBalance)
+		if t.CurrentBalance.ok {
+			prevBalance = t.CurrentBalance
+		}
+		if ok {
+			stmt.Transactions = append(stmt.Transactions, tx)
+		}
+	}
+	return stmt
+}
+
+// bankTransaction converts t; opening and closing balance entries, and
+// entries without a date or amount, are not transactions. prevBalance is the
+// balance after the entry before, to tell the direction of an OTHERS entry.
+func (t transaction) bankTransaction(prevBalance amount) (dto.BankTransaction, bool) {
+	date, ok := parseDate(t.TransactionTimestamp)
+	if !ok {
+		date, ok = parseDate(t.ValueDate)
+	}
+	if !ok || !t.Amount.ok || t.Amount.value == 0 {
+		return dto.BankTransaction{}, false
+	}
+
+	tx := dto.BankTransaction{
+		Date:        date,
+		Description: strings.TrimSpace(t.Narration),
+		Amount:      abs(t.Amount.value),
+		Balance:     t.CurrentBalance.value,
+	}
+	switch strings.ToUpper(t.Type) {
+	case "CREDIT", "INTEREST":
+		tx.IsCredit = true
+	case "DEBIT", "TDS", "INSTALLMENT":
+		tx.IsCredit = false
+	case "OPENING", "CLOSING":
+		return dto.BankTransaction{}, false
+	default:
+		if !prevBalance.ok || !t.CurrentBalance.ok {
+			return dto.BankTransaction{}, false
+		}
+		tx.IsCredit = t.CurrentBalance.value > prevBalance.value
+	}
+	return tx, true
+}
+
+// parseDate reads a schema date ("2024-02-01") or timestamp
+// ("2024-02-01T10:42:13+05:30") as the calendar date it names, at midnight
+// UTC like the dates of OCRed statements.
+func parseDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
@@ -0,0 +1,87 @@
+package accountaggregator
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const depositFI = `{
+  "Account": {
+    "type": "deposit",
+    "maskedAccNumber": "XXXXXXXX6789",
+    "version": "1.1",
+    "linkedAccRef": "a1b2c3d4",
+    "Profile": {"Holders": {"type": "JOINT", "Holder": [
+      {"name": "RAVI KUMAR", "dob": "1990-04-12", "mobile": "9876543210", "address": "12 MG Road, Bengaluru 560001", "pan": "ABCPK1234F"},
+      {"name": "PRIYA KUMAR"}
+    ]}},
+    "Summary": {"currentBalance": "81600.00", "currency": "INR", "type": "SAVINGS", "branch": "MG Road", "ifscCode": "hdfc0001234", "status": "ACTIVE"},
+    "Transactions": {"startDate": "2024-02-01", "endDate": "2024-02-29", "Transaction": [
+      {"type": "OPENING", "amount": "10000.00", "currentBalance": "10000.00", "valueDate": "2024-02-01"},
+      {"type": "CREDIT", "mode": "FT", "amount": "74050.00", "currentBalance": "84050.00", "transactionTimestamp": "2024-02-01T23:40:00+05:30", "valueDate": "2024-02-02", "txnId": "N032240012", "narration": "NEFT CR-ACME TECHNOLOGIES PVT LTD-SALARY FEB"},
+      {"type": "DEBIT", "mode": "UPI", "amount": 450, "currentBalance": "83600.00", "transactionTimestamp": "2024-02-03T09:15:00+05:30", "narration": "UPI/412345678901/SHARMA KIRANA/sharmakirana@okaxis"},
+      {"type": "OTHERS", "mode": "ATM", "amount": "2,000.00", "currentBalance": "81600.00", "valueDate": "2024-02-05", "narration": "ATM WDL MUMBAI"}
+    ]}
+  }
+}`
+
+func TestStatements(t *testing.T) {
+	stmts, err := Statements([]byte(depositFI))
+	require.NoError(t, err)
+	require.Len(t, stmts, 1)
+	stmt := stmts[0]
+
+	assert.Equal(t, "XXXXXXXX6789", stmt.AccountNumber)
+	assert.Equal(t, "RAVI KUMAR", stmt.AccountHolderName, "the primary holder")
+	assert.Equal(t, "HDFC0001234", stmt.IFSC)
+	assert.Equal(t, "MG Road", stmt.BankBranch)
+	assert.Equal(t, Source, stmt.Source)
+	require.NotNil(t, stmt.PeriodTo)
+	assert.Equal(t, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), *stmt.PeriodTo)
+
+	require.Len(t, stmt.Transactions, 3, "the opening balance is not a transaction")
+	salary := stmt.Transactions[0]
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), salary.Date, "the date of the timestamp, in its own zone")
+	assert.True(t, salary.IsCredit)
+	assert.Equal(t, 74050.0, salary.Amount)
+	assert.Equal(t, 84050.0, salary.Balance)
+
+	assert.False(t, stmt.Transactions[1].IsCredit)
+	assert.Equal(t, 450.0, stmt.Transactions[1].Amount, "amounts may be numbers")
+	assert.False(t, stmt.Transactions[2].IsCredit, "the balance fell")
+	assert.Equal(t, 2000.0, stmt.Transactions[2].Amount)
+}
+
+func TestStatementsFromFIFetch(t *testing.T) {
+	// an FI fetch response, lower-case keys, single elements as objects
+	fetch := `{"ver": "2.0.0", "txnid": "f35761ac", "FI": [
+	  {"fipID": "HDFC-FIP", "data": [
+	    {"linkRefNumber": "l1", "maskedAccNumber": "XXXX1111", "decryptedFI": {"account": {"type": "deposit", "maskedAccNumber": "XXXX1111",
+	      "profile": {"holders": {"holder": {"name": "RAVI KUMAR"}}},
+	      "transactions": {"transaction": {"type": "CREDIT", "amount": "74050.00", "valueDate": "2024-02-01", "narration": "SALARY"}}}}},
+	    {"linkRefNumber": "l2", "maskedAccNumber": "XXXX2222", "decryptedFI": {"account": {"type": "term_deposit", "maskedAccNumber": "XXXX2222", "transactions": {}}}}
+	  ]},
+	  {"fipID": "SBI-FIP", "data": [
+	    {"linkRefNumber": "l3", "maskedAccNumber": "XXXX3333", "decryptedFI": {"account": {"type": "deposit", "maskedAccNumber": "XXXX3333", "transactions": {"transaction": []}}}}
+	  ]}
+	]}`
+	stmts, err := Statements([]byte(fetch))
+	require.NoError(t, err)
+	require.Len(t, stmts, 2, "term deposits are skipped")
+	assert.Equal(t, "XXXX1111", stmts[0].AccountNumber)
+	assert.Equal(t, "RAVI KUMAR", stmts[0].AccountHolderName)
+	require.Len(t, stmts[0].Transactions, 1)
+	assert.True(t, stmts[0].Transactions[0].IsCredit)
+	assert.Equal(t, "XXXX3333", stmts[1].AccountNumber)
+	assert.Empty(t, stmts[1].Transactions)
+}
+
+func TestStatementsWithoutDepositAccount(t *testing.T) {
+	_, err := Statements([]byte(`{"consentId": "c1", "status": "ACTIVE"}`))
+	assert.ErrorIs(t, err, ErrNoDepositAccount)
+	_, err = Statements([]byte(`Date,Narration`))
+	assert.Error(t, err)
+}
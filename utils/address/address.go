@@ -0,0 +1,223 @@
+// Package address splits Indian postal addresses read from ID documents into
+// their components, validates PIN codes against an embedded prefix table and
+// scores how similar two addresses are.
+package address
+
+import (
+	"bufio"
+	_ "embed"
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// MatchThreshold is the Similarity from which two addresses are taken to be
+// the same.
+const MatchThreshold = 0.6
+
+//go:embed pincodes.csv
+var pincodeCSV string
+
+// PincodeInfo is what the PIN code table knows about a PIN code.
+type PincodeInfo struct {
+	District string // empty when the prefix spans several districts
+	State    string
+}
+
+// pincodes maps PIN code prefixes (2, 3 or 6 digits) to their area.
+var pincodes = loadPincodes(pincodeCSV)
+
+func loadPincodes(csv string) map[string]PincodeInfo {
+	table := map[string]PincodeInfo{}
+	sc := bufio.NewScanner(strings.NewReader(csv))
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, ",")
+		if len(fields) != 3 {
+			panic("address: malformed pincodes.csv line " + line)
+		}
+		table[fields[0]] = PincodeInfo{District: fields[1], State: fields[2]}
+	}
+	return table
+}
+
+var pincodeRe = regexp.MustCompile(`^[1-8]\d{5}$`)
+
+// LookupPincode returns the area of a 6-digit PIN code by its longest known
+// prefix; ok is false for a malformed or unassigned PIN code.
+func LookupPincode(pin string) (PincodeInfo, bool) {
+	if !pincodeRe.MatchString(pin) {
+		return PincodeInfo{}, false
+	}
+	for _, n := range []int{6, 3, 2} {
+		if info, ok := pincodes[pin[:n]]; ok {
+			return info, true
+		}
+	}
+	return PincodeInfo{}, false
+}
+
+// states are the states and union territories, with spellings seen on cards.
+var states = map[string]string{
+	"andhra pradesh": "Andhra Pradesh", "arunachal pradesh": "Arunachal Pradesh",
+	"assam": "Assam", "bihar": "Bihar", "chhattisgarh": "Chhattisgarh", "chattisgarh": "Chhattisgarh",
+	"goa": "Goa", "gujarat": "Gujarat", "haryana": "Haryana", "himachal pradesh": "Himachal Pradesh",
+	"jharkhand": "Jharkhand", "karnataka": "Karnataka", "kerala": "Kerala",
+	"madhya pradesh": "Madhya Pradesh", "maharashtra": "Maharashtra", "manipur": "Manipur",
+	"meghalaya": "Meghalaya", "mizoram": "Mizoram", "nagaland": "Nagaland",
+	"odisha": "Odisha", "orissa": "Odisha", "punjab": "Punjab", "rajasthan": "Rajasthan",
+	"sikkim": "Sikkim", "tamil nadu": "Tamil Nadu", "tamilnadu": "Tamil Nadu",
+	"telangana": "Telangana", "tripura": "Tripura", "uttar pradesh": "Uttar Pradesh",
+	"uttarakhand": "Uttarakhand", "uttaranchal": "Uttarakhand", "west bengal": "West Bengal",
+	"delhi": "Delhi", "nct of delhi": "Delhi", "new delhi": "Delhi",
+	"chandigarh": "Chandigarh", "puducherry": "Puducherry", "pondicherry": "Puducherry",
+	"jammu and kashmir": "Jammu and Kashmir", "jammu & kashmir": "Jammu and Kashmir",
+	"ladakh": "Ladakh", "lakshadweep": "Lakshadweep",
+	"andaman and nicobar islands":              "Andaman and Nicobar Islands",
+	"dadra and nagar haveli and daman and diu": "Dadra and Nagar Haveli and Daman and Diu",
+}
+
+var (
+	pinInTextRe  = regexp.MustCompile(`\b([1-8]\d{2})\s?(\d{3})\b`)
+	careOfRe     = regexp.MustCompile(`(?i)^(c|s|d|w)\s*/\s*o\b|^(care of|son of|daughter of|wife of)\b`)
+	districtRe   = regexp.MustCompile(`(?i)^(dist(rict)?|distt)\.?\s*[:\-]?\s*`)
+	postOfficeRe = regexp.MustCompile(`(?i)^(po|p\.o\.?|post office|post)\s*[:\-]?\s+`)
+	houseRe      = regexp.MustCompile(`(?i)^(h\.?\s*no|house|flat|plot|door|d\.?\s*no|no)\b|^#|\d`)
+	houseFirstRe = regexp.MustCompile(`^(\S*\d\S*)\s+(.+)$`)
+	streetRe     = regexp.MustCompile(`(?i)\b(road|rd|street|st|marg|lane|ln|gali|cross|main|path|avenue|highway)\b`)
+)
+
+// Parse splits an address as printed on an Aadhaar card or driving licence
+// ("C/O ..., house, street, locality, city, District, State - PIN") into its
+// components. Care-of names are dropped. District and state missing from the
+// text are taken from the PIN code; PincodeValid reports whether the PIN code
+// is known and agrees with the stated state.
+func Parse(s string) dto.Address {
+	var addr dto.Address
+	text := strings.Join(strings.Fields(s), " ")
+	if m := pinInTextRe.FindAllStringSubmatch(text, -1); len(m) > 0 {
+		last := m[len(m)-1]
+		addr.Pincode = last[1] + last[2]
+		text = strings.Replace(text, last[0], "", 1)
+	}
+
+	var rest []string
+	for _, seg := range strings.Split(text, ",") {
+		seg = strings.Trim(seg, " -.:")
+		if seg == "" || careOfRe.MatchString(seg) {
+			continue
+		}
+		if st, ok := states[strings.ToLower(seg)]; ok && addr.State == "" {
+			addr.State = st
+			continue
+		}
+		if loc := districtRe.ReplaceAllString(seg, ""); loc != seg {
+			addr.District = loc
+			continue
+		}
+		if po := postOfficeRe.ReplaceAllString(seg, ""); po != seg {
+			// the post office names the locality
+			rest = append(rest, po)
+			continue
+		}
+		rest = append(rest, seg)
+	}
+
+	var locality []string
+	for i, seg := range rest {
+		switch {
+		case i == len(rest)-1 && i > 0:
+			addr.City = seg
+		case addr.House == "" && addr.Street == "" && len(locality) == 0 && houseRe.MatchString(seg):
+			// "12 MG Road" is a house number and a street
+			if m := houseFirstRe.FindStringSubmatch(seg); m != nil && streetRe.MatchString(m[2]) {
+				addr.House, addr.Street = m[1], m[2]
+			} else {
+				addr.House = seg
+			}
+		case addr.Street == "" && len(locality) == 0 && streetRe.MatchString(seg):
+			addr.Street = seg
+		default:
+			locality = append(locality, seg)
+		}
+	}
+	addr.Locality = strings.Join(locality, ", ")
+
+	validatePincode(&addr)
+	return addr
+}
+
+func validatePincode(addr *dto.Address) {
+	if addr.Pincode == "" {
+		addr.Issues = append(addr.Issues, "no PIN code")
+		return
+	}
+	info, ok := LookupPincode(addr.Pincode)
+	if !ok {
+		addr.Issues = append(addr.Issues, "unknown PIN code "+addr.Pincode)
+		return
+	}
+	addr.PincodeValid = true
+	if addr.State == "" {
+		addr.State = info.State
+	} else if addr.State != info.State {
+		addr.PincodeValid = false
+		addr.Issues = append(addr.Issues, "PIN code "+addr.Pincode+" is in "+info.State+", not "+addr.State)
+	}
+	if addr.District == "" {
+		addr.District = info.District
+	}
+}
+
+// Similarity scores two parsed addresses, 0..1: the share of the shorter
+// address's words found in the other, raised when the PIN codes agree.
+// Addresses in different PIN codes score 0.
+func Similarity(a, b dto.Address) float64 {
+	if a.Pincode != "" && b.Pincode != "" && a.Pincode != b.Pincode {
+		return 0
+	}
+
+	ta, tb := words(a), words(b)
+	if len(ta) > len(tb) {
+		ta, tb = tb, ta
+	}
+	if len(ta) == 0 {
+		return 0
+	}
+	common := 0
+	for w := range ta {
+		if tb[w] {
+			common++
+		}
+	}
+	score := float64(common) / float64(len(ta))
+	if a.Pincode != "" && a.Pincode == b.Pincode {
+		score = 0.3 + 0.7*score
+	}
+	return score
+}
+
+var wordRe = regexp.MustCompile(`[a-z0-9]+`)
+
+// stopWords are address words that carry no location.
+var stopWords = map[string]bool{
+	"near": true, "opp": true, "behind": true, "no": true, "h": true, "house": true,
+	"flat": true, "plot": true, "road": true, "rd": true, "street": true, "st": true,
+}
+
+// words are the distinct words of the house, street, locality and city.
+func words(a dto.Address) map[string]bool {
+	out := map[string]bool{}
+	for _, part := range []string{a.House, a.Street, a.Locality, a.City} {
+		for _, w := range wordRe.FindAllString(strings.ToLower(part), -1) {
+			if !stopWords[w] {
+				out[w] = true
+			}
+		}
+	}
+	return out
+}
@@ -0,0 +1,61 @@
+package address
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	a := Parse("C/O Suresh Kumar, 12 MG Road, Indiranagar, Bengaluru, Karnataka, 560038")
+	assert.Equal(t, "12", a.House)
+	assert.Equal(t, "MG Road", a.Street)
+	assert.Equal(t, "Indiranagar", a.Locality)
+	assert.Equal(t, "Bengaluru", a.City)
+	assert.Equal(t, "Bengaluru Urban", a.District) // from the PIN code
+	assert.Equal(t, "Karnataka", a.State)
+	assert.Equal(t, "560038", a.Pincode)
+	assert.True(t, a.PincodeValid)
+
+	a = Parse("H.No 4-2-11, Gandhi Nagar, PO Kothapet, Dist: Guntur, Andhra Pradesh - 522 001")
+	assert.Equal(t, "H.No 4-2-11", a.House)
+	assert.Equal(t, "Gandhi Nagar", a.Locality)
+	assert.Equal(t, "Kothapet", a.City)
+	assert.Equal(t, "Guntur", a.District)
+	assert.Equal(t, "522001", a.Pincode)
+	assert.True(t, a.PincodeValid)
+
+	// state and PIN code disagree
+	a = Parse("Flat 3, Lake Road, Kolkata, Maharashtra 700029")
+	assert.False(t, a.PincodeValid)
+	assert.Contains(t, a.Issues, "PIN code 700029 is in West Bengal, not Maharashtra")
+
+	assert.Contains(t, Parse("Lake Road, Kolkata").Issues, "no PIN code")
+}
+
+func TestLookupPincode(t *testing.T) {
+	info, ok := LookupPincode("400050")
+	assert.True(t, ok)
+	assert.Equal(t, PincodeInfo{District: "Mumbai", State: "Maharashtra"}, info)
+
+	info, ok = LookupPincode("834001")
+	assert.True(t, ok)
+	assert.Equal(t, "Jharkhand", info.State)
+
+	for _, pin := range []string{"012345", "912345", "56003", "abcdef"} {
+		_, ok := LookupPincode(pin)
+		assert.False(t, ok, pin)
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	aadhaar := Parse("C/O Suresh Kumar, 12 MG Road, Indiranagar, Bengaluru, Karnataka, 560038")
+	statement := Parse("12, MG Road, Indiranagar, Bengaluru 560038")
+	assert.GreaterOrEqual(t, Similarity(aadhaar, statement), MatchThreshold)
+
+	other := Parse("45, Brigade Road, Ashok Nagar, Bengaluru 560025")
+	assert.Equal(t, 0.0, Similarity(aadhaar, other))
+
+	noPin := Parse("221B, Baker Street, Koramangala, Bengaluru")
+	assert.Less(t, Similarity(aadhaar, noPin), MatchThreshold)
+}
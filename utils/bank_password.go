@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// BankPassword is a candidate password of a protected statement and the
+// format it was built in (for logs: never log the password itself).
+type BankPassword struct {
+	Format   string
+	Password string
+}
+
+// BankPasswords builds the passwords Indian banks commonly protect e-statements
+// with from the account holder's details, most common formats first: the PAN,
+// the date of birth (DDMMYYYY, DDMMYY), the mobile number, the first four
+// letters of the name with the day and month of birth or the last four digits
+// of the mobile number, and the last five digits of the mobile number with
+// DDMMYY. Formats whose details are missing or unreadable are skipped;
+// duplicates are dropped.
+func BankPasswords(h dto.PasswordHints) []BankPassword {
+	var out []BankPassword
+	seen := map[string]bool{}
+	add := func(format, password string) {
+		if !seen[password] {
+			seen[password] = true
+			out = append(out, BankPassword{Format: format, Password: password})
+		}
+	}
+
+	pan := strings.ToUpper(strings.TrimSpace(h.PAN))
+	if pan != "" {
+		add("PAN", pan)
+		add("pan", strings.ToLower(pan))
+	}
+
+	var ddmmyyyy, ddmmyy, ddmm string
+	if iso, ok := NormalizeDOB(h.DOB); ok && len(iso) == len("2006-01-02") {
+		ddmm = iso[8:10] + iso[5:7]
+		ddmmyyyy, ddmmyy = ddmm+iso[:4], ddmm+iso[2:4]
+		add("DDMMYYYY", ddmmyyyy)
+		add("DDMMYY", ddmmyy)
+	}
+
+	phone := mobileNumber(h.Phone)
+	if phone != "" {
+		add("mobile", phone)
+	}
+
+	name := namePrefix(h.Name, 4)
+	if name != "" {
+		if ddmm != "" {
+			add("name4+DDMM", strings.ToLower(name)+ddmm)
+			add("NAME4+DDMM", strings.ToUpper(name)+ddmm)
+		}
+		if phone != "" {
+			add("name4+mobile4", strings.ToLower(name)+phone[6:])
+			add("NAME4+mobile4", strings.ToUpper(name)+phone[6:])
+		}
+	}
+	if phone != "" && ddmmyy != "" {
+		add("mobile5+DDMMYY", phone[5:]+ddmmyy)
+	}
+	if pan != "" && ddmmyyyy != "" {
+		add("PAN+DDMMYYYY", pan+ddmmyyyy)
+	}
+	return out
+}
+
+// mobileNumber is the 10-digit Indian mobile number in s, without a +91 or 0
+// prefix; empty when s has none.
+func mobileNumber(s string) string {
+	var digits strings.Builder
+	for _, r := range s {
+		if r >= '0' && r <= '9' {
+			digits.WriteRune(r)
+		}
+	}
+	d := digits.String()
+	switch {
+	case len(d) == 12 && strings.HasPrefix(d, "91"):
+		d = d[2:]
+	case len(d) == 11 && d[0] == '0':
+		d = d[1:]
+	}
+	if len(d) != 10 {
+		return ""
+	}
+	return d
+}
+
+// namePrefix is the first n letters of the first name; empty when it is
+// shorter.
+func namePrefix(name string, n int) string {
+	var letters []rune
+	for _, r := range strings.TrimSpace(name) {
+		if !unicode.IsLetter(r) {
+			break
+		}
+		letters = append(letters, r)
+	}
+	if len(letters) < n {
+		return ""
+	}
+	return string(letters[:n])
+}
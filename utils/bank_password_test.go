@@ -0,0 +1,33 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestBankPasswords(t *testing.T) {
+	got := map[string]string{}
+	for _, p := range BankPasswords(dto.PasswordHints{PAN: "abcpk1234f", DOB: "14/08/1990", Phone: "+91 98765 43210", Name: "Ravi Kumar"}) {
+		got[p.Format] = p.Password
+	}
+	assert.Equal(t, map[string]string{
+		"PAN":            "ABCPK1234F",
+		"pan":            "abcpk1234f",
+		"DDMMYYYY":       "14081990",
+		"DDMMYY":         "140890",
+		"mobile":         "9876543210",
+		"name4+DDMM":     "ravi1408",
+		"NAME4+DDMM":     "RAVI1408",
+		"name4+mobile4":  "ravi3210",
+		"NAME4+mobile4":  "RAVI3210",
+		"mobile5+DDMMYY": "43210140890",
+		"PAN+DDMMYYYY":   "ABCPK1234F14081990",
+	}, got)
+
+	assert.Equal(t, []BankPassword{{Format: "mobile", Password: "9876543210"}},
+		BankPasswords(dto.PasswordHints{Phone: "09876543210", DOB: "not a date", Name: "Al"}))
+	assert.Empty(t, BankPasswords(dto.PasswordHints{}))
+}
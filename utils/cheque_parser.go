@@ -0,0 +1,50 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	chequeAccountLabel = regexp.MustCompile(`(?i)\b(?:a/?c|account)\s*(?:no\.?|number|#)\s*[:.\-]?\s*(\d[\d ]{7,22}\d)`)
+	// the drawer's name is printed just above the signature line
+	chequeSignLine  = regexp.MustCompile(`(?i)please\s+sign\s+above|authori[sz]ed\s+signator|signature`)
+	chequeForPrefix = regexp.MustCompile(`(?i)^for\s+`)
+	chequeNameLine  = regexp.MustCompile(`^[A-Za-z][A-Za-z .&'()]{2,60}$`)
+	chequeCancelled = regexp.MustCompile(`(?i)\bcancell?ed\b`)
+)
+
+// ParseCheque reads the account holder, account number, IFSC and bank from
+// the printed text of a cheque leaf. The MICR line is read separately (see
+// ParseMICR) since it needs its own OCR pass.
+func ParseCheque(text string) dto.ChequeResponse {
+	res := dto.ChequeResponse{
+		IFSC:      ExtractIFSC(text),
+		Cancelled: chequeCancelled.MatchString(text),
+	}
+	res.BankName = ExtractBankName(text, res.IFSC)
+
+	if m := chequeAccountLabel.FindStringSubmatch(text); m != nil {
+		res.AccountNumber = strings.ReplaceAll(m[1], " ", "")
+	}
+
+	lines := splitAndTrimLines(text)
+	for i, l := range lines {
+		if !chequeSignLine.MatchString(l) {
+			continue
+		}
+		for j := i - 1; j >= 0 && j >= i-2; j-- {
+			name := chequeForPrefix.ReplaceAllString(lines[j], "")
+			if chequeNameLine.MatchString(name) && !chequeCancelled.MatchString(name) {
+				res.AccountHolderName = strings.TrimSpace(name)
+				break
+			}
+		}
+		if res.AccountHolderName != "" {
+			break
+		}
+	}
+	return res
+}
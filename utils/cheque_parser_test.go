@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCheque(t *testing.T) {
+	text := `HDFC BANK
+We understand your world
+Branch: Koramangala, Bengaluru
+RTGS/NEFT IFSC: HDFC0001234
+Pay
+Rupees
+A/c No. 50100123456789
+CANCELLED
+RAVI KUMAR
+Please sign above
+⑈004512⑈ 560240015⑆ 123456⑈ 31`
+
+	cheque := ParseCheque(text)
+	assert.Equal(t, "RAVI KUMAR", cheque.AccountHolderName)
+	assert.Equal(t, "50100123456789", cheque.AccountNumber)
+	assert.Equal(t, "HDFC0001234", cheque.IFSC)
+	assert.Equal(t, "HDFC Bank", cheque.BankName)
+	assert.True(t, cheque.Cancelled)
+}
+
+func TestParseChequeCompany(t *testing.T) {
+	text := `ICICI Bank
+IFSC Code: ICIC0000104
+A/C NO: 0104 0500 1234
+For ACME SOFTWARE PVT LTD
+Authorised Signatories`
+
+	cheque := ParseCheque(text)
+	assert.Equal(t, "ACME SOFTWARE PVT LTD", cheque.AccountHolderName)
+	assert.Equal(t, "010405001234", cheque.AccountNumber)
+	assert.False(t, cheque.Cancelled)
+}
+
+func TestParseMICR(t *testing.T) {
+	tests := []struct {
+		name, text                                  string
+		cheque, micr, base, txn, city, bank, branch string
+	}{
+		{"unicode symbols", "⑈004512⑈ 560240015⑆ 123456⑈ 31", "004512", "560240015", "123456", "31", "560", "240", "015"},
+		{"e13b model", "C004512C 560240015A 123456C 31", "004512", "560240015", "123456", "31", "560", "240", "015"},
+		{"symbols misread", "|:004512|: 56O240015|' 123456|: 3l", "004512", "560240015", "123456", "31", "560", "240", "015"},
+		{"no base account", "⑈004512⑈ 400002105⑆ 29", "004512", "400002105", "", "", "400", "002", "105"},
+		{"digits run together", "00451256024001512345631", "004512", "560240015", "123456", "31", "560", "240", "015"},
+		{"below other text", "Please sign above\n⑈004512⑈ 560240015⑆ 123456⑈ 31", "004512", "560240015", "123456", "31", "560", "240", "015"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line, ok := ParseMICR(tt.text)
+			assert.True(t, ok)
+			assert.Equal(t, tt.cheque, line.ChequeNumber)
+			assert.Equal(t, tt.micr, line.MICRCode)
+			assert.Equal(t, tt.base, line.BaseAccount)
+			assert.Equal(t, tt.txn, line.TransactionCode)
+			assert.Equal(t, tt.city, line.CityCode)
+			assert.Equal(t, tt.bank, line.BankCode)
+			assert.Equal(t, tt.branch, line.BranchCode)
+		})
+	}
+
+	_, ok := ParseMICR("A/c No. 50100123456789\nDate 12/05/2025")
+	assert.False(t, ok)
+}
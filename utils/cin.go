@@ -0,0 +1,19 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// A CIN is the listing status (L/U), 5-digit industry code, state, year of
+// incorporation, ownership class and registration number, e.g.
+// U72200KA2009PTC049889.
+var cinRegex = regexp.MustCompile(`\b([LU][0-9]{5}[A-Z]{2}[0-9]{4}[A-Z]{3}[0-9]{6})\b`)
+
+// ExtractCIN returns the first corporate identity number in text, or "".
+func ExtractCIN(text string) string {
+	if m := cinRegex.FindStringSubmatch(strings.ToUpper(text)); m != nil {
+		return m[1]
+	}
+	return ""
+}
@@ -0,0 +1,12 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractCIN(t *testing.T) {
+	assert.Equal(t, "U72200KA2009PTC049889", ExtractCIN("Acme Software Pvt Ltd\nCIN: u72200ka2009ptc049889\nPayslip"))
+	assert.Equal(t, "", ExtractCIN("GSTIN 29ABCDE1234F1Z5"))
+}
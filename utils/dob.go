@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dobDigitFix maps letters OCR confuses with digits inside a numeric date part.
+var dobDigitFix = strings.NewReplacer(
+	"O", "0", "o", "0", "Q", "0", "D", "0",
+	"I", "1", "l", "1", "i", "1", "|", "1", "!", "1",
+	"Z", "2", "z", "2", "S", "5", "s", "5", "B", "8", "G", "6", "b", "6",
+)
+
+var dobMonths = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March, "apr": time.April,
+	"may": time.May, "jun": time.June, "jul": time.July, "aug": time.August,
+	"sep": time.September, "oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+var dobSeparators = regexp.MustCompile(`[\s/\-.,]+`)
+
+// NormalizeDOB turns a date of birth as OCR read it into ISO 8601: "YYYY-MM-DD",
+// or "YYYY" when the document only gives the year of birth (older Aadhaar
+// cards). It accepts day-first numeric dates with / - . or space separators
+// ("14/08/1990", "14.08.90"), ISO dates, month names ("14 Aug 1990",
+// "14-AUG-1990") and undelimited "14081990", and repairs letters misread for
+// digits (O→0, l→1, S→5 ...). ok is false when s is not a plausible date of
+// birth: malformed, before 1900 or in the future.
+func NormalizeDOB(s string) (iso string, ok bool) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return "", false
+	}
+
+	parts := dobSeparators.Split(strings.Trim(s, " /-.,"), -1)
+	var day, month, year int
+	switch len(parts) {
+	case 1:
+		digits, isNum := dobNumber(parts[0])
+		switch {
+		case !isNum:
+			return "", false
+		case len(digits) == 4:
+			y, _ := strconv.Atoi(digits)
+			if y < 1900 || y > time.Now().Year() {
+				return "", false
+			}
+			return digits, true
+		case len(digits) == 8:
+			parts = []string{digits[:2], digits[2:4], digits[4:]}
+		default:
+			return "", false
+		}
+	case 3:
+	default:
+		return "", false
+	}
+
+	// year first (ISO) or day first
+	first, _ := dobNumber(parts[0])
+	if len(first) == 4 {
+		parts[0], parts[2] = parts[2], parts[0]
+	}
+
+	var isNum bool
+	var digits string
+	if digits, isNum = dobNumber(parts[0]); !isNum || len(digits) > 2 {
+		return "", false
+	}
+	day, _ = strconv.Atoi(digits)
+
+	if m, named := dobMonths[strings.ToLower(firstN(parts[1], 3))]; named && len(parts[1]) >= 3 {
+		month = int(m)
+	} else if digits, isNum = dobNumber(parts[1]); isNum && len(digits) <= 2 {
+		month, _ = strconv.Atoi(digits)
+	} else {
+		return "", false
+	}
+
+	digits, isNum = dobNumber(parts[2])
+	switch {
+	case !isNum:
+		return "", false
+	case len(digits) == 4:
+		year, _ = strconv.Atoi(digits)
+	case len(digits) == 2:
+		// two-digit years: the most recent past year
+		year, _ = strconv.Atoi(digits)
+		year += 2000
+		if year > time.Now().Year() {
+			year -= 100
+		}
+	default:
+		return "", false
+	}
+
+	t := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+	if t.Day() != day || int(t.Month()) != month || year < 1900 || t.After(time.Now()) {
+		return "", false
+	}
+	return t.Format("2006-01-02"), true
+}
+
+// dobNumber repairs OCR confusions in a numeric date part and reports whether
+// the result is all digits.
+func dobNumber(part string) (string, bool) {
+	fixed := dobDigitFix.Replace(part)
+	if fixed == "" {
+		return "", false
+	}
+	for _, r := range fixed {
+		if r < '0' || r > '9' {
+			return fixed, false
+		}
+	}
+	return fixed, true
+}
+
+func firstN(s string, n int) string {
+	if len(s) < n {
+		return s
+	}
+	return s[:n]
+}
+
+// SameDOB compares two normalized dates of birth. A year-only date matches
+// any date in that year.
+func SameDOB(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+	if len(a) == 4 || len(b) == 4 {
+		return a[:4] == b[:4]
+	}
+	return a == b
+}
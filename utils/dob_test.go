@@ -0,0 +1,35 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeDOB(t *testing.T) {
+	for in, want := range map[string]string{
+		"14/08/1990":  "1990-08-14",
+		"14-08-1990":  "1990-08-14",
+		"14.08.90":    "1990-08-14",
+		"1990-08-14":  "1990-08-14",
+		"14 Aug 1990": "1990-08-14",
+		"14-AUG-1990": "1990-08-14",
+		"14081990":    "1990-08-14",
+		"l4/O8/199O":  "1990-08-14", // OCR confusions
+		"1990":        "1990",
+		" 01/0l/2001": "2001-01-01",
+	} {
+		got, ok := NormalizeDOB(in)
+		assert.True(t, ok, in)
+		assert.Equal(t, want, got, in)
+	}
+
+	for _, in := range []string{"", "31/02/1990", "14/13/1990", "01/01/1850", "01/01/2999", "DOB", "14/08"} {
+		_, ok := NormalizeDOB(in)
+		assert.False(t, ok, in)
+	}
+
+	assert.True(t, SameDOB("1990-08-14", "1990"))
+	assert.False(t, SameDOB("1990-08-14", "1991-08-14"))
+	assert.False(t, SameDOB("", "1990"))
+}
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// =============================
+// DOCUMENT AGE HELPERS
+// =============================
+
+var monthNumbers = map[string]time.Month{
+	"jan": time.January, "feb": time.February, "mar": time.March,
+	"apr": time.April, "may": time.May, "jun": time.June,
+	"jul": time.July, "aug": time.August, "sep": time.September,
+	"oct": time.October, "nov": time.November, "dec": time.December,
+}
+
+// ParsePayMonth converts the PayMonth strings produced by the salary slip parser
+// ("October 2025", "Oct 2025", "10/2025") into the last day of that month.
+func ParsePayMonth(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+
+	if m := regexp.MustCompile(`^([A-Za-z]+)\s+(\d{4})$`).FindStringSubmatch(s); len(m) == 3 {
+		key := strings.ToLower(m[1])
+		if len(key) < 3 {
+			return time.Time{}, false
+		}
+		month, ok := monthNumbers[key[:3]]
+		if !ok {
+			return time.Time{}, false
+		}
+		year, _ := strconv.Atoi(m[2])
+		return endOfMonth(year, month), true
+	}
+
+	if m := regexp.MustCompile(`^(\d{1,2})/(\d{4})$`).FindStringSubmatch(s); len(m) == 3 {
+		month, _ := strconv.Atoi(m[1])
+		year, _ := strconv.Atoi(m[2])
+		if month < 1 || month > 12 {
+			return time.Time{}, false
+		}
+		return endOfMonth(year, time.Month(month)), true
+	}
+
+	return time.Time{}, false
+}
+
+func endOfMonth(year int, month time.Month) time.Time {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC)
+}
+
+// SlipContentDate returns the date a salary slip speaks for (end of its pay month).
+func SlipContentDate(slip dto.SalarySlipData) *time.Time {
+	t, ok := ParsePayMonth(slip.PayMonth)
+	if !ok {
+		return nil
+	}
+	return &t
+}
+
+// StatementContentDate returns the end of the statement period, falling back
+// to the latest transaction date when no period header was parsed.
+func StatementContentDate(stmt dto.BankStatementData) *time.Time {
+	if stmt.PeriodTo != nil {
+		return stmt.PeriodTo
+	}
+
+	var latest time.Time
+	for _, tx := range stmt.Transactions {
+		if tx.Date.After(latest) {
+			latest = tx.Date
+		}
+	}
+	if latest.IsZero() {
+		return nil
+	}
+	return &latest
+}
+
+// ResolveDocumentDate combines the content date with the scan/creation date.
+// Content dates win because a freshly scanned copy of an old statement is still old.
+func ResolveDocumentDate(contentDate, scanDate *time.Time) *time.Time {
+	if contentDate != nil {
+		return contentDate
+	}
+	return scanDate
+}
+
+// ScanPrecedesContent reports whether scanDate falls in a month before the
+// one contentDate is in. Content dates are the ends of pay months and
+// statement periods, and a slip or statement is often issued within that
+// last month, so the dates are compared by month rather than by day.
+func ScanPrecedesContent(contentDate, scanDate time.Time) bool {
+	sy, sm, _ := scanDate.Date()
+	cy, cm, _ := contentDate.Date()
+	return sy < cy || (sy == cy && sm < cm)
+}
+
+// DocumentAgeDays returns the number of whole days between docDate and now.
+func DocumentAgeDays(docDate, now time.Time) int {
+	days := int(now.Sub(docDate).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
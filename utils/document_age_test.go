@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePayMonth(t *testing.T) {
+	d, ok := ParsePayMonth("October 2025")
+	assert.True(t, ok)
+	assert.Equal(t, time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC), d)
+
+	d, ok = ParsePayMonth("Feb 2024")
+	assert.True(t, ok)
+	assert.Equal(t, 29, d.Day())
+
+	d, ok = ParsePayMonth("6/2025")
+	assert.True(t, ok)
+	assert.Equal(t, time.June, d.Month())
+
+	_, ok = ParsePayMonth("Unknown")
+	assert.False(t, ok)
+}
+
+func TestStatementPeriodDrivesDocumentAge(t *testing.T) {
+	text := `
+		Statement Period: 01/09/2025 to 30/09/2025
+		15/09/2025  SALARY CREDIT  50,000.00
+	`
+
+	stmt := ParseBankStatement(text)
+	contentDate := StatementContentDate(stmt)
+
+	assert.NotNil(t, contentDate)
+	assert.Equal(t, time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC), *contentDate)
+
+	now := time.Date(2025, time.October, 30, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30, DocumentAgeDays(*ResolveDocumentDate(contentDate, nil), now))
+}
+
+func TestScanPrecedesContent(t *testing.T) {
+	october := time.Date(2025, time.October, 31, 0, 0, 0, 0, time.UTC)
+
+	// an October slip scanned mid-October is on time
+	assert.False(t, ScanPrecedesContent(october, time.Date(2025, time.October, 15, 10, 0, 0, 0, time.UTC)))
+	assert.False(t, ScanPrecedesContent(october, time.Date(2025, time.November, 2, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, ScanPrecedesContent(october, time.Date(2025, time.September, 30, 0, 0, 0, 0, time.UTC)))
+	assert.True(t, ScanPrecedesContent(october, time.Date(2024, time.December, 1, 0, 0, 0, 0, time.UTC)))
+}
@@ -0,0 +1,115 @@
+package utils
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dlStateCodes are the state and union territory codes DL numbers start
+// with, old ones (OR, UA, DD/DN before the merger) included.
+var dlStateCodes = map[string]bool{
+	"AN": true, "AP": true, "AR": true, "AS": true, "BR": true, "CG": true, "CH": true, "DD": true,
+	"DL": true, "DN": true, "GA": true, "GJ": true, "HP": true, "HR": true, "JH": true, "JK": true,
+	"KA": true, "KL": true, "LA": true, "LD": true, "MH": true, "ML": true, "MN": true, "MP": true,
+	"MZ": true, "NL": true, "OD": true, "OR": true, "PB": true, "PY": true, "RJ": true, "SK": true,
+	"TN": true, "TR": true, "TS": true, "UA": true, "UK": true, "UP": true, "WB": true,
+}
+
+var (
+	// "MH12 20110012345", "MH-12-2011-0012345", "KA05/2010/0012345", with
+	// O/0 and I/1 confusions in the digits
+	dlNumberRegex = regexp.MustCompile(`\b([A-Z0-9]{2})[\s\-/]?([0-9OIL]{2})[\s\-/]?([0-9OIL]{4})[\s\-/]?([0-9OIL]{7})\b`)
+	// the older, irregular numbers
+	dlLegacyRegex = regexp.MustCompile(`\b[A-Z]{2}[\s-]?\d{2}[\s-]?\d{6,12}\b`)
+	dlDigitFix    = strings.NewReplacer("O", "0", "I", "1", "L", "1")
+	dlLetterFix   = strings.NewReplacer("0", "O", "1", "I", "5", "S", "8", "B")
+)
+
+// FindDLNumber returns the driving licence number in text, normalized to
+// the 15-character Sarathi format (state, RTO code, year of issue, serial:
+// "MH1220110012345"), and whether it is a valid one of that format. Older
+// numbers that do not follow it are returned as printed, not valid.
+func FindDLNumber(text string) (number string, valid bool) {
+	text = strings.ToUpper(text)
+	for _, m := range dlNumberRegex.FindAllStringSubmatch(text, -1) {
+		n := dlLetterFix.Replace(m[1]) + dlDigitFix.Replace(m[2]+m[3]+m[4])
+		if ValidDLNumber(n) {
+			return n, true
+		}
+	}
+	return dlLegacyRegex.FindString(text), false
+}
+
+// ValidDLNumber reports whether n is a Sarathi DL number: a known state
+// code, a two-digit RTO code other than 00, and a plausible year of issue.
+func ValidDLNumber(n string) bool {
+	if len(n) != 15 || !dlStateCodes[n[:2]] || n[2:4] == "00" {
+		return false
+	}
+	for _, r := range n[2:] {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	year, _ := strconv.Atoi(n[4:8])
+	return year >= 1950 && year <= time.Now().Year()
+}
+
+// dlClassAliases maps the ways licences print a class of vehicle to its
+// standard abbreviation.
+var dlClassAliases = []struct {
+	re    *regexp.Regexp
+	class string
+}{
+	{regexp.MustCompile(`\bMC\s*W/?O\s*G\b|\bM/CYCL\.?\s*W/?O\s*GEAR\b`), "MCWOG"},
+	{regexp.MustCompile(`\bMC\s*W/?\s*G\b|\bM/CYCL\.?\s*WG\b|\bM/CYCL\.?\s*WITH\s*GEAR\b`), "MCWG"},
+	// "LMV TRANS" on a class list is two classes; "LMV-TRANS" is one
+	{regexp.MustCompile(`\bLMV\s*(?:[-/(]\s*)?NT\b|\bLMV\s*[-/(]\s*NON[\s-]*TRANS(?:PORT)?\b`), "LMV-NT"},
+	{regexp.MustCompile(`\bLMV\s*(?:[-/(]\s*)?TR\b|\bLMV\s*[-/(]\s*TRANS(?:PORT)?\b`), "LMV-TR"},
+	{regexp.MustCompile(`\bLMV\b`), "LMV"},
+	{regexp.MustCompile(`\bHGMV\b`), "HGMV"},
+	{regexp.MustCompile(`\bHPMV\b`), "HPMV"},
+	{regexp.MustCompile(`\bHMV\b`), "HMV"},
+	{regexp.MustCompile(`\bHTV\b`), "HTV"},
+	{regexp.MustCompile(`\bMGV\b`), "MGV"},
+	// "Non-Transport" validity labels and "Transport Department" headings are
+	// not the class
+	{regexp.MustCompile(`\bNON[\s-]*TRANS(?:PORT)?\b|\bTRANSPORT\b`), ""},
+	{regexp.MustCompile(`\bTRANS\b`), "TRANS"},
+	{regexp.MustCompile(`\bTRCTR\b|\bTRACTOR\b`), "TRCTR"},
+	{regexp.MustCompile(`\bE[\s-]*RICK(?:SHAW)?\b|\bERIK\b`), "E-RICKSHAW"},
+	{regexp.MustCompile(`\bINVCRG\b`), "INVCRG"},
+}
+
+// ParseDLClasses returns the classes of vehicle a licence authorizes (LMV,
+// MCWG, TRANS ...), each once, in the order of dlClassAliases. "LMV-TR"
+// does not also count as "LMV" or "TRANS".
+func ParseDLClasses(text string) []string {
+	text = strings.ToUpper(text)
+	var classes []string
+	for _, a := range dlClassAliases {
+		if !a.re.MatchString(text) {
+			continue
+		}
+		if a.class != "" && !slices.Contains(classes, a.class) {
+			classes = append(classes, a.class)
+		}
+		text = a.re.ReplaceAllString(text, " ")
+	}
+	return classes
+}
+
+// DLExpired reports whether a licence valid till validTill (DD/MM/YYYY or
+// DD-MM-YYYY) has expired at now; it is valid through that day. ok is false
+// when validTill is not a date.
+func DLExpired(validTill string, now time.Time) (expired, ok bool) {
+	s := strings.NewReplacer("-", "/", ".", "/").Replace(strings.TrimSpace(validTill))
+	t, err := time.ParseInLocation("02/01/2006", s, now.Location())
+	if err != nil {
+		return false, false
+	}
+	return !now.Before(t.AddDate(0, 0, 1)), true
+}
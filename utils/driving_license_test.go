@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindDLNumber(t *testing.T) {
+	for text, want := range map[string]string{
+		"DL No. MH12 20110012345":        "MH1220110012345",
+		"DL NO: KA-05-2O10-0O12345":      "KA0520100012345",
+		"Licence No. TN22/2016/0001234":  "TN2220160001234",
+		"DL-0420110149646 Valid Till ..": "DL0420110149646",
+	} {
+		n, valid := FindDLNumber(text)
+		assert.Equal(t, want, n, text)
+		assert.True(t, valid, text)
+	}
+
+	n, valid := FindDLNumber("DL NO. XY12 20110012345")
+	assert.Equal(t, "XY12 20110012345", n, "unknown state: as printed")
+	assert.False(t, valid)
+	assert.False(t, ValidDLNumber("MH1220990012345"), "issued in the future")
+}
+
+func TestParseDLClasses(t *testing.T) {
+	text := `TRANSPORT DEPARTMENT GOVT OF MAHARASHTRA
+COV: MCWG LMV TRANS
+Validity (NT) 01/01/2040 Validity (TR) 01/01/2028
+Non-Transport`
+	assert.Equal(t, []string{"MCWG", "LMV", "TRANS"}, ParseDLClasses(text))
+	assert.Equal(t, []string{"MCWOG", "LMV-TR"}, ParseDLClasses("Class of Vehicle: MC W/O G, LMV-TR"))
+	assert.Empty(t, ParseDLClasses("Non-Transport"))
+}
+
+func TestDLExpired(t *testing.T) {
+	now := time.Date(2026, 3, 15, 10, 0, 0, 0, time.UTC)
+	for validTill, want := range map[string]bool{"14/03/2026": true, "15-03-2026": false, "01/01/2040": false} {
+		expired, ok := DLExpired(validTill, now)
+		assert.True(t, ok, validTill)
+		assert.Equal(t, want, expired, validTill)
+	}
+	_, ok := DLExpired("", now)
+	assert.False(t, ok)
+}
@@ -0,0 +1,199 @@
+package ekyc
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"slices"
+	"strings"
+)
+
+const xmlNamespace = "http://www.w3.org/XML/1998/namespace"
+
+// c14nFrame is an open element: its raw name and the namespaces in scope in
+// the document and as rendered in the output.
+type c14nFrame struct {
+	name     xml.Name
+	inScope  map[string]string
+	rendered map[string]string
+}
+
+// canonicalize returns the Canonical XML 1.0 form (without comments) of the
+// first element named apex in doc, or of the document element when apex is
+// empty, leaving out the first element named skip inside it: the enveloped
+// signature. Namespace declarations in scope at apex are rendered on it.
+func canonicalize(doc []byte, apex, skip string) ([]byte, error) {
+	d := xml.NewDecoder(bytes.NewReader(doc))
+	var (
+		out      bytes.Buffer
+		stack    []c14nFrame
+		started  bool
+		apexAt   = -1 // depth of the apex element
+		skipAt   = -1 // depth of the skipped element
+		skipDone bool
+	)
+	for {
+		tok, err := d.RawToken()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			parent := c14nFrame{inScope: map[string]string{}, rendered: map[string]string{}}
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1]
+			}
+			f := c14nFrame{name: t.Name, inScope: parent.inScope, rendered: parent.rendered}
+			if decls := namespaceDecls(t.Attr); len(decls) > 0 {
+				f.inScope = cloneWith(parent.inScope, decls)
+			}
+			stack = append(stack, f)
+			depth := len(stack) - 1
+
+			if apexAt < 0 && !started && (apex == "" || t.Name.Local == apex) {
+				apexAt, started = depth, true
+				stack[depth].rendered = map[string]string{}
+			}
+			if apexAt < 0 || skipAt >= 0 {
+				continue
+			}
+			if !skipDone && skip != "" && depth > apexAt && t.Name.Local == skip {
+				skipAt, skipDone = depth, true
+				continue
+			}
+			stack[depth].rendered = writeStart(&out, t, stack[depth].inScope, stack[depth].rendered)
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, errors.New("unbalanced XML")
+			}
+			depth := len(stack) - 1
+			stack = stack[:depth]
+			switch {
+			case apexAt < 0:
+			case skipAt >= 0:
+				if depth == skipAt {
+					skipAt = -1
+				}
+			default:
+				out.WriteString("</" + qualified(t.Name) + ">")
+				if depth == apexAt {
+					return out.Bytes(), nil
+				}
+			}
+		case xml.CharData:
+			if apexAt >= 0 && skipAt < 0 {
+				out.WriteString(escapeText(string(t)))
+			}
+		}
+	}
+	if !started {
+		return nil, errors.New("element " + apex + " not found")
+	}
+	return nil, errors.New("unterminated XML")
+}
+
+// writeStart renders a start tag: the namespace declarations that differ from
+// those already rendered, by prefix, then the attributes, by namespace URI and
+// local name. It returns the namespaces rendered for the element's children.
+func writeStart(out *bytes.Buffer, t xml.StartElement, inScope, rendered map[string]string) map[string]string {
+	out.WriteString("<" + qualified(t.Name))
+
+	var prefixes []string
+	for p, uri := range inScope {
+		if r, ok := rendered[p]; (ok && r != uri) || (!ok && (uri != "" || p != "")) {
+			prefixes = append(prefixes, p)
+		}
+	}
+	slices.Sort(prefixes)
+	if len(prefixes) > 0 {
+		rendered = cloneWith(rendered, nil)
+	}
+	for _, p := range prefixes {
+		rendered[p] = inScope[p]
+		name := "xmlns"
+		if p != "" {
+			name += ":" + p
+		}
+		out.WriteString(" " + name + `="` + escapeAttr(inScope[p]) + `"`)
+	}
+
+	type attr struct{ uri, name, value string }
+	var attrs []attr
+	for _, a := range t.Attr {
+		if a.Name.Space == "xmlns" || (a.Name.Space == "" && a.Name.Local == "xmlns") {
+			continue
+		}
+		uri := ""
+		switch a.Name.Space {
+		case "":
+		case "xml":
+			uri = xmlNamespace
+		default:
+			uri = inScope[a.Name.Space]
+		}
+		attrs = append(attrs, attr{uri, qualified(a.Name), a.Value})
+	}
+	slices.SortFunc(attrs, func(a, b attr) int {
+		if c := strings.Compare(a.uri, b.uri); c != 0 {
+			return c
+		}
+		return strings.Compare(localPart(a.name), localPart(b.name))
+	})
+	for _, a := range attrs {
+		out.WriteString(" " + a.name + `="` + escapeAttr(a.value) + `"`)
+	}
+	out.WriteString(">")
+	return rendered
+}
+
+// namespaceDecls returns the xmlns and xmlns:prefix attributes, by prefix.
+func namespaceDecls(attrs []xml.Attr) map[string]string {
+	decls := map[string]string{}
+	for _, a := range attrs {
+		switch {
+		case a.Name.Space == "" && a.Name.Local == "xmlns":
+			decls[""] = a.Value
+		case a.Name.Space == "xmlns":
+			decls[a.Name.Local] = a.Value
+		}
+	}
+	return decls
+}
+
+func cloneWith(m, extra map[string]string) map[string]string {
+	out := make(map[string]string, len(m)+len(extra))
+	for k, v := range m {
+		out[k] = v
+	}
+	for k, v := range extra {
+		out[k] = v
+	}
+	return out
+}
+
+// qualified is the raw name of a token, its prefix unresolved.
+func qualified(n xml.Name) string {
+	if n.Space == "" {
+		return n.Local
+	}
+	return n.Space + ":" + n.Local
+}
+
+func localPart(name string) string {
+	if i := strings.IndexByte(name, ':'); i >= 0 {
+		return name[i+1:]
+	}
+	return name
+}
+
+var (
+	textEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\r", "&#xD;")
+	attrEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", `"`, "&quot;", "\t", "&#x9;", "\n", "&#xA;", "\r", "&#xD;")
+)
+
+func escapeText(s string) string { return textEscaper.Replace(s) }
+func escapeAttr(s string) string { return attrEscaper.Replace(s) }
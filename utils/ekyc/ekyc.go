@@ -0,0 +1,292 @@
+// Package ekyc reads the UIDAI Aadhaar Paperless Offline e-KYC: a ZIP,
+// encrypted with a four-character share code the holder chooses, holding an
+// XML of their demographic details and photo signed by UIDAI (an enveloped
+// XML signature, RSA with SHA-1 or SHA-256). The XML carries hashes of the
+// holder's email and mobile number rather than the values themselves.
+package ekyc
+
+import (
+	"crypto"
+	"crypto/rsa"
+	_ "crypto/sha1" // SHA-1 signatures
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/utils/secureqr"
+	"github.com/Aashish23092/ocr-income-verification/utils/ziparchive"
+)
+
+var (
+	// ErrNoXML means the archive holds no XML file.
+	ErrNoXML = errors.New("archive holds no offline e-KYC XML")
+	// ErrNotEKYC means the XML is not an offline e-KYC.
+	ErrNotEKYC = errors.New("not an Aadhaar offline e-KYC XML")
+)
+
+// Data holds the offline e-KYC fields. DOB is DD-MM-YYYY as given by UIDAI;
+// Signature is one of the secureqr signature states.
+type Data struct {
+	ReferenceID string // last 4 Aadhaar digits + generation timestamp
+	Name        string
+	DOB         string
+	Gender      string
+	CareOf      string
+	Country     string
+	District    string
+	House       string
+	Landmark    string
+	Location    string
+	Pincode     string
+	PostOffice  string
+	State       string
+	Street      string
+	SubDistrict string
+	VTC         string
+
+	Photo      []byte // JPEG
+	EmailHash  string // hex; empty when no email is registered
+	MobileHash string
+	Signature  string
+}
+
+type offlineKYC struct {
+	XMLName     xml.Name `xml:"OfflinePaperlessKyc"`
+	ReferenceID string   `xml:"referenceId,attr"`
+	Poi         struct {
+		Name   string `xml:"name,attr"`
+		DOB    string `xml:"dob,attr"`
+		Gender string `xml:"gender,attr"`
+		Email  string `xml:"e,attr"`
+		Mobile string `xml:"m,attr"`
+	} `xml:"UidData>Poi"`
+	Poa struct {
+		CareOf      string `xml:"careof,attr"`
+		Country     string `xml:"country,attr"`
+		District    string `xml:"dist,attr"`
+		House       string `xml:"house,attr"`
+		Landmark    string `xml:"landmark,attr"`
+		Location    string `xml:"loc,attr"`
+		Pincode     string `xml:"pc,attr"`
+		PostOffice  string `xml:"po,attr"`
+		State       string `xml:"state,attr"`
+		Street      string `xml:"street,attr"`
+		SubDistrict string `xml:"subdist,attr"`
+		VTC         string `xml:"vtc,attr"`
+	} `xml:"UidData>Poa"`
+	Photo     string `xml:"UidData>Pht"`
+	Signature struct {
+		SignedInfo struct {
+			SignatureMethod struct {
+				Algorithm string `xml:",attr"`
+			}
+			Reference struct {
+				URI          string `xml:",attr"`
+				DigestMethod struct {
+					Algorithm string `xml:",attr"`
+				}
+				DigestValue string
+			}
+		}
+		SignatureValue string
+	}
+}
+
+// Open decrypts an offline e-KYC ZIP with the share code and decodes the XML
+// in it. limit bounds what the archive unpacks to.
+func Open(zipData []byte, shareCode string, uidaiKey *rsa.PublicKey, limit int64) (*Data, error) {
+	entries, err := ziparchive.Extract(zipData, shareCode, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if strings.EqualFold(path.Ext(e.Name), ".xml") {
+			return Decode(e.Data, uidaiKey)
+		}
+	}
+	return nil, ErrNoXML
+}
+
+// Decode decodes an offline e-KYC XML. With a UIDAI public key the signature
+// is checked and reported in Data.Signature; without one the fields are
+// returned unverified.
+func Decode(xmlData []byte, uidaiKey *rsa.PublicKey) (*Data, error) {
+	var k offlineKYC
+	if err := xml.Unmarshal(xmlData, &k); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrNotEKYC, err)
+	}
+	if k.ReferenceID == "" || k.Poi.Name == "" {
+		return nil, ErrNotEKYC
+	}
+
+	d := &Data{
+		ReferenceID: k.ReferenceID,
+		Name:        k.Poi.Name,
+		DOB:         k.Poi.DOB,
+		Gender:      k.Poi.Gender,
+		CareOf:      k.Poa.CareOf,
+		Country:     k.Poa.Country,
+		District:    k.Poa.District,
+		House:       k.Poa.House,
+		Landmark:    k.Poa.Landmark,
+		Location:    k.Poa.Location,
+		Pincode:     k.Poa.Pincode,
+		PostOffice:  k.Poa.PostOffice,
+		State:       k.Poa.State,
+		Street:      k.Poa.Street,
+		SubDistrict: k.Poa.SubDistrict,
+		VTC:         k.Poa.VTC,
+		EmailHash:   strings.ToLower(strings.TrimSpace(k.Poi.Email)),
+		MobileHash:  strings.ToLower(strings.TrimSpace(k.Poi.Mobile)),
+		Signature:   secureqr.SignatureUnverified,
+	}
+	if photo, err := base64.StdEncoding.DecodeString(stripSpace(k.Photo)); err == nil {
+		d.Photo = photo
+	}
+	if uidaiKey != nil {
+		d.Signature = secureqr.SignatureInvalid
+		if verifySignature(xmlData, &k, uidaiKey) == nil {
+			d.Signature = secureqr.SignatureVerified
+		}
+	}
+	return d, nil
+}
+
+// verifySignature checks the enveloped signature: the digest of the
+// canonical document without its Signature, then UIDAI's RSA signature over
+// the canonical SignedInfo.
+func verifySignature(xmlData []byte, k *offlineKYC, key *rsa.PublicKey) error {
+	info := k.Signature.SignedInfo
+	if info.Reference.URI != "" {
+		return fmt.Errorf("signature references %q, not the whole document", info.Reference.URI)
+	}
+	digestHash, err := hashFor(info.Reference.DigestMethod.Algorithm)
+	if err != nil {
+		return err
+	}
+	signHash, err := hashFor(info.SignatureMethod.Algorithm)
+	if err != nil {
+		return err
+	}
+
+	doc, err := canonicalize(xmlData, "", "Signature")
+	if err != nil {
+		return err
+	}
+	want, err := base64.StdEncoding.DecodeString(stripSpace(info.Reference.DigestValue))
+	if err != nil {
+		return fmt.Errorf("invalid digest value: %w", err)
+	}
+	h := digestHash.New()
+	h.Write(doc)
+	if subtle.ConstantTimeCompare(h.Sum(nil), want) != 1 {
+		return errors.New("document digest does not match")
+	}
+
+	signedInfo, err := canonicalize(xmlData, "SignedInfo", "")
+	if err != nil {
+		return err
+	}
+	sig, err := base64.StdEncoding.DecodeString(stripSpace(k.Signature.SignatureValue))
+	if err != nil {
+		return fmt.Errorf("invalid signature value: %w", err)
+	}
+	h = signHash.New()
+	h.Write(signedInfo)
+	return rsa.VerifyPKCS1v15(key, signHash, h.Sum(nil), sig)
+}
+
+// hashFor maps an XML signature digest or signature method to its hash.
+func hashFor(algorithm string) (crypto.Hash, error) {
+	switch a := strings.ToLower(algorithm); {
+	case strings.HasSuffix(a, "sha256"):
+		return crypto.SHA256, nil
+	case strings.HasSuffix(a, "sha1"):
+		return crypto.SHA1, nil
+	}
+	return 0, fmt.Errorf("unsupported signature algorithm %q", algorithm)
+}
+
+// Last4 returns the last four Aadhaar digits carried in the reference ID.
+func (d *Data) Last4() string {
+	if len(d.ReferenceID) < 4 {
+		return ""
+	}
+	return d.ReferenceID[:4]
+}
+
+// Address joins the address fields in the order printed on the card.
+func (d *Data) Address() string {
+	var parts []string
+	add := func(prefix, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			parts = append(parts, prefix+v)
+		}
+	}
+	add("C/O ", d.CareOf)
+	add("", d.House)
+	add("", d.Street)
+	add("", d.Landmark)
+	add("", d.Location)
+	add("", d.VTC)
+	add("PO ", d.PostOffice)
+	add("", d.SubDistrict)
+	add("", d.District)
+	add("", d.State)
+	add("", d.Pincode)
+	return strings.Join(parts, ", ")
+}
+
+// VerifyEmail reports whether email is the one the holder registered. ok is
+// false when the XML carries no email hash.
+func (d *Data) VerifyEmail(email, shareCode string) (match, ok bool) {
+	if d.EmailHash == "" {
+		return false, false
+	}
+	return d.contactHash(strings.TrimSpace(email), shareCode) == d.EmailHash, true
+}
+
+// VerifyMobile reports whether mobile (with or without +91) is the number
+// the holder registered. ok is false when the XML carries no mobile hash.
+func (d *Data) VerifyMobile(mobile, shareCode string) (match, ok bool) {
+	if d.MobileHash == "" {
+		return false, false
+	}
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, mobile)
+	if len(digits) > 10 {
+		digits = digits[len(digits)-10:]
+	}
+	return d.contactHash(digits, shareCode) == d.MobileHash, true
+}
+
+// contactHash is how UIDAI hashes an email or mobile number: SHA-256 of the
+// value and share code, hashed again (as hex) as many times as the last
+// Aadhaar digit, and once for a 0 or 1.
+func (d *Data) contactHash(value, shareCode string) string {
+	times := 1
+	if last4 := d.Last4(); len(last4) == 4 && last4[3] > '1' && last4[3] <= '9' {
+		times = int(last4[3] - '0')
+	}
+	h := value + shareCode
+	for range times {
+		sum := sha256.Sum256([]byte(h))
+		h = hex.EncodeToString(sum[:])
+	}
+	return h
+}
+
+// stripSpace removes the line breaks and indentation of base64 content.
+func stripSpace(s string) string {
+	return strings.Join(strings.Fields(s), "")
+}
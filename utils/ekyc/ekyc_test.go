@@ -0,0 +1,126 @@
+package ekyc
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/utils/secureqr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const signedInfoBody = `<CanonicalizationMethod Algorithm="http://www.w3.org/TR/2001/REC-xml-c14n-20010315"></CanonicalizationMethod>` +
+	`<SignatureMethod Algorithm="http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"></SignatureMethod>` +
+	`<Reference URI=""><Transforms><Transform Algorithm="http://www.w3.org/2000/09/xmldsig#enveloped-signature"></Transform></Transforms>` +
+	`<DigestMethod Algorithm="http://www.w3.org/2001/04/xmlenc#sha256"></DigestMethod><DigestValue>%DIGEST%</DigestValue></Reference>`
+
+// hashTimes4 is how UIDAI hashes a contact for an Aadhaar ending in 4.
+func hashTimes4(value string) string {
+	h := value
+	for range 4 {
+		sum := sha256.Sum256([]byte(h))
+		h = hex.EncodeToString(sum[:])
+	}
+	return h
+}
+
+// buildXML signs an offline e-KYC the way UIDAI does. The content is written
+// in canonical form, so the digest does not depend on canonicalize; format
+// rewrites it into an equivalent, non-canonical document.
+func buildXML(t *testing.T, key *rsa.PrivateKey, format func(string) string) []byte {
+	t.Helper()
+	content := `<UidData><Poi dob="05-06-1990" e="` + hashTimes4("anita@example.com1A2b") + `" gender="F" m="` + hashTimes4("98765432101A2b") + `" name="Anita Rao"></Poi>` +
+		`<Poa careof="D/O Ramesh Rao" country="India" dist="Bengaluru Urban" house="12" landmark="Near Temple" loc="Indiranagar" pc="560038" po="Indiranagar" state="Karnataka" street="MG Road" subdist="Bengaluru North" vtc="Bengaluru"></Poa>` +
+		`<Pht>` + base64.StdEncoding.EncodeToString([]byte{0xff, 0xd8, 0xff}) + `</Pht></UidData>`
+	root := `<OfflinePaperlessKyc referenceId="123420190308111430000">`
+
+	digest := sha256.Sum256([]byte(root + content + `</OfflinePaperlessKyc>`))
+	signedInfo := strings.Replace(signedInfoBody, "%DIGEST%", base64.StdEncoding.EncodeToString(digest[:]), 1)
+	infoDigest := sha256.Sum256([]byte(`<SignedInfo xmlns="http://www.w3.org/2000/09/xmldsig#">` + signedInfo + `</SignedInfo>`))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, infoDigest[:])
+	require.NoError(t, err)
+
+	doc := root + content + `<Signature xmlns="http://www.w3.org/2000/09/xmldsig#"><SignedInfo>` + signedInfo + `</SignedInfo>` +
+		`<SignatureValue>` + base64.StdEncoding.EncodeToString(sig) + `</SignatureValue></Signature></OfflinePaperlessKyc>`
+	if format != nil {
+		doc = format(doc)
+	}
+	return []byte(doc)
+}
+
+func TestDecode(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	d, err := Decode(buildXML(t, key, nil), &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, secureqr.SignatureVerified, d.Signature)
+	assert.Equal(t, "Anita Rao", d.Name)
+	assert.Equal(t, "05-06-1990", d.DOB)
+	assert.Equal(t, "1234", d.Last4())
+	assert.Equal(t, "C/O D/O Ramesh Rao, 12, MG Road, Near Temple, Indiranagar, Bengaluru, PO Indiranagar, Bengaluru North, Bengaluru Urban, Karnataka, 560038", d.Address())
+	assert.Equal(t, []byte{0xff, 0xd8, 0xff}, d.Photo)
+
+	match, ok := d.VerifyMobile("+91 98765 43210", "1A2b")
+	assert.True(t, ok)
+	assert.True(t, match)
+	match, _ = d.VerifyMobile("9876543211", "1A2b")
+	assert.False(t, match)
+	match, ok = d.VerifyEmail("anita@example.com", "1A2b")
+	assert.True(t, ok)
+	assert.True(t, match)
+}
+
+func TestDecodeCanonicalizes(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	// declaration, empty-element tags, attribute order and line breaks in
+	// base64 do not change the canonical form
+	doc := buildXML(t, key, func(s string) string {
+		s = strings.Replace(s, `<Poi dob="05-06-1990"`, `<Poi name="Anita Rao" dob="05-06-1990"`, 1)
+		s = strings.Replace(s, ` name="Anita Rao"></Poi>`, `/>`, 1)
+		s = strings.Replace(s, `<SignatureValue>`, "<SignatureValue>\n", 1)
+		return `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n" + s
+	})
+	d, err := Decode(doc, &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, secureqr.SignatureVerified, d.Signature)
+
+	tampered := bytes.Replace(doc, []byte("Anita Rao"), []byte("Anika Rao"), 1)
+	d, err = Decode(tampered, &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, secureqr.SignatureInvalid, d.Signature)
+
+	d, err = Decode(doc, nil)
+	require.NoError(t, err)
+	assert.Equal(t, secureqr.SignatureUnverified, d.Signature)
+}
+
+func TestOpen(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	f, err := w.Create("offlineaadhaar20190308111430000.xml")
+	require.NoError(t, err)
+	_, err = f.Write(buildXML(t, key, nil))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	d, err := Open(buf.Bytes(), "1A2b", &key.PublicKey, 1<<20)
+	require.NoError(t, err)
+	assert.Equal(t, "Anita Rao", d.Name)
+
+	_, err = Decode([]byte(`<PrintLetterBarcodeData uid="123412341234"/>`), nil)
+	assert.ErrorIs(t, err, ErrNotEKYC)
+}
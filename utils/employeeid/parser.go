@@ -1,43 +1,213 @@
 package employeeid
 
 import (
+	"encoding/json"
 	"regexp"
 	"strings"
+	"time"
+)
+
+var (
+	nameLabel        = regexp.MustCompile(`(?i)^(?:employee\s+|emp\.?\s+)?name\s*[:\-]\s*(.+)$`)
+	titleCaseName    = regexp.MustCompile(`^[A-Z][a-z]+(?: [A-Z][a-z]*\.?){1,3}$`)
+	upperCaseName    = regexp.MustCompile(`^[A-Z]+\.?(?: [A-Z]+\.?){1,3}$`)
+	employeeIDLabel  = regexp.MustCompile(`(?i)\b(?:emp(?:loyee)?\.?\s*(?:id|no|number|code)|staff\s*(?:id|no)|id\s*no)\.?\s*[:\-#]?\s*([A-Z0-9][A-Z0-9\-/]{2,})`)
+	employeeIDPrefix = regexp.MustCompile(`(?i)(EMP[- ]?\d{3,})`)
+	companyLabel     = regexp.MustCompile(`(?i)^(?:company|organi[sz]ation|employer)\s*(?:name)?\s*[:\-]\s*(.+)$`)
+	// legal forms first: "Solutions" alone may be part of a slogan
+	companyLegal    = regexp.MustCompile(`(?i)\b(?:private\s+limited|pvt\.?\s*ltd\.?|limited|ltd\.?|llp|inc\.?|corporation|corp\.?)$`)
+	companyBusiness = regexp.MustCompile(`(?i)\b(?:technologies|solutions|services|systems|industries|enterprises|consultancy|consulting|software|labs|group|bank)$`)
+	designationLbl  = regexp.MustCompile(`(?i)^(?:designation|role|title|position)\s*[:\-]\s*(.+)$`)
+	designationWord = regexp.MustCompile(`(?i)\b(?:engineer|developer|manager|analyst|executive|officer|associate|consultant|lead|director|intern|trainee|architect|specialist|administrator|designer|technician|supervisor|assistant|accountant|scientist|president|head)$`)
+	bloodGroup      = regexp.MustCompile(`(?i)\bblood\s*(?:group|grp\.?)?\s*[:\-]?\s*(AB|A|B|O)\s*(\+\s*ve|-\s*ve|\+|-|positive|negative)`)
+	validUntilLabel = regexp.MustCompile(`(?i)\b(?:valid\s*(?:up\s*to|upto|till|until|thru|through)|expiry(?:\s*date)?|exp(?:\.|iry)?\s*date|expires(?:\s*on)?)\s*[:\-]?\s*(.+)$`)
+	validFromLabel  = regexp.MustCompile(`(?i)\b(?:valid\s*from|date\s*of\s*issue|issue\s*date|issued\s*on|d\.?o\.?i\.?)\s*[:\-]?\s*(.+)$`)
+	cardHeading     = regexp.MustCompile(`(?i)\b(?:identity|card|employee|id|valid|blood|issued|signature|authori[sz]ed|address|phone|emergency)\b`)
+	vCardField      = regexp.MustCompile(`(?m)^([A-Z\-]+)(?:;[^:\r\n]*)?:(.*)$`)
 )
 
 // Extracts: Rohan Sharma
 func ParseNameID(text string) string {
 	lines := strings.Split(text, "\n")
 
+	for _, line := range lines {
+		if m := nameLabel.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 
-		// Match exact human names (Firstname Lastname)
-		if regexp.MustCompile(`^[A-Z][a-z]+ [A-Z][a-z]+$`).MatchString(line) {
+		// a bare human name, not the company, role or a card heading
+		if (titleCaseName.MatchString(line) || upperCaseName.MatchString(line)) && !cardHeading.MatchString(line) &&
+			!companyLegal.MatchString(line) && !companyBusiness.MatchString(line) && !designationWord.MatchString(line) {
 			return line
 		}
 	}
 	return ""
 }
 
+// Extracts: EMP-10234, from an "Emp ID" / "Employee No" / "Staff ID" label
+// or an EMP-prefixed code.
 func ParseEmployeeID(text string) string {
-	reg := regexp.MustCompile(`(?i)(EMP[- ]?\d{3,})`)
-	if m := reg.FindStringSubmatch(text); len(m) > 1 {
+	for _, m := range employeeIDLabel.FindAllStringSubmatch(text, -1) {
+		if strings.ContainsAny(m[1], "0123456789") {
+			return strings.ToUpper(m[1])
+		}
+	}
+	if m := employeeIDPrefix.FindStringSubmatch(text); len(m) > 1 {
 		return m[1]
 	}
 	return ""
 }
 
+// Extracts: TechNova Solutions Pvt Ltd, from a "Company:" label or the line
+// ending in a legal form (Pvt Ltd, Limited, LLP, Inc), else in a business
+// word (Solutions, Technologies ...).
 func ParseCompanyID(text string) string {
-	if strings.Contains(text, "TechNova Solutions Pvt Ltd") {
-		return "TechNova Solutions Pvt Ltd"
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		if m := companyLabel.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
+	for _, re := range []*regexp.Regexp{companyLegal, companyBusiness} {
+		for _, line := range lines {
+			if line = strings.TrimSpace(line); re.MatchString(line) && len(strings.Fields(line)) > 1 {
+				return line
+			}
+		}
 	}
 	return ""
 }
 
+// Extracts: Software Engineer, from a "Designation:" label or a short line
+// ending in a role word (Engineer, Manager, Analyst ...).
 func ParseDesignationID(text string) string {
-	if strings.Contains(text, "Software Engineer") {
-		return "Software Engineer"
+	lines := strings.Split(text, "\n")
+	for _, line := range lines {
+		if m := designationLbl.FindStringSubmatch(strings.TrimSpace(line)); m != nil {
+			return strings.TrimSpace(m[1])
+		}
+	}
+	for _, line := range lines {
+		line = strings.TrimSpace(line)
+		if designationWord.MatchString(line) && len(strings.Fields(line)) <= 5 && !strings.ContainsAny(line, "0123456789:") {
+			return line
+		}
 	}
 	return ""
 }
+
+// ParseBloodGroup extracts the blood group ("B+", "AB-") when the card
+// prints one.
+func ParseBloodGroup(text string) string {
+	m := bloodGroup.FindStringSubmatch(text)
+	if m == nil {
+		return ""
+	}
+	sign := "+"
+	if r := strings.ToLower(m[2]); strings.HasPrefix(r, "-") || r == "negative" {
+		sign = "-"
+	}
+	return strings.ToUpper(m[1]) + sign
+}
+
+// ParseValidity extracts the card's issue and expiry dates as YYYY-MM-DD,
+// or YYYY-MM for cards that print only a month ("Valid till 12/2026").
+func ParseValidity(text string) (from, until string) {
+	for _, line := range strings.Split(text, "\n") {
+		if m := validUntilLabel.FindStringSubmatch(line); m != nil && until == "" {
+			until = cardDate(m[1])
+		} else if m := validFromLabel.FindStringSubmatch(line); m != nil && from == "" {
+			from = cardDate(m[1])
+		}
+	}
+	return from, until
+}
+
+var cardDateLayouts = []struct{ layout, out string }{
+	{"02/01/2006", "2006-01-02"}, {"2/1/2006", "2006-01-02"}, {"02-01-2006", "2006-01-02"}, {"02.01.2006", "2006-01-02"},
+	{"2006-01-02", "2006-01-02"}, {"02 Jan 2006", "2006-01-02"}, {"2 Jan 2006", "2006-01-02"}, {"02-Jan-2006", "2006-01-02"},
+	{"Jan 2, 2006", "2006-01-02"}, {"January 2, 2006", "2006-01-02"}, {"2 January 2006", "2006-01-02"},
+	{"01/2006", "2006-01"}, {"01-2006", "2006-01"}, {"Jan 2006", "2006-01"}, {"January 2006", "2006-01"}, {"Jan-2006", "2006-01"},
+}
+
+// cardDate normalizes the date at the start of s.
+func cardDate(s string) string {
+	fields := strings.Fields(strings.TrimSpace(s))
+	// the date is one to three words, whatever follows it on the line
+	for n := min(3, len(fields)); n > 0; n-- {
+		candidate := strings.TrimRight(strings.Join(fields[:n], " "), ".,;")
+		for _, l := range cardDateLayouts {
+			if t, err := time.Parse(l.layout, candidate); err == nil {
+				return t.Format(l.out)
+			}
+		}
+	}
+	return ""
+}
+
+// Badge is what a QR code or barcode on an employee badge carries, as far as
+// it can be read: a vCard, JSON, "Label: value" lines or the bare employee ID.
+type Badge struct {
+	Name        string
+	EmployeeID  string
+	Company     string
+	Designation string
+}
+
+// ParseBadgeCode reads the decoded payload of a badge's QR code or barcode.
+func ParseBadgeCode(payload string) Badge {
+	payload = strings.TrimSpace(payload)
+	var b Badge
+	switch {
+	case strings.HasPrefix(strings.ToUpper(payload), "BEGIN:VCARD"):
+		for _, m := range vCardField.FindAllStringSubmatch(strings.ReplaceAll(payload, "\r", ""), -1) {
+			value := strings.TrimSpace(m[2])
+			switch m[1] {
+			case "FN":
+				b.Name = value
+			case "ORG":
+				b.Company = strings.TrimSpace(strings.Split(value, ";")[0])
+			case "TITLE", "ROLE":
+				if b.Designation == "" {
+					b.Designation = value
+				}
+			case "UID", "X-EMPLOYEE-ID":
+				b.EmployeeID = value
+			}
+		}
+	case strings.HasPrefix(payload, "{"):
+		var fields map[string]interface{}
+		if json.Unmarshal([]byte(payload), &fields) == nil {
+			for k, v := range fields {
+				s, ok := v.(string)
+				if !ok {
+					continue
+				}
+				switch strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(k)) {
+				case "name", "employeename", "fullname":
+					b.Name = s
+				case "empid", "employeeid", "id", "empno", "employeeno", "staffid", "empcode", "employeecode":
+					b.EmployeeID = s
+				case "company", "organisation", "organization", "employer", "companyname":
+					b.Company = s
+				case "designation", "title", "role", "position":
+					b.Designation = s
+				}
+			}
+		}
+	case strings.Contains(payload, ":") || strings.Contains(payload, "\n"):
+		b = Badge{
+			Name:        ParseNameID(payload),
+			EmployeeID:  ParseEmployeeID(payload),
+			Company:     ParseCompanyID(payload),
+			Designation: ParseDesignationID(payload),
+		}
+	case strings.ContainsAny(payload, "0123456789") && !strings.ContainsAny(payload, " /"):
+		// a 1D barcode: the employee ID alone
+		b.EmployeeID = strings.ToUpper(payload)
+	}
+	return b
+}
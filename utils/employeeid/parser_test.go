@@ -0,0 +1,46 @@
+package employeeid
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseEmployeeIDCard(t *testing.T) {
+	text := `GLOBEX INFOTECH PRIVATE LIMITED
+EMPLOYEE IDENTITY CARD
+PRIYA NAIR
+Senior Business Analyst
+Employee No: gx/2291
+Blood Group: AB -ve
+Date of Issue: 01/04/2024
+Valid Till: 12/2026`
+
+	assert.Equal(t, "PRIYA NAIR", ParseNameID(text))
+	assert.Equal(t, "GX/2291", ParseEmployeeID(text))
+	assert.Equal(t, "GLOBEX INFOTECH PRIVATE LIMITED", ParseCompanyID(text))
+	assert.Equal(t, "Senior Business Analyst", ParseDesignationID(text))
+	assert.Equal(t, "AB-", ParseBloodGroup(text))
+	from, until := ParseValidity(text)
+	assert.Equal(t, "2024-04-01", from)
+	assert.Equal(t, "2026-12", until)
+
+	// the original card layout
+	text = "TechNova Solutions Pvt Ltd\nRohan Sharma\nSoftware Engineer\nEMP-10234\nValid upto 31 Mar 2027"
+	assert.Equal(t, "Rohan Sharma", ParseNameID(text))
+	assert.Equal(t, "EMP-10234", ParseEmployeeID(text))
+	assert.Equal(t, "TechNova Solutions Pvt Ltd", ParseCompanyID(text))
+	assert.Equal(t, "Software Engineer", ParseDesignationID(text))
+	_, until = ParseValidity(text)
+	assert.Equal(t, "2027-03-31", until)
+}
+
+func TestParseBadgeCode(t *testing.T) {
+	assert.Equal(t, Badge{Name: "Rohan Sharma", EmployeeID: "EMP-10234", Company: "TechNova Solutions Pvt Ltd", Designation: "Software Engineer"},
+		ParseBadgeCode("BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Rohan Sharma\r\nORG:TechNova Solutions Pvt Ltd;Engineering\r\nTITLE:Software Engineer\r\nUID:EMP-10234\r\nEND:VCARD"))
+	assert.Equal(t, Badge{Name: "Priya Nair", EmployeeID: "GX2291", Company: "Globex"},
+		ParseBadgeCode(`{"emp_id": "GX2291", "name": "Priya Nair", "company": "Globex", "floor": 4}`))
+	assert.Equal(t, Badge{EmployeeID: "EMP-10234"}, ParseBadgeCode("Emp ID: EMP-10234"))
+	assert.Equal(t, Badge{EmployeeID: "GX2291"}, ParseBadgeCode("gx2291"))
+	assert.Equal(t, Badge{}, ParseBadgeCode("WELCOME"))
+}
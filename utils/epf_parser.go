@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	// establishment code: state, office, 7-digit establishment, 3-digit extension
+	epfEstablishmentRegex = regexp.MustCompile(`\b([A-Z]{5}[0-9]{10})\b`)
+	// member ID: the establishment code and a 7-digit account
+	epfMemberRegex   = regexp.MustCompile(`\b([A-Z]{5}[0-9]{17})\b`)
+	epfUANRegex      = regexp.MustCompile(`(?i)\bUAN\W{0,5}([0-9]{12})\b`)
+	epfWageMonth     = regexp.MustCompile(`^([A-Za-z]{3})[-/ ]?([0-9]{4})\b`)
+	epfDueMonth      = regexp.MustCompile(`(?i)due[\s-]*month\W{0,3}([0-9]{2})([0-9]{4})\b`)
+	epfDate          = regexp.MustCompile(`\b[0-9]{2}[-/][0-9]{2}[-/][0-9]{4}\b`)
+	epfAmount        = regexp.MustCompile(`\b[0-9][0-9,]*(?:\.[0-9]{1,2})?\b`)
+	epfNotContribute = regexp.MustCompile(`(?i)\b(?:int\.|interest|transfer|withdraw|claim|settle)`)
+)
+
+// ParseEPFPassbook extracts the UAN, member name and monthly contributions
+// by establishment from an EPFO member passbook.
+//
+// Each establishment starts at its "Establishment ID/Name BGBNG0012345000 /
+// ACME SOFTWARE PRIVATE LIMITED" line; its contribution rows give the wage
+// month, the transaction date, the due month and the amounts ("Apr-2024
+// 15-05-2024 CR Cont. For Due-Month 052024 30,000 15,000 3,600 2,350 1,250":
+// EPF and EPS wages, employee share, employer share, pension). Interest,
+// transfer and withdrawal rows are not contributions.
+func ParseEPFPassbook(text string) dto.EPFPassbookData {
+	lines := splitAndTrimLines(text)
+	res := dto.EPFPassbookData{Establishments: []dto.EPFEstablishment{}}
+	if m := epfUANRegex.FindStringSubmatch(text); m != nil {
+		res.UAN = m[1]
+	}
+
+	var current *dto.EPFEstablishment
+	establishment := func(id string) *dto.EPFEstablishment {
+		for i := range res.Establishments {
+			if res.Establishments[i].ID == id {
+				return &res.Establishments[i]
+			}
+		}
+		res.Establishments = append(res.Establishments, dto.EPFEstablishment{ID: id, Contributions: []dto.EPFContribution{}})
+		return &res.Establishments[len(res.Establishments)-1]
+	}
+
+	for _, l := range lines {
+		if epfNotContribute.MatchString(l) {
+			continue // a transfer row names the earlier member ID
+		}
+		u := strings.ToUpper(l)
+		if m := epfMemberRegex.FindStringSubmatchIndex(u); m != nil {
+			id := u[m[2]:m[3]]
+			current = establishment(id[:15])
+			current.MemberID = id
+			if res.MemberName == "" {
+				res.MemberName = epfNameAfter(l[m[3]:])
+			}
+			continue
+		}
+		if m := epfEstablishmentRegex.FindStringSubmatchIndex(u); m != nil {
+			current = establishment(u[m[2]:m[3]])
+			if name := epfNameAfter(l[m[3]:]); name != "" {
+				current.Name = name
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if c, ok := epfContribution(l); ok {
+			current.Contributions = append(current.Contributions, c)
+		}
+	}
+
+	for i := range res.Establishments {
+		res.Establishments[i].Contributions = mergeEPFMonths(res.Establishments[i].Contributions)
+	}
+	return res
+}
+
+// epfNameAfter reads the name after an ID ("/ ACME SOFTWARE PRIVATE LIMITED").
+func epfNameAfter(s string) string {
+	return strings.TrimSpace(strings.Trim(strings.TrimSpace(s), "/:-|"))
+}
+
+// epfContribution reads a contribution row. The amounts follow the due
+// month or, without one, the transaction date.
+func epfContribution(l string) (dto.EPFContribution, bool) {
+	var month time.Time
+	wage := epfWageMonth.FindStringSubmatchIndex(l)
+	if wage != nil {
+		t, err := time.Parse("Jan 2006", l[wage[2]:wage[3]]+" "+l[wage[4]:wage[5]])
+		if err != nil {
+			wage = nil
+		}
+		month = t
+	}
+	tail := ""
+	if due := epfDueMonth.FindStringSubmatchIndex(l); due != nil {
+		if wage == nil {
+			t, err := time.Parse("012006", l[due[2]:due[5]])
+			if err != nil {
+				return dto.EPFContribution{}, false
+			}
+			month = t.AddDate(0, -1, 0) // paid the month after the wages
+		}
+		tail = l[due[1]:]
+	} else if dates := epfDate.FindAllStringIndex(l, -1); wage != nil && dates != nil {
+		tail = l[dates[len(dates)-1][1]:]
+	}
+	if month.IsZero() || tail == "" {
+		return dto.EPFContribution{}, false
+	}
+
+	var amounts []float64
+	for _, a := range epfAmount.FindAllString(tail, -1) {
+		amounts = append(amounts, mustParseAmount(a))
+	}
+	c := dto.EPFContribution{Month: month.Format("2006-01")}
+	switch {
+	case len(amounts) >= 5: // EPF wages, EPS wages, shares
+		c.Wages = amounts[0]
+		amounts = amounts[2:]
+	case len(amounts) == 4: // EPF wages, shares
+		c.Wages = amounts[0]
+		amounts = amounts[1:]
+	case len(amounts) < 3:
+		return dto.EPFContribution{}, false
+	}
+	c.EmployeeShare, c.EmployerShare, c.PensionShare = amounts[0], amounts[1], amounts[2]
+	return c, true
+}
+
+// mergeEPFMonths adds up the rows of a month (arrears are paid on a row of
+// their own) and sorts the months.
+func mergeEPFMonths(rows []dto.EPFContribution) []dto.EPFContribution {
+	out := []dto.EPFContribution{}
+	for _, r := range rows {
+		i := slices.IndexFunc(out, func(c dto.EPFContribution) bool { return c.Month == r.Month })
+		if i < 0 {
+			out = append(out, r)
+			continue
+		}
+		out[i].Wages += r.Wages
+		out[i].EmployeeShare += r.EmployeeShare
+		out[i].EmployerShare += r.EmployerShare
+		out[i].PensionShare += r.PensionShare
+	}
+	slices.SortFunc(out, func(a, b dto.EPFContribution) int { return cmp.Compare(a.Month, b.Month) })
+	return out
+}
@@ -0,0 +1,49 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestParseEPFPassbook(t *testing.T) {
+	text := `Employees' Provident Fund Organisation, India
+Member Passbook
+Establishment ID/Name  BGBNG0012345000 / ACME SOFTWARE PRIVATE LIMITED
+Member ID/Name  BGBNG00123450000012345 / RAVI KUMAR
+UAN  100123456789
+Wage Month Transaction Date Transaction Type Particulars Wages EPF Wages EPS Employee Share Employer Share Pension Contribution
+MAY-2024 14-06-2024 CR Cont. For Due-Month 062024 30,000 15,000 3,600 2,350 1,250
+Apr-2024 15-05-2024 CR Cont. For Due-Month 052024 30,000 15,000 3,600 2,350 1,250
+Apr-2024 20-06-2024 CR Cont. For Due-Month 052024 5,000 0 600 600 0
+Int. Updated upto 31/03/2025 12,500 8,200 0
+Establishment ID/Name  MHBAN0054321000 / GLOBEX INDIA PVT LTD
+Member ID/Name  MHBAN00543210000067890 / RAVI KUMAR
+15-08-2024 CR Cont. For Due-Month 082024 40,000 15,000 4,800 3,550 1,250
+Transfer In from BGBNG00123450000012345 45,000 30,000 0
+Aug-2024 15-09-2024 CR Cont. For Due-Month 092024 40,000 15,000 4,800 3,550 1,250`
+
+	data := ParseEPFPassbook(text)
+	assert.Equal(t, "100123456789", data.UAN)
+	assert.Equal(t, "RAVI KUMAR", data.MemberName)
+	require.Len(t, data.Establishments, 2)
+
+	acme := data.Establishments[0]
+	assert.Equal(t, "BGBNG0012345000", acme.ID)
+	assert.Equal(t, "ACME SOFTWARE PRIVATE LIMITED", acme.Name)
+	assert.Equal(t, "BGBNG00123450000012345", acme.MemberID)
+	assert.Equal(t, []dto.EPFContribution{
+		{Month: "2024-04", Wages: 35000, EmployeeShare: 4200, EmployerShare: 2950, PensionShare: 1250},
+		{Month: "2024-05", Wages: 30000, EmployeeShare: 3600, EmployerShare: 2350, PensionShare: 1250},
+	}, acme.Contributions)
+
+	globex := data.Establishments[1]
+	assert.Equal(t, "GLOBEX INDIA PVT LTD", globex.Name)
+	require.Len(t, globex.Contributions, 2, "the transfer is not a contribution")
+	assert.Equal(t, "2024-07", globex.Contributions[0].Month, "wage month is the month before the due month")
+	assert.Equal(t, 4800.0, globex.Contributions[0].EmployeeShare)
+	assert.Equal(t, "2024-08", globex.Contributions[1].Month)
+}
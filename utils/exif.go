@@ -0,0 +1,178 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EXIFInfo holds the handful of EXIF tags the service cares about.
+type EXIFInfo struct {
+	Make             string
+	Model            string
+	Software         string
+	DateTime         *time.Time // IFD0 DateTime (last modified)
+	DateTimeOriginal *time.Time // Exif DateTimeOriginal (capture time)
+	Orientation      int
+}
+
+const (
+	exifTagMake             = 0x010F
+	exifTagModel            = 0x0110
+	exifTagOrientation      = 0x0112
+	exifTagSoftware         = 0x0131
+	exifTagDateTime         = 0x0132
+	exifTagExifIFD          = 0x8769
+	exifTagDateTimeOriginal = 0x9003
+)
+
+// ParseEXIF extracts EXIF metadata from a JPEG file.
+// Returns an error if the image has no EXIF segment.
+func ParseEXIF(data []byte) (*EXIFInfo, error) {
+	tiff, err := findEXIFSegment(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("invalid TIFF byte order")
+	}
+
+	info := &EXIFInfo{}
+	ifd0 := order.Uint32(tiff[4:8])
+
+	var exifIFD uint32
+	err = walkIFD(tiff, order, ifd0, func(tag, typ uint16, count uint32, value []byte) {
+		switch tag {
+		case exifTagMake:
+			info.Make = exifString(tiff, order, typ, count, value)
+		case exifTagModel:
+			info.Model = exifString(tiff, order, typ, count, value)
+		case exifTagSoftware:
+			info.Software = exifString(tiff, order, typ, count, value)
+		case exifTagDateTime:
+			info.DateTime = parseEXIFDate(exifString(tiff, order, typ, count, value))
+		case exifTagOrientation:
+			if typ == 3 {
+				info.Orientation = int(order.Uint16(value[:2]))
+			}
+		case exifTagExifIFD:
+			exifIFD = order.Uint32(value)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if exifIFD != 0 {
+		_ = walkIFD(tiff, order, exifIFD, func(tag, typ uint16, count uint32, value []byte) {
+			if tag == exifTagDateTimeOriginal {
+				info.DateTimeOriginal = parseEXIFDate(exifString(tiff, order, typ, count, value))
+			}
+		})
+	}
+
+	return info, nil
+}
+
+// CaptureDate returns the best available capture date from the EXIF data.
+func (e *EXIFInfo) CaptureDate() *time.Time {
+	if e == nil {
+		return nil
+	}
+	if e.DateTimeOriginal != nil {
+		return e.DateTimeOriginal
+	}
+	return e.DateTime
+}
+
+// findEXIFSegment walks JPEG markers until it finds the APP1 "Exif" segment
+// and returns the TIFF payload inside it.
+func findEXIFSegment(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG file")
+	}
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("malformed JPEG marker")
+		}
+		marker := data[pos+1]
+		// Start of scan: no metadata beyond this point
+		if marker == 0xDA {
+			break
+		}
+		size := int(binary.BigEndian.Uint16(data[pos+2 : pos+4]))
+		if size < 2 || pos+2+size > len(data) {
+			return nil, fmt.Errorf("truncated JPEG segment")
+		}
+		segment := data[pos+4 : pos+2+size]
+		if marker == 0xE1 && bytes.HasPrefix(segment, []byte("Exif\x00\x00")) {
+			tiff := segment[6:]
+			if len(tiff) < 8 {
+				return nil, fmt.Errorf("truncated EXIF header")
+			}
+			return tiff, nil
+		}
+		pos += 2 + size
+	}
+
+	return nil, fmt.Errorf("no EXIF segment found")
+}
+
+// walkIFD calls fn for every entry of the IFD at offset.
+// value is the raw 4-byte value/offset field of the entry.
+func walkIFD(tiff []byte, order binary.ByteOrder, offset uint32, fn func(tag, typ uint16, count uint32, value []byte)) error {
+	if int(offset)+2 > len(tiff) {
+		return fmt.Errorf("IFD offset out of range")
+	}
+	n := int(order.Uint16(tiff[offset : offset+2]))
+	start := int(offset) + 2
+	if start+n*12 > len(tiff) {
+		return fmt.Errorf("IFD entries out of range")
+	}
+
+	for i := 0; i < n; i++ {
+		e := tiff[start+i*12 : start+(i+1)*12]
+		fn(order.Uint16(e[0:2]), order.Uint16(e[2:4]), order.Uint32(e[4:8]), e[8:12])
+	}
+	return nil
+}
+
+// exifString decodes an ASCII (type 2) EXIF value.
+func exifString(tiff []byte, order binary.ByteOrder, typ uint16, count uint32, value []byte) string {
+	if typ != 2 || count == 0 {
+		return ""
+	}
+
+	var raw []byte
+	if count <= 4 {
+		raw = value[:count]
+	} else {
+		off := order.Uint32(value)
+		if int(off)+int(count) > len(tiff) {
+			return ""
+		}
+		raw = tiff[off : off+count]
+	}
+
+	return strings.TrimSpace(strings.TrimRight(string(raw), "\x00"))
+}
+
+// parseEXIFDate parses the "YYYY:MM:DD HH:MM:SS" EXIF date format.
+func parseEXIFDate(s string) *time.Time {
+	t, err := time.Parse("2006:01:02 15:04:05", s)
+	if err != nil {
+		return nil
+	}
+	return &t
+}
@@ -0,0 +1,166 @@
+// Package face locates and crops the holder's portrait on Indian ID documents
+// (Aadhaar, PAN, driving licence, passport) so it can be matched against a
+// selfie downstream.
+//
+// There is no learned detector: the portrait is the largest skin-toned blob
+// of face-like proportions inside the area where the document type prints
+// its photo. That is reliable on card scans and photos of cards, where the
+// portrait is the only large skin-coloured region.
+package face
+
+import (
+	"bytes"
+	"encoding/base64"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+)
+
+// Document types with a known photo position.
+const (
+	DocAadhaar  = "aadhaar"
+	DocPAN      = "pan"
+	DocDL       = "driving_license"
+	DocPassport = "passport"
+)
+
+// rect is a region in fractions of the page width/height.
+type rect struct{ x0, y0, x1, y1 float64 }
+
+// searchRegions are where each document prints the portrait; other types, and
+// driving licences (whose layout differs by state), are searched whole.
+var searchRegions = map[string]rect{
+	DocAadhaar:  {0, 0.15, 0.45, 0.95},
+	DocPAN:      {0, 0.2, 0.45, 1},
+	DocPassport: {0, 0.05, 0.45, 0.85},
+}
+
+// gridWidth is the width the search region is sampled at.
+const gridWidth = 160
+
+// Detect returns the bounds of the portrait (face with some hair and
+// shoulders, ID-photo framing) on img, or false when none is found.
+func Detect(img image.Image, docType string) (image.Rectangle, bool) {
+	b := img.Bounds()
+	r, ok := searchRegions[docType]
+	if !ok {
+		r = rect{0, 0, 1, 1}
+	}
+	region := image.Rect(
+		b.Min.X+int(r.x0*float64(b.Dx())), b.Min.Y+int(r.y0*float64(b.Dy())),
+		b.Min.X+int(r.x1*float64(b.Dx())), b.Min.Y+int(r.y1*float64(b.Dy())),
+	)
+	if region.Dx() < 8 || region.Dy() < 8 {
+		return image.Rectangle{}, false
+	}
+
+	step := region.Dx() / gridWidth
+	if step < 1 {
+		step = 1
+	}
+	w, h := region.Dx()/step, region.Dy()/step
+
+	skin := make([]bool, w*h)
+	for gy := 0; gy < h; gy++ {
+		for gx := 0; gx < w; gx++ {
+			skin[gy*w+gx] = isSkin(img.At(region.Min.X+gx*step, region.Min.Y+gy*step))
+		}
+	}
+
+	blob, size := largestBlob(skin, w, h)
+	// a face covers a fair share of the photo area, not a few stray pixels
+	if size < w*h/200 || size < 16 {
+		return image.Rectangle{}, false
+	}
+	bw, bh := blob.Dx(), blob.Dy()
+	if bh*10 < bw*8 || bh > bw*3 {
+		return image.Rectangle{}, false // not face-shaped
+	}
+
+	// Grid -> page coordinates, then widen to passport-photo framing
+	fx0, fy0 := region.Min.X+blob.Min.X*step, region.Min.Y+blob.Min.Y*step
+	fw, fh := bw*step, bh*step
+	crop := image.Rect(
+		fx0-fw*3/10, fy0-fh*35/100,
+		fx0+fw+fw*3/10, fy0+fh+fh*45/100,
+	).Intersect(b)
+	return crop, !crop.Empty()
+}
+
+// Crop returns the portrait cut out of img.
+func Crop(img image.Image, docType string) (image.Image, bool) {
+	r, ok := Detect(img, docType)
+	if !ok {
+		return nil, false
+	}
+	out := image.NewRGBA(image.Rect(0, 0, r.Dx(), r.Dy()))
+	draw.Draw(out, out.Bounds(), img, r.Min, draw.Src)
+	return out, true
+}
+
+// JPEG encodes img as a JPEG.
+func JPEG(img image.Image) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// EncodeJPEG returns img as a base64 (standard encoding) JPEG.
+func EncodeJPEG(img image.Image) (string, error) {
+	data, err := JPEG(img)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
+
+// isSkin is the YCbCr skin-tone range of Chai & Ngan, which holds across
+// skin tones because it ignores luma.
+func isSkin(c color.Color) bool {
+	r, g, b, _ := c.RGBA()
+	_, cb, cr := color.RGBToYCbCr(uint8(r>>8), uint8(g>>8), uint8(b>>8))
+	return cb >= 77 && cb <= 127 && cr >= 133 && cr <= 173
+}
+
+// largestBlob returns the bounding box and size of the largest 4-connected
+// group of set cells.
+func largestBlob(mask []bool, w, h int) (image.Rectangle, int) {
+	seen := make([]bool, len(mask))
+	var best image.Rectangle
+	bestSize := 0
+	var queue []int
+
+	for start := range mask {
+		if !mask[start] || seen[start] {
+			continue
+		}
+		seen[start] = true
+		queue = append(queue[:0], start)
+		box := image.Rect(start%w, start/w, start%w+1, start/w+1)
+		size := 0
+		for len(queue) > 0 {
+			i := queue[len(queue)-1]
+			queue = queue[:len(queue)-1]
+			size++
+			x, y := i%w, i/w
+			box = box.Union(image.Rect(x, y, x+1, y+1))
+			for _, n := range [4][2]int{{x - 1, y}, {x + 1, y}, {x, y - 1}, {x, y + 1}} {
+				if n[0] < 0 || n[1] < 0 || n[0] >= w || n[1] >= h {
+					continue
+				}
+				j := n[1]*w + n[0]
+				if mask[j] && !seen[j] {
+					seen[j] = true
+					queue = append(queue, j)
+				}
+			}
+		}
+		if size > bestSize {
+			best, bestSize = box, size
+		}
+	}
+	return best, bestSize
+}
@@ -0,0 +1,51 @@
+package face
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// card draws a white 860x540 card with a dark text band and, optionally, an
+// oval skin-toned face in the photo area on the left.
+func card(withFace bool) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 860, 540))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(img, image.Rect(420, 200, 800, 230), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	if withFace {
+		skin := color.RGBA{R: 198, G: 134, B: 104, A: 255}
+		cx, cy, rx, ry := 170, 300, 70, 95
+		for y := cy - ry; y <= cy+ry; y++ {
+			for x := cx - rx; x <= cx+rx; x++ {
+				dx, dy := float64(x-cx)/float64(rx), float64(y-cy)/float64(ry)
+				if dx*dx+dy*dy <= 1 {
+					img.Set(x, y, skin)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func TestDetectPortrait(t *testing.T) {
+	r, ok := Detect(card(true), DocAadhaar)
+	require.True(t, ok)
+	assert.True(t, image.Pt(170, 300).In(r), "crop %v misses the face", r)
+	assert.True(t, r.Min.Y < 205, "crop %v should include the hair above the face", r)
+	assert.Less(t, r.Max.X, 400)
+
+	img, ok := Crop(card(true), DocAadhaar)
+	require.True(t, ok)
+	b64, err := EncodeJPEG(img)
+	require.NoError(t, err)
+	assert.NotEmpty(t, b64)
+}
+
+func TestDetectNoPortrait(t *testing.T) {
+	_, ok := Detect(card(false), DocPAN)
+	assert.False(t, ok)
+}
@@ -0,0 +1,265 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// MaxSchemaFields bounds the fields of a caller-supplied schema.
+const MaxSchemaFields = 100
+
+var (
+	fieldDateISORe     = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	fieldDateNumericRe = regexp.MustCompile(`\b(\d{1,2})[/.\-](\d{1,2})[/.\-](\d{4}|\d{2})\b`)
+	fieldDateMonthRe   = regexp.MustCompile(`(?i)\b(\d{1,2})(?:st|nd|rd|th)?[\s\-]+([a-z]{3,9})\.?[\s,\-]+(\d{4}|\d{2})\b`)
+	// a value ends at a column gap
+	fieldGapRe = regexp.MustCompile(`\s{2,}|\t`)
+)
+
+// FieldExtractor reads the fields of a caller-supplied schema (see
+// dto.FieldSchema) from OCR output by label proximity: a value is what
+// follows one of the field's labels on its line, in the word layout
+// (ValueRightOf) or the text, or else the line below.
+type FieldExtractor struct {
+	fields []schemaField
+	// every label of the schema, lower case: a value ends where another starts
+	labels []string
+}
+
+type schemaField struct {
+	dto.FieldSpec
+	pattern *regexp.Regexp
+}
+
+// NewFieldExtractor checks and compiles a schema.
+func NewFieldExtractor(schema dto.FieldSchema) (*FieldExtractor, error) {
+	if len(schema.Fields) == 0 {
+		return nil, errors.New("schema has no fields")
+	}
+	if len(schema.Fields) > MaxSchemaFields {
+		return nil, fmt.Errorf("schema has %d fields, at most %d are allowed", len(schema.Fields), MaxSchemaFields)
+	}
+	e := &FieldExtractor{}
+	seen := map[string]bool{}
+	for i, spec := range schema.Fields {
+		if spec.Name == "" {
+			return nil, fmt.Errorf("field %d has no name", i+1)
+		}
+		if seen[spec.Name] {
+			return nil, fmt.Errorf("field %s is given twice", spec.Name)
+		}
+		seen[spec.Name] = true
+
+		f := schemaField{FieldSpec: spec}
+		f.Labels = nil
+		for _, l := range spec.Labels {
+			if l = strings.TrimSpace(l); l != "" {
+				f.Labels = append(f.Labels, l)
+				e.labels = append(e.labels, strings.ToLower(l))
+			}
+		}
+		if f.Type == "" {
+			f.Type = dto.FieldTypeString
+		}
+		switch f.Type {
+		case dto.FieldTypeString, dto.FieldTypeDate, dto.FieldTypeAmount:
+			if len(f.Labels) == 0 {
+				return nil, fmt.Errorf("field %s has no labels", f.Name)
+			}
+			if f.Pattern != "" {
+				return nil, fmt.Errorf("field %s: pattern is only for regex fields", f.Name)
+			}
+		case dto.FieldTypeRegex:
+			if f.Pattern == "" {
+				return nil, fmt.Errorf("field %s has no pattern", f.Name)
+			}
+			var err error
+			if f.pattern, err = regexp.Compile(f.Pattern); err != nil {
+				return nil, fmt.Errorf("field %s: invalid pattern: %w", f.Name, err)
+			}
+		default:
+			return nil, fmt.Errorf("field %s: unknown type %q", f.Name, f.Type)
+		}
+		e.fields = append(e.fields, f)
+	}
+	return e, nil
+}
+
+// Extract returns the fields found, in schema order, and the names of those
+// that were not.
+func (e *FieldExtractor) Extract(text string, words []dto.OCRWord) ([]dto.ExtractedField, []string) {
+	lines := strings.Split(text, "\n")
+	fields := make([]dto.ExtractedField, 0, len(e.fields))
+	missing := []string{}
+	for _, f := range e.fields {
+		if v, ok := e.extract(f, text, lines, words); ok {
+			fields = append(fields, v)
+		} else {
+			missing = append(missing, f.Name)
+		}
+	}
+	return fields, missing
+}
+
+func (e *FieldExtractor) extract(f schemaField, text string, lines []string, words []dto.OCRWord) (dto.ExtractedField, bool) {
+	for _, label := range f.Labels {
+		if raw := e.cut(ValueRightOf(words, label)); raw != "" {
+			if v, ok := f.parse(raw); ok {
+				return dto.ExtractedField{Name: f.Name, Value: v, Raw: raw, Label: label, Source: dto.FieldSourceLayout}, true
+			}
+		}
+		for _, raw := range e.textValues(lines, label) {
+			if v, ok := f.parse(raw); ok {
+				return dto.ExtractedField{Name: f.Name, Value: v, Raw: raw, Label: label, Source: dto.FieldSourceText}, true
+			}
+		}
+	}
+	if len(f.Labels) == 0 {
+		if m := f.pattern.FindStringSubmatch(text); m != nil {
+			return dto.ExtractedField{Name: f.Name, Value: regexValue(m), Raw: m[0], Source: dto.FieldSourceText}, true
+		}
+	}
+	return dto.ExtractedField{}, false
+}
+
+// textValues are the candidate values of label in the text: the rest of each
+// line it is on, then the next non-empty line.
+func (e *FieldExtractor) textValues(lines []string, label string) []string {
+	label = strings.ToLower(label)
+	var values []string
+	for i, line := range lines {
+		_, end, ok := findLabel(strings.ToLower(line), label)
+		if !ok || end > len(line) { // lower-casing may change byte lengths
+			continue
+		}
+		if v := e.cut(line[end:]); v != "" {
+			values = append(values, v)
+		}
+		for _, next := range lines[i+1:] {
+			if strings.TrimSpace(next) != "" {
+				if v := e.cut(next); v != "" {
+					values = append(values, v)
+				}
+				break
+			}
+		}
+	}
+	return values
+}
+
+// cut trims a value of the separators after its label and ends it at a
+// column gap or at another label of the schema.
+func (e *FieldExtractor) cut(v string) string {
+	v = strings.TrimLeft(v, " \t:-–=")
+	if loc := fieldGapRe.FindStringIndex(v); loc != nil {
+		v = v[:loc[0]]
+	}
+	lower := strings.ToLower(v)
+	for _, label := range e.labels {
+		if start, _, ok := findLabel(lower, label); ok && start > 0 && start <= len(v) {
+			v, lower = v[:start], lower[:start]
+		}
+	}
+	return strings.TrimRight(strings.TrimSpace(v), " :-")
+}
+
+// findLabel finds label in line as whole words; both are lower case.
+func findLabel(line, label string) (start, end int, ok bool) {
+	for from := 0; ; {
+		i := strings.Index(line[from:], label)
+		if i < 0 {
+			return 0, 0, false
+		}
+		start, end = from+i, from+i+len(label)
+		if (start == 0 || !isWordByte(line[start-1])) && (end == len(line) || !isWordByte(line[end])) {
+			return start, end, true
+		}
+		from = start + 1
+	}
+}
+
+func isWordByte(b byte) bool {
+	return b >= 0x80 || unicode.IsLetter(rune(b)) || unicode.IsDigit(rune(b))
+}
+
+// parse reads raw as the field's type.
+func (f schemaField) parse(raw string) (interface{}, bool) {
+	if f.Type == dto.FieldTypeRegex {
+		if m := f.pattern.FindStringSubmatch(raw); m != nil {
+			return regexValue(m), true
+		}
+		return nil, false
+	}
+	return ParseFieldValue(f.Type, raw)
+}
+
+// ParseFieldValue reads raw as a value of a field type other than regex: the
+// first amount in it as a float64, the first date as YYYY-MM-DD, or a string
+// as it is.
+func ParseFieldValue(fieldType, raw string) (interface{}, bool) {
+	switch fieldType {
+	case dto.FieldTypeAmount:
+		for _, m := range layoutAmountRe.FindAllString(raw, -1) {
+			if amount, err := strconv.ParseFloat(strings.ReplaceAll(m, ",", ""), 64); err == nil {
+				return amount, true
+			}
+		}
+		return nil, false
+	case dto.FieldTypeDate:
+		iso, ok := parseFieldDate(raw)
+		return iso, ok
+	}
+	return raw, raw != ""
+}
+
+// regexValue is the first group of a match, or the whole match.
+func regexValue(m []string) string {
+	if len(m) > 1 {
+		return m[1]
+	}
+	return m[0]
+}
+
+// parseFieldDate reads the first date in s, day first ("15/03/2024",
+// "15-03-24", "15 Mar 2024", "15th March, 2024") or ISO, as YYYY-MM-DD.
+func parseFieldDate(s string) (string, bool) {
+	var y, m, d int
+	switch {
+	case fieldDateISORe.MatchString(s):
+		p := fieldDateISORe.FindStringSubmatch(s)
+		y, m, d = atoi(p[1]), atoi(p[2]), atoi(p[3])
+	case fieldDateNumericRe.MatchString(s):
+		p := fieldDateNumericRe.FindStringSubmatch(s)
+		d, m, y = atoi(p[1]), atoi(p[2]), atoi(p[3])
+	case fieldDateMonthRe.MatchString(s):
+		p := fieldDateMonthRe.FindStringSubmatch(s)
+		name := strings.ToLower(p[2])
+		month, ok := monthNumbers[name[:3]]
+		if !ok || !strings.HasPrefix(strings.ToLower(month.String()), name) {
+			return "", false
+		}
+		d, m, y = atoi(p[1]), int(month), atoi(p[3])
+	default:
+		return "", false
+	}
+	if y < 100 {
+		y += 2000
+	}
+	t := time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	if t.Day() != d || int(t.Month()) != m {
+		return "", false
+	}
+	return t.Format("2006-01-02"), true
+}
+
+func atoi(s string) int {
+	n, _ := strconv.Atoi(s)
+	return n
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestFieldExtractor(t *testing.T) {
+	e, err := NewFieldExtractor(dto.FieldSchema{Fields: []dto.FieldSpec{
+		{Name: "invoice_no", Labels: []string{"Invoice No", "Bill No"}},
+		{Name: "invoice_date", Labels: []string{"Invoice Date", "Date"}, Type: dto.FieldTypeDate},
+		{Name: "total", Labels: []string{"Grand Total", "Amount Payable"}, Type: dto.FieldTypeAmount, Required: true},
+		{Name: "gstin", Type: dto.FieldTypeRegex, Pattern: `\b(\d{2}[A-Z]{5}\d{4}[A-Z][1-9A-Z]Z[0-9A-Z])\b`},
+		{Name: "customer", Labels: []string{"Billed To"}},
+		{Name: "po_number", Labels: []string{"PO Number"}},
+	}})
+	require.NoError(t, err)
+
+	text := "ACME TRADERS GSTIN 29ABCDE1234F1Z5\n" +
+		"Invoice No: INV-2024/118 Invoice Date: 15th March, 2024\n" +
+		"Billed To\nRavi Kumar\n" +
+		"Grand Total Rs. 1,18,000.00\n"
+	words := []dto.OCRWord{
+		{Text: "Amount", Page: 1, Box: dto.BoundingBox{X0: 10, Y0: 100, X1: 60, Y1: 110}},
+		{Text: "Payable", Page: 1, Box: dto.BoundingBox{X0: 65, Y0: 100, X1: 120, Y1: 110}},
+		{Text: "1,18,000.00", Page: 1, Box: dto.BoundingBox{X0: 400, Y0: 101, X1: 470, Y1: 111}},
+	}
+	fields, missing := e.Extract(text, words)
+	require.Len(t, fields, 5)
+	assert.Equal(t, dto.ExtractedField{Name: "invoice_no", Value: "INV-2024/118", Raw: "INV-2024/118", Label: "Invoice No", Source: dto.FieldSourceText}, fields[0])
+	assert.Equal(t, "2024-03-15", fields[1].Value)
+	assert.Equal(t, "Invoice Date", fields[1].Label)
+	assert.Equal(t, 118000.0, fields[2].Value)
+	assert.Equal(t, dto.FieldSourceText, fields[2].Source, "the first label is tried first")
+	assert.Equal(t, "29ABCDE1234F1Z5", fields[3].Value)
+	assert.Equal(t, "Ravi Kumar", fields[4].Value, "value on the line below the label")
+	assert.Equal(t, []string{"po_number"}, missing)
+
+	fields, _ = e.Extract("", words)
+	require.Len(t, fields, 1)
+	assert.Equal(t, dto.ExtractedField{Name: "total", Value: 118000.0, Raw: "1,18,000.00", Label: "Amount Payable", Source: dto.FieldSourceLayout}, fields[0])
+}
+
+func TestNewFieldExtractorRejectsBadSchemas(t *testing.T) {
+	for _, spec := range []dto.FieldSpec{
+		{Labels: []string{"Name"}},
+		{Name: "a"},
+		{Name: "a", Labels: []string{"A"}, Type: "number"},
+		{Name: "a", Type: dto.FieldTypeRegex, Pattern: "("},
+		{Name: "a", Labels: []string{"A"}, Pattern: `\d+`},
+	} {
+		_, err := NewFieldExtractor(dto.FieldSchema{Fields: []dto.FieldSpec{spec}})
+		assert.Error(t, err, "%+v", spec)
+	}
+	_, err := NewFieldExtractor(dto.FieldSchema{})
+	assert.Error(t, err)
+}
+
+func TestParseFieldDate(t *testing.T) {
+	for in, want := range map[string]string{
+		"15/03/2024":        "2024-03-15",
+		"on 5-3-24":         "2024-03-05",
+		"2024-03-15":        "2024-03-15",
+		"15 Sept 2024":      "2024-09-15",
+		"1st January, 2025": "2025-01-01",
+		"31/02/2024":        "",
+		"15 Marks 2024":     "",
+		"no date":           "",
+	} {
+		got, _ := parseFieldDate(in)
+		assert.Equal(t, want, got, in)
+	}
+}
@@ -0,0 +1,394 @@
+package fieldtemplate
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+)
+
+// Template describes how to pull fields out of one employer's salary slip or
+// one bank's statement layout. Templates are loaded from YAML files, e.g.:
+//
+//	name: acme_payslip
+//	doc_type: salary_slip
+//	match: ["ACME Technologies", "Pay Slip"]
+//	fields:
+//	  net_salary:
+//	    anchor: "Net\\s*Pay"
+//	    offset: 0
+//	    regex: '([0-9,]+\.\d{2})'
+//	    validators: [amount]
+//	    min: 1000
+//
+// Templates for a payroll product rather than one employer identify the
+// layout by its fingerprint, patterns expected in the first or last lines:
+//
+//	software: Keka
+//	fingerprint:
+//	  header: ["Payslip for the month of"]
+//	  footer: ["Gross Earnings - Total Deductions"]
+type Template struct {
+	Name        string           `yaml:"name"`
+	DocType     string           `yaml:"doc_type"`
+	Software    string           `yaml:"software"` // payroll product the layout comes from
+	Match       []string         `yaml:"match"`
+	Fingerprint *Fingerprint     `yaml:"fingerprint"`
+	Fields      map[string]Field `yaml:"fields"`
+
+	matchRes []*regexp.Regexp
+}
+
+// Fingerprint identifies a layout by patterns in its header (the first Lines
+// lines) and footer (the last Lines lines). A template with a fingerprint
+// only matches when at least one of them is found.
+type Fingerprint struct {
+	Header []string `yaml:"header"`
+	Footer []string `yaml:"footer"`
+	Lines  int      `yaml:"lines"` // default 10
+
+	headerRes []*regexp.Regexp
+	footerRes []*regexp.Regexp
+}
+
+const defaultFingerprintLines = 10
+
+// Field is a single extraction rule.
+//   - Anchor: regex locating the label line (case-insensitive)
+//   - Offset: lines below the anchor where the value lives (0 = same line)
+//   - Regex: applied to the target line; first capture group (or whole match) is the value
+//   - Validators: checks the value must pass; "amount" also makes it numeric
+type Field struct {
+	Anchor     string   `yaml:"anchor"`
+	Offset     int      `yaml:"offset"`
+	Regex      string   `yaml:"regex"`
+	Validators []string `yaml:"validators"`
+	Min        *float64 `yaml:"min"`
+	Max        *float64 `yaml:"max"`
+
+	anchorRe *regexp.Regexp
+	valueRe  *regexp.Regexp
+}
+
+// Registry holds the templates loaded from a directory.
+type Registry struct {
+	templates []*Template
+}
+
+// LoadDir parses every *.yaml / *.yml file in dir. A missing directory yields an empty registry.
+func LoadDir(dir string) (*Registry, error) {
+	reg := &Registry{}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return reg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template dir: %w", err)
+	}
+
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template %s: %w", e.Name(), err)
+		}
+		tpl, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid template %s: %w", e.Name(), err)
+		}
+		reg.templates = append(reg.templates, tpl)
+	}
+
+	sort.Slice(reg.templates, func(i, j int) bool { return reg.templates[i].Name < reg.templates[j].Name })
+	return reg, nil
+}
+
+// Parse decodes and compiles a single YAML template.
+func Parse(data []byte) (*Template, error) {
+	var tpl Template
+	if err := yaml.UnmarshalWithOptions(data, &tpl, yaml.DisallowUnknownField()); err != nil {
+		return nil, err
+	}
+	if tpl.Name == "" || tpl.DocType == "" {
+		return nil, fmt.Errorf("name and doc_type are required")
+	}
+	fp := tpl.Fingerprint
+	if len(tpl.Match) == 0 && (fp == nil || len(fp.Header)+len(fp.Footer) == 0) {
+		return nil, fmt.Errorf("at least one match or fingerprint pattern is required")
+	}
+
+	var err error
+	if tpl.matchRes, err = compilePatterns(tpl.Match); err != nil {
+		return nil, err
+	}
+	if fp != nil {
+		if fp.headerRes, err = compilePatterns(fp.Header); err != nil {
+			return nil, err
+		}
+		if fp.footerRes, err = compilePatterns(fp.Footer); err != nil {
+			return nil, err
+		}
+		if fp.Lines <= 0 {
+			fp.Lines = defaultFingerprintLines
+		}
+	}
+
+	for name, f := range tpl.Fields {
+		var err error
+		if f.anchorRe, err = regexp.Compile("(?i)" + f.Anchor); err != nil || f.Anchor == "" {
+			return nil, fmt.Errorf("field %s: bad anchor %q", name, f.Anchor)
+		}
+		if f.Regex != "" {
+			if f.valueRe, err = regexp.Compile(f.Regex); err != nil {
+				return nil, fmt.Errorf("field %s: bad regex: %w", name, err)
+			}
+		}
+		for _, v := range f.Validators {
+			if _, ok := validators[v]; !ok {
+				return nil, fmt.Errorf("field %s: unknown validator %q", name, v)
+			}
+		}
+		tpl.Fields[name] = f
+	}
+
+	return &tpl, nil
+}
+
+func compilePatterns(patterns []string) ([]*regexp.Regexp, error) {
+	res := make([]*regexp.Regexp, 0, len(patterns))
+	for _, m := range patterns {
+		re, err := regexp.Compile("(?i)" + m)
+		if err != nil {
+			return nil, fmt.Errorf("bad match pattern %q: %w", m, err)
+		}
+		res = append(res, re)
+	}
+	return res, nil
+}
+
+//go:embed builtin/*.yaml
+var builtinFS embed.FS
+
+// AddBuiltin registers the layouts of common payroll software (Zoho Payroll,
+// greytHR, Keka, ADP, SAP) shipped with the service, after the loaded
+// templates so that those win ties. A loaded template of the same name
+// replaces the built-in one.
+func (r *Registry) AddBuiltin() error {
+	have := map[string]bool{}
+	for _, tpl := range r.templates {
+		have[tpl.Name] = true
+	}
+	files, err := fs.Glob(builtinFS, "builtin/*.yaml")
+	if err != nil {
+		return err
+	}
+	for _, name := range files {
+		data, err := builtinFS.ReadFile(name)
+		if err != nil {
+			return err
+		}
+		tpl, err := Parse(data)
+		if err != nil {
+			return fmt.Errorf("invalid built-in template %s: %w", name, err)
+		}
+		if !have[tpl.Name] {
+			r.templates = append(r.templates, tpl)
+		}
+	}
+	return nil
+}
+
+// Templates returns the loaded templates.
+func (r *Registry) Templates() []*Template {
+	return r.templates
+}
+
+// Add registers an already-parsed template.
+func (r *Registry) Add(tpl *Template) {
+	r.templates = append(r.templates, tpl)
+}
+
+// Match returns the template for docType that fits text best: of those whose
+// match patterns all occur (and whose fingerprint, if any, is found), the one
+// with the most patterns found; the first registered on a tie.
+func (r *Registry) Match(docType, text string) *Template {
+	if r == nil {
+		return nil
+	}
+	var best *Template
+	bestScore := -1
+	for _, tpl := range r.templates {
+		if tpl.DocType != docType || !tpl.Matches(text) {
+			continue
+		}
+		if score := len(tpl.matchRes) + tpl.Fingerprint.score(text); score > bestScore {
+			best, bestScore = tpl, score
+		}
+	}
+	return best
+}
+
+// Matches reports whether every match pattern occurs in text and, for a
+// template with a fingerprint, whether the fingerprint is found.
+func (t *Template) Matches(text string) bool {
+	for _, re := range t.matchRes {
+		if !re.MatchString(text) {
+			return false
+		}
+	}
+	return t.Fingerprint == nil || t.Fingerprint.score(text) > 0
+}
+
+// score counts the header patterns found in the first Lines non-empty lines
+// of text and the footer patterns found in the last Lines.
+func (f *Fingerprint) score(text string) int {
+	if f == nil {
+		return 0
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.ReplaceAll(text, "\r", ""), "\n") {
+		if l = strings.TrimSpace(l); l != "" {
+			lines = append(lines, l)
+		}
+	}
+	n := min(f.Lines, len(lines))
+	header := strings.Join(lines[:n], "\n")
+	footer := strings.Join(lines[len(lines)-n:], "\n")
+
+	score := 0
+	for _, re := range f.headerRes {
+		if re.MatchString(header) {
+			score++
+		}
+	}
+	for _, re := range f.footerRes {
+		if re.MatchString(footer) {
+			score++
+		}
+	}
+	return score
+}
+
+// Extract runs every field rule against text. Values that fail validation are omitted.
+// Amount fields are returned as float64, everything else as string.
+func (t *Template) Extract(text string) map[string]interface{} {
+	lines := strings.Split(strings.ReplaceAll(text, "\r", ""), "\n")
+	out := make(map[string]interface{})
+
+	for name, f := range t.Fields {
+		if v, ok := f.extract(lines); ok {
+			out[name] = v
+		}
+	}
+	return out
+}
+
+// Apply extracts fields and overlays them onto target (a pointer to a DTO),
+// matching field names against the DTO's json tags. It returns how many
+// fields were found in text.
+func (t *Template) Apply(text string, target interface{}) (int, error) {
+	values := t.Extract(text)
+	if len(values) == 0 {
+		return 0, nil
+	}
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return 0, err
+	}
+	if err := json.Unmarshal(raw, target); err != nil {
+		return 0, err
+	}
+	return len(values), nil
+}
+
+func (f Field) extract(lines []string) (interface{}, bool) {
+	for i, line := range lines {
+		if !f.anchorRe.MatchString(line) {
+			continue
+		}
+		idx := i + f.Offset
+		if idx < 0 || idx >= len(lines) {
+			continue
+		}
+
+		value := strings.TrimSpace(lines[idx])
+		if f.valueRe != nil {
+			m := f.valueRe.FindStringSubmatch(value)
+			if m == nil {
+				continue
+			}
+			value = m[0]
+			if len(m) > 1 {
+				value = m[1]
+			}
+			value = strings.TrimSpace(value)
+		}
+
+		if v, ok := f.validate(value); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func (f Field) validate(value string) (interface{}, bool) {
+	if value == "" {
+		return nil, false
+	}
+
+	var result interface{} = value
+	for _, name := range f.Validators {
+		v, ok := validators[name](value)
+		if !ok {
+			return nil, false
+		}
+		if name == "amount" {
+			result = v
+		}
+	}
+
+	if amount, ok := result.(float64); ok {
+		if f.Min != nil && amount < *f.Min {
+			return nil, false
+		}
+		if f.Max != nil && amount > *f.Max {
+			return nil, false
+		}
+	}
+	return result, true
+}
+
+var (
+	nameRe    = regexp.MustCompile(`^[A-Za-z][A-Za-z .']{1,60}$`)
+	accountRe = regexp.MustCompile(`^[0-9]{9,18}$`)
+	ifscRe    = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	panRe     = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+	dateRe    = regexp.MustCompile(`^\d{1,2}[/-]\d{1,2}[/-]\d{2,4}$`)
+)
+
+// validators maps validator names usable in templates to their checks.
+var validators = map[string]func(string) (interface{}, bool){
+	"required": func(s string) (interface{}, bool) { return s, s != "" },
+	"amount": func(s string) (interface{}, bool) {
+		s = strings.NewReplacer(",", "", "₹", "", "Rs.", "", "Rs", "", "INR", "").Replace(s)
+		f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+		return f, err == nil
+	},
+	"name":           func(s string) (interface{}, bool) { return s, nameRe.MatchString(s) },
+	"account_number": func(s string) (interface{}, bool) { return s, accountRe.MatchString(strings.ReplaceAll(s, " ", "")) },
+	"ifsc":           func(s string) (interface{}, bool) { return s, ifscRe.MatchString(strings.ToUpper(s)) },
+	"pan":            func(s string) (interface{}, bool) { return s, panRe.MatchString(strings.ToUpper(s)) },
+	"date":           func(s string) (interface{}, bool) { return s, dateRe.MatchString(s) },
+}
@@ -0,0 +1,128 @@
+package fieldtemplate
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const acmeTemplate = `
+name: acme_payslip
+doc_type: salary_slip
+match: ["ACME Technologies", "Pay\\s*Slip"]
+fields:
+  employee_name:
+    anchor: "Employee Name"
+    regex: 'Employee Name\s*:\s*(.+)'
+    validators: [name]
+  net_salary:
+    anchor: "Net Pay"
+    offset: 1
+    regex: '([0-9,]+\.\d{2})'
+    validators: [amount]
+    min: 1000
+`
+
+func TestTemplateApply(t *testing.T) {
+	tpl, err := Parse([]byte(acmeTemplate))
+	require.NoError(t, err)
+
+	text := "ACME Technologies Pvt Ltd\nPay Slip for March 2025\nEmployee Name : Priya Nair\nNet Pay\nRs. 72,450.00\n"
+
+	reg := &Registry{}
+	reg.Add(tpl)
+	assert.Nil(t, reg.Match("bank_statement", text))
+	require.NotNil(t, reg.Match("salary_slip", text))
+
+	slip := dto.SalarySlipData{PayMonth: "March 2025"}
+	n, err := tpl.Apply(text, &slip)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	assert.Equal(t, "Priya Nair", slip.EmployeeName)
+	assert.Equal(t, 72450.0, slip.NetSalary)
+	assert.Equal(t, "March 2025", slip.PayMonth)
+
+	// the layout matches, but none of its fields are found
+	n, err = tpl.Apply("ACME Technologies Pay Slip\nEmployee: Priya Nair", &slip)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+}
+
+func TestParseRejectsUnknownValidator(t *testing.T) {
+	_, err := Parse([]byte("name: x\ndoc_type: salary_slip\nmatch: [x]\nfields:\n  a:\n    anchor: a\n    validators: [nope]\n"))
+	assert.Error(t, err)
+}
+
+func TestShippedTemplatesLoad(t *testing.T) {
+	reg, err := LoadDir("../../templates")
+	require.NoError(t, err)
+	assert.NotEmpty(t, reg.Templates())
+}
+
+func TestBuiltinFingerprints(t *testing.T) {
+	reg := &Registry{}
+	require.NoError(t, reg.AddBuiltin())
+
+	keka := `ACME SOFTWARE PRIVATE LIMITED
+Payslip for the month of October 2025
+Employee Name: Ravi Kumar Employee Number: AC102
+Designation: Software Engineer Department: Engineering
+Bank Account No: 123456789012
+Earnings Amount Deductions Amount
+Basic 40,000.00 PF 1,800.00
+Total Net Payable ₹58,200.00 (Rupees Fifty Eight Thousand Two Hundred Only)
+**Total Net Payable = Gross Earnings - Total Deductions`
+
+	tpl := reg.Match("salary_slip", keka)
+	require.NotNil(t, tpl)
+	assert.Equal(t, "keka", tpl.Name)
+	assert.Equal(t, "Keka", tpl.Software)
+
+	var slip dto.SalarySlipData
+	_, err := tpl.Apply(keka, &slip)
+	require.NoError(t, err)
+	assert.Equal(t, "Ravi Kumar", slip.EmployeeName)
+	assert.Equal(t, "October 2025", slip.PayMonth)
+	assert.Equal(t, 58200.0, slip.NetSalary)
+	assert.Equal(t, "Software Engineer", slip.Designation)
+	assert.Equal(t, "123456789012", slip.AccountNumber)
+
+	greythr := `Globex Corporation
+Payslip for the month of September 2025
+Name Priya Nair Employee No G1043
+Bank Name HDFC Bank Bank Account No 50100123456789
+Net Pay for the month ( Total Earnings - Total Deductions): 64,500.00
+This is a system generated payslip and does not require signature`
+
+	tpl = reg.Match("salary_slip", greythr)
+	require.NotNil(t, tpl)
+	assert.Equal(t, "greythr", tpl.Name)
+	slip = dto.SalarySlipData{}
+	_, err = tpl.Apply(greythr, &slip)
+	require.NoError(t, err)
+	assert.Equal(t, "Priya Nair", slip.EmployeeName)
+	assert.Equal(t, 64500.0, slip.NetSalary)
+	assert.Equal(t, "HDFC Bank", slip.BankName)
+
+	assert.Nil(t, reg.Match("salary_slip", "Salary slip\nNet Pay 40,000.00"))
+}
+
+func TestLoadedTemplateReplacesBuiltin(t *testing.T) {
+	own, err := Parse([]byte("name: keka\ndoc_type: salary_slip\nmatch: ['Total Net Payable']\n"))
+	require.NoError(t, err)
+	reg := &Registry{}
+	reg.Add(own)
+	require.NoError(t, reg.AddBuiltin())
+
+	n := 0
+	for _, tpl := range reg.Templates() {
+		if tpl.Name == "keka" {
+			n++
+			assert.Same(t, own, tpl)
+		}
+	}
+	assert.Equal(t, 1, n)
+}
@@ -0,0 +1,164 @@
+// Package filetype identifies uploads by their first bytes, whatever their
+// name or declared Content-Type say.
+package filetype
+
+import (
+	"bytes"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/utils/imageconv"
+	"github.com/Aashish23092/ocr-income-verification/utils/xlsx"
+	"github.com/Aashish23092/ocr-income-verification/utils/ziparchive"
+)
+
+// Format is a file format the service reads.
+type Format string
+
+const (
+	PDF  Format = "pdf"
+	PNG  Format = "png"
+	JPEG Format = "jpeg"
+	TIFF Format = "tiff"
+	HEIF Format = "heif" // HEIC and other HEIF images, converted to PNG
+	WebP Format = "webp" // converted to PNG
+	ZIP  Format = "zip"
+	CSV  Format = "csv"  // told by name or declared type, then checked to be text
+	XLSX Format = "xlsx" // a ZIP holding a workbook
+	JSON Format = "json" // told by name or declared type, then checked to be an object or array
+)
+
+var (
+	// Photos are the image formats, read directly or after conversion.
+	Photos = []Format{PNG, JPEG, TIFF, HEIF, WebP}
+	// Documents are the formats of a scanned or digital document.
+	Documents = append([]Format{PDF}, Photos...)
+	// Spreadsheets are the formats banks export statements in.
+	Spreadsheets = []Format{CSV, XLSX}
+)
+
+// HeaderSize is how many leading bytes Sniff needs.
+const HeaderSize = 16
+
+// Sniff returns the format of data from its magic bytes, or "" when it is
+// none of the formats above.
+func Sniff(data []byte) Format {
+	switch {
+	case bytes.HasPrefix(data, []byte("%PDF-")):
+		return PDF
+	case bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")):
+		return PNG
+	case bytes.HasPrefix(data, []byte("\xff\xd8\xff")):
+		return JPEG
+	case imageconv.IsTIFF(data):
+		return TIFF
+	case ziparchive.IsZip(data):
+		return ZIP
+	}
+	switch imageconv.Detect(data) {
+	case imageconv.FormatHEIF:
+		return HEIF
+	case imageconv.FormatWebP:
+		return WebP
+	}
+	return ""
+}
+
+// textSniffSize is how many leading bytes SniffFile checks to be text.
+const textSniffSize = 512
+
+// SniffFile is Sniff for a whole file of size bytes: it tells XLSX workbooks
+// from other ZIPs by the parts inside, and takes a text file for CSV or JSON
+// when its name or declared type says so (and JSON starts like an object or
+// array). None of them can be told from the first bytes alone.
+func SniffFile(r io.ReaderAt, size int64, name, declaredType string) Format {
+	head := make([]byte, min(size, textSniffSize))
+	n, _ := r.ReadAt(head, 0)
+	head = head[:n]
+
+	format := Sniff(head)
+	switch {
+	case format == ZIP && xlsx.IsWorkbook(r, size):
+		return XLSX
+	case format == "" && isText(head) && isNamed(name, declaredType, ".csv", "text/csv"):
+		return CSV
+	case format == "" && isText(head) && isNamed(name, declaredType, ".json", "application/json") && isJSONStart(head):
+		return JSON
+	}
+	return format
+}
+
+// isNamed reports whether a file has extension ext or declared media type
+// mediaType.
+func isNamed(name, declaredType, ext, mediaType string) bool {
+	declared, _, _ := strings.Cut(declaredType, ";")
+	return strings.EqualFold(filepath.Ext(name), ext) || strings.EqualFold(strings.TrimSpace(declared), mediaType)
+}
+
+func isJSONStart(data []byte) bool {
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return bytes.HasPrefix(data, []byte("{")) || bytes.HasPrefix(data, []byte("["))
+}
+
+// isText reports whether data is text: no control characters other than
+// tabs and line breaks. Bytes above ASCII are allowed, as spreadsheet
+// programs on Windows export CSV in Windows-1252 rather than UTF-8.
+func isText(data []byte) bool {
+	if len(data) == 0 {
+		return false
+	}
+	for _, c := range data {
+		if (c < 0x20 && c != '\t' && c != '\n' && c != '\r') || c == 0x7f {
+			return false
+		}
+	}
+	return true
+}
+
+var mimeTypes = map[Format]string{
+	PDF:  "application/pdf",
+	PNG:  "image/png",
+	JPEG: "image/jpeg",
+	TIFF: "image/tiff",
+	HEIF: "image/heic",
+	WebP: "image/webp",
+	ZIP:  "application/zip",
+	CSV:  "text/csv",
+	XLSX: "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet",
+	JSON: "application/json",
+}
+
+// MimeType is the MIME type of the format, application/octet-stream for an
+// unknown one.
+func (f Format) MimeType() string {
+	if t, ok := mimeTypes[f]; ok {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// String names the format as users know it.
+func (f Format) String() string {
+	switch f {
+	case "":
+		return "unknown"
+	case HEIF:
+		return "HEIC"
+	case WebP:
+		return "WebP"
+	}
+	return strings.ToUpper(string(f))
+}
+
+// Join lists formats for a message: "PDF, PNG or JPEG".
+func Join(formats []Format) string {
+	names := make([]string, len(formats))
+	for i, f := range formats {
+		names[i] = f.String()
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " or " + names[len(names)-1]
+}
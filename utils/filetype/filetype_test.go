@@ -0,0 +1,62 @@
+package filetype
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniff(t *testing.T) {
+	assert.Equal(t, PDF, Sniff([]byte("%PDF-1.7\n")))
+	assert.Equal(t, PNG, Sniff([]byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")))
+	assert.Equal(t, JPEG, Sniff([]byte("\xff\xd8\xff\xe0\x00\x10JFIF")))
+	assert.Equal(t, TIFF, Sniff([]byte("II*\x00\x08\x00\x00\x00")))
+	assert.Equal(t, HEIF, Sniff([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00")))
+	assert.Equal(t, WebP, Sniff([]byte("RIFF\x24\x00\x00\x00WEBPVP8L")))
+	assert.Equal(t, ZIP, Sniff([]byte("PK\x03\x04\x14\x00")))
+
+	// names and declared types do not matter, content does
+	assert.Equal(t, Format(""), Sniff([]byte("MZ\x90\x00"))) // a Windows executable
+	assert.Equal(t, Format(""), Sniff([]byte("<html><body>")))
+	assert.Equal(t, Format(""), Sniff(nil))
+}
+
+func TestSniffFile(t *testing.T) {
+	sniff := func(data []byte, name, declaredType string) Format {
+		return SniffFile(bytes.NewReader(data), int64(len(data)), name, declaredType)
+	}
+	zipOf := func(name string) []byte {
+		var buf bytes.Buffer
+		w := zip.NewWriter(&buf)
+		_, err := w.Create(name)
+		require.NoError(t, err)
+		require.NoError(t, w.Close())
+		return buf.Bytes()
+	}
+
+	assert.Equal(t, XLSX, sniff(zipOf("xl/workbook.xml"), "statement.zip", ""), "by content, whatever the name")
+	assert.Equal(t, ZIP, sniff(zipOf("jan.pdf"), "statement.xlsx", ""))
+
+	csv := []byte("Date,Narration,Debit,Credit,Balance\r\n01/02/2024,Caf\xe9 Mocha,250.00,,10250.00\r\n")
+	assert.Equal(t, CSV, sniff(csv, "statement.CSV", ""))
+	assert.Equal(t, CSV, sniff(csv, "download", "text/csv; charset=utf-8"))
+	assert.Equal(t, Format(""), sniff(csv, "statement.txt", "text/plain"), "text is CSV only when it says so")
+	assert.Equal(t, Format(""), sniff([]byte("MZ\x90\x00\x03\x00"), "statement.csv", ""))
+	assert.Equal(t, PDF, sniff([]byte("%PDF-1.7\n"), "statement.csv", ""))
+	assert.Equal(t, Format(""), sniff(nil, "statement.csv", ""))
+
+	aa := []byte("\xef\xbb\xbf\n  {\"FI\": [{\"fipID\": \"HDFC-FIP\"}]}")
+	assert.Equal(t, JSON, sniff(aa, "consent-fi.json", ""))
+	assert.Equal(t, JSON, sniff(aa, "upload", "application/json"))
+	assert.Equal(t, Format(""), sniff([]byte("not json"), "fi.json", ""))
+}
+
+func TestJoin(t *testing.T) {
+	assert.Equal(t, "PNG, JPEG, TIFF, HEIC or WebP", Join(Photos))
+	assert.Equal(t, "ZIP", Join([]Format{ZIP}))
+	assert.Equal(t, "application/octet-stream", Format("").MimeType())
+	assert.Equal(t, "image/jpeg", JPEG.MimeType())
+}
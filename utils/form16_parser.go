@@ -0,0 +1,169 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	tanPattern        = regexp.MustCompile(`\b([A-Z]{4}[0-9]{5}[A-Z])\b`)
+	panPatternAll     = regexp.MustCompile(`\b([A-Z]{5}[0-9]{4}[A-Z])\b`)
+	financialYearExpr = regexp.MustCompile(`(?i)financial\s*year[:\s]*(\d{4})\s*-\s*(\d{2,4})`)
+	form16AmountExpr  = regexp.MustCompile(`([0-9][0-9,]*\.?[0-9]*)\s*$`)
+)
+
+// ParseForm16 extracts structured data from Form-16 text (Part A and/or Part B).
+//
+// Part A carries the deductor TAN, the employee PAN and the quarterly TDS summary;
+// Part B carries the salary break-up. Both the embedded-text and OCR layouts put a
+// label and its value either on the same line or on one of the following lines.
+func ParseForm16(text string) dto.Form16Result {
+	lines := splitAndTrimLines(text)
+	upper := strings.ToUpper(text)
+
+	res := dto.Form16Result{
+		RawText: text,
+	}
+
+	// TAN (ABCD12345E) has a different shape from PAN (ABCDE1234F), so the first match is the deductor's.
+	if m := tanPattern.FindStringSubmatch(upper); len(m) > 1 {
+		res.EmployerTAN = m[1]
+	}
+	res.EmployeePAN = extractForm16EmployeePAN(lines, upper)
+
+	res.AssessmentYear = extractAssessmentYearFromLines(lines)
+	if res.AssessmentYear == "" {
+		res.AssessmentYear = extractAssessmentYear(text)
+	}
+	res.FinancialYear = extractForm16FinancialYear(text, res.AssessmentYear)
+
+	res.GrossSalary = extractForm16GrossSalary(lines)
+	res.TDSDeducted = extractForm16TDS(lines)
+
+	return res
+}
+
+// extractForm16EmployeePAN prefers the PAN printed under the "PAN of the Employee"
+// label, then any individual PAN (4th letter P); the deductor's PAN belongs to the company.
+func extractForm16EmployeePAN(lines []string, upper string) string {
+	for i, line := range lines {
+		l := strings.ToLower(line)
+		if !strings.Contains(l, "pan of the employee") {
+			continue
+		}
+		// Part A prints the labels as one row and the values as the next, in the same order.
+		for j := 0; j <= 3 && i+j < len(lines); j++ {
+			for _, m := range panPatternAll.FindAllString(strings.ToUpper(lines[i+j]), -1) {
+				if m[3] == 'P' {
+					return m
+				}
+			}
+		}
+	}
+
+	all := panPatternAll.FindAllString(upper, -1)
+	for _, m := range all {
+		if m[3] == 'P' {
+			return m
+		}
+	}
+	if len(all) > 0 {
+		return all[len(all)-1]
+	}
+	return ""
+}
+
+// extractForm16FinancialYear reads "Financial Year 2023-24" or derives it from the assessment year.
+func extractForm16FinancialYear(text, assessmentYear string) string {
+	if m := financialYearExpr.FindStringSubmatch(text); len(m) > 2 {
+		return m[1] + "-" + m[2][len(m[2])-2:]
+	}
+
+	parts := strings.SplitN(assessmentYear, "-", 2)
+	if len(parts) != 2 {
+		return ""
+	}
+	start, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return ""
+	}
+	return fmt.Sprintf("%d-%02d", start-1, start%100)
+}
+
+// extractForm16GrossSalary reads Part B item 1. The gross salary is the "Total"
+// row of its (a)-(d) break-up; when the layout has no such row, the amount on the
+// label line itself is used.
+func extractForm16GrossSalary(lines []string) float64 {
+	for i, line := range lines {
+		if !strings.Contains(strings.ToLower(line), "gross salary") {
+			continue
+		}
+		for j := 1; j <= 10 && i+j < len(lines); j++ {
+			l := strings.ToLower(lines[i+j])
+			if strings.Contains(l, "less") || strings.Contains(l, "allowance") {
+				break
+			}
+			if strings.HasPrefix(strings.TrimLeft(l, "()d. "), "total") {
+				if v := form16TrailingAmount(lines[i+j]); v > 0 {
+					return v
+				}
+			}
+		}
+		if v := form16TrailingAmount(line); v > 0 {
+			return v
+		}
+	}
+	return 0
+}
+
+// extractForm16TDS reads the tax deducted: the Part A summary total, falling back
+// to Part B's "Tax deducted at source" / "Net tax payable" lines.
+func extractForm16TDS(lines []string) float64 {
+	labels := []string{
+		"amount of tax deducted",
+		"total tax deducted",
+		"tax deducted at source",
+		"tax deducted",
+	}
+	for _, label := range labels {
+		for i, line := range lines {
+			if !strings.Contains(strings.ToLower(line), label) {
+				continue
+			}
+			if v := form16TrailingAmount(line); v > 0 {
+				return v
+			}
+			// Part A summary: the deducted column header, then quarter rows, then "Total (Rs.)".
+			for j := 1; j <= 8 && i+j < len(lines); j++ {
+				if strings.HasPrefix(strings.ToLower(lines[i+j]), "total") {
+					if v := form16TrailingAmount(lines[i+j]); v > 0 {
+						return v
+					}
+				}
+			}
+		}
+	}
+	return 0
+}
+
+// form16TrailingAmount returns the amount at the end of a line, ignoring section
+// references like "17(1)" and single-digit row numbers.
+func form16TrailingAmount(line string) float64 {
+	m := form16AmountExpr.FindStringSubmatch(strings.TrimSpace(line))
+	if len(m) < 2 {
+		return 0
+	}
+	raw := strings.ReplaceAll(m[1], ",", "")
+	if len(raw) < 2 {
+		return 0
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseForm16(t *testing.T) {
+	text := `
+		FORM NO. 16
+		PART A
+		Certificate under section 203 of the Income-tax Act, 1961 for tax deducted at source on salary
+		Name and address of the Employer  Name and address of the Employee
+		ACME SOFTWARE PVT LTD  RAHUL SHARMA
+		PAN of the Deductor  TAN of the Deductor  PAN of the Employee
+		AABCA1234C  MUMA12345B  ABCPS6789K
+		Assessment Year
+		2024-25
+		Quarter  Receipt Numbers  Amount paid/credited  Amount of tax deducted (Rs.)
+		Q1  QVBNMKLP  212500.00  11250.00
+		Q2  QVBNMKLQ  212500.00  11250.00
+		Total (Rs.)  850000.00  45,000.00
+		PART B
+		1. Gross Salary
+		(a) Salary as per provisions contained in section 17(1)  8,50,000.00
+		(b) Value of perquisites under section 17(2)  0.00
+		(d) Total  8,50,000.00
+		2. Less: Allowance to the extent exempt under section 10
+	`
+
+	res := ParseForm16(text)
+
+	assert.Equal(t, "MUMA12345B", res.EmployerTAN)
+	assert.Equal(t, "ABCPS6789K", res.EmployeePAN)
+	assert.Equal(t, "2024-25", res.AssessmentYear)
+	assert.Equal(t, "2023-24", res.FinancialYear)
+	assert.Equal(t, 850000.0, res.GrossSalary)
+	assert.Equal(t, 45000.0, res.TDSDeducted)
+}
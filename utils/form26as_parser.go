@@ -0,0 +1,209 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	// 4 letters, 5 digits, 1 letter; PANs are 5 letters, 4 digits, 1 letter.
+	tanRegex        = regexp.MustCompile(`\b([A-Z]{4}[0-9]{5}[A-Z])\b`)
+	form26PANRegex  = regexp.MustCompile(`\b([A-Z]{5}[0-9]{4}[A-Z])\b`)
+	form26AYRegex   = regexp.MustCompile(`(?i)assessment\s+year\s*[:\-]?\s*(\d{4}\s*-\s*\d{2,4})`)
+	tdsDateRegex    = regexp.MustCompile(`\b(\d{1,2})[-/ ]([A-Za-z]{3}|\d{1,2})[-/ ](\d{4})\b`)
+	tdsSectionRegex = regexp.MustCompile(`\b(19[2-6][A-Z]{0,3}|206[A-Z]{0,3})\b`)
+	tdsAmountRegex  = regexp.MustCompile(`[0-9][0-9,]*\.[0-9]{2}|[0-9][0-9,]{2,}`)
+
+	// "PART-I - Details of Tax Deducted at Source", "PART E - Details of SFT
+	// Transaction" or an AIS block ("TDS/TCS Information", "Payment of Taxes")
+	form26HeadingRegex = regexp.MustCompile(`^PART[\s-]+[A-Z0-9]{1,4}\b|\bINFORMATION\b|^PAYMENT OF TAXES|^DEMAND AND REFUND`)
+	sftCodeRegex       = regexp.MustCompile(`\bSFT\s*-?\s*(\d{3}[A-Z]?(?:\([A-Z]+\))?)`)
+	// "HDFC BANK LIMITED (MUMH03189E)": the reporting entity of an AIS SFT block
+	sftFilerRegex = regexp.MustCompile(`^([A-Z][A-Z0-9&.,' -]+?)\s*\(([A-Z0-9.]{8,})\)$`)
+)
+
+// ParseForm26AS extracts the TDS deducted for a PAN from the text of a Form
+// 26AS (Part A) or the TDS section of an Annual Information Statement.
+//
+// A deductor starts at a line with a TAN ("1 ACME SOFTWARE PRIVATE LIMITED
+// BLRA12345B 1200000.00 150000.00 150000.00", or "ACME ... (BLRA12345B)" in the
+// AIS); the entry lines below it carry a section, a transaction date and the
+// amount paid and tax deducted ("1 192 30-Apr-2024 F 15-May-2024 100000.00
+// 12500.00 12500.00"). An AIS names the section in the block heading ("Salary
+// (Section 192)") instead of on each row.
+//
+// The SFT part (26AS Part E, AIS "SFT Information") lists one transaction a
+// row, under an "SFT-005 Purchase of time deposits" heading and its filer in
+// the AIS, with the description and filer on the row in 26AS.
+func ParseForm26AS(text string) dto.Form26ASData {
+	lines := splitAndTrimLines(text)
+	upper := strings.ToUpper(text)
+
+	res := dto.Form26ASData{Kind: "26as"}
+	if strings.Contains(upper, "ANNUAL INFORMATION STATEMENT") {
+		res.Kind = "ais"
+	}
+	if m := form26AYRegex.FindStringSubmatch(text); len(m) > 1 {
+		res.AssessmentYear = strings.ReplaceAll(m[1], " ", "")
+	}
+	if m := form26PANRegex.FindStringSubmatch(upper); len(m) > 1 {
+		res.PAN = m[1]
+	}
+
+	var current *dto.TDSDeductor
+	section := "" // from an AIS block heading
+	sft := false  // in the SFT part
+	var sftCode, sftDesc, sftFiler string
+	for _, l := range lines {
+		u := strings.ToUpper(l)
+		date, dateAt := tdsEntryDate(u)
+
+		if dateAt < 0 && form26HeadingRegex.MatchString(u) {
+			sft = strings.Contains(u, "SFT")
+			sftCode, sftDesc, sftFiler = "", "", ""
+		}
+		if sft {
+			if dateAt < 0 {
+				if m := sftCodeRegex.FindStringSubmatchIndex(u); m != nil {
+					sftCode, sftFiler = "SFT-"+u[m[2]:m[3]], ""
+					sftDesc = strings.Trim(strings.TrimSpace(l[m[1]:]), "-:() ")
+				} else if m := sftFilerRegex.FindStringSubmatch(u); m != nil && sftCode != "" {
+					sftFiler = strings.TrimSpace(l[:len(m[1])])
+				}
+				continue
+			}
+			if t, ok := sftTransaction(l, u, date, dateAt); ok {
+				if sftCode != "" {
+					t.Code, t.Description, t.FilerName = sftCode, sftDesc, sftFiler
+				}
+				res.SFTTransactions = append(res.SFTTransactions, t)
+			}
+			continue
+		}
+
+		if tan := tanRegex.FindStringSubmatchIndex(u); tan != nil && dateAt < 0 {
+			res.Deductors = append(res.Deductors, dto.TDSDeductor{
+				Name: tdsDeductorName(l[:tan[0]]),
+				TAN:  u[tan[2]:tan[3]],
+			})
+			current = &res.Deductors[len(res.Deductors)-1]
+			if amounts := tdsAmounts(u[tan[1]:]); len(amounts) >= 2 {
+				current.TotalAmountPaid, current.TotalTaxDeducted = amounts[0], amounts[1]
+			}
+			continue
+		}
+
+		if dateAt < 0 {
+			if m := tdsSectionRegex.FindStringSubmatch(u); m != nil && strings.Contains(u, "SECTION") {
+				section = m[1]
+			}
+			continue
+		}
+		if current == nil {
+			continue
+		}
+
+		entry := dto.TDSEntry{Section: section, TransactionDate: date.Format("2006-01-02")}
+		entry.Quarter, entry.FinancialYear = TDSQuarter(date)
+		if m := tdsSectionRegex.FindStringSubmatch(u[:dateAt]); m != nil {
+			entry.Section = m[1]
+		}
+		// amounts follow the last date (the booking date in Form 26AS)
+		last := tdsDateRegex.FindAllStringIndex(u, -1)
+		amounts := tdsAmounts(u[last[len(last)-1][1]:])
+		if len(amounts) < 2 {
+			continue
+		}
+		entry.AmountPaid, entry.TaxDeducted = amounts[0], amounts[1]
+		current.Entries = append(current.Entries, entry)
+	}
+
+	for i := range res.Deductors {
+		d := &res.Deductors[i]
+		if d.TotalAmountPaid > 0 {
+			continue
+		}
+		for _, e := range d.Entries {
+			d.TotalAmountPaid += e.AmountPaid
+			d.TotalTaxDeducted += e.TaxDeducted
+		}
+	}
+	return res
+}
+
+// sftTransaction reads an SFT row: the amount is the last after the dates,
+// and in 26AS the text before the first date describes the transaction.
+func sftTransaction(l, u string, date time.Time, dateAt int) (dto.SFTTransaction, bool) {
+	last := tdsDateRegex.FindAllStringIndex(u, -1)
+	amounts := tdsAmounts(u[last[len(last)-1][1]:])
+	if len(amounts) == 0 {
+		return dto.SFTTransaction{}, false
+	}
+	return dto.SFTTransaction{
+		Description:     tdsDeductorName(l[:dateAt]),
+		TransactionDate: date.Format("2006-01-02"),
+		Amount:          amounts[len(amounts)-1],
+	}, true
+}
+
+// tdsEntryDate returns the first date of a line ("30-Apr-2024", "30/04/2024")
+// and where it starts, or -1 when the line has none.
+func tdsEntryDate(line string) (time.Time, int) {
+	for _, m := range tdsDateRegex.FindAllStringSubmatchIndex(line, -1) {
+		raw := line[m[2]:m[3]] + "-" + line[m[4]:m[5]] + "-" + line[m[6]:m[7]]
+		for _, layout := range []string{"2-Jan-2006", "2-1-2006"} {
+			if t, err := time.Parse(layout, raw); err == nil {
+				return t, m[0]
+			}
+		}
+	}
+	return time.Time{}, -1
+}
+
+// tdsDeductorName strips the serial number and punctuation around the name
+// before a TAN.
+func tdsDeductorName(s string) string {
+	s = strings.TrimSpace(strings.TrimRight(strings.TrimSpace(s), "(-:|"))
+	if f := strings.Fields(s); len(f) > 1 && isSerial(f[0]) {
+		s = strings.Join(f[1:], " ")
+	}
+	return s
+}
+
+func isSerial(s string) bool {
+	s = strings.TrimSuffix(s, ".")
+	if s == "" || len(s) > 3 {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// tdsAmounts returns the amounts in s, in order.
+func tdsAmounts(s string) []float64 {
+	var out []float64
+	for _, m := range tdsAmountRegex.FindAllString(s, -1) {
+		out = append(out, mustParseAmount(m))
+	}
+	return out
+}
+
+// TDSQuarter returns the TDS quarter ("Q1" for April to June ... "Q4" for
+// January to March) and financial year ("2024-25") of a date.
+func TDSQuarter(t time.Time) (quarter, financialYear string) {
+	month := int(t.Month())
+	start := t.Year()
+	if month < 4 {
+		start--
+	}
+	q := (month+8)%12/3 + 1
+	return fmt.Sprintf("Q%d", q), fmt.Sprintf("%d-%02d", start, (start+1)%100)
+}
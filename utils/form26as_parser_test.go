@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestParseForm26AS(t *testing.T) {
+	text := `Form 26AS
+Annual Tax Statement
+Permanent Account Number (PAN) ABCDE1234F   Assessment Year 2025-26
+PART-I - Details of Tax Deducted at Source
+Sr. No. Name of Deductor TAN of Deductor Total Amount Paid/Credited Total Tax Deducted Total TDS Deposited
+1 ACME SOFTWARE PRIVATE LIMITED BLRA12345B 200000.00 25000.00 25000.00
+Sr. No. Section Transaction Date Status of Booking Date of Booking Remarks Amount Paid/Credited Tax Deducted TDS Deposited
+1 192 31-May-2024 F 15-Jun-2024 - 100000.00 12500.00 12500.00
+2 192 31-Jan-2025 F 15-Feb-2025 - 100000.00 12500.00 12500.00
+2 HDFC BANK LIMITED MUMH03189E 4500.00 450.00 450.00
+1 194A 31-Mar-2025 F 10-Apr-2025 - 4500.00 450.00 450.00`
+
+	data := ParseForm26AS(text)
+	assert.Equal(t, "26as", data.Kind)
+	assert.Equal(t, "ABCDE1234F", data.PAN)
+	assert.Equal(t, "2025-26", data.AssessmentYear)
+	require.Len(t, data.Deductors, 2)
+
+	acme := data.Deductors[0]
+	assert.Equal(t, "ACME SOFTWARE PRIVATE LIMITED", acme.Name)
+	assert.Equal(t, "BLRA12345B", acme.TAN)
+	assert.Equal(t, 200000.0, acme.TotalAmountPaid)
+	require.Len(t, acme.Entries, 2)
+	assert.Equal(t, "192", acme.Entries[0].Section)
+	assert.Equal(t, "2024-05-31", acme.Entries[0].TransactionDate)
+	assert.Equal(t, "Q1", acme.Entries[0].Quarter)
+	assert.Equal(t, "Q4", acme.Entries[1].Quarter)
+	assert.Equal(t, "2024-25", acme.Entries[1].FinancialYear)
+	assert.Equal(t, 12500.0, acme.Entries[1].TaxDeducted)
+
+	require.Len(t, data.Deductors[1].Entries, 1)
+	assert.Equal(t, "194A", data.Deductors[1].Entries[0].Section)
+}
+
+func TestParseForm26ASAIS(t *testing.T) {
+	text := `Annual Information Statement (AIS)
+PAN ABCDE1234F
+TDS/TCS Information
+Salary (Section 192)
+ACME SOFTWARE PRIVATE LIMITED (BLRA12345B)
+1 Q1(Apr-Jun) 30/04/2024 90,000 9,000
+2 Q2(Jul-Sep) 31/07/2024 90,000 9,000`
+
+	data := ParseForm26AS(text)
+	assert.Equal(t, "ais", data.Kind)
+	require.Len(t, data.Deductors, 1)
+	d := data.Deductors[0]
+	assert.Equal(t, "ACME SOFTWARE PRIVATE LIMITED", d.Name)
+	require.Len(t, d.Entries, 2)
+	assert.Equal(t, "192", d.Entries[1].Section)
+	assert.Equal(t, "Q2", d.Entries[1].Quarter)
+	assert.Equal(t, 180000.0, d.TotalAmountPaid)
+}
+
+func TestParseForm26ASSFT(t *testing.T) {
+	form26 := `PART-I - Details of Tax Deducted at Source
+1 ACME SOFTWARE PRIVATE LIMITED BLRA12345B 100000.00 12500.00 12500.00
+1 192 31-May-2024 F 15-Jun-2024 - 100000.00 12500.00 12500.00
+PART E - Details of SFT Transaction
+Sr. No. Type of Transaction Name of SFT Filer Transaction Date Single/Joint Party Transaction Number of Parties Amount Mode Remarks
+1 Cash deposits in a current account HDFC BANK LTD 31-Mar-2025 Single 1 1200000.00 Cash
+PART F - Details of Tax Deducted at Source on Sale of Immovable Property`
+
+	data := ParseForm26AS(form26)
+	require.Len(t, data.Deductors, 1)
+	assert.Len(t, data.Deductors[0].Entries, 1)
+	require.Len(t, data.SFTTransactions, 1)
+	assert.Equal(t, dto.SFTTransaction{
+		Description: "Cash deposits in a current account HDFC BANK LTD", TransactionDate: "2025-03-31", Amount: 1200000,
+	}, data.SFTTransactions[0])
+
+	ais := `Annual Information Statement (AIS)
+SFT Information
+SFT-005 Purchase of time deposits
+HDFC Bank Limited (MUMH03189E)
+1 30/06/2024 5,00,000
+2 15/01/2025 2,50,000
+SFT-016(Int) Interest income
+STATE BANK OF INDIA (MUMS12345A)
+1 31/03/2025 42,000
+Payment of Taxes
+1 15/03/2025 Advance Tax 25,000`
+
+	data = ParseForm26AS(ais)
+	assert.Empty(t, data.Deductors)
+	require.Len(t, data.SFTTransactions, 3)
+	assert.Equal(t, dto.SFTTransaction{
+		Code: "SFT-005", Description: "Purchase of time deposits", FilerName: "HDFC Bank Limited", TransactionDate: "2025-01-15", Amount: 250000,
+	}, data.SFTTransactions[1])
+	assert.Equal(t, "SFT-016(INT)", data.SFTTransactions[2].Code)
+	assert.Equal(t, "Interest income", data.SFTTransactions[2].Description)
+	assert.Equal(t, "STATE BANK OF INDIA", data.SFTTransactions[2].FilerName)
+	assert.Equal(t, 42000.0, data.SFTTransactions[2].Amount)
+}
+
+func TestTDSQuarter(t *testing.T) {
+	for month, want := range map[time.Month]string{time.April: "Q1", time.September: "Q2", time.December: "Q3", time.March: "Q4"} {
+		q, _ := TDSQuarter(time.Date(2025, month, 1, 0, 0, 0, 0, time.UTC))
+		assert.Equal(t, want, q, month.String())
+	}
+}
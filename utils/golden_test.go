@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// go test ./utils -run TestGoldenCorpus -update rewrites the golden files
+// from the parsers' current output; review the diff before committing it.
+var updateGolden = flag.Bool("update", false, "rewrite testdata/golden/*/*.json from the parsers' output")
+
+// goldenParsers are the parsers run over the corpus, by directory under
+// testdata/golden.
+var goldenParsers = map[string]func(text string) interface{}{
+	"salary_slip":    func(text string) interface{} { return ParseSalarySlip(text) },
+	"bank_statement": func(text string) interface{} { return ParseBankStatement(text) },
+	"itr":            func(text string) interface{} { return ParseITR(text) },
+	"form_16":        func(text string) interface{} { return ParseForm16(text) },
+	"form_26as":      func(text string) interface{} { return ParseForm26AS(text) },
+	"gst_return":     func(text string) interface{} { return ParseGST(text) },
+	"rent":           func(text string) interface{} { return ParseRent(text) },
+	"epf_passbook":   func(text string) interface{} { return ParseEPFPassbook(text) },
+	"aadhaar":        func(text string) interface{} { return ParseAadhaarFromText(text) },
+	"pan":            func(text string) interface{} { return ParsePANText(text) },
+	"cheque":         func(text string) interface{} { return ParseCheque(text) },
+	"utility_bill":   func(text string) interface{} { return ParseUtilityBill(text) },
+}
+
+// TestGoldenCorpus runs every parser over its OCR text dumps and compares the
+// output, field by field, with the golden JSON next to each dump.
+func TestGoldenCorpus(t *testing.T) {
+	for docType, parse := range goldenParsers {
+		texts, err := filepath.Glob(filepath.Join("testdata", "golden", docType, "*.txt"))
+		require.NoError(t, err)
+		if len(texts) == 0 {
+			t.Errorf("no corpus for %s in testdata/golden/%s", docType, docType)
+		}
+		for _, path := range texts {
+			t.Run(docType+"/"+strings.TrimSuffix(filepath.Base(path), ".txt"), func(t *testing.T) {
+				text, err := os.ReadFile(path)
+				require.NoError(t, err)
+				got, err := json.MarshalIndent(parse(string(text)), "", "  ")
+				require.NoError(t, err)
+				got = append(got, '\n')
+
+				goldenPath := strings.TrimSuffix(path, ".txt") + ".json"
+				if *updateGolden {
+					require.NoError(t, os.WriteFile(goldenPath, got, 0o644))
+					return
+				}
+				want, err := os.ReadFile(goldenPath)
+				require.NoError(t, err, "no golden file; -update creates it")
+				if bytes.Equal(want, got) {
+					return
+				}
+				var wantV, gotV interface{}
+				require.NoError(t, json.Unmarshal(want, &wantV), goldenPath)
+				require.NoError(t, json.Unmarshal(got, &gotV))
+				diffs := jsonDiff("", wantV, gotV)
+				if len(diffs) == 0 {
+					diffs = []string{"formatting only"}
+				}
+				t.Errorf("output differs from %s (-update rewrites it):\n%s", goldenPath, strings.Join(diffs, "\n"))
+			})
+		}
+	}
+
+	// dumps of a type without a parser would never be checked
+	dirs, err := os.ReadDir(filepath.Join("testdata", "golden"))
+	require.NoError(t, err)
+	for _, d := range dirs {
+		if _, ok := goldenParsers[d.Name()]; d.IsDir() && !ok {
+			t.Errorf("testdata/golden/%s has no parser in goldenParsers", d.Name())
+		}
+	}
+}
+
+// missing stands in for a field or element one side does not have.
+type missing struct{}
+
+// jsonDiff lists where two decoded JSON values differ, as "path: want X, got
+// Y" lines.
+func jsonDiff(path string, want, got interface{}) []string {
+	switch w := want.(type) {
+	case map[string]interface{}:
+		g, ok := got.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(w)+len(g))
+		for k := range w {
+			keys = append(keys, k)
+		}
+		for k := range g {
+			if _, ok := w[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		var diffs []string
+		for _, k := range keys {
+			wv, ok := w[k]
+			if !ok {
+				wv = missing{}
+			}
+			gv, ok := g[k]
+			if !ok {
+				gv = missing{}
+			}
+			diffs = append(diffs, jsonDiff(strings.TrimPrefix(path+"."+k, "."), wv, gv)...)
+		}
+		return diffs
+	case []interface{}:
+		g, ok := got.([]interface{})
+		if !ok {
+			break
+		}
+		var diffs []string
+		for i := 0; i < max(len(w), len(g)); i++ {
+			var wv, gv interface{} = missing{}, missing{}
+			if i < len(w) {
+				wv = w[i]
+			}
+			if i < len(g) {
+				gv = g[i]
+			}
+			diffs = append(diffs, jsonDiff(fmt.Sprintf("%s[%d]", path, i), wv, gv)...)
+		}
+		return diffs
+	}
+	if w, g := jsonString(want), jsonString(got); w != g {
+		if path == "" {
+			path = "."
+		}
+		return []string{fmt.Sprintf("%s: want %s, got %s", path, w, g)}
+	}
+	return nil
+}
+
+func jsonString(v interface{}) string {
+	if _, ok := v.(missing); ok {
+		return "(missing)"
+	}
+	b, _ := json.Marshal(v)
+	return string(b)
+}
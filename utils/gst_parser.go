@@ -0,0 +1,116 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	// 2-digit state code, PAN, entity number, 'Z', check character.
+	gstinRegex       = regexp.MustCompile(`\b([0-9]{2}[A-Z]{5}[0-9]{4}[A-Z][1-9A-Z]Z[0-9A-Z])\b`)
+	gstYearRegex     = regexp.MustCompile(`(?i)\byear\b[\s:]*(\d{4})\s*-\s*\d{2,4}`)
+	gstPeriodRegex   = regexp.MustCompile(`(?i)\b(?:period|month)\b[\s:]*([A-Za-z]+)`)
+	gstRowRegex      = regexp.MustCompile(`^\(([a-e])\)`)
+	gstRowAmountExpr = regexp.MustCompile(`[0-9][0-9,]*\.[0-9]{2}|[0-9][0-9,]{2,}`)
+)
+
+// ParseGST extracts GSTIN, names and declared turnover from a GST registration
+// certificate or a GSTR-3B summary.
+func ParseGST(text string) dto.GSTData {
+	lines := splitAndTrimLines(text)
+	upper := strings.ToUpper(text)
+
+	res := dto.GSTData{Kind: "registration"}
+	if strings.Contains(upper, "GSTR-3B") || strings.Contains(upper, "GSTR 3B") || strings.Contains(upper, "OUTWARD") {
+		res.Kind = "gstr3b"
+	}
+
+	if m := gstinRegex.FindStringSubmatch(upper); len(m) > 1 {
+		res.GSTIN = m[1]
+	}
+	res.LegalName = gstLabelValue(lines, "legal name")
+	res.TradeName = gstLabelValue(lines, "trade name")
+
+	if res.Kind == "gstr3b" {
+		res.ReturnPeriod = gstReturnPeriod(text)
+		res.Turnover = gstOutwardTurnover(lines)
+	}
+	return res
+}
+
+// gstLabelValue reads "Legal Name of the registered person : ACME TRADERS" or
+// the same label with the value on the following line.
+func gstLabelValue(lines []string, label string) string {
+	for i, l := range lines {
+		lower := strings.ToLower(l)
+		idx := strings.Index(lower, label)
+		if idx < 0 {
+			continue
+		}
+		if sep := strings.IndexAny(l[idx:], ":"); sep >= 0 {
+			if v := strings.TrimSpace(l[idx+sep+1:]); v != "" {
+				return v
+			}
+		}
+		if i+1 < len(lines) && !strings.Contains(lines[i+1], ":") {
+			return lines[i+1]
+		}
+	}
+	return ""
+}
+
+// gstReturnPeriod turns the GSTR-3B "Year 2024-25" / "Period April" header into
+// "2024-04"; January to March fall in the second calendar year of the FY.
+func gstReturnPeriod(text string) string {
+	y := gstYearRegex.FindStringSubmatch(text)
+	p := gstPeriodRegex.FindStringSubmatch(text)
+	if len(y) < 2 || len(p) < 2 {
+		return ""
+	}
+	month, err := time.Parse("January", p[1]) // month names match case-insensitively
+	if err != nil {
+		return ""
+	}
+	year, _ := strconv.Atoi(y[1])
+	if month.Month() <= time.March {
+		year++
+	}
+	return fmt.Sprintf("%d-%02d", year, int(month.Month()))
+}
+
+// gstOutwardTurnover sums the "Total taxable value" column of table 3.1 rows
+// (a) outward taxable, (b) zero rated, (c) nil rated/exempt and (e) non-GST.
+// Row (d) is inward supplies under reverse charge and is not income.
+func gstOutwardTurnover(lines []string) float64 {
+	inTable := false
+	total := 0.0
+	for _, l := range lines {
+		lower := strings.ToLower(l)
+		if strings.HasPrefix(lower, "3.1") {
+			inTable = true
+			continue
+		}
+		if !inTable {
+			continue
+		}
+		if strings.HasPrefix(lower, "3.2") || strings.HasPrefix(lower, "4.") {
+			break
+		}
+
+		m := gstRowRegex.FindStringSubmatch(lower)
+		if len(m) < 2 || m[1] == "d" {
+			continue
+		}
+		if amt := gstRowAmountExpr.FindString(l[len(m[0]):]); amt != "" {
+			if v, err := strconv.ParseFloat(strings.ReplaceAll(amt, ",", ""), 64); err == nil {
+				total += v
+			}
+		}
+	}
+	return total
+}
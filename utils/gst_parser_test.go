@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseGSTR3B(t *testing.T) {
+	text := `
+		Form GSTR-3B
+		Year 2024-25
+		Period January
+		1. GSTIN 27AAPFU0939F1ZV
+		2(a). Legal name of the registered person : SHARMA ENTERPRISES
+		2(b). Trade name, if any : SHARMA TRADERS
+		3.1 Details of Outward Supplies and inward supplies liable to reverse charge
+		Nature of Supplies  Total Taxable value  Integrated Tax  Central Tax  State/UT Tax  Cess
+		(a) Outward taxable supplies (other than zero rated, nil rated and exempted)  4,50,000.00  0.00  40,500.00  40,500.00  0.00
+		(b) Outward taxable supplies (zero rated)  50,000.00  0.00  0.00  0.00  0.00
+		(c) Other outward supplies (Nil rated, exempted)  0.00  0.00  0.00  0.00  0.00
+		(d) Inward supplies (liable to reverse charge)  12,000.00  0.00  1,080.00  1,080.00  0.00
+		(e) Non-GST outward supplies  0.00  0.00  0.00  0.00  0.00
+		3.2 Of the supplies shown in 3.1 (a) above
+	`
+
+	res := ParseGST(text)
+
+	assert.Equal(t, "gstr3b", res.Kind)
+	assert.Equal(t, "27AAPFU0939F1ZV", res.GSTIN)
+	assert.Equal(t, "SHARMA ENTERPRISES", res.LegalName)
+	assert.Equal(t, "SHARMA TRADERS", res.TradeName)
+	assert.Equal(t, "2025-01", res.ReturnPeriod)
+	assert.Equal(t, 500000.0, res.Turnover)
+}
+
+func TestParseGSTRegistration(t *testing.T) {
+	text := `
+		Form GST REG-06
+		Registration Certificate
+		Registration Number : 07AAACR5055K1Z6
+		1. Legal Name
+		RAVI KUMAR
+		2. Trade Name, if any
+		KUMAR FOODS
+		3. Constitution of Business : Proprietorship
+	`
+
+	res := ParseGST(text)
+
+	assert.Equal(t, "registration", res.Kind)
+	assert.Equal(t, "07AAACR5055K1Z6", res.GSTIN)
+	assert.Equal(t, "RAVI KUMAR", res.LegalName)
+	assert.Equal(t, "KUMAR FOODS", res.TradeName)
+	assert.Zero(t, res.Turnover)
+}
@@ -0,0 +1,124 @@
+package handwriting
+
+import (
+	"image"
+	"image/draw"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Field kinds decide how the recognized text is normalized.
+const (
+	KindAmount = "amount"
+	KindDate   = "date"
+	KindText   = "text"
+)
+
+// Region is a box in page-relative coordinates (0–1) that usually holds handwriting.
+type Region struct {
+	Field          string
+	Kind           string
+	X0, Y0, X1, Y1 float64
+}
+
+// regions lists the handwritten areas per document type. Document types not
+// listed here never go through handwriting recognition.
+var regions = map[string][]Region{
+	// CTS-2010 cheque leaf, landscape
+	"cheque": {
+		{Field: "date", Kind: KindDate, X0: 0.72, Y0: 0.04, X1: 0.98, Y1: 0.17},
+		{Field: "payee", Kind: KindText, X0: 0.04, Y0: 0.17, X1: 0.80, Y1: 0.29},
+		{Field: "amount_words", Kind: KindText, X0: 0.04, Y0: 0.28, X1: 0.74, Y1: 0.44},
+		{Field: "amount_figures", Kind: KindAmount, X0: 0.74, Y0: 0.35, X1: 0.98, Y1: 0.50},
+	},
+}
+
+// Regions returns the handwritten regions for docType, or nil if it has none.
+func Regions(docType string) []Region {
+	return regions[strings.ToLower(docType)]
+}
+
+// Crop cuts region out of img, clamped to the image bounds.
+func Crop(img image.Image, r Region) image.Image {
+	b := img.Bounds()
+	rect := image.Rect(
+		b.Min.X+int(r.X0*float64(b.Dx())),
+		b.Min.Y+int(r.Y0*float64(b.Dy())),
+		b.Min.X+int(r.X1*float64(b.Dx())),
+		b.Min.Y+int(r.Y1*float64(b.Dy())),
+	).Intersect(b)
+
+	out := image.NewRGBA(image.Rect(0, 0, rect.Dx(), rect.Dy()))
+	draw.Draw(out, out.Bounds(), img, rect.Min, draw.Src)
+	return out
+}
+
+// Handwritten digits are commonly read as look-alike letters.
+var digitLookalikes = strings.NewReplacer(
+	"O", "0", "o", "0", "D", "0", "Q", "0",
+	"I", "1", "l", "1", "|", "1", "i", "1",
+	"Z", "2", "z", "2",
+	"S", "5", "s", "5",
+	"B", "8", "G", "6", "b", "6", "g", "9", "q", "9",
+)
+
+var (
+	amountPrefixRe = regexp.MustCompile(`(?i)^\s*(rs\.?|inr|₹)\s*`)
+	amountSuffixRe = regexp.MustCompile(`(?i)\s*(/-|=/-|-/-|only)\s*$`)
+	amountRe       = regexp.MustCompile(`^\d+(\.\d{1,2})?$`)
+)
+
+// NormalizeAmount turns a handwritten amount ("Rs. 45,2OO/-") into a number.
+func NormalizeAmount(raw string) (float64, bool) {
+	s := amountPrefixRe.ReplaceAllString(raw, "")
+	s = amountSuffixRe.ReplaceAllString(s, "")
+	s = digitLookalikes.Replace(s)
+	s = strings.NewReplacer(",", "", " ", "", "*", "", "#", "").Replace(s)
+	if !amountRe.MatchString(s) {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+var dateSeparatorRe = regexp.MustCompile(`[\s/.\-]+`)
+
+// NormalizeDate reads a handwritten date. Cheques use eight boxes (DDMMYYYY);
+// forms mostly use DD/MM/YYYY or DD-MM-YY.
+func NormalizeDate(raw string) (*time.Time, bool) {
+	s := strings.TrimSpace(digitLookalikes.Replace(raw))
+
+	parts := dateSeparatorRe.Split(s, -1)
+	if len(parts) == 3 {
+		s = pad2(parts[0]) + pad2(parts[1]) + parts[2]
+	} else {
+		s = strings.Join(parts, "")
+	}
+
+	var t time.Time
+	var err error
+	switch len(s) {
+	case 8:
+		t, err = time.Parse("02012006", s)
+	case 6:
+		t, err = time.Parse("020106", s)
+	default:
+		return nil, false
+	}
+	if err != nil {
+		return nil, false
+	}
+	return &t, true
+}
+
+func pad2(s string) string {
+	if len(s) == 1 {
+		return "0" + s
+	}
+	return s
+}
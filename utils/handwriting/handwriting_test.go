@@ -0,0 +1,44 @@
+package handwriting
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAmount(t *testing.T) {
+	cases := map[string]float64{
+		"Rs. 45,2OO/-":  45200,
+		"₹ 1,25,000.00": 125000,
+		"12S0O only":    12500,
+	}
+	for raw, want := range cases {
+		got, ok := NormalizeAmount(raw)
+		assert.True(t, ok, raw)
+		assert.Equal(t, want, got, raw)
+	}
+
+	_, ok := NormalizeAmount("Forty five thousand")
+	assert.False(t, ok)
+}
+
+func TestNormalizeDate(t *testing.T) {
+	for _, raw := range []string{"2 5 1 0 2 0 2 5", "25/1O/2025", "25-10-25"} {
+		d, ok := NormalizeDate(raw)
+		if assert.True(t, ok, raw) {
+			assert.Equal(t, "2025-10-25", d.Format("2006-01-02"), raw)
+		}
+	}
+
+	_, ok := NormalizeDate("32/13/2025")
+	assert.False(t, ok)
+}
+
+func TestRegionsGatedByDocType(t *testing.T) {
+	assert.NotEmpty(t, Regions("cheque"))
+	assert.Empty(t, Regions("salary_slip"))
+
+	crop := Crop(image.NewRGBA(image.Rect(0, 0, 1000, 460)), Regions("cheque")[0])
+	assert.Equal(t, 260, crop.Bounds().Dx())
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// ifscBanks maps the bank code (first four letters of an IFSC) to the bank.
+var ifscBanks = map[string]string{
+	"ABHY": "Abhyudaya Co-operative Bank",
+	"AIRP": "Airtel Payments Bank",
+	"ALLA": "Allahabad Bank",
+	"ANDB": "Andhra Bank",
+	"AUBL": "AU Small Finance Bank",
+	"BARB": "Bank of Baroda",
+	"BDBL": "Bandhan Bank",
+	"BKDN": "Dena Bank",
+	"BKID": "Bank of India",
+	"CBIN": "Central Bank of India",
+	"CITI": "Citibank",
+	"CIUB": "City Union Bank",
+	"CNRB": "Canara Bank",
+	"CORP": "Corporation Bank",
+	"COSB": "Cosmos Co-operative Bank",
+	"CSBK": "CSB Bank",
+	"DBSS": "DBS Bank India",
+	"DCBL": "DCB Bank",
+	"DEUT": "Deutsche Bank",
+	"DLXB": "Dhanlaxmi Bank",
+	"ESFB": "Equitas Small Finance Bank",
+	"FDRL": "Federal Bank",
+	"FINO": "Fino Payments Bank",
+	"HDFC": "HDFC Bank",
+	"HSBC": "HSBC",
+	"IBKL": "IDBI Bank",
+	"ICIC": "ICICI Bank",
+	"IDFB": "IDFC FIRST Bank",
+	"IDIB": "Indian Bank",
+	"INDB": "IndusInd Bank",
+	"IOBA": "Indian Overseas Bank",
+	"IPOS": "India Post Payments Bank",
+	"JAKA": "Jammu & Kashmir Bank",
+	"JSFB": "Jana Small Finance Bank",
+	"KARB": "Karnataka Bank",
+	"KKBK": "Kotak Mahindra Bank",
+	"KVBL": "Karur Vysya Bank",
+	"MAHB": "Bank of Maharashtra",
+	"NTBL": "Nainital Bank",
+	"ORBC": "Oriental Bank of Commerce",
+	"PSIB": "Punjab & Sind Bank",
+	"PUNB": "Punjab National Bank",
+	"PYTM": "Paytm Payments Bank",
+	"RATN": "RBL Bank",
+	"SBIN": "State Bank of India",
+	"SCBL": "Standard Chartered Bank",
+	"SIBL": "South Indian Bank",
+	"SRCB": "Saraswat Co-operative Bank",
+	"SVCB": "SVC Co-operative Bank",
+	"SYNB": "Syndicate Bank",
+	"TMBL": "Tamilnad Mercantile Bank",
+	"UBIN": "Union Bank of India",
+	"UCBA": "UCO Bank",
+	"UJVN": "Ujjivan Small Finance Bank",
+	"UTBI": "United Bank of India",
+	"UTIB": "Axis Bank",
+	"VIJB": "Vijaya Bank",
+	"YESB": "Yes Bank",
+}
+
+// bankBrands are how banks print their name in statement and payslip
+// headers, mapped to the bank code. Longer names come first so "Bank of
+// India" does not match "State Bank of India".
+var bankBrands = []struct{ brand, code string }{
+	{"STATE BANK OF INDIA", "SBIN"},
+	{"CENTRAL BANK OF INDIA", "CBIN"},
+	{"UNION BANK OF INDIA", "UBIN"},
+	{"KOTAK MAHINDRA BANK", "KKBK"},
+	{"INDIAN OVERSEAS BANK", "IOBA"},
+	{"PUNJAB NATIONAL BANK", "PUNB"},
+	{"PUNJAB & SIND BANK", "PSIB"},
+	{"BANK OF MAHARASHTRA", "MAHB"},
+	{"STANDARD CHARTERED", "SCBL"},
+	{"IDFC FIRST BANK", "IDFB"},
+	{"TAMILNAD MERCANTILE", "TMBL"},
+	{"KARUR VYSYA BANK", "KVBL"},
+	{"SOUTH INDIAN BANK", "SIBL"},
+	{"CITY UNION BANK", "CIUB"},
+	{"BANK OF BARODA", "BARB"},
+	{"BANK OF INDIA", "BKID"},
+	{"INDUSIND BANK", "INDB"},
+	{"KARNATAKA BANK", "KARB"},
+	{"FEDERAL BANK", "FDRL"},
+	{"BANDHAN BANK", "BDBL"},
+	{"CANARA BANK", "CNRB"},
+	{"INDIAN BANK", "IDIB"},
+	{"ICICI BANK", "ICIC"},
+	{"HDFC BANK", "HDFC"},
+	{"AXIS BANK", "UTIB"},
+	{"IDBI BANK", "IBKL"},
+	{"YES BANK", "YESB"},
+	{"UCO BANK", "UCBA"},
+	{"RBL BANK", "RATN"},
+	{"DBS BANK", "DBSS"},
+	{"AU SMALL FINANCE", "AUBL"},
+	{"CITIBANK", "CITI"},
+	{"HSBC", "HSBC"},
+}
+
+var (
+	ifscRe = regexp.MustCompile(`^[A-Z]{4}0[A-Z0-9]{6}$`)
+	// OCR reads the fixed fifth character 0 as O or D often enough to accept it
+	ifscCandidateRe = regexp.MustCompile(`\b([A-Z]{4})[0OD]([A-Z0-9]{6})\b`)
+	ifscLabelRe     = regexp.MustCompile(`\b(?:IFSC|IFS\s*CODE|RTGS\s*/?\s*NEFT\s*(?:IFSC|CODE))\b\s*(?:CODE)?\s*[:\-.]?\s*([A-Z0-9]{11})\b`)
+	bankLabelRe     = regexp.MustCompile(`\bBANK\s*NAME\s*[:\-]\s*([A-Z][A-Z &.]{2,40})`)
+)
+
+// ValidateIFSC reports whether code is a well-formed IFSC: four letters (the
+// bank), a zero, and six letters or digits (the branch).
+func ValidateIFSC(code string) bool {
+	return ifscRe.MatchString(code)
+}
+
+// BankForIFSC names the bank an IFSC belongs to, or "" for an unknown bank code.
+func BankForIFSC(code string) string {
+	if len(code) < 4 {
+		return ""
+	}
+	return ifscBanks[strings.ToUpper(code[:4])]
+}
+
+// ExtractIFSC finds the IFSC printed on a statement or payslip. A value after
+// an "IFSC" label wins; otherwise the first code-shaped word of a known bank.
+func ExtractIFSC(text string) string {
+	upper := strings.ToUpper(text)
+	if m := ifscLabelRe.FindStringSubmatch(upper); m != nil {
+		if code, ok := fixIFSC(m[1]); ok {
+			return code
+		}
+	}
+	for _, m := range ifscCandidateRe.FindAllStringSubmatch(upper, -1) {
+		if _, known := ifscBanks[m[1]]; known {
+			return m[1] + "0" + m[2]
+		}
+	}
+	return ""
+}
+
+// fixIFSC repairs the OCR misreads of the fifth character.
+func fixIFSC(s string) (string, bool) {
+	if len(s) != 11 {
+		return "", false
+	}
+	if c := s[4]; c == 'O' || c == 'D' {
+		s = s[:4] + "0" + s[5:]
+	}
+	return s, ValidateIFSC(s)
+}
+
+// ExtractBankName finds the bank named by a "Bank Name:" label (payslips) or
+// by the branding in the header of a statement, falling back to the bank of
+// the IFSC.
+func ExtractBankName(text, ifsc string) string {
+	upper := strings.ToUpper(text)
+	if m := bankLabelRe.FindStringSubmatch(upper); m != nil {
+		if name := brandedBank(m[1]); name != "" {
+			return name
+		}
+	}
+
+	header := upper
+	if lines := strings.SplitN(header, "\n", 16); len(lines) == 16 {
+		header = strings.Join(lines[:15], "\n")
+	}
+	if name := brandedBank(header); name != "" {
+		return name
+	}
+	return BankForIFSC(ifsc)
+}
+
+func brandedBank(s string) string {
+	for _, b := range bankBrands {
+		if strings.Contains(s, b.brand) {
+			return ifscBanks[b.code]
+		}
+	}
+	return ""
+}
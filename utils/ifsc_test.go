@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractIFSC(t *testing.T) {
+	assert.Equal(t, "HDFC0001234", ExtractIFSC("Branch: MG Road\nIFSC Code : HDFCO001234  MICR: 560240002"))
+	assert.Equal(t, "SBIN0000813", ExtractIFSC("NEFT transfer via SBIN0000813 on 01/03"))
+	assert.Equal(t, "", ExtractIFSC("Ref ABCD0123456 unknown bank code"))
+}
+
+func TestExtractBankName(t *testing.T) {
+	assert.Equal(t, "State Bank of India", ExtractBankName("STATE BANK OF INDIA\nAccount Statement", ""))
+	assert.Equal(t, "Axis Bank", ExtractBankName("ACME PVT LTD\nPayslip\nBank Name: Axis Bank Ltd", ""))
+	assert.Equal(t, "Kotak Mahindra Bank", ExtractBankName("Statement of account", "KKBK0000958"))
+	assert.True(t, ValidateIFSC("UTIB0000004"))
+	assert.False(t, ValidateIFSC("UTIB1000004"))
+}
@@ -0,0 +1,97 @@
+// Package imageconv turns phone camera formats (HEIC/HEIF, WebP) into PNG so
+// the OCR engines, which only read PNG, JPEG and PDF, can take them, and
+// splits scanner TIFFs into pages. Importing it also registers the WebP and
+// TIFF decoders with image.Decode.
+package imageconv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	_ "golang.org/x/image/webp"
+)
+
+// Formats that are converted to PNG.
+const (
+	FormatHEIF = "heif" // HEIC (HEVC-coded) and other HEIF images
+	FormatWebP = "webp"
+)
+
+// heifBrands are the ISO BMFF major brands of HEIF still images.
+var heifBrands = map[string]bool{
+	"heic": true, "heix": true, "heim": true, "heis": true,
+	"hevc": true, "hevx": true, "hevm": true, "hevs": true,
+	"mif1": true, "msf1": true,
+}
+
+// Detect returns the format of data when it is one that needs converting,
+// else "".
+func Detect(data []byte) string {
+	switch {
+	case len(data) >= 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP":
+		return FormatWebP
+	case len(data) >= 12 && string(data[4:8]) == "ftyp" && heifBrands[string(data[8:12])]:
+		return FormatHEIF
+	}
+	return ""
+}
+
+// ToPNG converts a HEIC/HEIF or WebP image to PNG. Data in any other format
+// is returned unchanged with converted false.
+func ToPNG(ctx context.Context, data []byte) (out []byte, converted bool, err error) {
+	switch Detect(data) {
+	case FormatWebP:
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to decode WebP image: %w", err)
+		}
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, false, err
+		}
+		return buf.Bytes(), true, nil
+	case FormatHEIF:
+		out, err := heifToPNG(ctx, data)
+		if err != nil {
+			return nil, false, err
+		}
+		return out, true, nil
+	}
+	return data, false, nil
+}
+
+// heifToPNG runs libheif's heif-convert, which is killed when ctx is done.
+// HEVC decoding is left to libheif rather than done in Go.
+func heifToPNG(ctx context.Context, data []byte) ([]byte, error) {
+	dir, err := os.MkdirTemp("", "heif-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	in, out := filepath.Join(dir, "in.heic"), filepath.Join(dir, "out.png")
+	if err := os.WriteFile(in, data, 0o600); err != nil {
+		return nil, fmt.Errorf("failed to write HEIF image: %w", err)
+	}
+
+	// heif-convert in.heic out.png (only the primary image is written)
+	cmd := exec.CommandContext(ctx, "heif-convert", in, out)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("heif-convert failed: %v\nOutput: %s", err, string(output))
+	}
+
+	converted, err := os.ReadFile(out)
+	if err != nil {
+		return nil, fmt.Errorf("HEIF image was not converted: %w", err)
+	}
+	return converted, nil
+}
@@ -0,0 +1,36 @@
+package imageconv
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetect(t *testing.T) {
+	assert.Equal(t, FormatWebP, Detect([]byte("RIFF\x24\x00\x00\x00WEBPVP8L")))
+	assert.Equal(t, FormatHEIF, Detect([]byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00")))
+	assert.Equal(t, FormatHEIF, Detect([]byte("\x00\x00\x00\x1cftypmif1\x00\x00\x00\x00")))
+	assert.Equal(t, "", Detect([]byte("\x00\x00\x00\x20ftypisom\x00\x00\x02\x00"))) // MP4 video
+	assert.Equal(t, "", Detect([]byte("%PDF-1.7")))
+	assert.Equal(t, "", Detect(nil))
+}
+
+func TestToPNGLeavesOtherFormats(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 4, 4))))
+
+	out, converted, err := ToPNG(context.Background(), buf.Bytes())
+	require.NoError(t, err)
+	assert.False(t, converted)
+	assert.Equal(t, buf.Bytes(), out)
+}
+
+func TestToPNGBadWebP(t *testing.T) {
+	_, _, err := ToPNG(context.Background(), []byte("RIFF\x04\x00\x00\x00WEBP"))
+	assert.Error(t, err)
+}
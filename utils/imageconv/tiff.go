@@ -0,0 +1,102 @@
+package imageconv
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"image"
+	"io"
+	"iter"
+
+	"golang.org/x/image/tiff"
+)
+
+// IsTIFF reports whether data is a (classic, not BigTIFF) TIFF file.
+func IsTIFF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("II*\x00")) || bytes.HasPrefix(data, []byte("MM\x00*"))
+}
+
+// TIFFPages yields the pages of a TIFF file, decoding one at a time like
+// rendered PDF pages. A page that fails to decode yields its error and the
+// rest still follow.
+func TIFFPages(data []byte) iter.Seq2[image.Image, error] {
+	return func(yield func(image.Image, error) bool) {
+		offsets, err := tiffPageOffsets(data)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for i, off := range offsets {
+			img, err := tiff.Decode(io.NewSectionReader(pageReader{data: data, ifd: off, order: tiffByteOrder(data)}, 0, int64(len(data))))
+			if err != nil {
+				err = fmt.Errorf("TIFF page %d: %w", i+1, err)
+			}
+			if !yield(img, err) {
+				return
+			}
+		}
+	}
+}
+
+func tiffByteOrder(data []byte) binary.ByteOrder {
+	if data[0] == 'M' {
+		return binary.BigEndian
+	}
+	return binary.LittleEndian
+}
+
+// tiffPageOffsets walks the chain of image file directories, one per page.
+func tiffPageOffsets(data []byte) ([]uint32, error) {
+	if !IsTIFF(data) || len(data) < 8 {
+		return nil, fmt.Errorf("not a TIFF file")
+	}
+	order := tiffByteOrder(data)
+
+	var offsets []uint32
+	seen := map[uint32]bool{}
+	for off := order.Uint32(data[4:8]); off != 0; {
+		if seen[off] || int64(off)+2 > int64(len(data)) {
+			break // a looping or truncated chain: keep the pages found so far
+		}
+		seen[off] = true
+		offsets = append(offsets, off)
+
+		next := int64(off) + 2 + 12*int64(order.Uint16(data[off:]))
+		if next+4 > int64(len(data)) {
+			break
+		}
+		off = order.Uint32(data[next:])
+	}
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("TIFF file has no pages")
+	}
+	return offsets, nil
+}
+
+// pageReader reads a TIFF file as if its first directory were ifd, which is
+// all it takes for a single-page decoder to read any page: directory offsets
+// are absolute.
+type pageReader struct {
+	data  []byte
+	ifd   uint32
+	order binary.ByteOrder
+}
+
+func (r pageReader) ReadAt(p []byte, off int64) (int, error) {
+	if off >= int64(len(r.data)) {
+		return 0, io.EOF
+	}
+	n := copy(p, r.data[off:])
+	// patch the header's first-directory offset (bytes 4 to 7)
+	var header [4]byte
+	r.order.PutUint32(header[:], r.ifd)
+	for i := range header {
+		if pos := int64(4+i) - off; pos >= 0 && pos < int64(n) {
+			p[pos] = header[i]
+		}
+	}
+	if n < len(p) {
+		return n, io.EOF
+	}
+	return n, nil
+}
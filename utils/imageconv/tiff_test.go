@@ -0,0 +1,78 @@
+package imageconv
+
+import (
+	"encoding/binary"
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// grayTIFF builds an uncompressed 8-bit grayscale TIFF with one page per
+// shade, each page w×h pixels of that shade.
+func grayTIFF(w, h int, shades ...byte) []byte {
+	le := binary.LittleEndian
+	data := []byte("II*\x00\x00\x00\x00\x00")
+	prevNext := 4 // where the offset of the next directory goes
+	for _, shade := range shades {
+		strip := len(data)
+		for i := 0; i < w*h; i++ {
+			data = append(data, shade)
+		}
+		if len(data)%2 == 1 {
+			data = append(data, 0)
+		}
+
+		ifd := len(data)
+		le.PutUint32(data[prevNext:], uint32(ifd))
+		entries := [][2]uint32{
+			{256, uint32(w)}, {257, uint32(h)}, {258, 8}, {259, 1}, {262, 1},
+			{273, uint32(strip)}, {277, 1}, {278, uint32(h)}, {279, uint32(w * h)},
+		}
+		data = le.AppendUint16(data, uint16(len(entries)))
+		for _, e := range entries {
+			data = le.AppendUint16(data, uint16(e[0]))
+			data = le.AppendUint16(data, 4) // LONG
+			data = le.AppendUint32(data, 1)
+			data = le.AppendUint32(data, e[1])
+		}
+		prevNext = len(data)
+		data = le.AppendUint32(data, 0)
+	}
+	return data
+}
+
+func TestTIFFPages(t *testing.T) {
+	data := grayTIFF(4, 3, 10, 200, 90)
+	require.True(t, IsTIFF(data))
+
+	var shades []uint8
+	for img, err := range TIFFPages(data) {
+		require.NoError(t, err)
+		assert.Equal(t, image.Rect(0, 0, 4, 3), img.Bounds())
+		shades = append(shades, img.(*image.Gray).GrayAt(1, 1).Y)
+	}
+	assert.Equal(t, []uint8{10, 200, 90}, shades)
+}
+
+func TestTIFFPagesLoopingChain(t *testing.T) {
+	data := grayTIFF(2, 2, 50)
+	// point the last directory back at itself
+	first := binary.LittleEndian.Uint32(data[4:])
+	binary.LittleEndian.PutUint32(data[len(data)-4:], first)
+
+	pages := 0
+	for _, err := range TIFFPages(data) {
+		require.NoError(t, err)
+		pages++
+	}
+	assert.Equal(t, 1, pages)
+}
+
+func TestTIFFPagesNotTIFF(t *testing.T) {
+	for _, err := range TIFFPages([]byte("%PDF-1.7")) {
+		assert.Error(t, err)
+	}
+	assert.False(t, IsTIFF([]byte("%PDF-1.7")))
+}
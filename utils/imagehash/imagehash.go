@@ -0,0 +1,69 @@
+// Package imagehash computes perceptual hashes of page images, so that the
+// same document uploaded twice is recognized even after it was re-encoded,
+// rescaled or lightly recompressed.
+package imagehash
+
+import (
+	"image"
+	"image/color"
+	"math/bits"
+)
+
+// DuplicateDistance is the Distance up to which two hashes are taken to be of
+// the same image.
+const DuplicateDistance = 6
+
+// samplesPerCell bounds the pixels averaged per grid cell (per axis).
+const samplesPerCell = 16
+
+// DHash is the 64-bit difference hash of img: the image is averaged down to
+// a 9x8 luminance grid and each bit tells whether a cell is brighter than its
+// right neighbour.
+func DHash(img image.Image) uint64 {
+	const w, h = 9, 8
+	b := img.Bounds()
+	if b.Dx() < w || b.Dy() < h {
+		return 0
+	}
+
+	lum := func(x, y int) uint32 { return uint32(color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y) }
+	switch src := img.(type) {
+	case *image.YCbCr:
+		lum = func(x, y int) uint32 { return uint32(src.Y[src.YOffset(x, y)]) }
+	case *image.Gray:
+		lum = func(x, y int) uint32 { return uint32(src.Pix[src.PixOffset(x, y)]) }
+	}
+
+	var grid [h][w]uint32
+	for gy := 0; gy < h; gy++ {
+		y0, y1 := b.Min.Y+gy*b.Dy()/h, b.Min.Y+(gy+1)*b.Dy()/h
+		for gx := 0; gx < w; gx++ {
+			x0, x1 := b.Min.X+gx*b.Dx()/w, b.Min.X+(gx+1)*b.Dx()/w
+			sx, sy := max(1, (x1-x0)/samplesPerCell), max(1, (y1-y0)/samplesPerCell)
+			var sum, n uint32
+			for y := y0; y < y1; y += sy {
+				for x := x0; x < x1; x += sx {
+					sum += lum(x, y)
+					n++
+				}
+			}
+			grid[gy][gx] = sum / n
+		}
+	}
+
+	var hash uint64
+	for y := 0; y < h; y++ {
+		for x := 0; x < w-1; x++ {
+			hash <<= 1
+			if grid[y][x] > grid[y][x+1] {
+				hash |= 1
+			}
+		}
+	}
+	return hash
+}
+
+// Distance is the number of bits in which two hashes differ.
+func Distance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,57 @@
+package imagehash
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// page draws a white page with dark text-like blocks placed by seed.
+func page(seed int64, w, h int) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	r := rand.New(rand.NewSource(seed))
+	for range 40 {
+		x, y := r.Intn(w*3/4), r.Intn(h*9/10)
+		bw, bh := w/8+r.Intn(w/4), h/60+r.Intn(h/30)
+		for yy := y; yy < min(h, y+bh); yy++ {
+			for xx := x; xx < min(w, x+bw); xx++ {
+				img.SetGray(xx, yy, color.Gray{Y: 30})
+			}
+		}
+	}
+	return img
+}
+
+// halve downsamples by two.
+func halve(src *image.Gray) *image.Gray {
+	b := src.Bounds()
+	dst := image.NewGray(image.Rect(0, 0, b.Dx()/2, b.Dy()/2))
+	for y := 0; y < dst.Rect.Dy(); y++ {
+		for x := 0; x < dst.Rect.Dx(); x++ {
+			dst.SetGray(x, y, src.GrayAt(2*x, 2*y))
+		}
+	}
+	return dst
+}
+
+func TestDHashMatchesResizedRecompressedCopy(t *testing.T) {
+	orig := page(1, 800, 1100)
+
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, halve(orig), &jpeg.Options{Quality: 40}))
+	copied, err := jpeg.Decode(&buf)
+	require.NoError(t, err)
+
+	assert.LessOrEqual(t, Distance(DHash(orig), DHash(copied)), DuplicateDistance)
+	assert.Greater(t, Distance(DHash(orig), DHash(page(2, 800, 1100))), DuplicateDistance)
+	assert.Zero(t, DHash(image.NewGray(image.Rect(0, 0, 4, 4))))
+}
@@ -0,0 +1,186 @@
+package imageprep
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"sort"
+)
+
+// Step is a single image preprocessing operation applied before OCR.
+type Step func(image.Image) image.Image
+
+// steps maps preprocessing names (as used in configs and CLI flags) to operations.
+var steps = map[string]Step{
+	"none":      func(img image.Image) image.Image { return img },
+	"grayscale": Grayscale,
+	"binarize":  func(img image.Image) image.Image { return Binarize(img, OtsuThreshold(img)) },
+}
+
+// Lookup returns the named preprocessing step.
+func Lookup(name string) (Step, error) {
+	s, ok := steps[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown preprocessing step %q", name)
+	}
+	return s, nil
+}
+
+// Names lists the registered preprocessing steps.
+func Names() []string {
+	names := make([]string, 0, len(steps))
+	for n := range steps {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Grayscale converts img to 8-bit grayscale.
+func Grayscale(img image.Image) image.Image {
+	b := img.Bounds()
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			out.Set(x, y, color.GrayModel.Convert(img.At(x, y)))
+		}
+	}
+	return out
+}
+
+// Binarize converts img to pure black/white using threshold (0-255).
+func Binarize(img image.Image, threshold uint8) image.Image {
+	gray := toGray(img)
+	b := gray.Bounds()
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if gray.GrayAt(x, y).Y > threshold {
+				out.SetGray(x, y, color.Gray{Y: 255})
+			}
+		}
+	}
+	return out
+}
+
+// OtsuThreshold picks the global threshold that best separates ink from paper.
+func OtsuThreshold(img image.Image) uint8 {
+	gray := toGray(img)
+	var hist [256]int
+	b := gray.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			hist[gray.GrayAt(x, y).Y]++
+		}
+	}
+
+	total := b.Dx() * b.Dy()
+	if total == 0 {
+		return 128
+	}
+
+	var sum float64
+	for i, h := range hist {
+		sum += float64(i * h)
+	}
+
+	var sumB, maxVar float64
+	var wB int
+	threshold := 128
+	for t := 0; t < 256; t++ {
+		wB += hist[t]
+		if wB == 0 {
+			continue
+		}
+		wF := total - wB
+		if wF == 0 {
+			break
+		}
+		sumB += float64(t * hist[t])
+		mB := sumB / float64(wB)
+		mF := (sum - sumB) / float64(wF)
+		between := float64(wB) * float64(wF) * (mB - mF) * (mB - mF)
+		if between > maxVar {
+			maxVar = between
+			threshold = t
+		}
+	}
+	return uint8(threshold)
+}
+
+func toGray(img image.Image) *image.Gray {
+	if g, ok := img.(*image.Gray); ok {
+		return g
+	}
+	return Grayscale(img).(*image.Gray)
+}
+
+// Upscale enlarges img factor times by pixel replication, so small print
+// reaches the glyph height OCR engines are trained on.
+func Upscale(img image.Image, factor int) image.Image {
+	if factor <= 1 {
+		return img
+	}
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, b.Dx()*factor, b.Dy()*factor))
+	for y := 0; y < out.Bounds().Dy(); y++ {
+		for x := 0; x < out.Bounds().Dx(); x++ {
+			out.Set(x, y, img.At(b.Min.X+x/factor, b.Min.Y+y/factor))
+		}
+	}
+	return out
+}
+
+// Rotate turns img clockwise by degrees, a multiple of 90.
+func Rotate(img image.Image, degrees int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	switch ((degrees%360 + 360) % 360) / 90 {
+	case 1:
+		return remap(img, h, w, func(x, y int) (int, int) { return y, h - 1 - x })
+	case 2:
+		return remap(img, w, h, func(x, y int) (int, int) { return w - 1 - x, h - 1 - y })
+	case 3:
+		return remap(img, h, w, func(x, y int) (int, int) { return w - 1 - y, x })
+	}
+	return img
+}
+
+// Orient applies an EXIF orientation (1-8) so that img reads upright, and
+// returns the clockwise rotation that took; mirrored orientations are also
+// flipped.
+func Orient(img image.Image, orientation int) (image.Image, int) {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	switch orientation {
+	case 2:
+		return remap(img, w, h, func(x, y int) (int, int) { return w - 1 - x, y }), 0
+	case 3:
+		return Rotate(img, 180), 180
+	case 4:
+		return remap(img, w, h, func(x, y int) (int, int) { return x, h - 1 - y }), 0
+	case 5:
+		return remap(img, h, w, func(x, y int) (int, int) { return y, x }), 270
+	case 6:
+		return Rotate(img, 90), 90
+	case 7:
+		return remap(img, h, w, func(x, y int) (int, int) { return w - 1 - y, h - 1 - x }), 90
+	case 8:
+		return Rotate(img, 270), 270
+	}
+	return img, 0
+}
+
+// remap builds a w×h image whose pixel (x, y) is img's pixel src(x, y),
+// relative to img's bounds.
+func remap(img image.Image, w, h int, src func(x, y int) (int, int)) image.Image {
+	b := img.Bounds()
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			sx, sy := src(x, y)
+			out.Set(x, y, img.At(b.Min.X+sx, b.Min.Y+sy))
+		}
+	}
+	return out
+}
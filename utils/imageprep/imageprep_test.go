@@ -0,0 +1,62 @@
+package imageprep
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// marked is a 3×2 white image with a black pixel in its top-left corner.
+func marked() image.Image {
+	img := image.NewGray(image.Rect(0, 0, 3, 2))
+	for i := range img.Pix {
+		img.Pix[i] = 255
+	}
+	img.SetGray(0, 0, color.Gray{})
+	return img
+}
+
+// corner returns the black pixel of img.
+func corner(img image.Image) image.Point {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			if r, _, _, _ := img.At(x, y).RGBA(); r == 0 {
+				return image.Pt(x, y)
+			}
+		}
+	}
+	return image.Pt(-1, -1)
+}
+
+func TestRotate(t *testing.T) {
+	assert.Equal(t, image.Pt(1, 0), corner(Rotate(marked(), 90)))
+	assert.Equal(t, image.Rect(0, 0, 2, 3), Rotate(marked(), 90).Bounds())
+	assert.Equal(t, image.Pt(2, 1), corner(Rotate(marked(), 180)))
+	assert.Equal(t, image.Pt(0, 2), corner(Rotate(marked(), 270)))
+	assert.Equal(t, image.Pt(0, 0), corner(Rotate(marked(), 360)))
+}
+
+func TestOrient(t *testing.T) {
+	tests := []struct {
+		orientation int
+		corner      image.Point
+		degrees     int
+	}{
+		{1, image.Pt(0, 0), 0},
+		{2, image.Pt(2, 0), 0},
+		{3, image.Pt(2, 1), 180},
+		{4, image.Pt(0, 1), 0},
+		{5, image.Pt(0, 0), 270},
+		{6, image.Pt(1, 0), 90},
+		{7, image.Pt(1, 2), 90},
+		{8, image.Pt(0, 2), 270},
+	}
+	for _, tt := range tests {
+		img, degrees := Orient(marked(), tt.orientation)
+		assert.Equal(t, tt.corner, corner(img), "orientation %d", tt.orientation)
+		assert.Equal(t, tt.degrees, degrees, "orientation %d", tt.orientation)
+	}
+}
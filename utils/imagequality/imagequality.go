@@ -0,0 +1,223 @@
+// Package imagequality measures how well a page image can be read: its
+// resolution, contrast and sharpness, and for photos whether glare hides part
+// of the document or the document runs off the frame.
+package imagequality
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+// Issue codes for a recapture prompt.
+const (
+	IssueBlurry        = "blurry"
+	IssueGlare         = "glare"
+	IssueCropped       = "cropped"
+	IssueLowResolution = "low_resolution"
+)
+
+const (
+	// analysisSide is the long side images are sampled down to before
+	// analysis; the thresholds below are calibrated at this size.
+	analysisSide = 1200
+	// fullResolutionSide is the short side, in pixels, that scores 100 for
+	// resolution: an A4 page at 150 dpi, or an ID card filling a phone photo.
+	fullResolutionSide = 1000
+	// blurVariance is the variance of the Laplacian below which a page is
+	// blurry; it scores 50 for sharpness.
+	blurVariance = 100
+	// clipped is the luminance from which a pixel is taken as blown out.
+	clipped = 250
+	// glareCell is the share of a grid cell that must be blown out for glare,
+	// and glareMaxClipped the share of the whole image above which bright
+	// pixels are taken as white paper (a flat scan) rather than glare.
+	glareCell       = 0.6
+	glareMaxClipped = 0.3
+	glareGrid       = 8
+	// edgeStep is the luminance step between neighbours that counts as ink.
+	edgeStep = 48
+)
+
+// Metrics are the quality scores (0-100) and issues of one page image.
+type Metrics struct {
+	ResolutionScore float64
+	ContrastScore   float64
+	SharpnessScore  float64
+	Issues          []string
+}
+
+// Analyze scores img. Glare and cropping are only judged for photos: rendered
+// PDF pages and flat scans are bright by design and fill their frame.
+func Analyze(img image.Image, photo bool) Metrics {
+	b := img.Bounds()
+	var m Metrics
+	short := min(b.Dx(), b.Dy())
+	m.ResolutionScore = math.Min(100, float64(short)/fullResolutionSide*100)
+	if short < fullResolutionSide/2 {
+		m.Issues = append(m.Issues, IssueLowResolution)
+	}
+
+	g := sample(img)
+	if g.w < 3 || g.h < 3 {
+		return m
+	}
+	m.ContrastScore = math.Min(100, g.stddev()/50*100)
+
+	variance := g.laplacianVariance()
+	m.SharpnessScore = 100 * variance / (variance + blurVariance)
+	if variance < blurVariance {
+		m.Issues = append(m.Issues, IssueBlurry)
+	}
+
+	if photo {
+		if g.glare() {
+			m.Issues = append(m.Issues, IssueGlare)
+		}
+		if g.cropped() {
+			m.Issues = append(m.Issues, IssueCropped)
+		}
+	}
+	return m
+}
+
+// gray is a luminance raster, row-major.
+type gray struct {
+	w, h int
+	pix  []uint8
+}
+
+func (g *gray) at(x, y int) int { return int(g.pix[y*g.w+x]) }
+
+// sample reads img into a luminance raster no longer than analysisSide.
+func sample(img image.Image) *gray {
+	b := img.Bounds()
+	step := max(1, (max(b.Dx(), b.Dy())+analysisSide-1)/analysisSide)
+	g := &gray{w: b.Dx() / step, h: b.Dy() / step}
+	g.pix = make([]uint8, g.w*g.h)
+
+	lum := func(x, y int) uint8 { return color.GrayModel.Convert(img.At(x, y)).(color.Gray).Y }
+	switch src := img.(type) {
+	case *image.YCbCr: // camera JPEGs: Y is the luminance
+		lum = func(x, y int) uint8 { return src.Y[src.YOffset(x, y)] }
+	case *image.Gray:
+		lum = func(x, y int) uint8 { return src.Pix[src.PixOffset(x, y)] }
+	}
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			g.pix[y*g.w+x] = lum(b.Min.X+x*step, b.Min.Y+y*step)
+		}
+	}
+	return g
+}
+
+func (g *gray) stddev() float64 {
+	var sum, sq float64
+	for _, p := range g.pix {
+		v := float64(p)
+		sum += v
+		sq += v * v
+	}
+	n := float64(len(g.pix))
+	mean := sum / n
+	return math.Sqrt(math.Max(0, sq/n-mean*mean))
+}
+
+// laplacianVariance is the variance of the 4-neighbour Laplacian: sharp
+// text has strong second derivatives at its strokes, blur flattens them.
+func (g *gray) laplacianVariance() float64 {
+	var sum, sq float64
+	n := 0
+	for y := 1; y < g.h-1; y++ {
+		for x := 1; x < g.w-1; x++ {
+			l := float64(g.at(x-1, y) + g.at(x+1, y) + g.at(x, y-1) + g.at(x, y+1) - 4*g.at(x, y))
+			sum += l
+			sq += l * l
+			n++
+		}
+	}
+	mean := sum / float64(n)
+	return sq/float64(n) - mean*mean
+}
+
+// glare reports a blown-out patch: a grid cell mostly clipped to white in an
+// image that is not mostly white.
+func (g *gray) glare() bool {
+	total := 0
+	cells := make([]int, glareGrid*glareGrid)
+	for y := 0; y < g.h; y++ {
+		for x := 0; x < g.w; x++ {
+			if g.at(x, y) >= clipped {
+				total++
+				cells[(y*glareGrid/g.h)*glareGrid+x*glareGrid/g.w]++
+			}
+		}
+	}
+	if float64(total) > glareMaxClipped*float64(len(g.pix)) {
+		return false
+	}
+	cellArea := float64(g.w*g.h) / (glareGrid * glareGrid)
+	for _, n := range cells {
+		if float64(n) >= glareCell*cellArea {
+			return true
+		}
+	}
+	return false
+}
+
+// cropped reports content running off the frame: in a strip along some edge
+// of the photo, most lines parallel to that edge cross ink. A document shot
+// whole has background or its own blank margin there; a single line (the
+// document's edge, a table edge) only crosses a few lines of the strip.
+func (g *gray) cropped() bool {
+	strip := max(3, min(g.w, g.h)/50)
+	edge := func(x, y int) bool {
+		return x+1 < g.w && absInt(g.at(x+1, y)-g.at(x, y)) >= edgeStep ||
+			y+1 < g.h && absInt(g.at(x, y+1)-g.at(x, y)) >= edgeStep
+	}
+	// inked reports whether a line (a row when horizontal) of the strip has
+	// ink on at least 5% of its pixels.
+	inked := func(pos int, horizontal bool) bool {
+		length := g.h
+		if horizontal {
+			length = g.w
+		}
+		n := 0
+		for i := 0; i < length; i++ {
+			x, y := pos, i
+			if horizontal {
+				x, y = i, pos
+			}
+			if edge(x, y) {
+				n++
+			}
+		}
+		return n*20 >= length
+	}
+	sides := []struct {
+		first, dir int
+		horizontal bool
+	}{
+		{0, 1, true}, {g.h - 1, -1, true}, // top, bottom
+		{0, 1, false}, {g.w - 1, -1, false}, // left, right
+	}
+	for _, s := range sides {
+		lines := 0
+		for i := 0; i < strip; i++ {
+			if inked(s.first+s.dir*i, s.horizontal) {
+				lines++
+			}
+		}
+		if lines*10 >= strip*6 {
+			return true
+		}
+	}
+	return false
+}
+
+func absInt(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
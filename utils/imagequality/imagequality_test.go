@@ -0,0 +1,92 @@
+package imagequality
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// page draws a w×h background with a document of the paper shade inset by
+// margin, covered in lines of dark "words" (strokes 3 px wide). Paper in a
+// photo is grey, in a scan white.
+func page(w, h, margin int, background, paper uint8) *image.Gray {
+	img := image.NewGray(image.Rect(0, 0, w, h))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Gray{Y: background}), image.Point{}, draw.Src)
+	doc := image.Rect(margin, margin, w-margin, h-margin)
+	draw.Draw(img, doc, image.NewUniform(color.Gray{Y: paper}), image.Point{}, draw.Src)
+	for y := doc.Min.Y + 20; y+14 < doc.Max.Y-10; y += 30 {
+		for x := doc.Min.X + 20; x+6 < doc.Max.X-20; x += 9 {
+			draw.Draw(img, image.Rect(x, y, x+3, y+14), image.NewUniform(color.Black), image.Point{}, draw.Src)
+		}
+	}
+	return img
+}
+
+// boxBlur averages each pixel with its (2r+1)² neighbourhood.
+func boxBlur(img *image.Gray, r int) *image.Gray {
+	b := img.Bounds()
+	out := image.NewGray(b)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			sum, n := 0, 0
+			for dy := -r; dy <= r; dy++ {
+				for dx := -r; dx <= r; dx++ {
+					if p := image.Pt(x+dx, y+dy); p.In(b) {
+						sum += int(img.GrayAt(p.X, p.Y).Y)
+						n++
+					}
+				}
+			}
+			out.SetGray(x, y, color.Gray{Y: uint8(sum / n)})
+		}
+	}
+	return out
+}
+
+func TestAnalyzeSharpPhoto(t *testing.T) {
+	m := Analyze(page(1200, 800, 80, 120, 210), true)
+	assert.Empty(t, m.Issues)
+	assert.Equal(t, 80.0, m.ResolutionScore)
+	assert.Greater(t, m.SharpnessScore, 50.0)
+	assert.Greater(t, m.ContrastScore, 0.0)
+}
+
+func TestAnalyzeBlurry(t *testing.T) {
+	m := Analyze(boxBlur(page(600, 400, 40, 120, 210), 4), true)
+	assert.Contains(t, m.Issues, IssueBlurry)
+	assert.Less(t, m.SharpnessScore, 50.0)
+}
+
+func TestAnalyzeGlare(t *testing.T) {
+	img := page(1200, 800, 80, 120, 210)
+	for y := 200; y < 200+img.Bounds().Dy()/4; y++ {
+		for x := 300; x < 300+img.Bounds().Dx()/4; x++ {
+			img.SetGray(x, y, color.Gray{Y: 255})
+		}
+	}
+	assert.Contains(t, Analyze(img, true).Issues, IssueGlare)
+	// a flat scan is white all over: no glare
+	assert.NotContains(t, Analyze(page(1200, 800, 0, 255, 255), false).Issues, IssueGlare)
+}
+
+func TestAnalyzeCropped(t *testing.T) {
+	// text lines run off the top of the frame
+	img := page(1200, 800, 0, 255, 255)
+	for x := 20; x+6 < 1180; x += 9 {
+		draw.Draw(img, image.Rect(x, 0, x+3, 14), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	}
+	assert.Contains(t, Analyze(img, true).Issues, IssueCropped)
+	// the whole document in frame, its edge crossing the strip once
+	assert.NotContains(t, Analyze(page(1200, 800, 10, 60, 210), true).Issues, IssueCropped)
+	// only photos are judged
+	assert.NotContains(t, Analyze(img, false).Issues, IssueCropped)
+}
+
+func TestAnalyzeLowResolution(t *testing.T) {
+	m := Analyze(page(400, 300, 10, 120, 210), true)
+	assert.Contains(t, m.Issues, IssueLowResolution)
+	assert.Equal(t, 30.0, m.ResolutionScore)
+}
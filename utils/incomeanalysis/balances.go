@@ -0,0 +1,75 @@
+package incomeanalysis
+
+import (
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Balances computes the closing, minimum and average balance of each month a
+// statement covers from its balance column. The balance at the end of a day
+// is carried over days without transactions; days before the first row
+// inside the statement period start from the opening balance that row
+// implies. Returns nil when no row has a balance.
+func Balances(stmt dto.BankStatementData) []dto.MonthlyBalance {
+	var rows []dto.BankTransaction
+	for _, tx := range stmt.Transactions {
+		if tx.Balance != 0 && !tx.Date.IsZero() {
+			rows = append(rows, tx)
+		}
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+	// Newest-first statements: same-day rows must stay in posting order
+	if rows[0].Date.After(rows[len(rows)-1].Date) {
+		for i, j := 0, len(rows)-1; i < j; i, j = i+1, j-1 {
+			rows[i], rows[j] = rows[j], rows[i]
+		}
+	}
+
+	start, end := dayOf(rows[0].Date), dayOf(rows[len(rows)-1].Date)
+	balance := rows[0].Balance
+	if stmt.PeriodFrom != nil && dayOf(*stmt.PeriodFrom).Before(start) {
+		start = dayOf(*stmt.PeriodFrom)
+		balance = rows[0].Balance - signedAmount(rows[0])
+	}
+	if stmt.PeriodTo != nil && dayOf(*stmt.PeriodTo).After(end) {
+		end = dayOf(*stmt.PeriodTo)
+	}
+
+	var out []dto.MonthlyBalance
+	var sum float64
+	i := 0
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		if len(out) == 0 || out[len(out)-1].Month != monthOf(d) {
+			if len(out) > 0 {
+				out[len(out)-1].Average = round2(sum / float64(out[len(out)-1].Days))
+			}
+			out = append(out, dto.MonthlyBalance{Month: monthOf(d), Minimum: balance})
+			sum = 0
+		}
+		mb := &out[len(out)-1]
+		for ; i < len(rows) && !dayOf(rows[i].Date).After(d); i++ {
+			balance = rows[i].Balance
+			mb.Minimum = min(mb.Minimum, balance)
+		}
+		mb.Minimum = min(mb.Minimum, balance)
+		mb.Closing = balance
+		mb.Days++
+		sum += balance
+	}
+	out[len(out)-1].Average = round2(sum / float64(out[len(out)-1].Days))
+	return out
+}
+
+func dayOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func signedAmount(tx dto.BankTransaction) float64 {
+	if tx.IsCredit {
+		return tx.Amount
+	}
+	return -tx.Amount
+}
@@ -0,0 +1,56 @@
+package incomeanalysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	returnRe       = regexp.MustCompile(`\b(RET|RTN|RETN|RETURN|RETURNED|BOUNCE|BOUNCED|DISHONOU?R(ED)?|REJECT(ED)?|UNPAID)\b`)
+	insufficientRe = regexp.MustCompile(`\b(INSUFF?|INSUFFICIENT|FUNDS? INSUFF\w*|IN-?SUFF\w*|NSF)\b`)
+	chargeRe       = regexp.MustCompile(`\b(CHG|CHGS|CHRG|CHRGS|CHARGE|CHARGES|PENAL|PENALTY|FEE|FEES)\b`)
+	chequeRe       = regexp.MustCompile(`\b(CHQ|CHEQUE|CHECK|CLG|CLEARING|I/?W|O/?W|INWARD|OUTWARD)\b`)
+	mandateRe      = regexp.MustCompile(`\b(ECS|NACH|ACH|MANDATE|EMI|SI)\b`)
+)
+
+// Bounces counts, per month, the cheques and ECS/NACH mandates returned
+// unpaid and the charges the bank levied for them or for insufficient funds.
+// Only months with such entries are listed.
+func Bounces(stmts []dto.BankStatementData) []dto.MonthlyBounces {
+	byMonth := map[string]*dto.MonthlyBounces{}
+	count := func(tx dto.BankTransaction) *dto.MonthlyBounces {
+		m := monthOf(tx.Date)
+		if byMonth[m] == nil {
+			byMonth[m] = &dto.MonthlyBounces{Month: m}
+		}
+		return byMonth[m]
+	}
+
+	txs := append(uniqueTransactions(stmts, false), uniqueTransactions(stmts, true)...)
+	for _, tx := range txs {
+		d := strings.ToUpper(tx.Description)
+		returned := returnRe.MatchString(d)
+		insufficient := insufficientRe.MatchString(d)
+		switch {
+		case chargeRe.MatchString(d) && (returned || insufficient):
+			mb := count(tx)
+			mb.PenaltyCharges++
+			mb.PenaltyAmount = round2(mb.PenaltyAmount + tx.Amount)
+		case !(returned || insufficient):
+		case mandateRe.MatchString(d):
+			count(tx).MandateReturns++
+		case chequeRe.MatchString(d) || insufficient:
+			count(tx).ChequeReturns++
+		}
+	}
+
+	out := []dto.MonthlyBounces{}
+	for _, mb := range byMonth {
+		out = append(out, *mb)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Month < out[j].Month })
+	return out
+}
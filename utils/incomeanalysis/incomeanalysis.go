@@ -0,0 +1,293 @@
+// Package incomeanalysis summarizes bank statement credits into monthly income.
+package incomeanalysis
+
+import (
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+const (
+	// amountTolerance is how far a credit may be from the payer's median amount
+	// and still count towards the same recurring run.
+	amountTolerance = 0.2
+	// minMonths is how many months a payer must credit to count as recurring;
+	// credits described as salary need fewer.
+	minMonths       = 3
+	minSalaryMonths = 2
+	// maxGapMonths allows one skipped month (e.g. salary paid on the 1st after a 31st).
+	maxGapMonths = 2
+)
+
+var (
+	tokenSplit = regexp.MustCompile(`[^A-Z0-9]+`)
+	hasDigit   = regexp.MustCompile(`[0-9]`)
+
+	// Payment rails, connecting words and salary/month words say nothing about who paid.
+	ignoredTokens = toSet(
+		"NEFT", "IMPS", "RTGS", "UPI", "ACH", "NACH", "ECS", "INB", "MB", "CMS",
+		"CR", "CREDIT", "CREDITED", "BY", "FROM", "TO", "TRF", "TRANSFER", "FT", "REF", "NO",
+		"SAL", "SALARY", "PAY", "PAYOUT", "FOR", "THE", "OF", "MONTH",
+		"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "SEPT", "OCT", "NOV", "DEC",
+		"JANUARY", "FEBRUARY", "MARCH", "APRIL", "JUNE", "JULY", "AUGUST", "SEPTEMBER", "OCTOBER", "NOVEMBER", "DECEMBER",
+	)
+)
+
+// Summarize aggregates the credits of all statements per month and detects
+// recurring salary-like credits. Returns nil when the statements have no dated
+// transactions. Transactions repeated across overlapping statements are counted once.
+func Summarize(stmts []dto.BankStatementData) *dto.MonthlyIncomeSummary {
+	months := coveredMonths(stmts)
+	if len(months) == 0 {
+		return nil
+	}
+
+	byMonth := map[string]*dto.MonthlyCredits{}
+	summary := &dto.MonthlyIncomeSummary{Months: []dto.MonthlyCredits{}, RecurringCredits: []dto.RecurringCredit{}}
+	for _, m := range months {
+		summary.Months = append(summary.Months, dto.MonthlyCredits{Month: m})
+	}
+	for i := range summary.Months {
+		byMonth[summary.Months[i].Month] = &summary.Months[i]
+	}
+
+	credits := uniqueCredits(stmts)
+	var total float64
+	groups := map[string][]dto.BankTransaction{}
+	for _, tx := range credits {
+		if mc, ok := byMonth[monthOf(tx.Date)]; ok {
+			mc.TotalCredits += tx.Amount
+			mc.CreditCount++
+		}
+		total += tx.Amount
+		if payer := payerOf(tx); payer != "" {
+			groups[payer] = append(groups[payer], tx)
+		}
+	}
+
+	payers := make([]string, 0, len(groups))
+	for p := range groups {
+		payers = append(payers, p)
+	}
+	sort.Strings(payers)
+
+	for _, payer := range payers {
+		run, ok := recurringRun(groups[payer])
+		if !ok {
+			continue
+		}
+		rc := dto.RecurringCredit{Payer: payer}
+		var sum float64
+		seen := map[string]bool{}
+		for _, tx := range run {
+			sum += tx.Amount
+			m := monthOf(tx.Date)
+			if mc, ok := byMonth[m]; ok {
+				mc.RecurringIncome += tx.Amount
+			}
+			if !seen[m] {
+				seen[m] = true
+				rc.Months = append(rc.Months, m)
+			}
+			if IsSalary(tx.Description) {
+				rc.SalaryLike = true
+			}
+		}
+		rc.AverageAmount = round2(sum / float64(len(rc.Months)))
+		summary.RecurringCredits = append(summary.RecurringCredits, rc)
+		summary.AverageMonthlyIncome += rc.AverageAmount
+	}
+
+	summary.AverageMonthlyCredits = round2(total / float64(len(months)))
+	summary.AverageMonthlyIncome = round2(summary.AverageMonthlyIncome)
+	return summary
+}
+
+// PayerKey reduces a transaction description to the name of whoever paid, e.g.
+// "NEFT-HDFC0001234-ACME SOFTWARE PVT LTD-SALARY OCT" → "ACME SOFTWARE PVT".
+func PayerKey(description string) string {
+	var out []string
+	for _, tok := range tokenSplit.Split(strings.ToUpper(description), -1) {
+		if len(tok) < 2 || hasDigit.MatchString(tok) || ignoredTokens[tok] {
+			continue
+		}
+		out = append(out, tok)
+		if len(out) == 3 {
+			break
+		}
+	}
+	return strings.Join(out, " ")
+}
+
+// payerOf is the PayerKey of a credit, taken from the UPI counterparty when
+// there is one: UPI descriptions start with reference numbers and app names.
+func payerOf(tx dto.BankTransaction) string {
+	if tx.UPI != nil && tx.UPI.Counterparty != "" {
+		return PayerKey(tx.UPI.Counterparty)
+	}
+	return PayerKey(tx.Description)
+}
+
+// recurringRun returns the longest monthly run of similar-amount credits from one payer.
+func recurringRun(txs []dto.BankTransaction) ([]dto.BankTransaction, bool) {
+	best := monthlyRun(txs)
+	months := distinctMonths(best)
+	salary := false
+	for _, tx := range best {
+		salary = salary || IsSalary(tx.Description)
+	}
+	// More than two credits a month is a frequent counterparty, not a monthly income.
+	if len(best) > 2*months {
+		return nil, false
+	}
+	if months >= minMonths || (salary && months >= minSalaryMonths) {
+		return best, true
+	}
+	return nil, false
+}
+
+// monthlyRun returns the longest run of transactions within amountTolerance of
+// the median amount with no more than maxGapMonths between them.
+func monthlyRun(txs []dto.BankTransaction) []dto.BankTransaction {
+	sort.Slice(txs, func(i, j int) bool { return txs[i].Date.Before(txs[j].Date) })
+
+	amounts := make([]float64, len(txs))
+	for i, tx := range txs {
+		amounts[i] = tx.Amount
+	}
+	median := medianOf(amounts)
+
+	var similar []dto.BankTransaction
+	for _, tx := range txs {
+		if math.Abs(tx.Amount-median) <= median*amountTolerance {
+			similar = append(similar, tx)
+		}
+	}
+
+	// Split into runs wherever consecutive credits are too far apart.
+	var best, cur []dto.BankTransaction
+	for i, tx := range similar {
+		if i > 0 && monthIndex(tx.Date)-monthIndex(similar[i-1].Date) > maxGapMonths {
+			cur = nil
+		}
+		cur = append(cur, tx)
+		if distinctMonths(cur) > distinctMonths(best) {
+			best = append([]dto.BankTransaction(nil), cur...)
+		}
+	}
+	return best
+}
+
+// coveredMonths lists every month from the earliest to the latest statement date.
+func coveredMonths(stmts []dto.BankStatementData) []string {
+	var first, last time.Time
+	note := func(t time.Time) {
+		if t.IsZero() {
+			return
+		}
+		if first.IsZero() || t.Before(first) {
+			first = t
+		}
+		if last.IsZero() || t.After(last) {
+			last = t
+		}
+	}
+	for _, s := range stmts {
+		if s.PeriodFrom != nil {
+			note(*s.PeriodFrom)
+		}
+		if s.PeriodTo != nil {
+			note(*s.PeriodTo)
+		}
+		for _, tx := range s.Transactions {
+			note(tx.Date)
+		}
+	}
+	if first.IsZero() {
+		return nil
+	}
+
+	var out []string
+	for m := time.Date(first.Year(), first.Month(), 1, 0, 0, 0, 0, time.UTC); !m.After(last); m = m.AddDate(0, 1, 0) {
+		out = append(out, m.Format("2006-01"))
+	}
+	return out
+}
+
+func uniqueCredits(stmts []dto.BankStatementData) []dto.BankTransaction {
+	return uniqueTransactions(stmts, true)
+}
+
+// uniqueTransactions returns the dated credits (or debits) of the statements,
+// counting a transaction repeated across overlapping statements once.
+func uniqueTransactions(stmts []dto.BankStatementData, credits bool) []dto.BankTransaction {
+	type key struct {
+		date   string
+		amount float64
+		desc   string
+	}
+	seen := map[key]bool{}
+	var out []dto.BankTransaction
+	for _, s := range stmts {
+		for _, tx := range s.Transactions {
+			if tx.IsCredit != credits || tx.Amount <= 0 || tx.Date.IsZero() {
+				continue
+			}
+			k := key{tx.Date.Format("2006-01-02"), tx.Amount, tx.Description}
+			if seen[k] {
+				continue
+			}
+			seen[k] = true
+			out = append(out, tx)
+		}
+	}
+	return out
+}
+
+// IsSalary reports whether a transaction description names a salary credit.
+func IsSalary(description string) bool {
+	d := strings.ToUpper(description)
+	return strings.Contains(d, "SALARY") || strings.Contains(d, "SAL ") || strings.Contains(d, "SAL-") || strings.HasPrefix(d, "SAL")
+}
+
+func distinctMonths(txs []dto.BankTransaction) int {
+	seen := map[string]bool{}
+	for _, tx := range txs {
+		seen[monthOf(tx.Date)] = true
+	}
+	return len(seen)
+}
+
+func monthOf(t time.Time) string {
+	return t.Format("2006-01")
+}
+
+func monthIndex(t time.Time) int {
+	return t.Year()*12 + int(t.Month())
+}
+
+func medianOf(v []float64) float64 {
+	s := append([]float64(nil), v...)
+	sort.Float64s(s)
+	n := len(s)
+	if n%2 == 1 {
+		return s[n/2]
+	}
+	return (s[n/2-1] + s[n/2]) / 2
+}
+
+func round2(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+func toSet(words ...string) map[string]bool {
+	m := make(map[string]bool, len(words))
+	for _, w := range words {
+		m[w] = true
+	}
+	return m
+}
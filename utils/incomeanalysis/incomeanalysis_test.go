@@ -0,0 +1,128 @@
+package incomeanalysis
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func day(y int, m time.Month, d int) time.Time {
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+func TestSummarizeDetectsSalary(t *testing.T) {
+	stmt := dto.BankStatementData{
+		Transactions: []dto.BankTransaction{
+			{Date: day(2025, 7, 1), IsCredit: true, Amount: 52000, Description: "NEFT-HDFC0001234-ACME SOFTWARE PVT LTD-SALARY JUN"},
+			{Date: day(2025, 7, 14), IsCredit: true, Amount: 1500, Description: "UPI/509812/RAHUL"},
+			{Date: day(2025, 7, 20), IsCredit: false, Amount: 20000, Description: "RENT"},
+			{Date: day(2025, 8, 1), IsCredit: true, Amount: 52000, Description: "NEFT-HDFC0004321-ACME SOFTWARE PVT LTD-SALARY JUL"},
+			{Date: day(2025, 9, 30), IsCredit: true, Amount: 54000, Description: "NEFT-HDFC0009876-ACME SOFTWARE PVT LTD-SALARY AUG"},
+			{Date: day(2025, 9, 12), IsCredit: true, Amount: 300, Description: "INTEREST CREDIT"},
+		},
+	}
+
+	s := Summarize([]dto.BankStatementData{stmt, stmt}) // overlapping statements count once
+	require.NotNil(t, s)
+
+	require.Len(t, s.Months, 3)
+	assert.Equal(t, "2025-07", s.Months[0].Month)
+	assert.Equal(t, 53500.0, s.Months[0].TotalCredits)
+	assert.Equal(t, 2, s.Months[0].CreditCount)
+	assert.Equal(t, 52000.0, s.Months[0].RecurringIncome)
+
+	require.Len(t, s.RecurringCredits, 1)
+	rc := s.RecurringCredits[0]
+	assert.Equal(t, "ACME SOFTWARE PVT", rc.Payer)
+	assert.True(t, rc.SalaryLike)
+	assert.Equal(t, []string{"2025-07", "2025-08", "2025-09"}, rc.Months)
+	assert.InDelta(t, 52666.67, s.AverageMonthlyIncome, 0.01)
+	assert.InDelta(t, (53500.0+52000+54300)/3, s.AverageMonthlyCredits, 0.01)
+}
+
+func TestSummarizeIgnoresIrregularPayers(t *testing.T) {
+	stmt := dto.BankStatementData{
+		Transactions: []dto.BankTransaction{
+			{Date: day(2025, 1, 5), IsCredit: true, Amount: 10000, Description: "IMPS/FREELANCE CLIENT"},
+			{Date: day(2025, 6, 5), IsCredit: true, Amount: 10000, Description: "IMPS/FREELANCE CLIENT"},
+			{Date: day(2025, 7, 5), IsCredit: true, Amount: 90000, Description: "IMPS/FREELANCE CLIENT"},
+		},
+	}
+
+	s := Summarize([]dto.BankStatementData{stmt})
+	require.NotNil(t, s)
+	assert.Empty(t, s.RecurringCredits)
+	assert.Zero(t, s.AverageMonthlyIncome)
+	assert.Len(t, s.Months, 7)
+
+	assert.Nil(t, Summarize(nil))
+}
+
+func TestObligationsGroupsEMIsByLender(t *testing.T) {
+	stmt := dto.BankStatementData{
+		Transactions: []dto.BankTransaction{
+			{Date: day(2025, 7, 5), Amount: 12500, Description: "NACH-DR-BAJAJ FINANCE LTD-EMI 0412"},
+			{Date: day(2025, 8, 5), Amount: 12500, Description: "NACH-DR-BAJAJ FINANCE LTD-EMI 0512"},
+			{Date: day(2025, 7, 10), Amount: 8000, Description: "ECS/HDFC BANK LOAN/45612"},
+			{Date: day(2025, 7, 12), Amount: 5000, Description: "NACH-DR-BSE STAR MF-SIP"},
+			{Date: day(2025, 7, 13), Amount: 590, Description: "ECS RTN CHGS INCL GST"},
+			{Date: day(2025, 7, 20), Amount: 20000, Description: "RENT"},
+			{Date: day(2025, 7, 1), IsCredit: true, Amount: 50000, Description: "SALARY"},
+		},
+	}
+
+	o := Obligations([]dto.BankStatementData{stmt}, 50000)
+	require.NotNil(t, o)
+	require.Len(t, o.Obligations, 2)
+	assert.Equal(t, dto.Obligation{Lender: "BAJAJ FINANCE LTD", Mode: "EMI", MonthlyAmount: 12500, Months: []string{"2025-07", "2025-08"}, Recurring: true}, o.Obligations[0])
+	assert.Equal(t, "HDFC BANK", o.Obligations[1].Lender)
+	assert.Equal(t, "ECS", o.Obligations[1].Mode)
+	assert.Equal(t, 20500.0, o.TotalMonthlyObligation)
+	assert.Equal(t, 0.41, o.FOIR)
+
+	assert.Nil(t, Obligations([]dto.BankStatementData{{Transactions: stmt.Transactions[4:]}}, 0))
+}
+
+func TestBouncesPerMonth(t *testing.T) {
+	stmt := dto.BankStatementData{
+		Transactions: []dto.BankTransaction{
+			{Date: day(2025, 7, 5), Amount: 12500, Description: "NACH-DR-BAJAJ FINANCE LTD-EMI 0412"},
+			{Date: day(2025, 7, 6), IsCredit: true, Amount: 12500, Description: "NACH RTN-BAJAJ FINANCE-INSUFFICIENT FUNDS"},
+			{Date: day(2025, 7, 6), Amount: 590, Description: "ECS RTN CHGS INCL GST"},
+			{Date: day(2025, 9, 2), Amount: 15000, Description: "I/W CHQ RETURN 004512 FUNDS INSUFFICIENT"},
+			{Date: day(2025, 9, 20), Amount: 20000, Description: "RENT"},
+		},
+	}
+
+	b := Bounces([]dto.BankStatementData{stmt})
+	assert.Equal(t, []dto.MonthlyBounces{
+		{Month: "2025-07", MandateReturns: 1, PenaltyCharges: 1, PenaltyAmount: 590},
+		{Month: "2025-09", ChequeReturns: 1},
+	}, b)
+}
+
+func TestBalancesPerMonth(t *testing.T) {
+	from, to := day(2025, 7, 1), day(2025, 8, 31)
+	stmt := dto.BankStatementData{
+		PeriodFrom: &from,
+		PeriodTo:   &to,
+		Transactions: []dto.BankTransaction{
+			{Date: day(2025, 7, 11), IsCredit: true, Amount: 50000, Balance: 60000, Description: "SALARY"},
+			{Date: day(2025, 7, 21), Amount: 40000, Balance: 20000, Description: "RENT"},
+			{Date: day(2025, 8, 1), Amount: 15000, Balance: 5000, Description: "EMI"},
+			{Date: day(2025, 8, 1), IsCredit: true, Amount: 55000, Balance: 60000, Description: "SALARY"},
+		},
+	}
+
+	b := Balances(stmt)
+	require.Len(t, b, 2)
+	// 10 days at 10000, 10 at 60000, 11 at 20000
+	assert.Equal(t, dto.MonthlyBalance{Month: "2025-07", Closing: 20000, Minimum: 10000, Average: 29677.42, Days: 31}, b[0])
+	assert.Equal(t, dto.MonthlyBalance{Month: "2025-08", Closing: 60000, Minimum: 5000, Average: 60000, Days: 31}, b[1])
+
+	assert.Nil(t, Balances(dto.BankStatementData{}))
+}
@@ -0,0 +1,118 @@
+package incomeanalysis
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	// emiRe finds the payment modes lenders collect EMIs through; the first
+	// match names the mode.
+	emiRe = regexp.MustCompile(`\b(EMI|NACH|ACH|ECS|LOAN|LN|SI)\b`)
+
+	// investmentTokens mark mandates that buy investments or insurance rather
+	// than repay a loan: mutual fund SIPs are collected through NACH too.
+	investmentTokens = toSet("MF", "MUTUAL", "FUND", "SIP", "BSE", "NSE", "CAMS", "KFINTECH", "ZERODHA", "GROWW",
+		"INSURANCE", "LIC", "PREMIUM", "NPS", "PPF", "RD")
+
+	// obligationTokens are the words of an EMI description besides the lender.
+	obligationTokens = toSet("EMI", "LOAN", "LN", "SI", "DR", "DEBIT", "DEBITED", "MANDATE", "D", "AC", "ACCT", "REPAYMENT", "INSTALMENT", "INSTALLMENT")
+)
+
+// Obligations finds the EMIs debited from the statements, grouped by lender,
+// and relates their monthly total to monthlyIncome (the average recurring
+// income; 0 when unknown). Returns nil when there are none.
+func Obligations(stmts []dto.BankStatementData, monthlyIncome float64) *dto.DebtObligations {
+	groups := map[string][]dto.BankTransaction{}
+	modes := map[string]string{}
+	for _, tx := range uniqueTransactions(stmts, false) {
+		lender, mode := emiDebit(tx.Description)
+		if lender == "" {
+			continue
+		}
+		groups[lender] = append(groups[lender], tx)
+		if modes[lender] == "" || mode == "EMI" {
+			modes[lender] = mode
+		}
+	}
+	if len(groups) == 0 {
+		return nil
+	}
+
+	lenders := make([]string, 0, len(groups))
+	for l := range groups {
+		lenders = append(lenders, l)
+	}
+	sort.Strings(lenders)
+
+	out := &dto.DebtObligations{Obligations: []dto.Obligation{}}
+	for _, lender := range lenders {
+		run := monthlyRun(groups[lender])
+		months := distinctMonths(run)
+		if months == 0 {
+			continue
+		}
+		o := dto.Obligation{Lender: lender, Mode: modes[lender], Recurring: months >= 2}
+		var sum float64
+		seen := map[string]bool{}
+		for _, tx := range run {
+			sum += tx.Amount
+			if m := monthOf(tx.Date); !seen[m] {
+				seen[m] = true
+				o.Months = append(o.Months, m)
+			}
+		}
+		o.MonthlyAmount = round2(sum / float64(months))
+		out.Obligations = append(out.Obligations, o)
+		out.TotalMonthlyObligation += o.MonthlyAmount
+	}
+
+	out.TotalMonthlyObligation = round2(out.TotalMonthlyObligation)
+	if monthlyIncome > 0 {
+		out.FOIR = round2(out.TotalMonthlyObligation / monthlyIncome)
+	}
+	return out
+}
+
+// emiDebit returns the lender and payment mode of a debit that repays a loan,
+// e.g. "NACH-DR-BAJAJ FINANCE LTD-EMI 0412" → "BAJAJ FINANCE LTD", "EMI";
+// empty for other debits.
+func emiDebit(description string) (lender, mode string) {
+	d := strings.ToUpper(description)
+	modes := emiRe.FindAllString(d, -1)
+	// a returned EMI and its bounce charge are counted by Bounces
+	if len(modes) == 0 || returnRe.MatchString(d) || chargeRe.MatchString(d) {
+		return "", ""
+	}
+	mode = modes[0]
+	for _, m := range modes {
+		if m == "EMI" {
+			mode = m
+		}
+	}
+	switch mode {
+	case "ACH":
+		mode = "NACH"
+	case "LN":
+		mode = "LOAN"
+	}
+
+	var out []string
+	for _, tok := range tokenSplit.Split(d, -1) {
+		if investmentTokens[tok] {
+			return "", ""
+		}
+		if len(tok) < 2 || hasDigit.MatchString(tok) || ignoredTokens[tok] || obligationTokens[tok] || len(out) == 3 {
+			continue
+		}
+		out = append(out, tok)
+	}
+	if len(out) == 0 {
+		// "EMI 12/36" with no lender named: still an obligation
+		out = []string{"UNKNOWN LENDER"}
+	}
+	return strings.Join(out, " "), mode
+}
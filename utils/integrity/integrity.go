@@ -0,0 +1,200 @@
+// Package integrity looks for signs that a bank statement was fabricated or
+// edited: running balances that do not add up, cloned transaction rows,
+// suspiciously round salary credits, PDF font anomalies and a PDF history of
+// editing (provenance).
+package integrity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/incomeanalysis"
+)
+
+const (
+	// balanceTolerance absorbs OCR rounding of amounts (in rupees).
+	balanceTolerance = 1.0
+	// roundSalaryUnit: net pay after PF, tax and professional tax is almost never
+	// a whole number of thousands.
+	roundSalaryUnit = 1000.0
+	// minFontPages is how many pages a statement needs before a font used on a
+	// single page stands out.
+	minFontPages = 3
+	// maxModifiedAfter is how long after creation a generated statement may
+	// still be written to.
+	maxModifiedAfter = time.Hour
+)
+
+// CheckStatement runs the checks that need only the parsed transactions.
+func CheckStatement(stmt dto.BankStatementData) []dto.FraudSignal {
+	var signals []dto.FraudSignal
+	signals = append(signals, checkBalances(stmt.Transactions)...)
+	signals = append(signals, checkDuplicates(stmt.Transactions)...)
+	signals = append(signals, checkRoundSalary(stmt.Transactions)...)
+	return signals
+}
+
+// CheckFonts runs the font checks on a text PDF; meta may be nil.
+func CheckFonts(meta *dto.PDFMetadata) []dto.FraudSignal {
+	if meta == nil {
+		return nil
+	}
+	return checkFonts(meta.PageFonts)
+}
+
+// checkBalances verifies that each balance follows from the previous one and
+// the row's amount. Statements list transactions oldest or newest first; the
+// order under which more rows reconcile is taken.
+func checkBalances(txs []dto.BankTransaction) []dto.FraudSignal {
+	var rows []dto.BankTransaction
+	for _, tx := range txs {
+		if tx.Balance != 0 {
+			rows = append(rows, tx)
+		}
+	}
+	if len(rows) < 2 {
+		return nil
+	}
+
+	ascending := mismatches(rows, func(prev, cur dto.BankTransaction) float64 { return prev.Balance + signed(cur) })
+	// newest first: the row above is the later one, so it carries the change
+	descending := mismatches(rows, func(prev, cur dto.BankTransaction) float64 { return prev.Balance - signed(prev) })
+	broken := ascending
+	if len(descending) < len(ascending) {
+		broken = descending
+	}
+
+	var signals []dto.FraudSignal
+	for _, i := range broken {
+		signals = append(signals, dto.FraudSignal{
+			Type: dto.FraudBalanceMismatch,
+			Detail: fmt.Sprintf("%s %q: balance %.2f does not reconcile with %.2f on the previous row",
+				rows[i].Date.Format("2006-01-02"), rows[i].Description, rows[i].Balance, rows[i-1].Balance),
+		})
+	}
+	return signals
+}
+
+// mismatches returns the indexes of rows whose balance differs from the one
+// expected from the row before.
+func mismatches(rows []dto.BankTransaction, expected func(prev, cur dto.BankTransaction) float64) []int {
+	var out []int
+	for i := 1; i < len(rows); i++ {
+		if math.Abs(expected(rows[i-1], rows[i])-rows[i].Balance) > balanceTolerance {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func signed(tx dto.BankTransaction) float64 {
+	if tx.IsCredit {
+		return tx.Amount
+	}
+	return -tx.Amount
+}
+
+// checkDuplicates flags rows identical in date, description, amount and
+// balance. Two genuine payments of the same amount on the same day still
+// leave different balances; a copied row does not.
+func checkDuplicates(txs []dto.BankTransaction) []dto.FraudSignal {
+	seen := map[string]bool{}
+	var signals []dto.FraudSignal
+	for _, tx := range txs {
+		desc := strings.Join(strings.Fields(strings.ToUpper(tx.Description)), " ")
+		if desc == "" || tx.Amount == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s|%s|%.2f|%t|%.2f", tx.Date.Format("2006-01-02"), desc, tx.Amount, tx.IsCredit, tx.Balance)
+		if seen[key] {
+			signals = append(signals, dto.FraudSignal{
+				Type:   dto.FraudDuplicateRow,
+				Detail: fmt.Sprintf("%s %q %.2f appears more than once", tx.Date.Format("2006-01-02"), tx.Description, tx.Amount),
+			})
+			continue
+		}
+		seen[key] = true
+	}
+	return signals
+}
+
+// checkRoundSalary flags salary credits that are an exact number of thousands.
+func checkRoundSalary(txs []dto.BankTransaction) []dto.FraudSignal {
+	var signals []dto.FraudSignal
+	for _, tx := range txs {
+		if !tx.IsCredit || tx.Amount < roundSalaryUnit || !incomeanalysis.IsSalary(tx.Description) {
+			continue
+		}
+		if math.Mod(tx.Amount, roundSalaryUnit) == 0 {
+			signals = append(signals, dto.FraudSignal{
+				Type:   dto.FraudRoundedSalary,
+				Detail: fmt.Sprintf("%s salary credit of %.2f is a round amount", tx.Date.Format("2006-01-02"), tx.Amount),
+			})
+		}
+	}
+	return signals
+}
+
+// checkFonts looks for the traces text editors leave in a PDF: the same font
+// embedded again as a second subset on one page (the edited text was set
+// with a fresh subset), and a font that only one inner page of a long
+// statement uses. Cover and summary pages legitimately have fonts of their own.
+func checkFonts(pageFonts [][]string) []dto.FraudSignal {
+	var signals []dto.FraudSignal
+	pagesUsing := map[string][]int{}
+	for i, fonts := range pageFonts {
+		page := i + 1
+		subsets := map[string][]string{}
+		for _, f := range fonts {
+			base := baseFont(f)
+			subsets[base] = append(subsets[base], f)
+			if n := len(pagesUsing[base]); n == 0 || pagesUsing[base][n-1] != page {
+				pagesUsing[base] = append(pagesUsing[base], page)
+			}
+		}
+		for _, base := range sortedKeys(subsets) {
+			if len(subsets[base]) > 1 {
+				signals = append(signals, dto.FraudSignal{
+					Type:   dto.FraudFontAnomaly,
+					Page:   page,
+					Detail: fmt.Sprintf("font %s is embedded %d times (%s)", base, len(subsets[base]), strings.Join(subsets[base], ", ")),
+				})
+			}
+		}
+	}
+
+	if len(pageFonts) < minFontPages {
+		return signals
+	}
+	for _, base := range sortedKeys(pagesUsing) {
+		if pages := pagesUsing[base]; len(pages) == 1 && pages[0] != 1 && pages[0] != len(pageFonts) {
+			signals = append(signals, dto.FraudSignal{
+				Type:   dto.FraudFontAnomaly,
+				Page:   pages[0],
+				Detail: fmt.Sprintf("font %s is used only on page %d of %d", base, pages[0], len(pageFonts)),
+			})
+		}
+	}
+	return signals
+}
+
+// baseFont strips the subset tag from "ABCDEF+Arial-BoldMT".
+func baseFont(name string) string {
+	if tag, base, ok := strings.Cut(name, "+"); ok && len(tag) == 6 {
+		return base
+	}
+	return name
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
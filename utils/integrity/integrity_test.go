@@ -0,0 +1,101 @@
+package integrity
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func day(d int) time.Time {
+	return time.Date(2025, time.March, d, 0, 0, 0, 0, time.UTC)
+}
+
+func types(signals []dto.FraudSignal) []string {
+	out := []string{}
+	for _, s := range signals {
+		out = append(out, s.Type)
+	}
+	return out
+}
+
+func TestCheckStatementCleanStatement(t *testing.T) {
+	stmt := dto.BankStatementData{Transactions: []dto.BankTransaction{
+		{Date: day(1), Description: "NEFT ACME LTD SALARY MAR", Amount: 48312.50, IsCredit: true, Balance: 50312.50},
+		{Date: day(3), Description: "UPI SWIGGY", Amount: 312.50, Balance: 50000},
+		{Date: day(3), Description: "UPI SWIGGY", Amount: 312.50, Balance: 49687.50},
+	}}
+	assert.Empty(t, CheckStatement(stmt))
+}
+
+func TestCheckStatementFlagsTampering(t *testing.T) {
+	// newest first, with an inflated salary credit and a cloned row
+	stmt := dto.BankStatementData{Transactions: []dto.BankTransaction{
+		{Date: day(5), Description: "ATM WDL", Amount: 2000, Balance: 83000},
+		{Date: day(5), Description: "ATM WDL", Amount: 2000, Balance: 83000},
+		{Date: day(1), Description: "NEFT ACME LTD SALARY MAR", Amount: 85000, IsCredit: true, Balance: 85000},
+		{Date: day(1), Description: "OPENING", Amount: 0, Balance: 12000},
+	}}
+	assert.Equal(t, []string{dto.FraudBalanceMismatch, dto.FraudBalanceMismatch, dto.FraudDuplicateRow, dto.FraudRoundedSalary},
+		types(CheckStatement(stmt)))
+}
+
+func TestCheckFonts(t *testing.T) {
+	meta := &dto.PDFMetadata{
+		PageFonts: [][]string{
+			{"AAAAAA+Arial", "AAAAAB+Arial-Bold"},
+			{"AAAAAA+Arial", "BCDEFG+Arial"},
+			{"AAAAAA+Arial", "CCCCCC+Helvetica"},
+			{"AAAAAA+Arial"},
+		},
+	}
+	signals := CheckFonts(meta)
+	assert.Equal(t, []string{dto.FraudFontAnomaly, dto.FraudFontAnomaly}, types(signals))
+	assert.Equal(t, 2, signals[0].Page)
+	assert.Equal(t, 3, signals[1].Page)
+	assert.Nil(t, CheckFonts(nil))
+}
+
+func TestInspectMetadata(t *testing.T) {
+	created := day(31)
+	modified := created.Add(48 * time.Hour)
+	meta := &dto.PDFMetadata{Producer: "iLovePDF", Creator: "Finacle Statement Generator", CreationDate: &created, ModDate: &modified}
+	pdf := []byte("%PDF-1.7\n...%%EOF\n...xref\n%%EOF\n")
+
+	p := InspectMetadata(meta, pdf)
+	assert.Equal(t, "iLovePDF", p.EditingSoftware)
+	assert.Equal(t, 1, p.IncrementalUpdates)
+	assert.Equal(t, []string{dto.ProvenanceEditingSoftware, dto.ProvenanceModifiedLater, dto.ProvenanceIncrementalSaves}, p.Findings)
+	assert.Equal(t, 100, p.TamperRisk)
+	assert.Len(t, ProvenanceSignals(p), 3)
+
+	clean := InspectMetadata(&dto.PDFMetadata{Producer: "iText 5.5.13", CreationDate: &created, ModDate: &created}, []byte("%PDF-1.4\n%%EOF\n"))
+	assert.Empty(t, clean.Findings)
+	assert.Zero(t, clean.TamperRisk)
+}
+
+func TestApplySignatures(t *testing.T) {
+	created := day(31)
+	modified := created.Add(48 * time.Hour)
+	meta := &dto.PDFMetadata{Producer: "iText", CreationDate: &created, ModDate: &modified}
+	signed := []byte("%PDF-1.7\n%%EOF\n%%EOF\n")
+
+	p := InspectMetadata(meta, signed)
+	ApplySignatures(p, []dto.DocumentSignature{{SignerName: "HDFC BANK LTD", Validity: dto.SignatureValid}})
+	assert.True(t, p.SignatureVerified)
+	assert.Empty(t, p.Findings)
+	assert.Zero(t, p.TamperRisk)
+
+	p = InspectMetadata(meta, signed)
+	ApplySignatures(p, []dto.DocumentSignature{{SignerName: "HDFC BANK LTD", Validity: dto.SignatureUnknown}})
+	assert.False(t, p.SignatureVerified)
+	assert.Equal(t, []string{dto.ProvenanceModifiedLater}, p.Findings)
+
+	p = InspectMetadata(meta, signed)
+	ApplySignatures(p, []dto.DocumentSignature{{SignerName: "HDFC BANK LTD", Validity: dto.SignatureInvalid, Reason: "document has been modified"}})
+	assert.False(t, p.SignatureVerified)
+	assert.Contains(t, p.Findings, dto.ProvenanceInvalidSignature)
+	assert.Equal(t, 100, p.TamperRisk)
+}
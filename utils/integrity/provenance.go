@@ -0,0 +1,194 @@
+package integrity
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// editors are PDF editors and online tools; a statement or payslip last
+// written by one of them was opened and saved again after the issuer made it.
+var editors = []struct{ match, name string }{
+	{"ilovepdf", "iLovePDF"},
+	{"smallpdf", "Smallpdf"},
+	{"sejda", "Sejda"},
+	{"pdfescape", "PDFescape"},
+	{"pdf-xchange editor", "PDF-XChange Editor"},
+	{"phantompdf", "Foxit PhantomPDF"},
+	{"foxit pdf editor", "Foxit PDF Editor"},
+	{"nitro", "Nitro PDF"},
+	{"pdfelement", "PDFelement"},
+	{"soda pdf", "Soda PDF"},
+	{"pdffiller", "pdfFiller"},
+	{"adobe acrobat pro", "Adobe Acrobat Pro"},
+	{"photoshop", "Adobe Photoshop"},
+	{"canva", "Canva"},
+}
+
+// wordProcessors regenerate a document from an editable copy; banks and
+// payroll systems do not issue statements from them.
+var wordProcessors = []struct{ match, name string }{
+	{"microsoft word", "Microsoft Word"},
+	{"microsoft® word", "Microsoft Word"},
+	{"microsoft excel", "Microsoft Excel"},
+	{"microsoft® excel", "Microsoft Excel"},
+	{"libreoffice", "LibreOffice"},
+	{"openoffice", "OpenOffice"},
+	{"google docs", "Google Docs"},
+	{"wps office", "WPS Office"},
+}
+
+// findingRisk is each finding's contribution to the tamper risk.
+var findingRisk = map[string]int{
+	dto.ProvenanceEditingSoftware:  60,
+	dto.ProvenanceRegenerated:      40,
+	dto.ProvenanceModifiedLater:    30,
+	dto.ProvenanceIncrementalSaves: 30,
+	dto.ProvenanceInvalidSignature: 80,
+}
+
+// InspectMetadata reads a PDF's history from its metadata and raw bytes:
+// the software that wrote it, whether it was modified after creation and how
+// many incremental updates were appended.
+func InspectMetadata(meta *dto.PDFMetadata, pdfData []byte) *dto.DocumentProvenance {
+	p := &dto.DocumentProvenance{
+		Producer:     meta.Producer,
+		Creator:      meta.Creator,
+		CreationDate: meta.CreationDate,
+		ModDate:      meta.ModDate,
+		Findings:     []string{},
+		Metadata:     meta,
+	}
+
+	software := strings.ToLower(meta.Producer + " " + meta.Creator)
+	if name := lookup(editors, software); name != "" {
+		p.EditingSoftware = name
+		p.Findings = append(p.Findings, dto.ProvenanceEditingSoftware)
+	} else if name := lookup(wordProcessors, software); name != "" {
+		p.EditingSoftware = name
+		p.Findings = append(p.Findings, dto.ProvenanceRegenerated)
+	}
+
+	if meta.CreationDate != nil && meta.ModDate != nil && meta.ModDate.Sub(*meta.CreationDate) > maxModifiedAfter {
+		p.Findings = append(p.Findings, dto.ProvenanceModifiedLater)
+	}
+
+	p.IncrementalUpdates = incrementalUpdates(pdfData)
+	if p.IncrementalUpdates > 0 {
+		p.Findings = append(p.Findings, dto.ProvenanceIncrementalSaves)
+	}
+
+	p.TamperRisk = risk(p.Findings)
+	return p
+}
+
+// ApplySignatures records the PDF's digital signatures on p. A valid signature
+// with nothing appended after it vouches for the whole file, so the metadata
+// findings no longer apply (signing itself is an incremental update); an
+// invalid one is a finding of its own.
+func ApplySignatures(p *dto.DocumentProvenance, sigs []dto.DocumentSignature) {
+	p.Signatures = sigs
+	invalid := false
+	for _, sig := range sigs {
+		switch {
+		case sig.Validity == dto.SignatureInvalid:
+			invalid = true
+		case sig.Validity == dto.SignatureValid && !sig.DocModified:
+			p.SignatureVerified = true
+		}
+	}
+
+	// Each signature is normally saved as an update of its own
+	if p.IncrementalUpdates = max(p.IncrementalUpdates-len(sigs), 0); p.IncrementalUpdates == 0 {
+		p.Findings = without(p.Findings, dto.ProvenanceIncrementalSaves)
+	}
+
+	if invalid {
+		p.SignatureVerified = false
+		p.Findings = append(p.Findings, dto.ProvenanceInvalidSignature)
+	} else if p.SignatureVerified {
+		p.Findings = []string{}
+	}
+	p.TamperRisk = risk(p.Findings)
+}
+
+func without(list []string, item string) []string {
+	out := []string{}
+	for _, s := range list {
+		if s != item {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func risk(findings []string) int {
+	r := 0
+	for _, f := range findings {
+		r += findingRisk[f]
+	}
+	return min(r, 100)
+}
+
+// ProvenanceSignals turns provenance findings into fraud signals; p may be nil.
+func ProvenanceSignals(p *dto.DocumentProvenance) []dto.FraudSignal {
+	if p == nil {
+		return nil
+	}
+	var signals []dto.FraudSignal
+	for _, f := range p.Findings {
+		var detail string
+		switch f {
+		case dto.ProvenanceEditingSoftware:
+			detail = "PDF was last saved with " + p.EditingSoftware
+		case dto.ProvenanceRegenerated:
+			detail = "PDF was generated by " + p.EditingSoftware
+		case dto.ProvenanceModifiedLater:
+			detail = fmt.Sprintf("PDF was modified on %s, after creation on %s",
+				p.ModDate.Format("2006-01-02 15:04"), p.CreationDate.Format("2006-01-02 15:04"))
+		case dto.ProvenanceIncrementalSaves:
+			detail = fmt.Sprintf("PDF has %d incremental update(s) appended", p.IncrementalUpdates)
+		case dto.ProvenanceInvalidSignature:
+			detail = "digital signature does not verify"
+			for _, sig := range p.Signatures {
+				if sig.Validity == dto.SignatureInvalid {
+					detail = fmt.Sprintf("digital signature of %s does not verify: %s", sig.SignerName, sig.Reason)
+					break
+				}
+			}
+		default:
+			detail = f
+		}
+		signals = append(signals, dto.FraudSignal{Type: dto.FraudMetadataAnomaly, Detail: detail})
+	}
+	return signals
+}
+
+func lookup(table []struct{ match, name string }, s string) string {
+	for _, e := range table {
+		if strings.Contains(s, e.match) {
+			return e.name
+		}
+	}
+	return ""
+}
+
+// incrementalUpdates counts the revisions appended to a PDF after it was first
+// written: every save ends with %%EOF, and a linearized file (fast web view)
+// has one extra section of its own.
+func incrementalUpdates(pdfData []byte) int {
+	n := bytes.Count(pdfData, []byte("%%EOF")) - 1
+	head := pdfData
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	if bytes.Contains(head, []byte("/Linearized")) {
+		n--
+	}
+	if n < 0 {
+		return 0
+	}
+	return n
+}
@@ -0,0 +1,112 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// itrComputationLabels are the computation sheet's rows for each figure of
+// dto.ITRComposition, most specific first: the detailed schedule ("Income
+// from Salary" heading, its break-up, then "Income chargeable under the head
+// Salaries") must give the head's total, not a line of the break-up.
+var itrComputationLabels = []struct {
+	field  func(*dto.ITRComposition) *float64
+	labels []*regexp.Regexp
+}{
+	{func(c *dto.ITRComposition) *float64 { return &c.Salary }, itrLabels(
+		`chargeable\s+under\s+the\s+head\W*salar`, `income\s+(?:from|under)\s+(?:the\s+head\s+)?\W*salar`, `^salary\s+income`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.HouseProperty }, itrLabels(
+		`chargeable\s+under\s+the\s+head\W*house\s+property`, `income\s+(?:from|under)\s+(?:the\s+head\s+)?\W*house\s+property`, `^house\s+property`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.Business }, itrLabels(
+		`profits?\s+(?:and|&)\s+gains?\s+(?:of|from)\s+business`, `income\s+from\s+business`, `^business\s+income`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.CapitalGains }, itrLabels(
+		`chargeable\s+under\s+the\s+head\W*capital\s+gains?`, `income\s+(?:from|under)\s+(?:the\s+head\s+)?\W*capital\s+gains?`, `^capital\s+gains?\b`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.OtherSources }, itrLabels(
+		`chargeable\s+under\s+the\s+head\W*other\s+sources`, `income\s+(?:from|under)\s+(?:the\s+head\s+)?\W*other\s+sources`, `^other\s+sources`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.GrossTotalIncome }, itrLabels(`gross\s+total\s+income`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.Deduction80C }, itrLabels(`\b80\s*c\b`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.Deduction80D }, itrLabels(`\b80\s*d\b`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.ChapterVIA }, itrLabels(`chapter[\s-]*vi[\s-]*a`)},
+	{func(c *dto.ITRComposition) *float64 { return &c.TaxComputed }, itrLabels(
+		`total\s+tax[\s,]*(?:surcharge\s*(?:and|&)\s*(?:health\s*(?:and|&)\s*)?(?:education\s+)?cess\s*)?(?:payable|liability)`,
+		`gross\s+tax\s+liability`, `tax\s+payable\s+on\s+total\s+income`, `tax\s+on\s+total\s+income`)},
+}
+
+// itrTrailingAmountExpr is the amount closing a row, with a loss written
+// "(-)2,00,000", "-2,00,000" or "(2,00,000)".
+var itrTrailingAmountExpr = regexp.MustCompile(`(\(-\)\s*|-|\()?(\d[\d,]*(?:\.\d{1,2})?)(\))?$`)
+
+func itrLabels(patterns ...string) []*regexp.Regexp {
+	out := make([]*regexp.Regexp, len(patterns))
+	for i, p := range patterns {
+		out[i] = regexp.MustCompile(`(?i)` + p)
+	}
+	return out
+}
+
+// parseITRComposition reads the income under each head, the deductions and
+// the tax computed from the computation sheet or full form printed with a
+// return. It returns nil when the text has none of them, as for an
+// acknowledgement alone.
+func parseITRComposition(lines []string) *dto.ITRComposition {
+	var c dto.ITRComposition
+	found := false
+	for _, row := range itrComputationLabels {
+		if v, ok := itrComputationAmount(lines, row.labels); ok {
+			*row.field(&c) = v
+			found = true
+		}
+	}
+	if !found {
+		return nil
+	}
+	return &c
+}
+
+// itrComputationAmount returns the amount of the first row matching a label,
+// trying labels in order. The amount is at the end of the row or, in OCR
+// that splits columns, alone on the next line.
+func itrComputationAmount(lines []string, labels []*regexp.Regexp) (float64, bool) {
+	for _, label := range labels {
+		for i, line := range lines {
+			loc := label.FindStringIndex(line)
+			if loc == nil {
+				continue
+			}
+			if v, ok := itrTrailingAmount(line[loc[1]:]); ok {
+				return v, true
+			}
+			if i+1 < len(lines) {
+				next := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(lines[i+1]), "₹"))
+				if v, ok := itrTrailingAmount(next); ok && itrTrailingAmountExpr.FindString(next) == next {
+					return v, true
+				}
+			}
+		}
+	}
+	return 0, false
+}
+
+// itrTrailingAmount parses the amount closing s, skipping section references
+// like "17(1)" and single-digit row numbers.
+func itrTrailingAmount(s string) (float64, bool) {
+	m := itrTrailingAmountExpr.FindStringSubmatch(strings.TrimSpace(s))
+	if m == nil {
+		return 0, false
+	}
+	raw := strings.ReplaceAll(m[2], ",", "")
+	if len(raw) < 2 || (m[1] == "(") != (m[3] == ")") {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return 0, false
+	}
+	if m[1] != "" {
+		v = -v
+	}
+	return v, true
+}
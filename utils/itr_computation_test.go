@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestParseITRComposition(t *testing.T) {
+	text := `COMPUTATION OF TOTAL INCOME
+Assessment Year 2024-25   PAN ABCPK1234F
+Income from Salary
+Gross Salary u/s 17(1)            14,00,000
+Less: Standard deduction u/s 16(ia)   50,000
+Income chargeable under the head Salaries   13,50,000
+Income from House Property   (-)2,00,000
+Profits and Gains of Business or Profession
+3,20,000
+Capital Gains   1,10,000.50
+Income from Other Sources   45,000
+Gross Total Income   16,25,000
+Less: Deductions under Chapter VI-A
+80C  Life insurance premium   1,50,000
+80CCD(1B) National Pension System   50,000
+80D  Medical insurance premium   25,000
+Total deductions under Chapter VI-A   2,25,000
+Total Income   14,00,000
+Tax on total income   2,32,500
+Health and Education Cess   9,300
+Total tax payable   2,41,800`
+
+	res := ParseITR(text)
+	assert.Equal(t, &dto.ITRComposition{
+		Salary: 1350000, HouseProperty: -200000, Business: 320000, CapitalGains: 110000.5, OtherSources: 45000,
+		GrossTotalIncome: 1625000, Deduction80C: 150000, Deduction80D: 25000, ChapterVIA: 225000, TaxComputed: 241800,
+	}, res.Composition)
+}
+
+func TestParseITRCompositionAcknowledgementOnly(t *testing.T) {
+	assert.Nil(t, ParseITR("INDIAN INCOME TAX RETURN ACKNOWLEDGEMENT\nTotal Income\n160850\nTaxes Paid\n9500").Composition)
+}
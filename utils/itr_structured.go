@@ -0,0 +1,286 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// StructuredITRConfidence is the confidence of figures read from the e-filing
+// utility's JSON/XML: they are the return as filed, not a reading of it.
+const StructuredITRConfidence = 99.0
+
+// ErrNotITR is returned for JSON or XML that is not a return prepared with
+// the income tax e-filing utility.
+var ErrNotITR = errors.New("not an income tax return from the e-filing utility")
+
+// itrNode is a JSON object, or an XML element with child elements, of a
+// structured return.
+type itrNode = map[string]interface{}
+
+// incomeSections hold the return's income computation, by form: ITR-1,
+// ITR-4 and ITR-2/3/5/6/7 (Part B-TI).
+var incomeSections = []string{"ITR1_IncomeDeductions", "IncomeDeductions", "PartB-TI", "PartB_TI"}
+
+// IsStructuredITR reports whether data is a JSON or XML file rather than a
+// PDF or image, judged by its first character.
+func IsStructuredITR(data []byte) bool {
+	data = bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	return len(data) > 0 && (data[0] == '{' || data[0] == '<')
+}
+
+// ParseStructuredITR reads an income tax return from the JSON (or, for older
+// years, XML) the e-filing utility prepares for upload, any of ITR-1 to
+// ITR-7. Both have the same element names:
+//
+//	{"ITR": {"ITR1": {"Form_ITR1": {"AssessmentYear": "2024"},
+//	  "PersonalInfo": {"PAN": "...", "AssesseeName": {...}},
+//	  "ITR1_IncomeDeductions": {"TotalIncome": 1050000}, ...}}}
+func ParseStructuredITR(data []byte) (dto.ITRResult, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	var root itrNode
+	var err error
+	if trimmed := bytes.TrimLeft(data, " \t\r\n"); len(trimmed) > 0 && trimmed[0] == '<' {
+		root, err = xmlTree(data)
+	} else {
+		err = json.Unmarshal(data, &root)
+	}
+	if err != nil {
+		return dto.ITRResult{}, fmt.Errorf("%w: %v", ErrNotITR, err)
+	}
+
+	itr, _ := root["ITR"].(itrNode)
+	var formName string
+	var form itrNode
+	for _, key := range sortedKeys(itr) {
+		if n, ok := itr[key].(itrNode); ok && strings.HasPrefix(key, "ITR") {
+			formName, form = key, n
+			break
+		}
+	}
+	if form == nil {
+		return dto.ITRResult{}, ErrNotITR
+	}
+
+	res := dto.ITRResult{
+		Form:       "ITR-" + strings.TrimPrefix(formName, "ITR"),
+		Source:     dto.ITRSourceStructured,
+		Confidence: StructuredITRConfidence,
+	}
+	info := itrSection(form, "PersonalInfo")
+	res.PAN = strings.ToUpper(itrString(info, "PAN"))
+	if name := itrSection(info, "AssesseeName"); name != nil {
+		var parts []string
+		for _, key := range []string{"FirstName", "MiddleName", "SurNameOrOrgName"} {
+			if p := itrString(name, key); p != "" {
+				parts = append(parts, p)
+			}
+		}
+		res.Name = strings.Join(parts, " ")
+	}
+	ay := itrString(itrSection(form, "Form_"+formName), "AssessmentYear")
+	if ay == "" {
+		ay = itrString(form, "AssessmentYear")
+	}
+	res.AssessmentYear = assessmentYear(ay)
+
+	income := form
+	for _, section := range incomeSections {
+		if n := itrSection(form, section); n != nil {
+			income = n
+			break
+		}
+	}
+	// the total income of a return is what tax is computed on
+	res.TotalIncome = itrNumber(income, "TotalIncome")
+	res.TaxableIncome = res.TotalIncome
+	res.Composition = structuredITRComposition(form, income)
+	res.TaxPaid = itrNumber(form, "TotalTaxesPaid")
+	res.RefundAmount = itrNumber(form, "RefundDue")
+
+	filed := itrString(itrSection(form, "Verification"), "Date")
+	if filed == "" {
+		filed = itrString(itrSection(form, "CreationInfo"), "JSONCreationDate")
+	}
+	if _, err := time.Parse("2006-01-02", filed); err == nil {
+		res.FilingDate = filed
+	}
+
+	if res.PAN == "" && res.TotalIncome == 0 {
+		return dto.ITRResult{}, fmt.Errorf("%w: %s has no PAN or income", ErrNotITR, res.Form)
+	}
+	return res, nil
+}
+
+// structuredITRComposition reads the heads of income from the form's income
+// computation (their names differ between ITR-1/4 and Part B-TI), and the
+// deductions and tax from the schedules.
+func structuredITRComposition(form, income itrNode) *dto.ITRComposition {
+	c := dto.ITRComposition{
+		Salary:           itrFirstNumber(income, "IncomeFromSal", "Salaries", "TotIncUnderHeadSalaries"),
+		HouseProperty:    itrFirstNumber(income, "TotalIncomeOfHP", "IncomeFromHP"),
+		Business:         itrFirstNumber(income, "IncomeFromBusinessProf", "TotProfBusGain"),
+		CapitalGains:     itrFirstNumber(income, "TotalCapGains"),
+		OtherSources:     itrFirstNumber(income, "IncomeOthSrc", "TotIncFromOS"),
+		GrossTotalIncome: itrFirstNumber(income, "GrossTotIncome", "GrossTotalIncome"),
+	}
+	// the utility's figures, not UsrDeductUndChapVIA as the filer entered them
+	deductions := itrSection(form, "DeductUndChapVIA")
+	c.Deduction80C = itrNumber(deductions, "Section80C")
+	c.Deduction80D = itrNumber(deductions, "Section80D")
+	c.ChapterVIA = itrNumber(deductions, "TotalChapVIADeductions")
+	if c.ChapterVIA == 0 {
+		c.ChapterVIA = itrNumber(income, "DeductionsUnderScheduleVIA")
+	}
+	c.TaxComputed = itrFirstNumber(form, "GrossTaxLiability", "NetTaxLiability", "TotalTaxPayable")
+	if c == (dto.ITRComposition{}) {
+		return nil
+	}
+	return &c
+}
+
+// assessmentYear writes the utility's "2024" as "2024-25", like the
+// acknowledgement does.
+func assessmentYear(ay string) string {
+	if y, err := strconv.Atoi(ay); err == nil && len(ay) == 4 {
+		return fmt.Sprintf("%d-%02d", y, (y+1)%100)
+	}
+	return ay
+}
+
+// itrFind returns the first value under key at any depth of n, searching
+// each object's own keys before its children (in key order, so the result
+// does not depend on map order).
+func itrFind(n interface{}, key string) (interface{}, bool) {
+	switch n := n.(type) {
+	case itrNode:
+		if v, ok := n[key]; ok {
+			return v, true
+		}
+		for _, k := range sortedKeys(n) {
+			if v, ok := itrFind(n[k], key); ok {
+				return v, true
+			}
+		}
+	case []interface{}:
+		for _, item := range n {
+			if v, ok := itrFind(item, key); ok {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// itrSection returns the object under key at any depth of n, or nil.
+func itrSection(n itrNode, key string) itrNode {
+	v, _ := itrFind(n, key)
+	section, _ := v.(itrNode)
+	return section
+}
+
+func itrString(n itrNode, key string) string {
+	v, _ := itrFind(n, key)
+	switch v := v.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	return ""
+}
+
+// itrNumber reads an amount, a JSON number or the text of an XML element.
+func itrNumber(n itrNode, key string) float64 {
+	v, _ := itrFind(n, key)
+	switch v := v.(type) {
+	case float64:
+		return v
+	case string:
+		f, _ := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		return f
+	}
+	return 0
+}
+
+// itrFirstNumber reads the first of keys present in n as an amount.
+func itrFirstNumber(n itrNode, keys ...string) float64 {
+	for _, key := range keys {
+		if _, ok := itrFind(n, key); ok {
+			return itrNumber(n, key)
+		}
+	}
+	return 0
+}
+
+func sortedKeys(n itrNode) []string {
+	keys := make([]string, 0, len(n))
+	for k := range n {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// xmlTree reads an XML document into the shape encoding/json gives the same
+// data: elements by local name (namespaces dropped), leaf elements as their
+// text and repeated elements as a list.
+func xmlTree(data []byte) (itrNode, error) {
+	type element struct {
+		name     string
+		children itrNode
+		text     strings.Builder
+	}
+	root := itrNode{}
+	var stack []*element
+	dec := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		tok, err := dec.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		switch t := tok.(type) {
+		case xml.StartElement:
+			stack = append(stack, &element{name: t.Name.Local, children: itrNode{}})
+		case xml.CharData:
+			if len(stack) > 0 {
+				stack[len(stack)-1].text.Write(t)
+			}
+		case xml.EndElement:
+			e := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			var v interface{} = e.children
+			if len(e.children) == 0 {
+				v = strings.TrimSpace(e.text.String())
+			}
+			parent := root
+			if len(stack) > 0 {
+				parent = stack[len(stack)-1].children
+			}
+			switch prev := parent[e.name].(type) {
+			case nil:
+				parent[e.name] = v
+			case []interface{}:
+				parent[e.name] = append(prev, v)
+			default:
+				parent[e.name] = []interface{}{prev, v}
+			}
+		}
+	}
+	if len(root) == 0 {
+		return nil, errors.New("empty XML document")
+	}
+	return root, nil
+}
@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestParseStructuredITRJSON(t *testing.T) {
+	data := []byte(`{"ITR": {"ITR1": {
+		"CreationInfo": {"SWVersionNo": "1.0", "JSONCreatedBy": "SW20000025", "JSONCreationDate": "2024-07-20"},
+		"Form_ITR1": {"FormName": "ITR-1", "AssessmentYear": "2024", "SchemaVer": "Ver1.0"},
+		"PersonalInfo": {"AssesseeName": {"FirstName": "RAVI", "SurNameOrOrgName": "KUMAR"}, "PAN": "abcpk1234f", "DOB": "1990-08-14"},
+		"ITR1_IncomeDeductions": {"GrossSalary": 1400000, "IncomeFromSal": 1350000, "TotalIncomeOfHP": -150000, "IncomeOthSrc": 50000,
+			"GrossTotIncome": 1250000, "UsrDeductUndChapVIA": {"Section80C": 180000, "TotalChapVIADeductions": 180000},
+			"DeductUndChapVIA": {"Section80C": 150000, "Section80D": 50000, "TotalChapVIADeductions": 200000}, "TotalIncome": 1050000},
+		"ITR1_TaxComputation": {"TotalTaxPayable": 127500, "EducationCess": 5100, "GrossTaxLiability": 132600},
+		"TaxPaid": {"TaxesPaid": {"TDS": 95000, "TotalTaxesPaid": 95000}, "BalTaxPayable": 0},
+		"Refund": {"RefundDue": 1200},
+		"Verification": {"Place": "BENGALURU", "Date": "2024-07-22"}
+	}}}`)
+	require.True(t, IsStructuredITR(data))
+
+	res, err := ParseStructuredITR(data)
+	require.NoError(t, err)
+	assert.Equal(t, dto.ITRResult{
+		PAN: "ABCPK1234F", Name: "RAVI KUMAR", AssessmentYear: "2024-25", Form: "ITR-1",
+		TotalIncome: 1050000, TaxableIncome: 1050000, TaxPaid: 95000, RefundAmount: 1200, FilingDate: "2024-07-22",
+		Source: dto.ITRSourceStructured, Confidence: StructuredITRConfidence,
+		Composition: &dto.ITRComposition{
+			Salary: 1350000, HouseProperty: -150000, OtherSources: 50000, GrossTotalIncome: 1250000,
+			Deduction80C: 150000, Deduction80D: 50000, ChapterVIA: 200000, TaxComputed: 132600,
+		},
+	}, res)
+}
+
+func TestParseStructuredITRXML(t *testing.T) {
+	data := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<ITRETURN:ITR xmlns:ITRETURN="http://incometaxindiaefiling.gov.in/main" xmlns:ITR2FORM="http://incometaxindiaefiling.gov.in/ITR2" xmlns:ITRForm="http://incometaxindiaefiling.gov.in/master">
+ <ITR2FORM:ITR2>
+  <ITRForm:CreationInfo><ITRForm:JSONCreationDate>2019-08-30</ITRForm:JSONCreationDate></ITRForm:CreationInfo>
+  <ITRForm:Form_ITR2><ITRForm:AssessmentYear>2019</ITRForm:AssessmentYear></ITRForm:Form_ITR2>
+  <ITRForm:PartA_GEN1>
+   <ITRForm:PersonalInfo>
+    <ITRForm:AssesseeName><ITRForm:FirstName>ANITA</ITRForm:FirstName><ITRForm:MiddleName>R</ITRForm:MiddleName><ITRForm:SurNameOrOrgName>SHARMA</ITRForm:SurNameOrOrgName></ITRForm:AssesseeName>
+    <ITRForm:PAN>BNZPS1234K</ITRForm:PAN>
+   </ITRForm:PersonalInfo>
+  </ITRForm:PartA_GEN1>
+  <ITRForm:ScheduleS><ITRForm:TotalIncome>900000</ITRForm:TotalIncome></ITRForm:ScheduleS>
+  <ITRForm:PartB-TI><ITRForm:Salaries>900000</ITRForm:Salaries><ITRForm:CapGain><ITRForm:ShortTerm><ITRForm:TotalShortTerm>250000</ITRForm:TotalShortTerm></ITRForm:ShortTerm><ITRForm:TotalCapGains>1250000</ITRForm:TotalCapGains></ITRForm:CapGain><ITRForm:GrossTotalIncome>2150000</ITRForm:GrossTotalIncome><ITRForm:DeductionsUnderScheduleVIA>150000</ITRForm:DeductionsUnderScheduleVIA><ITRForm:TotalIncome>2000000</ITRForm:TotalIncome></ITRForm:PartB-TI>
+  <ITRForm:PartB_TTI><ITRForm:TaxPaid><ITRForm:TaxesPaid><ITRForm:TotalTaxesPaid>420000</ITRForm:TotalTaxesPaid></ITRForm:TaxesPaid></ITRForm:TaxPaid></ITRForm:PartB_TTI>
+ </ITR2FORM:ITR2>
+</ITRETURN:ITR>`)
+	require.True(t, IsStructuredITR(data))
+
+	res, err := ParseStructuredITR(data)
+	require.NoError(t, err)
+	assert.Equal(t, "BNZPS1234K", res.PAN)
+	assert.Equal(t, "ANITA R SHARMA", res.Name)
+	assert.Equal(t, "2019-20", res.AssessmentYear)
+	assert.Equal(t, "ITR-2", res.Form)
+	assert.Equal(t, 2000000.0, res.TotalIncome, "Part B-TI, not a schedule's total")
+	assert.Equal(t, 420000.0, res.TaxPaid)
+	assert.Equal(t, "2019-08-30", res.FilingDate, "no verification date: when the file was made")
+	assert.Equal(t, &dto.ITRComposition{Salary: 900000, CapitalGains: 1250000, GrossTotalIncome: 2150000, ChapterVIA: 150000}, res.Composition)
+}
+
+func TestParseStructuredITRRejectsOtherFiles(t *testing.T) {
+	assert.False(t, IsStructuredITR([]byte("%PDF-1.7")))
+	for _, data := range []string{`{"invoice": {"total": 10}}`, `{"ITR": {"ITR1": {}}}`, `<html><body>ITR</body></html>`, `{"ITR": `} {
+		_, err := ParseStructuredITR([]byte(data))
+		assert.ErrorIs(t, err, ErrNotITR, data)
+	}
+}
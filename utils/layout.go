@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var layoutAmountRe = regexp.MustCompile(`[0-9][0-9,]*(?:\.\d{1,2})?`)
+
+// LayoutLines groups words into visual lines: words on the same page whose
+// boxes overlap vertically by at least half the smaller height share a line.
+// Lines are ordered top to bottom per page, words left to right.
+func LayoutLines(words []dto.OCRWord) [][]dto.OCRWord {
+	sorted := append([]dto.OCRWord(nil), words...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Page != sorted[j].Page {
+			return sorted[i].Page < sorted[j].Page
+		}
+		return sorted[i].Box.Y0 < sorted[j].Box.Y0
+	})
+
+	var lines [][]dto.OCRWord
+	for _, w := range sorted {
+		placed := false
+		for i := len(lines) - 1; i >= 0 && !placed; i-- {
+			if lines[i][0].Page == w.Page && sameLine(lines[i][0].Box, w.Box) {
+				lines[i] = append(lines[i], w)
+				placed = true
+			}
+		}
+		if !placed {
+			lines = append(lines, []dto.OCRWord{w})
+		}
+	}
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool { return line[i].Box.X0 < line[j].Box.X0 })
+	}
+	return lines
+}
+
+func sameLine(a, b dto.BoundingBox) bool {
+	top, bottom := a.Y0, a.Y1
+	if b.Y0 > top {
+		top = b.Y0
+	}
+	if b.Y1 < bottom {
+		bottom = b.Y1
+	}
+	smaller := a.Y1 - a.Y0
+	if h := b.Y1 - b.Y0; h < smaller {
+		smaller = h
+	}
+	return smaller > 0 && (bottom-top)*2 >= smaller
+}
+
+// ValueRightOf finds label (one or more words, case and punctuation
+// insensitive) on a line and returns the words to its right on that line,
+// e.g. "Net Pay :  45,200.00" → "45,200.00" even when the value sits in a
+// separate column that plain OCR text put on another line.
+func ValueRightOf(words []dto.OCRWord, label string) string {
+	want := strings.Fields(normalizeLayoutWord(label))
+	if len(want) == 0 {
+		return ""
+	}
+
+	for _, line := range LayoutLines(words) {
+		for start := 0; start+len(want) <= len(line); start++ {
+			match := true
+			for k, lw := range want {
+				if normalizeLayoutWord(line[start+k].Text) != lw {
+					match = false
+					break
+				}
+			}
+			if !match {
+				continue
+			}
+
+			var value []string
+			for _, w := range line[start+len(want):] {
+				if t := strings.Trim(w.Text, ":-"); t != "" {
+					value = append(value, t)
+				}
+			}
+			if len(value) > 0 {
+				return strings.Join(value, " ")
+			}
+		}
+	}
+	return ""
+}
+
+func normalizeLayoutWord(s string) string {
+	return strings.ToLower(strings.Trim(s, ":.-()"))
+}
+
+// RefineSalarySlipWithLayout fills net salary and employee name that the
+// line-order parser missed from label → value pairs in the word layout.
+func RefineSalarySlipWithLayout(data *dto.SalarySlipData, words []dto.OCRWord) {
+	if len(words) == 0 {
+		return
+	}
+	if data.NetSalary == 0 {
+		for _, label := range []string{"net pay", "net salary", "net amount payable", "take home"} {
+			if m := layoutAmountRe.FindString(ValueRightOf(words, label)); m != "" {
+				if amount, err := strconv.ParseFloat(strings.ReplaceAll(m, ",", ""), 64); err == nil && amount > 0 {
+					data.NetSalary = amount
+					break
+				}
+			}
+		}
+	}
+	if data.EmployeeName == "" {
+		for _, label := range []string{"employee name", "name of employee", "emp name"} {
+			if v := ValueRightOf(words, label); v != "" {
+				data.EmployeeName = v
+				break
+			}
+		}
+	}
+}
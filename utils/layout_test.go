@@ -0,0 +1,32 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func word(text string, x0, y0, x1, y1 int) dto.OCRWord {
+	return dto.OCRWord{Text: text, Page: 1, Box: dto.BoundingBox{X0: x0, Y0: y0, X1: x1, Y1: y1}}
+}
+
+func TestRefineSalarySlipWithLayout(t *testing.T) {
+	// Two-column slip: labels on the left, values in a right-hand column
+	// printed slightly lower, which plain OCR text splits onto separate lines.
+	words := []dto.OCRWord{
+		word("Employee", 10, 100, 90, 120), word("Name", 95, 101, 140, 121),
+		word("Net", 10, 300, 40, 320), word("Pay", 45, 300, 80, 320),
+		word("Rs.", 400, 304, 430, 324), word("45,200.00", 435, 305, 520, 325),
+		word("ANITA", 400, 104, 460, 124), word("RAO", 465, 103, 500, 123),
+		word("Deductions", 10, 200, 120, 220), word("1,800.00", 435, 201, 520, 221),
+	}
+
+	var data dto.SalarySlipData
+	RefineSalarySlipWithLayout(&data, words)
+	assert.Equal(t, 45200.0, data.NetSalary)
+	assert.Equal(t, "ANITA RAO", data.EmployeeName)
+
+	assert.Equal(t, "1,800.00", ValueRightOf(words, "deductions"))
+	assert.Empty(t, ValueRightOf(words, "gross pay"))
+}
@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// micrSymbols blanks out the E-13B control symbols, both as Unicode (transit
+// ⑆, amount ⑇, on-us ⑈, dash ⑉) and as Tesseract's e13b model reads them (A
+// to D), and repairs the digits a general-purpose model confuses with letters.
+var micrSymbols = strings.NewReplacer(
+	"⑆", " ", "⑇", " ", "⑈", " ", "⑉", " ",
+	"A", " ", "B", " ", "C", " ", "D", " ",
+	"O", "0", "o", "0", "I", "1", "l", "1", "|", " ",
+)
+
+var (
+	micrDigitRuns = regexp.MustCompile(`\d+`)
+	micrLetters   = regexp.MustCompile(`\pL`)
+)
+
+// micrFields are the digit counts of the code line: cheque number, MICR code,
+// base account number and transaction code.
+var micrFields = []int{6, 9, 6, 2}
+
+// ParseMICR decodes the code line of a CTS-2010 cheque, e.g. "⑈004512⑈
+// 400240015⑆ 123456⑈ 31" or, from the e13b model, "C004512C 400240015A
+// 123456C 31". The base account number and transaction code are optional;
+// ok is false when no cheque number followed by a MICR code is found. When
+// the symbols were lost and the digits of a line holding nothing else ran
+// together, the fields are cut by their widths.
+func ParseMICR(text string) (dto.MICRLine, bool) {
+	for _, line := range splitAndTrimLines(text) {
+		clean := micrSymbols.Replace(line)
+		runs := micrDigitRuns.FindAllString(clean, -1)
+		if fields := micrGroups(runs); fields != nil {
+			return micrLine(line, fields), true
+		}
+		if micrLetters.MatchString(clean) {
+			continue // text, not a code line
+		}
+		if fields := micrSplit(strings.Join(runs, "")); fields != nil {
+			return micrLine(line, fields), true
+		}
+	}
+	return dto.MICRLine{}, false
+}
+
+// micrGroups finds a 6-digit run followed by a 9-digit run and takes up to
+// two more runs of the expected widths after them.
+func micrGroups(runs []string) []string {
+	for i := 0; i+1 < len(runs); i++ {
+		if len(runs[i]) != micrFields[0] || len(runs[i+1]) != micrFields[1] {
+			continue
+		}
+		fields := []string{runs[i], runs[i+1]}
+		for j, width := range micrFields[2:] {
+			if k := i + 2 + j; k >= len(runs) || len(runs[k]) != width {
+				break
+			}
+			fields = append(fields, runs[i+2+j])
+		}
+		return fields
+	}
+	return nil
+}
+
+// micrSplit cuts digits that ran together into the code line's fields.
+func micrSplit(digits string) []string {
+	if n := len(digits); n != 15 && n != 21 && n != 23 {
+		return nil
+	}
+	var fields []string
+	for _, width := range micrFields {
+		if len(digits) < width {
+			break
+		}
+		fields = append(fields, digits[:width])
+		digits = digits[width:]
+	}
+	return fields
+}
+
+func micrLine(raw string, fields []string) dto.MICRLine {
+	m := dto.MICRLine{
+		ChequeNumber: fields[0],
+		MICRCode:     fields[1],
+		CityCode:     fields[1][:3],
+		BankCode:     fields[1][3:6],
+		BranchCode:   fields[1][6:],
+		Raw:          raw,
+	}
+	if len(fields) > 2 {
+		m.BaseAccount = fields[2]
+	}
+	if len(fields) > 3 {
+		m.TransactionCode = fields[3]
+	}
+	return m
+}
@@ -0,0 +1,157 @@
+// Package mrz decodes the TD3 machine readable zone printed at the bottom of
+// passport data pages (ICAO 9303 part 4): two lines of 44 characters.
+package mrz
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const lineLen = 44
+
+// Result is a decoded passport MRZ. Dates are DD/MM/YYYY.
+type Result struct {
+	DocumentType   string
+	IssuingCountry string
+	Surname        string
+	GivenNames     string
+	PassportNumber string
+	Nationality    string
+	DateOfBirth    string
+	Sex            string
+	ExpiryDate     string
+	PersonalNumber string
+	// FailedChecks names the check digits that did not match; empty when the MRZ is valid.
+	FailedChecks []string
+}
+
+// Valid reports whether every check digit matched.
+func (r *Result) Valid() bool {
+	return len(r.FailedChecks) == 0
+}
+
+var (
+	line1Re = regexp.MustCompile(`^P[A-Z0-9<][A-Z<]{3}[A-Z0-9<]{30,41}$`)
+	line2Re = regexp.MustCompile(`^[A-Z0-9<]{40,46}$`)
+
+	// OCR reads the filler as « or spaces out runs of it.
+	fillerFix = strings.NewReplacer("«", "<<", " ", "", "‹", "<", "(", "<", "{", "<")
+	// Numeric positions: letters OCR commonly confuses with digits.
+	digitFix = strings.NewReplacer("O", "0", "Q", "0", "D", "0", "I", "1", "L", "1", "Z", "2", "S", "5", "B", "8", "G", "6", "<", "0")
+)
+
+// Find locates the two MRZ lines in OCR text.
+func Find(text string) (string, string, bool) {
+	var lines []string
+	for _, l := range strings.Split(text, "\n") {
+		l = fillerFix.Replace(strings.ToUpper(strings.TrimSpace(l)))
+		if l != "" {
+			lines = append(lines, l)
+		}
+	}
+
+	for i := 0; i+1 < len(lines); i++ {
+		if line1Re.MatchString(lines[i]) && strings.Contains(lines[i], "<<") && line2Re.MatchString(lines[i+1]) {
+			return fit(lines[i]), fit(lines[i+1]), true
+		}
+	}
+	return "", "", false
+}
+
+// fit pads or truncates an OCR'd line to the TD3 length.
+func fit(line string) string {
+	if len(line) >= lineLen {
+		return line[:lineLen]
+	}
+	return line + strings.Repeat("<", lineLen-len(line))
+}
+
+// Parse decodes a TD3 MRZ. Check digit failures are reported in
+// Result.FailedChecks rather than as an error so callers can still use the
+// fields that did decode.
+func Parse(line1, line2 string) (*Result, error) {
+	if len(line1) != lineLen || len(line2) != lineLen {
+		return nil, fmt.Errorf("mrz lines must be %d characters", lineLen)
+	}
+	if line1[0] != 'P' {
+		return nil, fmt.Errorf("not a passport MRZ")
+	}
+
+	r := &Result{
+		DocumentType:   strings.TrimRight(line1[0:2], "<"),
+		IssuingCountry: strings.Trim(line1[2:5], "<"),
+		PassportNumber: strings.Trim(line2[0:9], "<"),
+		Nationality:    strings.Trim(line2[10:13], "<"),
+		Sex:            strings.Trim(line2[20:21], "<"),
+		PersonalNumber: strings.Trim(line2[28:42], "<"),
+	}
+
+	names := strings.SplitN(line1[5:], "<<", 2)
+	r.Surname = strings.TrimSpace(strings.ReplaceAll(names[0], "<", " "))
+	if len(names) > 1 {
+		r.GivenNames = strings.Join(strings.Fields(strings.ReplaceAll(names[1], "<", " ")), " ")
+	}
+
+	dob := digitFix.Replace(line2[13:19])
+	expiry := digitFix.Replace(line2[21:27])
+	r.DateOfBirth = formatDate(dob, false)
+	r.ExpiryDate = formatDate(expiry, true)
+
+	type check struct {
+		name  string
+		field string
+		digit byte
+	}
+	checks := []check{
+		{"passport_number", line2[0:9], line2[9]},
+		{"date_of_birth", dob, line2[19]},
+		{"expiry_date", expiry, line2[27]},
+		{"composite", line2[0:10] + dob + line2[19:20] + expiry + line2[27:43], line2[43]},
+	}
+	// The personal number check digit may be < when the field is empty.
+	if r.PersonalNumber != "" || line2[42] != '<' {
+		checks = append(checks, check{"personal_number", line2[28:42], line2[42]})
+	}
+
+	for _, c := range checks {
+		want := digitFix.Replace(string(c.digit))
+		if fmt.Sprint(CheckDigit(c.field)) != want {
+			r.FailedChecks = append(r.FailedChecks, c.name)
+		}
+	}
+	return r, nil
+}
+
+// CheckDigit computes the ICAO 9303 check digit: weights 7,3,1 over digit
+// values, A-Z as 10-35 and the filler as 0.
+func CheckDigit(s string) int {
+	weights := [3]int{7, 3, 1}
+	sum := 0
+	for i, c := range s {
+		v := 0
+		switch {
+		case c >= '0' && c <= '9':
+			v = int(c - '0')
+		case c >= 'A' && c <= 'Z':
+			v = int(c-'A') + 10
+		}
+		sum += v * weights[i%3]
+	}
+	return sum % 10
+}
+
+// formatDate turns YYMMDD into DD/MM/YYYY. Birth years after the current year
+// belong to the previous century; expiry dates are always in this one.
+func formatDate(yymmdd string, expiry bool) string {
+	t, err := time.Parse("060102", yymmdd)
+	if err != nil {
+		return ""
+	}
+	year := 2000 + t.Year()%100
+	if !expiry && year > time.Now().Year() {
+		year -= 100
+	}
+	return fmt.Sprintf("%02d/%02d/%d", t.Day(), int(t.Month()), year)
+}
@@ -0,0 +1,52 @@
+package mrz
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// ICAO 9303 specimen passport.
+const (
+	specimen1 = "P<UTOERIKSSON<<ANNA<MARIA<<<<<<<<<<<<<<<<<<<"
+	specimen2 = "L898902C36UTO7408122F1204159ZE184226B<<<<<10"
+)
+
+func TestParseSpecimen(t *testing.T) {
+	r, err := Parse(specimen1, specimen2)
+	require.NoError(t, err)
+
+	assert.True(t, r.Valid(), r.FailedChecks)
+	assert.Equal(t, "UTO", r.IssuingCountry)
+	assert.Equal(t, "ERIKSSON", r.Surname)
+	assert.Equal(t, "ANNA MARIA", r.GivenNames)
+	assert.Equal(t, "L898902C3", r.PassportNumber)
+	assert.Equal(t, "12/08/1974", r.DateOfBirth)
+	assert.Equal(t, "F", r.Sex)
+	assert.Equal(t, "15/04/2012", r.ExpiryDate)
+	assert.Equal(t, "ZE184226B", r.PersonalNumber)
+}
+
+func TestParseReportsBadCheckDigit(t *testing.T) {
+	r, err := Parse(specimen1, "L898902C37"+specimen2[10:])
+	require.NoError(t, err)
+
+	assert.False(t, r.Valid())
+	assert.Contains(t, r.FailedChecks, "passport_number")
+}
+
+func TestFindInOCRText(t *testing.T) {
+	text := "REPUBLIC OF UTOPIA\nPassport No. L898902C3\n" +
+		"P<UTOERIKSSON<<ANNA<MARIA« <<<<<<<<<<<<<<<<\n" +
+		"L898902C36UTO74O8122F1204159ZE184226B<<<<<10\n"
+
+	l1, l2, ok := Find(text)
+	require.True(t, ok)
+	assert.Equal(t, specimen1, l1)
+
+	r, err := Parse(l1, l2)
+	require.NoError(t, err)
+	assert.True(t, r.Valid(), r.FailedChecks)
+	assert.Equal(t, "12/08/1974", r.DateOfBirth)
+}
@@ -0,0 +1,201 @@
+package utils
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// NameMatchThreshold is the NameMatchScore from which two names are taken to
+// be the same person's. Scores are calibrated so that reordered names,
+// honorifics, initials and spelling variants stay above it while names
+// sharing only one of two words stay below.
+const NameMatchThreshold = 0.8
+
+// nameHonorifics are dropped before comparing (after RomanizeName folding).
+var nameHonorifics = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "mis": true, "mx": true, "master": true,
+	"shri": true, "sri": true, "smt": true, "km": true, "kum": true,
+	"dr": true, "prof": true, "capt": true, "late": true,
+}
+
+// nameAliases map abbreviations and spellings of common name words to one
+// form, so "Md Salim" matches "Mohammed Salim".
+var nameAliases = map[string]string{
+	"md": "mohamed", "mohd": "mohamed", "mohamad": "mohamed", "muhamad": "mohamed",
+	"muhamed": "mohamed", "mohamud": "mohamed",
+	"kr": "kumar", "pd": "prasad", "prashad": "prasad",
+}
+
+// namePhonetics folds spellings of the same sound (sh/s, th/t, z/j, y/i ...)
+// the way Indian names are transliterated inconsistently.
+var namePhonetics = strings.NewReplacer(
+	"sh", "s", "th", "t", "dh", "d", "bh", "b", "kh", "k", "gh", "g", "jh", "j",
+	"ch", "c", "ck", "k", "q", "k", "z", "j", "y", "i",
+)
+
+// NameMatchScore scores how likely two names denote the same person, 0..1.
+// Both names are brought into one script, stripped of honorifics and reduced
+// to phonetic keys; the score then combines a token alignment (tolerating
+// word order, initials and a missing middle name), the token-sort ratio and
+// the Jaro-Winkler similarity of the sorted keys.
+func NameMatchScore(a, b string) float64 {
+	ta, tb := nameTokens(a), nameTokens(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	sa := append([]string(nil), ta...)
+	sb := append([]string(nil), tb...)
+	sort.Strings(sa)
+	sort.Strings(sb)
+	ja, jb := strings.Join(sa, " "), strings.Join(sb, " ")
+
+	score := 0.5*tokenAlignment(ta, tb) + 0.25*levenshteinRatio(ja, jb) + 0.25*jaroWinkler(ja, jb)
+	return math.Min(score, 1)
+}
+
+// NamesMatch reports whether NameMatchScore reaches NameMatchThreshold.
+func NamesMatch(a, b string) bool {
+	return NameMatchScore(a, b) >= NameMatchThreshold
+}
+
+// nameTokens returns the phonetic keys of the words of a name.
+func nameTokens(name string) []string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(".,-_/'", r) {
+			return ' '
+		}
+		return r
+	}, name)
+
+	var tokens []string
+	for _, tok := range strings.Fields(RomanizeName(name)) {
+		if nameHonorifics[tok] {
+			continue
+		}
+		if alias, ok := nameAliases[tok]; ok {
+			tok = alias
+		}
+		tokens = append(tokens, phoneticKey(tok))
+	}
+	return tokens
+}
+
+func phoneticKey(tok string) string {
+	tok = namePhonetics.Replace(tok)
+	// fold letters doubled by the replacements ("ssh" -> "s")
+	var b strings.Builder
+	var prev rune
+	for _, r := range tok {
+		if r != prev {
+			b.WriteRune(r)
+		}
+		prev = r
+	}
+	return b.String()
+}
+
+// tokenAlignment pairs each word of the shorter name with its best unused
+// counterpart in the longer one. Words pair when equal, when one is the
+// other's initial, or when their spelling is close; anything else counts as
+// unmatched. Extra words in the longer name (a middle name or surname one
+// document leaves out) cost a little.
+func tokenAlignment(a, b []string) float64 {
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	used := make([]bool, len(b))
+	var total float64
+	for _, x := range a {
+		best, bestIdx := 0.0, -1
+		for j, y := range b {
+			if used[j] {
+				continue
+			}
+			if s := tokenScore(x, y); s > best {
+				best, bestIdx = s, j
+			}
+		}
+		if bestIdx >= 0 {
+			used[bestIdx] = true
+			total += best
+		}
+	}
+	aligned := total / float64(len(a))
+	return aligned * (0.85 + 0.15*float64(len(a))/float64(len(b)))
+}
+
+func tokenScore(x, y string) float64 {
+	switch {
+	case x == y:
+		return 1
+	case len(x) == 1 || len(y) == 1:
+		if x[0] == y[0] {
+			return 0.9
+		}
+		return 0
+	}
+	if r := levenshteinRatio(x, y); r >= 0.8 {
+		return r
+	}
+	return 0
+}
+
+func levenshteinRatio(a, b string) float64 {
+	la, lb := len([]rune(a)), len([]rune(b))
+	if la == 0 && lb == 0 {
+		return 1
+	}
+	return 1 - float64(levenshteinDistance(a, b))/float64(max(la, lb))
+}
+
+// jaroWinkler is the Jaro similarity of a and b boosted for a common prefix
+// of up to four characters.
+func jaroWinkler(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+
+	window := max(len(ra), len(rb))/2 - 1
+	matchedA := make([]bool, len(ra))
+	matchedB := make([]bool, len(rb))
+	matches := 0
+	for i, r := range ra {
+		lo, hi := max(0, i-window), minimize(len(rb), i+window+1)
+		for j := lo; j < hi; j++ {
+			if !matchedB[j] && rb[j] == r {
+				matchedA[i], matchedB[j] = true, true
+				matches++
+				break
+			}
+		}
+	}
+	if matches == 0 {
+		return 0
+	}
+
+	transpositions, j := 0, 0
+	for i := range ra {
+		if !matchedA[i] {
+			continue
+		}
+		for !matchedB[j] {
+			j++
+		}
+		if ra[i] != rb[j] {
+			transpositions++
+		}
+		j++
+	}
+
+	m := float64(matches)
+	jaro := (m/float64(len(ra)) + m/float64(len(rb)) + (m-float64(transpositions)/2)/m) / 3
+
+	prefix := 0
+	for prefix < min(4, len(ra), len(rb)) && ra[prefix] == rb[prefix] {
+		prefix++
+	}
+	return jaro + float64(prefix)*0.1*(1-jaro)
+}
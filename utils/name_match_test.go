@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNameMatchScore(t *testing.T) {
+	for _, tc := range []struct {
+		a, b  string
+		match bool
+	}{
+		{"Ravi Kumar", "RAVI KUMAR", true},
+		{"Ravi Kumar", "Kumar Ravi", true},
+		{"Ravi Kumar", "Mr. Ravi Kumar", true},
+		{"Ravi Kumar", "Shri Ravi Kumar", true},
+		{"R. Kumar", "Ravi Kumar", true},
+		{"Md Salim Ansari", "Mohammed Salim Ansari", true},
+		{"Mohd. Irfan", "Muhammad Irfan", true},
+		{"Vijay Kr Sharma", "Vijay Kumar Sharma", true},
+		{"Ravi Kumar", "Ravi Kumar Sharma", true},
+		{"Shashank Shrivastava", "Sashank Srivastava", true},
+		{"अजय सिंह", "Ajay Singh", true},
+
+		{"Ram Kumar", "Kumar Ramesh", false},
+		{"John Doe", "Jane Doe", false},
+		{"Anita Sharma", "Ravi Kumar", false},
+		{"S. Kumar", "Ravi Kumar", false},
+		{"", "Ravi Kumar", false},
+	} {
+		score := NameMatchScore(tc.a, tc.b)
+		assert.Equal(t, tc.match, NamesMatch(tc.a, tc.b), "%q vs %q: %.2f", tc.a, tc.b, score)
+		assert.InDelta(t, score, NameMatchScore(tc.b, tc.a), 1e-9, "symmetric")
+		assert.True(t, score >= 0 && score <= 1)
+	}
+	assert.Equal(t, 1.0, NameMatchScore("Ravi Kumar", "Kumar, Ravi"))
+}
@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
@@ -15,13 +16,16 @@ import (
 // =============================
 
 func ParseSalarySlip(ocrText string) dto.SalarySlipData {
-	return dto.SalarySlipData{
+	data := dto.SalarySlipData{
 		PayMonth:      extractMonth(ocrText),
 		NetSalary:     extractSalaryAmount(ocrText),
 		AccountNumber: extractAccountNumber(ocrText),
 		EmployeeName:  extractEmployeeName(ocrText),
 		EmployerName:  extractEmployerName(ocrText),
+		IFSC:          ExtractIFSC(ocrText),
 	}
+	data.BankName = ExtractBankName(ocrText, data.IFSC)
+	return data
 }
 
 // extractEmployerName attempts to detect the company name from salary slips.
@@ -247,37 +251,180 @@ func extractAccountHolderName(text string) string {
 
 func validName(n string) bool { return len(n) > 2 && len(n) < 50 }
 
+// statementAddressStop matches header lines that follow the customer's
+// address block on a statement.
+var statementAddressStop = regexp.MustCompile(`(?i)\b(account|a/c|ifsc|micr|branch|period|statement|customer\s*id|cust\s*id|e-?mail|phone|mobile|nominee|currency|date)\b`)
+
+// extractStatementAddress reads the customer's address printed in the header
+// of a statement: the text after an "Address" label and the lines below it,
+// up to the PIN code line or the next header field.
+func extractStatementAddress(lines []string) string {
+	labelRe := regexp.MustCompile(`(?i)^(?:customer\s*|communication\s*|registered\s*)?address\s*[:\-]?\s*(.*)$`)
+	pinRe := regexp.MustCompile(`\b\d{3}\s?\d{3}\b`)
+
+	for i, line := range lines {
+		m := labelRe.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		var parts []string
+		if first := strings.TrimRight(cleanAddressLine(m[1]), ", "); first != "" {
+			parts = append(parts, first)
+		}
+		for _, next := range lines[i+1:] {
+			if len(parts) >= 5 || pinRe.MatchString(strings.Join(parts, " ")) {
+				break
+			}
+			next = strings.TrimSpace(next)
+			if next == "" || statementAddressStop.MatchString(next) {
+				break
+			}
+			if cl := strings.TrimRight(cleanAddressLine(next), ", "); cl != "" {
+				parts = append(parts, cl)
+			}
+		}
+		return strings.Join(parts, ", ")
+	}
+	return ""
+}
+
 // =============================================
 // 🚀 NEW — FULL BANK STATEMENT PARSER
 // =============================================
 
 func ParseBankStatement(text string) dto.BankStatementData {
 	clean := normalizeLines(text)
+	from, to := extractStatementPeriod(text)
 
-	return dto.BankStatementData{
+	data := dto.BankStatementData{
 		AccountNumber:     extractAccountNumber(text),
 		AccountHolderName: extractAccountHolderName(text),
+		IFSC:              ExtractIFSC(text),
+		PeriodFrom:        from,
+		PeriodTo:          to,
 		Transactions:      parseBankTransactions(clean),
 	}
+	data.Address = extractStatementAddress(strings.Split(text, "\n"))
+	data.BankName = ExtractBankName(text, data.IFSC)
+	return data
+}
+
+// extractStatementPeriod finds headers like
+// "Statement Period: 01/09/2025 to 30/09/2025" or "From 01-09-2025 To 30-09-2025".
+func extractStatementPeriod(text string) (*time.Time, *time.Time) {
+	re := regexp.MustCompile(`(?i)(?:period|from)\s*[:\-]?\s*(?:from\s*)?(\d{1,2}[/-]\d{1,2}[/-]\d{2,4})\s*(?:to|till|-)\s*(\d{1,2}[/-]\d{1,2}[/-]\d{2,4})`)
+	m := re.FindStringSubmatch(text)
+	if len(m) != 3 {
+		return nil, nil
+	}
+
+	from, errFrom := parseDateSmart(m[1])
+	to, errTo := parseDateSmart(m[2])
+	if errFrom != nil || errTo != nil || to.Before(from) {
+		return nil, nil
+	}
+	return &from, &to
+}
+
+// MergePageTexts joins the OCR text of separately photographed statement pages.
+// Page-number lines and headers/footers repeated on every page are dropped
+// (kept once, from the first page), and a transaction row cut by a page break
+// (date on one page, amount on the next) is stitched back into one line.
+func MergePageTexts(pages []string) string {
+	pageNumRe := regexp.MustCompile(`(?i)^page\s*\d+(\s*(of|/)\s*\d+)?$`)
+	dateRe := regexp.MustCompile(`^\s*\d{1,2}[/-]\d{1,2}[/-]\d{2,4}`)
+	amountRe := regexp.MustCompile(`[0-9,]+\.\d{2}`)
+
+	const edge = 8 // lines at the top/bottom of a page considered header/footer
+
+	pageLines := make([][]string, len(pages))
+	seenOn := make(map[string]int) // normalized edge line -> number of pages it appears on
+	for i, p := range pages {
+		var lines []string
+		for _, l := range normalizeLines(p) {
+			if !pageNumRe.MatchString(l) {
+				lines = append(lines, l)
+			}
+		}
+		pageLines[i] = lines
+
+		seen := make(map[string]bool)
+		for j, l := range lines {
+			if j >= edge && j < len(lines)-edge {
+				continue
+			}
+			key := strings.ToLower(strings.Join(strings.Fields(l), " "))
+			if !seen[key] {
+				seen[key] = true
+				seenOn[key]++
+			}
+		}
+	}
+
+	var out []string
+	for i, lines := range pageLines {
+		emitted := false
+		for j, l := range lines {
+			key := strings.ToLower(strings.Join(strings.Fields(l), " "))
+			isEdge := j < edge || j >= len(lines)-edge
+			if i > 0 && isEdge && seenOn[key] > 1 && !dateRe.MatchString(l) {
+				continue
+			}
+
+			// Row split across the page break: previous page ended on a dated line
+			// without an amount and this page starts with an undated line.
+			if i > 0 && !emitted && len(out) > 0 {
+				prev := out[len(out)-1]
+				if dateRe.MatchString(prev) && !amountRe.MatchString(prev) && !dateRe.MatchString(l) {
+					out[len(out)-1] = prev + " " + l
+					emitted = true
+					continue
+				}
+			}
+			out = append(out, l)
+			emitted = true
+		}
+	}
+
+	return strings.Join(out, "\n")
 }
 
 // Main transaction dispatcher
 func parseBankTransactions(lines []string) []dto.BankTransaction {
 	tx := parseTabularTransactions(lines)
-	if len(tx) > 0 {
-		return tx
+	if len(tx) == 0 {
+		tx = parseLooseTransactions(lines)
 	}
-	return parseLooseTransactions(lines)
+	EnrichUPI(tx)
+	return tx
 }
 
 // ----------------------
 // 1. TABULAR FORMAT PARSER
 // ----------------------
 func parseTabularTransactions(lines []string) []dto.BankTransaction {
+	return (&tableParser{}).parse(lines)
+}
+
+// tableParser reads dated statement rows from text. Its state carries across
+// calls, so a StatementStream keeps the column header of page 1 and the running
+// balance while later pages arrive.
+type tableParser struct {
+	cols        []tableColumn // amount columns from the header row, by rune offset
+	prevBalance float64
+	hasBalance  bool
+}
+
+func (p *tableParser) parse(lines []string) []dto.BankTransaction {
 	dateRe := regexp.MustCompile(`^\s*(\d{1,2}[/-]\d{1,2}[/-]\d{2,4})`)
 	var tx []dto.BankTransaction
 
 	for _, line := range lines {
+		tokens, spans := tokenSpans(line)
+		if cols := detectTableColumns(tokens, spans); cols != nil {
+			p.cols = cols
+			continue
+		}
 		if !dateRe.MatchString(line) {
 			continue
 		}
@@ -287,6 +434,18 @@ func parseTabularTransactions(lines []string) []dto.BankTransaction {
 			continue
 		}
 
+		// Trailing amount cells: with a balance column the last one is the balance
+		n := 0
+		for n < len(parts)-2 && tableAmountRe.MatchString(parts[len(parts)-1-n]) {
+			n++
+		}
+		if n >= 2 {
+			if t, ok := p.balanceRow(parts, spans, n); ok {
+				tx = append(tx, t)
+				continue
+			}
+		}
+
 		dateStr := parts[0]
 		amountStr := parts[len(parts)-1]
 		amount := mustParseAmount(amountStr)
@@ -297,23 +456,94 @@ func parseTabularTransactions(lines []string) []dto.BankTransaction {
 		desc := strings.Join(parts[1:len(parts)-1], " ")
 		date, _ := parseDateSmart(dateStr)
 
-		up := strings.ToUpper(desc + " " + amountStr)
-		isCredit := strings.Contains(up, "CR") ||
-			strings.Contains(up, "CREDIT") ||
-			strings.Contains(up, "NEFT") ||
-			strings.Contains(up, "UPI") ||
-			strings.Contains(up, "SALARY")
-
 		tx = append(tx, dto.BankTransaction{
 			Date:        date,
 			Amount:      amount,
 			Description: desc,
-			IsCredit:    isCredit,
+			IsCredit:    creditByKeywords(desc + " " + amountStr),
 		})
 	}
 	return tx
 }
 
+// balanceRow reads a row ending in n amount cells, the last being the balance.
+// Debit vs credit is taken, in order of reliability, from the change in the
+// running balance, the header's column order when every cell is filled, the
+// column the amount sits under, and finally narration keywords.
+func (p *tableParser) balanceRow(parts []string, spans [][2]int, n int) (dto.BankTransaction, bool) {
+	date, err := parseDateSmart(parts[0])
+	if err != nil {
+		return dto.BankTransaction{}, false
+	}
+	cells := parts[len(parts)-n:]
+	balance := mustParseAmount(cells[n-1])
+	desc := strings.Join(parts[1:len(parts)-n], " ")
+
+	// the one non-zero amount besides the balance
+	idx, amount := -1, 0.0
+	for i, c := range cells[:n-1] {
+		if v := mustParseAmount(c); v != 0 {
+			if idx >= 0 {
+				return dto.BankTransaction{}, false // debit and credit both filled: not a row we understand
+			}
+			idx, amount = i, v
+		}
+	}
+	if idx < 0 {
+		return dto.BankTransaction{}, false
+	}
+
+	isCredit := creditByKeywords(desc + " " + cells[idx])
+	switch {
+	case p.hasBalance && math.Abs(p.prevBalance+amount-balance) < 0.01:
+		isCredit = true
+	case p.hasBalance && math.Abs(p.prevBalance-amount-balance) < 0.01:
+		isCredit = false
+	case len(p.cols) == n:
+		isCredit = p.cols[idx].kind == colCredit
+	case len(p.cols) > 0 && len(spans) == len(parts):
+		isCredit = nearestColumn(p.cols, spans[len(parts)-n+idx][1]) == colCredit
+	}
+	p.prevBalance, p.hasBalance = balance, true
+
+	return dto.BankTransaction{
+		Date:        date,
+		Amount:      amount,
+		Description: desc,
+		IsCredit:    isCredit,
+		Balance:     balance,
+	}, true
+}
+
+func creditByKeywords(s string) bool {
+	up := strings.ToUpper(s)
+	return strings.Contains(up, "CR") ||
+		strings.Contains(up, "CREDIT") ||
+		strings.Contains(up, "NEFT") ||
+		strings.Contains(up, "UPI") ||
+		strings.Contains(up, "SALARY")
+}
+
+// tokenSpans splits a line into whitespace-separated tokens with their rune offsets.
+func tokenSpans(line string) ([]string, [][2]int) {
+	var tokens []string
+	var spans [][2]int
+	start := -1
+	runes := []rune(line)
+	for i := 0; i <= len(runes); i++ {
+		space := i == len(runes) || runes[i] == ' ' || runes[i] == '\t'
+		if !space && start < 0 {
+			start = i
+		}
+		if space && start >= 0 {
+			tokens = append(tokens, string(runes[start:i]))
+			spans = append(spans, [2]int{start, i})
+			start = -1
+		}
+	}
+	return tokens, spans
+}
+
 // ----------------------
 // 2. LOOSE FORMAT PARSER
 // ----------------------
@@ -394,55 +624,15 @@ func NormalizeString(s string) string {
 	return s
 }
 
+// CompareNames reports whether two names denote the same person (see
+// NameMatchScore).
 func CompareNames(a, b string) bool {
-	if a == "" || b == "" {
-		return false
-	}
-	a2 := NormalizeString(a)
-	b2 := NormalizeString(b)
-	if a2 == b2 {
-		return true
-	}
-	if strings.Contains(a2, b2) || strings.Contains(b2, a2) {
-		return true
-	}
-
-	wa := strings.Fields(strings.ToLower(a))
-	wb := strings.Fields(strings.ToLower(b))
-	if len(wa) > len(wb) {
-		wa, wb = wb, wa
-	}
-
-	match := 0
-	for _, x := range wa {
-		for _, y := range wb {
-			if strings.Contains(y, x) || strings.Contains(x, y) {
-				match++
-				break
-			}
-		}
-	}
-
-	return float64(match)/float64(len(wa)) >= 0.5
+	return NamesMatch(a, b)
 }
 
+// CalculateNameSimilarity is the NameMatchScore of two names.
 func CalculateNameSimilarity(a, b string) float64 {
-	a2 := NormalizeString(a)
-	b2 := NormalizeString(b)
-
-	if a2 == "" && b2 == "" {
-		return 1.0
-	}
-	if a2 == "" || b2 == "" {
-		return 0.0
-	}
-
-	dist := levenshteinDistance(a2, b2)
-	maxLen := len(a2)
-	if len(b2) > maxLen {
-		maxLen = len(b2)
-	}
-	return 1 - float64(dist)/float64(maxLen)
+	return NameMatchScore(a, b)
 }
 
 func levenshteinDistance(a, b string) int {
@@ -560,6 +750,11 @@ func ParseITR(ocrText string) dto.ITRResult {
 	// -----------------------
 	res.FilingDate = extractITRFilingDate(lines)
 
+	// -----------------------
+	// 8. INCOME COMPOSITION (computation sheet / full form)
+	// -----------------------
+	res.Composition = parseITRComposition(lines)
+
 	return res
 }
 
@@ -1,9 +1,9 @@
 package utils
 
 import (
+	"strings"
 	"testing"
 
-	"github.com/Aashish23092/ocr-income-verification/dto"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -43,6 +43,21 @@ func TestParseBankStatement(t *testing.T) {
 	assert.Equal(t, "SALARY CREDIT", data.Transactions[0].Description)
 }
 
+func TestExtractStatementAddress(t *testing.T) {
+	text := `SBI
+Customer Name: RAVI KUMAR
+Address: 12, MG Road,
+Indiranagar
+Bengaluru 560038
+Account Number: 00000012345678
+IFSC: SBIN0001234`
+	assert.Equal(t, "12, MG Road, Indiranagar, Bengaluru 560038", extractStatementAddress(strings.Split(text, "\n")))
+
+	// the block ends at the next header field when no PIN code is printed
+	assert.Equal(t, "Flat 4B, Lake View", extractStatementAddress([]string{"Address : Flat 4B, Lake View", "Branch: Andheri"}))
+	assert.Empty(t, extractStatementAddress([]string{"Account Holder: John Doe"}))
+}
+
 func TestCompareNames(t *testing.T) {
 	assert.True(t, CompareNames("John Doe", "John Doe"))
 	assert.True(t, CompareNames("John Doe", "MR JOHN DOE"))
@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergePageTexts(t *testing.T) {
+	page1 := `
+		HDFC Bank
+		Date  Narration  Amount
+		01/10/2025  NEFT SALARY ACME  50,000.00
+		05/10/2025  UPI RENT
+		Page 1 of 2
+	`
+	page2 := `
+		HDFC Bank
+		Date  Narration  Amount
+		TO LANDLORD  15,000.00
+		10/10/2025  ATM WDL  2,000.00
+		Page 2 of 2
+	`
+
+	merged := MergePageTexts([]string{page1, page2})
+
+	assert.Equal(t, "HDFC Bank\nDate  Narration  Amount\n"+
+		"01/10/2025  NEFT SALARY ACME  50,000.00\n"+
+		"05/10/2025  UPI RENT TO LANDLORD  15,000.00\n"+
+		"10/10/2025  ATM WDL  2,000.00", merged)
+
+	stmt := ParseBankStatement(merged)
+	assert.Len(t, stmt.Transactions, 3)
+}
+
+func TestStatementStreamMatchesMerge(t *testing.T) {
+	page1 := `
+		HDFC Bank
+		Date  Narration  Amount
+		01/10/2025  NEFT SALARY ACME  50,000.00
+		05/10/2025  UPI RENT
+		Page 1 of 2
+	`
+	page2 := `
+		HDFC Bank
+		Date  Narration  Amount
+		TO LANDLORD  15,000.00
+		10/10/2025  ATM WDL  2,000.00
+		Page 2 of 2
+	`
+
+	stream := NewStatementStream()
+	stream.AddPage(page1)
+	stream.AddPage(page2)
+
+	want := ParseBankStatement(MergePageTexts([]string{page1, page2}))
+	got := stream.Result()
+
+	assert.Equal(t, 2, stream.Pages())
+	assert.Equal(t, want.Transactions, got.Transactions)
+	assert.Equal(t, "UPI RENT TO LANDLORD", got.Transactions[1].Description)
+}
@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+// PAN holder types by the 4th character.
+var panHolderTypes = map[byte]string{
+	'P': "individual",
+	'C': "company",
+	'H': "huf",
+	'F': "firm",
+	'T': "trust",
+	'A': "association_of_persons",
+	'B': "body_of_individuals",
+	'G': "government",
+	'J': "artificial_juridical_person",
+	'L': "local_authority",
+}
+
+var panStructureRe = regexp.MustCompile(`^[A-Z]{5}[0-9]{4}[A-Z]$`)
+
+// PANValidation is the outcome of ValidatePAN.
+type PANValidation struct {
+	Valid      bool     // well-formed PAN with a known holder type
+	HolderType string   // individual, company, huf, firm, trust, ...
+	Issues     []string // why it is invalid, plus name_initial_mismatch
+}
+
+// ValidatePAN checks a PAN's structure (AAAAA9999A), that the 4th character is
+// a known holder type, and, when the holder's name is known, that the 5th
+// character is the initial of the surname (individuals, the last word of the
+// name) or of the entity name. The final check character's algorithm is not
+// published, so it is only checked to be a letter.
+func ValidatePAN(pan, name string) PANValidation {
+	pan = strings.ToUpper(strings.TrimSpace(pan))
+	v := PANValidation{}
+
+	if pan == "" {
+		v.Issues = append(v.Issues, "pan_not_found")
+		return v
+	}
+	if !panStructureRe.MatchString(pan) {
+		v.Issues = append(v.Issues, "invalid_structure")
+		return v
+	}
+
+	holder, ok := panHolderTypes[pan[3]]
+	if !ok {
+		v.Issues = append(v.Issues, "unknown_holder_type")
+		return v
+	}
+	v.HolderType = holder
+	v.Valid = true
+
+	if initial := panNameInitial(name, pan[3] == 'P'); initial != 0 && initial != pan[4] {
+		v.Issues = append(v.Issues, "name_initial_mismatch")
+	}
+	return v
+}
+
+// panNameInitial returns the letter the PAN's 5th character should carry, or 0
+// when the name gives nothing to compare.
+func panNameInitial(name string, individual bool) byte {
+	var words []string
+	for _, w := range strings.Fields(strings.ToUpper(name)) {
+		w = strings.Trim(w, ".,")
+		if len(w) > 0 && w[0] >= 'A' && w[0] <= 'Z' {
+			words = append(words, w)
+		}
+	}
+	if len(words) == 0 {
+		return 0
+	}
+	if individual {
+		return words[len(words)-1][0]
+	}
+	return words[0][0]
+}
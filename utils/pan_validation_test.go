@@ -0,0 +1,27 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePAN(t *testing.T) {
+	v := ValidatePAN("ABCPS1234K", "RAHUL KUMAR SHARMA")
+	assert.True(t, v.Valid)
+	assert.Equal(t, "individual", v.HolderType)
+	assert.Empty(t, v.Issues)
+
+	v = ValidatePAN("ABCPS1234K", "RAHUL VERMA")
+	assert.True(t, v.Valid)
+	assert.Equal(t, []string{"name_initial_mismatch"}, v.Issues)
+
+	v = ValidatePAN("AABCA1234C", "ACME SOFTWARE PVT LTD")
+	assert.True(t, v.Valid)
+	assert.Equal(t, "company", v.HolderType)
+	assert.Empty(t, v.Issues)
+
+	assert.Equal(t, []string{"unknown_holder_type"}, ValidatePAN("ABCXS1234K", "").Issues)
+	assert.Equal(t, []string{"invalid_structure"}, ValidatePAN("ABCPS12X4K", "").Issues)
+	assert.False(t, ValidatePAN("", "").Valid)
+}
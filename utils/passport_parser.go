@@ -0,0 +1,92 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+type PassportParsed struct {
+	PassportNumber string
+	Surname        string
+	GivenNames     string
+	DOB            string
+	Sex            string
+	Nationality    string
+	ExpiryDate     string
+}
+
+var (
+	passportNumberRegex = regexp.MustCompile(`\b([A-Z][0-9]{7})\b`)
+	passportDateRegex   = regexp.MustCompile(`\b(\d{2}[/\-.]\d{2}[/\-.]\d{4})\b`)
+)
+
+// ParsePassportText reads the printed (visual zone) fields of a passport data
+// page. It is the fallback for when no MRZ could be decoded.
+func ParsePassportText(raw string) PassportParsed {
+	t := strings.ToUpper(raw)
+	lines := splitAndTrimLines(t)
+
+	res := PassportParsed{}
+	if m := passportNumberRegex.FindStringSubmatch(t); len(m) > 1 {
+		res.PassportNumber = m[1]
+	}
+
+	res.Surname = passportValueAfter(lines, "SURNAME")
+	res.GivenNames = passportValueAfter(lines, "GIVEN NAME")
+	res.Nationality = passportValueAfter(lines, "NATIONALITY")
+	res.DOB = passportDateAfter(lines, "DATE OF BIRTH")
+	res.ExpiryDate = passportDateAfter(lines, "DATE OF EXPIRY")
+
+	switch sex := passportValueAfter(lines, "SEX"); {
+	case strings.HasPrefix(sex, "M"):
+		res.Sex = "M"
+	case strings.HasPrefix(sex, "F"):
+		res.Sex = "F"
+	}
+
+	return res
+}
+
+// passportValueAfter returns the text following label, on the same line after a
+// separator or on the next line. Bilingual cards print "Surname / उपनाम"; only
+// the Latin part of the label line is matched.
+func passportValueAfter(lines []string, label string) string {
+	for i, l := range lines {
+		idx := strings.Index(l, label)
+		if idx < 0 {
+			continue
+		}
+		rest := strings.TrimSpace(l[idx+len(label):])
+		rest = strings.TrimLeft(rest, "(S)/: ")
+		if rest != "" && isNameLike(rest) && isLatin(rest) {
+			return rest
+		}
+		if i+1 < len(lines) && isLatin(lines[i+1]) {
+			return lines[i+1]
+		}
+	}
+	return ""
+}
+
+func passportDateAfter(lines []string, label string) string {
+	for i, l := range lines {
+		if !strings.Contains(l, label) {
+			continue
+		}
+		for j := i; j <= i+2 && j < len(lines); j++ {
+			if m := passportDateRegex.FindStringSubmatch(lines[j]); len(m) > 1 {
+				return strings.NewReplacer("-", "/", ".", "/").Replace(m[1])
+			}
+		}
+	}
+	return ""
+}
+
+func isLatin(s string) bool {
+	for _, c := range s {
+		if c > 0x7f {
+			return false
+		}
+	}
+	return true
+}
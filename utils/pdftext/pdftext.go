@@ -0,0 +1,172 @@
+// Package pdftext reads the text layer of a PDF page as laid out on the page:
+// words keep their spaces and label-value pairs stay on one line, with the
+// columns of a table separated by runs of spaces (as OCR text and
+// utils.ExtractFields expect) rather than glued together.
+package pdftext
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Glyph is one character drawn on the page. X and Y are its origin in points
+// (Y increasing upwards), W its advance width, 0 when the font does not say,
+// and Size the font size.
+type Glyph struct {
+	S       string
+	X, Y, W float64
+	Size    float64
+}
+
+// PageText returns the laid-out text of a page. The PDF library panics on
+// malformed content streams; that is returned as an error.
+func PageText(p pdf.Page) (text string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			text, err = "", fmt.Errorf("unreadable content stream: %v", r)
+		}
+	}()
+	content := p.Content()
+	glyphs := make([]Glyph, 0, len(content.Text))
+	for _, t := range content.Text {
+		glyphs = append(glyphs, Glyph{S: t.S, X: t.X, Y: t.Y, W: t.W, Size: t.FontSize})
+	}
+	return Layout(glyphs), nil
+}
+
+// run is a stretch of glyphs drawn one after the other on a baseline.
+type run struct {
+	text   strings.Builder
+	x0, x1 float64 // x1 is estimated when widths are unknown
+	y      float64
+	size   float64
+	n      int  // glyphs
+	exact  bool // every glyph had a width
+	lastX  float64
+	lastW  float64
+}
+
+// Layout arranges glyphs, in content stream order, into lines top to bottom.
+// Glyphs following on from each other form runs; runs on a baseline form a
+// line, left to right. Runs are joined with a space where the gap between
+// them is wider than a letter spacing, and with at least two spaces, aligned
+// to a character grid, where it is a column gap.
+func Layout(glyphs []Glyph) string {
+	runs := groupRuns(glyphs)
+	if len(runs) == 0 {
+		return ""
+	}
+
+	// character grid: half the typical font size
+	sizes := make([]float64, len(runs))
+	minX := math.Inf(1)
+	for i, r := range runs {
+		sizes[i] = r.size
+		minX = math.Min(minX, r.x0)
+	}
+	sort.Float64s(sizes)
+	cell := sizes[len(sizes)/2] / 2
+
+	var out strings.Builder
+	for _, line := range groupLines(runs) {
+		var b strings.Builder
+		width := 0 // characters written
+		var prev *run
+		for _, r := range line {
+			text := r.text.String()
+			if prev != nil {
+				gap := r.x0 - prev.x1
+				switch {
+				case gap >= 2*cell:
+					col := int((r.x0-minX)/cell + 0.5)
+					pad := max(2, col-width)
+					b.WriteString(strings.Repeat(" ", pad))
+					width += pad
+				case gap > 0.15*r.size || !prev.exact || !r.exact:
+					if !strings.HasSuffix(b.String(), " ") && !strings.HasPrefix(text, " ") {
+						b.WriteByte(' ')
+						width++
+					}
+				}
+			}
+			b.WriteString(text)
+			width += utf8.RuneCountInString(text)
+			prev = r
+		}
+		if s := strings.TrimRight(b.String(), " "); s != "" {
+			out.WriteString(s)
+			out.WriteByte('\n')
+		}
+	}
+	return out.String()
+}
+
+// groupRuns joins consecutive glyphs into runs where each starts about where
+// the previous one ended on the same baseline. Glyphs without a width all
+// start at the run's position, the PDF library not advancing over them.
+func groupRuns(glyphs []Glyph) []*run {
+	var runs []*run
+	var cur *run
+	for _, g := range glyphs {
+		if g.S == "" || g.S == "\n" || g.S == "\r" {
+			continue
+		}
+		size := g.Size
+		if size <= 0 {
+			size = 10
+		}
+		if cur != nil {
+			end := cur.lastX + cur.lastW
+			if math.Abs(g.Y-cur.y) < 0.2*size && g.X >= cur.lastX-0.05*size && g.X <= end+0.15*size {
+				cur.text.WriteString(g.S)
+				cur.n++
+				cur.exact = cur.exact && g.W > 0
+				cur.lastX, cur.lastW = g.X, g.W
+				continue
+			}
+		}
+		if g.S == " " {
+			cur = nil // a space starts no run
+			continue
+		}
+		cur = &run{x0: g.X, y: g.Y, size: size, n: 1, exact: g.W > 0, lastX: g.X, lastW: g.W}
+		cur.text.WriteString(g.S)
+		runs = append(runs, cur)
+	}
+	for _, r := range runs {
+		if r.exact {
+			r.x1 = r.lastX + r.lastW
+		} else {
+			r.x1 = r.x0 + float64(r.n)*r.size/2
+		}
+	}
+	return runs
+}
+
+// groupLines groups runs whose baselines are within half a font size, top
+// line first, each line's runs left to right.
+func groupLines(runs []*run) [][]*run {
+	sorted := append([]*run(nil), runs...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].y > sorted[j].y })
+
+	var lines [][]*run
+	for _, r := range sorted {
+		if n := len(lines); n > 0 {
+			first := lines[n-1][0]
+			if math.Abs(first.y-r.y) < 0.5*math.Min(first.size, r.size) {
+				lines[n-1] = append(lines[n-1], r)
+				continue
+			}
+		}
+		lines = append(lines, []*run{r})
+	}
+	for _, line := range lines {
+		sort.SliceStable(line, func(i, j int) bool { return line[i].x0 < line[j].x0 })
+	}
+	return lines
+}
@@ -0,0 +1,74 @@
+package pdftext
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ledongthuc/pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// textPDF builds a one-page PDF drawing content with Helvetica, every glyph
+// 500/1000 em wide.
+func textPDF(t *testing.T, content string) []byte {
+	t.Helper()
+	widths := strings.TrimSpace(strings.Repeat("500 ", 95))
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Resources << /Font << /F1 4 0 R >> >> /Contents 5 0 R >>",
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding /FirstChar 32 /LastChar 126 /Widths [" + widths + "] >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", len(content), content),
+	}
+	var b bytes.Buffer
+	b.WriteString("%PDF-1.4\n")
+	offsets := make([]int, len(objects))
+	for i, obj := range objects {
+		offsets[i] = b.Len()
+		fmt.Fprintf(&b, "%d 0 obj\n%s\nendobj\n", i+1, obj)
+	}
+	xref := b.Len()
+	fmt.Fprintf(&b, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, off := range offsets {
+		fmt.Fprintf(&b, "%010d 00000 n \n", off)
+	}
+	fmt.Fprintf(&b, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xref)
+	return b.Bytes()
+}
+
+func TestPageText(t *testing.T) {
+	// a label and value in separate cells, a word split by kerning, and a
+	// second line
+	data := textPDF(t, "BT /F1 10 Tf 50 700 Td [(Net)-200(Pay)-6000(45,200.00)] TJ ET\n"+
+		"BT /F1 10 Tf 50 680 Td [(Gro)10(ss)] TJ ET\n"+
+		"BT /F1 10 Tf 142 680 Td (50,000.00) Tj ET")
+	r, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	text, err := PageText(r.Page(1))
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Regexp(t, `^Net Pay {2,}45,200\.00$`, lines[0])
+	assert.Regexp(t, `^Gross {2,}50,000\.00$`, lines[1])
+	// the values are in one column
+	assert.Equal(t, strings.Index(lines[0], "4"), strings.Index(lines[1], "5"))
+}
+
+func TestLayoutWithoutWidths(t *testing.T) {
+	// fonts without widths: the library leaves every glyph of a string at
+	// its start
+	var glyphs []Glyph
+	for _, s := range []struct {
+		text string
+		x    float64
+	}{{"Account", 50}, {"No", 90}, {"1234", 300}} {
+		for _, ch := range s.text {
+			glyphs = append(glyphs, Glyph{S: string(ch), X: s.x, Y: 700, Size: 10})
+		}
+	}
+	assert.Regexp(t, `^Account No {2,}1234\n$`, Layout(glyphs))
+}
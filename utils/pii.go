@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// piiPatterns are listed in priority order: when two matches overlap,
+// the earlier pattern wins (a 12-digit Aadhaar is not also an account number).
+var piiPatterns = []struct {
+	typ string
+	re  *regexp.Regexp
+}{
+	{dto.PIITypeEmail, regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)},
+	{dto.PIITypeAadhaar, regexp.MustCompile(`\b[2-9]\d{3}[ \-]?\d{4}[ \-]?\d{4}\b`)},
+	{dto.PIITypeAadhaar, regexp.MustCompile(`\b[Xx]{4}[ \-]?[Xx]{4}[ \-]?\d{4}\b`)},
+	{dto.PIITypePAN, regexp.MustCompile(`\b[A-Z]{5}[0-9]{4}[A-Z]\b`)},
+	{dto.PIITypePhone, regexp.MustCompile(`(?:\+91[ \-]?|\b0)?\b[6-9]\d{4}[ \-]?\d{5}\b`)},
+	{dto.PIITypeAccountNumber, regexp.MustCompile(`\b\d{9,18}\b`)},
+	{dto.PIITypeAccountNumber, regexp.MustCompile(`[Xx*]{4,}\d{3,6}\b`)},
+}
+
+// ScanPII finds Aadhaar numbers, PANs, account numbers, phone numbers and email
+// addresses in text. Findings are sorted by offset and never overlap.
+func ScanPII(text string) []dto.PIIFinding {
+	type candidate struct {
+		dto.PIIFinding
+		priority int
+	}
+
+	var cands []candidate
+	for prio, p := range piiPatterns {
+		for _, loc := range p.re.FindAllStringIndex(text, -1) {
+			cands = append(cands, candidate{
+				PIIFinding: dto.PIIFinding{
+					Type:   p.typ,
+					Start:  loc[0],
+					End:    loc[1],
+					Masked: MaskValue(text[loc[0]:loc[1]]),
+				},
+				priority: prio,
+			})
+		}
+	}
+
+	sort.SliceStable(cands, func(i, j int) bool {
+		if cands[i].priority != cands[j].priority {
+			return cands[i].priority < cands[j].priority
+		}
+		return cands[i].Start < cands[j].Start
+	})
+
+	var kept []dto.PIIFinding
+	for _, c := range cands {
+		overlaps := false
+		for _, k := range kept {
+			if c.Start < k.End && k.Start < c.End {
+				overlaps = true
+				break
+			}
+		}
+		if !overlaps {
+			kept = append(kept, c.PIIFinding)
+		}
+	}
+
+	sort.Slice(kept, func(i, j int) bool { return kept[i].Start < kept[j].Start })
+	return kept
+}
+
+// SummarizePII counts findings per type.
+func SummarizePII(findings []dto.PIIFinding) dto.PIISummary {
+	summary := dto.PIISummary{}
+	for _, f := range findings {
+		summary[f.Type]++
+	}
+	return summary
+}
+
+// MaskValue keeps only the last 4 characters of a value (emails keep the domain).
+func MaskValue(v string) string {
+	if at := strings.LastIndex(v, "@"); at > 0 {
+		return strings.Repeat("*", at) + v[at:]
+	}
+	compact := strings.NewReplacer(" ", "", "-", "").Replace(v)
+	if len(compact) <= 4 {
+		return strings.Repeat("*", len(compact))
+	}
+	return strings.Repeat("X", len(compact)-4) + compact[len(compact)-4:]
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScanPII(t *testing.T) {
+	text := "Name: Ravi Kumar PAN ABCDE1234F Aadhaar 2345 6789 0123\n" +
+		"A/c No 123456789012345 Mob +91 98765 43210 ravi.k@example.com"
+
+	findings := ScanPII(text)
+
+	var types []string
+	for _, f := range findings {
+		types = append(types, f.Type)
+		assert.NotContains(t, f.Masked, "6789", "masked value must not leak the middle digits")
+	}
+	assert.Equal(t, []string{
+		dto.PIITypePAN, dto.PIITypeAadhaar, dto.PIITypeAccountNumber, dto.PIITypePhone, dto.PIITypeEmail,
+	}, types)
+
+	aadhaar := findings[1]
+	assert.Equal(t, "2345 6789 0123", text[aadhaar.Start:aadhaar.End])
+	assert.Equal(t, "XXXXXXXX0123", aadhaar.Masked)
+
+	summary := SummarizePII(findings)
+	assert.Equal(t, 1, summary[dto.PIITypeEmail])
+}
@@ -0,0 +1,88 @@
+// Package redact hides sensitive regions of document images before they are
+// returned or archived.
+package redact
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// BlackOut returns a copy of img with each box filled black, grown by pad
+// pixels on every side so the edges of OCR boxes do not leave strokes showing.
+func BlackOut(img image.Image, boxes []dto.BoundingBox, pad int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, img, b.Min, draw.Src)
+	for _, box := range boxes {
+		r := image.Rect(box.X0-pad, box.Y0-pad, box.X1+pad, box.Y1+pad).Intersect(b)
+		draw.Draw(out, r, image.NewUniform(color.Black), image.Point{}, draw.Src)
+	}
+	return out
+}
+
+// Blur returns a copy of img with each box, grown by pad pixels, turned into
+// a coarse mosaic: blocks of a quarter of the box's smaller side (at least 8
+// pixels) take their average colour, which leaves nothing of text or a face
+// to recover.
+func Blur(img image.Image, boxes []dto.BoundingBox, pad int) *image.RGBA {
+	b := img.Bounds()
+	out := image.NewRGBA(b)
+	draw.Draw(out, b, img, b.Min, draw.Src)
+	for _, box := range boxes {
+		r := image.Rect(box.X0-pad, box.Y0-pad, box.X1+pad, box.Y1+pad).Intersect(b)
+		block := max(8, min(r.Dx(), r.Dy())/4)
+		for y := r.Min.Y; y < r.Max.Y; y += block {
+			for x := r.Min.X; x < r.Max.X; x += block {
+				cell := image.Rect(x, y, x+block, y+block).Intersect(r)
+				draw.Draw(out, cell, image.NewUniform(average(out, cell)), image.Point{}, draw.Src)
+			}
+		}
+	}
+	return out
+}
+
+func average(img *image.RGBA, r image.Rectangle) color.RGBA {
+	var sr, sg, sb, n int
+	for y := r.Min.Y; y < r.Max.Y; y++ {
+		for x := r.Min.X; x < r.Max.X; x++ {
+			c := img.RGBAAt(x, y)
+			sr, sg, sb, n = sr+int(c.R), sg+int(c.G), sb+int(c.B), n+1
+		}
+	}
+	if n == 0 {
+		return color.RGBA{A: 0xff}
+	}
+	return color.RGBA{uint8(sr / n), uint8(sg / n), uint8(sb / n), 0xff}
+}
+
+// signatureLabel matches the caption of a signature: "Signature", "Authorised
+// Signatory", "Sign here", the Hindi "हस्ताक्षर".
+var signatureLabel = regexp.MustCompile(`(?i)\b(?:signature|signatory|sign\s+(?:here|above|below))\b|हस्ताक्षर`)
+
+// SignatureBoxes returns where the signatures captioned by OCR words are
+// likely to be: above the caption, a few lines high and a little wider than
+// it, and for a caption ending in a colon ("Signature:") also to its right.
+// There is no ink detection, so the boxes are generous.
+func SignatureBoxes(words []dto.OCRWord) []dto.OCRWord {
+	var out []dto.OCRWord
+	for _, w := range words {
+		if !signatureLabel.MatchString(w.Text) {
+			continue
+		}
+		h := max(w.Box.Y1-w.Box.Y0, 8)
+		above := w
+		above.Box = dto.BoundingBox{X0: w.Box.X0 - 2*h, Y0: w.Box.Y0 - 4*h, X1: w.Box.X1 + 2*h, Y1: w.Box.Y0}
+		out = append(out, above)
+		if strings.HasSuffix(strings.TrimSpace(w.Text), ":") {
+			right := w
+			right.Box = dto.BoundingBox{X0: w.Box.X1, Y0: w.Box.Y0 - h, X1: w.Box.X1 + 10*h, Y1: w.Box.Y1 + h}
+			out = append(out, right)
+		}
+	}
+	return out
+}
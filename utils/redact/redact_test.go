@@ -0,0 +1,55 @@
+package redact
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlackOut(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 100, 50))
+	for i := range img.Pix {
+		img.Pix[i] = 0xff
+	}
+	out := BlackOut(img, []dto.BoundingBox{{X0: 10, Y0: 10, X1: 30, Y1: 20}, {X0: 90, Y0: 40, X1: 120, Y1: 60}}, 2)
+
+	black := color.RGBA{0, 0, 0, 0xff}
+	assert.Equal(t, black, out.RGBAAt(8, 8), "padded")
+	assert.Equal(t, black, out.RGBAAt(99, 49), "clipped to the image")
+	assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, out.RGBAAt(50, 30))
+	assert.Equal(t, uint8(0xff), img.Pix[0], "the original is left alone")
+}
+
+func TestBlur(t *testing.T) {
+	img := image.NewGray(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			if (x/2+y/2)%2 == 0 {
+				img.Pix[y*img.Stride+x] = 0xff
+			}
+		}
+	}
+	out := Blur(img, []dto.BoundingBox{{X0: 0, Y0: 0, X1: 32, Y1: 32}}, 0)
+
+	// an 8-pixel block of the 2-pixel checkerboard averages to mid grey
+	assert.Equal(t, color.RGBA{0x7f, 0x7f, 0x7f, 0xff}, out.RGBAAt(3, 5))
+	assert.Equal(t, out.RGBAAt(0, 0), out.RGBAAt(7, 7))
+	assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, out.RGBAAt(40, 40), "outside the box")
+}
+
+func TestSignatureBoxes(t *testing.T) {
+	words := []dto.OCRWord{
+		{Text: "Name", Page: 1, Box: dto.BoundingBox{X0: 10, Y0: 10, X1: 60, Y1: 30}},
+		{Text: "Signature", Page: 1, Box: dto.BoundingBox{X0: 300, Y0: 400, X1: 380, Y1: 420}},
+		{Text: "Signature:", Page: 2, Box: dto.BoundingBox{X0: 50, Y0: 100, X1: 140, Y1: 120}},
+	}
+	boxes := SignatureBoxes(words)
+	if assert.Len(t, boxes, 3) {
+		assert.Equal(t, dto.BoundingBox{X0: 260, Y0: 320, X1: 420, Y1: 400}, boxes[0].Box, "above the caption")
+		assert.Equal(t, 2, boxes[1].Page)
+		assert.Equal(t, dto.BoundingBox{X0: 140, Y0: 80, X1: 340, Y1: 140}, boxes[2].Box, "beside a caption ending in a colon")
+	}
+}
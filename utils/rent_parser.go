@@ -0,0 +1,160 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+const (
+	rentName = `((?:[A-Z][A-Za-z.']*\s?){1,5})`
+	rentDate = `(\d{1,2}(?:st|nd|rd|th)?[\s/.\-]+(?:\d{1,2}|[A-Za-z]{3,9})[\s/.\-,]+\d{4})`
+)
+
+var (
+	rentHonorific = regexp.MustCompile(`(?i)^(mr|mrs|ms|miss|shri|sri|smt|dr|m/s)\.?\s+`)
+
+	rentLandlordLabel = regexp.MustCompile(`(?i)\b(?:landlord|owner|lessor|licensor)(?:'s)?\s*(?:name)?\s*[:\-]\s*` + rentName)
+	rentTenantLabel   = regexp.MustCompile(`(?i)\b(?:tenant|lessee|licensee)(?:'s)?\s*(?:name)?\s*[:\-]\s*` + rentName)
+	// "between Mr. Suresh Reddy ... (LANDLORD/LESSOR/LICENSOR) and Mr. Ravi Kumar ... (TENANT)"
+	rentBetween = regexp.MustCompile(`(?i)\bbetween\s+(?:(?:mr|mrs|ms|shri|sri|smt|dr)\.?\s+)?` + rentName + `.{0,200}?\band\s+(?:(?:mr|mrs|ms|shri|sri|smt|dr)\.?\s+)?` + rentName)
+	// "Received (with thanks) from Mr. Ravi Kumar a sum of ..."
+	rentReceivedFrom = regexp.MustCompile(`(?i)\breceived\s+(?:with\s+thanks\s+)?from\s+(?:(?:mr|mrs|ms|shri|sri|smt|dr)\.?\s+)?` + rentName)
+
+	rentAmount = regexp.MustCompile(`(?i)\b(?:monthly\s+rent|rent\s+amount|rent|sum|amount)\s*(?:of|:|-|is)?\s*(?:rs\.?|inr|₹|rupees)\s*([0-9][0-9,]*(?:\.[0-9]{1,2})?)`)
+
+	rentMonthOf       = regexp.MustCompile(`(?i)\bfor\s+the\s+month\s+of\s+([A-Za-z]{3,9})[\s,'\-]+(\d{4})`)
+	rentPeriod        = regexp.MustCompile(`(?i)\b(?:from|period)\s*:?\s*` + rentDate + `\s*(?:to|till|until|-)\s*` + rentDate)
+	rentTerm          = regexp.MustCompile(`(?i)\bperiod\s+of\s+(\d{1,3})\s*\(?[a-z ]*\)?\s*months?\b.{0,60}?(?:from|commencing|starting|w\.?e\.?f\.?)\D{0,20}` + rentDate)
+	rentAddress       = regexp.MustCompile(`(?i)\b(?:property|premises|flat|house)\s+(?:located\s+|situated\s+|bearing\s+)?at\s+(.{10,200}?)(?:\s+for\s+the|\s+for\s+a|\s+from\s|\s+on\s+a|\.\s|\(|$)`)
+	rentAddressLbl    = regexp.MustCompile(`(?i)\b(?:property\s+)?address\s*[:\-]\s*(.{10,200}?)(?:\s+(?:landlord|tenant|owner|rent|amount|pan)\b|$)`)
+	rentOrdinal       = regexp.MustCompile(`(?i)(\d)(st|nd|rd|th)\b`)
+	rentDateSeparator = regexp.MustCompile(`[\s/.\-,]+`)
+)
+
+// ParseRent extracts the landlord, tenant, monthly rent, period and property
+// address from a rent receipt ("Received from Mr. Ravi Kumar a sum of Rs.
+// 25,000 towards rent of the property at ... for the month of April 2025") or
+// a rental agreement ("... between Mr. Suresh Reddy (LANDLORD) and Mr. Ravi
+// Kumar (TENANT) ... monthly rent of Rs. 25,000/- ... for a period of 11
+// months commencing from 01/04/2025").
+func ParseRent(text string) dto.RentData {
+	flat := strings.Join(strings.Fields(text), " ")
+	upper := strings.ToUpper(flat)
+
+	res := dto.RentData{Kind: "receipt"}
+	if strings.Contains(upper, "AGREEMENT") || strings.Contains(upper, "LEAVE AND LICEN") || strings.Contains(upper, "LEASE DEED") {
+		res.Kind = "agreement"
+	}
+
+	if m := rentLandlordLabel.FindStringSubmatch(flat); m != nil {
+		res.LandlordName = rentPerson(m[1])
+	}
+	if m := rentTenantLabel.FindStringSubmatch(flat); m != nil {
+		res.TenantName = rentPerson(m[1])
+	}
+	if m := rentBetween.FindStringSubmatch(flat); m != nil && res.Kind == "agreement" {
+		if res.LandlordName == "" {
+			res.LandlordName = rentPerson(m[1])
+		}
+		if res.TenantName == "" {
+			res.TenantName = rentPerson(m[2])
+		}
+	}
+	if m := rentReceivedFrom.FindStringSubmatch(flat); m != nil && res.TenantName == "" {
+		res.TenantName = rentPerson(m[1])
+	}
+
+	if m := form26PANRegex.FindStringSubmatch(upper); m != nil {
+		res.LandlordPAN = m[1] // only the landlord's PAN is asked for
+	}
+
+	for _, m := range rentAmount.FindAllStringSubmatch(flat, -1) {
+		if amount := mustParseAmount(m[1]); amount > 0 {
+			res.MonthlyRent = amount
+			break
+		}
+	}
+
+	switch {
+	case rentTerm.MatchString(flat):
+		m := rentTerm.FindStringSubmatch(flat)
+		months, _ := strconv.Atoi(m[1])
+		if from, ok := parseDMYDate(m[2]); ok && months > 0 {
+			res.PeriodFrom = from.Format("2006-01-02")
+			res.PeriodTo = from.AddDate(0, months, -1).Format("2006-01-02")
+		}
+	case rentPeriod.MatchString(flat):
+		m := rentPeriod.FindStringSubmatch(flat)
+		from, okFrom := parseDMYDate(m[1])
+		to, okTo := parseDMYDate(m[2])
+		if okFrom && okTo {
+			res.PeriodFrom, res.PeriodTo = from.Format("2006-01-02"), to.Format("2006-01-02")
+		}
+	case rentMonthOf.MatchString(flat):
+		m := rentMonthOf.FindStringSubmatch(flat)
+		if month, ok := monthNumbers[strings.ToLower(firstN(m[1], 3))]; ok {
+			year, _ := strconv.Atoi(m[2])
+			res.PeriodFrom = time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+			res.PeriodTo = endOfMonth(year, month).Format("2006-01-02")
+		}
+	}
+
+	if m := rentAddressLbl.FindStringSubmatch(flat); m != nil {
+		res.Address = strings.Trim(m[1], " ,.")
+	} else if m := rentAddress.FindStringSubmatch(flat); m != nil {
+		res.Address = strings.Trim(m[1], " ,.")
+	}
+	return res
+}
+
+// rentNameStop are words that end a name caught by a loose name pattern.
+var rentNameStop = map[string]bool{
+	"s/o": true, "d/o": true, "w/o": true, "hereinafter": true, "residing": true, "aged": true,
+	"a": true, "the": true, "sum": true, "of": true, "rs": true, "towards": true,
+	"landlord": true, "tenant": true, "owner": true, "pan": true,
+}
+
+// rentPerson strips the honorific and trailing words from a name.
+func rentPerson(s string) string {
+	words := strings.Fields(rentHonorific.ReplaceAllString(strings.TrimSpace(s), ""))
+	for i, w := range words {
+		if rentNameStop[strings.ToLower(strings.Trim(w, ".,"))] {
+			words = words[:i]
+			break
+		}
+	}
+	return strings.Trim(strings.Join(words, " "), " ,.")
+}
+
+// parseDMYDate reads a day-first date: "01/04/2025", "1-4-2025", "1st April
+// 2025" or "01 Apr, 2025".
+func parseDMYDate(s string) (time.Time, bool) {
+	s = rentOrdinal.ReplaceAllString(s, "$1")
+	parts := rentDateSeparator.Split(strings.TrimSpace(s), -1)
+	if len(parts) != 3 {
+		return time.Time{}, false
+	}
+	day, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return time.Time{}, false
+	}
+	year, err := strconv.Atoi(parts[2])
+	if err != nil {
+		return time.Time{}, false
+	}
+	var month time.Month
+	if n, err := strconv.Atoi(parts[1]); err == nil {
+		month = time.Month(n)
+	} else if m, ok := monthNumbers[strings.ToLower(firstN(parts[1], 3))]; ok {
+		month = m
+	}
+	t := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	if month < 1 || month > 12 || t.Day() != day {
+		return time.Time{}, false
+	}
+	return t, true
+}
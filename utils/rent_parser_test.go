@@ -0,0 +1,45 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRentReceipt(t *testing.T) {
+	text := `RENT RECEIPT
+Receipt No: 12
+Received with thanks from Mr. Ravi Kumar a sum of Rs. 25,000/- (Rupees Twenty Five Thousand Only)
+towards rent of the property located at Flat 302, Green Residency, HSR Layout, Bengaluru 560102
+for the month of April 2025.
+Landlord Name: Suresh Reddy
+Landlord PAN: ABCPR1234K`
+
+	rent := ParseRent(text)
+	assert.Equal(t, "receipt", rent.Kind)
+	assert.Equal(t, "Ravi Kumar", rent.TenantName)
+	assert.Equal(t, "Suresh Reddy", rent.LandlordName)
+	assert.Equal(t, "ABCPR1234K", rent.LandlordPAN)
+	assert.Equal(t, 25000.0, rent.MonthlyRent)
+	assert.Equal(t, "2025-04-01", rent.PeriodFrom)
+	assert.Equal(t, "2025-04-30", rent.PeriodTo)
+	assert.Equal(t, "Flat 302, Green Residency, HSR Layout, Bengaluru 560102", rent.Address)
+}
+
+func TestParseRentAgreement(t *testing.T) {
+	text := `RENTAL AGREEMENT
+This Rental Agreement is made on 1st April 2025 between Mr. Suresh Reddy, S/o Late K. Reddy
+(hereinafter called the LANDLORD) and Ms. Priya Nair, aged 29 years (hereinafter called the TENANT).
+The Landlord lets out the premises situated at 14, 2nd Cross, Indiranagar, Bengaluru 560038 for a
+period of 11 (eleven) months commencing from 01/04/2025 on a monthly rent of Rs. 32,500/- payable
+on or before the 5th of every month.`
+
+	rent := ParseRent(text)
+	assert.Equal(t, "agreement", rent.Kind)
+	assert.Equal(t, "Suresh Reddy", rent.LandlordName)
+	assert.Equal(t, "Priya Nair", rent.TenantName)
+	assert.Equal(t, 32500.0, rent.MonthlyRent)
+	assert.Equal(t, "2025-04-01", rent.PeriodFrom)
+	assert.Equal(t, "2026-02-28", rent.PeriodTo)
+	assert.Equal(t, "14, 2nd Cross, Indiranagar, Bengaluru 560038", rent.Address)
+}
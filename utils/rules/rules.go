@@ -0,0 +1,160 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"github.com/goccy/go-yaml"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// maxRuleNodes bounds the size of a single expression so a tenant cannot
+// register something pathologically expensive.
+const maxRuleNodes = 500
+
+// Rule is one tenant-defined decision rule. The expression is evaluated over the
+// verification response (json field names) and must hold for the applicant to pass:
+//
+//	# rules/acme.yaml
+//	- id: ACME_MIN_INCOME
+//	  expr: mean(map(salary_slips, .net_salary)) > 25000 && cross_check.name_similarity > 0.8
+//	  on_fail: reject
+//	  message: Net salary below 25k or name mismatch
+type Rule struct {
+	ID      string `yaml:"id"`
+	Expr    string `yaml:"expr"`
+	OnFail  string `yaml:"on_fail"` // review (default) | reject
+	Message string `yaml:"message"`
+
+	program *vm.Program
+}
+
+// Engine holds the compiled rules of every tenant.
+type Engine struct {
+	tenants map[string][]*Rule
+}
+
+// LoadDir reads one <tenant-id>.yaml file per tenant from dir. A missing directory yields an empty engine.
+func LoadDir(dir string) (*Engine, error) {
+	eng := &Engine{tenants: map[string][]*Rule{}}
+
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return eng, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules dir: %w", err)
+	}
+
+	for _, e := range entries {
+		ext := strings.ToLower(filepath.Ext(e.Name()))
+		if e.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules %s: %w", e.Name(), err)
+		}
+		rules, err := Parse(data)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rules %s: %w", e.Name(), err)
+		}
+		eng.tenants[strings.TrimSuffix(e.Name(), filepath.Ext(e.Name()))] = rules
+	}
+	return eng, nil
+}
+
+// Parse decodes and compiles a tenant's rule list.
+func Parse(data []byte) ([]*Rule, error) {
+	var rules []*Rule
+	if err := yaml.UnmarshalWithOptions(data, &rules, yaml.DisallowUnknownField()); err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	for _, r := range rules {
+		if r.ID == "" || r.Expr == "" {
+			return nil, fmt.Errorf("id and expr are required")
+		}
+		if seen[r.ID] {
+			return nil, fmt.Errorf("duplicate rule id %s", r.ID)
+		}
+		seen[r.ID] = true
+
+		switch r.OnFail {
+		case "":
+			r.OnFail = dto.DecisionReview
+		case dto.DecisionReview, dto.DecisionReject:
+		default:
+			return nil, fmt.Errorf("rule %s: on_fail must be %q or %q", r.ID, dto.DecisionReview, dto.DecisionReject)
+		}
+
+		program, err := expr.Compile(r.Expr, expr.AsBool(), expr.AllowUndefinedVariables(), expr.MaxNodes(maxRuleNodes))
+		if err != nil {
+			return nil, fmt.Errorf("rule %s: %w", r.ID, err)
+		}
+		r.program = program
+	}
+	return rules, nil
+}
+
+// Set replaces the rules of one tenant.
+func (e *Engine) Set(tenantID string, rules []*Rule) {
+	if e.tenants == nil {
+		e.tenants = map[string][]*Rule{}
+	}
+	e.tenants[tenantID] = rules
+}
+
+// HasRules reports whether tenantID has any rules configured.
+func (e *Engine) HasRules(tenantID string) bool {
+	return e != nil && len(e.tenants[tenantID]) > 0
+}
+
+// Evaluate runs the tenant's rules against result and returns the decision.
+// Expressions only see a JSON copy of result and have no access to functions
+// with side effects. A rule that fails to evaluate (e.g. the field it reads is
+// missing) sends the application to review rather than silently passing.
+func (e *Engine) Evaluate(tenantID string, result interface{}) (*dto.Decision, error) {
+	raw, err := json.Marshal(result)
+	if err != nil {
+		return nil, err
+	}
+	env := map[string]interface{}{}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return nil, err
+	}
+
+	decision := &dto.Decision{Outcome: dto.DecisionApprove, Reasons: []dto.DecisionReason{}}
+	if e == nil {
+		return decision, nil
+	}
+
+	for _, r := range e.tenants[tenantID] {
+		out, err := expr.Run(r.program, env)
+		if err != nil {
+			decision.Add(dto.DecisionReason{
+				RuleID:  r.ID,
+				Outcome: dto.DecisionReview,
+				Message: fmt.Sprintf("rule could not be evaluated: %v", err),
+			})
+			continue
+		}
+		if passed, _ := out.(bool); passed {
+			continue
+		}
+
+		msg := r.Message
+		if msg == "" {
+			msg = "rule failed: " + r.Expr
+		}
+		decision.Add(dto.DecisionReason{RuleID: r.ID, Outcome: r.OnFail, Message: msg})
+	}
+	return decision, nil
+}
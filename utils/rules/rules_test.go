@@ -0,0 +1,57 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestEvaluateReportsFailedRuleIDs(t *testing.T) {
+	rules, err := Parse([]byte(`
+- id: MIN_INCOME
+  expr: mean(map(salary_slips, .net_salary)) > 25000
+  on_fail: reject
+- id: NAME_MATCH
+  expr: cross_check.name_similarity > 0.8
+- id: MISSING_FIELD
+  expr: income_analysis.avg_monthly_income > 25000
+`))
+	require.NoError(t, err)
+
+	eng := &Engine{}
+	eng.Set("acme", rules)
+
+	resp := dto.IncomeVerificationResponse{
+		SalarySlips: []dto.SalarySlipData{{NetSalary: 20000}, {NetSalary: 22000}},
+		CrossCheck:  dto.CrossCheckResult{NameSimilarity: 0.95},
+	}
+	decision, err := eng.Evaluate("acme", resp)
+	require.NoError(t, err)
+
+	assert.Equal(t, dto.DecisionReject, decision.Outcome)
+	require.Len(t, decision.Reasons, 2)
+	assert.Equal(t, "MIN_INCOME", decision.Reasons[0].RuleID)
+	assert.Equal(t, "MISSING_FIELD", decision.Reasons[1].RuleID)
+	assert.Equal(t, dto.DecisionReview, decision.Reasons[1].Outcome)
+
+	decision, err = eng.Evaluate("other-tenant", resp)
+	require.NoError(t, err)
+	assert.Equal(t, dto.DecisionApprove, decision.Outcome)
+}
+
+func TestParseRejectsBadRules(t *testing.T) {
+	_, err := Parse([]byte("- id: X\n  expr: 'cross_check.name_similarity >'\n"))
+	assert.Error(t, err)
+
+	_, err = Parse([]byte("- id: X\n  expr: cross_check.name_match\n  on_fail: approve\n"))
+	assert.Error(t, err)
+}
+
+func TestShippedRulesLoad(t *testing.T) {
+	eng, err := LoadDir("../../rules")
+	require.NoError(t, err)
+	assert.True(t, eng.HasRules("example-tenant"))
+}
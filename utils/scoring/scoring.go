@@ -0,0 +1,223 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"os"
+
+	"github.com/goccy/go-yaml"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Component names, also the keys of a Weights map.
+const (
+	DocumentQuality = "document_quality"
+	CrossCheck      = "cross_check"
+	FraudSignals    = "fraud_signals"
+	IncomeStability = "income_stability"
+)
+
+// Weights maps component name to its relative weight. Weights need not sum to 1.
+type Weights map[string]float64
+
+// DefaultWeights apply to tenants without their own configuration.
+var DefaultWeights = Weights{
+	DocumentQuality: 0.25,
+	CrossCheck:      0.35,
+	FraudSignals:    0.25,
+	IncomeStability: 0.15,
+}
+
+// fraudPenalties are subtracted from 100 for each document carrying the issue.
+var fraudPenalties = map[string]float64{
+	"text_layer_mismatch":             60,
+	"scan_date_precedes_content_date": 40,
+	"stale_document":                  20,
+	dto.FraudBalanceMismatch:          40,
+	dto.FraudDuplicateRow:             30,
+	dto.FraudFontAnomaly:              30,
+	dto.FraudMetadataAnomaly:          20,
+	dto.FraudRoundedSalary:            10,
+}
+
+// Config holds default and per-tenant weights, loaded from YAML:
+//
+//	default:
+//	  document_quality: 0.25
+//	  cross_check: 0.35
+//	  fraud_signals: 0.25
+//	  income_stability: 0.15
+//	tenants:
+//	  acme:
+//	    cross_check: 0.6
+//	    fraud_signals: 0.4
+type Config struct {
+	Default Weights            `yaml:"default"`
+	Tenants map[string]Weights `yaml:"tenants"`
+}
+
+// LoadFile reads weights from path. A missing file yields the default weights.
+func LoadFile(path string) (*Config, error) {
+	cfg := &Config{Default: DefaultWeights, Tenants: map[string]Weights{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read score weights: %w", err)
+	}
+	if err := yaml.UnmarshalWithOptions(data, cfg, yaml.DisallowUnknownField()); err != nil {
+		return nil, fmt.Errorf("invalid score weights: %w", err)
+	}
+
+	if err := validate(cfg.Default); err != nil {
+		return nil, fmt.Errorf("default: %w", err)
+	}
+	for tenant, w := range cfg.Tenants {
+		if err := validate(w); err != nil {
+			return nil, fmt.Errorf("tenant %s: %w", tenant, err)
+		}
+	}
+	return cfg, nil
+}
+
+func validate(w Weights) error {
+	var total float64
+	for name, v := range w {
+		if _, ok := DefaultWeights[name]; !ok {
+			return fmt.Errorf("unknown component %q", name)
+		}
+		if v < 0 {
+			return fmt.Errorf("component %s has a negative weight", name)
+		}
+		total += v
+	}
+	if total == 0 {
+		return fmt.Errorf("at least one component needs a positive weight")
+	}
+	return nil
+}
+
+// WeightsFor returns the tenant's weights, or the defaults.
+func (c *Config) WeightsFor(tenantID string) Weights {
+	if c == nil {
+		return DefaultWeights
+	}
+	if w, ok := c.Tenants[tenantID]; ok {
+		return w
+	}
+	if c.Default != nil {
+		return c.Default
+	}
+	return DefaultWeights
+}
+
+// Score reduces a verification to a single 0–100 number. Components are scored
+// independently on 0–100 and combined by their normalized weights.
+func Score(resp dto.IncomeVerificationResponse, weights Weights) dto.VerificationScore {
+	scores := map[string]float64{
+		DocumentQuality: documentQualityScore(resp),
+		CrossCheck:      crossCheckScore(resp),
+		FraudSignals:    fraudScore(resp),
+		IncomeStability: incomeStabilityScore(resp.SalarySlips),
+	}
+
+	var total float64
+	for _, w := range weights {
+		total += w
+	}
+
+	out := dto.VerificationScore{VerificationID: resp.VerificationID, Components: []dto.ScoreComponent{}}
+	for _, name := range []string{DocumentQuality, CrossCheck, FraudSignals, IncomeStability} {
+		w := weights[name]
+		if total > 0 {
+			w /= total
+		}
+		out.Components = append(out.Components, dto.ScoreComponent{Name: name, Score: round1(scores[name]), Weight: w})
+		out.Score += w * scores[name]
+	}
+	out.Score = round1(out.Score)
+	return out
+}
+
+func documentQualityScore(resp dto.IncomeVerificationResponse) float64 {
+	var sum float64
+	var n int
+	for _, q := range qualities(resp) {
+		sum += q.FinalScore
+		n++
+	}
+	if n == 0 {
+		return 0
+	}
+	return clamp(sum / float64(n))
+}
+
+func crossCheckScore(resp dto.IncomeVerificationResponse) float64 {
+	cc := resp.CrossCheck
+	score := 40 * cc.NameSimilarity
+	if cc.AccountMatch {
+		score += 30
+	}
+	if n := len(resp.SalarySlips); n > 0 {
+		credited := n - len(cc.MissingSalaryCredits)
+		if credited < 0 {
+			credited = 0
+		}
+		score += 30 * float64(credited) / float64(n)
+	}
+	return clamp(score)
+}
+
+func fraudScore(resp dto.IncomeVerificationResponse) float64 {
+	score := 100.0
+	for _, q := range qualities(resp) {
+		for _, issue := range q.Issues {
+			score -= fraudPenalties[issue]
+		}
+	}
+	return clamp(score)
+}
+
+// incomeStabilityScore falls as the coefficient of variation of net salary rises;
+// a single slip gives no evidence either way.
+func incomeStabilityScore(slips []dto.SalarySlipData) float64 {
+	if len(slips) < 2 {
+		return 50
+	}
+	var sum float64
+	for _, s := range slips {
+		sum += s.NetSalary
+	}
+	mean := sum / float64(len(slips))
+	if mean <= 0 {
+		return 0
+	}
+	var variance float64
+	for _, s := range slips {
+		variance += (s.NetSalary - mean) * (s.NetSalary - mean)
+	}
+	cv := math.Sqrt(variance/float64(len(slips))) / mean
+	return clamp(100 * (1 - cv/0.5))
+}
+
+func qualities(resp dto.IncomeVerificationResponse) []dto.DocumentQuality {
+	var out []dto.DocumentQuality
+	for _, s := range resp.SalarySlips {
+		out = append(out, s.Quality)
+	}
+	for _, b := range resp.BankStatements {
+		out = append(out, b.Quality)
+	}
+	return out
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(100, v))
+}
+
+func round1(v float64) float64 {
+	return math.Round(v*10) / 10
+}
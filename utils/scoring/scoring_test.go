@@ -0,0 +1,45 @@
+package scoring
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+func TestScoreWeighsComponents(t *testing.T) {
+	resp := dto.IncomeVerificationResponse{
+		SalarySlips: []dto.SalarySlipData{
+			{NetSalary: 50000, Quality: dto.DocumentQuality{FinalScore: 90}},
+			{NetSalary: 50000, Quality: dto.DocumentQuality{FinalScore: 70}},
+		},
+		BankStatements: []dto.BankStatementData{
+			{Quality: dto.DocumentQuality{FinalScore: 80, Issues: []string{"text_layer_mismatch"}}},
+		},
+		CrossCheck: dto.CrossCheckResult{NameSimilarity: 1, AccountMatch: true},
+	}
+
+	score := Score(resp, DefaultWeights)
+	require.Len(t, score.Components, 4)
+	byName := map[string]float64{}
+	for _, c := range score.Components {
+		byName[c.Name] = c.Score
+	}
+	assert.Equal(t, 80.0, byName[DocumentQuality])
+	assert.Equal(t, 100.0, byName[CrossCheck])
+	assert.Equal(t, 40.0, byName[FraudSignals])
+	assert.Equal(t, 100.0, byName[IncomeStability])
+	assert.Equal(t, 20+35+10+15.0, score.Score)
+
+	// Only fraud signals count for this tenant.
+	assert.Equal(t, 40.0, Score(resp, Weights{FraudSignals: 2}).Score)
+}
+
+func TestShippedWeightsLoad(t *testing.T) {
+	cfg, err := LoadFile("../../scoring.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, 0.4, cfg.WeightsFor("example-tenant")[FraudSignals])
+	assert.Equal(t, DefaultWeights, cfg.WeightsFor("unknown"))
+}
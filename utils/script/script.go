@@ -0,0 +1,104 @@
+// Package script tells which writing system a text is in, so documents in an
+// Indian script can be OCRed with the Tesseract language that reads it.
+package script
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Script names, as in unicode.Scripts and Tesseract's OSD output.
+const (
+	Latin      = "Latin"
+	Devanagari = "Devanagari"
+	Bengali    = "Bengali"
+	Gujarati   = "Gujarati"
+	Gurmukhi   = "Gurmukhi"
+	Kannada    = "Kannada"
+	Malayalam  = "Malayalam"
+	Oriya      = "Oriya"
+	Tamil      = "Tamil"
+	Telugu     = "Telugu"
+)
+
+// languages maps a script to the Tesseract language most Indian documents in
+// it are written in: Devanagari documents are taken for Hindi rather than
+// Marathi or Nepali.
+var languages = map[string]string{
+	Devanagari: "hin",
+	Bengali:    "ben",
+	Gujarati:   "guj",
+	Gurmukhi:   "pan",
+	Kannada:    "kan",
+	Malayalam:  "mal",
+	Oriya:      "ori",
+	Tamil:      "tam",
+	Telugu:     "tel",
+}
+
+// detected are the scripts Detect counts.
+var detected = []string{Latin, Devanagari, Bengali, Gujarati, Gurmukhi, Kannada, Malayalam, Oriya, Tamil, Telugu}
+
+// Result is the dominant script of a text and its share (0-1) of the
+// characters in any script.
+type Result struct {
+	Script string
+	Share  float64
+}
+
+// Detect returns the script most of text's letters and vowel signs are in;
+// digits, punctuation and scripts other than Latin and the Indian ones are
+// not counted. The zero Result means text has none.
+func Detect(text string) Result {
+	counts := make(map[string]int, len(detected))
+	total := 0
+	for _, r := range text {
+		if r < 0x80 {
+			if unicode.IsLetter(r) {
+				counts[Latin]++
+				total++
+			}
+			continue
+		}
+		for _, name := range detected {
+			if unicode.Is(unicode.Scripts[name], r) {
+				counts[name]++
+				total++
+				break
+			}
+		}
+	}
+	var best Result
+	for _, name := range detected {
+		if n := counts[name]; n > 0 && float64(n)/float64(total) > best.Share {
+			best = Result{Script: name, Share: float64(n) / float64(total)}
+		}
+	}
+	return best
+}
+
+// Language is the Tesseract language spec for documents in script, with
+// English for the Latin parts Indian documents always have ("hin+eng"), or ""
+// for Latin and scripts without a language here.
+func Language(script string) string {
+	if lang, ok := languages[script]; ok {
+		return lang + "+eng"
+	}
+	return ""
+}
+
+// Covers reports whether the Tesseract language spec have includes every
+// language of want ("hin+eng" covers "hin" and "eng+hin").
+func Covers(have, want string) bool {
+	langs := strings.Split(have, "+")
+	for _, w := range strings.Split(want, "+") {
+		found := false
+		for _, l := range langs {
+			found = found || l == w
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
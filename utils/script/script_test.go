@@ -0,0 +1,27 @@
+package script
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	r := Detect("भारत सरकार Government of India\nनाम: रवि कुमार")
+	assert.Equal(t, Devanagari, r.Script)
+	assert.Greater(t, r.Share, 0.5)
+
+	assert.Equal(t, Tamil, Detect("இந்திய அரசு பெயர்").Script)
+	assert.Equal(t, Result{Script: Latin, Share: 1}, Detect("NET PAY 45,000.00"))
+	assert.Equal(t, Result{}, Detect("12/08/1990 - 45,000"))
+}
+
+func TestLanguage(t *testing.T) {
+	assert.Equal(t, "hin+eng", Language(Devanagari))
+	assert.Equal(t, "tam+eng", Language(Tamil))
+	assert.Empty(t, Language(Latin))
+	assert.Empty(t, Language("Han"))
+
+	assert.True(t, Covers("eng+hin", "hin+eng"))
+	assert.False(t, Covers("eng", "hin+eng"))
+}
@@ -0,0 +1,252 @@
+// Package secureqr decodes the UIDAI Secure QR printed on Aadhaar cards and
+// e-Aadhaar letters since 2019. The QR holds a base-10 number whose big-endian
+// bytes are a gzip stream of 0xFF-delimited text fields, followed by the JPEG
+// 2000 photo, optional SHA-256 hashes of email/mobile, and a 256-byte
+// RSA-SHA256 signature by UIDAI over everything before it.
+package secureqr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"regexp"
+	"strings"
+)
+
+const (
+	signatureLen = 256
+	hashLen      = 32
+	delimiter    = 0xFF
+)
+
+// Email/mobile indicator values (first field; second after a version marker).
+const (
+	indicatorNone   = "0"
+	indicatorEmail  = "1"
+	indicatorMobile = "2"
+	indicatorBoth   = "3"
+)
+
+// Signature states.
+const (
+	SignatureVerified   = "verified"
+	SignatureUnverified = "unverified" // no UIDAI certificate configured
+	SignatureInvalid    = "invalid"
+)
+
+// ErrNotSecureQR means the QR text is not a Secure QR number.
+var ErrNotSecureQR = errors.New("not an Aadhaar secure QR")
+
+var numericRe = regexp.MustCompile(`^[0-9]{100,}$`)
+
+// Data holds the decoded fields. DOB is DD-MM-YYYY as printed by UIDAI.
+type Data struct {
+	Version     string // "" for the original format, "V2" onwards for later ones
+	ReferenceID string // last 4 Aadhaar digits + generation timestamp
+	Name        string
+	DOB         string
+	Gender      string
+	CareOf      string
+	District    string
+	Landmark    string
+	House       string
+	Location    string
+	Pincode     string
+	PostOffice  string
+	State       string
+	Street      string
+	SubDistrict string
+	VTC         string
+	MobileLast4 string // V2 onwards
+
+	Photo      []byte // JPEG 2000
+	EmailHash  []byte
+	MobileHash []byte
+	Signature  string // verified | unverified | invalid
+}
+
+// Last4 returns the last four Aadhaar digits carried in the reference ID.
+func (d *Data) Last4() string {
+	if len(d.ReferenceID) < 4 {
+		return ""
+	}
+	return d.ReferenceID[:4]
+}
+
+// Address joins the address fields in the order printed on the card.
+func (d *Data) Address() string {
+	var parts []string
+	add := func(prefix, v string) {
+		if v = strings.TrimSpace(v); v != "" {
+			parts = append(parts, prefix+v)
+		}
+	}
+	add("C/O ", d.CareOf)
+	add("", d.House)
+	add("", d.Street)
+	add("", d.Landmark)
+	add("", d.Location)
+	add("", d.VTC)
+	add("PO ", d.PostOffice)
+	add("", d.SubDistrict)
+	add("", d.District)
+	add("", d.State)
+	add("", d.Pincode)
+	return strings.Join(parts, ", ")
+}
+
+// IsSecureQR reports whether QR text looks like a Secure QR number.
+func IsSecureQR(text string) bool {
+	return numericRe.MatchString(strings.TrimSpace(text))
+}
+
+// Decode decodes a Secure QR. With a UIDAI public key the signature is checked
+// and reported in Data.Signature; without one the fields are returned unverified.
+func Decode(text string, uidaiKey *rsa.PublicKey) (*Data, error) {
+	text = strings.TrimSpace(text)
+	if !IsSecureQR(text) {
+		return nil, ErrNotSecureQR
+	}
+	n, ok := new(big.Int).SetString(text, 10)
+	if !ok {
+		return nil, ErrNotSecureQR
+	}
+
+	raw, err := decompress(n.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress secure QR: %w", err)
+	}
+	if len(raw) <= signatureLen {
+		return nil, fmt.Errorf("secure QR payload too short")
+	}
+
+	signed, signature := raw[:len(raw)-signatureLen], raw[len(raw)-signatureLen:]
+	d := &Data{Signature: SignatureUnverified}
+	if uidaiKey != nil {
+		digest := sha256.Sum256(signed)
+		if rsa.VerifyPKCS1v15(uidaiKey, crypto.SHA256, digest[:], signature) == nil {
+			d.Signature = SignatureVerified
+		} else {
+			d.Signature = SignatureInvalid
+		}
+	}
+
+	if err := d.parseFields(signed); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parseFields splits off the text fields; the photo may itself contain 0xFF,
+// so only the known number of delimiters is consumed.
+func (d *Data) parseFields(signed []byte) error {
+	fieldCount := 16
+	if bytes.HasPrefix(signed, []byte("V")) {
+		fieldCount = 18 // version marker in front, last 4 mobile digits at the end
+	}
+
+	fields := make([]string, 0, fieldCount)
+	rest := signed
+	for len(fields) < fieldCount {
+		i := bytes.IndexByte(rest, delimiter)
+		if i < 0 {
+			return fmt.Errorf("secure QR has %d fields, want %d", len(fields), fieldCount)
+		}
+		fields = append(fields, string(rest[:i]))
+		rest = rest[i+1:]
+	}
+
+	if fieldCount == 18 {
+		d.Version = fields[0]
+		d.MobileLast4 = fields[17]
+		fields = fields[1:]
+	}
+
+	indicator := fields[0]
+	d.ReferenceID = fields[1]
+	d.Name = fields[2]
+	d.DOB = fields[3]
+	d.Gender = fields[4]
+	d.CareOf = fields[5]
+	d.District = fields[6]
+	d.Landmark = fields[7]
+	d.House = fields[8]
+	d.Location = fields[9]
+	d.Pincode = fields[10]
+	d.PostOffice = fields[11]
+	d.State = fields[12]
+	d.Street = fields[13]
+	d.SubDistrict = fields[14]
+	d.VTC = fields[15]
+
+	// Hashes sit between the photo and the signature: email first, then mobile.
+	switch indicator {
+	case indicatorBoth:
+		if len(rest) < 2*hashLen {
+			return fmt.Errorf("secure QR hashes truncated")
+		}
+		d.MobileHash = rest[len(rest)-hashLen:]
+		d.EmailHash = rest[len(rest)-2*hashLen : len(rest)-hashLen]
+		rest = rest[:len(rest)-2*hashLen]
+	case indicatorEmail, indicatorMobile:
+		if len(rest) < hashLen {
+			return fmt.Errorf("secure QR hash truncated")
+		}
+		if indicator == indicatorEmail {
+			d.EmailHash = rest[len(rest)-hashLen:]
+		} else {
+			d.MobileHash = rest[len(rest)-hashLen:]
+		}
+		rest = rest[:len(rest)-hashLen]
+	case indicatorNone:
+	default:
+		return fmt.Errorf("unknown secure QR email/mobile indicator %q", indicator)
+	}
+	d.Photo = rest
+	return nil
+}
+
+// decompress accepts gzip (the documented format) and bare zlib (seen on some letters).
+func decompress(data []byte) ([]byte, error) {
+	if r, err := gzip.NewReader(bytes.NewReader(data)); err == nil {
+		defer r.Close()
+		return io.ReadAll(r)
+	}
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+// LoadPublicKey reads the UIDAI signing certificate (PEM or DER) published for
+// offline verification and returns its RSA public key.
+func LoadPublicKey(path string) (*rsa.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read UIDAI certificate: %w", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse UIDAI certificate: %w", err)
+	}
+	key, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("UIDAI certificate does not hold an RSA key")
+	}
+	return key, nil
+}
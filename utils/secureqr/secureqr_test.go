@@ -0,0 +1,90 @@
+package secureqr
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// buildQR produces a Secure QR number the way UIDAI does, signed with key.
+func buildQR(t *testing.T, key *rsa.PrivateKey, fields []string, photo []byte, hashes ...[]byte) string {
+	t.Helper()
+	var payload bytes.Buffer
+	for _, f := range fields {
+		payload.WriteString(f)
+		payload.WriteByte(delimiter)
+	}
+	payload.Write(photo)
+	for _, h := range hashes {
+		payload.Write(h)
+	}
+	digest := sha256.Sum256(payload.Bytes())
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	require.NoError(t, err)
+	payload.Write(sig)
+
+	var gz bytes.Buffer
+	w := gzip.NewWriter(&gz)
+	_, err = w.Write(payload.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return new(big.Int).SetBytes(gz.Bytes()).String()
+}
+
+func TestDecodeV2(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fields := []string{"V2", "2", "123420190301120000000", "Anita Rao", "05-06-1990", "F", "D/O Ramesh Rao",
+		"Bengaluru Urban", "Near Temple", "12", "Indiranagar", "560038", "Indiranagar", "Karnataka", "MG Road",
+		"Bengaluru North", "Bengaluru", "9876"}
+	photo := []byte{0x00, 0x00, 0x00, 0x0c, 0x6a, 0x50, 0xff, 0xff, 0x01} // contains the delimiter byte
+	mobileHash := bytes.Repeat([]byte{0xab}, hashLen)
+	qr := buildQR(t, key, fields, photo, mobileHash)
+
+	assert.True(t, IsSecureQR(qr))
+
+	d, err := Decode(qr, &key.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureVerified, d.Signature)
+	assert.Equal(t, "V2", d.Version)
+	assert.Equal(t, "Anita Rao", d.Name)
+	assert.Equal(t, "05-06-1990", d.DOB)
+	assert.Equal(t, "1234", d.Last4())
+	assert.Equal(t, "9876", d.MobileLast4)
+	assert.Equal(t, photo, d.Photo)
+	assert.Equal(t, mobileHash, d.MobileHash)
+	assert.True(t, strings.HasPrefix(d.Address(), "C/O D/O Ramesh Rao, 12, MG Road"))
+	assert.True(t, strings.HasSuffix(d.Address(), "Karnataka, 560038"))
+}
+
+func TestDecodeSignatureStates(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	other, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	fields := []string{"0", "567820190301120000000", "Ravi Kumar", "01-01-1985", "M", "", "Pune", "", "", "", "411001", "", "Maharashtra", "", "", "Pune"}
+	qr := buildQR(t, key, fields, []byte{0x01, 0x02})
+
+	d, err := Decode(qr, nil)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureUnverified, d.Signature)
+	assert.Equal(t, "Ravi Kumar", d.Name)
+
+	d, err = Decode(qr, &other.PublicKey)
+	require.NoError(t, err)
+	assert.Equal(t, SignatureInvalid, d.Signature)
+
+	_, err = Decode("<PrintLetterBarcodeData uid=\"1\"/>", nil)
+	assert.ErrorIs(t, err, ErrNotSecureQR)
+}
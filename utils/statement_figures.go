@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// =============================
+// STATEMENT KEY FIGURES
+// =============================
+
+// StatementFigures are the headline numbers of a statement page, used to compare
+// two independent readings (text layer vs OCR) of the same page.
+type StatementFigures struct {
+	ClosingBalance   *float64
+	TotalCredits     float64
+	TotalDebits      float64
+	TransactionCount int
+}
+
+var closingBalanceRe = regexp.MustCompile(`(?i)closing\s+balance[^0-9]*([0-9,]+\.\d{2})`)
+
+// ExtractStatementFigures parses text and sums its transactions. The closing
+// balance comes from an explicit "Closing Balance" line, else the running balance
+// of the last transaction.
+func ExtractStatementFigures(text string) StatementFigures {
+	var f StatementFigures
+
+	txs := parseBankTransactions(normalizeLines(text))
+	f.TransactionCount = len(txs)
+	for _, tx := range txs {
+		if tx.IsCredit {
+			f.TotalCredits += tx.Amount
+		} else {
+			f.TotalDebits += tx.Amount
+		}
+	}
+
+	if m := closingBalanceRe.FindStringSubmatch(text); len(m) == 2 {
+		v := mustParseAmount(m[1])
+		f.ClosingBalance = &v
+	} else if len(txs) > 0 && txs[len(txs)-1].Balance != 0 {
+		v := txs[len(txs)-1].Balance
+		f.ClosingBalance = &v
+	}
+	return f
+}
+
+// CompareStatementFigures reports figures that differ by more than tolerance.
+// Totals are only compared when both readings found the same number of rows;
+// otherwise a missed OCR row would look like tampering.
+func CompareStatementFigures(page int, textLayer, ocr StatementFigures, tolerance float64) []dto.FigureMismatch {
+	var out []dto.FigureMismatch
+	differs := func(a, b float64) bool { return math.Abs(a-b) > tolerance }
+
+	if textLayer.ClosingBalance != nil && ocr.ClosingBalance != nil && differs(*textLayer.ClosingBalance, *ocr.ClosingBalance) {
+		out = append(out, dto.FigureMismatch{Page: page, Figure: "closing_balance", TextLayer: *textLayer.ClosingBalance, OCR: *ocr.ClosingBalance})
+	}
+
+	if textLayer.TransactionCount > 0 && textLayer.TransactionCount == ocr.TransactionCount {
+		if differs(textLayer.TotalCredits, ocr.TotalCredits) {
+			out = append(out, dto.FigureMismatch{Page: page, Figure: "total_credits", TextLayer: textLayer.TotalCredits, OCR: ocr.TotalCredits})
+		}
+		if differs(textLayer.TotalDebits, ocr.TotalDebits) {
+			out = append(out, dto.FigureMismatch{Page: page, Figure: "total_debits", TextLayer: textLayer.TotalDebits, OCR: ocr.TotalDebits})
+		}
+	}
+	return out
+}
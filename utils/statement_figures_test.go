@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareStatementFiguresFlagsEditedClosingBalance(t *testing.T) {
+	textLayer := ExtractStatementFigures("Opening Balance 1,000.00\nClosing Balance 98,450.00\n")
+	ocr := ExtractStatementFigures("Opening Balance 1,000.00\nClosing Balance 8,450.00\n")
+
+	require.NotNil(t, textLayer.ClosingBalance)
+	mismatches := CompareStatementFigures(3, textLayer, ocr, 1.0)
+	require.Len(t, mismatches, 1)
+	assert.Equal(t, "closing_balance", mismatches[0].Figure)
+	assert.Equal(t, 3, mismatches[0].Page)
+	assert.Equal(t, 98450.0, mismatches[0].TextLayer)
+	assert.Equal(t, 8450.0, mismatches[0].OCR)
+
+	assert.Empty(t, CompareStatementFigures(3, textLayer, textLayer, 1.0))
+}
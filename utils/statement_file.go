@@ -0,0 +1,369 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/Aashish23092/ocr-income-verification/utils/xlsx"
+)
+
+// ErrNoStatementTable is returned for a statement file without a recognizable
+// transaction table.
+var ErrNoStatementTable = errors.New("no transaction table: no row names a date column and debit/credit or amount columns")
+
+// statementHeaderRows is how far down a statement file its column header
+// row is looked for, below the account details.
+const statementHeaderRows = 50
+
+// StatementFileRows returns the rows of a bank statement downloaded as CSV or
+// XLSX, each cell trimmed.
+func StatementFileRows(data []byte, format filetype.Format) ([][]string, error) {
+	var rows [][]string
+	var err error
+	switch format {
+	case filetype.CSV:
+		rows, err = readCSV(data)
+	case filetype.XLSX:
+		rows, err = xlsx.Rows(data)
+	default:
+		return nil, fmt.Errorf("%s is not a statement file format", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for i := range row {
+			row[i] = strings.TrimSpace(row[i])
+		}
+	}
+	return rows, nil
+}
+
+// readCSV reads CSV separated by commas, semicolons or tabs, whichever the
+// first lines use most. Text that is not UTF-8 is taken for Windows-1252,
+// the way Excel saves CSV on Windows.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	if !utf8.Valid(data) {
+		data = decodeWindows1252(data)
+	}
+
+	head := data
+	if lines := bytes.SplitN(data, []byte("\n"), 11); len(lines) > 10 {
+		head = bytes.Join(lines[:10], []byte("\n"))
+	}
+	sep, most := ',', bytes.Count(head, []byte(","))
+	for _, c := range []rune{';', '\t'} {
+		if n := bytes.Count(head, []byte(string(c))); n > most {
+			sep, most = c, n
+		}
+	}
+
+	r := csv.NewReader(bytes.NewReader(data))
+	r.Comma = sep
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	return rows, nil
+}
+
+// decodeWindows1252 converts Windows-1252 text to UTF-8; the bytes it does
+// not define are read as Latin-1.
+func decodeWindows1252(data []byte) []byte {
+	high := map[byte]rune{
+		0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ', 0x89: '‰',
+		0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ', 0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•',
+		0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›', 0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+	}
+	var b bytes.Buffer
+	for _, c := range data {
+		if r, ok := high[c]; ok {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.Bytes()
+}
+
+// StatementFileText is the text of statement rows, cells separated like the
+// columns of OCR text, for the checks that read text (PII, templates).
+func StatementFileText(rows [][]string) string {
+	var b strings.Builder
+	for _, row := range rows {
+		b.WriteString(strings.TrimRight(strings.Join(row, "  "), " "))
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Roles of statement file columns.
+const (
+	fileColDate = iota
+	fileColValueDate
+	fileColDescription
+	fileColDebit
+	fileColCredit
+	fileColAmount
+	fileColDrCr
+	fileColBalance
+)
+
+// statementFileColumn returns the role a header cell names.
+func statementFileColumn(label string) (int, bool) {
+	h := strings.ToLower(strings.Join(strings.Fields(strings.Trim(label, " .:*")), " "))
+	has := func(words ...string) bool {
+		for _, w := range words {
+			if strings.Contains(h, w) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case h == "":
+		return 0, false
+	case h == "dr/cr" || h == "cr/dr" || h == "dr / cr" || h == "cr / dr" || h == "debit/credit" || h == "type" || h == "txn type" || h == "transaction type":
+		return fileColDrCr, true
+	case has("balance"):
+		return fileColBalance, true
+	case has("withdrawal", "debit") || h == "dr" || strings.HasPrefix(h, "dr "):
+		return fileColDebit, true
+	case has("deposit", "credit") || h == "cr" || strings.HasPrefix(h, "cr "):
+		return fileColCredit, true
+	case has("value date", "value dt"):
+		return fileColValueDate, true
+	case has("date") || h == "dt" || strings.HasSuffix(h, " dt"):
+		return fileColDate, true
+	case has("narration", "description", "particulars", "remarks", "details"):
+		return fileColDescription, true
+	case has("amount"):
+		return fileColAmount, true
+	}
+	return 0, false
+}
+
+// findStatementHeader returns the index of the column header row and the
+// column of each role, the first column of a role winning.
+func findStatementHeader(rows [][]string) (int, map[int]int, bool) {
+	for i, row := range rows {
+		if i >= statementHeaderRows {
+			break
+		}
+		cols := map[int]int{}
+		for j, cell := range row {
+			if role, ok := statementFileColumn(cell); ok {
+				if _, seen := cols[role]; !seen {
+					cols[role] = j
+				}
+			}
+		}
+		if _, ok := cols[fileColDate]; !ok {
+			if vd, ok := cols[fileColValueDate]; ok {
+				cols[fileColDate] = vd
+			}
+		}
+		_, hasDate := cols[fileColDate]
+		_, hasDebit := cols[fileColDebit]
+		_, hasCredit := cols[fileColCredit]
+		_, hasAmount := cols[fileColAmount]
+		if hasDate && (hasDebit || hasCredit || hasAmount) {
+			return i, cols, true
+		}
+	}
+	return 0, nil, false
+}
+
+// ParseStatementRows reads a bank statement downloaded as a spreadsheet: the
+// account details from the rows above the column header, and a transaction
+// per dated row below it, each amount taken from its column. Undated rows
+// continue the narration of the transaction above; opening balance and total
+// rows are skipped.
+func ParseStatementRows(rows [][]string) (dto.BankStatementData, error) {
+	headerRow, cols, ok := findStatementHeader(rows)
+	if !ok {
+		return dto.BankStatementData{}, ErrNoStatementTable
+	}
+
+	// the account details read as the header of an OCRed statement does
+	details := StatementFileText(rows[:headerRow])
+	from, to := extractStatementPeriod(details)
+	data := dto.BankStatementData{
+		AccountNumber:     extractAccountNumber(details),
+		AccountHolderName: extractAccountHolderName(details),
+		IFSC:              ExtractIFSC(details),
+		PeriodFrom:        from,
+		PeriodTo:          to,
+		Address:           extractStatementAddress(strings.Split(details, "\n")),
+	}
+	data.BankName = ExtractBankName(details, data.IFSC)
+
+	cell := func(row []string, role int) string {
+		if j, ok := cols[role]; ok && j < len(row) {
+			return row[j]
+		}
+		return ""
+	}
+	prevBalance, hasBalance := 0.0, false
+	for _, row := range rows[headerRow+1:] {
+		date, ok := parseStatementFileDate(cell(row, fileColDate))
+		if !ok {
+			// a narration wrapped onto the next row
+			if desc := cell(row, fileColDescription); desc != "" && len(data.Transactions) > 0 && !rowHasAmount(row, cols) {
+				tx := &data.Transactions[len(data.Transactions)-1]
+				tx.Description = strings.TrimSpace(tx.Description + " " + desc)
+			}
+			continue
+		}
+
+		tx := dto.BankTransaction{Date: date, Description: cell(row, fileColDescription), RawLine: strings.Join(nonEmpty(row), "  ")}
+		balance, balanceOK := parseStatementFileAmount(cell(row, fileColBalance))
+		debit, _ := parseStatementFileAmount(cell(row, fileColDebit))
+		credit, _ := parseStatementFileAmount(cell(row, fileColCredit))
+		switch {
+		case debit.value != 0:
+			tx.Amount, tx.IsCredit = abs64(debit.value), false
+		case credit.value != 0:
+			tx.Amount, tx.IsCredit = abs64(credit.value), true
+		default:
+			amount, ok := parseStatementFileAmount(cell(row, fileColAmount))
+			if !ok || amount.value == 0 {
+				continue // opening balance, totals
+			}
+			tx.Amount = abs64(amount.value)
+			switch drcr := strings.ToLower(cell(row, fileColDrCr)); {
+			case strings.HasPrefix(drcr, "d"):
+				tx.IsCredit = false
+			case strings.HasPrefix(drcr, "c"):
+				tx.IsCredit = true
+			case amount.marked:
+				tx.IsCredit = amount.credit
+			case amount.value < 0:
+				tx.IsCredit = false
+			case balanceOK && hasBalance:
+				tx.IsCredit = balance.value > prevBalance
+			default:
+				tx.IsCredit = true
+			}
+		}
+		if balanceOK {
+			tx.Balance = balance.value
+			prevBalance, hasBalance = balance.value, true
+		}
+		data.Transactions = append(data.Transactions, tx)
+	}
+	EnrichUPI(data.Transactions)
+	return data, nil
+}
+
+func rowHasAmount(row []string, cols map[int]int) bool {
+	for _, role := range []int{fileColDebit, fileColCredit, fileColAmount, fileColBalance} {
+		if j, ok := cols[role]; ok && j < len(row) {
+			if a, ok := parseStatementFileAmount(row[j]); ok && a.value != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func nonEmpty(row []string) []string {
+	var cells []string
+	for _, c := range row {
+		if c != "" {
+			cells = append(cells, c)
+		}
+	}
+	return cells
+}
+
+func abs64(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
+}
+
+// fileAmount is an amount cell: its value, negative when written with a
+// minus, in parentheses or as Dr, and whether it was marked Cr or Dr.
+type fileAmount struct {
+	value  float64
+	marked bool
+	credit bool
+}
+
+var fileAmountCurrencyRe = regexp.MustCompile(`(?i)^(₹|rs\.?|inr)\s*|\s*(₹|rs\.?|inr)$`)
+
+// parseStatementFileAmount reads amounts as banks export them: "1,23,456.78",
+// "-500", "(500.00)", "2,000.00 Cr", "₹ 450". An empty or "-" cell is not an
+// amount.
+func parseStatementFileAmount(s string) (fileAmount, bool) {
+	s = fileAmountCurrencyRe.ReplaceAllString(strings.TrimSpace(s), "")
+	var a fileAmount
+	upper := strings.ToUpper(s)
+	switch {
+	case strings.HasSuffix(upper, "CR"):
+		a.marked, a.credit = true, true
+		s = s[:len(s)-2]
+	case strings.HasSuffix(upper, "DR"):
+		a.marked = true
+		s = s[:len(s)-2]
+	}
+	s = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(s), "."))
+	negative := false
+	if strings.HasPrefix(s, "(") && strings.HasSuffix(s, ")") {
+		negative, s = true, s[1:len(s)-1]
+	}
+	if strings.HasPrefix(s, "-") {
+		negative, s = true, s[1:]
+	}
+	s = strings.ReplaceAll(strings.ReplaceAll(s, ",", ""), " ", "")
+	if s == "" {
+		return a, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return a, false
+	}
+	if negative || (a.marked && !a.credit) {
+		f = -f
+	}
+	a.value = f
+	return a, true
+}
+
+var (
+	fileDateTimeRe = regexp.MustCompile(`(?i)[T\s]+\d{1,2}:\d{2}(:\d{2}(\.\d+)?)?(\s*[ap]m)?$`)
+
+	statementFileDateLayouts = []string{
+		"2/1/2006", "2/1/06", "2-1-2006", "2-1-06", "2.1.2006", "2006-01-02", "2006/01/02",
+		"2 Jan 2006", "2-Jan-2006", "2/Jan/2006", "2 Jan 06", "2-Jan-06", "2 January 2006", "Jan 2, 2006",
+	}
+)
+
+// parseStatementFileDate reads a transaction date, with or without a time,
+// day first as Indian banks write dates.
+func parseStatementFileDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(fileDateTimeRe.ReplaceAllString(strings.TrimSpace(s), ""))
+	if s == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range statementFileDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil && t.Year() >= 1990 {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
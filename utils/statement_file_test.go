@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Aashish23092/ocr-income-verification/utils/filetype"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseStatementRowsDebitCreditColumns(t *testing.T) {
+	// a netbanking download: account details, then the table
+	csv := "\xef\xbb\xbfHDFC BANK Ltd.,,,,,,\n" +
+		"Account Holder Name : RAVI KUMAR,,,,,,\n" +
+		"Account No : 50100123456789,,,,,,\n" +
+		"IFSC : HDFC0001234,,,,,,\n" +
+		"Statement From : 01/02/2024 To : 29/02/2024,,,,,,\n" +
+		",,,,,,\n" +
+		"Date,Narration,Chq./Ref.No.,Value Dt,Withdrawal Amt.,Deposit Amt.,Closing Balance\n" +
+		"01/02/24,OPENING BALANCE,,,,,\"10,000.00\"\n" +
+		"01/02/24,NEFT CR-ACME TECHNOLOGIES PVT LTD-SALARY FEB,N032240012,01/02/24,,\"74,050.00\",\"84,050.00\"\n" +
+		"03/02/24,UPI-SHARMA KIRANA-sharmakirana@okaxis-,412345678901,03/02/24,450.00,,\"83,600.00\"\n" +
+		",STORES-UPI,,,,,\n" +
+		"05/02/24,ATM WDL 05FEB24 MUMBAI,,05/02/24,\"2,000.00\",,\"81,600.00\"\n" +
+		",Total,,,\"2,450.00\",\"74,050.00\",\n"
+
+	rows, err := StatementFileRows([]byte(csv), filetype.CSV)
+	require.NoError(t, err)
+	data, err := ParseStatementRows(rows)
+	require.NoError(t, err)
+
+	assert.Equal(t, "50100123456789", data.AccountNumber)
+	assert.Equal(t, "HDFC0001234", data.IFSC)
+	require.Len(t, data.Transactions, 3, "opening balance and total rows are not transactions")
+
+	salary := data.Transactions[0]
+	assert.Equal(t, time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), salary.Date)
+	assert.True(t, salary.IsCredit)
+	assert.Equal(t, 74050.0, salary.Amount)
+	assert.Equal(t, 84050.0, salary.Balance)
+
+	upi := data.Transactions[1]
+	assert.False(t, upi.IsCredit)
+	assert.Equal(t, 450.0, upi.Amount)
+	assert.Equal(t, "UPI-SHARMA KIRANA-sharmakirana@okaxis- STORES-UPI", upi.Description, "a wrapped narration continues")
+	require.NotNil(t, upi.UPI)
+	assert.Equal(t, "sharmakirana@okaxis", upi.UPI.VPA)
+
+	assert.Equal(t, 2000.0, data.Transactions[2].Amount)
+}
+
+func TestParseStatementRowsAmountColumn(t *testing.T) {
+	// semicolon-separated, Windows-1252, one amount column with Dr/Cr
+	csv := "Txn Date;Value Date;Description;Amount (INR);Dr / Cr;Balance\r\n" +
+		"15-Jan-2024 10:42:13;15-Jan-2024;Caf\xe9 Coffee Day;(250.00);DR;9,750.00 Cr\r\n" +
+		"31-Jan-2024;31-Jan-2024;SALARY JAN;74,050.00;CR;83,800.00 Cr\r\n" +
+		"02-Feb-2024;02-Feb-2024;EMI HOME LOAN;\"20,000.00 Dr\";;63,800.00\r\n"
+
+	rows, err := StatementFileRows([]byte(csv), filetype.CSV)
+	require.NoError(t, err)
+	data, err := ParseStatementRows(rows)
+	require.NoError(t, err)
+	require.Len(t, data.Transactions, 3)
+
+	assert.Equal(t, "Café Coffee Day", data.Transactions[0].Description)
+	assert.Equal(t, time.Date(2024, 1, 15, 0, 0, 0, 0, time.UTC), data.Transactions[0].Date)
+	assert.False(t, data.Transactions[0].IsCredit)
+	assert.Equal(t, 250.0, data.Transactions[0].Amount)
+	assert.Equal(t, 9750.0, data.Transactions[0].Balance)
+	assert.True(t, data.Transactions[1].IsCredit)
+	assert.False(t, data.Transactions[2].IsCredit, "marked Dr in the amount")
+	assert.Equal(t, 20000.0, data.Transactions[2].Amount)
+}
+
+func TestParseStatementRowsWithoutTable(t *testing.T) {
+	_, err := ParseStatementRows([][]string{{"Name", "Ravi"}, {"Phone", "9876543210"}})
+	assert.ErrorIs(t, err, ErrNoStatementTable)
+}
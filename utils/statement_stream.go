@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	streamPageNumRe = regexp.MustCompile(`(?i)^page\s*\d+(\s*(of|/)\s*\d+)?$`)
+	streamDateRe    = regexp.MustCompile(`^\s*\d{1,2}[/-]\d{1,2}[/-]\d{2,4}`)
+	streamAmountRe  = regexp.MustCompile(`[0-9,]+\.\d{2}`)
+)
+
+// StatementStream parses a bank statement one page at a time, so transactions
+// of a long statement are extracted as each page is OCR'd instead of after the
+// whole document. It applies the same clean-up as MergePageTexts, limited to
+// what is known so far: header/footer lines already seen at the edge of an
+// earlier page are dropped, and a row cut by a page break is stitched back
+// before it is parsed.
+type StatementStream struct {
+	pages   int
+	header  string          // first page: account details and period are printed there
+	edges   map[string]bool // normalized header/footer lines of earlier pages
+	pending string          // last line of the previous page, parsed once the next page shows it is complete
+	table   tableParser     // column header and running balance carry across pages
+	tabular []dto.BankTransaction
+	loose   []dto.BankTransaction
+}
+
+func NewStatementStream() *StatementStream {
+	return &StatementStream{edges: map[string]bool{}}
+}
+
+// Pages returns the number of pages added so far.
+func (s *StatementStream) Pages() int {
+	return s.pages
+}
+
+// AddPage parses the next page's text.
+func (s *StatementStream) AddPage(text string) {
+	const edge = 8 // lines at the top/bottom of a page considered header/footer
+
+	var lines []string
+	for _, l := range normalizeLines(text) {
+		if !streamPageNumRe.MatchString(l) {
+			lines = append(lines, l)
+		}
+	}
+
+	var out []string
+	pageEdges := map[string]bool{}
+	stitched := false
+	for j, l := range lines {
+		key := strings.ToLower(strings.Join(strings.Fields(l), " "))
+		isEdge := j < edge || j >= len(lines)-edge
+		if isEdge {
+			pageEdges[key] = true
+		}
+		if s.pages > 0 && isEdge && s.edges[key] && !streamDateRe.MatchString(l) {
+			continue
+		}
+
+		// Row split across the page break: previous page ended on a dated line
+		// without an amount and this page starts with an undated line.
+		if len(out) == 0 && !stitched && s.pending != "" &&
+			streamDateRe.MatchString(s.pending) && !streamAmountRe.MatchString(s.pending) && !streamDateRe.MatchString(l) {
+			s.pending += " " + l
+			stitched = true
+			continue
+		}
+		out = append(out, l)
+	}
+	for k := range pageEdges {
+		s.edges[k] = true
+	}
+
+	if s.pages == 0 {
+		s.header = text
+	}
+	s.pages++
+
+	if len(out) == 0 {
+		return
+	}
+	if s.pending != "" {
+		out = append([]string{s.pending}, out...)
+	}
+	s.consume(out[:len(out)-1])
+	s.pending = out[len(out)-1]
+}
+
+// Result finishes parsing and returns the statement.
+func (s *StatementStream) Result() dto.BankStatementData {
+	if s.pending != "" {
+		s.consume([]string{s.pending})
+		s.pending = ""
+	}
+
+	from, to := extractStatementPeriod(s.header)
+	data := dto.BankStatementData{
+		AccountNumber:     extractAccountNumber(s.header),
+		AccountHolderName: extractAccountHolderName(s.header),
+		PeriodFrom:        from,
+		PeriodTo:          to,
+		Transactions:      s.tabular,
+	}
+	data.IFSC = ExtractIFSC(s.header)
+	data.BankName = ExtractBankName(s.header, data.IFSC)
+	// Same choice as parseBankTransactions: tabular rows win when there are any
+	if len(data.Transactions) == 0 {
+		data.Transactions = s.loose
+	}
+	EnrichUPI(data.Transactions)
+	return data
+}
+
+func (s *StatementStream) consume(lines []string) {
+	s.tabular = append(s.tabular, s.table.parse(lines)...)
+	s.loose = append(s.loose, parseLooseTransactions(lines)...)
+}
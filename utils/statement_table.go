@@ -0,0 +1,155 @@
+package utils
+
+import (
+	"math"
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// Columns of a bank statement transaction table.
+const (
+	colDebit = iota
+	colCredit
+	colBalance
+)
+
+var (
+	tableAmountRe = regexp.MustCompile(`^[0-9][0-9,]*\.\d{2}(?i:cr|dr)?$`)
+	tableDateRe   = regexp.MustCompile(`^\d{1,2}[/-]\d{1,2}[/-]\d{2,4}$`)
+
+	// header words naming the amount columns
+	tableHeaderWords = map[string]int{
+		"withdrawal": colDebit, "withdrawals": colDebit, "debit": colDebit, "debits": colDebit, "dr": colDebit,
+		"deposit": colCredit, "deposits": colCredit, "credit": colCredit, "credits": colCredit, "cr": colCredit,
+		"balance": colBalance,
+	}
+)
+
+// tableColumn is an amount column found in a header row.
+type tableColumn struct {
+	kind   int
+	x0, x1 int // header label box (page pixels) or rune offsets (text)
+}
+
+// detectTableColumns recognizes a header row naming a balance column and at
+// least one of debit/credit, returning the amount columns left to right.
+func detectTableColumns(labels []string, spans [][2]int) []tableColumn {
+	var cols []tableColumn
+	seen := map[int]bool{}
+	for i, label := range labels {
+		kind, ok := tableHeaderWords[strings.ToLower(strings.Trim(label, ".:()"))]
+		if !ok || seen[kind] {
+			continue
+		}
+		seen[kind] = true
+		cols = append(cols, tableColumn{kind: kind, x0: spans[i][0], x1: spans[i][1]})
+	}
+	if !seen[colBalance] || (!seen[colDebit] && !seen[colCredit]) {
+		return nil
+	}
+	return cols
+}
+
+// nearestColumn assigns an amount ending at x1 to the column whose label's
+// right edge is closest (amounts are right-aligned under their heading).
+func nearestColumn(cols []tableColumn, x1 int) int {
+	best, bestDist := colBalance, math.MaxInt
+	for _, c := range cols {
+		if d := abs(c.x1 - x1); d < bestDist {
+			best, bestDist = c.kind, d
+		}
+	}
+	return best
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
+
+// ParseStatementTable rebuilds the transaction table of a bank statement from
+// OCR word boxes. Column positions come from the header row (Withdrawal /
+// Deposit / Balance, repeated per page or carried over); each amount is put in
+// the column it sits under, so debit and credit are told apart by position
+// instead of keywords. Undated rows continue the previous narration. It returns
+// nil when no such table is found.
+func ParseStatementTable(words []dto.OCRWord) []dto.BankTransaction {
+	var cols []tableColumn
+	var tx []dto.BankTransaction
+
+	for _, line := range LayoutLines(words) {
+		labels := make([]string, len(line))
+		spans := make([][2]int, len(line))
+		for i, w := range line {
+			labels[i] = w.Text
+			spans[i] = [2]int{w.Box.X0, w.Box.X1}
+		}
+		if c := detectTableColumns(labels, spans); c != nil {
+			cols = c
+			continue
+		}
+		if cols == nil {
+			continue
+		}
+
+		row, ok := tableRow(cols, labels, spans)
+		switch {
+		case ok:
+			tx = append(tx, row)
+		case len(tx) > 0 && row.Description != "" && row.Amount == 0 && row.Balance == 0:
+			// wrapped narration of the previous transaction
+			tx[len(tx)-1].Description += " " + row.Description
+		}
+	}
+	EnrichUPI(tx)
+	return tx
+}
+
+// tableRow reads one row given its tokens and their horizontal spans. ok is
+// false for rows without a date or amount (continuations, totals).
+func tableRow(cols []tableColumn, tokens []string, spans [][2]int) (dto.BankTransaction, bool) {
+	var row dto.BankTransaction
+	var desc []string
+	dated := false
+	var debit, credit float64
+
+	for i, tok := range tokens {
+		switch {
+		case !dated && len(desc) == 0 && tableDateRe.MatchString(tok):
+			if d, err := parseDateSmart(tok); err == nil {
+				row.Date = d
+				dated = true
+				continue
+			}
+			desc = append(desc, tok)
+		case tableAmountRe.MatchString(tok):
+			amount := mustParseAmount(tok)
+			switch nearestColumn(cols, spans[i][1]) {
+			case colDebit:
+				debit = amount
+			case colCredit:
+				credit = amount
+			case colBalance:
+				row.Balance = amount
+			}
+		case tableDateRe.MatchString(tok) && len(desc) == 0:
+			// value date column
+		default:
+			desc = append(desc, tok)
+		}
+	}
+
+	row.Description = strings.Join(desc, " ")
+	switch {
+	case credit > 0:
+		row.Amount, row.IsCredit = credit, true
+	case debit > 0:
+		row.Amount = debit
+	}
+	row.RawLine = strings.Join(tokens, " ")
+	return row, dated && row.Amount > 0
+}
@@ -0,0 +1,58 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func cell(text string, x0, x1, y int) dto.OCRWord {
+	return dto.OCRWord{Text: text, Box: dto.BoundingBox{X0: x0, Y0: y, X1: x1, Y1: y + 12}}
+}
+
+func TestParseStatementTableByColumn(t *testing.T) {
+	words := []dto.OCRWord{
+		cell("Date", 10, 50, 100), cell("Narration", 80, 160, 100),
+		cell("Withdrawal", 300, 380, 100), cell("Deposit", 420, 480, 100), cell("Balance", 530, 590, 100),
+
+		cell("01/04/2024", 10, 70, 130), cell("SALARY", 80, 130, 130), cell("ACME", 135, 170, 130),
+		cell("50,000.00", 420, 480, 130), cell("60,000.00", 530, 590, 130),
+
+		// no keyword gives away the direction: only the column does
+		cell("03/04/2024", 10, 70, 160), cell("IMPS/998877", 80, 160, 160),
+		cell("1,500.00", 330, 380, 160), cell("58,500.00", 530, 590, 160),
+		cell("REF", 80, 105, 175), cell("RENT", 110, 140, 175),
+	}
+
+	tx := ParseStatementTable(words)
+	if assert.Len(t, tx, 2) {
+		assert.True(t, tx[0].IsCredit)
+		assert.Equal(t, 50000.0, tx[0].Amount)
+		assert.Equal(t, 60000.0, tx[0].Balance)
+
+		assert.False(t, tx[1].IsCredit)
+		assert.Equal(t, 1500.0, tx[1].Amount)
+		assert.Equal(t, 58500.0, tx[1].Balance)
+		assert.Equal(t, "IMPS/998877 REF RENT", tx[1].Description)
+	}
+}
+
+func TestParseTabularTransactionsBalanceColumn(t *testing.T) {
+	lines := []string{
+		"Date        Narration          Withdrawal   Deposit     Balance",
+		"01/04/2024  SALARY ACME                     50,000.00   60,000.00",
+		"03/04/2024  IMPS/998877 CRED   1,500.00                 58,500.00",
+		"05/04/2024  REFUND 4411                     500.00      59,000.00",
+	}
+
+	tx := parseTabularTransactions(lines)
+	if assert.Len(t, tx, 3) {
+		assert.True(t, tx[0].IsCredit)
+		// "CRED" in the narration would read as a credit by keywords
+		assert.False(t, tx[1].IsCredit)
+		assert.Equal(t, 1500.0, tx[1].Amount)
+		assert.True(t, tx[2].IsCredit)
+		assert.Equal(t, 59000.0, tx[2].Balance)
+	}
+}
@@ -0,0 +1,139 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Devanagari → Latin tables (a simplified Hunterian scheme, as used for Indian names).
+var (
+	devanagariVowels = map[rune]string{
+		'अ': "a", 'आ': "aa", 'इ': "i", 'ई': "ee", 'उ': "u", 'ऊ': "oo", 'ऋ': "ri",
+		'ए': "e", 'ऐ': "ai", 'ओ': "o", 'औ': "au", 'ऑ': "o",
+	}
+	devanagariMatras = map[rune]string{
+		'ा': "aa", 'ि': "i", 'ी': "ee", 'ु': "u", 'ू': "oo", 'ृ': "ri",
+		'े': "e", 'ै': "ai", 'ो': "o", 'ौ': "au", 'ॉ': "o",
+	}
+	devanagariConsonants = map[rune]string{
+		'क': "k", 'ख': "kh", 'ग': "g", 'घ': "gh", 'ङ': "n",
+		'च': "ch", 'छ': "chh", 'ज': "j", 'झ': "jh", 'ञ': "n",
+		'ट': "t", 'ठ': "th", 'ड': "d", 'ढ': "dh", 'ण': "n",
+		'त': "t", 'थ': "th", 'द': "d", 'ध': "dh", 'न': "n",
+		'प': "p", 'फ': "ph", 'ब': "b", 'भ': "bh", 'म': "m",
+		'य': "y", 'र': "r", 'ल': "l", 'व': "v", 'ळ': "l",
+		'श': "sh", 'ष': "sh", 'स': "s", 'ह': "h",
+		// precomposed nukta forms
+		'\u0958': "q", '\u0959': "kh", '\u095A': "g", '\u095B': "z", '\u095C': "r", '\u095D': "rh", '\u095E': "f", '\u095F': "y",
+	}
+	devanagariNuktaForms = map[rune]string{'क': "q", 'ज': "z", 'ड': "r", 'ढ': "rh", 'फ': "f"}
+)
+
+const (
+	devanagariVirama = '्'
+	devanagariNukta  = '़'
+)
+
+// HasDevanagari reports whether s contains Devanagari script.
+func HasDevanagari(s string) bool {
+	for _, r := range s {
+		if unicode.Is(unicode.Devanagari, r) {
+			return true
+		}
+	}
+	return false
+}
+
+// TransliterateDevanagari romanizes Devanagari text ("राहुल शर्मा" → "rahul
+// sharmaa"), leaving other characters unchanged. The inherent vowel of a
+// word-final consonant is dropped, as Hindi pronounces it.
+func TransliterateDevanagari(s string) string {
+	runes := []rune(s)
+	var b strings.Builder
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if cons, ok := devanagariConsonants[r]; ok {
+			if i+1 < len(runes) && runes[i+1] == devanagariNukta {
+				if alt, ok := devanagariNuktaForms[r]; ok {
+					cons = alt
+				}
+				i++
+			}
+			b.WriteString(cons)
+
+			var next rune
+			if i+1 < len(runes) {
+				next = runes[i+1]
+			}
+			switch {
+			case next == devanagariVirama:
+				i++ // dead consonant: no vowel
+			case devanagariMatras[next] != "":
+				b.WriteString(devanagariMatras[next])
+				i++
+			case !isDevanagariLetter(next) && syllables(runes, i) > 1:
+				// word-final schwa deletion
+			default:
+				b.WriteByte('a')
+			}
+			continue
+		}
+
+		switch {
+		case devanagariVowels[r] != "":
+			b.WriteString(devanagariVowels[r])
+		case r == 'ं' || r == 'ँ':
+			b.WriteByte('n')
+		case r == 'ः':
+			b.WriteByte('h')
+		case r == '।' || r == '॥':
+			b.WriteByte('.')
+		case r >= '०' && r <= '९':
+			b.WriteRune('0' + (r - '०'))
+		case r == devanagariNukta || r == devanagariVirama:
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func isDevanagariLetter(r rune) bool {
+	_, cons := devanagariConsonants[r]
+	return cons || devanagariVowels[r] != "" || devanagariMatras[r] != "" ||
+		r == devanagariNukta || r == devanagariVirama || r == 'ं' || r == 'ँ' || r == 'ः'
+}
+
+// syllables counts the vowel-bearing letters of the word ending at runes[end].
+func syllables(runes []rune, end int) int {
+	n := 0
+	for i := end; i >= 0 && isDevanagariLetter(runes[i]); i-- {
+		if _, ok := devanagariConsonants[runes[i]]; ok && (i+1 >= len(runes) || runes[i+1] != devanagariVirama) {
+			n++
+		} else if devanagariVowels[runes[i]] != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// RomanizeName turns a name in Devanagari or Latin script into a lower-case
+// phonetic key, so "राहुल शर्मा", "Rahul Sharma" and "RAAHUL SHARMA" compare
+// equal: long vowels and doubled letters are folded, v/w unified, and "ngh"
+// folded to "nh" (सिंह is written Singh).
+func RomanizeName(name string) string {
+	s := strings.ToLower(TransliterateDevanagari(name))
+	s = strings.NewReplacer("aa", "a", "ee", "i", "oo", "u", "w", "v", "ph", "f", "ngh", "nh").Replace(s)
+
+	var b strings.Builder
+	var prev rune
+	for _, r := range s {
+		if r == prev && unicode.IsLetter(r) {
+			continue
+		}
+		b.WriteRune(r)
+		prev = r
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
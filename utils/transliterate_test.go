@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTransliterateDevanagari(t *testing.T) {
+	assert.Equal(t, "raahul sharmaa", TransliterateDevanagari("राहुल शर्मा"))
+	assert.Equal(t, "ramesh kumaar", TransliterateDevanagari("रमेश कुमार"))
+	assert.Equal(t, "DOB: 01/01/1990", TransliterateDevanagari("DOB: ०१/०१/१९९०"))
+}
+
+func TestRomanizedNamesMatch(t *testing.T) {
+	assert.Equal(t, RomanizeName("Rahul Sharma"), RomanizeName("राहुल शर्मा"))
+	assert.Equal(t, RomanizeName("SUNITA DEVI"), RomanizeName("सुनीता देवी"))
+	assert.True(t, CompareNames("राहुल शर्मा", "RAHUL SHARMA"))
+	assert.Greater(t, CalculateNameSimilarity("अजय सिंह", "Ajay Singh"), 0.9)
+}
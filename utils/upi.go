@@ -0,0 +1,103 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+var (
+	upiMarkerRe = regexp.MustCompile(`(?i)(^|[^A-Z])UPI([^A-Z]|$)`)
+	upiRefRe    = regexp.MustCompile(`^[0-9]{12}$`)
+	upiNameRe   = regexp.MustCompile(`^[A-Za-z][A-Za-z .&']{2,}$`)
+
+	// Words of UPI descriptions that are not the counterparty: direction and
+	// flow codes, app names and the payment-app handles banks print without
+	// the user part of the VPA.
+	upiNoise = map[string]bool{
+		"UPI": true, "CR": true, "DR": true, "P2A": true, "P2M": true, "P2P": true, "IN": true, "OUT": true,
+		"PAYMENT": true, "PAYMENT FROM PHONEPE": true, "PAYMENT FROM PHONE": true, "SENT USING PAYTM UPI": true,
+		"NA": true, "NULL": true, "PAY": true, "COLLECT": true, "REQUEST": true, "TRANSFER": true,
+		"GPAY": true, "PHONEPE": true, "PAYTM": true, "BHIM": true, "AMAZON PAY": true, "CRED": true,
+		"YBL": true, "IBL": true, "AXL": true, "APL": true, "OKAXIS": true, "OKSBI": true, "OKICICI": true,
+		"OKHDFCBANK": true, "PTYES": true, "PTSBI": true, "PTAXIS": true, "PTHDFC": true,
+	}
+)
+
+// ParseUPI reads the counterparty, VPA and reference number out of a UPI
+// transaction description, in the layouts banks print them:
+//
+//	UPI/CR/412345678901/RAVI KUMAR/okaxis/Rent
+//	UPI-RAVI KUMAR-ravi.k@okaxis-HDFC0001234-412345678901-RENT
+//	UPI/412345678901/Payment from PhonePe/ravi@ybl/AXIS BANK
+//
+// It returns nil when the description is not a UPI transaction.
+func ParseUPI(description string) *dto.UPIDetails {
+	if !upiMarkerRe.MatchString(description) {
+		return nil
+	}
+	sep := "/"
+	if !strings.Contains(description, "/") {
+		sep = "-"
+	}
+
+	upi := &dto.UPIDetails{}
+	for _, tok := range strings.Split(description, sep) {
+		tok = strings.TrimSpace(tok)
+		switch {
+		case strings.Contains(tok, "@"):
+			if upi.VPA == "" {
+				upi.VPA = strings.ToLower(tok)
+			}
+		case upiRefRe.MatchString(tok):
+			if upi.Reference == "" {
+				upi.Reference = tok
+			}
+		case upi.Counterparty == "" && upiNameRe.MatchString(tok):
+			upper := strings.ToUpper(tok)
+			if upiNoise[upper] || strings.HasSuffix(upper, "BANK") || ifscBanks[upper] != "" {
+				continue
+			}
+			upi.Counterparty = strings.Join(strings.Fields(upper), " ")
+		}
+	}
+	if *upi == (dto.UPIDetails{}) {
+		return nil
+	}
+	return upi
+}
+
+// EnrichUPI sets the UPI details of every UPI transaction in txs.
+func EnrichUPI(txs []dto.BankTransaction) {
+	for i := range txs {
+		txs[i].UPI = ParseUPI(txs[i].Description)
+	}
+}
+
+// partySuffixes say what kind of entity a name is, not which one.
+var partySuffixes = map[string]bool{
+	"PVT": true, "PRIVATE": true, "LTD": true, "LIMITED": true, "LLP": true, "INC": true,
+	"CO": true, "COMPANY": true, "CORP": true, "CORPORATION": true, "INDIA": true, "THE": true,
+	"MR": true, "MRS": true, "MS": true, "SHRI": true, "SMT": true,
+}
+
+// SameParty reports whether two names (people or companies, as printed on
+// payslips and in transaction descriptions) share a distinctive word, e.g.
+// "ACME SOFTWARE PRIVATE LIMITED" and "ACME SOFTWARE PVT".
+func SameParty(a, b string) bool {
+	words := map[string]bool{}
+	for _, w := range strings.FieldsFunc(strings.ToUpper(a), notLetter) {
+		if len(w) >= 3 && !partySuffixes[w] {
+			words[w] = true
+		}
+	}
+	for _, w := range strings.FieldsFunc(strings.ToUpper(b), notLetter) {
+		if words[w] {
+			return true
+		}
+	}
+	return false
+}
+
+func notLetter(r rune) bool { return r < 'A' || r > 'Z' }
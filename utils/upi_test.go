@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUPI(t *testing.T) {
+	assert.Equal(t, &dto.UPIDetails{Reference: "412345678901", Counterparty: "RAVI KUMAR"},
+		ParseUPI("UPI/CR/412345678901/RAVI KUMAR/okaxis/Rent"))
+	assert.Equal(t, &dto.UPIDetails{VPA: "ravi.k@okaxis", Counterparty: "RAVI KUMAR", Reference: "412345678901"},
+		ParseUPI("UPI-RAVI KUMAR-ravi.k@okaxis-HDFC0001234-412345678901-RENT"))
+	assert.Equal(t, &dto.UPIDetails{VPA: "payroll@icici", Counterparty: "ACME SOFTWARE PVT LTD", Reference: "512345678901"},
+		ParseUPI("UPI/512345678901/ACME SOFTWARE PVT LTD/payroll@icici/ICICI BANK/Salary"))
+	assert.Nil(t, ParseUPI("NEFT-HDFC0001234-ACME SOFTWARE PVT LTD-SALARY OCT"))
+}
+
+func TestSameParty(t *testing.T) {
+	assert.True(t, SameParty("ACME SOFTWARE PRIVATE LIMITED", "ACME SOFTWARE PVT"))
+	assert.False(t, SameParty("RAVI KUMAR", "ACME SOFTWARE PVT LTD"))
+}
@@ -0,0 +1,118 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+)
+
+// billProviders maps words printed on bills to the provider and bill type,
+// checked in order.
+var billProviders = []struct {
+	pattern  *regexp.Regexp
+	provider string
+	billType string
+}{
+	{regexp.MustCompile(`\bBESCOM\b`), "BESCOM", dto.BillElectricity},
+	{regexp.MustCompile(`\bMSEDCL\b|MAHAVITARAN`), "MSEDCL", dto.BillElectricity},
+	{regexp.MustCompile(`\bTANGEDCO\b|\bTNEB\b`), "TANGEDCO", dto.BillElectricity},
+	{regexp.MustCompile(`\bBSES\b`), "BSES", dto.BillElectricity},
+	{regexp.MustCompile(`TATA POWER`), "Tata Power", dto.BillElectricity},
+	{regexp.MustCompile(`ADANI ELECTRICITY`), "Adani Electricity", dto.BillElectricity},
+	{regexp.MustCompile(`\bTSSPDCL\b|\bAPSPDCL\b|\bCESC\b|\bUPPCL\b|\bPSPCL\b|\bKSEB\b`), "", dto.BillElectricity},
+	{regexp.MustCompile(`\bAIRTEL\b`), "Airtel", dto.BillTelecom},
+	{regexp.MustCompile(`\bJIO\b`), "Jio", dto.BillTelecom},
+	{regexp.MustCompile(`VODAFONE|\bVI POSTPAID\b`), "Vi", dto.BillTelecom},
+	{regexp.MustCompile(`\bBSNL\b`), "BSNL", dto.BillTelecom},
+	{regexp.MustCompile(`ACT FIBERNET`), "ACT Fibernet", dto.BillTelecom},
+	{regexp.MustCompile(`MAHANAGAR GAS|\bMGL\b`), "Mahanagar Gas", dto.BillGas},
+	{regexp.MustCompile(`INDRAPRASTHA GAS|\bIGL\b`), "Indraprastha Gas", dto.BillGas},
+	{regexp.MustCompile(`GUJARAT GAS`), "Gujarat Gas", dto.BillGas},
+	{regexp.MustCompile(`INDANE|BHARAT ?GAS|\bHP ?GAS\b`), "", dto.BillGas},
+}
+
+var (
+	billTypeWords = []struct {
+		pattern  *regexp.Regexp
+		billType string
+	}{
+		{regexp.MustCompile(`ELECTRICITY|\bKWH\b|UNITS CONSUMED|METER READING`), dto.BillElectricity},
+		{regexp.MustCompile(`POSTPAID|BROADBAND|TELEPHONE|LANDLINE|MOBILE (BILL|SERVICES)`), dto.BillTelecom},
+		{regexp.MustCompile(`\bPNG\b|PIPED (NATURAL )?GAS|\bSCM\b|\bLPG\b`), dto.BillGas},
+	}
+
+	billNameLabel    = regexp.MustCompile(`(?i)^(?:consumer|customer|subscriber|account)?\s*name\s*[:\-]?\s*(?:mr\.?|mrs\.?|ms\.?|shri|smt\.?)?\s*([A-Za-z][A-Za-z .']{2,60})$`)
+	billNumberLabel  = regexp.MustCompile(`(?i)\b(?:consumer|customer|account|ca|bp|k|service|relationship|connection)\s*(?:no\.?|number|id)\s*[:\-]?\s*([A-Z0-9][A-Z0-9\-/]{4,20})\b`)
+	billAddressLabel = regexp.MustCompile(`(?i)^(?:service|billing|installation|supply|premises)?\s*address\s*[:\-]?\s*(.*)$`)
+	billDateLabel    = regexp.MustCompile(`(?i)\b(?:bill(?:ing)?\s*(?:issue\s*)?date|date\s*of\s*bill|invoice\s*date|statement\s*date)\s*[:\-]?\s*` + rentDate)
+	billAmountLabel  = regexp.MustCompile(`(?i)\b(?:total\s*amount\s*(?:due|payable)|net\s*amount\s*payable|amount\s*payable|amount\s*due|total\s*due|bill\s*amount|payable\s*amount)\s*(?:\(.*?\))?\s*[:\-]?\s*(?:rs\.?|inr|₹)?\s*([0-9][0-9,]*(?:\.[0-9]{1,2})?)`)
+	billLabelLine    = regexp.MustCompile(`^[A-Za-z .()/]{2,30}\s*:`)
+	billPinLine      = regexp.MustCompile(`\b[1-8]\d{2}\s?\d{3}\b`)
+)
+
+// ParseUtilityBill reads the consumer name and number, service address, bill
+// date and amount payable from an electricity, telecom or gas bill.
+func ParseUtilityBill(text string) dto.AddressProofResponse {
+	lines := splitAndTrimLines(text)
+	upper := strings.ToUpper(text)
+
+	var res dto.AddressProofResponse
+	for _, p := range billProviders {
+		if p.pattern.MatchString(upper) {
+			res.Provider, res.BillType = p.provider, p.billType
+			break
+		}
+	}
+	if res.BillType == "" {
+		for _, w := range billTypeWords {
+			if w.pattern.MatchString(upper) {
+				res.BillType = w.billType
+				break
+			}
+		}
+	}
+
+	for i, l := range lines {
+		if m := billNameLabel.FindStringSubmatch(l); m != nil && res.ConsumerName == "" {
+			res.ConsumerName = strings.TrimSpace(m[1])
+		}
+		if m := billNumberLabel.FindStringSubmatch(l); m != nil && res.ConsumerNumber == "" {
+			res.ConsumerNumber = m[1]
+		}
+		if m := billAddressLabel.FindStringSubmatch(l); m != nil && res.Address == "" {
+			res.Address = billAddress(m[1], lines[i+1:])
+		}
+		if m := billDateLabel.FindStringSubmatch(l); m != nil && res.BillDate == "" {
+			if t, ok := parseDMYDate(m[1]); ok {
+				res.BillDate = t.Format("2006-01-02")
+			}
+		}
+		if m := billAmountLabel.FindStringSubmatch(l); m != nil && res.Amount == 0 {
+			res.Amount = mustParseAmount(m[1])
+		}
+	}
+	return res
+}
+
+// billAddress joins the address after its label with the lines below it, up
+// to the line with the PIN code or the next labelled line, at most five lines.
+func billAddress(first string, next []string) string {
+	parts := []string{}
+	if first = strings.Trim(first, " ,"); first != "" {
+		parts = append(parts, first)
+		if billPinLine.MatchString(first) {
+			return first
+		}
+	}
+	for _, l := range next {
+		if len(parts) == 5 || billLabelLine.MatchString(l) {
+			break
+		}
+		parts = append(parts, strings.Trim(l, " ,"))
+		if billPinLine.MatchString(l) {
+			break
+		}
+	}
+	return strings.Join(parts, ", ")
+}
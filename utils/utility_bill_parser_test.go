@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/Aashish23092/ocr-income-verification/dto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUtilityBillElectricity(t *testing.T) {
+	text := `BANGALORE ELECTRICITY SUPPLY COMPANY LIMITED (BESCOM)
+Electricity Bill
+Account ID: 7362519804
+Name: Ravi Kumar
+Address: No 12, 4th Cross,
+HSR Layout Sector 2,
+Bengaluru 560102
+Bill Date: 05-10-2025
+Units Consumed 214 KWH
+Net Amount Payable: Rs. 1,842.00`
+
+	bill := ParseUtilityBill(text)
+	assert.Equal(t, dto.BillElectricity, bill.BillType)
+	assert.Equal(t, "BESCOM", bill.Provider)
+	assert.Equal(t, "Ravi Kumar", bill.ConsumerName)
+	assert.Equal(t, "7362519804", bill.ConsumerNumber)
+	assert.Equal(t, "No 12, 4th Cross, HSR Layout Sector 2, Bengaluru 560102", bill.Address)
+	assert.Equal(t, "2025-10-05", bill.BillDate)
+	assert.Equal(t, 1842.0, bill.Amount)
+}
+
+func TestParseUtilityBillTelecom(t *testing.T) {
+	text := `Airtel Postpaid Bill
+Customer Name: Priya Nair
+Relationship No: 1-98765432
+Billing Address: Flat 4B, Lake View Apartments, Kakkanad, Kochi, Kerala 682030
+Bill date 12 Sep 2025
+Amount Due ₹ 799.00`
+
+	bill := ParseUtilityBill(text)
+	assert.Equal(t, dto.BillTelecom, bill.BillType)
+	assert.Equal(t, "Airtel", bill.Provider)
+	assert.Equal(t, "Priya Nair", bill.ConsumerName)
+	assert.Equal(t, "1-98765432", bill.ConsumerNumber)
+	assert.Equal(t, "Flat 4B, Lake View Apartments, Kakkanad, Kochi, Kerala 682030", bill.Address)
+	assert.Equal(t, "2025-09-12", bill.BillDate)
+	assert.Equal(t, 799.0, bill.Amount)
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerhoeffValid(t *testing.T) {
+	assert.True(t, VerhoeffValid("2363"))
+	assert.False(t, VerhoeffValid("2364"))
+	assert.True(t, ValidAadhaar("626079518316"))
+	assert.False(t, ValidAadhaar("626079518361"), "adjacent digits swapped")
+	assert.False(t, ValidAadhaar("62607951831"), "11 digits")
+}
+
+func TestParseAadhaarNumberChecksum(t *testing.T) {
+	// the VID's groups come last; the checksum picks the Aadhaar number
+	text := `Your Aadhaar No. :
+6260 7951 8316
+VID : 9134 5678 9012 3456
+Issued 2019`
+	res := ParseAadhaarFromText(text)
+	assert.Equal(t, "626079518316", res.AadhaarNumber)
+	assert.Equal(t, "8316", res.AadhaarLast4)
+
+	// an OCR-mangled number is not reported, though its last group is kept
+	res = ParseAadhaarFromText("Aadhaar No: 6260 7951 8361\nDownload Date: 2021")
+	assert.Empty(t, res.AadhaarNumber)
+	assert.Equal(t, "8361", res.AadhaarLast4)
+
+	// a valid number beats an earlier mangled one
+	res = ParseAadhaarFromText("4918 3746 5260\n4918 3746 5206")
+	assert.Equal(t, "491837465206", res.AadhaarNumber)
+	assert.Equal(t, "5206", res.AadhaarLast4)
+}
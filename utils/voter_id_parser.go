@@ -0,0 +1,134 @@
+package utils
+
+import (
+	"regexp"
+	"strings"
+)
+
+type VoterIDParsed struct {
+	EPICNumber   string
+	Name         string
+	RelationType string
+	RelationName string
+	Address      string
+	RawText      string
+}
+
+var (
+	// Current EPIC numbers: three letters and seven digits (ABC1234567).
+	epicRegex = regexp.MustCompile(`\b([A-Z]{3}[0-9]{7})\b`)
+	// Older state-issued cards: AP/12/123/123456.
+	legacyEPICRegex = regexp.MustCompile(`\b([A-Z]{2}/[0-9]{2}/[0-9]{3}/[0-9]{6,7})\b`)
+
+	voterLabelRegex = regexp.MustCompile(`^([A-Z' ]+?)\s*[:\-]\s*(.*)$`)
+	voterLabelWords = []string{"NAME", "ADDRESS", "SEX", "GENDER", "BIRTH", "AGE"}
+	pincodeRegex    = regexp.MustCompile(`\b[1-9][0-9]{5}\b`)
+)
+
+// voterRelationLabels maps the relation label printed on the card to RelationType.
+var voterRelationLabels = []struct {
+	label, relation string
+}{
+	{"FATHER", "father"},
+	{"HUSBAND", "husband"},
+	{"MOTHER", "mother"},
+	{"RELATION", "relation"},
+}
+
+// ParseVoterIDText extracts EPIC card fields from OCR text of the front and/or back side.
+func ParseVoterIDText(raw string) VoterIDParsed {
+	t := strings.ToUpper(raw)
+	res := VoterIDParsed{RawText: raw}
+
+	if m := epicRegex.FindStringSubmatch(t); len(m) > 1 {
+		res.EPICNumber = m[1]
+	} else if m := legacyEPICRegex.FindStringSubmatch(t); len(m) > 1 {
+		res.EPICNumber = m[1]
+	}
+
+	lines := splitAndTrimLines(t)
+	for i, l := range lines {
+		label, inline := splitVoterLabel(l)
+		if label == "" {
+			continue
+		}
+		value := inline
+		if value == "" && i+1 < len(lines) {
+			value = lines[i+1]
+		}
+
+		switch {
+		case strings.Contains(label, "NAME") && res.RelationName == "" && voterRelation(label) != "":
+			res.RelationType = voterRelation(label)
+			res.RelationName = cleanVoterName(value)
+		case strings.Contains(label, "NAME") && res.Name == "" && voterRelation(label) == "":
+			res.Name = cleanVoterName(value)
+		case strings.Contains(label, "ADDRESS") && res.Address == "":
+			res.Address = collectVoterAddress(inline, lines[i+1:])
+		}
+	}
+
+	return res
+}
+
+// splitVoterLabel splits "ELECTOR'S NAME : RAHUL" into its label and value.
+// A line that is just a label ("NAME") returns an empty value; lines that are
+// not one of the card's labels return an empty label.
+func splitVoterLabel(line string) (string, string) {
+	label, value := line, ""
+	if m := voterLabelRegex.FindStringSubmatch(line); len(m) > 2 {
+		label, value = strings.TrimSpace(m[1]), strings.TrimSpace(m[2])
+	}
+	for _, w := range voterLabelWords {
+		if strings.HasSuffix(label, w) || (value != "" && strings.Contains(label, w)) {
+			return label, value
+		}
+	}
+	return "", ""
+}
+
+func voterRelation(label string) string {
+	for _, r := range voterRelationLabels {
+		if strings.Contains(label, r.label) {
+			return r.relation
+		}
+	}
+	return ""
+}
+
+func cleanVoterName(s string) string {
+	s = strings.TrimSpace(s)
+	if !isNameLike(s) {
+		return ""
+	}
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// collectVoterAddress joins the address value with its continuation lines,
+// stopping at the PIN code, the next label, or after four lines.
+func collectVoterAddress(first string, rest []string) string {
+	parts := []string{}
+	if first != "" {
+		parts = append(parts, first)
+		if pincodeRegex.MatchString(first) {
+			return first
+		}
+	}
+
+	for _, l := range rest {
+		if len(parts) >= 4 {
+			break
+		}
+		if label, _ := splitVoterLabel(l); label != "" {
+			break
+		}
+		if strings.Contains(l, "ELECTORAL REGISTRATION OFFICER") || strings.HasPrefix(l, "DATE") {
+			break
+		}
+		parts = append(parts, l)
+		if pincodeRegex.MatchString(l) {
+			break
+		}
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,42 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVoterIDText(t *testing.T) {
+	text := `
+		ELECTION COMMISSION OF INDIA
+		IDENTITY CARD
+		ABC1234567
+		Elector's Name : Rahul Sharma
+		Father's Name : Suresh Sharma
+		Sex : Male
+		Date of Birth : 01/01/1990
+		Address :
+		H.No 12, MG Road
+		Indiranagar, Bengaluru 560038
+		Electoral Registration Officer
+	`
+
+	res := ParseVoterIDText(text)
+
+	assert.Equal(t, "ABC1234567", res.EPICNumber)
+	assert.Equal(t, "RAHUL SHARMA", res.Name)
+	assert.Equal(t, "father", res.RelationType)
+	assert.Equal(t, "SURESH SHARMA", res.RelationName)
+	assert.Equal(t, "H.NO 12, MG ROAD, INDIRANAGAR, BENGALURU 560038", res.Address)
+}
+
+func TestParseVoterIDTextLabelsOnOwnLine(t *testing.T) {
+	text := "XYZ7654321\nName\nPriya Verma\nHusband's Name\nAmit Verma\n"
+
+	res := ParseVoterIDText(text)
+
+	assert.Equal(t, "XYZ7654321", res.EPICNumber)
+	assert.Equal(t, "PRIYA VERMA", res.Name)
+	assert.Equal(t, "husband", res.RelationType)
+	assert.Equal(t, "AMIT VERMA", res.RelationName)
+}
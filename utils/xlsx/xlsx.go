@@ -0,0 +1,340 @@
+// Package xlsx reads the cell values of an Office Open XML workbook (.xlsx),
+// as banks let customers download their statements. Only values are read:
+// formulas as their cached results, dates as "2006-01-02", other numbers as
+// Excel stores them; formatting, merged cells and charts are ignored.
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrNotWorkbook is returned for data that is not an XLSX workbook.
+var ErrNotWorkbook = errors.New("not an XLSX workbook")
+
+// maxPartSize bounds how far one part of the workbook may expand, against
+// ZIP bombs.
+const maxPartSize = 64 << 20
+
+// maxColumns and maxCells bound the rows read from a worksheet, cells
+// padding a row up to its last one included: a statement is far smaller,
+// and a few bytes of XML can name a cell thousands of columns out. Cells
+// beyond maxColumns are dropped.
+const (
+	maxColumns = 256
+	maxCells   = 1 << 20
+)
+
+// IsWorkbook reports whether the ZIP archive in r is an XLSX workbook.
+func IsWorkbook(r io.ReaderAt, size int64) bool {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return false
+	}
+	for _, f := range zr.File {
+		if f.Name == "xl/workbook.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// Rows returns the non-empty rows of the first worksheet, top to bottom,
+// each cell as text; missing cells are "".
+func Rows(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, ErrNotWorkbook
+	}
+	files := map[string]*zip.File{}
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+	if files["xl/workbook.xml"] == nil {
+		return nil, ErrNotWorkbook
+	}
+
+	sheet, err := firstSheet(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared []string
+	if f := files["xl/sharedStrings.xml"]; f != nil {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	var dateStyles []bool
+	if f := files["xl/styles.xml"]; f != nil {
+		if dateStyles, err = readDateStyles(f); err != nil {
+			return nil, err
+		}
+	}
+	return readSheet(sheet, shared, dateStyles, workbookDate1904(files["xl/workbook.xml"]))
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, maxPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("%s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// firstSheet finds the first worksheet of the workbook through its
+// relationships, falling back to sheet1.xml.
+func firstSheet(files map[string]*zip.File) (*zip.File, error) {
+	var wb struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Rels []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodePart(files["xl/workbook.xml"], &wb); err != nil {
+		return nil, err
+	}
+	if f := files["xl/_rels/workbook.xml.rels"]; f != nil && len(wb.Sheets) > 0 {
+		if err := decodePart(f, &rels); err != nil {
+			return nil, err
+		}
+		for _, r := range rels.Rels {
+			if r.ID != wb.Sheets[0].RID {
+				continue
+			}
+			name := path.Join("xl", r.Target)
+			if strings.HasPrefix(r.Target, "/") {
+				name = strings.TrimPrefix(r.Target, "/")
+			}
+			if f := files[name]; f != nil {
+				return f, nil
+			}
+		}
+	}
+	if f := files["xl/worksheets/sheet1.xml"]; f != nil {
+		return f, nil
+	}
+	return nil, errors.New("workbook has no worksheet")
+}
+
+// richText is a string item: plain text or runs of formatted text.
+type richText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (s richText) String() string {
+	if len(s.Runs) == 0 {
+		return s.T
+	}
+	var b strings.Builder
+	for _, r := range s.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []richText `xml:"si"`
+	}
+	if err := decodePart(f, &sst); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(sst.Items))
+	for i, si := range sst.Items {
+		shared[i] = si.String()
+	}
+	return shared, nil
+}
+
+// readDateStyles returns, per cell style index, whether its number format
+// shows a date.
+func readDateStyles(f *zip.File) ([]bool, error) {
+	var styles struct {
+		NumFmts []struct {
+			ID   int    `xml:"numFmtId,attr"`
+			Code string `xml:"formatCode,attr"`
+		} `xml:"numFmts>numFmt"`
+		Xfs []struct {
+			NumFmtID int `xml:"numFmtId,attr"`
+		} `xml:"cellXfs>xf"`
+	}
+	if err := decodePart(f, &styles); err != nil {
+		return nil, err
+	}
+	custom := map[int]bool{}
+	for _, nf := range styles.NumFmts {
+		custom[nf.ID] = isDateFormat(nf.Code)
+	}
+	dates := make([]bool, len(styles.Xfs))
+	for i, xf := range styles.Xfs {
+		if isDate, ok := custom[xf.NumFmtID]; ok {
+			dates[i] = isDate
+		} else {
+			// built-in date and date-time formats
+			dates[i] = (xf.NumFmtID >= 14 && xf.NumFmtID <= 22) || (xf.NumFmtID >= 45 && xf.NumFmtID <= 47)
+		}
+	}
+	return dates, nil
+}
+
+// isDateFormat reports whether a custom number format shows a date: it has
+// a day, month or year outside quoted text, brackets and escapes.
+func isDateFormat(code string) bool {
+	inQuote, inBracket := false, false
+	for i := 0; i < len(code); i++ {
+		switch c := code[i]; {
+		case c == '"':
+			inQuote = !inQuote
+		case inQuote:
+		case c == '[':
+			inBracket = true
+		case c == ']':
+			inBracket = false
+		case inBracket:
+		case c == '\\' || c == '_' || c == '*':
+			i++
+		case strings.ContainsRune("dDyY", rune(c)):
+			return true
+		}
+	}
+	return false
+}
+
+func workbookDate1904(f *zip.File) bool {
+	var wb struct {
+		Pr struct {
+			Date1904 string `xml:"date1904,attr"`
+		} `xml:"workbookPr"`
+	}
+	if decodePart(f, &wb) != nil {
+		return false
+	}
+	return wb.Pr.Date1904 == "1" || wb.Pr.Date1904 == "true"
+}
+
+type cell struct {
+	Ref    string   `xml:"r,attr"`
+	Type   string   `xml:"t,attr"`
+	Style  int      `xml:"s,attr"`
+	Value  string   `xml:"v"`
+	Inline richText `xml:"is"`
+}
+
+func readSheet(f *zip.File, shared []string, dateStyles []bool, date1904 bool) ([][]string, error) {
+	var ws struct {
+		Rows []struct {
+			Cells []cell `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodePart(f, &ws); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	cells := 0
+	for _, r := range ws.Rows {
+		var row []string
+		for _, c := range r.Cells {
+			col := len(row)
+			if c.Ref != "" {
+				if n, ok := columnIndex(c.Ref); ok {
+					col = n
+				}
+			}
+			if col >= maxColumns {
+				continue
+			}
+			if col >= len(row) {
+				row = append(row, make([]string, col+1-len(row))...)
+			}
+			row[col] = cellText(c, shared, dateStyles, date1904)
+		}
+		if isEmpty(row) {
+			continue
+		}
+		if cells += len(row); cells > maxCells {
+			return nil, fmt.Errorf("%s: more than %d cells", f.Name, maxCells)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func cellText(c cell, shared []string, dateStyles []bool, date1904 bool) string {
+	switch c.Type {
+	case "s":
+		i, err := strconv.Atoi(strings.TrimSpace(c.Value))
+		if err != nil || i < 0 || i >= len(shared) {
+			return ""
+		}
+		return shared[i]
+	case "inlineStr":
+		return c.Inline.String()
+	case "b":
+		if c.Value == "1" {
+			return "TRUE"
+		}
+		return "FALSE"
+	case "str", "e":
+		return c.Value
+	}
+	if c.Style >= 0 && c.Style < len(dateStyles) && dateStyles[c.Style] {
+		if serial, err := strconv.ParseFloat(c.Value, 64); err == nil {
+			return serialDate(serial, date1904).Format("2006-01-02")
+		}
+	}
+	return c.Value
+}
+
+// serialDate converts an Excel date serial number: days since 1899-12-30
+// (which absorbs Excel's phantom 1900-02-29), or since 1904-01-01.
+func serialDate(serial float64, date1904 bool) time.Time {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	if date1904 {
+		epoch = time.Date(1904, 1, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return epoch.AddDate(0, 0, int(math.Floor(serial)))
+}
+
+// columnIndex returns the 0-based column of a cell reference such as "C12".
+func columnIndex(ref string) (int, bool) {
+	n := 0
+	i := 0
+	for ; i < len(ref) && ref[i] >= 'A' && ref[i] <= 'Z'; i++ {
+		n = n*26 + int(ref[i]-'A'+1)
+	}
+	if i == 0 || n > 16384 {
+		return 0, false
+	}
+	return n - 1, true
+}
+
+func isEmpty(row []string) bool {
+	for _, v := range row {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
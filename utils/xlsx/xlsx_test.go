@@ -0,0 +1,109 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// workbook zips parts into an XLSX.
+func workbook(t *testing.T, parts map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range parts {
+		f, err := w.Create(name)
+		require.NoError(t, err)
+		_, err = f.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+const ns = `xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"`
+
+func TestRows(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"xl/workbook.xml":            `<workbook ` + ns + `><sheets><sheet name="Statement" sheetId="1" r:id="rId3"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId3" Target="worksheets/statement.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst ` + ns + `><si><t>Date</t></si><si><t>Narration</t></si><si><r><t>NEFT </t></r><r><t>ACME PAYROLL</t></r></si></sst>`,
+		"xl/styles.xml":              `<styleSheet ` + ns + `><numFmts><numFmt numFmtId="164" formatCode="dd\-mmm\-yyyy"/><numFmt numFmtId="165" formatCode="#,##0.00"/></numFmts><cellXfs><xf numFmtId="0"/><xf numFmtId="164"/><xf numFmtId="165"/><xf numFmtId="14"/></cellXfs></styleSheet>`,
+		"xl/worksheets/statement.xml": `<worksheet ` + ns + `><sheetData>
+			<row r="1"><c r="A1" t="s"><v>0</v></c><c r="B1" t="s"><v>1</v></c><c r="D1" t="inlineStr"><is><t>Credit</t></is></c></row>
+			<row r="2"><c r="A2"/></row>
+			<row r="3"><c r="A3" s="1"><v>45323</v></c><c r="B3" t="s"><v>2</v></c><c r="D3" s="2"><v>74050.5</v></c></row>
+			<row r="4"><c r="A4" s="3"><v>45324.75</v></c><c r="C4" t="str"><f>SUM(D3)</f><v>74050.5</v></c></row>
+		</sheetData></worksheet>`,
+	})
+	require.True(t, IsWorkbook(bytes.NewReader(data), int64(len(data))))
+
+	rows, err := Rows(data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{
+		{"Date", "Narration", "", "Credit"},
+		{"2024-02-01", "NEFT ACME PAYROLL", "", "74050.5"},
+		{"2024-02-02", "", "74050.5"},
+	}, rows, "empty rows are skipped, numbers kept as stored, dates formatted")
+}
+
+func TestRowsDate1904(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"xl/workbook.xml":          `<workbook ` + ns + `><workbookPr date1904="1"/></workbook>`,
+		"xl/styles.xml":            `<styleSheet ` + ns + `><cellXfs><xf numFmtId="0"/><xf numFmtId="15"/></cellXfs></styleSheet>`,
+		"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData><row><c s="1"><v>0</v></c><c><v>7</v></c></row></sheetData></worksheet>`,
+	})
+	rows, err := Rows(data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1904-01-01", "7"}}, rows)
+}
+
+func TestRowsFarAndOutOfOrderCells(t *testing.T) {
+	data := workbook(t, map[string]string{
+		"xl/workbook.xml": `<workbook ` + ns + `/>`,
+		"xl/worksheets/sheet1.xml": `<worksheet ` + ns + `><sheetData>
+			<row><c r="C1"><v>3</v></c><c r="A1"><v>1</v></c><c r="XFD1"><v>far</v></c></row>
+			<row><c r="XFD2"><v>far</v></c></row>
+		</sheetData></worksheet>`,
+	})
+	rows, err := Rows(data)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"1", "", "3"}}, rows, "cells beyond maxColumns are dropped, earlier ones kept")
+}
+
+func TestRowsTooManyCells(t *testing.T) {
+	var sheet strings.Builder
+	sheet.WriteString(`<worksheet ` + ns + `><sheetData>`)
+	ref := "IV" // the last column read
+	for i := 0; i <= maxCells/maxColumns; i++ {
+		sheet.WriteString(`<row><c r="` + ref + `1"><v>1</v></c></row>`)
+	}
+	sheet.WriteString(`</sheetData></worksheet>`)
+	data := workbook(t, map[string]string{
+		"xl/workbook.xml":          `<workbook ` + ns + `/>`,
+		"xl/worksheets/sheet1.xml": sheet.String(),
+	})
+	_, err := Rows(data)
+	assert.ErrorContains(t, err, "more than")
+}
+
+func TestNotWorkbook(t *testing.T) {
+	data := workbook(t, map[string]string{"payslip.pdf": "%PDF-1.7"})
+	assert.False(t, IsWorkbook(bytes.NewReader(data), int64(len(data))))
+	_, err := Rows(data)
+	assert.ErrorIs(t, err, ErrNotWorkbook)
+	_, err = Rows([]byte("Date,Narration"))
+	assert.ErrorIs(t, err, ErrNotWorkbook)
+}
+
+func TestIsDateFormat(t *testing.T) {
+	assert.True(t, isDateFormat(`dd/mm/yyyy`))
+	assert.True(t, isDateFormat(`[$-409]d-mmm-yy;@`))
+	assert.False(t, isDateFormat(`#,##0.00`))
+	assert.False(t, isDateFormat(`[Red]0.00`))
+	assert.False(t, isDateFormat(`0.00" days"`))
+}
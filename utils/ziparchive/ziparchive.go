@@ -0,0 +1,255 @@
+// Package ziparchive unpacks uploaded ZIP archives, including the
+// password-protected ones banks and UIDAI hand out: traditional PKWARE
+// (ZipCrypto) and WinZip AES encryption are both read, which archive/zip
+// does not do on its own.
+package ziparchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path"
+	"strings"
+)
+
+var (
+	ErrPasswordRequired = errors.New("archive is password-protected")
+	ErrWrongPassword    = errors.New("wrong archive password")
+	ErrTooLarge         = errors.New("archive expands beyond the size limit")
+)
+
+// methodAES is the compression method of WinZip AES entries; the actual one
+// is in the AES extra field.
+const methodAES = 99
+
+// aesExtraID is the header ID of the WinZip AES extra field.
+const aesExtraID = 0x9901
+
+// Entry is one file of an archive.
+type Entry struct {
+	Name string // path inside the archive
+	Data []byte
+}
+
+// IsZip reports whether data starts like a ZIP archive.
+func IsZip(data []byte) bool {
+	return bytes.HasPrefix(data, []byte("PK\x03\x04"))
+}
+
+// Extract returns the files of a ZIP archive in archive order, decrypting
+// encrypted entries with password. Directories and macOS resource forks are
+// skipped. limit bounds the total uncompressed size, so a zip bomb fails with
+// ErrTooLarge rather than filling memory.
+func Extract(data []byte, password string, limit int64) ([]Entry, error) {
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid ZIP archive: %w", err)
+	}
+	var entries []Entry
+	remaining := limit
+	for _, f := range r.File {
+		if f.FileInfo().IsDir() || strings.HasPrefix(f.Name, "__MACOSX/") || strings.HasPrefix(path.Base(f.Name), "._") {
+			continue
+		}
+		body, err := readFile(f, password, remaining)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		remaining -= int64(len(body))
+		entries = append(entries, Entry{Name: f.Name, Data: body})
+	}
+	return entries, nil
+}
+
+// readFile reads at most limit bytes of f's content.
+func readFile(f *zip.File, password string, limit int64) ([]byte, error) {
+	if f.Flags&0x1 == 0 {
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer rc.Close()
+		return readLimited(rc, limit)
+	}
+	if password == "" {
+		return nil, ErrPasswordRequired
+	}
+
+	raw, err := f.OpenRaw()
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := io.ReadAll(raw)
+	if err != nil {
+		return nil, err
+	}
+	if f.Method == methodAES {
+		return readAES(f, ciphertext, password, limit)
+	}
+	return readZipCrypto(f, ciphertext, password, limit)
+}
+
+// readZipCrypto decrypts and inflates a traditional PKWARE encrypted entry.
+func readZipCrypto(f *zip.File, data []byte, password string, limit int64) ([]byte, error) {
+	if len(data) < 12 {
+		return nil, zip.ErrFormat
+	}
+	keys := newZipCrypto([]byte(password))
+	keys.decrypt(data)
+	// The last header byte repeats the high byte of the CRC, or of the
+	// modification time when sizes and CRC follow the data
+	check := byte(f.CRC32 >> 24)
+	if f.Flags&0x8 != 0 {
+		check = byte(f.ModifiedTime >> 8)
+	}
+	if data[11] != check {
+		return nil, ErrWrongPassword
+	}
+	body, err := decompress(f.Method, data[12:], limit)
+	if err != nil {
+		return nil, err
+	}
+	if crc32.ChecksumIEEE(body) != f.CRC32 {
+		return nil, ErrWrongPassword // one password in 256 passes the header check
+	}
+	return body, nil
+}
+
+// zipCrypto holds the three keys of the traditional PKWARE cipher.
+type zipCrypto struct{ k0, k1, k2 uint32 }
+
+func newZipCrypto(password []byte) *zipCrypto {
+	z := &zipCrypto{0x12345678, 0x23456789, 0x34567890}
+	for _, b := range password {
+		z.update(b)
+	}
+	return z
+}
+
+func (z *zipCrypto) update(b byte) {
+	z.k0 = crc32Update(z.k0, b)
+	z.k1 = (z.k1+z.k0&0xff)*134775813 + 1
+	z.k2 = crc32Update(z.k2, byte(z.k1>>24))
+}
+
+func (z *zipCrypto) stream() byte {
+	t := z.k2 | 2
+	return byte((t * (t ^ 1)) >> 8)
+}
+
+func (z *zipCrypto) decrypt(buf []byte) {
+	for i, c := range buf {
+		buf[i] = c ^ z.stream()
+		z.update(buf[i])
+	}
+}
+
+func crc32Update(crc uint32, b byte) uint32 {
+	return crc32.IEEETable[byte(crc)^b] ^ crc>>8
+}
+
+// readAES authenticates, decrypts and inflates a WinZip AES (AE-1/AE-2) entry:
+// salt, 2-byte password verifier, AES-CTR data, 10-byte HMAC-SHA1 code.
+func readAES(f *zip.File, data []byte, password string, limit int64) ([]byte, error) {
+	strength, method, ok := aesExtra(f.Extra)
+	if !ok {
+		return nil, zip.ErrAlgorithm
+	}
+	keyLen := 8 + 8*int(strength) // 1, 2, 3 = AES-128, 192, 256
+	saltLen := keyLen / 2
+	if len(data) < saltLen+2+10 {
+		return nil, zip.ErrFormat
+	}
+	salt, verifier := data[:saltLen], data[saltLen:saltLen+2]
+	ciphertext, code := data[saltLen+2:len(data)-10], data[len(data)-10:]
+
+	keys, err := pbkdf2.Key(sha1.New, password, salt, 1000, 2*keyLen+2)
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal(keys[2*keyLen:], verifier) {
+		return nil, ErrWrongPassword
+	}
+	mac := hmac.New(sha1.New, keys[keyLen:2*keyLen])
+	mac.Write(ciphertext)
+	if !hmac.Equal(mac.Sum(nil)[:10], code) {
+		return nil, errors.New("archive entry failed authentication")
+	}
+
+	block, err := aes.NewCipher(keys[:keyLen])
+	if err != nil {
+		return nil, err
+	}
+	// CTR with a little-endian counter starting at 1, unlike crypto/cipher's
+	var counter, stream [aes.BlockSize]byte
+	for i := range ciphertext {
+		if i%aes.BlockSize == 0 {
+			binary.LittleEndian.PutUint64(counter[:], uint64(i/aes.BlockSize+1))
+			block.Encrypt(stream[:], counter[:])
+		}
+		ciphertext[i] ^= stream[i%aes.BlockSize]
+	}
+
+	body, err := decompress(method, ciphertext, limit)
+	if err != nil {
+		return nil, err
+	}
+	// AE-2 entries leave the CRC out
+	if f.CRC32 != 0 && crc32.ChecksumIEEE(body) != f.CRC32 {
+		return nil, zip.ErrChecksum
+	}
+	return body, nil
+}
+
+// aesExtra reads the key strength and actual compression method from the
+// WinZip AES extra field.
+func aesExtra(extra []byte) (strength byte, method uint16, ok bool) {
+	for len(extra) >= 4 {
+		id, size := binary.LittleEndian.Uint16(extra), int(binary.LittleEndian.Uint16(extra[2:]))
+		extra = extra[4:]
+		if size > len(extra) {
+			break
+		}
+		if id == aesExtraID && size >= 7 {
+			strength, method = extra[4], binary.LittleEndian.Uint16(extra[5:])
+			return strength, method, strength >= 1 && strength <= 3
+		}
+		extra = extra[size:]
+	}
+	return 0, 0, false
+}
+
+func decompress(method uint16, data []byte, limit int64) ([]byte, error) {
+	switch method {
+	case zip.Store:
+		if int64(len(data)) > limit {
+			return nil, ErrTooLarge
+		}
+		return data, nil
+	case zip.Deflate:
+		r := flate.NewReader(bytes.NewReader(data))
+		defer r.Close()
+		return readLimited(r, limit)
+	}
+	return nil, zip.ErrAlgorithm
+}
+
+func readLimited(r io.Reader, limit int64) ([]byte, error) {
+	body, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(body)) > limit {
+		return nil, ErrTooLarge
+	}
+	return body, nil
+}
@@ -0,0 +1,108 @@
+package ziparchive
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/flate"
+	"crypto/aes"
+	"crypto/hmac"
+	"crypto/pbkdf2"
+	"crypto/sha1"
+	"encoding/binary"
+	"hash/crc32"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deflate(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	require.NoError(t, err)
+	w.Write(data)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// zipCryptoEntry is data deflated and encrypted with the PKWARE cipher.
+func zipCryptoEntry(t *testing.T, data []byte, password string) []byte {
+	z := newZipCrypto([]byte(password))
+	header := make([]byte, 12)
+	header[11] = byte(crc32.ChecksumIEEE(data) >> 24)
+	out := append(header, deflate(t, data)...)
+	for i, c := range out {
+		out[i] = c ^ z.stream()
+		z.update(c)
+	}
+	return out
+}
+
+// aesEntry is data deflated and encrypted as a WinZip AE-2 AES-256 entry.
+func aesEntry(t *testing.T, data []byte, password string) []byte {
+	salt := bytes.Repeat([]byte{7}, 16)
+	keys, err := pbkdf2.Key(sha1.New, password, salt, 1000, 66)
+	require.NoError(t, err)
+	ciphertext := deflate(t, data)
+	block, err := aes.NewCipher(keys[:32])
+	require.NoError(t, err)
+	var counter, stream [aes.BlockSize]byte
+	for i := range ciphertext {
+		if i%aes.BlockSize == 0 {
+			binary.LittleEndian.PutUint64(counter[:], uint64(i/aes.BlockSize+1))
+			block.Encrypt(stream[:], counter[:])
+		}
+		ciphertext[i] ^= stream[i%aes.BlockSize]
+	}
+	mac := hmac.New(sha1.New, keys[32:64])
+	mac.Write(ciphertext)
+	out := append(append(salt, keys[64:]...), ciphertext...)
+	return append(out, mac.Sum(nil)[:10]...)
+}
+
+func TestExtract(t *testing.T) {
+	pdf := []byte("%PDF-1.7 statement " + string(bytes.Repeat([]byte("x"), 500)))
+	png := []byte("\x89PNG\r\n\x1a\n page")
+
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	_, err := w.Create("docs/")
+	require.NoError(t, err)
+	plain, err := w.Create("docs/readme.txt")
+	require.NoError(t, err)
+	plain.Write([]byte("hello"))
+
+	enc := zipCryptoEntry(t, pdf, "ABCPK1234F")
+	raw, err := w.CreateRaw(&zip.FileHeader{
+		Name: "docs/statement.pdf", Method: zip.Deflate, Flags: 0x1,
+		CRC32: crc32.ChecksumIEEE(pdf), CompressedSize64: uint64(len(enc)), UncompressedSize64: uint64(len(pdf)),
+	})
+	require.NoError(t, err)
+	raw.Write(enc)
+
+	enc = aesEntry(t, png, "ABCPK1234F")
+	extra := []byte{0x01, 0x99, 7, 0, 2, 0, 'A', 'E', 3, 8, 0} // AE-2, AES-256, deflated
+	raw, err = w.CreateRaw(&zip.FileHeader{
+		Name: "page1.png", Method: methodAES, Flags: 0x1, Extra: extra,
+		CompressedSize64: uint64(len(enc)), UncompressedSize64: uint64(len(png)),
+	})
+	require.NoError(t, err)
+	raw.Write(enc)
+	require.NoError(t, w.Close())
+	archive := buf.Bytes()
+
+	assert.True(t, IsZip(archive))
+	entries, err := Extract(archive, "ABCPK1234F", 1<<20)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, Entry{Name: "docs/readme.txt", Data: []byte("hello")}, entries[0])
+	assert.Equal(t, Entry{Name: "docs/statement.pdf", Data: pdf}, entries[1])
+	assert.Equal(t, Entry{Name: "page1.png", Data: png}, entries[2])
+
+	_, err = Extract(archive, "", 1<<20)
+	assert.ErrorIs(t, err, ErrPasswordRequired)
+	_, err = Extract(archive, "wrong", 1<<20)
+	assert.ErrorIs(t, err, ErrWrongPassword)
+	_, err = Extract(archive, "ABCPK1234F", 100)
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
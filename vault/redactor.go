@@ -0,0 +1,164 @@
+package vault
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Aashish23092/ocr-income-verification/utils"
+)
+
+// TokenPrefix starts every token, so they are recognizable in output text.
+const TokenPrefix = "tok_"
+
+// Redactor masks (or tokenizes) identity numbers in JSON documents.
+type Redactor struct {
+	// Types are the dto.PIIType* values to protect.
+	Types map[string]bool
+	// Store receives tokenized values; nil disables tokenization.
+	Store Store
+}
+
+// NewRedactor protects the given PII types.
+func NewRedactor(types []string, store Store) *Redactor {
+	r := &Redactor{Types: map[string]bool{}, Store: store}
+	for _, t := range types {
+		r.Types[strings.TrimSpace(t)] = true
+	}
+	return r
+}
+
+// CanTokenize reports whether a secret store is configured.
+func (r *Redactor) CanTokenize() bool {
+	return r != nil && r.Store != nil
+}
+
+// Tokenize stores secret under a new random token and returns the token.
+func (r *Redactor) Tokenize(ctx context.Context, secret Secret) (string, error) {
+	if !r.CanTokenize() {
+		return "", fmt.Errorf("tokenization is not configured")
+	}
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	token := TokenPrefix + hex.EncodeToString(b)
+	if err := r.Store.Put(ctx, token, secret); err != nil {
+		return "", err
+	}
+	return token, nil
+}
+
+// Detokenize returns the original value of a token.
+func (r *Redactor) Detokenize(ctx context.Context, token string) (Secret, error) {
+	if !r.CanTokenize() {
+		return Secret{}, fmt.Errorf("tokenization is not configured")
+	}
+	if !strings.HasPrefix(token, TokenPrefix) {
+		return Secret{}, ErrNotFound
+	}
+	return r.Store.Get(ctx, token)
+}
+
+// base64Fields hold images and files, whose base64 may by chance read like an
+// identity number.
+var base64Fields = map[string]bool{"photo": true, "masked_images": true, "redacted_file": true}
+
+// JSON rewrites every string value of a JSON document, replacing protected
+// identity numbers with their masked form (last four characters kept) or,
+// with tokenize set, with a token. Keys, numbers and structure are untouched,
+// and so are the base64 image fields. Already masked values stay as they are.
+func (r *Redactor) JSON(ctx context.Context, data []byte, tokenize bool) ([]byte, error) {
+	if r == nil || len(r.Types) == 0 {
+		return data, nil
+	}
+
+	var out bytes.Buffer
+	out.Grow(len(data))
+	lastKey := ""
+	for i := 0; i < len(data); {
+		if data[i] != '"' {
+			out.WriteByte(data[i])
+			i++
+			continue
+		}
+
+		end := stringEnd(data, i)
+		lit := data[i : end+1]
+		// a string followed by ':' is a key
+		j := end + 1
+		for j < len(data) && (data[j] == ' ' || data[j] == '\n' || data[j] == '\t' || data[j] == '\r') {
+			j++
+		}
+		if j < len(data) && data[j] == ':' {
+			lastKey = string(lit[1 : len(lit)-1])
+			out.Write(lit)
+		} else if base64Fields[lastKey] || !bytes.ContainsAny(lit, "0123456789") {
+			out.Write(lit)
+		} else {
+			var value string
+			if err := json.Unmarshal(lit, &value); err != nil {
+				return nil, err
+			}
+			protected, err := r.protect(ctx, value, tokenize)
+			if err != nil {
+				return nil, err
+			}
+			if protected == value {
+				out.Write(lit)
+			} else {
+				enc, _ := json.Marshal(protected)
+				out.Write(enc)
+			}
+		}
+		i = end + 1
+	}
+	return out.Bytes(), nil
+}
+
+// protect rewrites one string value.
+func (r *Redactor) protect(ctx context.Context, s string, tokenize bool) (string, error) {
+	findings := utils.ScanPII(s)
+	var b strings.Builder
+	last := 0
+	for _, f := range findings {
+		value := s[f.Start:f.End]
+		if !r.Types[f.Type] || !strings.ContainsAny(value, "0123456789") || strings.ContainsAny(value, "Xx*") {
+			continue
+		}
+		replacement := f.Masked
+		if tokenize && r.CanTokenize() {
+			compact := strings.NewReplacer(" ", "", "-", "").Replace(value)
+			token, err := r.Tokenize(ctx, Secret{Type: f.Type, Value: compact})
+			if err != nil {
+				return "", err
+			}
+			replacement = token
+		}
+		b.WriteString(s[last:f.Start])
+		b.WriteString(replacement)
+		last = f.End
+	}
+	if last == 0 {
+		return s, nil
+	}
+	b.WriteString(s[last:])
+	return b.String(), nil
+}
+
+// stringEnd returns the index of the quote closing the JSON string opened at start.
+func stringEnd(data []byte, start int) int {
+	for i := start + 1; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i
+		}
+	}
+	return len(data) - 1
+}
@@ -0,0 +1,159 @@
+// Package vault keeps identity numbers out of API output: it masks them in
+// JSON responses and, on request, swaps them for reversible tokens whose
+// originals are held in a secret store for authorized systems to look up.
+package vault
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrNotFound is returned for unknown tokens.
+var ErrNotFound = errors.New("token not found")
+
+// Secret is the original value behind a token.
+type Secret struct {
+	Type  string `json:"type"` // dto.PIIType*
+	Value string `json:"value"`
+}
+
+// Store holds token -> secret.
+type Store interface {
+	Put(ctx context.Context, token string, secret Secret) error
+	Get(ctx context.Context, token string) (Secret, error)
+}
+
+// NewStore builds the secret store selected by backend: memory (tokens die
+// with the process; for development), hashicorp (Vault KV v2 at addr, using
+// authToken, under mount) or off/"" (returns nil: tokenization disabled).
+func NewStore(backend, addr, authToken, mount string) (Store, error) {
+	switch backend {
+	case "", "off":
+		return nil, nil
+	case "memory":
+		return NewMemoryStore(), nil
+	case "hashicorp":
+		if addr == "" || authToken == "" {
+			return nil, fmt.Errorf("hashicorp token store needs VAULT_ADDR and VAULT_TOKEN")
+		}
+		return NewKVStore(addr, authToken, mount), nil
+	}
+	return nil, fmt.Errorf("unknown token store backend %q", backend)
+}
+
+// MemoryStore keeps secrets in process memory.
+type MemoryStore struct {
+	mu      sync.RWMutex
+	secrets map[string]Secret
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{secrets: map[string]Secret{}}
+}
+
+func (m *MemoryStore) Put(_ context.Context, token string, secret Secret) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.secrets[token] = secret
+	return nil
+}
+
+func (m *MemoryStore) Get(_ context.Context, token string) (Secret, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	s, ok := m.secrets[token]
+	if !ok {
+		return Secret{}, ErrNotFound
+	}
+	return s, nil
+}
+
+// KVStore stores secrets in a HashiCorp Vault KV version 2 engine, one entry
+// per token under <mount>/data/ocr-tokens/.
+type KVStore struct {
+	Addr  string
+	Token string
+	Mount string
+	HTTP  *http.Client
+}
+
+func NewKVStore(addr, token, mount string) *KVStore {
+	if mount == "" {
+		mount = "secret"
+	}
+	return &KVStore{
+		Addr:  strings.TrimRight(addr, "/"),
+		Token: token,
+		Mount: strings.Trim(mount, "/"),
+		HTTP:  &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+func (k *KVStore) url(token string) string {
+	return k.Addr + "/v1/" + k.Mount + "/data/ocr-tokens/" + url.PathEscape(token)
+}
+
+func (k *KVStore) Put(ctx context.Context, token string, secret Secret) error {
+	body, err := json.Marshal(map[string]Secret{"data": secret})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, k.url(token), bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := k.do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (k *KVStore) Get(ctx context.Context, token string) (Secret, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, k.url(token), nil)
+	if err != nil {
+		return Secret{}, err
+	}
+	resp, err := k.do(req)
+	if err != nil {
+		return Secret{}, err
+	}
+	defer resp.Body.Close()
+
+	var out struct {
+		Data struct {
+			Data Secret `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return Secret{}, err
+	}
+	return out.Data.Data, nil
+}
+
+func (k *KVStore) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("X-Vault-Token", k.Token)
+	resp, err := k.HTTP.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	case resp.StatusCode >= 300:
+		resp.Body.Close()
+		return nil, fmt.Errorf("vault returned %s", resp.Status)
+	}
+	return resp, nil
+}
@@ -0,0 +1,80 @@
+package vault
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactorMasksAndTokenizes(t *testing.T) {
+	ctx := context.Background()
+	r := NewRedactor([]string{"aadhaar"}, NewMemoryStore())
+
+	body := []byte(`{"aadhaar_number":"234567890123","raw_text":"Name: Ravi\n2345 6789 0123\nVID 9999","net_salary":234567890123,"masked":"XXXX XXXX 0123","photo":"ab/2345 6789 0123/cd","masked_images":["ab/2345 6789 0123/cd"],"234567890123":1}`)
+
+	masked, err := r.JSON(ctx, body, false)
+	require.NoError(t, err)
+	var out map[string]interface{}
+	require.NoError(t, json.Unmarshal(masked, &out))
+	assert.Equal(t, "XXXXXXXX0123", out["aadhaar_number"])
+	assert.Equal(t, "Name: Ravi\nXXXXXXXX0123\nVID 9999", out["raw_text"])
+	assert.Equal(t, 234567890123.0, out["net_salary"]) // numbers are not identity numbers
+	assert.Equal(t, "XXXX XXXX 0123", out["masked"])
+	assert.Equal(t, "ab/2345 6789 0123/cd", out["photo"])
+	assert.Equal(t, []interface{}{"ab/2345 6789 0123/cd"}, out["masked_images"])
+	assert.Contains(t, out, "234567890123") // keys are left alone
+
+	tokenized, err := r.JSON(ctx, body, true)
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(tokenized, &out))
+	token := out["aadhaar_number"].(string)
+	assert.True(t, strings.HasPrefix(token, TokenPrefix))
+
+	secret, err := r.Detokenize(ctx, token)
+	require.NoError(t, err)
+	assert.Equal(t, Secret{Type: "aadhaar", Value: "234567890123"}, secret)
+
+	_, err = r.Detokenize(ctx, TokenPrefix+"unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestKVStore(t *testing.T) {
+	kv := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "root-token", r.Header.Get("X-Vault-Token"))
+		assert.True(t, strings.HasPrefix(r.URL.Path, "/v1/kv/data/ocr-tokens/"))
+		switch r.Method {
+		case http.MethodPost:
+			var in map[string]json.RawMessage
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&in))
+			kv[r.URL.Path] = in["data"]
+			w.Write([]byte(`{"data":{"version":1}}`))
+		case http.MethodGet:
+			data, ok := kv[r.URL.Path]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Write([]byte(`{"data":{"data":` + string(data) + `}}`))
+		}
+	}))
+	defer srv.Close()
+
+	store, err := NewStore("hashicorp", srv.URL, "root-token", "kv")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "tok_1", Secret{Type: "aadhaar", Value: "234567890123"}))
+	got, err := store.Get(ctx, "tok_1")
+	require.NoError(t, err)
+	assert.Equal(t, "234567890123", got.Value)
+
+	_, err = store.Get(ctx, "tok_2")
+	assert.ErrorIs(t, err, ErrNotFound)
+}